@@ -66,6 +66,58 @@ Merge all configmaps
 {{- end }}
 {{- end -}}
 
+{{/*
+Registry configuration, with the S3 storage driver when S3 storage is enabled.
+Blobs are served through the registry rather than redirecting clients to the bucket, which nodes may not be able to reach.
+The blob descriptor cache is disabled with garbage collection, as it would keep serving the layers that were removed.
+*/}}
+{{- define "docker-registry.configData" -}}
+{{- $configData := deepCopy .Values.secrets.configData -}}
+{{- if and .Values.garbageCollect.enabled $configData.storage -}}
+{{- $_ := set $configData "storage" (omit $configData.storage "cache") -}}
+{{- end -}}
+{{- if .Values.s3.enabled -}}
+{{- $s3 := dict "bucket" (required "A valid s3.bucket value is required when S3 storage is enabled" .Values.s3.bucket) "region" (required "A valid s3.region value is required when S3 storage is enabled" .Values.s3.region) "secure" .Values.s3.secure -}}
+{{- range $key, $value := dict "regionendpoint" .Values.s3.regionEndpoint "rootdirectory" .Values.s3.rootDirectory "accesskey" .Values.s3.accessKey "secretkey" .Values.s3.secretKey -}}
+{{- if $value -}}
+{{- $_ := set $s3 $key $value -}}
+{{- end -}}
+{{- end -}}
+{{- $storage := default dict $configData.storage -}}
+{{- $_ := set $storage "s3" $s3 -}}
+{{- $_ := set $storage "redirect" (dict "disable" true) -}}
+{{- $_ := set $configData "storage" $storage -}}
+{{- end -}}
+{{- toJson $configData -}}
+{{- end -}}
+
+{{/*
+Image of the registry implementation.
+*/}}
+{{- define "docker-registry.image" -}}
+{{- if eq .Values.type "zot" -}}
+{{ .Values.zot.image.repository }}:{{ .Values.zot.image.tag }}
+{{- else -}}
+{{ .Values.image.repository }}:{{ .Values.image.tag }}
+{{- end -}}
+{{- end -}}
+
+{{/*
+zot configuration, which accepts the Docker manifests of the images Zarf pushes and garbage collects in the background.
+*/}}
+{{- define "docker-registry.zotConfigData" -}}
+{{- if .Values.s3.enabled -}}
+{{- fail "S3 storage is not supported with the zot registry" -}}
+{{- end -}}
+{{- $storage := dict "rootDirectory" "/var/lib/registry" "gc" .Values.garbageCollect.enabled -}}
+{{- if .Values.garbageCollect.enabled -}}
+{{- $_ := set $storage "gcInterval" .Values.zot.gcInterval -}}
+{{- $_ := set $storage "gcDelay" .Values.zot.gcDelay -}}
+{{- end -}}
+{{- $http := dict "address" "0.0.0.0" "port" "5000" "compat" (list "docker2s2") "auth" (dict "htpasswd" (dict "path" "/etc/zot/htpasswd")) -}}
+{{- toJson (dict "distSpecVersion" "1.1.0" "storage" $storage "http" $http "log" (dict "level" "info")) -}}
+{{- end -}}
+
 {{/*
 Create the name of the service account to use
 */}}
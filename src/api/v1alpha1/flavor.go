@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package v1alpha1 holds the definition of the v1alpha1 Zarf Package
+package v1alpha1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// FlavorList is a list of flavors that can also be written as a single flavor.
+type FlavorList []string
+
+// UnmarshalJSON reads a flavor list from either a string or a list of strings.
+func (f *FlavorList) UnmarshalJSON(b []byte) error {
+	var flavor string
+	if err := json.Unmarshal(b, &flavor); err == nil {
+		*f = FlavorList{flavor}
+		if flavor == "" {
+			*f = nil
+		}
+		return nil
+	}
+	var flavors []string
+	if err := json.Unmarshal(b, &flavors); err != nil {
+		return fmt.Errorf("flavor must be a string or a list of strings: %w", err)
+	}
+	*f = flavors
+	return nil
+}
+
+// UnmarshalYAML reads a flavor list from either a string or a list of strings.
+func (f *FlavorList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var flavor string
+	if err := unmarshal(&flavor); err == nil {
+		*f = FlavorList{flavor}
+		if flavor == "" {
+			*f = nil
+		}
+		return nil
+	}
+	var flavors []string
+	if err := unmarshal(&flavors); err != nil {
+		return fmt.Errorf("flavor must be a string or a list of strings: %w", err)
+	}
+	*f = flavors
+	return nil
+}
+
+// MarshalJSON writes a single flavor as a string so that packages stay readable by older versions of Zarf.
+func (f FlavorList) MarshalJSON() ([]byte, error) {
+	if len(f) == 1 {
+		return json.Marshal(f[0])
+	}
+	return json.Marshal([]string(f))
+}
+
+// MarshalYAML writes a single flavor as a string so that packages stay readable by older versions of Zarf.
+func (f FlavorList) MarshalYAML() (interface{}, error) {
+	if len(f) == 1 {
+		return f[0], nil
+	}
+	return []string(f), nil
+}
+
+// JSONSchema allows a flavor list to be written as a single flavor in the generated json schema.
+func (FlavorList) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}
+}
+
+// Matches returns true if the list is empty or contains one of the given flavors.
+func (f FlavorList) Matches(flavors []string) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, flavor := range f {
+		if slices.Contains(flavors, flavor) {
+			return true
+		}
+	}
+	return false
+}
+
+// ZarfFlavor defines a flavor of the package and the flavors it inherits components from.
+type ZarfFlavor struct {
+	// The name of the flavor, as given to '--flavor' on 'zarf package create'.
+	Name string `json:"name" jsonschema:"pattern=^[a-zA-Z0-9\\-_.]+$"`
+	// Description of the flavor.
+	Description string `json:"description,omitempty"`
+	// Flavors whose components are also included when this flavor is created, components limited to this flavor take precedence over components of the same name limited to an inherited flavor.
+	Inherits []string `json:"inherits,omitempty"`
+}
+
+// ValidateFlavors returns an error if the flavors of the package or the flavors its components are limited to are invalid.
+func (pkg ZarfPackage) ValidateFlavors() error {
+	var err error
+	declared := map[string]ZarfFlavor{}
+	for _, flavor := range pkg.Flavors {
+		if flavor.Name == "" {
+			err = errors.Join(err, errors.New("flavor name cannot be empty"))
+			continue
+		}
+		if _, ok := declared[flavor.Name]; ok {
+			err = errors.Join(err, fmt.Errorf("flavor %q is not unique", flavor.Name))
+		}
+		declared[flavor.Name] = flavor
+	}
+	for _, flavor := range pkg.Flavors {
+		for _, parent := range flavor.Inherits {
+			if _, ok := declared[parent]; !ok {
+				err = errors.Join(err, fmt.Errorf("flavor %q inherits undefined flavor %q", flavor.Name, parent))
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Walk the inheritance of every flavor to find cycles.
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if slices.Contains(path, name) {
+			return fmt.Errorf("flavor inheritance cycle %s", strings.Join(append(path, name), " -> "))
+		}
+		for _, parent := range declared[name].Inherits {
+			if err := visit(parent, append(path, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, flavor := range pkg.Flavors {
+		if cycleErr := visit(flavor.Name, nil); cycleErr != nil {
+			return cycleErr
+		}
+	}
+
+	for _, component := range pkg.Components {
+		seen := map[string]bool{}
+		for _, flavor := range component.Only.Flavor {
+			if flavor == "" {
+				err = errors.Join(err, fmt.Errorf("component %q only.flavor cannot contain an empty flavor", component.Name))
+			}
+			if seen[flavor] {
+				err = errors.Join(err, fmt.Errorf("component %q only.flavor lists %q more than once", component.Name, flavor))
+			}
+			seen[flavor] = true
+		}
+	}
+	return err
+}
+
+// ResolveFlavor returns the given flavor followed by every flavor it inherits, from the most to the least specific.
+// Flavors that are not defined by the package do not inherit any other flavors.
+func (pkg ZarfPackage) ResolveFlavor(flavor string) ([]string, error) {
+	if err := pkg.ValidateFlavors(); err != nil {
+		return nil, err
+	}
+	if flavor == "" {
+		return nil, nil
+	}
+	inherits := map[string][]string{}
+	for _, f := range pkg.Flavors {
+		inherits[f.Name] = f.Inherits
+	}
+	resolved := []string{flavor}
+	for i := 0; i < len(resolved); i++ {
+		for _, parent := range inherits[resolved[i]] {
+			if !slices.Contains(resolved, parent) {
+				resolved = append(resolved, parent)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// DefinedFlavors returns every flavor the package can be created with, the flavors it declares in the order they are
+// declared followed by the other flavors its components are limited to in the order they first appear.
+func (pkg ZarfPackage) DefinedFlavors() []string {
+	flavors := []string{}
+	for _, flavor := range pkg.Flavors {
+		if !slices.Contains(flavors, flavor.Name) {
+			flavors = append(flavors, flavor.Name)
+		}
+	}
+	for _, component := range pkg.Components {
+		for _, flavor := range component.Only.Flavor {
+			if flavor != "" && !slices.Contains(flavors, flavor) {
+				flavors = append(flavors, flavor)
+			}
+		}
+	}
+	return flavors
+}
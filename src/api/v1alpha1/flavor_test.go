@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package v1alpha1 holds the definition of the v1alpha1 Zarf Package
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlavorList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		yaml     string
+		expected FlavorList
+	}{
+		{
+			name:     "single flavor",
+			yaml:     "flavor: upstream",
+			expected: FlavorList{"upstream"},
+		},
+		{
+			name:     "flavor list",
+			yaml:     "flavor: [upstream, registry1]",
+			expected: FlavorList{"upstream", "registry1"},
+		},
+		{
+			name: "empty flavor",
+			yaml: `flavor: ""`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var fromYAML ZarfComponentOnlyTarget
+			require.NoError(t, goyaml.Unmarshal([]byte(tt.yaml), &fromYAML))
+			require.Equal(t, tt.expected, fromYAML.Flavor)
+
+			b, err := json.Marshal(fromYAML)
+			require.NoError(t, err)
+			var fromJSON ZarfComponentOnlyTarget
+			require.NoError(t, json.Unmarshal(b, &fromJSON))
+			require.Equal(t, tt.expected, fromJSON.Flavor)
+		})
+	}
+
+	// A single flavor is written as a string so older versions of Zarf can read it.
+	b, err := json.Marshal(ZarfComponentOnlyTarget{Flavor: FlavorList{"upstream"}})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"cluster":{},"flavor":"upstream"}`, string(b))
+	y, err := goyaml.Marshal(ZarfComponentOnlyTarget{Flavor: FlavorList{"upstream"}})
+	require.NoError(t, err)
+	require.Contains(t, string(y), "flavor: upstream")
+}
+
+func TestResolveFlavor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		pkg         ZarfPackage
+		flavor      string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "undeclared flavor",
+			flavor:   "upstream",
+			expected: []string{"upstream"},
+		},
+		{
+			name: "no flavor",
+			pkg: ZarfPackage{
+				Flavors: []ZarfFlavor{{Name: "upstream"}},
+			},
+		},
+		{
+			name: "inherited flavors",
+			pkg: ZarfPackage{
+				Flavors: []ZarfFlavor{
+					{Name: "upstream"},
+					{Name: "registry1", Inherits: []string{"upstream"}},
+					{Name: "registry1-fips", Inherits: []string{"registry1", "fips"}},
+					{Name: "fips", Inherits: []string{"upstream"}},
+				},
+			},
+			flavor:   "registry1-fips",
+			expected: []string{"registry1-fips", "registry1", "fips", "upstream"},
+		},
+		{
+			name: "undefined parent",
+			pkg: ZarfPackage{
+				Flavors: []ZarfFlavor{{Name: "registry1", Inherits: []string{"upstream"}}},
+			},
+			flavor:      "registry1",
+			expectedErr: `flavor "registry1" inherits undefined flavor "upstream"`,
+		},
+		{
+			name: "duplicate flavor",
+			pkg: ZarfPackage{
+				Flavors: []ZarfFlavor{{Name: "upstream"}, {Name: "upstream"}},
+			},
+			expectedErr: `flavor "upstream" is not unique`,
+		},
+		{
+			name: "duplicate component flavor",
+			pkg: ZarfPackage{
+				Components: []ZarfComponent{
+					{Name: "podinfo", Only: ZarfComponentOnlyTarget{Flavor: FlavorList{"upstream", "upstream"}}},
+				},
+			},
+			expectedErr: `component "podinfo" only.flavor lists "upstream" more than once`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			flavors, err := tt.pkg.ResolveFlavor(tt.flavor)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, flavors)
+		})
+	}
+}
+
+func TestDefinedFlavors(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, ZarfPackage{Components: []ZarfComponent{{Name: "podinfo"}}}.DefinedFlavors())
+
+	pkg := ZarfPackage{
+		Flavors: []ZarfFlavor{
+			{Name: "upstream"},
+			{Name: "registry1", Inherits: []string{"upstream"}},
+		},
+		Components: []ZarfComponent{
+			{Name: "podinfo", Only: ZarfComponentOnlyTarget{Flavor: FlavorList{"fips", "upstream"}}},
+			{Name: "game"},
+			{Name: "grafana", Only: ZarfComponentOnlyTarget{Flavor: FlavorList{"chainguard", "fips"}}},
+		},
+	}
+	require.Equal(t, []string{"upstream", "registry1", "fips", "chainguard"}, pkg.DefinedFlavors())
+}
@@ -43,6 +43,8 @@ const (
 	ZarfPackageConfig ZarfPackageKind = "ZarfPackageConfig"
 	// APIVersion the api version of this package.
 	APIVersion string = "zarf.dev/v1alpha1"
+	// MultiArch is the architecture of packages built for more than one architecture.
+	MultiArch = "multi"
 )
 
 // ZarfPackage the top-level structure of a Zarf config file.
@@ -61,6 +63,12 @@ type ZarfPackage struct {
 	Constants []Constant `json:"constants,omitempty"`
 	// Variable template values applied on deploy for K8s resources.
 	Variables []InteractiveVariable `json:"variables,omitempty"`
+	// Custom commands to run once before and after the components of the package are created, deployed or removed.
+	Actions ZarfComponentActions `json:"actions,omitempty"`
+	// Local files or directories of documentation to include in the package as a separate layer that can be pulled without the rest of the package.
+	Docs []string `json:"docs,omitempty"`
+	// Flavors of the package and the flavors they inherit components from.
+	Flavors []ZarfFlavor `json:"flavors,omitempty"`
 }
 
 // IsInitConfig returns whether a Zarf package is an init config.
@@ -68,6 +76,11 @@ func (pkg ZarfPackage) IsInitConfig() bool {
 	return pkg.Kind == ZarfInitConfig
 }
 
+// IsMultiArch returns whether a Zarf package was built for more than one architecture.
+func (pkg ZarfPackage) IsMultiArch() bool {
+	return pkg.Metadata.Architecture == MultiArch
+}
+
 // HasImages returns true if one of the components contains an image.
 func (pkg ZarfPackage) HasImages() bool {
 	for _, component := range pkg.Components {
@@ -158,6 +171,8 @@ type ZarfMetadata struct {
 	Uncompressed bool `json:"uncompressed,omitempty"`
 	// The target cluster architecture for this package.
 	Architecture string `json:"architecture,omitempty" jsonschema:"example=arm64,example=amd64"`
+	// The target cluster architectures for a single package built for multiple architectures (e.g. [amd64, arm64]), overrides architecture.
+	Architectures []string `json:"architectures,omitempty"`
 	// Yaml OnLy Online (YOLO): True enables deploying a Zarf package without first running zarf init against the cluster. This is ideal for connected environments where you want to use existing VCS and container registries.
 	YOLO bool `json:"yolo,omitempty"`
 	// Comma-separated list of package authors (including contact info).
@@ -168,6 +183,8 @@ type ZarfMetadata struct {
 	Source string `json:"source,omitempty"`
 	// Name of the distributing entity, organization or individual.
 	Vendor string `json:"vendor,omitempty"`
+	// PEM encoded public key (or a path to one on create) that entitlement tokens for gated components are verified against on deploy.
+	EntitlementKey string `json:"entitlementKey,omitempty"`
 	// Checksum of a checksums.txt file that contains checksums all the layers within the package.
 	AggregateChecksum string `json:"aggregateChecksum,omitempty"`
 }
@@ -192,10 +209,18 @@ type ZarfBuildData struct {
 	Differential bool `json:"differential,omitempty"`
 	// Version of a previously built package used as the basis for creating this differential package.
 	DifferentialPackageVersion string `json:"differentialPackageVersion,omitempty"`
+	// Aggregate checksum of the previously built package used as the basis for creating this differential package.
+	DifferentialPackageChecksum string `json:"differentialPackageChecksum,omitempty"`
 	// List of components that were not included in this package due to differential packaging.
 	DifferentialMissing []string `json:"differentialMissing,omitempty"`
+	// SBOM rules that skipped or redacted the SBOMs of components in this package, keyed by component name.
+	SBOMRedactions map[string]ZarfComponentSBOM `json:"sbomRedactions,omitempty"`
+	// The formats the SBOMs in this package were created in.
+	SBOMFormats []string `json:"sbomFormats,omitempty"`
 	// The minimum version of Zarf that does not have breaking package structure changes.
 	LastNonBreakingVersion string `json:"lastNonBreakingVersion,omitempty"`
 	// The flavor of Zarf used to build this package.
 	Flavor string `json:"flavor,omitempty"`
+	// The Zarf features this package relies on, checked against the deploying version of Zarf.
+	Features []string `json:"features,omitempty"`
 }
@@ -23,15 +23,27 @@ type ZarfComponent struct {
 	// Do not prompt user to install this component. (Defaults to false)
 	Optional *bool `json:"optional,omitempty"`
 
+	// The entitlement a signed entitlement token supplied on deploy must grant for this component to be deployed.
+	Entitlement string `json:"entitlement,omitempty"`
+
 	// Filter when this component is included in package creation or deployment.
 	Only ZarfComponentOnlyTarget `json:"only,omitempty"`
 
+	// Names of the components that must finish deploying before this component starts when components are deployed concurrently.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
 	// Import a component from another Zarf package.
 	Import ZarfComponentImport `json:"import,omitempty"`
 
 	// Kubernetes manifests to be included in a generated Helm chart on package deploy.
 	Manifests []ZarfManifest `json:"manifests,omitempty"`
 
+	// Kyverno or Gatekeeper policy bundles to install before the rest of the component is deployed.
+	Policies []ZarfPolicy `json:"policies,omitempty"`
+
+	// Rego or CEL policies that the rendered charts, manifests and policy bundles of the component are checked against on deploy before they are applied.
+	PolicyGates []ZarfPolicyGate `json:"policyGates,omitempty"`
+
 	// Helm charts to install during package deploy.
 	Charts []ZarfChart `json:"charts,omitempty"`
 
@@ -41,17 +53,32 @@ type ZarfComponent struct {
 	// Files or folders to place on disk during package deployment.
 	Files []ZarfFile `json:"files,omitempty"`
 
-	// List of OCI images to include in the package.
+	// List of OCI images to include in the package, images prefixed with docker-daemon:, podman: or containerd: are loaded from a local container runtime instead of a registry.
 	Images []string `json:"images,omitempty"`
 
+	// List of OCI artifacts that are not container images (e.g. Helm OCI charts, WASM modules, Flux OCIRepository or ORAS artifacts) to include in the package and push to the Zarf registry on deploy.
+	Artifacts []string `json:"artifacts,omitempty"`
+
 	// List of git repos to include in the package.
 	Repos []string `json:"repos,omitempty"`
 
+	// Control how SBOMs are generated for the files and images in this component.
+	SBOM ZarfComponentSBOM `json:"sbom,omitempty"`
+
 	// Custom commands to run at various stages of a package lifecycle.
 	Actions ZarfComponentActions `json:"actions,omitempty"`
 
 	// List of resources to health check after deployment
 	HealthChecks []NamespacedObjectKindReference `json:"healthChecks,omitempty"`
+
+	// HTTP or TCP probes of the applications of the component to run after the health checks, for applications that are not working as soon as their pods are ready.
+	Readiness []ZarfComponentReadiness `json:"readiness,omitempty"`
+
+	// The maximum time to wait for the charts, manifests, health checks and readiness probes of the component (i.e. 15m), overriding the deploy timeout.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Named tunnels to the services of the component that are listed by zarf connect list and opened with zarf connect {NAME}.
+	Connect []ZarfComponentConnect `json:"connect,omitempty"`
 }
 
 // NamespacedObjectKindReference is a reference to a specific resource in a namespace using its kind and API version.
@@ -66,15 +93,48 @@ type NamespacedObjectKindReference struct {
 	Name string `json:"name"`
 }
 
+// ZarfComponentConnect is a named tunnel to a service of a component that can be opened with zarf connect.
+type ZarfComponentConnect struct {
+	// The name of the tunnel, passed to zarf connect.
+	Name string `json:"name" jsonschema:"pattern=^[a-z0-9][a-z0-9\\-]*$"`
+	// Text that explains what the service is used for, shown by zarf connect list.
+	Description string `json:"description,omitempty"`
+	// The namespace of the service.
+	Namespace string `json:"namespace"`
+	// The name of the service.
+	Service string `json:"service"`
+	// The port of the service to connect to.
+	Port int `json:"port" jsonschema:"example=80,example=9898"`
+	// URL path that is appended to the address of the tunnel.
+	URL string `json:"url,omitempty" jsonschema:"example=/admin"`
+}
+
+// ZarfComponentReadiness is an HTTP or TCP probe of an application that must pass after the component is deployed.
+type ZarfComponentReadiness struct {
+	// A name for the probe, shown in the deploy output.
+	Name string `json:"name"`
+	// The protocol to probe with.
+	Protocol string `json:"protocol" jsonschema:"enum=tcp,enum=http,enum=https"`
+	// The address to probe, a host of the form {SERVICE}.{NAMESPACE}.svc.cluster.local is reached through a tunnel to the service and ###ZARF_VAR_### and ###ZARF_CONST### templates are replaced.
+	Address string `json:"address" jsonschema:"example=podinfo.podinfo.svc.cluster.local:9898/readyz,example=postgres.db.svc.cluster.local:5432"`
+	// The HTTP status code to expect if using http or https (default 200).
+	Code int `json:"code,omitempty" jsonschema:"example=200,example=204"`
+	// Timeout in seconds for the probe to pass (defaults to the timeout of the component).
+	MaxTotalSeconds int `json:"maxTotalSeconds,omitempty"`
+	// Number of failed attempts after which the probe fails (default 0, retry until the timeout).
+	MaxRetries int `json:"maxRetries,omitempty"`
+}
+
 // RequiresCluster returns if the component requires a cluster connection to deploy.
 func (c ZarfComponent) RequiresCluster() bool {
-	hasImages := len(c.Images) > 0
+	hasImages := len(c.Images) > 0 || len(c.Artifacts) > 0
 	hasCharts := len(c.Charts) > 0
 	hasManifests := len(c.Manifests) > 0
+	hasPolicies := len(c.Policies) > 0
 	hasRepos := len(c.Repos) > 0
 	hasDataInjections := len(c.DataInjections) > 0
 
-	if hasImages || hasCharts || hasManifests || hasRepos || hasDataInjections {
+	if hasImages || hasCharts || hasManifests || hasPolicies || hasRepos || hasDataInjections {
 		return true
 	}
 
@@ -95,8 +155,10 @@ type ZarfComponentOnlyTarget struct {
 	LocalOS string `json:"localOS,omitempty" jsonschema:"enum=linux,enum=darwin,enum=windows"`
 	// Only deploy component to specified clusters.
 	Cluster ZarfComponentOnlyCluster `json:"cluster,omitempty"`
-	// Only include this component when a matching '--flavor' is specified on 'zarf package create'.
-	Flavor string `json:"flavor,omitempty"`
+	// Only include this component when one of the listed flavors, or a flavor inheriting one of them, is specified with '--flavor' on 'zarf package create'.
+	Flavor FlavorList `json:"flavor,omitempty"`
+	// Only include this component when the expression is true on 'zarf package create', e.g. arch == "amd64" && vars.GPU == "true" where vars are the package templates given with '--set'.
+	Expression string `json:"expression,omitempty"`
 }
 
 // ZarfComponentOnlyCluster represents the architecture and K8s cluster distribution to filter on.
@@ -147,6 +209,10 @@ type ZarfChart struct {
 	ValuesFiles []string `json:"valuesFiles,omitempty"`
 	// [alpha] List of variables to set in the Helm chart.
 	Variables []ZarfChartVariable `json:"variables,omitempty"`
+	// Controls for the Helm hooks of the chart, for upstream hooks that cannot run in the target environment.
+	Hooks ZarfChartHooks `json:"hooks,omitempty"`
+	// The maximum time to wait for the chart to install or upgrade and its resources to be ready (i.e. 30m), overriding the timeout of the component.
+	Timeout string `json:"timeout,omitempty"`
 }
 
 // HelmRepoSource represents a Helm chart stored in a Helm repository.
@@ -177,6 +243,16 @@ type OCISource struct {
 	URL string `json:"url"`
 }
 
+// ZarfChartHooks controls how the Helm hooks of a chart are run on deploy.
+type ZarfChartHooks struct {
+	// Hook events whose hooks are not run (i.e. pre-delete to not run an upstream cleanup hook that reaches the internet).
+	Skip []string `json:"skip,omitempty" jsonschema:"enum=pre-install,enum=post-install,enum=pre-upgrade,enum=post-upgrade,enum=pre-delete,enum=post-delete,enum=pre-rollback,enum=post-rollback,enum=test"`
+	// Install and upgrade hook events whose hooks are deployed as regular resources of the release instead of being run as hooks.
+	Convert []string `json:"convert,omitempty" jsonschema:"enum=pre-install,enum=post-install,enum=pre-upgrade,enum=post-upgrade"`
+	// The maximum time a hook Job or Pod of the chart may run before it is failed (i.e. 5m), set as its activeDeadlineSeconds.
+	Timeout string `json:"timeout,omitempty"`
+}
+
 // ZarfChartVariable represents a variable that can be set for a Helm chart overrides.
 type ZarfChartVariable struct {
 	// The name of the variable.
@@ -201,6 +277,42 @@ type ZarfManifest struct {
 	Kustomizations []string `json:"kustomizations,omitempty"`
 	// Whether to not wait for manifest resources to be ready before continuing. (Defaults to true)
 	Wait *bool `json:"wait,omitempty"`
+	// The maximum time to wait for the manifests to be applied and their resources to be ready (i.e. 10m), overriding the timeout of the component.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// ZarfPolicy defines a bundle of admission policies to install in the cluster.
+type ZarfPolicy struct {
+	// A name to give this policy bundle; this will become the name of the dynamically-created helm chart.
+	Name string `json:"name"`
+	// The policy engine the bundle is written for.
+	Engine string `json:"engine" jsonschema:"enum=kyverno,enum=gatekeeper"`
+	// The namespace to deploy namespaced policies to.
+	Namespace string `json:"namespace,omitempty"`
+	// List of local policy YAML files or remote URLs to deploy (in order).
+	Files []string `json:"files"`
+}
+
+// ZarfPolicyGate defines Rego or CEL policies that the rendered manifests of a component are checked against on deploy.
+type ZarfPolicyGate struct {
+	// A name for the policy gate, shown in the deploy output.
+	Name string `json:"name"`
+	// The language the policies are written in, Rego policies deny objects with the messages of the deny rules of package zarf and CEL policies are YAML files of validations with an expression and message.
+	Engine string `json:"engine" jsonschema:"enum=rego,enum=cel"`
+	// List of local policy files or remote URLs to check the rendered manifests against.
+	Files []string `json:"files"`
+}
+
+// ZarfComponentSBOM defines how SBOMs are generated for a component's files and images.
+type ZarfComponentSBOM struct {
+	// Do not generate SBOMs for the files, repos and images in this component.
+	Skip bool `json:"skip,omitempty"`
+	// Glob patterns of paths to leave out of the SBOMs. Image paths are matched against the path in the image (e.g. /opt/app/**) and file and repo paths against the path in the component (e.g. files/0/** or repos/**).
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+	// Remove the licenses (including any license texts) from the packages in the SBOMs.
+	StripLicenses bool `json:"stripLicenses,omitempty"`
+	// Remove the file hashes from the packages and files in the SBOMs.
+	OmitFileHashes bool `json:"omitFileHashes,omitempty"`
 }
 
 // ZarfComponentActions are ActionSets that map to different zarf package operations.
@@ -241,6 +353,8 @@ type ZarfComponentActionDefaults struct {
 	Env []string `json:"env,omitempty"`
 	// (cmd only) Indicates a preference for a shell for the provided cmd to be executed in on supported operating systems.
 	Shell Shell `json:"shell,omitempty"`
+	// (cmd only) The image (image:tag) of an ephemeral container to run commands in instead of on the host, with the working directory mounted.
+	RunIn string `json:"runIn,omitempty"`
 }
 
 // ZarfComponentAction represents a single action to run during a zarf package operation.
@@ -259,6 +373,8 @@ type ZarfComponentAction struct {
 	Cmd string `json:"cmd,omitempty"`
 	// (cmd only) Indicates a preference for a shell for the provided cmd to be executed in on supported operating systems.
 	Shell *Shell `json:"shell,omitempty"`
+	// (cmd only) The image (image:tag) of an ephemeral container to run the command in instead of on the host, with the working directory mounted.
+	RunIn *string `json:"runIn,omitempty"`
 	// (onDeploy/cmd only) An array of variables to update with the output of the command. These variables will be available to all remaining actions and components in the package.
 	SetVariables []Variable `json:"setVariables,omitempty"`
 	// Description of the action to be displayed during package execution instead of the command.
@@ -323,10 +439,14 @@ type ZarfDataInjection struct {
 type ZarfComponentImport struct {
 	// The name of the component to import from the referenced zarf.yaml.
 	Name string `json:"name,omitempty"`
-	// The path to the directory containing the zarf.yaml to import.
+	// The path to the directory containing the zarf.yaml to import, or workspace:<name> to import from a package in the same workspace.
 	Path string `json:"path,omitempty"`
-	// [beta] The URL to a Zarf package to import via OCI.
-	URL string `json:"url,omitempty" jsonschema:"pattern=^oci://.*$"`
+	// [beta] The URL to a Zarf package to import via OCI, or git:<repo-url>@<ref>//<path> to import from a package in a git repository.
+	URL string `json:"url,omitempty" jsonschema:"pattern=^(oci://|git:).*$"`
+	// Set to "*" to import every component of the referenced zarf.yaml instead of a single component by name.
+	Components string `json:"components,omitempty" jsonschema:"enum=*"`
+	// A prefix added to the names of the components imported with components: "*".
+	Prefix string `json:"prefix,omitempty" jsonschema:"pattern=^[a-z0-9][a-z0-9\\-]*$"`
 }
 
 // JSONSchemaExtend extends the generated json schema during `zarf internal gen-config-schema`
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package v1beta1 holds the definition of the v1beta1 Zarf Package
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+)
+
+// FlavorList is a list of flavors that can also be written as a single flavor.
+type FlavorList []string
+
+// UnmarshalJSON reads a flavor list from either a string or a list of strings.
+func (f *FlavorList) UnmarshalJSON(b []byte) error {
+	var flavor string
+	if err := json.Unmarshal(b, &flavor); err == nil {
+		*f = FlavorList{flavor}
+		if flavor == "" {
+			*f = nil
+		}
+		return nil
+	}
+	var flavors []string
+	if err := json.Unmarshal(b, &flavors); err != nil {
+		return fmt.Errorf("flavor must be a string or a list of strings: %w", err)
+	}
+	*f = flavors
+	return nil
+}
+
+// UnmarshalYAML reads a flavor list from either a string or a list of strings.
+func (f *FlavorList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var flavor string
+	if err := unmarshal(&flavor); err == nil {
+		*f = FlavorList{flavor}
+		if flavor == "" {
+			*f = nil
+		}
+		return nil
+	}
+	var flavors []string
+	if err := unmarshal(&flavors); err != nil {
+		return fmt.Errorf("flavor must be a string or a list of strings: %w", err)
+	}
+	*f = flavors
+	return nil
+}
+
+// MarshalJSON writes a single flavor as a string so that packages stay readable by older versions of Zarf.
+func (f FlavorList) MarshalJSON() ([]byte, error) {
+	if len(f) == 1 {
+		return json.Marshal(f[0])
+	}
+	return json.Marshal([]string(f))
+}
+
+// MarshalYAML writes a single flavor as a string so that packages stay readable by older versions of Zarf.
+func (f FlavorList) MarshalYAML() (interface{}, error) {
+	if len(f) == 1 {
+		return f[0], nil
+	}
+	return []string(f), nil
+}
+
+// JSONSchema allows a flavor list to be written as a single flavor in the generated json schema.
+func (FlavorList) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}
+}
+
+// ZarfFlavor defines a flavor of the package and the flavors it inherits components from.
+type ZarfFlavor struct {
+	// The name of the flavor, as given to '--flavor' on 'zarf package create'.
+	Name string `json:"name" jsonschema:"pattern=^[a-zA-Z0-9\\-_.]+$"`
+	// Description of the flavor.
+	Description string `json:"description,omitempty"`
+	// Flavors whose components are also included when this flavor is created, components limited to this flavor take precedence over components of the same name limited to an inherited flavor.
+	Inherits []string `json:"inherits,omitempty"`
+}
@@ -52,6 +52,12 @@ type ZarfPackage struct {
 	Constants []Constant `json:"constants,omitempty"`
 	// Variable template values applied on deploy for K8s resources.
 	Variables []InteractiveVariable `json:"variables,omitempty"`
+	// Custom commands to run once before and after the components of the package are created, deployed or removed.
+	Actions ZarfComponentActions `json:"actions,omitempty"`
+	// Local files or directories of documentation to include in the package as a separate layer that can be pulled without the rest of the package.
+	Docs []string `json:"docs,omitempty"`
+	// Flavors of the package and the flavors they inherit components from.
+	Flavors []ZarfFlavor `json:"flavors,omitempty"`
 }
 
 // IsInitConfig returns whether a Zarf package is an init config.
@@ -143,8 +149,12 @@ type ZarfMetadata struct {
 	Uncompressed bool `json:"uncompressed,omitempty"`
 	// The target cluster architecture for this package.
 	Architecture string `json:"architecture,omitempty" jsonschema:"example=arm64,example=amd64"`
+	// The target cluster architectures for a single package built for multiple architectures (e.g. [amd64, arm64]), overrides architecture.
+	Architectures []string `json:"architectures,omitempty"`
 	// Default to true, when false components cannot have images or git repos as they will be pulled from the internet
 	Airgap *bool `json:"airgap,omitempty"`
+	// PEM encoded public key (or a path to one on create) that entitlement tokens for gated components are verified against on deploy.
+	EntitlementKey string `json:"entitlementKey,omitempty"`
 	// Annotations are key-value pairs that can be used to store metadata about the package.
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
@@ -171,10 +181,18 @@ type ZarfBuildData struct {
 	Differential bool `json:"differential,omitempty"`
 	// Version of a previously built package used as the basis for creating this differential package.
 	DifferentialPackageVersion string `json:"differentialPackageVersion,omitempty"`
+	// Aggregate checksum of the previously built package used as the basis for creating this differential package.
+	DifferentialPackageChecksum string `json:"differentialPackageChecksum,omitempty"`
 	// List of components that were not included in this package due to differential packaging.
 	DifferentialMissing []string `json:"differentialMissing,omitempty"`
+	// SBOM rules that skipped or redacted the SBOMs of components in this package, keyed by component name.
+	SBOMRedactions map[string]ZarfComponentSBOM `json:"sbomRedactions,omitempty"`
+	// The formats the SBOMs in this package were created in.
+	SBOMFormats []string `json:"sbomFormats,omitempty"`
 	// The minimum version of Zarf that does not have breaking package structure changes.
 	LastNonBreakingVersion string `json:"lastNonBreakingVersion,omitempty"`
 	// The flavor of Zarf used to build this package.
 	Flavor string `json:"flavor,omitempty"`
+	// The Zarf features this package relies on, checked against the deploying version of Zarf.
+	Features []string `json:"features,omitempty"`
 }
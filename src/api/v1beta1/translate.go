@@ -85,6 +85,9 @@ func TranslateAlphaPackage(alphaPkg v1alpha1.ZarfPackage) (ZarfPackage, error) {
 		betaPkg.Components[i].Actions.OnDeploy = transformActionSet(betaPkg.Components[i].Actions.OnDeploy, alphaPkg.Components[i].Actions.OnDeploy)
 		betaPkg.Components[i].Actions.OnRemove = transformActionSet(betaPkg.Components[i].Actions.OnRemove, alphaPkg.Components[i].Actions.OnRemove)
 	}
+	betaPkg.Actions.OnCreate = transformActionSet(betaPkg.Actions.OnCreate, alphaPkg.Actions.OnCreate)
+	betaPkg.Actions.OnDeploy = transformActionSet(betaPkg.Actions.OnDeploy, alphaPkg.Actions.OnDeploy)
+	betaPkg.Actions.OnRemove = transformActionSet(betaPkg.Actions.OnRemove, alphaPkg.Actions.OnRemove)
 
 	return betaPkg, nil
 }
@@ -30,6 +30,19 @@ func TestTranslate(t *testing.T) {
 			oldPkg: v1alpha1.ZarfPackage{
 				APIVersion: v1alpha1.APIVersion,
 				Kind:       v1alpha1.ZarfPackageConfig,
+				Actions: v1alpha1.ZarfComponentActions{
+					OnDeploy: v1alpha1.ZarfComponentActionSet{
+						Defaults: v1alpha1.ZarfComponentActionDefaults{
+							MaxTotalSeconds: 30,
+						},
+						After: []v1alpha1.ZarfComponentAction{
+							{
+								MaxTotalSeconds: &maxSeconds,
+								MaxRetries:      &maxRetries,
+							},
+						},
+					},
+				},
 				Components: []v1alpha1.ZarfComponent{
 					{
 						Name:     "optional",
@@ -130,6 +143,19 @@ func TestTranslate(t *testing.T) {
 				Metadata: ZarfMetadata{
 					Annotations: map[string]string{},
 				},
+				Actions: ZarfComponentActions{
+					OnDeploy: ZarfComponentActionSet{
+						Defaults: ZarfComponentActionDefaults{
+							Timeout: &v1.Duration{Duration: time.Duration(time.Second * 30)},
+						},
+						After: []ZarfComponentAction{
+							{
+								Timeout: &v1.Duration{Duration: time.Duration(time.Second * 60)},
+								Retries: 10,
+							},
+						},
+					},
+				},
 				Components: []ZarfComponent{
 					{
 						Name:     "optional",
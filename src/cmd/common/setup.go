@@ -9,10 +9,11 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"time"
+	"path/filepath"
 
 	"github.com/pterm/pterm"
 
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
@@ -46,17 +47,17 @@ func SetupCLI(logLevel string, skipLogFile, noColor bool) error {
 	}
 
 	if !skipLogFile {
-		ts := time.Now().Format("2006-01-02-15-04-05")
-		f, err := os.CreateTemp("", fmt.Sprintf("zarf-%s-*.log", ts))
+		logDir := filepath.Join(config.GetAbsCachePath(), "logs")
+		w, err := message.NewRotatingWriter(logDir, message.LogFileMaxSize, message.LogFileMaxAge, message.LogFileMaxBackups)
 		if err != nil {
-			return fmt.Errorf("could not create a log file in a the temporary directory: %w", err)
+			return fmt.Errorf("could not open a log file in %s: %w", logDir, err)
 		}
-		logFile, err := message.UseLogFile(f)
+		logFile, err := message.UseLogFile(w)
 		if err != nil {
-			return fmt.Errorf("could not save a log file to the temporary directory: %w", err)
+			return fmt.Errorf("could not save a log file to %s: %w", logDir, err)
 		}
 		pterm.SetDefaultOutput(io.MultiWriter(os.Stderr, logFile))
-		message.Notef("Saving log file to %s", f.Name())
+		message.Notef("Saving log file to %s with correlation ID %s", w.Path(), message.CorrelationID())
 	}
 	return nil
 }
@@ -6,12 +6,14 @@ package common
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/fatih/color"
 
 	"github.com/zarf-dev/zarf/src/pkg/lint"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/composer"
 )
 
 // PrintFindings prints the findings in the LintError as a table.
@@ -35,7 +37,7 @@ func PrintFindings(lintErr *lint.LintError) {
 			})
 		}
 		var packagePathFromUser string
-		if helpers.IsOCIURL(findings[0].PackagePathOverride) {
+		if helpers.IsOCIURL(findings[0].PackagePathOverride) || strings.HasPrefix(findings[0].PackagePathOverride, composer.GitImportPrefix) {
 			packagePathFromUser = findings[0].PackagePathOverride
 		} else {
 			packagePathFromUser = filepath.Join(lintErr.BaseDir, findings[0].PackagePathOverride)
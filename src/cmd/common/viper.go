@@ -7,6 +7,7 @@ package common
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -26,15 +27,26 @@ const (
 	VNoProgress            = "no_progress"
 	VNoColor               = "no_color"
 	VZarfCache             = "zarf_cache"
+	VNoCache               = "no_cache"
 	VTmpDir                = "tmp_dir"
 	VInsecure              = "insecure"
 	VPlainHTTP             = "plain_http"
 	VInsecureSkipTLSVerify = "insecure_skip_tls_verify"
+	VCACerts               = "ca_certs"
+	VClientCert            = "client_cert"
+	VClientKey             = "client_key"
+	VProxy                 = "proxy"
+	VRegistryMirrors       = "registry_mirrors"
+	VKubeQPS               = "kube_qps"
+	VKubeBurst             = "kube_burst"
+	VKubeConfig            = "kubeconfig"
+	VKubeContexts          = "kube_contexts"
 
 	// Init config keys
 
-	VInitComponents   = "init.components"
-	VInitStorageClass = "init.storage_class"
+	VInitComponents         = "init.components"
+	VInitStorageClass       = "init.storage_class"
+	VInitStateEncryptionKey = "init.state_encryption_key"
 
 	// Init Git config keys
 
@@ -44,6 +56,11 @@ const (
 	VInitGitPullUser = "init.git.pull_username"
 	VInitGitPullPass = "init.git.pull_password"
 
+	VInitGitResources    = "init.git.resources"
+	VInitGitPVCSize      = "init.git.pvc_size"
+	VInitGitNodeSelector = "init.git.node_selector"
+	VInitGitTolerations  = "init.git.tolerations"
+
 	// Init Registry config keys
 
 	VInitRegistryURL      = "init.registry.url"
@@ -53,6 +70,18 @@ const (
 	VInitRegistryPushPass = "init.registry.push_password"
 	VInitRegistryPullUser = "init.registry.pull_username"
 	VInitRegistryPullPass = "init.registry.pull_password"
+	VInitRegistryType     = "init.registry.type"
+
+	VInitRegistryResources    = "init.registry.resources"
+	VInitRegistryPVCSize      = "init.registry.pvc_size"
+	VInitRegistryNodeSelector = "init.registry.node_selector"
+	VInitRegistryTolerations  = "init.registry.tolerations"
+
+	// Init Agent config keys
+
+	VInitAgentResources    = "init.agent.resources"
+	VInitAgentNodeSelector = "init.agent.node_selector"
+	VInitAgentTolerations  = "init.agent.tolerations"
 
 	// Init Package config keys
 
@@ -60,39 +89,83 @@ const (
 	VInitArtifactPushUser  = "init.artifact.push_username"
 	VInitArtifactPushToken = "init.artifact.push_token"
 
+	// Init PKI config keys
+
+	VInitCACert = "init.pki.ca_cert"
+	VInitCAKey  = "init.pki.ca_key"
+	VInitTLSSAN = "init.pki.tls_san"
+
 	// Package config keys
 
-	VPkgOCIConcurrency = "package.oci_concurrency"
-	VPkgPublicKey      = "package.public_key"
+	VPkgOCIConcurrency        = "package.oci_concurrency"
+	VPkgPublicKey             = "package.public_key"
+	VPkgDecryptionKey         = "package.decryption_key"
+	VPkgDecryptionPassphrase  = "package.decryption_passphrase"
+	VPkgCertificateIdentity   = "package.certificate_identity"
+	VPkgCertificateOIDCIssuer = "package.certificate_oidc_issuer"
+	VPkgTrustedRoot           = "package.trusted_root"
+	VPkgSourceResolvers       = "package.source_resolvers"
 
 	// Package create config keys
 
-	VPkgCreateSet                = "package.create.set"
-	VPkgCreateOutput             = "package.create.output"
-	VPkgCreateSbom               = "package.create.sbom"
-	VPkgCreateSbomOutput         = "package.create.sbom_output"
-	VPkgCreateSkipSbom           = "package.create.skip_sbom"
-	VPkgCreateMaxPackageSize     = "package.create.max_package_size"
-	VPkgCreateSigningKey         = "package.create.signing_key"
-	VPkgCreateSigningKeyPassword = "package.create.signing_key_password"
-	VPkgCreateDifferential       = "package.create.differential"
-	VPkgCreateRegistryOverride   = "package.create.registry_override"
-	VPkgCreateFlavor             = "package.create.flavor"
+	VPkgCreateSet                  = "package.create.set"
+	VPkgCreateEnvFiles             = "package.create.env_files"
+	VPkgCreateOutput               = "package.create.output"
+	VPkgCreateSbom                 = "package.create.sbom"
+	VPkgCreateSbomOutput           = "package.create.sbom_output"
+	VPkgCreateSkipSbom             = "package.create.skip_sbom"
+	VPkgCreateSbomFormats          = "package.create.sbom_formats"
+	VPkgCreateMaxPackageSize       = "package.create.max_package_size"
+	VPkgCreateMaxArtifactSize      = "package.create.max_artifact_size"
+	VPkgCreateSigningKey           = "package.create.signing_key"
+	VPkgCreateSigningKeyPassword   = "package.create.signing_key_password"
+	VPkgCreateDifferential         = "package.create.differential"
+	VPkgCreateRegistryOverride     = "package.create.registry_override"
+	VPkgCreateFlavor               = "package.create.flavor"
+	VPkgCreateAllFlavors           = "package.create.all_flavors"
+	VPkgCreateIncludeSignatures    = "package.create.include_signatures"
+	VPkgCreateLocked               = "package.create.locked"
+	VPkgCreateDryRun               = "package.create.dry_run"
+	VPkgCreateChecksumDB           = "package.create.checksum_db"
+	VPkgCreateEncryptionKey        = "package.create.encryption_key"
+	VPkgCreateEncryptionPassphrase = "package.create.encryption_passphrase"
+	VPkgCreateKeyless              = "package.create.keyless"
+	VPkgCreateFulcioURL            = "package.create.fulcio_url"
+	VPkgCreateRekorURL             = "package.create.rekor_url"
+	VPkgCreateOIDCIssuer           = "package.create.oidc_issuer"
+	VPkgCreateIdentityToken        = "package.create.identity_token"
 
 	// Package deploy config keys
 
-	VPkgDeploySet          = "package.deploy.set"
-	VPkgDeployComponents   = "package.deploy.components"
-	VPkgDeployShasum       = "package.deploy.shasum"
-	VPkgDeploySget         = "package.deploy.sget"
-	VPkgDeploySkipWebhooks = "package.deploy.skip_webhooks"
-	VPkgDeployTimeout      = "package.deploy.timeout"
-	VPkgRetries            = "package.deploy.retries"
+	VPkgDeploySet               = "package.deploy.set"
+	VPkgDeployComponents        = "package.deploy.components"
+	VPkgDeployShasum            = "package.deploy.shasum"
+	VPkgDeploySourceMirrors     = "package.deploy.source_mirrors"
+	VPkgDeployArchiveChecksum   = "package.deploy.archive_checksum"
+	VPkgDeployArchiveSignature  = "package.deploy.archive_signature"
+	VPkgDeployArchiveKey        = "package.deploy.archive_key"
+	VPkgDeploySget              = "package.deploy.sget"
+	VPkgDeploySkipWebhooks      = "package.deploy.skip_webhooks"
+	VPkgDeployTimeout           = "package.deploy.timeout"
+	VPkgDeployComponentTimeouts = "package.deploy.component_timeouts"
+	VPkgDeployEntitlements      = "package.deploy.entitlements"
+	VPkgDeployVariableOverlays  = "package.deploy.variable_overlays"
+	VPkgDeployPublishStatus     = "package.deploy.publish_status"
+	VPkgDeployConcurrency       = "package.deploy.concurrency"
+	VPkgDeployRollbackOnFail    = "package.deploy.rollback_on_failure"
+	VPkgDeployPolicyGates       = "package.deploy.policy_gates"
+	VPkgDeployDryRun            = "package.deploy.dry_run"
+	VPkgRetries                 = "package.deploy.retries"
 
 	// Package publish config keys
 
 	VPkgPublishSigningKey         = "package.publish.signing_key"
 	VPkgPublishSigningKeyPassword = "package.publish.signing_key_password"
+	VPkgPublishKeyless            = "package.publish.keyless"
+	VPkgPublishFulcioURL          = "package.publish.fulcio_url"
+	VPkgPublishRekorURL           = "package.publish.rekor_url"
+	VPkgPublishOIDCIssuer         = "package.publish.oidc_issuer"
+	VPkgPublishIdentityToken      = "package.publish.identity_token"
 
 	// Package pull config keys
 
@@ -109,6 +182,9 @@ var (
 
 	// Viper configuration error
 	vConfigError error
+
+	// Config files that were read, in the order they were merged
+	vConfigFiles []string
 )
 
 // InitViper initializes the viper singleton for the CLI
@@ -125,13 +201,15 @@ func InitViper() *viper.Viper {
 		return v
 	}
 
-	// Specify an alternate config file
-	cfgFile := os.Getenv("ZARF_CONFIG")
+	// Specify alternate config files, later files are layered on top of earlier ones (e.g. base.yaml:overlay.yaml)
+	cfgFiles := filepath.SplitList(os.Getenv("ZARF_CONFIG"))
+	var overlays []string
 
 	// Don't forget to read config either from cfgFile or from home directory!
-	if cfgFile != "" {
+	if len(cfgFiles) > 0 {
 		// Use config file from the flag.
-		v.SetConfigFile(cfgFile)
+		v.SetConfigFile(cfgFiles[0])
+		overlays = cfgFiles[1:]
 	} else {
 		// Search config paths in the current directory and $HOME/.zarf.
 		v.AddConfigPath(".")
@@ -146,6 +224,16 @@ func InitViper() *viper.Viper {
 
 	// Optional, so ignore errors
 	vConfigError = v.ReadInConfig()
+	if vConfigError == nil {
+		vConfigFiles = append(vConfigFiles, v.ConfigFileUsed())
+		for _, overlay := range overlays {
+			v.SetConfigFile(overlay)
+			if vConfigError = v.MergeInConfig(); vConfigError != nil {
+				break
+			}
+			vConfigFiles = append(vConfigFiles, overlay)
+		}
+	}
 
 	// Set default values for viper
 	setDefaults()
@@ -177,7 +265,7 @@ func printViperConfigUsed() {
 		message.WarnErrf(vConfigError, lang.CmdViperErrLoadingConfigFile, vConfigError.Error())
 		return
 	}
-	if cfgFile := v.ConfigFileUsed(); cfgFile != "" {
+	for _, cfgFile := range vConfigFiles {
 		message.Notef(lang.CmdViperInfoUsingConfigFile, cfgFile)
 	}
 }
@@ -186,6 +274,8 @@ func setDefaults() {
 	// Root defaults that are non-zero values
 	v.SetDefault(VLogLevel, "info")
 	v.SetDefault(VZarfCache, config.ZarfDefaultCachePath)
+	v.SetDefault(VKubeQPS, config.ZarfDefaultKubeQPS)
+	v.SetDefault(VKubeBurst, config.ZarfDefaultKubeBurst)
 
 	// Package defaults that are non-zero values
 	v.SetDefault(VPkgOCIConcurrency, 3)
@@ -193,4 +283,5 @@ func setDefaults() {
 
 	// Deploy opts that are non-zero values
 	v.SetDefault(VPkgDeployTimeout, config.ZarfDefaultTimeout)
+	v.SetDefault(VPkgDeployConcurrency, 1)
 }
@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -13,11 +14,13 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 var (
-	cliOnly bool
-	zt      cluster.TunnelInfo
+	cliOnly     bool
+	zt          cluster.TunnelInfo
+	statusWatch bool
 )
 var connectCmd = &cobra.Command{
 	Use:     "connect { REGISTRY | GIT | connect-name }",
@@ -103,9 +106,79 @@ var connectListCmd = &cobra.Command{
 	},
 }
 
+var connectStatusCmd = &cobra.Command{
+	Use:   "status [ PACKAGE ]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: lang.CmdConnectStatusShort,
+	Long:  lang.CmdConnectStatusLong,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		packageName := ""
+		if len(args) > 0 {
+			packageName = args[0]
+		}
+
+		ctx := cmd.Context()
+		c, err := cluster.NewCluster()
+		if err != nil {
+			return err
+		}
+		agent, err := c.NewAgentClient(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to connect to the Zarf agent: %w", err)
+		}
+		defer agent.Close()
+
+		lastUpdated := map[string]time.Time{}
+		for {
+			statuses, err := agent.DeployStatuses(ctx, packageName)
+			if err != nil {
+				return err
+			}
+			if len(statuses) == 0 {
+				message.Note(lang.CmdConnectStatusNone)
+				return nil
+			}
+
+			// Only print the statuses again when a deploy has made progress.
+			changed, deploying := false, false
+			for _, status := range statuses {
+				if !lastUpdated[status.Package].Equal(status.Updated) {
+					changed = true
+					lastUpdated[status.Package] = status.Updated
+				}
+				if status.Phase == types.ComponentStatusDeploying {
+					deploying = true
+				}
+			}
+			if changed {
+				message.PrintDeployStatusTable(statuses)
+			}
+			if !statusWatch {
+				return nil
+			}
+
+			if !deploying {
+				for _, status := range statuses {
+					if status.Phase == types.ComponentStatusFailed {
+						return fmt.Errorf("deploy of package %s failed: %s", status.Package, status.Error)
+					}
+				}
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(2 * time.Second):
+			}
+		}
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(connectCmd)
 	connectCmd.AddCommand(connectListCmd)
+	connectCmd.AddCommand(connectStatusCmd)
 
 	connectCmd.Flags().StringVar(&zt.ResourceName, "name", "", lang.CmdConnectFlagName)
 	connectCmd.Flags().StringVar(&zt.Namespace, "namespace", cluster.ZarfNamespaceName, lang.CmdConnectFlagNamespace)
@@ -113,4 +186,6 @@ func init() {
 	connectCmd.Flags().IntVar(&zt.LocalPort, "local-port", 0, lang.CmdConnectFlagLocalPort)
 	connectCmd.Flags().IntVar(&zt.RemotePort, "remote-port", 0, lang.CmdConnectFlagRemotePort)
 	connectCmd.Flags().BoolVar(&cliOnly, "cli-only", false, lang.CmdConnectFlagCliOnly)
+
+	connectStatusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, lang.CmdConnectStatusFlagWatch)
 }
@@ -8,8 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -25,6 +27,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/lint"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager"
+	"github.com/zarf-dev/zarf/src/pkg/packager/workspace"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
@@ -286,6 +289,24 @@ var devLintCmd = &cobra.Command{
 		pkgConfig.CreateOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgCreateSet), pkgConfig.CreateOpts.SetVariables, strings.ToUpper)
 
+		if workspace.IsWorkspace(pkgConfig.CreateOpts.BaseDir) {
+			lintErrs, err := lint.ValidateWorkspace(cmd.Context(), pkgConfig.CreateOpts.BaseDir, pkgConfig.CreateOpts.Flavor, pkgConfig.CreateOpts.SetVariables)
+			if err != nil {
+				return err
+			}
+			failed := 0
+			for _, lintErr := range lintErrs {
+				common.PrintFindings(lintErr)
+				if !lintErr.OnlyWarnings() {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf(lang.CmdDevLintErrWorkspace, failed)
+			}
+			return nil
+		}
+
 		err := lint.Validate(cmd.Context(), pkgConfig.CreateOpts.BaseDir, pkgConfig.CreateOpts.Flavor, pkgConfig.CreateOpts.SetVariables)
 		var lintErr *lint.LintError
 		if errors.As(err, &lintErr) {
@@ -302,6 +323,77 @@ var devLintCmd = &cobra.Command{
 	},
 }
 
+var devInspectCmd = &cobra.Command{
+	Use:   "inspect [ DIRECTORY ]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: lang.CmdDevInspectShort,
+	Long:  lang.CmdDevInspectLong,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pkgConfig.CreateOpts.BaseDir = common.SetBaseDirectory(args)
+
+		pkgClient, err := packager.New(&pkgConfig)
+		if err != nil {
+			return err
+		}
+		defer pkgClient.ClearTempPaths()
+
+		if err := pkgClient.InspectDefinition(cmd.Context()); err != nil {
+			return fmt.Errorf("unable to inspect package definition: %w", err)
+		}
+		return nil
+	},
+}
+
+var devLockCmd = &cobra.Command{
+	Use:   "lock [ DIRECTORY ]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: lang.CmdDevLockShort,
+	Long:  lang.CmdDevLockLong,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pkgConfig.CreateOpts.BaseDir = common.SetBaseDirectory(args)
+
+		v := common.GetViper()
+		pkgConfig.CreateOpts.SetVariables = helpers.TransformAndMergeMap(
+			v.GetStringMapString(common.VPkgCreateSet), pkgConfig.CreateOpts.SetVariables, strings.ToUpper)
+
+		pkgClient, err := packager.New(&pkgConfig)
+		if err != nil {
+			return err
+		}
+		defer pkgClient.ClearTempPaths()
+
+		if err := pkgClient.Lock(cmd.Context()); err != nil {
+			return fmt.Errorf("unable to lock package inputs: %w", err)
+		}
+		return nil
+	},
+}
+
+var devTreeCmd = &cobra.Command{
+	Use:   "tree [ DIRECTORY ]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: lang.CmdDevTreeShort,
+	Long:  lang.CmdDevTreeLong,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pkgConfig.CreateOpts.BaseDir = common.SetBaseDirectory(args)
+
+		v := common.GetViper()
+		pkgConfig.CreateOpts.SetVariables = helpers.TransformAndMergeMap(
+			v.GetStringMapString(common.VPkgCreateSet), pkgConfig.CreateOpts.SetVariables, strings.ToUpper)
+
+		pkgClient, err := packager.New(&pkgConfig)
+		if err != nil {
+			return err
+		}
+		defer pkgClient.ClearTempPaths()
+
+		if err := pkgClient.InspectImportTree(cmd.Context()); err != nil {
+			return fmt.Errorf("unable to inspect the import tree: %w", err)
+		}
+		return nil
+	},
+}
+
 func init() {
 	v := common.GetViper()
 	rootCmd.AddCommand(devCmd)
@@ -313,6 +405,9 @@ func init() {
 	devCmd.AddCommand(devFindImagesCmd)
 	devCmd.AddCommand(devGenConfigFileCmd)
 	devCmd.AddCommand(devLintCmd)
+	devCmd.AddCommand(devInspectCmd)
+	devCmd.AddCommand(devLockCmd)
+	devCmd.AddCommand(devTreeCmd)
 
 	bindDevDeployFlags(v)
 	bindDevGenerateFlags(v)
@@ -335,11 +430,19 @@ func init() {
 	// skip searching cosign artifacts in find images
 	devFindImagesCmd.Flags().BoolVar(&pkgConfig.FindImagesOpts.SkipCosign, "skip-cosign", false, lang.CmdDevFlagFindImagesSkipCosign)
 
-	defaultRegistry := fmt.Sprintf("%s:%d", helpers.IPV4Localhost, types.ZarfInClusterContainerRegistryNodePort)
+	defaultRegistry := net.JoinHostPort(helpers.IPV4Localhost, strconv.Itoa(types.ZarfInClusterContainerRegistryNodePort))
 	devFindImagesCmd.Flags().StringVar(&pkgConfig.FindImagesOpts.RegistryURL, "registry-url", defaultRegistry, lang.CmdDevFlagFindImagesRegistry)
 
 	devLintCmd.Flags().StringToStringVar(&pkgConfig.CreateOpts.SetVariables, "set", v.GetStringMapString(common.VPkgCreateSet), lang.CmdPackageCreateFlagSet)
 	devLintCmd.Flags().StringVarP(&pkgConfig.CreateOpts.Flavor, "flavor", "f", v.GetString(common.VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
+	devInspectCmd.Flags().StringVarP(&pkgConfig.CreateOpts.Flavor, "flavor", "f", v.GetString(common.VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
+	devInspectCmd.Flags().BoolVar(&pkgConfig.InspectOpts.Provenance, "provenance", false, lang.CmdDevInspectFlagProvenance)
+	devLockCmd.Flags().StringToStringVar(&pkgConfig.CreateOpts.SetVariables, "set", v.GetStringMapString(common.VPkgCreateSet), lang.CmdPackageCreateFlagSet)
+	devLockCmd.Flags().StringVarP(&pkgConfig.CreateOpts.Flavor, "flavor", "f", v.GetString(common.VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
+	devLockCmd.Flags().StringToStringVar(&pkgConfig.CreateOpts.RegistryOverrides, "registry-override", v.GetStringMapString(common.VPkgCreateRegistryOverride), lang.CmdPackageCreateFlagRegistryOverride)
+	devLockCmd.Flags().BoolVar(&pkgConfig.CreateOpts.Locked, "locked", v.GetBool(common.VPkgCreateLocked), lang.CmdDevLockFlagLocked)
+	devTreeCmd.Flags().StringToStringVar(&pkgConfig.CreateOpts.SetVariables, "set", v.GetStringMapString(common.VPkgCreateSet), lang.CmdPackageCreateFlagSet)
+	devTreeCmd.Flags().StringVarP(&pkgConfig.CreateOpts.Flavor, "flavor", "f", v.GetString(common.VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
 	devTransformGitLinksCmd.Flags().StringVar(&pkgConfig.InitOpts.GitServer.PushUsername, "git-account", types.ZarfGitPushUser, lang.CmdDevFlagGitAccount)
 }
 
@@ -54,26 +54,25 @@ var initCmd = &cobra.Command{
 			return err
 		}
 
-		src, err := sources.New(&pkgConfig.PkgOpts)
-		if err != nil {
-			return err
-		}
-
 		v := common.GetViper()
 		pkgConfig.PkgOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgDeploySet), pkgConfig.PkgOpts.SetVariables, strings.ToUpper)
 
-		pkgClient, err := packager.New(&pkgConfig, packager.WithSource(src))
-		if err != nil {
-			return err
-		}
-		defer pkgClient.ClearTempPaths()
-
-		err = pkgClient.Deploy(cmd.Context())
-		if err != nil {
-			return err
+		deploy := func(ctx context.Context, kubeContext string) error {
+			cfg := deployConfig()
+			cfg.DeployOpts.KubeContext = kubeContext
+			src, err := sources.New(&cfg.PkgOpts)
+			if err != nil {
+				return err
+			}
+			pkgClient, err := packager.New(&cfg, packager.WithSource(src))
+			if err != nil {
+				return err
+			}
+			defer pkgClient.ClearTempPaths()
+			return pkgClient.Deploy(ctx)
 		}
-		return nil
+		return deployToKubeContexts(cmd.Context(), deploy)
 	},
 }
 
@@ -167,6 +166,16 @@ func validateInitFlags() error {
 		}
 	}
 
+	// The registry type selects the implementation of the internal registry, so it cannot be combined with 'registry-url'
+	switch pkgConfig.InitOpts.RegistryInfo.Type {
+	case "", types.RegistryTypeDistribution, types.RegistryTypeZot:
+	default:
+		return fmt.Errorf(lang.CmdInitErrValidateRegistryType, pkgConfig.InitOpts.RegistryInfo.Type)
+	}
+	if pkgConfig.InitOpts.RegistryInfo.Address != "" && pkgConfig.InitOpts.RegistryInfo.Type != "" {
+		return errors.New(lang.CmdInitErrValidateRegistryTypeExternal)
+	}
+
 	// If 'artifact-url' is provided, make sure they provided values for the username and password of the push user
 	if pkgConfig.InitOpts.ArtifactServer.Address != "" {
 		if pkgConfig.InitOpts.ArtifactServer.PushUsername == "" || pkgConfig.InitOpts.ArtifactServer.PushToken == "" {
@@ -209,18 +218,43 @@ func init() {
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.PullUsername, "registry-pull-username", v.GetString(common.VInitRegistryPullUser), lang.CmdInitFlagRegPullUser)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.PullPassword, "registry-pull-password", v.GetString(common.VInitRegistryPullPass), lang.CmdInitFlagRegPullPass)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryInfo.Secret, "registry-secret", v.GetString(common.VInitRegistrySecret), lang.CmdInitFlagRegSecret)
+	initCmd.Flags().StringVar((*string)(&pkgConfig.InitOpts.RegistryInfo.Type), "registry-type", v.GetString(common.VInitRegistryType), lang.CmdInitFlagRegType)
+
+	// Flags for the resources and scheduling of the registry, git server and agent
+	initCmd.Flags().StringToStringVar(&pkgConfig.InitOpts.RegistryOverrides.Resources, "registry-resources", v.GetStringMapString(common.VInitRegistryResources), lang.CmdInitFlagRegResources)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.RegistryOverrides.PVCSize, "registry-pvc-size", v.GetString(common.VInitRegistryPVCSize), lang.CmdInitFlagRegPVCSize)
+	initCmd.Flags().StringToStringVar(&pkgConfig.InitOpts.RegistryOverrides.NodeSelector, "registry-node-selector", v.GetStringMapString(common.VInitRegistryNodeSelector), lang.CmdInitFlagRegNodeSelector)
+	initCmd.Flags().StringSliceVar(&pkgConfig.InitOpts.RegistryOverrides.Tolerations, "registry-tolerations", v.GetStringSlice(common.VInitRegistryTolerations), lang.CmdInitFlagRegTolerations)
+	initCmd.Flags().StringToStringVar(&pkgConfig.InitOpts.GitServerOverrides.Resources, "git-resources", v.GetStringMapString(common.VInitGitResources), lang.CmdInitFlagGitResources)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.GitServerOverrides.PVCSize, "git-pvc-size", v.GetString(common.VInitGitPVCSize), lang.CmdInitFlagGitPVCSize)
+	initCmd.Flags().StringToStringVar(&pkgConfig.InitOpts.GitServerOverrides.NodeSelector, "git-node-selector", v.GetStringMapString(common.VInitGitNodeSelector), lang.CmdInitFlagGitNodeSelector)
+	initCmd.Flags().StringSliceVar(&pkgConfig.InitOpts.GitServerOverrides.Tolerations, "git-tolerations", v.GetStringSlice(common.VInitGitTolerations), lang.CmdInitFlagGitTolerations)
+	initCmd.Flags().StringToStringVar(&pkgConfig.InitOpts.AgentOverrides.Resources, "agent-resources", v.GetStringMapString(common.VInitAgentResources), lang.CmdInitFlagAgentResources)
+	initCmd.Flags().StringToStringVar(&pkgConfig.InitOpts.AgentOverrides.NodeSelector, "agent-node-selector", v.GetStringMapString(common.VInitAgentNodeSelector), lang.CmdInitFlagAgentNodeSelector)
+	initCmd.Flags().StringSliceVar(&pkgConfig.InitOpts.AgentOverrides.Tolerations, "agent-tolerations", v.GetStringSlice(common.VInitAgentTolerations), lang.CmdInitFlagAgentTolerations)
 
 	// Flags for using an external artifact server
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.ArtifactServer.Address, "artifact-url", v.GetString(common.VInitArtifactURL), lang.CmdInitFlagArtifactURL)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.ArtifactServer.PushUsername, "artifact-push-username", v.GetString(common.VInitArtifactPushUser), lang.CmdInitFlagArtifactPushUser)
 	initCmd.Flags().StringVar(&pkgConfig.InitOpts.ArtifactServer.PushToken, "artifact-push-token", v.GetString(common.VInitArtifactPushToken), lang.CmdInitFlagArtifactPushToken)
 
+	// Flags for using a private CA
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.CACertPath, "ca-cert", v.GetString(common.VInitCACert), lang.CmdInitFlagCACert)
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.CAKeyPath, "ca-key", v.GetString(common.VInitCAKey), lang.CmdInitFlagCAKey)
+	initCmd.Flags().StringSliceVar(&pkgConfig.InitOpts.TLSSubjectAltNames, "tls-san", v.GetStringSlice(common.VInitTLSSAN), lang.CmdInitFlagTLSSAN)
+
+	// Flags for encrypting the Zarf state
+	initCmd.Flags().StringVar(&pkgConfig.InitOpts.StateEncryptionKey, "state-encryption-key", v.GetString(common.VInitStateEncryptionKey), lang.CmdInitFlagStateEncryptionKey)
+
 	// Flags that control how a deployment proceeds
 	// Always require adopt-existing-resources flag (no viper)
 	initCmd.Flags().BoolVar(&pkgConfig.DeployOpts.AdoptExistingResources, "adopt-existing-resources", false, lang.CmdPackageDeployFlagAdoptExistingResources)
 	initCmd.Flags().BoolVar(&pkgConfig.DeployOpts.SkipWebhooks, "skip-webhooks", v.GetBool(common.VPkgDeploySkipWebhooks), lang.CmdPackageDeployFlagSkipWebhooks)
 	initCmd.Flags().DurationVar(&pkgConfig.DeployOpts.Timeout, "timeout", v.GetDuration(common.VPkgDeployTimeout), lang.CmdPackageDeployFlagTimeout)
 
+	initCmd.Flags().StringVar(&config.CommonOptions.KubeConfig, "kubeconfig", v.GetString(common.VKubeConfig), lang.CmdPackageDeployFlagKubeConfig)
+	initCmd.Flags().StringSliceVar(&kubeContexts, "kube-context", v.GetStringSlice(common.VKubeContexts), lang.CmdPackageDeployFlagKubeContext)
+
 	initCmd.Flags().IntVar(&pkgConfig.PkgOpts.Retries, "retries", v.GetInt(common.VPkgRetries), lang.CmdPackageFlagRetries)
 	initCmd.Flags().StringVarP(&pkgConfig.PkgOpts.PublicKeyPath, "key", "k", v.GetString(common.VPkgPublicKey), lang.CmdPackageFlagFlagPublicKey)
 	initCmd.Flags().BoolVar(&pkgConfig.PkgOpts.SkipSignatureValidation, "skip-signature-validation", false, lang.CmdPackageFlagSkipSignatureValidation)
@@ -9,9 +9,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/invopop/jsonschema"
@@ -22,6 +24,8 @@ import (
 	"github.com/zarf-dev/zarf/src/cmd/common"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/internal/agent"
+	"github.com/zarf-dev/zarf/src/internal/agent/audit"
+	agentHttp "github.com/zarf-dev/zarf/src/internal/agent/http"
 	"github.com/zarf-dev/zarf/src/internal/gitea"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -29,7 +33,15 @@ import (
 )
 
 var (
-	rollback bool
+	rollback           bool
+	auditLogFile       string
+	auditLogMaxSize    int
+	auditLogMaxBackups int
+	agentLimits        agentHttp.Limits
+	proxyLimits        agentHttp.Limits
+	proxyCacheDir      string
+	proxyCacheMaxSize  int
+	proxyCacheTTL      time.Duration
 )
 
 var internalCmd = &cobra.Command{
@@ -47,7 +59,17 @@ var agentCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		return agent.StartWebhook(cmd.Context(), cluster)
+		// Audit records are always written to stdout so they are collected with the agent logs
+		var auditLog io.Writer = os.Stdout
+		if auditLogFile != "" {
+			rf, err := audit.NewRollingFile(auditLogFile, int64(auditLogMaxSize)*1024*1024, auditLogMaxBackups)
+			if err != nil {
+				return err
+			}
+			defer rf.Close()
+			auditLog = io.MultiWriter(os.Stdout, rf)
+		}
+		return agent.StartWebhook(cmd.Context(), cluster, audit.NewLogger(auditLog), agentLimits)
 	},
 }
 
@@ -60,7 +82,14 @@ var httpProxyCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		return agent.StartHTTPProxy(cmd.Context(), cluster)
+		var cache *agentHttp.ProxyCache
+		if proxyCacheDir != "" {
+			cache, err = agentHttp.NewProxyCache(proxyCacheDir, int64(proxyCacheMaxSize)*1024*1024, proxyCacheTTL)
+			if err != nil {
+				return err
+			}
+		}
+		return agent.StartHTTPProxy(cmd.Context(), cluster, proxyLimits, cache)
 	},
 }
 
@@ -381,9 +410,28 @@ func init() {
 	internalCmd.AddCommand(isValidHostname)
 	internalCmd.AddCommand(computeCrc32)
 
+	agentCmd.Flags().StringVar(&auditLogFile, "audit-log-file", "", lang.CmdInternalAgentFlagAuditLogFile)
+	agentCmd.Flags().IntVar(&auditLogMaxSize, "audit-log-max-size", 10, lang.CmdInternalAgentFlagAuditLogMaxSize)
+	agentCmd.Flags().IntVar(&auditLogMaxBackups, "audit-log-max-backups", 3, lang.CmdInternalAgentFlagAuditLogMaxBackups)
+	// Admission requests hold the object and its previous version, which can each be a few megabytes. They are all sent
+	// by the API server, and a rejected request fails the admission, so they are not rate or concurrency limited by default.
+	addServerLimitFlags(agentCmd, &agentLimits, 16*1024*1024, 0, 0)
+	// The proxy forwards package uploads so their size is not limited by default
+	addServerLimitFlags(httpProxyCmd, &proxyLimits, 0, 100, 100)
+	httpProxyCmd.Flags().StringVar(&proxyCacheDir, "cache-dir", "", lang.CmdInternalProxyFlagCacheDir)
+	httpProxyCmd.Flags().IntVar(&proxyCacheMaxSize, "cache-max-size", 512, lang.CmdInternalProxyFlagCacheMaxSize)
+	httpProxyCmd.Flags().DurationVar(&proxyCacheTTL, "cache-ttl", 30*time.Second, lang.CmdInternalProxyFlagCacheTTL)
 	updateGiteaPVC.Flags().BoolVarP(&rollback, "rollback", "r", false, lang.CmdInternalFlagUpdateGiteaPVCRollback)
 }
 
+func addServerLimitFlags(cmd *cobra.Command, limits *agentHttp.Limits, maxBodyBytes int64, rateLimit float64, maxConcurrent int) {
+	cmd.Flags().Int64Var(&limits.MaxBodyBytes, "max-request-bytes", maxBodyBytes, lang.CmdInternalFlagMaxRequestBytes)
+	cmd.Flags().Float64Var(&limits.RateLimit, "rate-limit", rateLimit, lang.CmdInternalFlagRateLimit)
+	cmd.Flags().IntVar(&limits.RateBurst, "rate-burst", 200, lang.CmdInternalFlagRateBurst)
+	cmd.Flags().IntVar(&limits.MaxConcurrent, "max-concurrent-requests", maxConcurrent, lang.CmdInternalFlagMaxConcurrentRequests)
+	cmd.Flags().DurationVar(&limits.MaxConcurrentWait, "max-concurrent-wait", 5*time.Second, lang.CmdInternalFlagMaxConcurrentWait)
+}
+
 func addHiddenDummyFlag(cmd *cobra.Command, flagDummy string) {
 	if cmd.PersistentFlags().Lookup(flagDummy) == nil {
 		var dummyStr string
@@ -6,20 +6,27 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/cmd/common"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/internal/packager2"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/lint"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/packager/workspace"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
 
 	"oras.land/oras-go/v2/registry"
@@ -58,24 +65,96 @@ var packageCreateCmd = &cobra.Command{
 		pkgConfig.CreateOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgCreateSet), pkgConfig.CreateOpts.SetVariables, strings.ToUpper)
 
-		pkgClient, err := packager.New(&pkgConfig)
-		if err != nil {
-			return err
+		// Keep the architecture of the CLI single valued and hand a list of architectures to the creator instead
+		if strings.Contains(config.CLIArch, ",") {
+			pkgConfig.CreateOpts.Architectures = strings.Split(config.CLIArch, ",")
+			config.CLIArch = ""
 		}
-		defer pkgClient.ClearTempPaths()
 
-		err = pkgClient.Create(cmd.Context())
-		var lintErr *lint.LintError
-		if errors.As(err, &lintErr) {
-			common.PrintFindings(lintErr)
+		if workspace.IsWorkspace(pkgConfig.CreateOpts.BaseDir) {
+			return createWorkspace(cmd.Context(), pkgConfig)
 		}
-		if err != nil {
-			return fmt.Errorf("failed to create package: %w", err)
+		if pkgConfig.CreateOpts.AllFlavors {
+			return createAllFlavors(cmd.Context(), pkgConfig)
 		}
-		return nil
+
+		return createPackage(cmd.Context(), pkgConfig)
 	},
 }
 
+// createPackage creates the package in the base directory of the given config.
+func createPackage(ctx context.Context, cfg types.PackagerConfig) error {
+	pkgClient, err := packager.New(&cfg)
+	if err != nil {
+		return err
+	}
+	defer pkgClient.ClearTempPaths()
+
+	err = pkgClient.Create(ctx)
+	var lintErr *lint.LintError
+	if errors.As(err, &lintErr) {
+		common.PrintFindings(lintErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create package: %w", err)
+	}
+	return nil
+}
+
+// createWorkspace creates every package in the workspace in the base directory of the given config, in the order the
+// workspace lists them.
+func createWorkspace(ctx context.Context, cfg types.PackagerConfig) error {
+	ws, err := workspace.Read(cfg.CreateOpts.BaseDir)
+	if err != nil {
+		return err
+	}
+	setVariables := cfg.CreateOpts.SetVariables
+	for _, dir := range ws.PackageDirs() {
+		message.HeaderInfof("📦 WORKSPACE PACKAGE %s", dir)
+		cfg.CreateOpts.BaseDir = dir
+		cfg.CreateOpts.SetVariables = ws.MergeVariables(setVariables)
+		cfg.Pkg = v1alpha1.ZarfPackage{}
+		if err := createPackage(ctx, cfg); err != nil {
+			return fmt.Errorf("%s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// createAllFlavors creates one package for every flavor defined by the package in the base directory of the given
+// config. Local packages are written to a directory per flavor since the flavor is not part of their file name.
+func createAllFlavors(ctx context.Context, cfg types.PackagerConfig) error {
+	if cfg.CreateOpts.Flavor != "" {
+		return errors.New("--flavor cannot be used with --all-flavors")
+	}
+	var pkg v1alpha1.ZarfPackage
+	if err := utils.ReadYaml(filepath.Join(cfg.CreateOpts.BaseDir, layout.ZarfYAML), &pkg); err != nil {
+		return fmt.Errorf("unable to read the package definition: %w", err)
+	}
+	flavors := pkg.DefinedFlavors()
+	if len(flavors) == 0 {
+		return fmt.Errorf("%s does not define any flavors", filepath.Join(cfg.CreateOpts.BaseDir, layout.ZarfYAML))
+	}
+	output := cfg.CreateOpts.Output
+	for _, flavor := range flavors {
+		message.HeaderInfof("📦 FLAVOR %s", flavor)
+		cfg.CreateOpts.Flavor = flavor
+		if !helpers.IsOCIURL(output) {
+			cfg.CreateOpts.Output = filepath.Join(output, flavor)
+		}
+		if !helpers.IsOCIURL(output) && !cfg.CreateOpts.DryRun {
+			if err := helpers.CreateDirectory(cfg.CreateOpts.Output, helpers.ReadWriteExecuteUser); err != nil {
+				return err
+			}
+		}
+		cfg.Pkg = v1alpha1.ZarfPackage{}
+		if err := createPackage(ctx, cfg); err != nil {
+			return fmt.Errorf("flavor %s: %w", flavor, err)
+		}
+	}
+	return nil
+}
+
 var packageDeployCmd = &cobra.Command{
 	Use:     "deploy [ PACKAGE_SOURCE ]",
 	Aliases: []string{"d"},
@@ -98,22 +177,73 @@ var packageDeployCmd = &cobra.Command{
 		v := common.GetViper()
 		pkgConfig.PkgOpts.SetVariables = helpers.TransformAndMergeMap(
 			v.GetStringMapString(common.VPkgDeploySet), pkgConfig.PkgOpts.SetVariables, strings.ToUpper)
+		pkgConfig.DeployOpts.ComponentTimeouts = helpers.TransformAndMergeMap(
+			v.GetStringMapString(common.VPkgDeployComponentTimeouts), pkgConfig.DeployOpts.ComponentTimeouts, strings.ToLower)
 
-		pkgClient, err := packager.New(&pkgConfig)
-		if err != nil {
-			return err
+		deploy := func(ctx context.Context, kubeContext string) error {
+			cfg := deployConfig()
+			cfg.DeployOpts.KubeContext = kubeContext
+			pkgClient, err := packager.New(&cfg)
+			if err != nil {
+				return err
+			}
+			defer pkgClient.ClearTempPaths()
+			return pkgClient.Deploy(ctx)
 		}
-		defer pkgClient.ClearTempPaths()
-
-		ctx := cmd.Context()
-
-		if err := pkgClient.Deploy(ctx); err != nil {
+		if err := deployToKubeContexts(cmd.Context(), deploy); err != nil {
 			return fmt.Errorf("failed to deploy package: %w", err)
 		}
 		return nil
 	},
 }
 
+// deployConfig returns a copy of the packager config for one deployment, as deploying updates the config.
+func deployConfig() types.PackagerConfig {
+	cfg := pkgConfig
+	cfg.PkgOpts.SetVariables = maps.Clone(pkgConfig.PkgOpts.SetVariables)
+	return cfg
+}
+
+// deployToKubeContexts runs deploy against the cluster of each context given with --kube-context in turn, or against
+// the current context when none are given, and prints a summary of the deployments when there is more than one.
+func deployToKubeContexts(ctx context.Context, deploy func(ctx context.Context, kubeContext string) error) error {
+	if len(kubeContexts) == 0 {
+		return deploy(ctx, "")
+	}
+	if len(kubeContexts) == 1 {
+		return deploy(ctx, kubeContexts[0])
+	}
+	results, err := packager2.ForEachKubeContext(ctx, kubeContexts, func(ctx context.Context, kubeContext string) error {
+		message.HeaderInfof(lang.CmdPackageDeployKubeContextHeader, kubeContext)
+		return deploy(ctx, kubeContext)
+	})
+	header := []string{"Context", "Status", "Duration"}
+	data := [][]string{}
+	for _, result := range results {
+		status := "Succeeded"
+		if result.Err != nil {
+			status = "Failed"
+		}
+		data = append(data, []string{result.KubeContext, status, result.Duration.Round(time.Second).String()})
+	}
+	message.Table(header, data)
+	if err != nil {
+		return fmt.Errorf(lang.CmdPackageDeployKubeContextsFailed, len(kubeContexts)-countSucceeded(results), len(kubeContexts), err)
+	}
+	return nil
+}
+
+// countSucceeded returns the number of deployments that succeeded.
+func countSucceeded(results []packager2.KubeContextResult) int {
+	count := 0
+	for _, result := range results {
+		if result.Err == nil {
+			count++
+		}
+	}
+	return count
+}
+
 var packageMirrorCmd = &cobra.Command{
 	Use:     "mirror-resources [ PACKAGE_SOURCE ]",
 	Aliases: []string{"mr"},
@@ -180,11 +310,18 @@ var packageInspectCmd = &cobra.Command{
 	ValidArgsFunction: getPackageCompletionArgs,
 }
 
+var listOutputFormat string
+
 var packageListCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"l", "ls"},
 	Short:   lang.CmdPackageListShort,
+	Long:    lang.CmdPackageListLong,
 	RunE: func(cmd *cobra.Command, _ []string) error {
+		if listOutputFormat != "table" && listOutputFormat != "json" {
+			return fmt.Errorf(lang.CmdPackageListErrOutput, listOutputFormat)
+		}
+
 		timeoutCtx, cancel := context.WithTimeout(cmd.Context(), cluster.DefaultTimeout)
 		defer cancel()
 		c, err := cluster.NewClusterWithWait(timeoutCtx)
@@ -198,24 +335,21 @@ var packageListCmd = &cobra.Command{
 			return fmt.Errorf("unable to get the packages deployed to the cluster: %w", err)
 		}
 
-		// Populate a matrix of all the deployed packages
-		packageData := [][]string{}
-
-		for _, pkg := range deployedZarfPackages {
-			var components []string
+		inventories, inventoryErr := c.PackageInventories(ctx, deployedZarfPackages)
+		if inventoryErr != nil {
+			return inventoryErr
+		}
 
-			for _, component := range pkg.DeployedComponents {
-				components = append(components, component.Name)
+		if listOutputFormat == "json" {
+			b, err := json.Marshal(inventories)
+			if err != nil {
+				return fmt.Errorf("could not marshal json output: %w", err)
 			}
-
-			packageData = append(packageData, []string{
-				pkg.Name, pkg.Data.Metadata.Version, fmt.Sprintf("%v", components),
-			})
+			fmt.Println(string(b))
+		} else {
+			printPackageInventories(inventories)
 		}
 
-		header := []string{"Package", "Version", "Components"}
-		message.Table(header, packageData)
-
 		// Print out any unmarshalling errors
 		if err != nil {
 			return fmt.Errorf("unable to read all of the packages deployed to the cluster: %w", err)
@@ -224,8 +358,51 @@ var packageListCmd = &cobra.Command{
 	},
 }
 
+// printPackageInventories prints a table of the deployed packages and a table of the drift of the packages that drifted.
+func printPackageInventories(inventories []cluster.PackageInventory) {
+	// Populate a matrix of all the deployed packages
+	packageData := [][]string{}
+	driftData := [][]string{}
+	drifted := 0
+
+	for _, inventory := range inventories {
+		var components []string
+
+		for _, component := range inventory.Components {
+			components = append(components, component.Name)
+			for _, name := range component.MissingReleases {
+				driftData = append(driftData, []string{inventory.Name, component.Name, fmt.Sprintf("helm release %s is not deployed", name)})
+			}
+			for _, name := range component.MissingObjects {
+				driftData = append(driftData, []string{inventory.Name, component.Name, fmt.Sprintf("%s was removed", name)})
+			}
+			for _, image := range component.ImagesNotRunning {
+				driftData = append(driftData, []string{inventory.Name, component.Name, fmt.Sprintf("image %s is not running", image)})
+			}
+		}
+		if inventory.Status == cluster.InventoryStatusUnknown {
+			driftData = append(driftData, []string{inventory.Name, "", fmt.Sprintf("unable to check for drift: %s", inventory.Error)})
+		}
+		if inventory.Status != cluster.InventoryStatusSynced {
+			drifted++
+		}
+
+		packageData = append(packageData, []string{
+			inventory.Name, inventory.Version, fmt.Sprintf("%v", components), string(inventory.Status),
+		})
+	}
+
+	header := []string{"Package", "Version", "Components", "Status"}
+	message.Table(header, packageData)
+
+	if drifted > 0 {
+		message.Warnf(lang.CmdPackageListDrift, drifted)
+		message.Table([]string{"Package", "Component", "Drift"}, driftData)
+	}
+}
+
 var packageRemoveCmd = &cobra.Command{
-	Use:     "remove { PACKAGE_SOURCE | PACKAGE_NAME } --confirm",
+	Use:     "remove { PACKAGE_SOURCE | PACKAGE_NAME }",
 	Aliases: []string{"u", "rm"},
 	Args:    cobra.MaximumNArgs(1),
 	Short:   lang.CmdPackageRemoveShort,
@@ -406,12 +583,19 @@ func init() {
 	bindRemoveFlags(v)
 	bindPublishFlags(v)
 	bindPullFlags(v)
+
+	packageListCmd.Flags().StringVarP(&listOutputFormat, "output", "o", "table", lang.CmdPackageListFlagOutput)
 }
 
 func bindPackageFlags(v *viper.Viper) {
 	packageFlags := packageCmd.PersistentFlags()
 	packageFlags.IntVar(&config.CommonOptions.OCIConcurrency, "oci-concurrency", v.GetInt(common.VPkgOCIConcurrency), lang.CmdPackageFlagConcurrency)
 	packageFlags.StringVarP(&pkgConfig.PkgOpts.PublicKeyPath, "key", "k", v.GetString(common.VPkgPublicKey), lang.CmdPackageFlagFlagPublicKey)
+	packageFlags.StringVar(&pkgConfig.PkgOpts.DecryptionKeyPath, "decryption-key", v.GetString(common.VPkgDecryptionKey), lang.CmdPackageFlagDecryptionKey)
+	packageFlags.StringVar(&pkgConfig.PkgOpts.DecryptionPassphrase, "decryption-passphrase", v.GetString(common.VPkgDecryptionPassphrase), lang.CmdPackageFlagDecryptionPassphrase)
+	packageFlags.StringVar(&pkgConfig.PkgOpts.CertificateIdentity, "certificate-identity", v.GetString(common.VPkgCertificateIdentity), lang.CmdPackageFlagCertificateIdentity)
+	packageFlags.StringVar(&pkgConfig.PkgOpts.CertificateOIDCIssuer, "certificate-oidc-issuer", v.GetString(common.VPkgCertificateOIDCIssuer), lang.CmdPackageFlagCertificateOIDCIssuer)
+	packageFlags.StringVar(&pkgConfig.PkgOpts.TrustedRootPath, "trusted-root", v.GetString(common.VPkgTrustedRoot), lang.CmdPackageFlagTrustedRoot)
 }
 
 func bindCreateFlags(v *viper.Viper) {
@@ -430,15 +614,30 @@ func bindCreateFlags(v *viper.Viper) {
 
 	createFlags.StringVar(&pkgConfig.CreateOpts.DifferentialPackagePath, "differential", v.GetString(common.VPkgCreateDifferential), lang.CmdPackageCreateFlagDifferential)
 	createFlags.StringToStringVar(&pkgConfig.CreateOpts.SetVariables, "set", v.GetStringMapString(common.VPkgCreateSet), lang.CmdPackageCreateFlagSet)
+	createFlags.StringSliceVar(&pkgConfig.CreateOpts.EnvFiles, "env-file", v.GetStringSlice(common.VPkgCreateEnvFiles), lang.CmdPackageCreateFlagEnvFile)
 	createFlags.BoolVarP(&pkgConfig.CreateOpts.ViewSBOM, "sbom", "s", v.GetBool(common.VPkgCreateSbom), lang.CmdPackageCreateFlagSbom)
 	createFlags.StringVar(&pkgConfig.CreateOpts.SBOMOutputDir, "sbom-out", v.GetString(common.VPkgCreateSbomOutput), lang.CmdPackageCreateFlagSbomOut)
 	createFlags.BoolVar(&pkgConfig.CreateOpts.SkipSBOM, "skip-sbom", v.GetBool(common.VPkgCreateSkipSbom), lang.CmdPackageCreateFlagSkipSbom)
+	createFlags.StringSliceVar(&pkgConfig.CreateOpts.SBOMFormats, "sbom-format", v.GetStringSlice(common.VPkgCreateSbomFormats), lang.CmdPackageCreateFlagSbomFormat)
 	createFlags.IntVarP(&pkgConfig.CreateOpts.MaxPackageSizeMB, "max-package-size", "m", v.GetInt(common.VPkgCreateMaxPackageSize), lang.CmdPackageCreateFlagMaxPackageSize)
+	createFlags.StringVar(&pkgConfig.CreateOpts.MaxArtifactSize, "max-artifact-size", v.GetString(common.VPkgCreateMaxArtifactSize), lang.CmdPackageCreateFlagMaxArtifactSize)
 	createFlags.StringToStringVar(&pkgConfig.CreateOpts.RegistryOverrides, "registry-override", v.GetStringMapString(common.VPkgCreateRegistryOverride), lang.CmdPackageCreateFlagRegistryOverride)
 	createFlags.StringVarP(&pkgConfig.CreateOpts.Flavor, "flavor", "f", v.GetString(common.VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.AllFlavors, "all-flavors", v.GetBool(common.VPkgCreateAllFlavors), lang.CmdPackageCreateFlagAllFlavors)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.IncludeSignatures, "include-signatures", v.GetBool(common.VPkgCreateIncludeSignatures), lang.CmdPackageCreateFlagIncludeSignatures)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.Locked, "locked", v.GetBool(common.VPkgCreateLocked), lang.CmdPackageCreateFlagLocked)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.DryRun, "dry-run", v.GetBool(common.VPkgCreateDryRun), lang.CmdPackageCreateFlagDryRun)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.ChecksumDB, "checksum-db", v.GetBool(common.VPkgCreateChecksumDB), lang.CmdPackageCreateFlagChecksumDB)
+	createFlags.StringVar(&pkgConfig.CreateOpts.EncryptionKeyPath, "encryption-key", v.GetString(common.VPkgCreateEncryptionKey), lang.CmdPackageCreateFlagEncryptionKey)
+	createFlags.StringVar(&pkgConfig.CreateOpts.EncryptionPassphrase, "encryption-passphrase", v.GetString(common.VPkgCreateEncryptionPassphrase), lang.CmdPackageCreateFlagEncryptionPassphrase)
 
 	createFlags.StringVar(&pkgConfig.CreateOpts.SigningKeyPath, "signing-key", v.GetString(common.VPkgCreateSigningKey), lang.CmdPackageCreateFlagSigningKey)
 	createFlags.StringVar(&pkgConfig.CreateOpts.SigningKeyPassword, "signing-key-pass", v.GetString(common.VPkgCreateSigningKeyPassword), lang.CmdPackageCreateFlagSigningKeyPassword)
+	createFlags.BoolVar(&pkgConfig.CreateOpts.Keyless.Enabled, "keyless", v.GetBool(common.VPkgCreateKeyless), lang.CmdPackageFlagKeyless)
+	createFlags.StringVar(&pkgConfig.CreateOpts.Keyless.FulcioURL, "fulcio-url", v.GetString(common.VPkgCreateFulcioURL), lang.CmdPackageFlagFulcioURL)
+	createFlags.StringVar(&pkgConfig.CreateOpts.Keyless.RekorURL, "rekor-url", v.GetString(common.VPkgCreateRekorURL), lang.CmdPackageFlagRekorURL)
+	createFlags.StringVar(&pkgConfig.CreateOpts.Keyless.OIDCIssuer, "oidc-issuer", v.GetString(common.VPkgCreateOIDCIssuer), lang.CmdPackageFlagOIDCIssuer)
+	createFlags.StringVar(&pkgConfig.CreateOpts.Keyless.IdentityToken, "identity-token", v.GetString(common.VPkgCreateIdentityToken), lang.CmdPackageFlagIdentityToken)
 
 	createFlags.StringVarP(&pkgConfig.CreateOpts.SigningKeyPath, "key", "k", v.GetString(common.VPkgCreateSigningKey), lang.CmdPackageCreateFlagDeprecatedKey)
 	createFlags.StringVar(&pkgConfig.CreateOpts.SigningKeyPassword, "key-pass", v.GetString(common.VPkgCreateSigningKeyPassword), lang.CmdPackageCreateFlagDeprecatedKeyPassword)
@@ -460,14 +659,29 @@ func bindDeployFlags(v *viper.Viper) {
 	deployFlags.BoolVar(&pkgConfig.DeployOpts.AdoptExistingResources, "adopt-existing-resources", false, lang.CmdPackageDeployFlagAdoptExistingResources)
 	deployFlags.BoolVar(&pkgConfig.DeployOpts.SkipWebhooks, "skip-webhooks", v.GetBool(common.VPkgDeploySkipWebhooks), lang.CmdPackageDeployFlagSkipWebhooks)
 	deployFlags.DurationVar(&pkgConfig.DeployOpts.Timeout, "timeout", v.GetDuration(common.VPkgDeployTimeout), lang.CmdPackageDeployFlagTimeout)
+	deployFlags.StringToStringVar(&pkgConfig.DeployOpts.ComponentTimeouts, "component-timeout", v.GetStringMapString(common.VPkgDeployComponentTimeouts), lang.CmdPackageDeployFlagComponentTimeout)
 
 	deployFlags.IntVar(&pkgConfig.PkgOpts.Retries, "retries", v.GetInt(common.VPkgRetries), lang.CmdPackageFlagRetries)
 	deployFlags.StringToStringVar(&pkgConfig.PkgOpts.SetVariables, "set", v.GetStringMapString(common.VPkgDeploySet), lang.CmdPackageDeployFlagSet)
+	deployFlags.StringVar(&pkgConfig.DeployOpts.VariableOverlays, "variable-overlays", v.GetString(common.VPkgDeployVariableOverlays), lang.CmdPackageDeployFlagVariableOverlays)
+	deployFlags.BoolVar(&pkgConfig.DeployOpts.PublishStatus, "publish-status", v.GetBool(common.VPkgDeployPublishStatus), lang.CmdPackageDeployFlagPublishStatus)
+	deployFlags.IntVar(&pkgConfig.DeployOpts.Concurrency, "concurrency", v.GetInt(common.VPkgDeployConcurrency), lang.CmdPackageDeployFlagConcurrency)
+	deployFlags.BoolVar(&pkgConfig.DeployOpts.RollbackOnFailure, "rollback-on-failure", v.GetBool(common.VPkgDeployRollbackOnFail), lang.CmdPackageDeployFlagRollbackOnFailure)
+	deployFlags.BoolVar(&pkgConfig.DeployOpts.DryRun, "dry-run", v.GetBool(common.VPkgDeployDryRun), lang.CmdPackageDeployFlagDryRun)
+	deployFlags.StringSliceVar(&pkgConfig.DeployOpts.PolicyGates, "policy-gate", v.GetStringSlice(common.VPkgDeployPolicyGates), lang.CmdPackageDeployFlagPolicyGate)
 	deployFlags.StringVar(&pkgConfig.PkgOpts.OptionalComponents, "components", v.GetString(common.VPkgDeployComponents), lang.CmdPackageDeployFlagComponents)
 	deployFlags.StringVar(&pkgConfig.PkgOpts.Shasum, "shasum", v.GetString(common.VPkgDeployShasum), lang.CmdPackageDeployFlagShasum)
+	deployFlags.StringSliceVar(&pkgConfig.PkgOpts.SourceMirrors, "source-mirror", v.GetStringSlice(common.VPkgDeploySourceMirrors), lang.CmdPackageDeployFlagSourceMirror)
+	deployFlags.StringVar(&pkgConfig.PkgOpts.ArchiveChecksum, "archive-checksum", v.GetString(common.VPkgDeployArchiveChecksum), lang.CmdPackageDeployFlagArchiveChecksum)
+	deployFlags.StringVar(&pkgConfig.PkgOpts.ArchiveSignature, "archive-signature", v.GetString(common.VPkgDeployArchiveSignature), lang.CmdPackageDeployFlagArchiveSignature)
+	deployFlags.StringVar(&pkgConfig.PkgOpts.ArchiveKeyPath, "archive-key", v.GetString(common.VPkgDeployArchiveKey), lang.CmdPackageDeployFlagArchiveKey)
+	deployFlags.StringSliceVar(&pkgConfig.DeployOpts.Entitlements, "entitlement", v.GetStringSlice(common.VPkgDeployEntitlements), lang.CmdPackageDeployFlagEntitlement)
 	deployFlags.StringVar(&pkgConfig.PkgOpts.SGetKeyPath, "sget", v.GetString(common.VPkgDeploySget), lang.CmdPackageDeployFlagSget)
 	deployFlags.BoolVar(&pkgConfig.PkgOpts.SkipSignatureValidation, "skip-signature-validation", false, lang.CmdPackageFlagSkipSignatureValidation)
 
+	deployFlags.StringVar(&config.CommonOptions.KubeConfig, "kubeconfig", v.GetString(common.VKubeConfig), lang.CmdPackageDeployFlagKubeConfig)
+	deployFlags.StringSliceVar(&kubeContexts, "kube-context", v.GetStringSlice(common.VKubeContexts), lang.CmdPackageDeployFlagKubeContext)
+
 	deployFlags.MarkHidden("sget")
 }
 
@@ -487,6 +701,7 @@ func bindMirrorFlags(v *viper.Viper) {
 
 	mirrorFlags.IntVar(&pkgConfig.PkgOpts.Retries, "retries", v.GetInt(common.VPkgRetries), lang.CmdPackageFlagRetries)
 	mirrorFlags.StringVar(&pkgConfig.PkgOpts.OptionalComponents, "components", v.GetString(common.VPkgDeployComponents), lang.CmdPackageMirrorFlagComponents)
+	mirrorFlags.StringSliceVar(&pkgConfig.DeployOpts.Entitlements, "entitlement", v.GetStringSlice(common.VPkgDeployEntitlements), lang.CmdPackageMirrorFlagEntitlement)
 
 	// Flags for using an external Git server
 	mirrorFlags.StringVar(&pkgConfig.InitOpts.GitServer.Address, "git-url", v.GetString(common.VInitGitURL), lang.CmdInitFlagGitURL)
@@ -503,6 +718,7 @@ func bindInspectFlags(_ *viper.Viper) {
 	inspectFlags := packageInspectCmd.Flags()
 	inspectFlags.BoolVarP(&pkgConfig.InspectOpts.ViewSBOM, "sbom", "s", false, lang.CmdPackageInspectFlagSbom)
 	inspectFlags.StringVar(&pkgConfig.InspectOpts.SBOMOutputDir, "sbom-out", "", lang.CmdPackageInspectFlagSbomOut)
+	inspectFlags.StringVar(&pkgConfig.InspectOpts.DocsOutputDir, "docs-out", "", lang.CmdPackageInspectFlagDocsOut)
 	inspectFlags.BoolVar(&pkgConfig.InspectOpts.ListImages, "list-images", false, lang.CmdPackageInspectFlagListImages)
 	inspectFlags.BoolVar(&pkgConfig.PkgOpts.SkipSignatureValidation, "skip-signature-validation", false, lang.CmdPackageFlagSkipSignatureValidation)
 }
@@ -511,14 +727,19 @@ func bindRemoveFlags(v *viper.Viper) {
 	removeFlags := packageRemoveCmd.Flags()
 	removeFlags.BoolVar(&config.CommonOptions.Confirm, "confirm", false, lang.CmdPackageRemoveFlagConfirm)
 	removeFlags.StringVar(&pkgConfig.PkgOpts.OptionalComponents, "components", v.GetString(common.VPkgDeployComponents), lang.CmdPackageRemoveFlagComponents)
+	removeFlags.BoolVar(&pkgConfig.RemoveOpts.PruneArtifacts, "prune-artifacts", false, lang.CmdPackageRemoveFlagPruneArtifacts)
 	removeFlags.BoolVar(&pkgConfig.PkgOpts.SkipSignatureValidation, "skip-signature-validation", false, lang.CmdPackageFlagSkipSignatureValidation)
-	_ = packageRemoveCmd.MarkFlagRequired("confirm")
 }
 
 func bindPublishFlags(v *viper.Viper) {
 	publishFlags := packagePublishCmd.Flags()
 	publishFlags.StringVar(&pkgConfig.PublishOpts.SigningKeyPath, "signing-key", v.GetString(common.VPkgPublishSigningKey), lang.CmdPackagePublishFlagSigningKey)
 	publishFlags.StringVar(&pkgConfig.PublishOpts.SigningKeyPassword, "signing-key-pass", v.GetString(common.VPkgPublishSigningKeyPassword), lang.CmdPackagePublishFlagSigningKeyPassword)
+	publishFlags.BoolVar(&pkgConfig.PublishOpts.Keyless.Enabled, "keyless", v.GetBool(common.VPkgPublishKeyless), lang.CmdPackageFlagKeyless)
+	publishFlags.StringVar(&pkgConfig.PublishOpts.Keyless.FulcioURL, "fulcio-url", v.GetString(common.VPkgPublishFulcioURL), lang.CmdPackageFlagFulcioURL)
+	publishFlags.StringVar(&pkgConfig.PublishOpts.Keyless.RekorURL, "rekor-url", v.GetString(common.VPkgPublishRekorURL), lang.CmdPackageFlagRekorURL)
+	publishFlags.StringVar(&pkgConfig.PublishOpts.Keyless.OIDCIssuer, "oidc-issuer", v.GetString(common.VPkgPublishOIDCIssuer), lang.CmdPackageFlagOIDCIssuer)
+	publishFlags.StringVar(&pkgConfig.PublishOpts.Keyless.IdentityToken, "identity-token", v.GetString(common.VPkgPublishIdentityToken), lang.CmdPackageFlagIdentityToken)
 	publishFlags.BoolVar(&pkgConfig.PkgOpts.SkipSignatureValidation, "skip-signature-validation", false, lang.CmdPackageFlagSkipSignatureValidation)
 }
 
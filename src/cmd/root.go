@@ -20,12 +20,15 @@ import (
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
 var (
 	// Default global config for the packager
 	pkgConfig = types.PackagerConfig{}
+	// kubeContexts are the kubeconfig contexts of the clusters to deploy to
+	kubeContexts []string
 	// LogLevelCLI holds the log level as input from a command
 	LogLevelCLI string
 	// SkipLogFile is a flag to skip logging to a file
@@ -43,6 +46,11 @@ var rootCmd = &cobra.Command{
 			config.CommonOptions.PlainHTTP = true
 		}
 
+		// Only package create builds for more than one architecture, every other command uses a single architecture
+		if strings.Contains(config.CLIArch, ",") && cmd != packageCreateCmd {
+			return fmt.Errorf(lang.RootCmdErrArchList, config.CLIArch)
+		}
+
 		// Skip for vendor only commands
 		if common.CheckVendorOnlyFromPath(cmd) {
 			return nil
@@ -68,6 +76,12 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+
+		for scheme, command := range common.GetViper().GetStringMapString(common.VPkgSourceResolvers) {
+			if err := sources.RegisterResolver(scheme, command); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
 	Short:         lang.RootCmdShort,
@@ -125,9 +139,17 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&message.NoProgress, "no-progress", v.GetBool(common.VNoProgress), lang.RootCmdFlagNoProgress)
 	rootCmd.PersistentFlags().BoolVar(&NoColor, "no-color", v.GetBool(common.VNoColor), lang.RootCmdFlagNoColor)
 	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.CachePath, "zarf-cache", v.GetString(common.VZarfCache), lang.RootCmdFlagCachePath)
+	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.NoCache, "no-cache", v.GetBool(common.VNoCache), lang.RootCmdFlagNoCache)
 	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.TempDirectory, "tmpdir", v.GetString(common.VTmpDir), lang.RootCmdFlagTempDir)
 	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.Insecure, "insecure", v.GetBool(common.VInsecure), lang.RootCmdFlagInsecure)
 	rootCmd.PersistentFlags().MarkDeprecated("insecure", "please use --plain-http, --insecure-skip-tls-verify, or --skip-signature-validation instead.")
 	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.PlainHTTP, "plain-http", v.GetBool(common.VPlainHTTP), lang.RootCmdFlagPlainHTTP)
 	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.InsecureSkipTLSVerify, "insecure-skip-tls-verify", v.GetBool(common.VInsecureSkipTLSVerify), lang.RootCmdFlagInsecureSkipTLSVerify)
+	rootCmd.PersistentFlags().StringSliceVar(&config.CommonOptions.CACertPaths, "ca-cert", v.GetStringSlice(common.VCACerts), lang.RootCmdFlagCACert)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.ClientCertPath, "client-cert", v.GetString(common.VClientCert), lang.RootCmdFlagClientCert)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.ClientKeyPath, "client-key", v.GetString(common.VClientKey), lang.RootCmdFlagClientKey)
+	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.Proxy, "proxy", v.GetString(common.VProxy), lang.RootCmdFlagProxy)
+	rootCmd.PersistentFlags().StringToStringVar(&config.CommonOptions.RegistryMirrors, "registry-mirror", v.GetStringMapString(common.VRegistryMirrors), lang.RootCmdFlagRegistryMirror)
+	rootCmd.PersistentFlags().Float32Var(&config.CommonOptions.KubeQPS, "kube-qps", float32(v.GetFloat64(common.VKubeQPS)), lang.RootCmdFlagKubeQPS)
+	rootCmd.PersistentFlags().IntVar(&config.CommonOptions.KubeBurst, "kube-burst", v.GetInt(common.VKubeBurst), lang.RootCmdFlagKubeBurst)
 }
@@ -76,6 +76,20 @@ func init() {
 	// Always require confirm flag (no viper)
 	pruneCmd.Flags().BoolVar(&config.CommonOptions.Confirm, "confirm", false, lang.CmdToolsRegistryPruneFlagConfirm)
 
+	gcOpts := cluster.RegistryGarbageCollectOptions{}
+	gcCmd := &cobra.Command{
+		Use:     "gc",
+		Aliases: []string{"garbage-collect"},
+		Short:   lang.CmdToolsRegistryGCShort,
+		Long:    lang.CmdToolsRegistryGCLong,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return garbageCollectRegistry(cmd, gcOpts)
+		},
+	}
+
+	gcCmd.Flags().BoolVar(&gcOpts.DryRun, "dry-run", false, lang.CmdToolsRegistryGCFlagDryRun)
+	gcCmd.Flags().BoolVar(&gcOpts.DeleteUntagged, "delete-untagged", false, lang.CmdToolsRegistryGCFlagDeleteUntagged)
+
 	craneLogin := craneCmd.NewCmdAuthLogin()
 	craneLogin.Example = ""
 
@@ -91,6 +105,7 @@ func init() {
 	registryCmd.AddCommand(zarfCraneInternalWrapper(craneCmd.NewCmdDelete, &craneOptions, lang.CmdToolsRegistryDeleteExample, 0))
 	registryCmd.AddCommand(zarfCraneInternalWrapper(craneCmd.NewCmdDigest, &craneOptions, lang.CmdToolsRegistryDigestExample, 0))
 	registryCmd.AddCommand(pruneCmd)
+	registryCmd.AddCommand(gcCmd)
 	registryCmd.AddCommand(craneCmd.NewCmdVersion())
 
 	registryCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, lang.CmdToolsRegistryFlagVerbose)
@@ -261,7 +276,7 @@ func doPruneImagesForPackages(zarfState *types.ZarfState, zarfPackages []types.D
 
 		for _, component := range pkg.Data.Components {
 			if _, ok := deployedComponents[component.Name]; ok {
-				for _, image := range component.Images {
+				for _, image := range component.ImagesAndArtifacts() {
 					// We use the no checksum image since it will always exist and will share the same digest with other tags
 					transformedImageNoCheck, err := transform.ImageTransformHostWithoutChecksum(registryEndpoint, image)
 					if err != nil {
@@ -358,3 +373,40 @@ func doPruneImagesForPackages(zarfState *types.ZarfState, zarfPackages []types.D
 
 	return nil
 }
+
+func garbageCollectRegistry(cmd *cobra.Command, opts cluster.RegistryGarbageCollectOptions) error {
+	c, err := cluster.NewCluster()
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	zarfState, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	if !zarfState.RegistryInfo.IsInternal() {
+		return errors.New(lang.CmdToolsRegistryGCErrExternal)
+	}
+	if zarfState.RegistryInfo.IsZot() {
+		return errors.New(lang.CmdToolsRegistryGCErrZot)
+	}
+
+	message.Note(lang.CmdToolsRegistryGCRun)
+	if err := c.GarbageCollectRegistry(ctx, opts, os.Stderr); err != nil {
+		return err
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	spinner := message.NewProgressSpinner(lang.CmdToolsRegistryGCRestart)
+	defer spinner.Stop()
+	if err := c.RestartRegistry(ctx); err != nil {
+		return fmt.Errorf("unable to restart the registry: %w", err)
+	}
+	spinner.Success()
+
+	return nil
+}
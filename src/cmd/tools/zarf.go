@@ -5,15 +5,23 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"os"
 	"slices"
+	"strconv"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/object"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/defenseunicorns/pkg/oci"
@@ -24,9 +32,12 @@ import (
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
 	"github.com/zarf-dev/zarf/src/internal/packager/template"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
 	"github.com/zarf-dev/zarf/src/pkg/pki"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -34,6 +45,9 @@ import (
 var subAltNames []string
 var outputDirectory string
 var updateCredsInitOpts types.ZarfInitOptions
+var clearCacheMaxAge time.Duration
+var clearCacheMaxSize string
+var stateSnapshotOpts cluster.SnapshotEncryptionOptions
 
 var deprecatedGetGitCredsCmd = &cobra.Command{
 	Use:    "get-git-password",
@@ -137,65 +151,99 @@ var updateCredsCmd = &cobra.Command{
 			}
 		}
 
-		if confirm {
-			// Update registry and git pull secrets
-			if slices.Contains(args, message.RegistryKey) {
-				err := c.UpdateZarfManagedImageSecrets(ctx, newState)
-				if err != nil {
-					return err
-				}
-			}
-			if slices.Contains(args, message.GitKey) {
-				err := c.UpdateZarfManagedGitSecrets(ctx, newState)
-				if err != nil {
-					return err
-				}
-			}
-			// TODO once Zarf is changed so the default state is empty for a service when it is not deployed
-			// and sufficient time has passed for users state to get updated we can remove this check
-			internalGitServerExists, err := c.InternalGitServerExists(cmd.Context())
-			if err != nil {
-				return err
-			}
+		if !confirm {
+			return nil
+		}
+		return updateCredentials(ctx, c, oldState, newState, args)
+	},
+}
 
-			// Update artifact token (if internal)
-			if slices.Contains(args, message.ArtifactKey) && newState.ArtifactServer.PushToken == "" && newState.ArtifactServer.IsInternal() && internalGitServerExists {
-				newState.ArtifactServer.PushToken, err = c.UpdateInternalArtifactServerToken(ctx, oldState.GitServer)
-				if err != nil {
-					return fmt.Errorf("unable to create the new Gitea artifact token: %w", err)
-				}
-			}
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: lang.CmdToolsStateShort,
+}
 
-			// Save the final Zarf State
-			err = c.SaveZarfState(ctx, newState)
-			if err != nil {
-				return fmt.Errorf("failed to save the Zarf State to the cluster: %w", err)
-			}
+var stateExportCmd = &cobra.Command{
+	Use:     "export FILE",
+	Short:   lang.CmdToolsStateExportShort,
+	Long:    lang.CmdToolsStateExportLong,
+	Example: lang.CmdToolsStateExportExample,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		c, err := cluster.NewClusterWithWait(timeoutCtx)
+		if err != nil {
+			return err
+		}
+
+		snapshot, err := c.ExportStateSnapshot(ctx)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_TRUNC, helpers.ReadWriteUser)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := cluster.WriteStateSnapshot(ctx, f, snapshot, stateSnapshotOpts); err != nil {
+			return err
+		}
+		if stateSnapshotOpts.KeyURI == "" && stateSnapshotOpts.Password == "" {
+			message.Warn(lang.CmdToolsStateExportUnencrypted)
+		}
+		message.Successf(lang.CmdToolsStateExportSuccess, len(snapshot.DeployedPackages), args[0])
+		return nil
+	},
+}
 
-			// Update Zarf 'init' component Helm releases if present
-			h := helm.NewClusterOnly(&types.PackagerConfig{}, template.GetZarfVariableConfig(), newState, c)
+var stateImportCmd = &cobra.Command{
+	Use:     "import FILE",
+	Short:   lang.CmdToolsStateImportShort,
+	Long:    lang.CmdToolsStateImportLong,
+	Example: lang.CmdToolsStateImportExample,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
 
-			if slices.Contains(args, message.RegistryKey) && newState.RegistryInfo.IsInternal() {
-				err = h.UpdateZarfRegistryValues(ctx)
-				if err != nil {
-					// Warn if we couldn't actually update the registry (it might not be installed and we should try to continue)
-					message.Warnf(lang.CmdToolsUpdateCredsUnableUpdateRegistry, err.Error())
-				}
-			}
-			if slices.Contains(args, message.GitKey) && newState.GitServer.IsInternal() && internalGitServerExists {
-				err := c.UpdateInternalGitServerSecret(cmd.Context(), oldState.GitServer, newState.GitServer)
-				if err != nil {
-					return fmt.Errorf("unable to update Zarf Git Server values: %w", err)
-				}
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		snapshot, err := cluster.ReadStateSnapshot(ctx, f, stateSnapshotOpts.Password)
+		if err != nil {
+			return err
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		c, err := cluster.NewClusterWithWait(timeoutCtx)
+		if err != nil {
+			return err
+		}
+
+		if currentState, err := c.LoadZarfState(ctx); err == nil {
+			plan := interactive.Plan{
+				Operation: "Import",
+				Source:    args[0],
+				State: &interactive.PlanState{
+					Current:  cluster.SanitizeZarfState(currentState),
+					Incoming: cluster.SanitizeZarfState(snapshot.State),
+				},
 			}
-			if slices.Contains(args, message.AgentKey) {
-				err = h.UpdateZarfAgentValues(ctx)
-				if err != nil {
-					// Warn if we couldn't actually update the agent (it might not be installed and we should try to continue)
-					message.Warnf(lang.CmdToolsUpdateCredsUnableUpdateAgent, err.Error())
-				}
+			if err := interactive.NewConfirmer(config.CommonOptions.Confirm).Confirm(plan, lang.CmdToolsStateImportConfirmOverwrite); err != nil {
+				return fmt.Errorf("import cancelled: %w", err)
 			}
 		}
+
+		if err := c.ImportStateSnapshot(ctx, snapshot); err != nil {
+			return err
+		}
+		message.Successf(lang.CmdToolsStateImportSuccess, len(snapshot.DeployedPackages), snapshot.Created.Format(time.RFC3339))
+		message.Note(lang.CmdToolsStateImportNextSteps)
 		return nil
 	},
 }
@@ -204,16 +252,72 @@ var clearCacheCmd = &cobra.Command{
 	Use:     "clear-cache",
 	Aliases: []string{"c"},
 	Short:   lang.CmdToolsClearCacheShort,
+	Long:    lang.CmdToolsClearCacheLong,
+	Example: lang.CmdToolsClearCacheExample,
 	RunE: func(_ *cobra.Command, _ []string) error {
-		message.Notef(lang.CmdToolsClearCacheDir, config.GetAbsCachePath())
-		if err := os.RemoveAll(config.GetAbsCachePath()); err != nil {
-			return fmt.Errorf("unable to clear the cache directory %s: %w", config.GetAbsCachePath(), err)
+		cachePath := config.GetAbsCachePath()
+		message.Notef(lang.CmdToolsClearCacheDir, cachePath)
+
+		entries, err := utils.ListCacheEntries(cachePath, layout.ImagesDir, layout.ReposDir)
+		if err != nil {
+			return fmt.Errorf("unable to read the cache directory %s: %w", cachePath, err)
+		}
+		printCacheSize(entries)
+
+		if clearCacheMaxAge <= 0 && clearCacheMaxSize == "" {
+			if err := os.RemoveAll(cachePath); err != nil {
+				return fmt.Errorf("unable to clear the cache directory %s: %w", cachePath, err)
+			}
+			message.Successf(lang.CmdToolsClearCacheSuccess, cachePath)
+			return nil
+		}
+
+		olderThan := time.Time{}
+		if clearCacheMaxAge > 0 {
+			olderThan = time.Now().Add(-clearCacheMaxAge)
+		}
+		maxSize := int64(-1)
+		if clearCacheMaxSize != "" {
+			q, err := resource.ParseQuantity(clearCacheMaxSize)
+			if err != nil {
+				return fmt.Errorf("invalid max size %q: %w", clearCacheMaxSize, err)
+			}
+			maxSize = q.Value()
+		}
+		removed, err := utils.PruneCacheEntries(entries, olderThan, maxSize)
+		if err != nil {
+			return fmt.Errorf("unable to prune the cache directory %s: %w", cachePath, err)
+		}
+		removedSize := int64(0)
+		for _, entry := range removed {
+			removedSize += entry.Size
 		}
-		message.Successf(lang.CmdToolsClearCacheSuccess, config.GetAbsCachePath())
+		message.Successf(lang.CmdToolsClearCachePruned, len(removed), utils.ByteFormat(float64(removedSize), 2), cachePath)
 		return nil
 	},
 }
 
+// printCacheSize prints how many entries and bytes each group of the cache holds.
+func printCacheSize(entries []utils.CacheEntry) {
+	groups := []string{layout.ImagesDir, layout.ReposDir, utils.CacheGroupOther}
+	counts := map[string]int{}
+	sizes := map[string]int64{}
+	total := int64(0)
+	for _, entry := range entries {
+		counts[entry.Group]++
+		sizes[entry.Group] += entry.Size
+		total += entry.Size
+	}
+
+	header := []string{"Cache", "Entries", "Size"}
+	data := [][]string{}
+	for _, group := range groups {
+		data = append(data, []string{group, strconv.Itoa(counts[group]), utils.ByteFormat(float64(sizes[group]), 2)})
+	}
+	data = append(data, []string{"total", strconv.Itoa(len(entries)), utils.ByteFormat(float64(total), 2)})
+	message.Table(header, data)
+}
+
 var downloadInitCmd = &cobra.Command{
 	Use:   "download-init",
 	Short: lang.CmdToolsDownloadInitShort,
@@ -328,6 +432,257 @@ var generateKeyCmd = &cobra.Command{
 	},
 }
 
+var rotateCredsCmd = &cobra.Command{
+	Use:     "rotate-credentials",
+	Short:   lang.CmdToolsRotateCredsShort,
+	Long:    lang.CmdToolsRotateCredsLong,
+	Example: lang.CmdToolsRotateCredsExample,
+	Aliases: []string{"rc"},
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		validKeys := []string{message.RegistryKey, message.GitKey, message.ArtifactKey}
+		if len(args) == 0 {
+			args = validKeys
+		} else if !slices.Contains(validKeys, args[0]) {
+			cmd.Help()
+			return fmt.Errorf("invalid service key specified, valid key choices are: %v", validKeys)
+		}
+
+		ctx := cmd.Context()
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		c, err := cluster.NewClusterWithWait(timeoutCtx)
+		if err != nil {
+			return err
+		}
+
+		oldState, err := c.LoadZarfState(ctx)
+		if err != nil {
+			return err
+		}
+		internalGitServerExists, err := c.InternalGitServerExists(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Only the credentials of the services that Zarf runs in the cluster can be generated
+		services := []string{}
+		for _, service := range args {
+			internal := false
+			switch service {
+			case message.RegistryKey:
+				internal = oldState.RegistryInfo.IsInternal()
+			case message.GitKey:
+				internal = oldState.GitServer.IsInternal() && internalGitServerExists
+			case message.ArtifactKey:
+				internal = oldState.ArtifactServer.IsInternal() && internalGitServerExists
+			}
+			if !internal {
+				message.Warnf(lang.CmdToolsRotateCredsSkipped, serviceNames[service], service)
+				continue
+			}
+			services = append(services, service)
+		}
+		if len(services) == 0 {
+			return errors.New(lang.CmdToolsRotateCredsNoServices)
+		}
+
+		newState, err := cluster.MergeZarfState(oldState, types.ZarfInitOptions{}, services)
+		if err != nil {
+			return fmt.Errorf("unable to update Zarf credentials: %w", err)
+		}
+
+		deployments, err := credentialConsumers(ctx, c, services)
+		if err != nil {
+			return err
+		}
+
+		confirmer := interactive.NewConfirmer(config.CommonOptions.Confirm)
+		if confirmer.Prompts() {
+			message.PrintCredentialUpdates(oldState, newState, services)
+		}
+		if config.CommonOptions.Confirm {
+			message.Note(lang.CmdToolsUpdateCredsConfirmProvided)
+		}
+		if err := confirmer.Confirm(rotationPlan(oldState, newState, services, deployments), lang.CmdToolsUpdateCredsConfirmContinue); err != nil {
+			return fmt.Errorf("rotation cancelled: %w", err)
+		}
+
+		if err := updateCredentials(ctx, c, oldState, newState, services); err != nil {
+			return err
+		}
+		return restartCredentialConsumers(ctx, c, deployments)
+	},
+}
+
+// serviceNames are the names of the services by their keys.
+var serviceNames = map[string]string{
+	message.RegistryKey: "registry",
+	message.GitKey:      "git server",
+	message.ArtifactKey: "artifact server",
+}
+
+// rotationPlan describes the rotation of the credentials of the given services and the restart of the given deployments
+// for confirmation.
+func rotationPlan(oldState, newState *types.ZarfState, services, deployments []string) interactive.Plan {
+	plan := interactive.Plan{
+		Operation: "Rotate",
+	}
+	for _, service := range services {
+		fields := map[string][2]string{}
+		switch service {
+		case message.RegistryKey:
+			o, n := oldState.RegistryInfo, newState.RegistryInfo
+			fields["pushUsername"] = [2]string{o.PushUsername, n.PushUsername}
+			fields["pushPassword"] = [2]string{o.PushPassword, n.PushPassword}
+			fields["pullUsername"] = [2]string{o.PullUsername, n.PullUsername}
+			fields["pullPassword"] = [2]string{o.PullPassword, n.PullPassword}
+		case message.GitKey:
+			o, n := oldState.GitServer, newState.GitServer
+			fields["pushUsername"] = [2]string{o.PushUsername, n.PushUsername}
+			fields["pushPassword"] = [2]string{o.PushPassword, n.PushPassword}
+			fields["pullUsername"] = [2]string{o.PullUsername, n.PullUsername}
+			fields["pullPassword"] = [2]string{o.PullPassword, n.PullPassword}
+		case message.ArtifactKey:
+			o, n := oldState.ArtifactServer, newState.ArtifactServer
+			fields["pushUsername"] = [2]string{o.PushUsername, n.PushUsername}
+			fields["pushToken"] = [2]string{o.PushToken, n.PushToken}
+		}
+		changed := []string{}
+		for field, values := range fields {
+			if values[0] != values[1] {
+				changed = append(changed, field)
+			}
+		}
+		slices.Sort(changed)
+		plan.Credentials = append(plan.Credentials, interactive.PlanCredential{Service: service, Changed: changed})
+	}
+	for _, name := range deployments {
+		plan.Restarts = append(plan.Restarts, interactive.PlanResource{Name: name, Namespace: cluster.ZarfNamespaceName})
+	}
+	return plan
+}
+
+// credentialConsumers returns the deployments that are restarted when the credentials of the given services are
+// rotated: the agent, so that it does not serve cached credentials, and the registry, which is rolled by the update of
+// its values.
+func credentialConsumers(ctx context.Context, c *cluster.Cluster, services []string) ([]string, error) {
+	deployments := []string{}
+	_, err := c.Clientset.AppsV1().Deployments(cluster.ZarfNamespaceName).Get(ctx, cluster.ZarfAgentName, metav1.GetOptions{})
+	if err == nil {
+		deployments = append(deployments, cluster.ZarfAgentName)
+	} else if !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+	if slices.Contains(services, message.RegistryKey) {
+		deployments = append(deployments, cluster.ZarfRegistryName)
+	}
+	return deployments, nil
+}
+
+// restartCredentialConsumers restarts the agent, if it is one of the given deployments, and waits for the deployments
+// to be ready with the new credentials.
+func restartCredentialConsumers(ctx context.Context, c *cluster.Cluster, deployments []string) error {
+	spinner := message.NewProgressSpinner(lang.CmdToolsRotateCredsRestart)
+	defer spinner.Stop()
+
+	if slices.Contains(deployments, cluster.ZarfAgentName) {
+		if err := c.RestartDeployment(ctx, cluster.ZarfNamespaceName, cluster.ZarfAgentName); err != nil {
+			return err
+		}
+	}
+
+	objs := []object.ObjMetadata{}
+	for _, name := range deployments {
+		objs = append(objs, object.ObjMetadata{
+			GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			Namespace: cluster.ZarfNamespaceName,
+			Name:      name,
+		})
+	}
+	waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer waitCancel()
+	if err := cluster.WaitForObjectsReady(waitCtx, c.Watcher, objs); err != nil {
+		return err
+	}
+
+	spinner.Success()
+	return nil
+}
+
+// updateCredentials updates the secrets, services and state of the cluster from the credentials of the old state to
+// those of the new state for the given services.
+func updateCredentials(ctx context.Context, c *cluster.Cluster, oldState, newState *types.ZarfState, services []string) error {
+	// Certificates issued by a previous private CA are no longer trusted once it is replaced
+	caBundleChanged := !bytes.Equal(oldState.CABundle, newState.CABundle)
+
+	// Update registry and git pull secrets
+	if slices.Contains(services, message.RegistryKey) {
+		err := c.UpdateZarfManagedImageSecrets(ctx, newState)
+		if err != nil {
+			return err
+		}
+	}
+	if slices.Contains(services, message.GitKey) {
+		err := c.UpdateZarfManagedGitSecrets(ctx, newState)
+		if err != nil {
+			return err
+		}
+	}
+	if caBundleChanged {
+		err := c.UpdateZarfManagedCABundles(ctx, newState)
+		if err != nil {
+			return err
+		}
+	}
+	// TODO once Zarf is changed so the default state is empty for a service when it is not deployed
+	// and sufficient time has passed for users state to get updated we can remove this check
+	internalGitServerExists, err := c.InternalGitServerExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Update artifact token (if internal)
+	if slices.Contains(services, message.ArtifactKey) && newState.ArtifactServer.PushToken == "" && newState.ArtifactServer.IsInternal() && internalGitServerExists {
+		newState.ArtifactServer.PushToken, err = c.UpdateInternalArtifactServerToken(ctx, oldState.GitServer)
+		if err != nil {
+			return fmt.Errorf("unable to create the new Gitea artifact token: %w", err)
+		}
+	}
+
+	// Save the final Zarf State
+	err = c.SaveZarfState(ctx, newState)
+	if err != nil {
+		return fmt.Errorf("failed to save the Zarf State to the cluster: %w", err)
+	}
+
+	// Update Zarf 'init' component Helm releases if present
+	h := helm.NewClusterOnly(&types.PackagerConfig{}, template.GetZarfVariableConfig(), newState, c)
+
+	if slices.Contains(services, message.RegistryKey) && newState.RegistryInfo.IsInternal() {
+		err = h.UpdateZarfRegistryValues(ctx)
+		if err != nil {
+			// Warn if we couldn't actually update the registry (it might not be installed and we should try to continue)
+			message.Warnf(lang.CmdToolsUpdateCredsUnableUpdateRegistry, err.Error())
+		}
+	}
+	if slices.Contains(services, message.GitKey) && newState.GitServer.IsInternal() && internalGitServerExists {
+		err := c.UpdateInternalGitServerSecret(ctx, oldState.GitServer, newState.GitServer)
+		if err != nil {
+			return fmt.Errorf("unable to update Zarf Git Server values: %w", err)
+		}
+	}
+	if slices.Contains(services, message.AgentKey) || caBundleChanged {
+		err = h.UpdateZarfAgentValues(ctx)
+		if err != nil {
+			// Warn if we couldn't actually update the agent (it might not be installed and we should try to continue)
+			message.Warnf(lang.CmdToolsUpdateCredsUnableUpdateAgent, err.Error())
+		}
+	}
+	return nil
+}
+
 func init() {
 	v := common.InitViper()
 
@@ -358,10 +713,30 @@ func init() {
 	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.ArtifactServer.PushUsername, "artifact-push-username", v.GetString(common.VInitArtifactPushUser), lang.CmdInitFlagArtifactPushUser)
 	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.ArtifactServer.PushToken, "artifact-push-token", v.GetString(common.VInitArtifactPushToken), lang.CmdInitFlagArtifactPushToken)
 
+	// Flags for using a private CA
+	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.CACertPath, "ca-cert", v.GetString(common.VInitCACert), lang.CmdInitFlagCACert)
+	updateCredsCmd.Flags().StringVar(&updateCredsInitOpts.CAKeyPath, "ca-key", v.GetString(common.VInitCAKey), lang.CmdInitFlagCAKey)
+	updateCredsCmd.Flags().StringSliceVar(&updateCredsInitOpts.TLSSubjectAltNames, "tls-san", v.GetStringSlice(common.VInitTLSSAN), lang.CmdInitFlagTLSSAN)
+
 	updateCredsCmd.Flags().SortFlags = true
 
+	toolsCmd.AddCommand(rotateCredsCmd)
+	// Always require confirm flag (no viper)
+	rotateCredsCmd.Flags().BoolVar(&config.CommonOptions.Confirm, "confirm", false, lang.CmdToolsRotateCredsConfirmFlag)
+
+	toolsCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateExportCmd.Flags().StringVar(&stateSnapshotOpts.KeyURI, "encryption-key", "", lang.CmdToolsStateExportFlagEncryptionKey)
+	stateExportCmd.Flags().StringVar(&stateSnapshotOpts.Password, "password", "", lang.CmdToolsStateExportFlagPassword)
+	stateCmd.AddCommand(stateImportCmd)
+	stateImportCmd.Flags().StringVar(&stateSnapshotOpts.Password, "password", "", lang.CmdToolsStateImportFlagPassword)
+	// Always require confirm flag (no viper)
+	stateImportCmd.Flags().BoolVar(&config.CommonOptions.Confirm, "confirm", false, lang.CmdToolsStateImportFlagConfirm)
+
 	toolsCmd.AddCommand(clearCacheCmd)
 	clearCacheCmd.Flags().StringVar(&config.CommonOptions.CachePath, "zarf-cache", config.ZarfDefaultCachePath, lang.CmdToolsClearCacheFlagCachePath)
+	clearCacheCmd.Flags().DurationVar(&clearCacheMaxAge, "max-age", 0, lang.CmdToolsClearCacheFlagMaxAge)
+	clearCacheCmd.Flags().StringVar(&clearCacheMaxSize, "max-size", "", lang.CmdToolsClearCacheFlagMaxSize)
 
 	toolsCmd.AddCommand(downloadInitCmd)
 	downloadInitCmd.Flags().StringVarP(&outputDirectory, "output-directory", "o", "", lang.CmdToolsDownloadInitFlagOutputDirectory)
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+
+	pkgkubernetes "github.com/defenseunicorns/pkg/kubernetes"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func credentialsState() *types.ZarfState {
+	return &types.ZarfState{
+		RegistryInfo: types.RegistryInfo{
+			Address:      "registry.example.com",
+			PushUsername: "zarf-push",
+			PushPassword: "registry-push",
+			PullUsername: "zarf-pull",
+			PullPassword: "registry-pull",
+		},
+		GitServer: types.GitServerInfo{
+			Address:      "https://git.example.com",
+			PushUsername: "zarf-git-user",
+			PushPassword: "git-push",
+			PullUsername: "zarf-git-read-user",
+			PullPassword: "git-pull",
+		},
+	}
+}
+
+func TestUpdateCredentials(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		services        []string
+		registryRotated bool
+		gitRotated      bool
+	}{
+		{
+			name:            "registry",
+			services:        []string{message.RegistryKey},
+			registryRotated: true,
+		},
+		{
+			name:       "git server",
+			services:   []string{message.GitKey},
+			gitRotated: true,
+		},
+		{
+			name:            "all",
+			services:        []string{message.RegistryKey, message.GitKey},
+			registryRotated: true,
+			gitRotated:      true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+			oldState := credentialsState()
+			for _, name := range []string{cluster.ZarfNamespaceName, "podinfo"} {
+				_, err := c.Clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			oldRegistrySecret, err := c.GenerateRegistryPullCreds(ctx, "podinfo", config.ZarfImagePullSecretName, oldState.RegistryInfo)
+			require.NoError(t, err)
+			_, err = c.Clientset.CoreV1().Secrets("podinfo").Create(ctx, oldRegistrySecret, metav1.CreateOptions{})
+			require.NoError(t, err)
+			oldGitSecret := c.GenerateGitPullCreds("podinfo", config.ZarfGitServerSecretName, oldState.GitServer)
+			_, err = c.Clientset.CoreV1().Secrets("podinfo").Create(ctx, oldGitSecret, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			newState := credentialsState()
+			newState.RegistryInfo.PushPassword = "new-registry-push"
+			newState.RegistryInfo.PullPassword = "new-registry-pull"
+			newState.GitServer.PushPassword = "new-git-push"
+			newState.GitServer.PullPassword = "new-git-pull"
+			// Only the credentials of the rotated services are generated anew.
+			if !tt.registryRotated {
+				newState.RegistryInfo = oldState.RegistryInfo
+			}
+			if !tt.gitRotated {
+				newState.GitServer = oldState.GitServer
+			}
+
+			err = updateCredentials(ctx, c, oldState, newState, tt.services)
+			require.NoError(t, err)
+
+			registrySecret, err := c.Clientset.CoreV1().Secrets("podinfo").Get(ctx, config.ZarfImagePullSecretName, metav1.GetOptions{})
+			require.NoError(t, err)
+			gitSecret, err := c.Clientset.CoreV1().Secrets("podinfo").Get(ctx, config.ZarfGitServerSecretName, metav1.GetOptions{})
+			require.NoError(t, err)
+			savedState, err := c.LoadZarfState(ctx)
+			require.NoError(t, err)
+
+			if tt.registryRotated {
+				require.NotEqual(t, oldRegistrySecret.Data, registrySecret.Data)
+				expectedSecret, err := c.GenerateRegistryPullCreds(ctx, "podinfo", config.ZarfImagePullSecretName, newState.RegistryInfo)
+				require.NoError(t, err)
+				require.Equal(t, expectedSecret.Data, registrySecret.Data)
+			} else {
+				require.Equal(t, oldRegistrySecret.Data, registrySecret.Data)
+			}
+			require.Equal(t, newState.RegistryInfo, savedState.RegistryInfo)
+
+			if tt.gitRotated {
+				require.Equal(t, map[string]string{"username": "zarf-git-read-user", "password": "new-git-pull"}, gitSecret.StringData)
+			} else {
+				require.Equal(t, oldGitSecret.StringData, gitSecret.StringData)
+			}
+			require.Equal(t, newState.GitServer, savedState.GitServer)
+		})
+	}
+}
+
+func TestCredentialConsumers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		agentDeployed       bool
+		services            []string
+		expectedDeployments []string
+	}{
+		{
+			name:                "registry with the agent",
+			agentDeployed:       true,
+			services:            []string{message.RegistryKey, message.GitKey},
+			expectedDeployments: []string{cluster.ZarfAgentName, cluster.ZarfRegistryName},
+		},
+		{
+			name:                "registry without the agent",
+			services:            []string{message.RegistryKey},
+			expectedDeployments: []string{cluster.ZarfRegistryName},
+		},
+		{
+			name:                "git server with the agent",
+			agentDeployed:       true,
+			services:            []string{message.GitKey},
+			expectedDeployments: []string{cluster.ZarfAgentName},
+		},
+		{
+			name:                "git server without the agent",
+			services:            []string{message.GitKey},
+			expectedDeployments: []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+			if tt.agentDeployed {
+				agent := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: cluster.ZarfAgentName, Namespace: cluster.ZarfNamespaceName}}
+				_, err := c.Clientset.AppsV1().Deployments(cluster.ZarfNamespaceName).Create(ctx, agent, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			deployments, err := credentialConsumers(ctx, c, tt.services)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedDeployments, deployments)
+		})
+	}
+}
+
+func TestRestartCredentialConsumers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := &cluster.Cluster{
+		Clientset: fake.NewSimpleClientset(),
+		Watcher:   pkgkubernetes.NewImmediateWatcher(status.CurrentStatus),
+	}
+	for _, name := range []string{cluster.ZarfAgentName, cluster.ZarfRegistryName} {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.ZarfNamespaceName}}
+		_, err := c.Clientset.AppsV1().Deployments(cluster.ZarfNamespaceName).Create(ctx, deployment, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	err := restartCredentialConsumers(ctx, c, []string{cluster.ZarfAgentName, cluster.ZarfRegistryName})
+	require.NoError(t, err)
+
+	// The agent is restarted, while the registry is rolled by the update of its values.
+	agent, err := c.Clientset.AppsV1().Deployments(cluster.ZarfNamespaceName).Get(ctx, cluster.ZarfAgentName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Contains(t, agent.Spec.Template.Annotations, "zarf.dev/restartedAt")
+	registry, err := c.Clientset.AppsV1().Deployments(cluster.ZarfNamespaceName).Get(ctx, cluster.ZarfRegistryName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotContains(t, registry.Spec.Template.Annotations, "zarf.dev/restartedAt")
+}
+
+func TestRotationPlan(t *testing.T) {
+	t.Parallel()
+
+	oldState := credentialsState()
+	newState := credentialsState()
+	newState.RegistryInfo.PushPassword = "new-registry-push"
+	newState.RegistryInfo.PullPassword = "new-registry-pull"
+
+	plan := rotationPlan(oldState, newState, []string{message.RegistryKey, message.GitKey}, []string{cluster.ZarfAgentName, cluster.ZarfRegistryName})
+	expected := interactive.Plan{
+		Operation: "Rotate",
+		Credentials: []interactive.PlanCredential{
+			{Service: message.RegistryKey, Changed: []string{"pullPassword", "pushPassword"}},
+			{Service: message.GitKey, Changed: []string{}},
+		},
+		Restarts: []interactive.PlanResource{
+			{Name: cluster.ZarfAgentName, Namespace: cluster.ZarfNamespaceName},
+			{Name: cluster.ZarfRegistryName, Namespace: cluster.ZarfNamespaceName},
+		},
+	}
+	require.Equal(t, expected, plan)
+}
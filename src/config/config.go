@@ -19,7 +19,9 @@ import (
 const (
 	GithubProject = "zarf-dev/zarf"
 
-	ZarfAgentHost = "agent-hook.zarf.svc"
+	ZarfAgentHost     = "agent-hook.zarf.svc"
+	ZarfRegistryHost  = "zarf-docker-registry.zarf.svc"
+	ZarfGitServerHost = "zarf-gitea-http.zarf.svc"
 
 	ZarfCleanupScriptsPath = "/opt/zarf"
 
@@ -28,12 +30,14 @@ const (
 	ZarfDeployStage = "Deploy"
 	ZarfCreateStage = "Create"
 	ZarfMirrorStage = "Mirror"
+	ZarfRemoveStage = "Remove"
 )
 
 // Zarf Constants for In-Cluster Services.
 const (
 	ZarfImagePullSecretName = "private-registry"
 	ZarfGitServerSecretName = "private-git-server"
+	ZarfCABundleName        = "zarf-ca-bundle"
 
 	UnsetCLIVersion = "unset-development-only"
 )
@@ -69,6 +73,10 @@ var (
 	// Default Time Vars
 	ZarfDefaultTimeout = 15 * time.Minute
 	ZarfDefaultRetries = 3
+
+	// Default Kubernetes API client rate limits, matching the defaults of client-go
+	ZarfDefaultKubeQPS   float32 = 5
+	ZarfDefaultKubeBurst         = 10
 )
 
 // GetArch returns the arch based on a priority list with options for overriding.
@@ -0,0 +1,448 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package config stores the global configuration and constants.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/viper"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// File is the typed contents of a zarf-config file (zarf-config.toml, .yaml, .json or .ini).
+type File struct {
+	LogLevel              string            `json:"log_level,omitempty"`
+	Architecture          string            `json:"architecture,omitempty"`
+	NoLogFile             bool              `json:"no_log_file,omitempty"`
+	NoProgress            bool              `json:"no_progress,omitempty"`
+	NoColor               bool              `json:"no_color,omitempty"`
+	ZarfCache             string            `json:"zarf_cache,omitempty"`
+	NoCache               bool              `json:"no_cache,omitempty"`
+	TmpDir                string            `json:"tmp_dir,omitempty"`
+	Insecure              bool              `json:"insecure,omitempty"`
+	PlainHTTP             bool              `json:"plain_http,omitempty"`
+	InsecureSkipTLSVerify bool              `json:"insecure_skip_tls_verify,omitempty"`
+	CACerts               []string          `json:"ca_certs,omitempty"`
+	ClientCert            string            `json:"client_cert,omitempty"`
+	ClientKey             string            `json:"client_key,omitempty"`
+	Proxy                 string            `json:"proxy,omitempty"`
+	RegistryMirrors       map[string]string `json:"registry_mirrors,omitempty"`
+	KubeQPS               float32           `json:"kube_qps,omitempty"`
+	KubeBurst             int               `json:"kube_burst,omitempty"`
+	KubeConfig            string            `json:"kubeconfig,omitempty"`
+	KubeContexts          []string          `json:"kube_contexts,omitempty"`
+	Init                  InitFile          `json:"init,omitempty"`
+	Package               PackageFile       `json:"package,omitempty"`
+	Dev                   DevFile           `json:"dev,omitempty"`
+}
+
+// InitFile is the init section of a zarf-config file.
+type InitFile struct {
+	Components         string           `json:"components,omitempty"`
+	StorageClass       string           `json:"storage_class,omitempty"`
+	StateEncryptionKey string           `json:"state_encryption_key,omitempty"`
+	Git                InitGitFile      `json:"git,omitempty"`
+	Registry           InitRegistryFile `json:"registry,omitempty"`
+	Artifact           InitArtifactFile `json:"artifact,omitempty"`
+	Agent              InitAgentFile    `json:"agent,omitempty"`
+	PKI                InitPKIFile      `json:"pki,omitempty"`
+}
+
+// InitGitFile is the init.git section of a zarf-config file.
+type InitGitFile struct {
+	URL          string            `json:"url,omitempty"`
+	PushUsername string            `json:"push_username,omitempty"`
+	PushPassword string            `json:"push_password,omitempty"`
+	PullUsername string            `json:"pull_username,omitempty"`
+	PullPassword string            `json:"pull_password,omitempty"`
+	Resources    map[string]string `json:"resources,omitempty"`
+	PVCSize      string            `json:"pvc_size,omitempty"`
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+	Tolerations  []string          `json:"tolerations,omitempty"`
+}
+
+// InitRegistryFile is the init.registry section of a zarf-config file.
+type InitRegistryFile struct {
+	URL          string            `json:"url,omitempty"`
+	NodePort     int               `json:"nodeport,omitempty"`
+	Secret       string            `json:"secret,omitempty"`
+	PushUsername string            `json:"push_username,omitempty"`
+	PushPassword string            `json:"push_password,omitempty"`
+	PullUsername string            `json:"pull_username,omitempty"`
+	PullPassword string            `json:"pull_password,omitempty"`
+	Type         string            `json:"type,omitempty"`
+	Resources    map[string]string `json:"resources,omitempty"`
+	PVCSize      string            `json:"pvc_size,omitempty"`
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+	Tolerations  []string          `json:"tolerations,omitempty"`
+}
+
+// InitAgentFile is the init.agent section of a zarf-config file.
+type InitAgentFile struct {
+	Resources    map[string]string `json:"resources,omitempty"`
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+	Tolerations  []string          `json:"tolerations,omitempty"`
+}
+
+// InitArtifactFile is the init.artifact section of a zarf-config file.
+type InitArtifactFile struct {
+	URL          string `json:"url,omitempty"`
+	PushUsername string `json:"push_username,omitempty"`
+	PushToken    string `json:"push_token,omitempty"`
+}
+
+// InitPKIFile is the init.pki section of a zarf-config file.
+type InitPKIFile struct {
+	CACert string   `json:"ca_cert,omitempty"`
+	CAKey  string   `json:"ca_key,omitempty"`
+	TLSSAN []string `json:"tls_san,omitempty"`
+}
+
+// PackageFile is the package section of a zarf-config file.
+type PackageFile struct {
+	OCIConcurrency        int                `json:"oci_concurrency,omitempty"`
+	PublicKey             string             `json:"public_key,omitempty"`
+	DecryptionKey         string             `json:"decryption_key,omitempty"`
+	DecryptionPassphrase  string             `json:"decryption_passphrase,omitempty"`
+	CertificateIdentity   string             `json:"certificate_identity,omitempty"`
+	CertificateOIDCIssuer string             `json:"certificate_oidc_issuer,omitempty"`
+	TrustedRoot           string             `json:"trusted_root,omitempty"`
+	SourceResolvers       map[string]string  `json:"source_resolvers,omitempty"`
+	Create                PackageCreateFile  `json:"create,omitempty"`
+	Deploy                PackageDeployFile  `json:"deploy,omitempty"`
+	Publish               PackagePublishFile `json:"publish,omitempty"`
+	Pull                  PackagePullFile    `json:"pull,omitempty"`
+}
+
+// PackageCreateFile is the package.create section of a zarf-config file.
+type PackageCreateFile struct {
+	Set                  map[string]string `json:"set,omitempty"`
+	EnvFiles             []string          `json:"env_files,omitempty"`
+	Output               string            `json:"output,omitempty"`
+	SBOM                 bool              `json:"sbom,omitempty"`
+	SBOMOutput           string            `json:"sbom_output,omitempty"`
+	SkipSBOM             bool              `json:"skip_sbom,omitempty"`
+	SBOMFormats          []string          `json:"sbom_formats,omitempty"`
+	MaxPackageSize       int               `json:"max_package_size,omitempty"`
+	MaxArtifactSize      string            `json:"max_artifact_size,omitempty"`
+	SigningKey           string            `json:"signing_key,omitempty"`
+	SigningKeyPassword   string            `json:"signing_key_password,omitempty"`
+	Differential         string            `json:"differential,omitempty"`
+	RegistryOverride     map[string]string `json:"registry_override,omitempty"`
+	Flavor               string            `json:"flavor,omitempty"`
+	AllFlavors           bool              `json:"all_flavors,omitempty"`
+	IncludeSignatures    bool              `json:"include_signatures,omitempty"`
+	Locked               bool              `json:"locked,omitempty"`
+	DryRun               bool              `json:"dry_run,omitempty"`
+	ChecksumDB           bool              `json:"checksum_db,omitempty"`
+	EncryptionKey        string            `json:"encryption_key,omitempty"`
+	EncryptionPassphrase string            `json:"encryption_passphrase,omitempty"`
+	Keyless              bool              `json:"keyless,omitempty"`
+	FulcioURL            string            `json:"fulcio_url,omitempty"`
+	RekorURL             string            `json:"rekor_url,omitempty"`
+	OIDCIssuer           string            `json:"oidc_issuer,omitempty"`
+	IdentityToken        string            `json:"identity_token,omitempty"`
+}
+
+// PackageDeployFile is the package.deploy section of a zarf-config file.
+type PackageDeployFile struct {
+	Set               map[string]string `json:"set,omitempty"`
+	Components        string            `json:"components,omitempty"`
+	Shasum            string            `json:"shasum,omitempty"`
+	SourceMirrors     []string          `json:"source_mirrors,omitempty"`
+	ArchiveChecksum   string            `json:"archive_checksum,omitempty"`
+	ArchiveSignature  string            `json:"archive_signature,omitempty"`
+	ArchiveKey        string            `json:"archive_key,omitempty"`
+	Sget              string            `json:"sget,omitempty"`
+	SkipWebhooks      bool              `json:"skip_webhooks,omitempty"`
+	Timeout           time.Duration     `json:"timeout,omitempty"`
+	ComponentTimeouts map[string]string `json:"component_timeouts,omitempty"`
+	Retries           int               `json:"retries,omitempty"`
+	Entitlements      []string          `json:"entitlements,omitempty"`
+	VariableOverlays  string            `json:"variable_overlays,omitempty"`
+	PublishStatus     bool              `json:"publish_status,omitempty"`
+	Concurrency       int               `json:"concurrency,omitempty"`
+	RollbackOnFail    bool              `json:"rollback_on_failure,omitempty"`
+	DryRun            bool              `json:"dry_run,omitempty"`
+	PolicyGates       []string          `json:"policy_gates,omitempty"`
+}
+
+// PackagePublishFile is the package.publish section of a zarf-config file.
+type PackagePublishFile struct {
+	SigningKey         string `json:"signing_key,omitempty"`
+	SigningKeyPassword string `json:"signing_key_password,omitempty"`
+	Keyless            bool   `json:"keyless,omitempty"`
+	FulcioURL          string `json:"fulcio_url,omitempty"`
+	RekorURL           string `json:"rekor_url,omitempty"`
+	OIDCIssuer         string `json:"oidc_issuer,omitempty"`
+	IdentityToken      string `json:"identity_token,omitempty"`
+}
+
+// PackagePullFile is the package.pull section of a zarf-config file.
+type PackagePullFile struct {
+	OutputDirectory string `json:"output_directory,omitempty"`
+}
+
+// DevFile is the dev section of a zarf-config file.
+type DevFile struct {
+	Deploy DevDeployFile `json:"deploy,omitempty"`
+}
+
+// DevDeployFile is the dev.deploy section of a zarf-config file.
+type DevDeployFile struct {
+	NoYOLO bool `json:"no_yolo,omitempty"`
+}
+
+// DefaultFile returns a zarf-config with the same non-zero defaults as the CLI.
+func DefaultFile() File {
+	return File{
+		LogLevel:  "info",
+		ZarfCache: ZarfDefaultCachePath,
+		KubeQPS:   ZarfDefaultKubeQPS,
+		KubeBurst: ZarfDefaultKubeBurst,
+		Package: PackageFile{
+			OCIConcurrency: 3,
+			Deploy: PackageDeployFile{
+				Timeout: ZarfDefaultTimeout,
+				Retries: ZarfDefaultRetries,
+			},
+		},
+	}
+}
+
+// Load reads the zarf-config file at path on top of the defaults of DefaultFile, followed by any overlays in order so that
+// the keys set in later files override those set in earlier ones. Unlike the CLI, environment variables are not read so
+// that every loaded configuration is isolated from the process it is loaded in.
+func Load(path string, overlays ...string) (File, error) {
+	// Use a key delimiter that cannot appear in keys so that map keys such as registry hosts are not split on dots,
+	// except for INI files where sections are nested by their dotted names.
+	v := viper.New()
+	if filepath.Ext(path) != ".ini" {
+		v = viper.NewWithOptions(viper.KeyDelimiter("::"))
+	}
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return File{}, fmt.Errorf("unable to read the zarf-config file %s: %w", path, err)
+	}
+	for _, overlay := range overlays {
+		v.SetConfigFile(overlay)
+		if err := v.MergeInConfig(); err != nil {
+			return File{}, fmt.Errorf("unable to read the zarf-config file %s: %w", overlay, err)
+		}
+	}
+	f := DefaultFile()
+	if err := v.Unmarshal(&f, func(c *mapstructure.DecoderConfig) {
+		c.TagName = "json"
+	}); err != nil {
+		return File{}, fmt.Errorf("unable to parse the zarf-config file %s: %w", strings.Join(append([]string{path}, overlays...), ", "), err)
+	}
+	return f, nil
+}
+
+// Write writes the zarf-config to path as TOML, YAML or JSON depending on its extension, overwriting any existing file.
+func Write(path string, f File) error {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	values := map[string]any{}
+	if err := json.Unmarshal(b, &values); err != nil {
+		return err
+	}
+	// Durations are written in their human readable form rather than as nanoseconds.
+	if f.Package.Deploy.Timeout != 0 {
+		values["package"].(map[string]any)["deploy"].(map[string]any)["timeout"] = f.Package.Deploy.Timeout.String()
+	}
+
+	var out []byte
+	switch ext := strings.TrimPrefix(filepath.Ext(path), "."); ext {
+	case "toml":
+		out, err = toml.Marshal(values)
+	case "yaml", "yml":
+		out, err = goyaml.Marshal(values)
+	case "json":
+		out, err = json.MarshalIndent(values, "", "  ")
+	default:
+		return fmt.Errorf("unable to write the zarf-config file %s: unsupported format %q", path, ext)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, out, helpers.ReadWriteUser); err != nil {
+		return fmt.Errorf("unable to write the zarf-config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// CommonOptions returns the options of the zarf-config that apply across commands.
+func (f File) CommonOptions() types.ZarfCommonOptions {
+	return types.ZarfCommonOptions{
+		Insecure:              f.Insecure,
+		InsecureSkipTLSVerify: f.InsecureSkipTLSVerify,
+		PlainHTTP:             f.PlainHTTP,
+		CACertPaths:           f.CACerts,
+		ClientCertPath:        f.ClientCert,
+		ClientKeyPath:         f.ClientKey,
+		Proxy:                 f.Proxy,
+		RegistryMirrors:       maps.Clone(f.RegistryMirrors),
+		CachePath:             f.ZarfCache,
+		NoCache:               f.NoCache,
+		TempDirectory:         f.TmpDir,
+		OCIConcurrency:        f.Package.OCIConcurrency,
+		KubeQPS:               f.KubeQPS,
+		KubeBurst:             f.KubeBurst,
+		KubeConfig:            f.KubeConfig,
+	}
+}
+
+// PackagerConfig returns the packager options of the zarf-config, with the keys of set variables upper cased like the
+// CLI does.
+func (f File) PackagerConfig() types.PackagerConfig {
+	create := f.Package.Create
+	deploy := f.Package.Deploy
+	publish := f.Package.Publish
+	return types.PackagerConfig{
+		CreateOpts: types.ZarfCreateOptions{
+			SkipSBOM:                create.SkipSBOM,
+			Output:                  create.Output,
+			ViewSBOM:                create.SBOM,
+			SBOMOutputDir:           create.SBOMOutput,
+			SBOMFormats:             create.SBOMFormats,
+			SetVariables:            upperKeys(create.Set),
+			EnvFiles:                create.EnvFiles,
+			MaxPackageSizeMB:        create.MaxPackageSize,
+			MaxArtifactSize:         create.MaxArtifactSize,
+			SigningKeyPath:          create.SigningKey,
+			SigningKeyPassword:      create.SigningKeyPassword,
+			DifferentialPackagePath: create.Differential,
+			RegistryOverrides:       maps.Clone(create.RegistryOverride),
+			Flavor:                  create.Flavor,
+			AllFlavors:              create.AllFlavors,
+			NoYOLO:                  f.Dev.Deploy.NoYOLO,
+			IncludeSignatures:       create.IncludeSignatures,
+			Locked:                  create.Locked,
+			DryRun:                  create.DryRun,
+			ChecksumDB:              create.ChecksumDB,
+			EncryptionKeyPath:       create.EncryptionKey,
+			EncryptionPassphrase:    create.EncryptionPassphrase,
+			Keyless: types.ZarfKeylessSigningOptions{
+				Enabled:       create.Keyless,
+				FulcioURL:     create.FulcioURL,
+				RekorURL:      create.RekorURL,
+				OIDCIssuer:    create.OIDCIssuer,
+				IdentityToken: create.IdentityToken,
+			},
+		},
+		PkgOpts: types.ZarfPackageOptions{
+			Shasum:                deploy.Shasum,
+			SourceMirrors:         deploy.SourceMirrors,
+			ArchiveChecksum:       deploy.ArchiveChecksum,
+			ArchiveSignature:      deploy.ArchiveSignature,
+			ArchiveKeyPath:        deploy.ArchiveKey,
+			OptionalComponents:    deploy.Components,
+			SGetKeyPath:           deploy.Sget,
+			SetVariables:          upperKeys(deploy.Set),
+			PublicKeyPath:         f.Package.PublicKey,
+			Retries:               deploy.Retries,
+			DecryptionKeyPath:     f.Package.DecryptionKey,
+			DecryptionPassphrase:  f.Package.DecryptionPassphrase,
+			CertificateIdentity:   f.Package.CertificateIdentity,
+			CertificateOIDCIssuer: f.Package.CertificateOIDCIssuer,
+			TrustedRootPath:       f.Package.TrustedRoot,
+		},
+		DeployOpts: types.ZarfDeployOptions{
+			SkipWebhooks:      deploy.SkipWebhooks,
+			Timeout:           deploy.Timeout,
+			ComponentTimeouts: maps.Clone(deploy.ComponentTimeouts),
+			Entitlements:      deploy.Entitlements,
+			VariableOverlays:  deploy.VariableOverlays,
+			PublishStatus:     deploy.PublishStatus,
+			Concurrency:       deploy.Concurrency,
+			RollbackOnFailure: deploy.RollbackOnFail,
+			DryRun:            deploy.DryRun,
+			PolicyGates:       deploy.PolicyGates,
+		},
+		InitOpts: types.ZarfInitOptions{
+			GitServer: types.GitServerInfo{
+				Address:      f.Init.Git.URL,
+				PushUsername: f.Init.Git.PushUsername,
+				PushPassword: f.Init.Git.PushPassword,
+				PullUsername: f.Init.Git.PullUsername,
+				PullPassword: f.Init.Git.PullPassword,
+			},
+			RegistryInfo: types.RegistryInfo{
+				Address:      f.Init.Registry.URL,
+				NodePort:     f.Init.Registry.NodePort,
+				Secret:       f.Init.Registry.Secret,
+				PushUsername: f.Init.Registry.PushUsername,
+				PushPassword: f.Init.Registry.PushPassword,
+				PullUsername: f.Init.Registry.PullUsername,
+				PullPassword: f.Init.Registry.PullPassword,
+			},
+			ArtifactServer: types.ArtifactServerInfo{
+				Address:      f.Init.Artifact.URL,
+				PushUsername: f.Init.Artifact.PushUsername,
+				PushToken:    f.Init.Artifact.PushToken,
+			},
+			StorageClass:       f.Init.StorageClass,
+			CACertPath:         f.Init.PKI.CACert,
+			CAKeyPath:          f.Init.PKI.CAKey,
+			TLSSubjectAltNames: f.Init.PKI.TLSSAN,
+			StateEncryptionKey: f.Init.StateEncryptionKey,
+			RegistryOverrides: types.InitComponentOverrides{
+				Resources:    f.Init.Registry.Resources,
+				PVCSize:      f.Init.Registry.PVCSize,
+				NodeSelector: f.Init.Registry.NodeSelector,
+				Tolerations:  f.Init.Registry.Tolerations,
+			},
+			GitServerOverrides: types.InitComponentOverrides{
+				Resources:    f.Init.Git.Resources,
+				PVCSize:      f.Init.Git.PVCSize,
+				NodeSelector: f.Init.Git.NodeSelector,
+				Tolerations:  f.Init.Git.Tolerations,
+			},
+			AgentOverrides: types.InitComponentOverrides{
+				Resources:    f.Init.Agent.Resources,
+				NodeSelector: f.Init.Agent.NodeSelector,
+				Tolerations:  f.Init.Agent.Tolerations,
+			},
+		},
+		PublishOpts: types.ZarfPublishOptions{
+			SigningKeyPath:     publish.SigningKey,
+			SigningKeyPassword: publish.SigningKeyPassword,
+			Keyless: types.ZarfKeylessSigningOptions{
+				Enabled:       publish.Keyless,
+				FulcioURL:     publish.FulcioURL,
+				RekorURL:      publish.RekorURL,
+				OIDCIssuer:    publish.OIDCIssuer,
+				IdentityToken: publish.IdentityToken,
+			},
+		},
+		PullOpts: types.ZarfPullOptions{
+			OutputDirectory: f.Package.Pull.OutputDirectory,
+		},
+	}
+}
+
+func upperKeys(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	upper := make(map[string]string, len(m))
+	for k, v := range m {
+		upper[strings.ToUpper(k)] = v
+	}
+	return upper
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package config stores the global configuration and constants.
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadExamples(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"zarf-config.toml", "zarf-config.yaml", "zarf-config.json", "zarf-config.ini"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := Load(filepath.Join("..", "..", "examples", "config-file", name))
+			require.NoError(t, err)
+			require.Equal(t, "info", f.LogLevel)
+			require.Equal(t, ZarfDefaultTimeout, f.Package.Deploy.Timeout)
+			require.Equal(t, "lion", f.Package.Deploy.Components)
+
+			cfg := f.PackagerConfig()
+			require.Equal(t, "stripes", cfg.CreateOpts.SetVariables["ZEBRA"])
+			require.Equal(t, "iridescent", cfg.PkgOpts.SetVariables["SCORPION"])
+			require.Equal(t, "lion", cfg.PkgOpts.OptionalComponents)
+			require.Equal(t, ZarfDefaultRetries, cfg.PkgOpts.Retries)
+			require.Equal(t, 3, f.CommonOptions().OCIConcurrency)
+		})
+	}
+}
+
+func TestLoadOverlays(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	require.NoError(t, os.WriteFile(base, []byte("log_level: debug\npackage:\n  create:\n    set:\n      domain: example.com\n      registry: ghcr.io\n    env_files:\n      - base.env\n"), 0o600))
+	overlay := filepath.Join(dir, "overlay.toml")
+	require.NoError(t, os.WriteFile(overlay, []byte("[package.create.set]\ndomain = 'prod.example.com'\n"), 0o600))
+
+	f, err := Load(base, overlay)
+	require.NoError(t, err)
+	require.Equal(t, "debug", f.LogLevel)
+	require.Equal(t, map[string]string{"domain": "prod.example.com", "registry": "ghcr.io"}, f.Package.Create.Set)
+	require.Equal(t, []string{"base.env"}, f.PackagerConfig().CreateOpts.EnvFiles)
+
+	_, err = Load(base, filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestWriteLoad(t *testing.T) {
+	t.Parallel()
+
+	f := DefaultFile()
+	f.TmpDir = "/tmp/zarf"
+	f.InsecureSkipTLSVerify = true
+	f.Init.PKI.TLSSAN = []string{"registry.example.com", "10.0.0.1"}
+	f.Init.Registry.NodePort = 31999
+	f.Init.Registry.Type = "zot"
+	f.Init.Registry.PVCSize = "100Gi"
+	f.Init.Agent.Tolerations = []string{"dedicated=infra:NoSchedule"}
+	f.Package.Create.Set = map[string]string{"DOMAIN": "example.com"}
+	f.Package.Create.RegistryOverride = map[string]string{"docker.io": "registry.example.com"}
+	f.Package.Deploy.Timeout = 30 * time.Minute
+	f.Dev.Deploy.NoYOLO = true
+
+	for _, ext := range []string{"toml", "yaml", "json"} {
+		ext := ext
+		t.Run(ext, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "zarf-config."+ext)
+			require.NoError(t, Write(path, f))
+			loaded, err := Load(path)
+			require.NoError(t, err)
+			// Keys are case insensitive in zarf-config files.
+			expected := f
+			expected.Package.Create.Set = map[string]string{"domain": "example.com"}
+			require.Equal(t, expected, loaded)
+			require.Equal(t, "example.com", loaded.PackagerConfig().CreateOpts.SetVariables["DOMAIN"])
+		})
+	}
+
+	_, err := Load(filepath.Join(t.TempDir(), "zarf-config.toml"))
+	require.Error(t, err)
+}
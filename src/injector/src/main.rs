@@ -24,6 +24,7 @@ use regex_lite::Regex;
 use serde_json::Value;
 use sha2::{Digest, Sha256};
 use tar::Archive;
+use tokio::net::TcpListener;
 use tokio_util::io::ReaderStream;
 const OCI_MIME_TYPE: &str = "application/vnd.oci.image.manifest.v1+json";
 
@@ -263,7 +264,11 @@ async fn main() {
 
     unpack(payload_sha);
 
-    let listener = tokio::net::TcpListener::bind("0.0.0.0:5000").await.unwrap();
+    // Listen on both IP families, falling back to IPv4 on nodes without IPv6
+    let listener = match TcpListener::bind("[::]:5000").await {
+        Ok(listener) => listener,
+        Err(_) => TcpListener::bind("0.0.0.0:5000").await.unwrap(),
+    };
     println!("listening on {}", listener.local_addr().unwrap());
     axum::serve(listener, start_seed_registry()).await.unwrap();
     println!("Usage: {} <sha256sum>", args[1]);
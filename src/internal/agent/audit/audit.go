@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package audit records the changes made by the Zarf agent as structured JSON.
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	v1 "k8s.io/api/admission/v1"
+)
+
+// Record is the audit record of a resource mutated by the agent.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Hook      string    `json:"hook"`
+	UID       string    `json:"uid"`
+	Operation string    `json:"operation"`
+	User      string    `json:"user,omitempty"`
+	Group     string    `json:"group,omitempty"`
+	Version   string    `json:"version"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Changes   []Change  `json:"changes"`
+	// DryRun is set when the changes were reported but not applied
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Change is a value rewritten by the agent, such as an image or a repository URL.
+type Change struct {
+	// JSON pointer to the value within the resource
+	Path string `json:"path"`
+	// Value before the mutation, empty if the value was added
+	Original string `json:"original,omitempty"`
+	// Value after the mutation
+	Mutated string `json:"mutated"`
+}
+
+// NewRecord returns the audit record of the patch operations applied to the resource in the admission request.
+func NewRecord(hook string, r *v1.AdmissionRequest, ops []operations.PatchOperation) Record {
+	name := r.Name
+	obj := map[string]any{}
+	// The object is decoded best effort, the record is still useful without the original values
+	//nolint:errcheck // ignore
+	json.Unmarshal(r.Object.Raw, &obj)
+	if metadata, ok := obj["metadata"].(map[string]any); ok && name == "" {
+		// The name of a created resource is only in the object
+		name, _ = metadata["name"].(string)
+		if name == "" {
+			name, _ = metadata["generateName"].(string)
+		}
+	}
+
+	record := Record{
+		Time:      time.Now().UTC(),
+		Hook:      hook,
+		UID:       string(r.UID),
+		Operation: string(r.Operation),
+		User:      r.UserInfo.Username,
+		Group:     r.Kind.Group,
+		Version:   r.Kind.Version,
+		Kind:      r.Kind.Kind,
+		Namespace: r.Namespace,
+		Name:      name,
+		Changes:   []Change{},
+	}
+	for _, op := range ops {
+		mutated, ok := op.Value.(string)
+		if !ok {
+			continue
+		}
+		original, _ := lookup(obj, op.Path).(string)
+		if original == mutated {
+			continue
+		}
+		record.Changes = append(record.Changes, Change{Path: op.Path, Original: original, Mutated: mutated})
+	}
+	return record
+}
+
+// lookup returns the value at the JSON pointer within the object or nil if it does not exist.
+func lookup(obj any, pointer string) any {
+	if pointer == "" {
+		return obj
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := obj.(type) {
+		case map[string]any:
+			obj = v[token]
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			obj = v[i]
+		default:
+			return nil
+		}
+	}
+	return obj
+}
+
+// Logger writes audit records as JSON lines.
+type Logger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLogger returns a logger that writes audit records to w.
+func NewLogger(w io.Writer) *Logger {
+	return &Logger{w: w}
+}
+
+// Log writes the audit record as a single line.
+func (l *Logger) Log(record Record) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.w.Write(append(b, '\n'))
+	return err
+}
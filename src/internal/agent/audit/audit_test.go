@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	v1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestNewRecord(t *testing.T) {
+	t.Parallel()
+
+	raw := []byte(`{
+		"metadata": {"generateName": "app-", "labels": {"app": "nginx"}, "annotations": {"example.com/url": "https://github.com/stefanprodan/podinfo.git"}},
+		"spec": {"containers": [{"name": "nginx", "image": "nginx:1.27"}, {"name": "sidecar", "image": "127.0.0.1:31999/library/busybox:1.36-zarf-123"}]}
+	}`)
+	r := &v1.AdmissionRequest{
+		UID:       "a1b2",
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Namespace: "default",
+		Operation: v1.Create,
+		UserInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:kube-system:replicaset-controller"},
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+	ops := []operations.PatchOperation{
+		operations.ReplacePatchOperation("/spec/imagePullSecrets", []map[string]string{{"name": "private-registry"}}),
+		operations.ReplacePatchOperation("/spec/containers/0/image", "127.0.0.1:31999/library/nginx:1.27-zarf-456"),
+		operations.ReplacePatchOperation("/spec/containers/1/image", "127.0.0.1:31999/library/busybox:1.36-zarf-123"),
+		operations.ReplacePatchOperation("/metadata/annotations/example.com~1url", "http://zarf-gitea-http.zarf.svc:3000/zarf-git-user/podinfo-1646971829.git"),
+		operations.AddPatchOperation("/spec/containers/-", "invalid"),
+		operations.ReplacePatchOperation("/metadata/labels", map[string]string{"app": "nginx", "zarf-agent": "patched"}),
+	}
+
+	record := NewRecord("/mutate/pod", r, ops)
+	require.NotZero(t, record.Time)
+	record.Time = time.Time{}
+	require.Equal(t, Record{
+		Hook:      "/mutate/pod",
+		UID:       "a1b2",
+		Operation: "CREATE",
+		User:      "system:serviceaccount:kube-system:replicaset-controller",
+		Version:   "v1",
+		Kind:      "Pod",
+		Namespace: "default",
+		Name:      "app-",
+		Changes: []Change{
+			{Path: "/spec/containers/0/image", Original: "nginx:1.27", Mutated: "127.0.0.1:31999/library/nginx:1.27-zarf-456"},
+			{Path: "/metadata/annotations/example.com~1url", Original: "https://github.com/stefanprodan/podinfo.git", Mutated: "http://zarf-gitea-http.zarf.svc:3000/zarf-git-user/podinfo-1646971829.git"},
+			{Path: "/spec/containers/-", Mutated: "invalid"},
+		},
+	}, record)
+}
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf)
+	require.NoError(t, logger.Log(Record{Hook: "/mutate/flux-gitrepository", Kind: "GitRepository", Name: "podinfo"}))
+	require.NoError(t, logger.Log(Record{Hook: "/mutate/pod", Kind: "Pod", Changes: []Change{{Path: "/spec/containers/0/image", Mutated: "nginx"}}}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(lines[1], &record))
+	require.Equal(t, "/mutate/pod", record["hook"])
+	require.Equal(t, []any{map[string]any{"path": "/spec/containers/0/image", "mutated": "nginx"}}, record["changes"])
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit", "agent.log")
+	rf, err := NewRollingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := rf.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, rf.Close())
+
+	for name, expected := range map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"} {
+		b, err := os.ReadFile(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(b))
+	}
+	require.NoFileExists(t, path+".3")
+
+	// the size of an existing file counts towards the maximum, and it is removed when no backups are kept
+	rf, err = NewRollingFile(path, 10, 0)
+	require.NoError(t, err)
+	_, err = rf.Write([]byte("fifth\n"))
+	require.NoError(t, err)
+	require.NoError(t, rf.Close())
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "fifth\n", string(b))
+	b, err = os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "third\n", string(b))
+
+	_, err = NewRollingFile(path, 0, 1)
+	require.EqualError(t, err, "the maximum size of "+path+" must be greater than 0")
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/config/lang"
@@ -44,6 +45,8 @@ type ApplicationSpec struct {
 type ApplicationSource struct {
 	// RepoURL is the URL to the repository (Git or Helm) that contains the application manifests.
 	RepoURL string `json:"repoURL"`
+	// Chart is a Helm chart name, and must be specified for applications sourced from a Helm repo.
+	Chart string `json:"chart,omitempty"`
 }
 
 // NewApplicationMutationHook creates a new instance of the ArgoCD Application mutation hook.
@@ -60,7 +63,7 @@ func NewApplicationMutationHook(ctx context.Context, cluster *cluster.Cluster) o
 
 // mutateApplication mutates the git repository url to point to the repository URL defined in the ZarfState.
 func mutateApplication(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
-	state, err := cluster.LoadZarfState(ctx)
+	state, err := loadZarfState(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -72,23 +75,9 @@ func mutateApplication(ctx context.Context, r *v1.AdmissionRequest, cluster *clu
 		return nil, fmt.Errorf(lang.ErrUnmarshal, err)
 	}
 
-	patches := make([]operations.PatchOperation, 0)
-	if app.Spec.Source != nil {
-		patchedURL, err := getPatchedRepoURL(app.Spec.Source.RepoURL, state.GitServer, r)
-		if err != nil {
-			return nil, err
-		}
-		patches = populateSingleSourceArgoApplicationPatchOperations(patchedURL, patches)
-	}
-
-	if len(app.Spec.Sources) > 0 {
-		for idx, source := range app.Spec.Sources {
-			patchedURL, err := getPatchedRepoURL(source.RepoURL, state.GitServer, r)
-			if err != nil {
-				return nil, err
-			}
-			patches = populateMultipleSourceArgoApplicationPatchOperations(idx, patchedURL, patches)
-		}
+	patches, err := getApplicationSourcePatches(ctx, cluster, state, r, "/spec", app.Spec)
+	if err != nil {
+		return nil, err
 	}
 
 	patches = append(patches, getLabelPatch(app.Labels))
@@ -130,12 +119,83 @@ func getPatchedRepoURL(repoURL string, gs types.GitServerInfo, r *v1.AdmissionRe
 	return patchedURL, nil
 }
 
-// Patch updates of the Argo source spec.
-func populateSingleSourceArgoApplicationPatchOperations(repoURL string, patches []operations.PatchOperation) []operations.PatchOperation {
-	return append(patches, operations.ReplacePatchOperation("/spec/source/repoURL", repoURL))
+// getApplicationSourcePatches returns the patches that point the sources of the application spec at the given path to
+// the Zarf git server or registry.
+func getApplicationSourcePatches(ctx context.Context, c *cluster.Cluster, state *types.ZarfState, r *v1.AdmissionRequest, path string, spec ApplicationSpec) ([]operations.PatchOperation, error) {
+	patches := make([]operations.PatchOperation, 0)
+	// The registry address is only looked up if the application has a source from an OCI Helm repository.
+	registryAddress := ""
+	patchSource := func(source ApplicationSource, sourcePath string) error {
+		if isTemplated(source.RepoURL) {
+			message.Debugf("Skipping the templated repoURL (%s) at %s", source.RepoURL, sourcePath)
+			return nil
+		}
+		if source.Chart == "" {
+			patchedURL, err := getPatchedRepoURL(source.RepoURL, state.GitServer, r)
+			if err != nil {
+				return err
+			}
+			patches = append(patches, operations.ReplacePatchOperation(sourcePath+"/repoURL", patchedURL))
+			return nil
+		}
+		if !isOCIChartRepo(source.RepoURL) {
+			message.Debugf("Skipping the Helm repository repoURL (%s) at %s as it can not be served by Zarf", source.RepoURL, sourcePath)
+			return nil
+		}
+		if registryAddress == "" {
+			// Get the registry service info if this is a NodePort service to use the internal kube-dns
+			address, err := c.GetServiceInfoFromRegistryAddress(ctx, state.RegistryInfo.Address)
+			if err != nil {
+				return err
+			}
+			registryAddress = address
+		}
+		patchedURL, err := getPatchedChartRepoURL(source.RepoURL, registryAddress)
+		if err != nil {
+			return err
+		}
+		patches = append(patches, operations.ReplacePatchOperation(sourcePath+"/repoURL", patchedURL))
+		return nil
+	}
+
+	if spec.Source != nil {
+		if err := patchSource(*spec.Source, path+"/source"); err != nil {
+			return nil, err
+		}
+	}
+	for idx, source := range spec.Sources {
+		if err := patchSource(source, fmt.Sprintf("%s/sources/%d", path, idx)); err != nil {
+			return nil, err
+		}
+	}
+	return patches, nil
+}
+
+// getPatchedChartRepoURL points an OCI Helm repository at the Zarf registry, keeping the path of the repository.
+func getPatchedChartRepoURL(repoURL string, registryAddress string) (string, error) {
+	refInfo, err := transform.ParseImageRef(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse the Helm repository URL %s: %w", repoURL, err)
+	}
+	// Do not mutate a URL that has already been mutated.
+	if strings.HasPrefix(registryAddress, refInfo.Host) {
+		return repoURL, nil
+	}
+	patchedURL := fmt.Sprintf("%s/%s", registryAddress, refInfo.Path)
+	if strings.HasPrefix(repoURL, helpers.OCIURLPrefix) {
+		patchedURL = helpers.OCIURLPrefix + patchedURL
+	}
+	message.Debugf("original Helm repoURL of (%s) got mutated to (%s)", repoURL, patchedURL)
+	return patchedURL, nil
+}
+
+// isOCIChartRepo returns true if a Helm repository URL refers to an OCI registry, Argo CD treats Helm repository
+// URLs without a scheme as OCI registries.
+func isOCIChartRepo(repoURL string) bool {
+	return strings.HasPrefix(repoURL, helpers.OCIURLPrefix) || !strings.Contains(repoURL, "://")
 }
 
-// Patch updates of the Argo sources spec.
-func populateMultipleSourceArgoApplicationPatchOperations(idx int, repoURL string, patches []operations.PatchOperation) []operations.PatchOperation {
-	return append(patches, operations.ReplacePatchOperation(fmt.Sprintf("/spec/sources/%d/repoURL", idx), repoURL))
+// isTemplated returns true if a value is an ApplicationSet template that is only resolved by Argo CD.
+func isTemplated(value string) bool {
+	return strings.Contains(value, "{{")
 }
@@ -33,10 +33,13 @@ func TestArgoAppWebhook(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
-	state := &types.ZarfState{GitServer: types.GitServerInfo{
-		Address:      "https://git-server.com",
-		PushUsername: "a-push-user",
-	}}
+	state := &types.ZarfState{
+		GitServer: types.GitServerInfo{
+			Address:      "https://git-server.com",
+			PushUsername: "a-push-user",
+		},
+		RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"},
+	}
 	c := createTestClientWithZarfState(ctx, t, state)
 	handler := admission.NewHandler().Serve(NewApplicationMutationHook(ctx, c))
 
@@ -78,6 +81,52 @@ func TestArgoAppWebhook(t *testing.T) {
 			},
 			code: http.StatusOK,
 		},
+		{
+			name: "should mutate helm chart sources from OCI repositories",
+			admissionReq: createArgoAppAdmissionRequest(t, v1.Create, &Application{
+				Spec: ApplicationSpec{
+					Sources: []ApplicationSource{
+						{
+							RepoURL: "ghcr.io/stefanprodan/charts",
+							Chart:   "podinfo",
+						},
+						{
+							RepoURL: "oci://ghcr.io/stefanprodan/charts",
+							Chart:   "podinfo",
+						},
+						{
+							RepoURL: "https://stefanprodan.github.io/podinfo",
+							Chart:   "podinfo",
+						},
+						{
+							RepoURL: "127.0.0.1:31999/stefanprodan/charts",
+							Chart:   "podinfo",
+						},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/sources/0/repoURL",
+					"127.0.0.1:31999/stefanprodan/charts",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/sources/1/repoURL",
+					"oci://127.0.0.1:31999/stefanprodan/charts",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/sources/3/repoURL",
+					"127.0.0.1:31999/stefanprodan/charts",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
 		{
 			name: "should return internal server error on bad git URL",
 			admissionReq: createArgoAppAdmissionRequest(t, v1.Create, &Application{
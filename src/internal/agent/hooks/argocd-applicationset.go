@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplicationSet is a definition of an ArgoCD ApplicationSet resource.
+// The ArgoCD ApplicationSet structs in this file have been partially copied from upstream.
+//
+// https://github.com/argoproj/argo-cd/blob/v2.11.0/pkg/apis/application/v1alpha1/applicationset_types.go
+type ApplicationSet struct {
+	Spec ApplicationSetSpec `json:"spec"`
+	metav1.ObjectMeta
+}
+
+// ApplicationSetSpec represents a class of application set state.
+type ApplicationSetSpec struct {
+	// Generators produce the parameters that the template is rendered with.
+	Generators []ApplicationSetGenerator `json:"generators"`
+	// Template is the Application that is rendered for each set of parameters.
+	Template ApplicationSetTemplate `json:"template"`
+}
+
+// ApplicationSetTemplate represents the Application that is rendered by an ApplicationSet.
+type ApplicationSetTemplate struct {
+	Spec ApplicationSpec `json:"spec"`
+}
+
+// ApplicationSetGenerator represents a generator at the top level of an ApplicationSet or nested in a matrix or merge
+// generator.
+type ApplicationSetGenerator struct {
+	// Git generates parameters from the files or directories of a git repository.
+	Git *GitGenerator `json:"git,omitempty"`
+	// Matrix combines the parameters of every pair of its generators.
+	Matrix *CombinationGenerator `json:"matrix,omitempty"`
+	// Merge merges the parameters of its generators.
+	Merge *CombinationGenerator `json:"merge,omitempty"`
+}
+
+// GitGenerator generates parameters from the files or directories of a git repository.
+type GitGenerator struct {
+	// RepoURL is the URL to the git repository.
+	RepoURL string `json:"repoURL"`
+}
+
+// CombinationGenerator combines the parameters of the generators nested within it.
+type CombinationGenerator struct {
+	Generators []ApplicationSetGenerator `json:"generators"`
+}
+
+// NewApplicationSetMutationHook creates a new instance of the ArgoCD ApplicationSet mutation hook.
+func NewApplicationSetMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateApplicationSet(ctx, r, cluster)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateApplicationSet(ctx, r, cluster)
+		},
+	}
+}
+
+// mutateApplicationSet mutates the git repository urls of the generators and the repository urls of the application
+// template to point to the git server and registry defined in the ZarfState.
+func mutateApplicationSet(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+	state, err := loadZarfState(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Debugf("Using the url of (%s) to mutate the ArgoCD ApplicationSet", state.GitServer.Address)
+
+	appSet := ApplicationSet{}
+	if err = json.Unmarshal(r.Object.Raw, &appSet); err != nil {
+		return nil, fmt.Errorf(lang.ErrUnmarshal, err)
+	}
+
+	patches, err := getGeneratorPatches(state.GitServer, r, "/spec/generators", appSet.Spec.Generators)
+	if err != nil {
+		return nil, err
+	}
+
+	templatePatches, err := getApplicationSourcePatches(ctx, cluster, state, r, "/spec/template/spec", appSet.Spec.Template.Spec)
+	if err != nil {
+		return nil, err
+	}
+	patches = append(patches, templatePatches...)
+
+	patches = append(patches, getLabelPatch(appSet.Labels))
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
+
+// getGeneratorPatches returns the patches that point the git generators at the given path, including those nested in
+// matrix and merge generators, to the Zarf git server.
+func getGeneratorPatches(gs types.GitServerInfo, r *v1.AdmissionRequest, path string, generators []ApplicationSetGenerator) ([]operations.PatchOperation, error) {
+	patches := make([]operations.PatchOperation, 0)
+	for idx, generator := range generators {
+		generatorPath := fmt.Sprintf("%s/%d", path, idx)
+		if generator.Git != nil && !isTemplated(generator.Git.RepoURL) {
+			patchedURL, err := getPatchedRepoURL(generator.Git.RepoURL, gs, r)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, operations.ReplacePatchOperation(generatorPath+"/git/repoURL", patchedURL))
+		}
+		if generator.Matrix != nil {
+			nestedPatches, err := getGeneratorPatches(gs, r, generatorPath+"/matrix/generators", generator.Matrix.Generators)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, nestedPatches...)
+		}
+		if generator.Merge != nil {
+			nestedPatches, err := getGeneratorPatches(gs, r, generatorPath+"/merge/generators", generator.Merge.Generators)
+			if err != nil {
+				return nil, err
+			}
+			patches = append(patches, nestedPatches...)
+		}
+	}
+	return patches, nil
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func createArgoAppSetAdmissionRequest(t *testing.T, op v1.Operation, argoAppSet *ApplicationSet) *v1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(argoAppSet)
+	require.NoError(t, err)
+	return &v1.AdmissionRequest{
+		Operation: op,
+		Object: runtime.RawExtension{
+			Raw: raw,
+		},
+	}
+}
+
+func TestArgoAppSetWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := &types.ZarfState{
+		GitServer: types.GitServerInfo{
+			Address:      "https://git-server.com",
+			PushUsername: "a-push-user",
+		},
+		RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"},
+	}
+	c := createTestClientWithZarfState(ctx, t, state)
+	handler := admission.NewHandler().Serve(NewApplicationSetMutationHook(ctx, c))
+
+	tests := []admissionTest{
+		{
+			name: "should mutate generators and the template",
+			admissionReq: createArgoAppSetAdmissionRequest(t, v1.Create, &ApplicationSet{
+				Spec: ApplicationSetSpec{
+					Generators: []ApplicationSetGenerator{
+						{
+							Git: &GitGenerator{RepoURL: "https://diff-git-server.com/peanuts"},
+						},
+						{
+							Matrix: &CombinationGenerator{
+								Generators: []ApplicationSetGenerator{
+									{
+										Git: &GitGenerator{RepoURL: "https://diff-git-server.com/cashews"},
+									},
+									{
+										Merge: &CombinationGenerator{
+											Generators: []ApplicationSetGenerator{
+												{
+													Git: &GitGenerator{RepoURL: "https://diff-git-server.com/almonds"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					Template: ApplicationSetTemplate{
+						Spec: ApplicationSpec{
+							Source: &ApplicationSource{RepoURL: "https://diff-git-server.com/peanuts"},
+							Sources: []ApplicationSource{
+								{
+									RepoURL: "{{ .repoURL }}",
+								},
+								{
+									RepoURL: "ghcr.io/stefanprodan/charts",
+									Chart:   "podinfo",
+								},
+							},
+						},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/generators/0/git/repoURL",
+					"https://git-server.com/a-push-user/peanuts-3883081014",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/generators/1/matrix/generators/0/git/repoURL",
+					"https://git-server.com/a-push-user/cashews-580170494",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/generators/1/matrix/generators/1/merge/generators/0/git/repoURL",
+					"https://git-server.com/a-push-user/almonds-640159520",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/template/spec/source/repoURL",
+					"https://git-server.com/a-push-user/peanuts-3883081014",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/template/spec/sources/1/repoURL",
+					"127.0.0.1:31999/stefanprodan/charts",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should return internal server error on bad git URL",
+			admissionReq: createArgoAppSetAdmissionRequest(t, v1.Create, &ApplicationSet{
+				Spec: ApplicationSetSpec{
+					Generators: []ApplicationSetGenerator{
+						{
+							Git: &GitGenerator{RepoURL: "https://bad-url"},
+						},
+					},
+				},
+			}),
+			code:        http.StatusInternalServerError,
+			errContains: AgentErrTransformGitURL,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
@@ -52,7 +52,7 @@ func mutateRepositorySecret(ctx context.Context, r *v1.AdmissionRequest, cluster
 	isUpdate := r.Operation == v1.Update
 	var isPatched bool
 
-	state, err := cluster.LoadZarfState(ctx)
+	state, err := loadZarfState(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -4,7 +4,14 @@
 // Package hooks contains the mutation hooks for the Zarf agent.
 package hooks
 
-import "github.com/zarf-dev/zarf/src/internal/agent/operations"
+import (
+	"context"
+
+	"github.com/zarf-dev/zarf/src/internal/agent/metrics"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+)
 
 func getLabelPatch(currLabels map[string]string) operations.PatchOperation {
 	if currLabels == nil {
@@ -13,3 +20,14 @@ func getLabelPatch(currLabels map[string]string) operations.PatchOperation {
 	currLabels["zarf-agent"] = "patched"
 	return operations.ReplacePatchOperation("/metadata/labels", currLabels)
 }
+
+// loadZarfState loads the Zarf state from the state cache of the cluster, or from the cluster if it has none, and
+// records fetches from the cluster in the agent metrics.
+func loadZarfState(ctx context.Context, c *cluster.Cluster) (*types.ZarfState, error) {
+	if cache, ok := stateCaches.Load(c); ok {
+		return cache.(*StateCache).Load(ctx)
+	}
+	state, err := c.LoadZarfState(ctx)
+	metrics.ObserveStateFetch(err)
+	return state, err
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	v1 "k8s.io/api/admission/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// AgentConfigMapName is the name of the ConfigMap in the Zarf namespace holding the agent configuration.
+	AgentConfigMapName = "zarf-agent-config"
+	// AgentConfigDataKey is the key within the ConfigMap holding the agent configuration.
+	AgentConfigDataKey = "config.yaml"
+	// DefaultIgnoreAnnotation is the annotation that opts a resource out of the agent unless configured otherwise.
+	DefaultIgnoreAnnotation = "zarf.dev/agent"
+)
+
+// AgentConfig is the configuration of the agent that is reloaded while it is running.
+type AgentConfig struct {
+	// Namespaces whose resources are ignored by the agent, as names or glob patterns (e.g. team-*)
+	IgnoreNamespaces []string `json:"ignoreNamespaces,omitempty"`
+	// Label selectors of the resources ignored by the agent (e.g. app.kubernetes.io/managed-by=operator)
+	IgnoreLabelSelectors []string `json:"ignoreLabelSelectors,omitempty"`
+	// Annotation that ignores a resource when it is set to skip or ignore
+	IgnoreAnnotation string `json:"ignoreAnnotation,omitempty"`
+	// Report the changes the agent would make in its audit log without applying them
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// AgentConfigStore holds the latest agent configuration loaded from the cluster.
+type AgentConfigStore struct {
+	mu        sync.RWMutex
+	config    AgentConfig
+	selectors []labels.Selector
+}
+
+// NewAgentConfigStore returns a store with the default agent configuration.
+func NewAgentConfigStore() *AgentConfigStore {
+	return &AgentConfigStore{config: AgentConfig{IgnoreAnnotation: DefaultIgnoreAnnotation}}
+}
+
+// Config returns the current agent configuration.
+func (s *AgentConfigStore) Config() AgentConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// Reload loads the agent configuration from the cluster, keeping the current configuration if it is invalid.
+func (s *AgentConfigStore) Reload(ctx context.Context, c *cluster.Cluster) error {
+	config := AgentConfig{}
+	cm, err := c.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Get(ctx, AgentConfigMapName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal([]byte(cm.Data[AgentConfigDataKey]), &config); err != nil {
+			return fmt.Errorf("unable to parse the agent configuration in %s: %w", AgentConfigMapName, err)
+		}
+	}
+	if config.IgnoreAnnotation == "" {
+		config.IgnoreAnnotation = DefaultIgnoreAnnotation
+	}
+
+	selectors := []labels.Selector{}
+	for _, selector := range config.IgnoreLabelSelectors {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return fmt.Errorf("invalid label selector %q in %s: %w", selector, AgentConfigMapName, err)
+		}
+		selectors = append(selectors, parsed)
+	}
+	for _, namespace := range config.IgnoreNamespaces {
+		if _, err := path.Match(namespace, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q in %s: %w", namespace, AgentConfigMapName, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.Equal(s.config.IgnoreNamespaces, config.IgnoreNamespaces) ||
+		!slices.Equal(s.config.IgnoreLabelSelectors, config.IgnoreLabelSelectors) ||
+		s.config.IgnoreAnnotation != config.IgnoreAnnotation ||
+		s.config.DryRun != config.DryRun {
+		message.Infof("Loaded the agent configuration from %s", AgentConfigMapName)
+	}
+	s.config = config
+	s.selectors = selectors
+	return nil
+}
+
+// Ignored returns whether the resource in the admission request is ignored by the agent configuration.
+func (s *AgentConfigStore) Ignored(r *v1.AdmissionRequest) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, namespace := range s.config.IgnoreNamespaces {
+		if ok, _ := path.Match(namespace, r.Namespace); ok {
+			return true, nil
+		}
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(r.Object.Raw, &obj.Object); err != nil {
+		return false, fmt.Errorf(lang.ErrUnmarshal, err)
+	}
+	switch obj.GetAnnotations()[s.config.IgnoreAnnotation] {
+	case "skip", "ignore":
+		return true, nil
+	}
+	objLabels := labels.Set(obj.GetLabels())
+	for _, selector := range s.selectors {
+		if selector.Matches(objLabels) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Wrap returns a hook that allows the resources ignored by the agent configuration without changing them and passes
+// every other resource to the given hook, whose patches are only reported when the agent is in dry run mode.
+func (s *AgentConfigStore) Wrap(hook operations.Hook) operations.Hook {
+	wrap := func(fn operations.AdmitFunc) operations.AdmitFunc {
+		if fn == nil {
+			return nil
+		}
+		return func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			ignored, err := s.Ignored(r)
+			if err != nil {
+				return nil, err
+			}
+			if ignored {
+				return &operations.Result{Allowed: true}, nil
+			}
+			result, err := fn(r)
+			if err != nil || result == nil {
+				return result, err
+			}
+			result.DryRun = s.Config().DryRun
+			return result, nil
+		}
+	}
+	return operations.Hook{
+		Create:  wrap(hook.Create),
+		Delete:  wrap(hook.Delete),
+		Update:  wrap(hook.Update),
+		Connect: wrap(hook.Connect),
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/internal/agent/audit"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestAgentConfigStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := createTestClientWithZarfState(ctx, t, &types.ZarfState{})
+	store := NewAgentConfigStore()
+
+	// the defaults are used until a configuration is created
+	require.NoError(t, store.Reload(ctx, c))
+	require.Equal(t, AgentConfig{IgnoreAnnotation: DefaultIgnoreAnnotation}, store.Config())
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: AgentConfigMapName, Namespace: cluster.ZarfNamespaceName},
+		Data: map[string]string{
+			AgentConfigDataKey: `
+ignoreNamespaces:
+  - team-*
+ignoreLabelSelectors:
+  - app.kubernetes.io/managed-by in (operator)
+ignoreAnnotation: example.com/agent
+`,
+		},
+	}
+	cm, err := c.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Create(ctx, cm, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, store.Reload(ctx, c))
+
+	calls := 0
+	hook := store.Wrap(operations.Hook{
+		Create: func(_ *v1.AdmissionRequest) (*operations.Result, error) {
+			calls++
+			return &operations.Result{Allowed: true, Msg: "mutated"}, nil
+		},
+	})
+	require.Nil(t, hook.Update)
+
+	tests := []struct {
+		name        string
+		namespace   string
+		meta        metav1.ObjectMeta
+		expectedMsg string
+	}{
+		{
+			name:        "not ignored",
+			namespace:   "default",
+			meta:        metav1.ObjectMeta{Labels: map[string]string{"app.kubernetes.io/managed-by": "helm"}},
+			expectedMsg: "mutated",
+		},
+		{
+			name:      "ignored namespace",
+			namespace: "team-a",
+		},
+		{
+			name:      "ignored labels",
+			namespace: "default",
+			meta:      metav1.ObjectMeta{Labels: map[string]string{"app.kubernetes.io/managed-by": "operator"}},
+		},
+		{
+			name:      "ignored annotation",
+			namespace: "default",
+			meta:      metav1.ObjectMeta{Annotations: map[string]string{"example.com/agent": "skip"}},
+		},
+		{
+			name:        "annotation that is no longer configured",
+			namespace:   "default",
+			meta:        metav1.ObjectMeta{Annotations: map[string]string{DefaultIgnoreAnnotation: "ignore"}},
+			expectedMsg: "mutated",
+		},
+	}
+	for _, tt := range tests {
+		raw, err := json.Marshal(map[string]any{"metadata": tt.meta})
+		require.NoError(t, err)
+		result, err := hook.Execute(&v1.AdmissionRequest{
+			Operation: v1.Create,
+			Namespace: tt.namespace,
+			Object:    runtime.RawExtension{Raw: raw},
+		})
+		require.NoError(t, err, tt.name)
+		require.True(t, result.Allowed, tt.name)
+		require.Equal(t, tt.expectedMsg, result.Msg, tt.name)
+	}
+	require.Equal(t, 2, calls)
+
+	// an invalid configuration keeps the current one
+	cm.Data[AgentConfigDataKey] = "ignoreLabelSelectors: ['app in (']"
+	_, err = c.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.ErrorContains(t, store.Reload(ctx, c), "invalid label selector")
+	require.Equal(t, []string{"team-*"}, store.Config().IgnoreNamespaces)
+}
+
+func TestAgentConfigDryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := &types.ZarfState{RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"}}
+	c := createTestClientWithZarfState(ctx, t, state)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: AgentConfigMapName, Namespace: cluster.ZarfNamespaceName},
+		Data:       map[string]string{AgentConfigDataKey: "dryRun: true"},
+	}
+	_, err := c.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Create(ctx, cm, metav1.CreateOptions{})
+	require.NoError(t, err)
+	store := NewAgentConfigStore()
+	require.NoError(t, store.Reload(ctx, c))
+
+	auditLog := &bytes.Buffer{}
+	handler := admission.NewHandler().WithAuditLog(audit.NewLogger(auditLog)).Serve(store.Wrap(NewPodMutationHook(ctx, c)))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}},
+	}
+	rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Create, pod), handler)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var review v1.AdmissionReview
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&review))
+	require.True(t, review.Response.Allowed)
+	require.Empty(t, review.Response.Patch)
+	require.Nil(t, review.Response.PatchType)
+
+	var record audit.Record
+	require.NoError(t, json.Unmarshal(auditLog.Bytes(), &record))
+	require.True(t, record.DryRun)
+	require.Equal(t, []audit.Change{{
+		Path:     "/spec/containers/0/image",
+		Original: "nginx",
+		Mutated:  "127.0.0.1:31999/library/nginx:latest-zarf-3793515731",
+	}}, record.Changes)
+}
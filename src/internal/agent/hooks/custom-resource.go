@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	v1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ImageRulesConfigMapName is the name of the ConfigMap in the Zarf namespace holding the custom resource image rules.
+	ImageRulesConfigMapName = "zarf-agent-image-rules"
+	// ImageRulesDataKey is the key within the ConfigMap holding the custom resource image rules.
+	ImageRulesDataKey = "rules.yaml"
+	// AgentWebhookConfigName is the name of the MutatingWebhookConfiguration of the Zarf agent.
+	AgentWebhookConfigName = "zarf"
+	// CustomResourceWebhookName is the name of the webhook mutating the custom resources matched by the image rules.
+	CustomResourceWebhookName = "agent-custom-resource.zarf.dev"
+)
+
+// ImageRule tells the agent where the images are referenced within a custom resource.
+type ImageRule struct {
+	// The API group of the custom resource (e.g. tekton.dev)
+	Group string `json:"group"`
+	// The plural resource name of the custom resource (e.g. taskruns)
+	Resource string `json:"resource"`
+	// JSONPath expressions of the image references within the custom resource (e.g. .spec.steps[*].image)
+	Paths []string `json:"paths"`
+}
+
+var (
+	imagePathSegment = regexp.MustCompile(`^([^\[\]]*)((?:\[(?:\*|\d+)\])*)$`)
+	imagePathIndex   = regexp.MustCompile(`\[(\*|\d+)\]`)
+)
+
+// imagePathToken is a field of an object or an element of a list (all of them when index is -1) in an image path.
+type imagePathToken struct {
+	field string
+	index int
+	list  bool
+}
+
+// parseImagePath parses a JSONPath expression made of fields, list indexes and [*] wildcards.
+func parseImagePath(path string) ([]imagePathToken, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}"), "$")
+	trimmed = strings.TrimPrefix(trimmed, ".")
+	if trimmed == "" {
+		return nil, fmt.Errorf("image path %q is empty", path)
+	}
+	tokens := []imagePathToken{}
+	for _, segment := range strings.Split(trimmed, ".") {
+		match := imagePathSegment.FindStringSubmatch(segment)
+		if match == nil || (match[1] == "" && match[2] == "") {
+			return nil, fmt.Errorf("image path %q is invalid, only fields, list indexes and [*] are supported", path)
+		}
+		if match[1] != "" {
+			tokens = append(tokens, imagePathToken{field: match[1]})
+		}
+		for _, index := range imagePathIndex.FindAllStringSubmatch(match[2], -1) {
+			if index[1] == "*" {
+				tokens = append(tokens, imagePathToken{index: -1, list: true})
+				continue
+			}
+			i, err := strconv.Atoi(index[1])
+			if err != nil {
+				return nil, fmt.Errorf("image path %q has an invalid index %q", path, index[1])
+			}
+			tokens = append(tokens, imagePathToken{index: i, list: true})
+		}
+	}
+	return tokens, nil
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// findImages returns the image references at the given path within obj keyed by their JSON pointer.
+func findImages(obj any, pointer string, tokens []imagePathToken, found map[string]string) {
+	walkImagePath(obj, pointer, tokens, func(pointer string, value any) {
+		if image, ok := value.(string); ok && image != "" {
+			found[pointer] = image
+		}
+	})
+}
+
+// walkImagePath calls fn with every value at the given path within obj and its JSON pointer.
+func walkImagePath(obj any, pointer string, tokens []imagePathToken, fn func(pointer string, value any)) {
+	if len(tokens) == 0 {
+		fn(pointer, obj)
+		return
+	}
+	token := tokens[0]
+	if !token.list {
+		fields, ok := obj.(map[string]any)
+		if !ok {
+			return
+		}
+		if child, ok := fields[token.field]; ok {
+			walkImagePath(child, pointer+"/"+jsonPointerEscaper.Replace(token.field), tokens[1:], fn)
+		}
+		return
+	}
+	elements, ok := obj.([]any)
+	if !ok {
+		return
+	}
+	for i, child := range elements {
+		if token.index == -1 || token.index == i {
+			walkImagePath(child, fmt.Sprintf("%s/%d", pointer, i), tokens[1:], fn)
+		}
+	}
+}
+
+// LoadImageRules returns the custom resource image rules configured in the cluster, if any.
+func LoadImageRules(ctx context.Context, c *cluster.Cluster) ([]ImageRule, error) {
+	cm, err := c.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Get(ctx, ImageRulesConfigMapName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rules := []ImageRule{}
+	if err := yaml.Unmarshal([]byte(cm.Data[ImageRulesDataKey]), &rules); err != nil {
+		return nil, fmt.Errorf("unable to parse the image rules in %s: %w", ImageRulesConfigMapName, err)
+	}
+	for _, rule := range rules {
+		if rule.Resource == "" {
+			return nil, fmt.Errorf("image rule for group %q in %s must have a resource", rule.Group, ImageRulesConfigMapName)
+		}
+		for _, path := range rule.Paths {
+			if _, err := parseImagePath(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rules, nil
+}
+
+// SyncCustomResourceWebhook updates the custom resource webhook of the agent to match the resources in the image rules.
+func SyncCustomResourceWebhook(ctx context.Context, c *cluster.Cluster) error {
+	rules, err := LoadImageRules(ctx, c)
+	if err != nil {
+		return err
+	}
+	webhookRules := []admissionregistrationv1.RuleWithOperations{}
+	for _, rule := range rules {
+		webhookRules = append(webhookRules, admissionregistrationv1.RuleWithOperations{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{rule.Group},
+				APIVersions: []string{"*"},
+				Resources:   []string{rule.Resource},
+			},
+		})
+	}
+
+	webhookConfigs := c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	webhookConfig, err := webhookConfigs.Get(ctx, AgentWebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	for i, webhook := range webhookConfig.Webhooks {
+		if webhook.Name != CustomResourceWebhookName {
+			continue
+		}
+		if equality.Semantic.DeepEqual(webhook.Rules, webhookRules) {
+			return nil
+		}
+		webhookConfig.Webhooks[i].Rules = webhookRules
+		_, err = webhookConfigs.Update(ctx, webhookConfig, metav1.UpdateOptions{})
+		return err
+	}
+	return fmt.Errorf("webhook %s not found in %s", CustomResourceWebhookName, AgentWebhookConfigName)
+}
+
+// NewCustomResourceMutationHook creates a new instance of the custom resource mutation hook.
+func NewCustomResourceMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateCustomResource(ctx, r, cluster)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateCustomResource(ctx, r, cluster)
+		},
+	}
+}
+
+// mutateCustomResource mutates the image references that the image rules locate in a custom resource to point to the
+// registry defined in the ZarfState.
+func mutateCustomResource(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+	src := &unstructured.Unstructured{}
+	if err := json.Unmarshal(r.Object.Raw, &src.Object); err != nil {
+		return nil, fmt.Errorf(lang.ErrUnmarshal, err)
+	}
+
+	labels := src.GetLabels()
+	if labels != nil && labels["zarf-agent"] == "patched" {
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: []operations.PatchOperation{},
+		}, nil
+	}
+
+	rules, err := LoadImageRules(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]string{}
+	for _, rule := range rules {
+		if rule.Group != r.Resource.Group || rule.Resource != r.Resource.Resource {
+			continue
+		}
+		for _, path := range rule.Paths {
+			tokens, err := parseImagePath(path)
+			if err != nil {
+				return nil, err
+			}
+			findImages(src.Object, "", tokens, found)
+		}
+	}
+	if len(found) == 0 {
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: []operations.PatchOperation{},
+		}, nil
+	}
+
+	state, err := loadZarfState(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	registryURL := state.RegistryInfo.Address
+
+	patches, err := imagePatches(registryURL, found)
+	if err != nil {
+		return nil, err
+	}
+	patches = append(patches, getLabelPatch(labels))
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
+
+// imagePatches returns the patches pointing the images keyed by their JSON pointer to the registry, in pointer order.
+func imagePatches(registryURL string, found map[string]string) ([]operations.PatchOperation, error) {
+	pointers := make([]string, 0, len(found))
+	for pointer := range found {
+		pointers = append(pointers, pointer)
+	}
+	slices.Sort(pointers)
+
+	patches := []operations.PatchOperation{}
+	for _, pointer := range pointers {
+		replacement, err := transform.ImageTransformHost(registryURL, found[pointer])
+		if err != nil {
+			return nil, fmt.Errorf("unable to transform the image at %s: %w", pointer, err)
+		}
+		message.Debugf("original image of (%s) at %s got mutated to (%s)", found[pointer], pointer, replacement)
+		patches = append(patches, operations.ReplacePatchOperation(pointer, replacement))
+	}
+	return patches, nil
+}
@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const testImageRules = `
+- group: tekton.dev
+  resource: taskruns
+  paths:
+    - .spec.taskSpec.steps[*].image
+    - $.spec.taskSpec.sidecars[0].image
+- group: kubevirt.io
+  resource: virtualmachines
+  paths:
+    - spec.template.spec.volumes[*].containerDisk.image
+`
+
+func createCustomResourceAdmissionRequest(t *testing.T, op v1.Operation, group, resource string, obj map[string]any) *v1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+	return &v1.AdmissionRequest{
+		Operation: op,
+		Resource:  metav1.GroupVersionResource{Group: group, Version: "v1", Resource: resource},
+		Object: runtime.RawExtension{
+			Raw: raw,
+		},
+	}
+}
+
+func createImageRulesConfigMap(ctx context.Context, t *testing.T, c *cluster.Cluster, rules string) {
+	t.Helper()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ImageRulesConfigMapName,
+			Namespace: cluster.ZarfNamespaceName,
+		},
+		Data: map[string]string{
+			ImageRulesDataKey: rules,
+		},
+	}
+	_, err := c.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Create(ctx, cm, metav1.CreateOptions{})
+	require.NoError(t, err)
+}
+
+func TestCustomResourceMutationWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := &types.ZarfState{RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"}}
+
+	taskRun := map[string]any{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "TaskRun",
+		"metadata":   map[string]any{"name": "build"},
+		"spec": map[string]any{
+			"taskSpec": map[string]any{
+				"steps": []any{
+					map[string]any{"name": "clone", "image": "alpine/git:2.45.2"},
+					map[string]any{"name": "build", "image": "gcr.io/kaniko-project/executor@sha256:1d3f4d4e4b6e0e7b3f2c3b5e6a8f4c8e2d1f7a9b0c3e5d7f9a1b3c5d7e9f1a3b"},
+				},
+				"sidecars": []any{
+					map[string]any{"name": "docker", "image": "docker:dind"},
+					map[string]any{"name": "other", "image": "busybox"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		admissionTest
+		rules string
+	}{
+		{
+			admissionTest: admissionTest{
+				name:         "should not mutate without image rules",
+				admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "taskruns", taskRun),
+				code:         http.StatusOK,
+			},
+		},
+		{
+			admissionTest: admissionTest{
+				name:         "should not mutate resources without a rule",
+				admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "pipelineruns", taskRun),
+				code:         http.StatusOK,
+			},
+			rules: testImageRules,
+		},
+		{
+			admissionTest: admissionTest{
+				name: "should not mutate when agent patched",
+				admissionReq: createCustomResourceAdmissionRequest(t, v1.Update, "tekton.dev", "taskruns", map[string]any{
+					"metadata": map[string]any{"name": "build", "labels": map[string]any{"zarf-agent": "patched"}},
+					"spec":     taskRun["spec"],
+				}),
+				code: http.StatusOK,
+			},
+			rules: testImageRules,
+		},
+		{
+			admissionTest: admissionTest{
+				name:         "should mutate the images matched by the rules",
+				admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "taskruns", taskRun),
+				patch: []operations.PatchOperation{
+					operations.ReplacePatchOperation(
+						"/spec/taskSpec/sidecars/0/image",
+						"127.0.0.1:31999/library/docker:dind-zarf-1958758067",
+					),
+					operations.ReplacePatchOperation(
+						"/spec/taskSpec/steps/0/image",
+						"127.0.0.1:31999/alpine/git:2.45.2-zarf-2739568766",
+					),
+					operations.ReplacePatchOperation(
+						"/spec/taskSpec/steps/1/image",
+						"127.0.0.1:31999/kaniko-project/executor@sha256:1d3f4d4e4b6e0e7b3f2c3b5e6a8f4c8e2d1f7a9b0c3e5d7f9a1b3c5d7e9f1a3b",
+					),
+					operations.ReplacePatchOperation(
+						"/metadata/labels",
+						map[string]string{
+							"zarf-agent": "patched",
+						},
+					),
+				},
+				code: http.StatusOK,
+			},
+			rules: testImageRules,
+		},
+		{
+			admissionTest: admissionTest{
+				name:         "error on invalid rules",
+				admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "taskruns", taskRun),
+				errContains:  `image path "spec.steps[first].image" is invalid`,
+				code:         http.StatusInternalServerError,
+			},
+			rules: "- group: tekton.dev\n  resource: taskruns\n  paths: [\"spec.steps[first].image\"]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := createTestClientWithZarfState(ctx, t, state)
+			if tt.rules != "" {
+				createImageRulesConfigMap(ctx, t, c, tt.rules)
+			}
+			handler := admission.NewHandler().Serve(NewCustomResourceMutationHook(ctx, c))
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt.admissionTest)
+		})
+	}
+}
+
+func TestSyncCustomResourceWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := createTestClientWithZarfState(ctx, t, &types.ZarfState{})
+
+	err := SyncCustomResourceWebhook(ctx, c)
+	require.Error(t, err)
+
+	webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: AgentWebhookConfigName},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{Name: "agent-pod.zarf.dev"},
+			{Name: CustomResourceWebhookName},
+		},
+	}
+	_, err = c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(ctx, webhookConfig, metav1.CreateOptions{})
+	require.NoError(t, err)
+	createImageRulesConfigMap(ctx, t, c, testImageRules)
+
+	require.NoError(t, SyncCustomResourceWebhook(ctx, c))
+	webhookConfig, err = c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, AgentWebhookConfigName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, webhookConfig.Webhooks[0].Rules)
+	ops := []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update}
+	expected := []admissionregistrationv1.RuleWithOperations{
+		{
+			Operations: ops,
+			Rule:       admissionregistrationv1.Rule{APIGroups: []string{"tekton.dev"}, APIVersions: []string{"*"}, Resources: []string{"taskruns"}},
+		},
+		{
+			Operations: ops,
+			Rule:       admissionregistrationv1.Rule{APIGroups: []string{"kubevirt.io"}, APIVersions: []string{"*"}, Resources: []string{"virtualmachines"}},
+		},
+	}
+	require.Equal(t, expected, webhookConfig.Webhooks[1].Rules)
+}
@@ -46,7 +46,7 @@ func mutateGitRepo(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster
 		isUpdate = r.Operation == v1.Update
 	)
 
-	state, err := cluster.LoadZarfState(ctx)
+	state, err := loadZarfState(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
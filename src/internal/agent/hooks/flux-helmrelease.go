@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// NewHelmReleaseMutationHook creates a new instance of the helm release mutation hook.
+func NewHelmReleaseMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateHelmRelease(ctx, r, cluster)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateHelmRelease(ctx, r, cluster)
+		},
+	}
+}
+
+// mutateHelmRelease adds a kustomize post renderer to a helm release that points the images of the deployed Zarf
+// packages to the registry defined in the ZarfState.
+func mutateHelmRelease(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+	src := &unstructured.Unstructured{}
+	if err := json.Unmarshal(r.Object.Raw, &src.Object); err != nil {
+		return nil, fmt.Errorf(lang.ErrUnmarshal, err)
+	}
+
+	labels := src.GetLabels()
+	if labels != nil && labels["zarf-agent"] == "patched" {
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: []operations.PatchOperation{},
+		}, nil
+	}
+
+	state, err := loadZarfState(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	deployedPackages, err := cluster.GetDeployedZarfPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	images, err := helmReleaseImageOverrides(state.RegistryInfo.Address, deployedPackages)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: []operations.PatchOperation{},
+		}, nil
+	}
+	postRenderer := map[string]any{"kustomize": map[string]any{"images": images}}
+
+	var patches []operations.PatchOperation
+	// The post renderer is added last so that it also rewrites images set by the post renderers of the release
+	if _, ok, _ := unstructured.NestedSlice(src.Object, "spec", "postRenderers"); ok {
+		patches = append(patches, operations.AddPatchOperation("/spec/postRenderers/-", postRenderer))
+	} else {
+		patches = append(patches, operations.AddPatchOperation("/spec/postRenderers", []any{postRenderer}))
+	}
+	patches = append(patches, getLabelPatch(labels))
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
+
+// helmReleaseImageOverrides returns the kustomize image overrides pointing the images of the deployed packages to the
+// registry, both as they are written in the package and with their full name.
+func helmReleaseImageOverrides(registryURL string, deployedPackages []types.DeployedPackage) ([]map[string]string, error) {
+	newNames := map[string]string{}
+	for _, deployedPackage := range deployedPackages {
+		for _, component := range deployedPackage.Data.Components {
+			for _, image := range component.Images {
+				_, image = transform.SplitImageRuntime(image)
+				ref, err := transform.ParseImageRef(image)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse the image %q of the %s package: %w", image, deployedPackage.Name, err)
+				}
+				// Images that are already in the registry do not need to be overridden
+				if ref.Host == registryURL {
+					continue
+				}
+				newName := fmt.Sprintf("%s/%s", registryURL, ref.Path)
+				newNames[imageName(image)] = newName
+				newNames[ref.Name] = newName
+			}
+		}
+	}
+
+	names := make([]string, 0, len(newNames))
+	for name := range newNames {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	images := []map[string]string{}
+	for _, name := range names {
+		images = append(images, map[string]string{"name": name, "newName": newNames[name]})
+	}
+	return images, nil
+}
+
+// imageName returns the image reference as it is written without its tag or digest.
+func imageName(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHelmReleaseMutationWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := &types.ZarfState{RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"}}
+	c := createTestClientWithZarfState(ctx, t, state)
+
+	deployedPackage := types.DeployedPackage{
+		Name: "podinfo",
+		Data: v1alpha1.ZarfPackage{
+			Components: []v1alpha1.ZarfComponent{
+				{Name: "podinfo", Images: []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "nginx:1.27@sha256:0b7a4c5e4b6e0e7b3f2c3b5e6a8f4c8e2d1f7a9b0c3e5d7f9a1b3c5d7e9f1a3b"}},
+				{Name: "seed", Images: []string{"127.0.0.1:31999/library/registry:2.8.3"}},
+			},
+		},
+	}
+	data, err := json.Marshal(deployedPackage)
+	require.NoError(t, err)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.ZarfPackagePrefix + deployedPackage.Name,
+			Namespace: cluster.ZarfNamespaceName,
+			Labels:    map[string]string{cluster.ZarfPackageInfoLabel: deployedPackage.Name},
+		},
+		Data: map[string][]byte{"data": data},
+	}
+	_, err = c.Clientset.CoreV1().Secrets(cluster.ZarfNamespaceName).Create(ctx, secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	handler := admission.NewHandler().Serve(NewHelmReleaseMutationHook(ctx, c))
+
+	postRenderer := map[string]any{
+		"kustomize": map[string]any{
+			"images": []map[string]string{
+				{"name": "docker.io/library/nginx", "newName": "127.0.0.1:31999/library/nginx"},
+				{"name": "ghcr.io/stefanprodan/podinfo", "newName": "127.0.0.1:31999/stefanprodan/podinfo"},
+				{"name": "nginx", "newName": "127.0.0.1:31999/library/nginx"},
+			},
+		},
+	}
+	spec := map[string]any{
+		"chart": map[string]any{
+			"spec": map[string]any{
+				"chart":     "podinfo",
+				"sourceRef": map[string]any{"kind": "HelmRepository", "name": "podinfo"},
+			},
+		},
+	}
+
+	tests := []admissionTest{
+		{
+			name: "should add the post renderer",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "helm.toolkit.fluxcd.io", "helmreleases", map[string]any{
+				"metadata": map[string]any{"name": "podinfo"},
+				"spec":     spec,
+			}),
+			patch: []operations.PatchOperation{
+				operations.AddPatchOperation("/spec/postRenderers", []any{postRenderer}),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should append the post renderer to the existing ones",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "helm.toolkit.fluxcd.io", "helmreleases", map[string]any{
+				"metadata": map[string]any{"name": "podinfo", "labels": map[string]any{"app": "podinfo"}},
+				"spec": map[string]any{
+					"chart": spec["chart"],
+					"postRenderers": []any{
+						map[string]any{"kustomize": map[string]any{"images": []any{map[string]any{"name": "nginx", "newTag": "1.27.1"}}}},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.AddPatchOperation("/spec/postRenderers/-", postRenderer),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"app":        "podinfo",
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should not mutate when agent patched",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Update, "helm.toolkit.fluxcd.io", "helmreleases", map[string]any{
+				"metadata": map[string]any{"name": "podinfo", "labels": map[string]any{"zarf-agent": "patched"}},
+				"spec":     spec,
+			}),
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
@@ -54,7 +54,7 @@ func mutateHelmRepo(ctx context.Context, r *v1.AdmissionRequest, cluster *cluste
 		}, nil
 	}
 
-	zarfState, err := cluster.LoadZarfState(ctx)
+	zarfState, err := loadZarfState(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
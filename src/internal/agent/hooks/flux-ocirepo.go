@@ -57,7 +57,7 @@ func mutateOCIRepo(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster
 		}, nil
 	}
 
-	zarfState, err := cluster.LoadZarfState(ctx)
+	zarfState, err := loadZarfState(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	v1 "k8s.io/api/admission/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ImagePolicyConfigMapName is the name of the ConfigMap in the Zarf namespace holding the image policy.
+	ImagePolicyConfigMapName = "zarf-agent-image-policy"
+	// ImagePolicyModeKey is the key within the ConfigMap holding the image policy mode.
+	ImagePolicyModeKey = "mode"
+	// ImagePolicyAllowlistKey is the key within the ConfigMap holding the comma separated registries allowed besides the
+	// Zarf registry.
+	ImagePolicyAllowlistKey = "allowlist"
+)
+
+// ImagePolicyMode is how the agent treats pods referencing images outside of the allowed registries.
+type ImagePolicyMode string
+
+// The image policy modes.
+const (
+	// ImagePolicyOff allows every pod.
+	ImagePolicyOff ImagePolicyMode = "off"
+	// ImagePolicyAudit allows every pod but warns about images outside of the allowed registries.
+	ImagePolicyAudit ImagePolicyMode = "audit"
+	// ImagePolicyEnforce rejects pods referencing images outside of the allowed registries.
+	ImagePolicyEnforce ImagePolicyMode = "enforce"
+)
+
+// ImagePolicy is the air gap image policy that the agent validates pods against.
+type ImagePolicy struct {
+	Mode ImagePolicyMode
+	// Registries or repository prefixes allowed besides the Zarf registry (e.g. ghcr.io or ghcr.io/my-org)
+	Allowlist []string
+}
+
+// LoadImagePolicy returns the image policy configured in the cluster, the policy is off if it is not configured.
+func LoadImagePolicy(ctx context.Context, c *cluster.Cluster) (ImagePolicy, error) {
+	policy := ImagePolicy{Mode: ImagePolicyOff}
+	cm, err := c.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Get(ctx, ImagePolicyConfigMapName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return policy, nil
+	}
+	if err != nil {
+		return policy, err
+	}
+
+	switch mode := ImagePolicyMode(strings.ToLower(strings.TrimSpace(cm.Data[ImagePolicyModeKey]))); mode {
+	case "", ImagePolicyOff:
+	case ImagePolicyAudit, ImagePolicyEnforce:
+		policy.Mode = mode
+	default:
+		return policy, fmt.Errorf("invalid image policy mode %q in %s, expected %s, %s or %s",
+			mode, ImagePolicyConfigMapName, ImagePolicyOff, ImagePolicyAudit, ImagePolicyEnforce)
+	}
+	for _, registry := range strings.Split(cm.Data[ImagePolicyAllowlistKey], ",") {
+		registry = strings.TrimSuffix(strings.TrimSpace(registry), "/")
+		if registry != "" {
+			policy.Allowlist = append(policy.Allowlist, registry)
+		}
+	}
+	return policy, nil
+}
+
+// allowed returns whether the image is in the Zarf registry or in one of the allowed registries.
+func (p ImagePolicy) allowed(registryURL, image string) (bool, error) {
+	ref, err := transform.ParseImageRef(image)
+	if err != nil {
+		return false, err
+	}
+	for _, registry := range append([]string{registryURL}, p.Allowlist...) {
+		if ref.Name == registry || strings.HasPrefix(ref.Name, registry+"/") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NewPodValidationHook creates a new instance of the pods image policy validation hook.
+func NewPodValidationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return validatePod(ctx, r, cluster)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return validatePod(ctx, r, cluster)
+		},
+	}
+}
+
+// validatePod checks the images of a pod against the image policy once it has been mutated.
+func validatePod(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+	policy, err := LoadImagePolicy(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Mode == ImagePolicyOff {
+		return &operations.Result{Allowed: true}, nil
+	}
+
+	pod, err := parsePod(r.Object.Raw)
+	if err != nil {
+		return nil, fmt.Errorf(lang.AgentErrParsePod, err)
+	}
+
+	state, err := loadZarfState(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	registryURL := state.RegistryInfo.Address
+
+	images := []string{}
+	for _, container := range pod.Spec.InitContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range pod.Spec.Containers {
+		images = append(images, container.Image)
+	}
+
+	violations := []string{}
+	for _, image := range images {
+		ok, err := policy.allowed(registryURL, image)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the image %q: %w", image, err)
+		}
+		if !ok {
+			violations = append(violations, fmt.Sprintf(lang.AgentWarnImagePolicy, image, registryURL))
+		}
+	}
+	if len(violations) == 0 {
+		return &operations.Result{Allowed: true}, nil
+	}
+
+	if policy.Mode == ImagePolicyAudit {
+		for _, violation := range violations {
+			message.Warnf("pod %s/%s: %s", r.Namespace, pod.Name, violation)
+		}
+		return &operations.Result{Allowed: true, Warnings: violations}, nil
+	}
+	return &operations.Result{
+		Allowed: false,
+		Msg:     strings.Join(violations, ", "),
+	}, nil
+}
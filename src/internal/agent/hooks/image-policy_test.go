@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodValidationWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := &types.ZarfState{RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"}}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init", Image: "127.0.0.1:31999/library/busybox:1.36-zarf-123"}},
+			Containers: []corev1.Container{
+				{Name: "app", Image: "ghcr.io/my-org/app:1.0.0"},
+				{Name: "sidecar", Image: "nginx"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		policy          map[string]string
+		allowed         bool
+		expectedMsg     string
+		expectedWarning []string
+		expectedErr     string
+	}{
+		{
+			name:    "allowed without a policy",
+			allowed: true,
+		},
+		{
+			name:    "allowed when the policy is off",
+			policy:  map[string]string{ImagePolicyModeKey: "off", ImagePolicyAllowlistKey: ""},
+			allowed: true,
+		},
+		{
+			name:            "warns in audit mode",
+			policy:          map[string]string{ImagePolicyModeKey: "audit", ImagePolicyAllowlistKey: "ghcr.io/my-org"},
+			allowed:         true,
+			expectedWarning: []string{`image "nginx" is not in the Zarf registry (127.0.0.1:31999) or an allowed registry`},
+		},
+		{
+			name:        "rejects in enforce mode",
+			policy:      map[string]string{ImagePolicyModeKey: "enforce", ImagePolicyAllowlistKey: "ghcr.io/other-org, docker.io/library/"},
+			allowed:     false,
+			expectedMsg: `image "ghcr.io/my-org/app:1.0.0" is not in the Zarf registry (127.0.0.1:31999) or an allowed registry`,
+		},
+		{
+			name:    "allowed in enforce mode with an allowlist",
+			policy:  map[string]string{ImagePolicyModeKey: "Enforce", ImagePolicyAllowlistKey: "ghcr.io,docker.io/library"},
+			allowed: true,
+		},
+		{
+			name:        "error on an invalid mode",
+			policy:      map[string]string{ImagePolicyModeKey: "strict"},
+			expectedErr: `invalid image policy mode "strict"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := createTestClientWithZarfState(ctx, t, state)
+			if tt.policy != nil {
+				cm := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: ImagePolicyConfigMapName, Namespace: cluster.ZarfNamespaceName},
+					Data:       tt.policy,
+				}
+				_, err := c.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Create(ctx, cm, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			handler := admission.NewHandler().Serve(NewPodValidationHook(ctx, c))
+			rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Create, pod), handler)
+
+			var review v1.AdmissionReview
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&review))
+			if tt.expectedErr != "" {
+				require.Equal(t, http.StatusInternalServerError, rr.Code)
+				require.Contains(t, review.Response.Result.Message, tt.expectedErr)
+				return
+			}
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Equal(t, tt.allowed, review.Response.Allowed)
+			require.Equal(t, tt.expectedMsg, review.Response.Result.Message)
+			require.Equal(t, tt.expectedWarning, review.Response.Warnings)
+			require.Empty(t, review.Response.Patch)
+		})
+	}
+}
@@ -59,7 +59,7 @@ func mutatePod(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Clu
 		}, nil
 	}
 
-	state, err := cluster.LoadZarfState(ctx)
+	state, err := loadZarfState(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
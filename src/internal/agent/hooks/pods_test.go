@@ -13,11 +13,13 @@ import (
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
 	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/types"
 	v1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func createPodAdmissionRequest(t *testing.T, op v1.Operation, pod *corev1.Pod) *v1.AdmissionRequest {
@@ -154,3 +156,55 @@ func TestPodMutationWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestPodMutationWebhookEncryptedState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// The agent decrypts the state with the key secret in the Zarf namespace, which its role can read.
+	c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+	state := &types.ZarfState{
+		RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999", PullUsername: "zarf-pull", PullPassword: "pull-password"},
+		Encryption:   &types.StateEncryption{KeyURI: "k8s://zarf/zarf-state-key"},
+	}
+	require.NoError(t, cluster.ValidateAgentStateEncryptionKey(state.Encryption.KeyURI))
+	require.NoError(t, c.SaveZarfState(ctx, state))
+	handler := admission.NewHandler().Serve(NewPodMutationHook(ctx, c))
+
+	admissionReq := createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+		},
+	})
+	rr := sendAdmissionRequest(t, admissionReq, handler)
+	verifyAdmission(t, rr, admissionTest{
+		patch: []operations.PatchOperation{
+			operations.ReplacePatchOperation(
+				"/spec/imagePullSecrets",
+				[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+			),
+			operations.ReplacePatchOperation(
+				"/spec/containers/0/image",
+				"127.0.0.1:31999/library/nginx:latest-zarf-3793515731",
+			),
+			operations.ReplacePatchOperation(
+				"/metadata/labels",
+				map[string]string{"zarf-agent": "patched"},
+			),
+			operations.ReplacePatchOperation(
+				"/metadata/annotations",
+				map[string]string{"zarf.dev/original-image-nginx": "nginx"},
+			),
+		},
+		code: http.StatusOK,
+	})
+
+	// Without the key the state can not be decrypted, so the request is rejected.
+	require.NoError(t, c.Clientset.CoreV1().Secrets(cluster.ZarfNamespaceName).Delete(ctx, "zarf-state-key", metav1.DeleteOptions{}))
+	rr = sendAdmissionRequest(t, admissionReq, handler)
+	verifyAdmission(t, rr, admissionTest{
+		code:        http.StatusInternalServerError,
+		errContains: "unable to get the key secret zarf/zarf-state-key",
+	})
+}
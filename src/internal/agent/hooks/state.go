@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zarf-dev/zarf/src/internal/agent/metrics"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// stateCaches holds the state cache of each cluster that the hooks load the Zarf state from.
+var stateCaches sync.Map
+
+// StateCache serves the Zarf state to the hooks from memory. A state older than refreshAfter is still served while it
+// is refreshed in the background, and a state older than maxAge is refreshed before it is served.
+type StateCache struct {
+	cluster      *cluster.Cluster
+	refreshAfter time.Duration
+	maxAge       time.Duration
+
+	mu         sync.Mutex
+	state      *types.ZarfState
+	fetched    time.Time
+	refreshing bool
+	// fetchMu makes concurrent requests for an expired state wait on a single fetch
+	fetchMu sync.Mutex
+}
+
+// NewStateCache creates a state cache for the cluster and makes the hooks load the Zarf state of the cluster from it.
+func NewStateCache(c *cluster.Cluster, refreshAfter, maxAge time.Duration) *StateCache {
+	cache := &StateCache{
+		cluster:      c,
+		refreshAfter: refreshAfter,
+		maxAge:       max(maxAge, refreshAfter),
+	}
+	stateCaches.Store(c, cache)
+	return cache
+}
+
+// Load returns the cached Zarf state, fetching it from the cluster if it is missing or expired.
+func (s *StateCache) Load(ctx context.Context) (*types.ZarfState, error) {
+	s.mu.Lock()
+	state, age := s.state, time.Since(s.fetched)
+	if state != nil && age <= s.maxAge {
+		if age > s.refreshAfter && !s.refreshing {
+			s.refreshing = true
+			go func() {
+				// The refresh outlives the admission request that started it
+				//nolint:errcheck // the stale state is kept on failure and the error is logged
+				s.refresh(context.WithoutCancel(ctx))
+				s.mu.Lock()
+				s.refreshing = false
+				s.mu.Unlock()
+			}()
+		}
+		s.mu.Unlock()
+		return copyState(state), nil
+	}
+	s.mu.Unlock()
+
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	// Another request may have fetched the state while this one waited
+	s.mu.Lock()
+	state, age = s.state, time.Since(s.fetched)
+	s.mu.Unlock()
+	if state != nil && age <= s.maxAge {
+		return copyState(state), nil
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyState(s.state), nil
+}
+
+// Run refreshes the cached state every refreshAfter until the context is cancelled, so that requests are served from a
+// fresh state.
+func (s *StateCache) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.refreshAfter)
+	defer ticker.Stop()
+	for {
+		//nolint:errcheck // the stale state is kept on failure and the error is logged
+		s.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh fetches the state from the cluster, keeping the cached state if it fails.
+func (s *StateCache) refresh(ctx context.Context) error {
+	state, err := s.cluster.LoadZarfState(ctx)
+	metrics.ObserveStateFetch(err)
+	if err != nil {
+		message.Warnf("Unable to refresh the cached Zarf state: %s", err)
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+	s.fetched = time.Now()
+	return nil
+}
+
+// copyState returns a shallow copy of the state so that a hook can not change the cached state.
+func copyState(state *types.ZarfState) *types.ZarfState {
+	cp := *state
+	return &cp
+}
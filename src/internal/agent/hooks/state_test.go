@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestStateCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := createTestClientWithZarfState(ctx, t, &types.ZarfState{RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"}})
+	var fetches atomic.Int32
+	var fail atomic.Bool
+	c.Clientset.(*fake.Clientset).PrependReactor("get", "secrets", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		fetches.Add(1)
+		if fail.Load() {
+			return true, nil, errors.New("api server unavailable")
+		}
+		return false, nil, nil
+	})
+	setAddress := func(address string) {
+		t.Helper()
+		stateData, err := json.Marshal(&types.ZarfState{RegistryInfo: types.RegistryInfo{Address: address}})
+		require.NoError(t, err)
+		secret, err := c.Clientset.CoreV1().Secrets(cluster.ZarfNamespaceName).Get(ctx, cluster.ZarfStateSecretName, metav1.GetOptions{})
+		require.NoError(t, err)
+		secret.Data[cluster.ZarfStateDataKey] = stateData
+		_, err = c.Clientset.CoreV1().Secrets(cluster.ZarfNamespaceName).Update(ctx, secret, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		fetches.Store(0)
+	}
+	age := func(cache *StateCache, age time.Duration) {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		cache.fetched = time.Now().Add(-age)
+	}
+
+	cache := NewStateCache(c, time.Minute, 10*time.Minute)
+
+	// The first load fetches the state and the next ones are served from the cache
+	for range 3 {
+		state, err := loadZarfState(ctx, c)
+		require.NoError(t, err)
+		require.Equal(t, "127.0.0.1:31999", state.RegistryInfo.Address)
+	}
+	require.Equal(t, int32(1), fetches.Load())
+
+	// A hook can not change the cached state
+	state, err := loadZarfState(ctx, c)
+	require.NoError(t, err)
+	state.RegistryInfo.Address = "changed"
+	state, err = loadZarfState(ctx, c)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:31999", state.RegistryInfo.Address)
+
+	// A stale state is served while it is refreshed in the background
+	setAddress("127.0.0.1:32000")
+	age(cache, 2*time.Minute)
+	state, err = loadZarfState(ctx, c)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:31999", state.RegistryInfo.Address)
+	require.Eventually(t, func() bool {
+		state, err := cache.Load(ctx)
+		return err == nil && state.RegistryInfo.Address == "127.0.0.1:32000"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(1), fetches.Load())
+
+	// A stale state is kept when the refresh fails
+	fail.Store(true)
+	fetches.Store(0)
+	age(cache, 2*time.Minute)
+	state, err = loadZarfState(ctx, c)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:32000", state.RegistryInfo.Address)
+	require.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return fetches.Load() == 1 && !cache.refreshing
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// An expired state is not served
+	age(cache, 20*time.Minute)
+	_, err = loadZarfState(ctx, c)
+	require.ErrorContains(t, err, "api server unavailable")
+
+	// An expired state is refreshed before it is served
+	fail.Store(false)
+	setAddress("127.0.0.1:32001")
+	state, err = loadZarfState(ctx, c)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:32001", state.RegistryInfo.Address)
+	require.Equal(t, int32(1), fetches.Load())
+}
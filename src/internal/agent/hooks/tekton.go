@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	v1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// taskSpecImagePaths returns the paths of the step and sidecar images of the task spec at prefix.
+func taskSpecImagePaths(prefix string) []string {
+	return []string{
+		prefix + ".steps[*].image",
+		prefix + ".sidecars[*].image",
+		prefix + ".stepTemplate.image",
+	}
+}
+
+var (
+	taskRunImagePaths = append(taskSpecImagePaths("spec.taskSpec"),
+		// v1beta1 tasks can be referenced from a Tekton bundle without a resolver
+		"spec.taskRef.bundle",
+	)
+	pipelineRunImagePaths = append(append(
+		taskSpecImagePaths("spec.pipelineSpec.tasks[*].taskSpec"),
+		taskSpecImagePaths("spec.pipelineSpec.finally[*].taskSpec")...),
+		// v1beta1 pipelines and pipeline tasks can be referenced from a Tekton bundle without a resolver
+		"spec.pipelineRef.bundle",
+		"spec.pipelineSpec.tasks[*].taskRef.bundle",
+		"spec.pipelineSpec.finally[*].taskRef.bundle",
+	)
+	taskRunRefPaths     = []string{"spec.taskRef"}
+	pipelineRunRefPaths = []string{"spec.pipelineRef", "spec.pipelineSpec.tasks[*].taskRef", "spec.pipelineSpec.finally[*].taskRef"}
+)
+
+// NewTaskRunMutationHook creates a new instance of the Tekton TaskRun mutation hook.
+func NewTaskRunMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateTektonRun(ctx, r, cluster, taskRunImagePaths, taskRunRefPaths)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateTektonRun(ctx, r, cluster, taskRunImagePaths, taskRunRefPaths)
+		},
+	}
+}
+
+// NewPipelineRunMutationHook creates a new instance of the Tekton PipelineRun mutation hook.
+func NewPipelineRunMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateTektonRun(ctx, r, cluster, pipelineRunImagePaths, pipelineRunRefPaths)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateTektonRun(ctx, r, cluster, pipelineRunImagePaths, pipelineRunRefPaths)
+		},
+	}
+}
+
+// mutateTektonRun mutates the step and sidecar images and the bundles referenced by a Tekton run to point to the
+// registry defined in the ZarfState.
+func mutateTektonRun(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, imagePaths, refPaths []string) (*operations.Result, error) {
+	src := &unstructured.Unstructured{}
+	if err := json.Unmarshal(r.Object.Raw, &src.Object); err != nil {
+		return nil, fmt.Errorf(lang.ErrUnmarshal, err)
+	}
+
+	labels := src.GetLabels()
+	if labels != nil && labels["zarf-agent"] == "patched" {
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: []operations.PatchOperation{},
+		}, nil
+	}
+
+	found := map[string]string{}
+	for _, path := range imagePaths {
+		tokens, err := parseImagePath(path)
+		if err != nil {
+			return nil, err
+		}
+		findImages(src.Object, "", tokens, found)
+	}
+	for _, path := range refPaths {
+		tokens, err := parseImagePath(path)
+		if err != nil {
+			return nil, err
+		}
+		walkImagePath(src.Object, "", tokens, func(pointer string, ref any) {
+			findBundleParam(ref, pointer, found)
+		})
+	}
+	for pointer, image := range found {
+		// Images set from parameters are only known once Tekton resolves them
+		if strings.Contains(image, "$(") {
+			delete(found, pointer)
+		}
+	}
+	if len(found) == 0 {
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: []operations.PatchOperation{},
+		}, nil
+	}
+
+	state, err := loadZarfState(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	patches, err := imagePatches(state.RegistryInfo.Address, found)
+	if err != nil {
+		return nil, err
+	}
+	patches = append(patches, getLabelPatch(labels))
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
+
+// findBundleParam adds the bundle image of a task or pipeline reference that uses the bundles resolver.
+func findBundleParam(ref any, pointer string, found map[string]string) {
+	fields, ok := ref.(map[string]any)
+	if !ok || fields["resolver"] != "bundles" {
+		return
+	}
+	params, ok := fields["params"].([]any)
+	if !ok {
+		return
+	}
+	for i, param := range params {
+		param, ok := param.(map[string]any)
+		if !ok || param["name"] != "bundle" {
+			continue
+		}
+		if image, ok := param["value"].(string); ok && image != "" {
+			found[fmt.Sprintf("%s/params/%d/value", pointer, i)] = image
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+)
+
+func TestTaskRunMutationWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := &types.ZarfState{RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"}}
+	c := createTestClientWithZarfState(ctx, t, state)
+	handler := admission.NewHandler().Serve(NewTaskRunMutationHook(ctx, c))
+
+	tests := []admissionTest{
+		{
+			name: "should mutate the step, step template and sidecar images",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "taskruns", map[string]any{
+				"metadata": map[string]any{"name": "build"},
+				"spec": map[string]any{
+					"params": []any{map[string]any{"name": "image", "value": "alpine"}},
+					"taskSpec": map[string]any{
+						"stepTemplate": map[string]any{"image": "alpine/git:2.45.2"},
+						"steps": []any{
+							map[string]any{"name": "clone"},
+							map[string]any{"name": "build", "image": "gcr.io/kaniko-project/executor@sha256:1d3f4d4e4b6e0e7b3f2c3b5e6a8f4c8e2d1f7a9b0c3e5d7f9a1b3c5d7e9f1a3b"},
+							map[string]any{"name": "param", "image": "$(params.image)"},
+						},
+						"sidecars": []any{
+							map[string]any{"name": "docker", "image": "docker:dind"},
+						},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/taskSpec/sidecars/0/image",
+					"127.0.0.1:31999/library/docker:dind-zarf-1958758067",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/taskSpec/stepTemplate/image",
+					"127.0.0.1:31999/alpine/git:2.45.2-zarf-2739568766",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/taskSpec/steps/1/image",
+					"127.0.0.1:31999/kaniko-project/executor@sha256:1d3f4d4e4b6e0e7b3f2c3b5e6a8f4c8e2d1f7a9b0c3e5d7f9a1b3c5d7e9f1a3b",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should mutate the bundle of a task reference",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "taskruns", map[string]any{
+				"metadata": map[string]any{"name": "build", "labels": map[string]any{"app": "build"}},
+				"spec": map[string]any{
+					"taskRef": map[string]any{
+						"resolver": "bundles",
+						"params": []any{
+							map[string]any{"name": "bundle", "value": "ghcr.io/tektoncd/catalog/upstream/tasks/git-clone:0.9"},
+							map[string]any{"name": "name", "value": "git-clone"},
+							map[string]any{"name": "kind", "value": "task"},
+						},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/taskRef/params/0/value",
+					"127.0.0.1:31999/tektoncd/catalog/upstream/tasks/git-clone:0.9-zarf-2404329004",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"app":        "build",
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should not mutate a task reference from another resolver",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "taskruns", map[string]any{
+				"metadata": map[string]any{"name": "build"},
+				"spec": map[string]any{
+					"taskRef": map[string]any{
+						"resolver": "git",
+						"params": []any{
+							map[string]any{"name": "bundle", "value": "ghcr.io/tektoncd/catalog/upstream/tasks/git-clone:0.9"},
+						},
+					},
+				},
+			}),
+			code: http.StatusOK,
+		},
+		{
+			name: "should not mutate when agent patched",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Update, "tekton.dev", "taskruns", map[string]any{
+				"metadata": map[string]any{"name": "build", "labels": map[string]any{"zarf-agent": "patched"}},
+				"spec": map[string]any{
+					"taskSpec": map[string]any{"steps": []any{map[string]any{"name": "build", "image": "alpine"}}},
+				},
+			}),
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
+
+func TestPipelineRunMutationWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	state := &types.ZarfState{RegistryInfo: types.RegistryInfo{Address: "127.0.0.1:31999"}}
+	c := createTestClientWithZarfState(ctx, t, state)
+	handler := admission.NewHandler().Serve(NewPipelineRunMutationHook(ctx, c))
+
+	tests := []admissionTest{
+		{
+			name: "should mutate the images and bundles of the pipeline tasks",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "pipelineruns", map[string]any{
+				"metadata": map[string]any{"name": "release"},
+				"spec": map[string]any{
+					"pipelineSpec": map[string]any{
+						"tasks": []any{
+							map[string]any{
+								"name": "clone",
+								"taskRef": map[string]any{
+									"resolver": "bundles",
+									"params": []any{
+										map[string]any{"name": "name", "value": "git-clone"},
+										map[string]any{"name": "bundle", "value": "ghcr.io/tektoncd/catalog/upstream/tasks/git-clone:0.9"},
+									},
+								},
+							},
+							map[string]any{
+								"name": "build",
+								"taskSpec": map[string]any{
+									"steps": []any{map[string]any{"name": "build", "image": "golang:1.22"}},
+								},
+							},
+						},
+						"finally": []any{
+							map[string]any{
+								"name": "notify",
+								"taskSpec": map[string]any{
+									"steps": []any{map[string]any{"name": "notify", "image": "curlimages/curl:8.8.0"}},
+								},
+							},
+						},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/pipelineSpec/finally/0/taskSpec/steps/0/image",
+					"127.0.0.1:31999/curlimages/curl:8.8.0-zarf-2584732664",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/pipelineSpec/tasks/0/taskRef/params/1/value",
+					"127.0.0.1:31999/tektoncd/catalog/upstream/tasks/git-clone:0.9-zarf-2404329004",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/pipelineSpec/tasks/1/taskSpec/steps/0/image",
+					"127.0.0.1:31999/library/golang:1.22-zarf-696884413",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should mutate the bundle of a v1beta1 pipeline reference",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "pipelineruns", map[string]any{
+				"metadata": map[string]any{"name": "release"},
+				"spec": map[string]any{
+					"pipelineRef": map[string]any{"name": "release", "bundle": "ghcr.io/my-org/pipelines/release:1.0.0"},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/pipelineRef/bundle",
+					"127.0.0.1:31999/my-org/pipelines/release:1.0.0-zarf-29966245",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should not mutate a pipeline reference by name",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, "tekton.dev", "pipelineruns", map[string]any{
+				"metadata": map[string]any{"name": "release"},
+				"spec":     map[string]any{"pipelineRef": map[string]any{"name": "release"}},
+			}),
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
@@ -8,11 +8,15 @@ package admission
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/audit"
+	"github.com/zarf-dev/zarf/src/internal/agent/metrics"
 	"github.com/zarf-dev/zarf/src/internal/agent/operations"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	corev1 "k8s.io/api/admission/v1"
@@ -23,7 +27,8 @@ import (
 
 // Handler represents the HTTP handler for an admission webhook.
 type Handler struct {
-	decoder runtime.Decoder
+	decoder  runtime.Decoder
+	auditLog *audit.Logger
 }
 
 // NewHandler returns a new admission Handler.
@@ -33,9 +38,21 @@ func NewHandler() *Handler {
 	}
 }
 
+// WithAuditLog sets the logger that records every mutation made by the handler.
+func (h *Handler) WithAuditLog(auditLog *audit.Logger) *Handler {
+	h.auditLog = auditLog
+	return h
+}
+
 // Serve returns an http.HandlerFunc for an admission webhook.
 func (h *Handler) Serve(hook operations.Hook) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		outcome := metrics.ResultErrored
+		defer func() {
+			metrics.ObserveAdmission(r.URL.Path, outcome, time.Since(start))
+		}()
+
 		w.Header().Set("Content-Type", "application/json")
 		if r.Method != http.MethodPost {
 			http.Error(w, lang.AgentErrInvalidMethod, http.StatusMethodNotAllowed)
@@ -49,7 +66,12 @@ func (h *Handler) Serve(hook operations.Hook) http.HandlerFunc {
 
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, fmt.Sprintf(lang.AgentErrBadRequest, err), http.StatusBadRequest)
+			code := http.StatusBadRequest
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				code = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, fmt.Sprintf(lang.AgentErrBadRequest, err), code)
 			return
 		}
 
@@ -92,14 +114,15 @@ func (h *Handler) Serve(hook operations.Hook) http.HandlerFunc {
 		admissionResponse := corev1.AdmissionReview{
 			TypeMeta: admissionMeta,
 			Response: &corev1.AdmissionResponse{
-				UID:     review.Request.UID,
-				Allowed: result.Allowed,
-				Result:  &metav1.Status{Message: result.Msg},
+				UID:      review.Request.UID,
+				Allowed:  result.Allowed,
+				Result:   &metav1.Status{Message: result.Msg},
+				Warnings: result.Warnings,
 			},
 		}
 
 		// Set the patch operations for mutating admission
-		if len(result.PatchOps) > 0 {
+		if len(result.PatchOps) > 0 && !result.DryRun {
 			jsonPatchType := corev1.PatchTypeJSONPatch
 			patchBytes, err := json.Marshal(result.PatchOps)
 			if err != nil {
@@ -117,6 +140,23 @@ func (h *Handler) Serve(hook operations.Hook) http.HandlerFunc {
 			return
 		}
 
+		switch {
+		case !result.Allowed:
+			outcome = metrics.ResultDenied
+		case len(result.PatchOps) > 0 && result.DryRun:
+			outcome = metrics.ResultDryRun
+		case len(result.PatchOps) > 0:
+			outcome = metrics.ResultMutated
+		default:
+			outcome = metrics.ResultSkipped
+		}
+		if h.auditLog != nil && result.Allowed && len(result.PatchOps) > 0 {
+			record := audit.NewRecord(r.URL.Path, review.Request, result.PatchOps)
+			record.DryRun = result.DryRun
+			if err := h.auditLog.Log(record); err != nil {
+				message.Warnf(lang.AgentWarnAuditLog, err)
+			}
+		}
 		message.Infof(lang.AgentInfoWebhookAllowed, r.URL.Path, review.Request.Operation, result.Allowed)
 		w.WriteHeader(http.StatusOK)
 		//nolint: errcheck // ignore
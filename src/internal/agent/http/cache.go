@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package http provides a http server for the webhook and proxy.
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zarf-dev/zarf/src/internal/agent/metrics"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// maxCachedRequestBody is the largest git-upload-pack request that the proxy caches the response of.
+const maxCachedRequestBody = 1024 * 1024
+
+// ProxyCache is an on-disk cache of the upstream responses of the proxy. It holds responses for up to its TTL and
+// evicts the least recently used responses once it grows past its maximum size.
+type ProxyCache struct {
+	dir     string
+	maxSize int64
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type proxyCacheEntry struct {
+	key    string
+	size   int64
+	stored time.Time
+}
+
+type cachedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+}
+
+// NewProxyCache creates a proxy cache in dir, removing any responses cached there by a previous proxy.
+func NewProxyCache(dir string, maxSize int64, ttl time.Duration) (*ProxyCache, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &ProxyCache{
+		dir:     dir,
+		maxSize: maxSize,
+		ttl:     ttl,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}, nil
+}
+
+// key returns the cache key of a transformed proxy request, or false if its response can not be cached.
+//
+// Downloads and git ref advertisements are GET requests, and git packfiles are fetched with a POST to git-upload-pack
+// that is cached by the wants and haves in its body.
+func (c *ProxyCache) key(r *http.Request) (string, bool) {
+	if r.Header.Get("Range") != "" {
+		return "", false
+	}
+	hash := sha256.New()
+	switch {
+	case r.Method == http.MethodGet:
+		// Pushes need the current refs of the repo
+		if r.URL.Query().Get("service") == "git-receive-pack" {
+			return "", false
+		}
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/git-upload-pack"):
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxCachedRequestBody+1))
+		if err != nil {
+			return "", false
+		}
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+		if len(body) > maxCachedRequestBody {
+			return "", false
+		}
+		hash.Write(body)
+	default:
+		return "", false
+	}
+	// The response links are rewritten for the host and scheme the request was sent to
+	for _, part := range []string{
+		r.Method,
+		r.URL.String(),
+		getTLSScheme(r.TLS),
+		r.Header.Get("X-Forwarded-Host"),
+		r.Header.Get("Accept"),
+		r.Header.Get("Content-Type"),
+		r.Header.Get("Content-Encoding"),
+		r.Header.Get("Git-Protocol"),
+	} {
+		hash.Write([]byte(part + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+// serve writes the cached response for the key, returning false if there is none.
+func (c *ProxyCache) serve(w http.ResponseWriter, key string) bool {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if ok && time.Since(elem.Value.(*proxyCacheEntry).stored) > c.ttl {
+		c.remove(elem)
+		ok = false
+	}
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	// The response may have been evicted since, in which case it is fetched again
+	meta, err := os.ReadFile(c.path(key) + ".json")
+	if err != nil {
+		return false
+	}
+	resp := cachedResponse{}
+	if err := json.Unmarshal(meta, &resp); err != nil {
+		return false
+	}
+	body, err := os.Open(c.path(key))
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, body); err != nil {
+		message.Debugf("Unable to serve the cached proxy response: %s", err)
+	}
+	return true
+}
+
+// store caches the response as its body is read, once it is read in full.
+func (c *ProxyCache) store(resp *http.Response, key string) {
+	if resp.StatusCode != http.StatusOK || resp.ContentLength > c.maxSize {
+		return
+	}
+	tmp, err := os.CreateTemp(c.dir, ".tmp-")
+	if err != nil {
+		message.Debugf("Unable to cache the proxy response: %s", err)
+		return
+	}
+	header := resp.Header.Clone()
+	// Sessions are not shared between clients
+	header.Del("Set-Cookie")
+	resp.Body = &cacheWriter{
+		ReadCloser: resp.Body,
+		cache:      c,
+		key:        key,
+		file:       tmp,
+		resp:       cachedResponse{StatusCode: resp.StatusCode, Header: header},
+	}
+}
+
+// commit adds the response written to the temporary file to the cache.
+func (c *ProxyCache) commit(key, tmp string, resp cachedResponse, size int64) error {
+	meta, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmp+".json", meta, 0o600); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.Rename(tmp+".json", c.path(key)+".json"); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path(key)); err != nil {
+		return err
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.size -= elem.Value.(*proxyCacheEntry).size
+		c.lru.Remove(elem)
+	}
+	c.entries[key] = c.lru.PushFront(&proxyCacheEntry{key: key, size: size, stored: time.Now()})
+	c.size += size
+	for c.size > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+	return nil
+}
+
+// remove removes an entry from the cache, the caller must hold the lock.
+func (c *ProxyCache) remove(elem *list.Element) {
+	entry := elem.Value.(*proxyCacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+	_ = os.Remove(c.path(entry.key))
+	_ = os.Remove(c.path(entry.key) + ".json")
+}
+
+func (c *ProxyCache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// cacheWriter copies a response body to the cache as the client reads it.
+type cacheWriter struct {
+	io.ReadCloser
+	cache *ProxyCache
+	key   string
+	file  *os.File
+	resp  cachedResponse
+	size  int64
+	done  bool
+}
+
+func (cw *cacheWriter) Read(p []byte) (int, error) {
+	n, err := cw.ReadCloser.Read(p)
+	if cw.done {
+		return n, err
+	}
+	if n > 0 {
+		cw.size += int64(n)
+		if cw.size > cw.cache.maxSize {
+			cw.abort()
+			return n, err
+		}
+		if _, werr := cw.file.Write(p[:n]); werr != nil {
+			message.Debugf("Unable to cache the proxy response: %s", werr)
+			cw.abort()
+			return n, err
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		cw.done = true
+		if cerr := cw.file.Close(); cerr != nil {
+			message.Debugf("Unable to cache the proxy response: %s", cerr)
+			_ = os.Remove(cw.file.Name())
+			return n, err
+		}
+		if cerr := cw.cache.commit(cw.key, cw.file.Name(), cw.resp, cw.size); cerr != nil {
+			message.Debugf("Unable to cache the proxy response: %s", cerr)
+			_ = os.Remove(cw.file.Name())
+			_ = os.Remove(cw.file.Name() + ".json")
+		}
+	}
+	return n, err
+}
+
+// Close discards a response that was not read in full.
+func (cw *cacheWriter) Close() error {
+	cw.abort()
+	return cw.ReadCloser.Close()
+}
+
+func (cw *cacheWriter) abort() {
+	if cw.done {
+		return
+	}
+	cw.done = true
+	_ = cw.file.Close()
+	_ = os.Remove(cw.file.Name())
+}
+
+// observeProxyCache records a lookup in the proxy cache in the agent metrics.
+func observeProxyCache(hit bool) {
+	if hit {
+		metrics.ObserveProxyCache(metrics.CacheHit)
+		return
+	}
+	metrics.ObserveProxyCache(metrics.CacheMiss)
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestProxyCache(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	fetches := map[string]int{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		mu.Lock()
+		fetches[r.URL.Path+"?"+r.URL.RawQuery]++
+		mu.Unlock()
+		w.Header().Set("Set-Cookie", "session=secret")
+		//nolint: errcheck // ignore
+		w.Write([]byte(r.URL.Path + " " + string(body)))
+	}))
+	t.Cleanup(upstream.Close)
+	fetched := func(target string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return fetches[target]
+	}
+
+	c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+	state := &types.ZarfState{GitServer: types.GitServerInfo{Address: upstream.URL, PushUsername: "push-user", PushPassword: "push-password"}}
+	require.NoError(t, c.SaveZarfState(context.Background(), state))
+
+	send := func(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		// Requests received by a server only have the path in their URL
+		host, path, _ := strings.Cut(strings.TrimPrefix(target, "http://"), "/")
+		req := httptest.NewRequest(method, "/"+path, strings.NewReader(body))
+		req.Host = host
+		req.Header.Set("User-Agent", "git/2.45.2")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr
+	}
+
+	t.Run("repeated fetches", func(t *testing.T) {
+		t.Parallel()
+		cache, err := NewProxyCache(t.TempDir(), 1024*1024, time.Minute)
+		require.NoError(t, err)
+		h := ProxyHandler(c, cache)
+
+		refs := "/push-user/podinfo-1646971829.git/info/refs?service=git-upload-pack"
+		first := send(h, http.MethodGet, "http://github.com/stefanprodan/podinfo.git/info/refs?service=git-upload-pack", "")
+		second := send(h, http.MethodGet, "http://github.com/stefanprodan/podinfo.git/info/refs?service=git-upload-pack", "")
+		require.Equal(t, first.Body.String(), second.Body.String())
+		require.Empty(t, second.Header().Get("Set-Cookie"))
+		require.Equal(t, 1, fetched(refs))
+
+		// Packfiles are cached by the request body
+		pack := "/push-user/podinfo-1646971829.git/git-upload-pack?"
+		send(h, http.MethodPost, "http://github.com/stefanprodan/podinfo.git/git-upload-pack", "want a")
+		second = send(h, http.MethodPost, "http://github.com/stefanprodan/podinfo.git/git-upload-pack", "want a")
+		require.Equal(t, "/push-user/podinfo-1646971829.git/git-upload-pack want a", second.Body.String())
+		require.Equal(t, 1, fetched(pack))
+		send(h, http.MethodPost, "http://github.com/stefanprodan/podinfo.git/git-upload-pack", "want b")
+		require.Equal(t, 2, fetched(pack))
+
+		// Pushes are never cached
+		pushRefs := "/push-user/podinfo-1646971829.git/info/refs?service=git-receive-pack"
+		send(h, http.MethodGet, "http://github.com/stefanprodan/podinfo.git/info/refs?service=git-receive-pack", "")
+		send(h, http.MethodGet, "http://github.com/stefanprodan/podinfo.git/info/refs?service=git-receive-pack", "")
+		require.Equal(t, 2, fetched(pushRefs))
+	})
+
+	t.Run("expired responses", func(t *testing.T) {
+		t.Parallel()
+		cache, err := NewProxyCache(t.TempDir(), 1024*1024, time.Nanosecond)
+		require.NoError(t, err)
+		h := ProxyHandler(c, cache)
+
+		refs := "/push-user/flux-757286684.git/info/refs?service=git-upload-pack"
+		send(h, http.MethodGet, "http://github.com/fluxcd/flux.git/info/refs?service=git-upload-pack", "")
+		send(h, http.MethodGet, "http://github.com/fluxcd/flux.git/info/refs?service=git-upload-pack", "")
+		require.Equal(t, 2, fetched(refs))
+	})
+
+	t.Run("least recently used responses are evicted", func(t *testing.T) {
+		t.Parallel()
+		cache, err := NewProxyCache(t.TempDir(), 150, time.Minute)
+		require.NoError(t, err)
+		h := ProxyHandler(c, cache)
+
+		// Each response is just over half of the cache
+		first := "/push-user/lru-first-"
+		second := "/push-user/lru-second-"
+		send(h, http.MethodPost, "http://github.com/zarf-dev/lru-first.git/git-upload-pack", strings.Repeat("a", 50))
+		send(h, http.MethodPost, "http://github.com/zarf-dev/lru-second.git/git-upload-pack", strings.Repeat("a", 50))
+		send(h, http.MethodPost, "http://github.com/zarf-dev/lru-second.git/git-upload-pack", strings.Repeat("a", 50))
+		send(h, http.MethodPost, "http://github.com/zarf-dev/lru-first.git/git-upload-pack", strings.Repeat("a", 50))
+		counts := map[string]int{}
+		mu.Lock()
+		for target, n := range fetches {
+			for _, prefix := range []string{first, second} {
+				if strings.HasPrefix(target, prefix) {
+					counts[prefix] += n
+				}
+			}
+		}
+		mu.Unlock()
+		require.Equal(t, map[string]int{first: 2, second: 1}, counts)
+	})
+}
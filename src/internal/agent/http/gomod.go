@@ -0,0 +1,390 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package http provides a http server for the webhook and proxy.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	modzip "golang.org/x/mod/zip"
+
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// GoModuleProxyPrefix is the path the proxy serves the Go module proxy protocol under, so that GOPROXY is set to the
+// proxy URL followed by this prefix.
+const GoModuleProxyPrefix = "/zarf-go-proxy/"
+
+var errGoModuleNotFound = errors.New("not found")
+
+// goModuleRequest is a request of the Go module proxy protocol (https://go.dev/ref/mod#goproxy-protocol).
+type goModuleRequest struct {
+	// The module path (e.g. github.com/stefanprodan/podinfo)
+	path string
+	// The version requested, empty for the list and latest endpoints
+	version string
+	// The endpoint requested: list, latest, info, mod or zip
+	endpoint string
+}
+
+// parseGoModuleRequest parses the path of a Go module proxy request, without the GoModuleProxyPrefix.
+func parseGoModuleRequest(requestPath string) (goModuleRequest, error) {
+	if escaped, ok := strings.CutSuffix(requestPath, "/@latest"); ok {
+		modulePath, err := module.UnescapePath(escaped)
+		return goModuleRequest{path: modulePath, endpoint: "latest"}, err
+	}
+	escaped, file, ok := strings.Cut(requestPath, "/@v/")
+	if !ok {
+		return goModuleRequest{}, fmt.Errorf("%s is not a Go module proxy path", requestPath)
+	}
+	modulePath, err := module.UnescapePath(escaped)
+	if err != nil {
+		return goModuleRequest{}, err
+	}
+	if file == "list" {
+		return goModuleRequest{path: modulePath, endpoint: "list"}, nil
+	}
+	ext := path.Ext(file)
+	endpoint := strings.TrimPrefix(ext, ".")
+	if endpoint != "info" && endpoint != "mod" && endpoint != "zip" {
+		return goModuleRequest{}, fmt.Errorf("%s is not a Go module proxy path", requestPath)
+	}
+	version, err := module.UnescapeVersion(strings.TrimSuffix(file, ext))
+	if err != nil {
+		return goModuleRequest{}, err
+	}
+	if err := module.Check(modulePath, version); err != nil {
+		return goModuleRequest{}, err
+	}
+	return goModuleRequest{path: modulePath, version: version, endpoint: endpoint}, nil
+}
+
+// GoModuleProxyHandler serves the Go module proxy protocol from the Go registry of the artifact server, where the Go
+// modules of packages are stored, falling back to building the modules from the tags of the repos on the git server.
+func GoModuleProxyHandler(cluster *cluster.Cluster) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		requestPath := strings.TrimPrefix(r.URL.Path, GoModuleProxyPrefix)
+		req, err := parseGoModuleRequest(requestPath)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		state, err := cluster.LoadZarfState(r.Context())
+		if err != nil {
+			message.Debugf("%#v", err)
+			http.Error(w, "unable to load Zarf state, see the Zarf HTTP proxy logs for more details", http.StatusInternalServerError)
+			return
+		}
+
+		if serveGoModuleFromRegistry(w, r, state, requestPath) {
+			return
+		}
+		err = serveGoModuleFromGit(r.Context(), w, state, req)
+		if errors.Is(err, errGoModuleNotFound) {
+			// The go command moves on to the next proxy in GOPROXY on a 404
+			http.Error(w, fmt.Sprintf("%s %s not found on the Zarf artifact or git server", req.path, req.version), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			message.Debugf("Unable to serve the Go module %s %s: %s", req.path, req.version, err)
+			http.Error(w, "unable to serve the Go module, see the Zarf HTTP proxy logs for more details", http.StatusInternalServerError)
+		}
+	}
+}
+
+// serveGoModuleFromRegistry forwards the request to the Go registry of the artifact server, returning false if it does
+// not have the module.
+func serveGoModuleFromRegistry(w http.ResponseWriter, r *http.Request, state *types.ZarfState, requestPath string) bool {
+	target := fmt.Sprintf("%s/go/%s", strings.TrimSuffix(state.ArtifactServer.Address, "/"), requestPath)
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, target, nil)
+	if err != nil {
+		message.Debugf("Unable to request the Go module from the artifact server: %s", err)
+		return false
+	}
+	req.SetBasicAuth(state.ArtifactServer.PushUsername, state.ArtifactServer.PushToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		message.Debugf("Unable to request the Go module from the artifact server: %s", err)
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		message.Debugf("Unable to serve the Go module from the artifact server: %s", err)
+	}
+	return true
+}
+
+// goModuleRepo is the repo on the git server a module is found in.
+type goModuleRepo struct {
+	url  string
+	auth *githttp.BasicAuth
+	// The directory of the module within the repo, without the major version suffix
+	subdir string
+	// The tags of the repo by name
+	tags map[string]*plumbing.Reference
+}
+
+// serveGoModuleFromGit serves the module from the tags of its repo on the git server.
+func serveGoModuleFromGit(ctx context.Context, w http.ResponseWriter, state *types.ZarfState, req goModuleRequest) error {
+	repo, err := findGoModuleRepo(ctx, state, req.path)
+	if err != nil {
+		return err
+	}
+
+	version := req.version
+	switch req.endpoint {
+	case "list":
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		_, err := io.WriteString(w, strings.Join(repo.versions(req.path), "\n"))
+		return err
+	case "latest":
+		versions := repo.versions(req.path)
+		if len(versions) == 0 {
+			return errGoModuleNotFound
+		}
+		version = versions[len(versions)-1]
+	}
+
+	tag := version
+	if repo.subdir != "" {
+		tag = repo.subdir + "/" + version
+	}
+	if _, ok := repo.tags[tag]; !ok {
+		return errGoModuleNotFound
+	}
+	commit, tree, err := repo.checkout(ctx, tag, req.path)
+	if err != nil {
+		return err
+	}
+
+	switch req.endpoint {
+	case "info", "latest":
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(map[string]any{
+			"Version": version,
+			"Time":    commit.Committer.When.UTC().Format(time.RFC3339),
+		})
+	case "mod":
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		goMod, err := tree.File("go.mod")
+		if errors.Is(err, object.ErrFileNotFound) {
+			// Modules without a go.mod are served with a synthesized one, like the public proxy does
+			_, err := fmt.Fprintf(w, "module %s\n", req.path)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		contents, err := goMod.Contents()
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, contents)
+		return err
+	default:
+		files := []modzip.File{}
+		err := tree.Files().ForEach(func(f *object.File) error {
+			files = append(files, goModuleFile{File: f})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		return modzip.Create(w, module.Version{Path: req.path, Version: version}, files)
+	}
+}
+
+// findGoModuleRepo finds the repo of a module on the git server by trying the module path and then its parent paths.
+func findGoModuleRepo(ctx context.Context, state *types.ZarfState, modulePath string) (*goModuleRepo, error) {
+	prefix, _, _ := module.SplitPathVersion(modulePath)
+	elems := strings.Split(prefix, "/")
+	auth := &githttp.BasicAuth{Username: state.GitServer.PullUsername, Password: state.GitServer.PullPassword}
+	for i := len(elems); i >= 2; i-- {
+		repoURL, err := transform.GitURL(state.GitServer.Address, fmt.Sprintf("https://%s.git", strings.Join(elems[:i], "/")), state.GitServer.PushUsername)
+		if err != nil {
+			return nil, err
+		}
+		remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{repoURL.String()}})
+		refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+		if errors.Is(err, transport.ErrRepositoryNotFound) || errors.Is(err, transport.ErrEmptyRemoteRepository) || errors.Is(err, transport.ErrAuthenticationRequired) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags := map[string]*plumbing.Reference{}
+		for _, ref := range refs {
+			if ref.Name().IsTag() {
+				tags[ref.Name().Short()] = ref
+			}
+		}
+		return &goModuleRepo{
+			url:    repoURL.String(),
+			auth:   auth,
+			subdir: strings.Join(elems[i:], "/"),
+			tags:   tags,
+		}, nil
+	}
+	return nil, errGoModuleNotFound
+}
+
+// versions returns the versions of the module tagged in the repo.
+func (repo *goModuleRepo) versions(modulePath string) []string {
+	versions := []string{}
+	for tag := range repo.tags {
+		version := tag
+		if repo.subdir != "" {
+			var ok bool
+			version, ok = strings.CutPrefix(tag, repo.subdir+"/")
+			if !ok {
+				continue
+			}
+		}
+		if semver.IsValid(version) && semver.Canonical(version) == version && module.Check(modulePath, version) == nil {
+			versions = append(versions, version)
+		}
+	}
+	semver.Sort(versions)
+	return versions
+}
+
+// checkout returns the commit of the tag and the tree of the module at it.
+func (repo *goModuleRepo) checkout(ctx context.Context, tag, modulePath string) (*object.Commit, *object.Tree, error) {
+	r, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           repo.url,
+		Auth:          repo.auth,
+		ReferenceName: plumbing.NewTagReferenceName(tag),
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags,
+		NoCheckout:    true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to clone %s at %s: %w", repo.url, tag, err)
+	}
+	head, err := r.Head()
+	if err != nil {
+		return nil, nil, err
+	}
+	commit, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A major version is either developed in a subdirectory named after it or on its own branch
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+	dirs := []string{repo.subdir}
+	if pathMajor != "" {
+		dirs = []string{path.Join(repo.subdir, strings.TrimPrefix(pathMajor, "/")), repo.subdir}
+	}
+	for _, dir := range dirs {
+		moduleTree := tree
+		if dir != "" {
+			moduleTree, err = tree.Tree(dir)
+			if errors.Is(err, object.ErrDirectoryNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if pathMajor != "" {
+			if _, err := moduleTree.File("go.mod"); err != nil {
+				continue
+			}
+		}
+		return commit, moduleTree, nil
+	}
+	return nil, nil, errGoModuleNotFound
+}
+
+// goModuleFile is a file of a module within its tree.
+type goModuleFile struct {
+	*object.File
+}
+
+func (f goModuleFile) Path() string {
+	return f.Name
+}
+
+func (f goModuleFile) Lstat() (fs.FileInfo, error) {
+	return goModuleFileInfo{f.File}, nil
+}
+
+func (f goModuleFile) Open() (io.ReadCloser, error) {
+	return f.Reader()
+}
+
+type goModuleFileInfo struct {
+	*object.File
+}
+
+func (fi goModuleFileInfo) Name() string {
+	return path.Base(fi.File.Name)
+}
+
+func (fi goModuleFileInfo) Size() int64 {
+	return fi.File.Size
+}
+
+func (fi goModuleFileInfo) Mode() fs.FileMode {
+	switch fi.File.Mode {
+	case filemode.Symlink:
+		return fs.ModeSymlink | 0o777
+	case filemode.Executable:
+		return 0o755
+	default:
+		return 0o644
+	}
+}
+
+func (goModuleFileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (goModuleFileInfo) IsDir() bool {
+	return false
+}
+
+func (goModuleFileInfo) Sys() any {
+	return nil
+}
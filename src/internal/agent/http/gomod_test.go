@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fluxcd/gitkit"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestParseGoModuleRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path     string
+		expected goModuleRequest
+		err      bool
+	}{
+		{
+			path:     "github.com/stefanprodan/podinfo/@v/list",
+			expected: goModuleRequest{path: "github.com/stefanprodan/podinfo", endpoint: "list"},
+		},
+		{
+			path:     "github.com/stefanprodan/podinfo/@latest",
+			expected: goModuleRequest{path: "github.com/stefanprodan/podinfo", endpoint: "latest"},
+		},
+		{
+			path:     "github.com/!burnt!sushi/toml/@v/v1.3.2.info",
+			expected: goModuleRequest{path: "github.com/BurntSushi/toml", version: "v1.3.2", endpoint: "info"},
+		},
+		{
+			path:     "github.com/stefanprodan/podinfo/v6/@v/v6.7.0.mod",
+			expected: goModuleRequest{path: "github.com/stefanprodan/podinfo/v6", version: "v6.7.0", endpoint: "mod"},
+		},
+		{
+			path:     "github.com/stefanprodan/podinfo/v6/@v/v6.7.0-rc.1.zip",
+			expected: goModuleRequest{path: "github.com/stefanprodan/podinfo/v6", version: "v6.7.0-rc.1", endpoint: "zip"},
+		},
+		{
+			path: "github.com/stefanprodan/podinfo/v6/@v/v5.0.0.zip",
+			err:  true,
+		},
+		{
+			path: "github.com/stefanprodan/podinfo/@v/v1.0.0.tar",
+			err:  true,
+		},
+		{
+			path: "github.com/stefanprodan/podinfo",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := parseGoModuleRequest(tt.path)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, req)
+		})
+	}
+}
+
+func TestGoModuleProxyHandler(t *testing.T) {
+	t.Parallel()
+
+	gitSrv := gitkit.New(gitkit.Config{Dir: t.TempDir(), AutoCreate: true})
+	require.NoError(t, gitSrv.Setup())
+	gitServer := httptest.NewServer(http.HandlerFunc(gitSrv.ServeHTTP))
+	t.Cleanup(gitServer.Close)
+	artifactServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/go/example.com/vendored/@v/list" {
+			//nolint: errcheck // ignore
+			w.Write([]byte("v0.1.0\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(artifactServer.Close)
+
+	state := &types.ZarfState{
+		GitServer:      types.GitServerInfo{Address: gitServer.URL, PushUsername: "push-user", PullUsername: "pull-user", PullPassword: "pull-password"},
+		ArtifactServer: types.ArtifactServerInfo{Address: artifactServer.URL},
+	}
+	c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+	require.NoError(t, c.SaveZarfState(context.Background(), state))
+
+	// A repo with a module at its root and one in a subdirectory
+	fs := memfs.New()
+	repo, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, contents := range map[string]string{
+		"go.mod":     "module github.com/example/hello\n\ngo 1.22\n",
+		"hello.go":   "package hello\n",
+		"sub/go.mod": "module github.com/example/hello/sub\n",
+		"sub/sub.go": "package sub\n",
+	} {
+		f, err := fs.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(contents))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		_, err = w.Add(name)
+		require.NoError(t, err)
+	}
+	hash, err := w.Commit("Initial commit", &git.CommitOptions{Author: &object.Signature{Email: "example@example.com"}})
+	require.NoError(t, err)
+	for _, tag := range []string{"v1.0.0", "v1.1.0", "not-a-version", "sub/v0.1.0"} {
+		_, err = repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+	repoURL, err := transform.GitURL(gitServer.URL, "https://github.com/example/hello.git", "push-user")
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{repoURL.String()}})
+	require.NoError(t, err)
+	require.NoError(t, repo.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"}}))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		GoModuleProxyHandler(c).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, GoModuleProxyPrefix+path, nil))
+		return rr
+	}
+
+	rr := get("github.com/example/hello/@v/list")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "v1.0.0\nv1.1.0", rr.Body.String())
+
+	rr = get("github.com/example/hello/@latest")
+	require.Equal(t, http.StatusOK, rr.Code)
+	info := map[string]string{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &info))
+	require.Equal(t, "v1.1.0", info["Version"])
+	require.NotEmpty(t, info["Time"])
+
+	rr = get("github.com/example/hello/@v/v1.0.0.mod")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "module github.com/example/hello\n\ngo 1.22\n", rr.Body.String())
+
+	// The zip holds the module without the nested module
+	rr = get("github.com/example/hello/@v/v1.0.0.zip")
+	require.Equal(t, http.StatusOK, rr.Code)
+	zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	require.NoError(t, err)
+	names := []string{}
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	require.ElementsMatch(t, []string{"github.com/example/hello@v1.0.0/go.mod", "github.com/example/hello@v1.0.0/hello.go"}, names)
+
+	rr = get("github.com/example/hello/sub/@v/list")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "v0.1.0", rr.Body.String())
+	rr = get("github.com/example/hello/sub/@v/v0.1.0.mod")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "module github.com/example/hello/sub\n", rr.Body.String())
+
+	// Modules stored in the artifact server are served from it
+	rr = get("example.com/vendored/@v/list")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "v0.1.0\n", rr.Body.String())
+
+	require.Equal(t, http.StatusNotFound, get("github.com/example/hello/@v/v1.2.0.info").Code)
+	require.Equal(t, http.StatusNotFound, get("github.com/example/missing/@v/list").Code)
+}
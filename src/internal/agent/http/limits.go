@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package http provides a http server for the webhook and proxy.
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/zarf-dev/zarf/src/internal/agent/metrics"
+)
+
+// clientIdleTimeout is how long the rate limiter of a client is kept after its last request.
+const clientIdleTimeout = 5 * time.Minute
+
+// Limits are the limits applied to the requests served by the agent, a zero value disables a limit.
+type Limits struct {
+	// Largest request body accepted in bytes
+	MaxBodyBytes int64
+	// Requests per second allowed from a single client
+	RateLimit float64
+	// Requests a single client can make at once before being rate limited
+	RateBurst int
+	// Requests served at the same time across every client
+	MaxConcurrent int
+	// How long a request waits for one of the concurrent requests to finish before it is rejected
+	MaxConcurrentWait time.Duration
+	// ClientKey returns the client a request is rate limited as, the remote host of the request when it is nil
+	ClientKey func(r *http.Request) string
+}
+
+// RemoteHostKey rate limits requests by the host they are sent from.
+func RemoteHostKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// AdmissionClientKey rate limits admission requests by the namespace of the resource, or the user that made the request
+// for resources that are not namespaced. Every admission request is sent by the API server, so limiting by remote host
+// would throttle the whole cluster as a single client.
+func AdmissionClientKey(r *http.Request) string {
+	b, err := io.ReadAll(r.Body)
+	// Hand the body back to the next handler, which reports any read error itself
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), r.Body))
+	if err != nil {
+		return RemoteHostKey(r)
+	}
+	review := struct {
+		Request struct {
+			Namespace string `json:"namespace"`
+			UserInfo  struct {
+				Username string `json:"username"`
+			} `json:"userInfo"`
+		} `json:"request"`
+	}{}
+	if err := json.Unmarshal(b, &review); err != nil {
+		return RemoteHostKey(r)
+	}
+	if review.Request.Namespace != "" {
+		return "namespace/" + review.Request.Namespace
+	}
+	return "user/" + review.Request.UserInfo.Username
+}
+
+// client is the rate limiter of a single client.
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// limitHandler rejects the requests that go over the limits before they reach the next handler.
+type limitHandler struct {
+	next      http.Handler
+	limits    Limits
+	inflight  chan struct{}
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastPrune time.Time
+}
+
+// LimitHandler returns a handler that applies the limits to the requests served by next.
+func LimitHandler(next http.Handler, limits Limits) http.Handler {
+	h := &limitHandler{
+		next:      next,
+		limits:    limits,
+		clients:   map[string]*client{},
+		lastPrune: time.Now(),
+	}
+	if limits.MaxConcurrent > 0 {
+		h.inflight = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *limitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.limits.MaxBodyBytes > 0 {
+		if r.ContentLength > h.limits.MaxBodyBytes {
+			metrics.ObserveRejectedRequest(metrics.RejectedBodySize)
+			http.Error(w, fmt.Sprintf("request body is larger than %d bytes", h.limits.MaxBodyBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, h.limits.MaxBodyBytes)
+	}
+
+	if h.limits.RateLimit > 0 {
+		if delay, ok := h.allow(r); !ok {
+			metrics.ObserveRejectedRequest(metrics.RejectedRateLimit)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(w, "too many requests from this client", http.StatusTooManyRequests)
+			return
+		}
+	}
+
+	if h.inflight != nil {
+		if !h.acquire(r) {
+			metrics.ObserveRejectedRequest(metrics.RejectedConcurrency)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many concurrent requests", http.StatusServiceUnavailable)
+			return
+		}
+		defer func() { <-h.inflight }()
+	}
+
+	h.next.ServeHTTP(w, r)
+}
+
+// acquire takes one of the concurrent request slots, waiting up to MaxConcurrentWait for one to free up so that short
+// bursts are queued rather than rejected.
+func (h *limitHandler) acquire(r *http.Request) bool {
+	select {
+	case h.inflight <- struct{}{}:
+		return true
+	default:
+	}
+	if h.limits.MaxConcurrentWait <= 0 {
+		return false
+	}
+	timer := time.NewTimer(h.limits.MaxConcurrentWait)
+	defer timer.Stop()
+	select {
+	case h.inflight <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// allow takes a token from the rate limiter of the client that made the request, returning how long the client should
+// wait if none is available.
+func (h *limitHandler) allow(r *http.Request) (time.Duration, bool) {
+	clientKey := h.limits.ClientKey
+	if clientKey == nil {
+		clientKey = RemoteHostKey
+	}
+	key := clientKey(r)
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if now.Sub(h.lastPrune) > clientIdleTimeout {
+		for key, c := range h.clients {
+			if now.Sub(c.lastSeen) > clientIdleTimeout {
+				delete(h.clients, key)
+			}
+		}
+		h.lastPrune = now
+	}
+	c, ok := h.clients[key]
+	if !ok {
+		burst := max(h.limits.RateBurst, 1)
+		c = &client{limiter: rate.NewLimiter(rate.Limit(h.limits.RateLimit), burst)}
+		h.clients[key] = c
+	}
+	c.lastSeen = now
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay, false
+	}
+	return 0, true
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitHandler(t *testing.T) {
+	t.Parallel()
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		//nolint: errcheck // ignore
+		w.Write(b)
+	})
+	send := func(h http.Handler, remoteAddr, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mutate/pod", strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("body size", func(t *testing.T) {
+		t.Parallel()
+		h := LimitHandler(echo, Limits{MaxBodyBytes: 5})
+		require.Equal(t, http.StatusOK, send(h, "10.0.0.1:5000", "hello").Code)
+		require.Equal(t, http.StatusRequestEntityTooLarge, send(h, "10.0.0.1:5000", "hello world").Code)
+
+		// bodies without a content length are limited while they are read
+		req := httptest.NewRequest(http.MethodPost, "/mutate/pod", io.NopCloser(strings.NewReader("hello world")))
+		req.ContentLength = -1
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		t.Parallel()
+		h := LimitHandler(echo, Limits{RateLimit: 0.001, RateBurst: 2})
+		require.Equal(t, http.StatusOK, send(h, "10.0.0.1:5000", "").Code)
+		require.Equal(t, http.StatusOK, send(h, "10.0.0.1:5001", "").Code)
+		rr := send(h, "10.0.0.1:5002", "")
+		require.Equal(t, http.StatusTooManyRequests, rr.Code)
+		require.NotEmpty(t, rr.Header().Get("Retry-After"))
+		// other clients have their own limit
+		require.Equal(t, http.StatusOK, send(h, "10.0.0.2:5000", "").Code)
+	})
+
+	t.Run("admission client key", func(t *testing.T) {
+		t.Parallel()
+		h := LimitHandler(echo, Limits{RateLimit: 0.001, RateBurst: 1, ClientKey: AdmissionClientKey})
+		podinfo := `{"request":{"namespace":"podinfo","userInfo":{"username":"system:serviceaccount:kube-system:replicaset-controller"}}}`
+		rr := send(h, "10.0.0.1:5000", podinfo)
+		require.Equal(t, http.StatusOK, rr.Code)
+		// the next handler still reads the whole body
+		require.Equal(t, podinfo, rr.Body.String())
+		require.Equal(t, http.StatusTooManyRequests, send(h, "10.0.0.1:5000", podinfo).Code)
+		// other namespaces sent from the same API server have their own limit
+		require.Equal(t, http.StatusOK, send(h, "10.0.0.1:5000", `{"request":{"namespace":"gitea"}}`).Code)
+		require.Equal(t, http.StatusOK, send(h, "10.0.0.1:5000", `{"request":{"userInfo":{"username":"admin"}}}`).Code)
+		require.Equal(t, http.StatusTooManyRequests, send(h, "10.0.0.1:5000", `{"request":{"userInfo":{"username":"admin"}}}`).Code)
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		t.Parallel()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		blocking := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		})
+		h := LimitHandler(blocking, Limits{MaxConcurrent: 1, MaxConcurrentWait: 10 * time.Millisecond})
+
+		done := make(chan int)
+		go func() {
+			done <- send(h, "10.0.0.1:5000", "").Code
+		}()
+		<-started
+		require.Equal(t, http.StatusServiceUnavailable, send(h, "10.0.0.2:5000", "").Code)
+		close(release)
+		require.Equal(t, http.StatusOK, <-done)
+	})
+
+	t.Run("concurrency queue", func(t *testing.T) {
+		t.Parallel()
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+		blocking := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			started <- struct{}{}
+			<-release
+			w.WriteHeader(http.StatusOK)
+		})
+		h := LimitHandler(blocking, Limits{MaxConcurrent: 1, MaxConcurrentWait: time.Minute})
+
+		done := make(chan int, 2)
+		go func() {
+			done <- send(h, "10.0.0.1:5000", "").Code
+		}()
+		<-started
+		// the second request waits for the first one to finish instead of being rejected
+		go func() {
+			done <- send(h, "10.0.0.2:5000", "").Code
+		}()
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		require.Equal(t, http.StatusOK, <-done)
+		require.Equal(t, http.StatusOK, <-done)
+	})
+
+	t.Run("no limits", func(t *testing.T) {
+		t.Parallel()
+		h := LimitHandler(echo, Limits{})
+		for range 10 {
+			require.Equal(t, http.StatusOK, send(h, "10.0.0.1:5000", strings.Repeat("a", 1024)).Code)
+		}
+	})
+}
@@ -19,8 +19,9 @@ import (
 	"github.com/zarf-dev/zarf/src/types"
 )
 
-// ProxyHandler constructs a new httputil.ReverseProxy and returns an http handler.
-func ProxyHandler(cluster *cluster.Cluster) http.HandlerFunc {
+// ProxyHandler constructs a new httputil.ReverseProxy and returns an http handler. Upstream responses are served from
+// the cache when it is not nil.
+func ProxyHandler(cluster *cluster.Cluster, cache *ProxyCache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		state, err := cluster.LoadZarfState(r.Context())
 		if err != nil {
@@ -39,6 +40,22 @@ func ProxyHandler(cluster *cluster.Cluster) http.HandlerFunc {
 			return
 		}
 		proxy := &httputil.ReverseProxy{Director: func(_ *http.Request) {}, ModifyResponse: proxyResponseTransform}
+		if cache != nil {
+			if key, ok := cache.key(r); ok {
+				hit := cache.serve(w, key)
+				observeProxyCache(hit)
+				if hit {
+					return
+				}
+				proxy.ModifyResponse = func(resp *http.Response) error {
+					if err := proxyResponseTransform(resp); err != nil {
+						return err
+					}
+					cache.store(resp, key)
+					return nil
+				}
+			}
+		}
 		proxy.ServeHTTP(w, r)
 	}
 }
@@ -78,6 +95,8 @@ func proxyRequestTransform(r *http.Request, state *types.ZarfState) error {
 			targetURL, err = transform.PipTransformURL(state.ArtifactServer.Address, getTLSScheme(r.TLS)+r.Host+r.URL.String())
 		case isNpmUserAgent(r.UserAgent()):
 			targetURL, err = transform.NpmTransformURL(state.ArtifactServer.Address, getTLSScheme(r.TLS)+r.Host+r.URL.String())
+		case isMavenUserAgent(r.UserAgent()):
+			targetURL, err = transform.MavenTransformURL(state.ArtifactServer.Address, getTLSScheme(r.TLS)+r.Host+r.URL.String())
 		default:
 			targetURL, err = transform.GenTransformURL(state.ArtifactServer.Address, getTLSScheme(r.TLS)+r.Host+r.URL.String())
 		}
@@ -106,9 +125,9 @@ func proxyResponseTransform(resp *http.Response) error {
 		resp.Header.Set("Location", locationURL.String())
 	}
 
-	// Handle text content returns that may contain links
+	// Handle text content returns that may contain links (including the object links of Git LFS batch responses)
 	contentType := resp.Header.Get("Content-Type")
-	if strings.HasPrefix(contentType, "text") || strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "application/xml") {
+	if strings.HasPrefix(contentType, "text") || strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "application/xml") || strings.HasPrefix(contentType, "application/vnd.git-lfs+json") {
 		forwardedPrefix := fmt.Sprintf("%s%s%s", getTLSScheme(resp.Request.TLS), resp.Request.Header.Get("X-Forwarded-Host"), transform.NoTransform)
 		targetPrefix := fmt.Sprintf("%s%s", getTLSScheme(resp.TLS), resp.Request.Host)
 		b, err := io.ReadAll(resp.Body)
@@ -158,3 +177,7 @@ func isPipUserAgent(userAgent string) bool {
 func isNpmUserAgent(userAgent string) bool {
 	return strings.HasPrefix(userAgent, "npm") || strings.HasPrefix(userAgent, "pnpm") || strings.HasPrefix(userAgent, "yarn") || strings.HasPrefix(userAgent, "bun")
 }
+
+func isMavenUserAgent(userAgent string) bool {
+	return strings.HasPrefix(userAgent, "Apache-Maven") || strings.HasPrefix(userAgent, "Gradle")
+}
@@ -5,8 +5,10 @@ package http
 
 import (
 	"crypto/tls"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -55,6 +57,25 @@ func TestProxyRequestTransform(t *testing.T) {
 	}
 }
 
+func TestProxyResponseTransformLFS(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "http://zarf-gitea-http.zarf.svc.cluster.local:3000/zarf-git-user/podinfo-1646971829.git/info/lfs/objects/batch", nil)
+	req.Header.Set("X-Forwarded-Host", "github.com")
+	body := `{"objects":[{"oid":"abc","size":1,"actions":{"download":{"href":"http://zarf-gitea-http.zarf.svc.cluster.local:3000/zarf-git-user/podinfo-1646971829.git/info/lfs/objects/abc"}}}]}`
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/vnd.git-lfs+json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+	require.NoError(t, proxyResponseTransform(resp))
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"objects":[{"oid":"abc","size":1,"actions":{"download":{"href":"http://github.com/zarf-3xx-no-transform/zarf-git-user/podinfo-1646971829.git/info/lfs/objects/abc"}}}]}`, string(b))
+}
+
 func TestGetTLSScheme(t *testing.T) {
 	t.Parallel()
 
@@ -117,67 +138,100 @@ func TestUserAgent(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name        string
-		userAgent   string
-		expectedGit bool
-		expectedPip bool
-		expectedNpm bool
+		name          string
+		userAgent     string
+		expectedGit   bool
+		expectedPip   bool
+		expectedNpm   bool
+		expectedMaven bool
 	}{
 		{
-			name:        "unknown user agent",
-			userAgent:   "Firefox",
-			expectedGit: false,
-			expectedPip: false,
-			expectedNpm: false,
+			name:          "unknown user agent",
+			userAgent:     "Firefox",
+			expectedGit:   false,
+			expectedPip:   false,
+			expectedNpm:   false,
+			expectedMaven: false,
+		},
+		{
+			name:          "git user agent",
+			userAgent:     "git/2.0.0",
+			expectedGit:   true,
+			expectedPip:   false,
+			expectedNpm:   false,
+			expectedMaven: false,
+		},
+		{
+			name:          "git lfs user agent",
+			userAgent:     "git-lfs/3.5.1 (GitHub; linux amd64; go 1.22.2)",
+			expectedGit:   true,
+			expectedPip:   false,
+			expectedNpm:   false,
+			expectedMaven: false,
+		},
+		{
+			name:          "pip user agent",
+			userAgent:     "pip/1.2.3",
+			expectedGit:   false,
+			expectedPip:   true,
+			expectedNpm:   false,
+			expectedMaven: false,
 		},
 		{
-			name:        "git user agent",
-			userAgent:   "git/2.0.0",
-			expectedGit: true,
-			expectedPip: false,
-			expectedNpm: false,
+			name:          "twine user agent",
+			userAgent:     "twine/1.8.1",
+			expectedGit:   false,
+			expectedPip:   true,
+			expectedNpm:   false,
+			expectedMaven: false,
 		},
 		{
-			name:        "pip user agent",
-			userAgent:   "pip/1.2.3",
-			expectedGit: false,
-			expectedPip: true,
-			expectedNpm: false,
+			name:          "npm user agent",
+			userAgent:     "npm/1.0.0",
+			expectedGit:   false,
+			expectedPip:   false,
+			expectedNpm:   true,
+			expectedMaven: false,
 		},
 		{
-			name:        "twine user agent",
-			userAgent:   "twine/1.8.1",
-			expectedGit: false,
-			expectedPip: true,
-			expectedNpm: false,
+			name:          "pnpm user agent",
+			userAgent:     "pnpm/1.0.0",
+			expectedGit:   false,
+			expectedPip:   false,
+			expectedNpm:   true,
+			expectedMaven: false,
 		},
 		{
-			name:        "npm user agent",
-			userAgent:   "npm/1.0.0",
-			expectedGit: false,
-			expectedPip: false,
-			expectedNpm: true,
+			name:          "yarn user agent",
+			userAgent:     "yarn/1.0.0",
+			expectedGit:   false,
+			expectedPip:   false,
+			expectedNpm:   true,
+			expectedMaven: false,
 		},
 		{
-			name:        "pnpm user agent",
-			userAgent:   "pnpm/1.0.0",
-			expectedGit: false,
-			expectedPip: false,
-			expectedNpm: true,
+			name:          "bun user agent",
+			userAgent:     "bun/1.0.0",
+			expectedGit:   false,
+			expectedPip:   false,
+			expectedNpm:   true,
+			expectedMaven: false,
 		},
 		{
-			name:        "yarn user agent",
-			userAgent:   "yarn/1.0.0",
-			expectedGit: false,
-			expectedPip: false,
-			expectedNpm: true,
+			name:          "maven user agent",
+			userAgent:     "Apache-Maven/3.9.6 (Java 17.0.10; Linux 6.5.0)",
+			expectedGit:   false,
+			expectedPip:   false,
+			expectedNpm:   false,
+			expectedMaven: true,
 		},
 		{
-			name:        "bun user agent",
-			userAgent:   "bun/1.0.0",
-			expectedGit: false,
-			expectedPip: false,
-			expectedNpm: true,
+			name:          "gradle user agent",
+			userAgent:     "Gradle/8.5 (Linux;6.5.0;amd64) (Eclipse Adoptium;17.0.10;17.0.10+7)",
+			expectedGit:   false,
+			expectedPip:   false,
+			expectedNpm:   false,
+			expectedMaven: true,
 		},
 	}
 	for _, tt := range tests {
@@ -187,6 +241,7 @@ func TestUserAgent(t *testing.T) {
 			require.Equal(t, tt.expectedGit, isGitUserAgent(tt.userAgent))
 			require.Equal(t, tt.expectedPip, isPipUserAgent(tt.userAgent))
 			require.Equal(t, tt.expectedNpm, isNpmUserAgent(tt.userAgent))
+			require.Equal(t, tt.expectedMaven, isMavenUserAgent(tt.userAgent))
 		})
 	}
 }
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package http provides a http server for the webhook and proxy.
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// StatusHandler serves the deploy status that deploys publish to the cluster, for every package or only the package
+// given by the package query parameter.
+func StatusHandler(cluster *cluster.Cluster) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		statuses, err := cluster.GetDeployStatuses(r.Context())
+		if err != nil {
+			message.Debugf("%#v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			//nolint: errcheck // ignore
+			w.Write([]byte("unable to get the deploy status, see the Zarf agent logs for more details"))
+			return
+		}
+		if name := r.URL.Query().Get("package"); name != "" {
+			filtered := []types.DeployStatus{}
+			for _, status := range statuses {
+				if status.Package == name {
+					filtered = append(filtered, status)
+				}
+			}
+			statuses = filtered
+		}
+		w.Header().Set("Content-Type", "application/json")
+		//nolint: errcheck // ignore
+		json.NewEncoder(w).Encode(statuses)
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+	for _, name := range []string{"podinfo", "game"} {
+		require.NoError(t, c.PublishDeployStatus(context.Background(), types.DeployStatus{Package: name, Phase: types.ComponentStatusDeploying}))
+	}
+
+	tests := []struct {
+		name             string
+		method           string
+		target           string
+		expectedCode     int
+		expectedPackages []string
+	}{
+		{
+			name:             "all packages",
+			method:           http.MethodGet,
+			target:           "/status",
+			expectedCode:     http.StatusOK,
+			expectedPackages: []string{"game", "podinfo"},
+		},
+		{
+			name:             "one package",
+			method:           http.MethodGet,
+			target:           "/status?package=podinfo",
+			expectedCode:     http.StatusOK,
+			expectedPackages: []string{"podinfo"},
+		},
+		{
+			name:             "unknown package",
+			method:           http.MethodGet,
+			target:           "/status?package=unknown",
+			expectedCode:     http.StatusOK,
+			expectedPackages: []string{},
+		},
+		{
+			name:         "publishing through the agent is not allowed",
+			method:       http.MethodPost,
+			target:       "/status",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			StatusHandler(c).ServeHTTP(rr, httptest.NewRequest(tt.method, tt.target, nil))
+			require.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			statuses := []types.DeployStatus{}
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&statuses))
+			packages := []string{}
+			for _, status := range statuses {
+				packages = append(packages, status.Package)
+			}
+			require.Equal(t, tt.expectedPackages, packages)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package metrics contains the Prometheus metrics of the Zarf agent.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The results of an admission request.
+const (
+	// ResultMutated is an allowed request that the hook returned patches for.
+	ResultMutated = "mutated"
+	// ResultSkipped is an allowed request that the hook left unchanged.
+	ResultSkipped = "skipped"
+	// ResultDryRun is an allowed request that the hook returned patches for that were not applied.
+	ResultDryRun = "dry-run"
+	// ResultDenied is a request that the hook rejected.
+	ResultDenied = "denied"
+	// ResultErrored is a request that could not be processed.
+	ResultErrored = "errored"
+)
+
+// The reasons a request is rejected before it is handled.
+const (
+	// RejectedBodySize is a request with a body over the size limit.
+	RejectedBodySize = "body-size"
+	// RejectedRateLimit is a request from a client that went over the rate limit.
+	RejectedRateLimit = "rate-limit"
+	// RejectedConcurrency is a request made while the agent was serving the maximum number of concurrent requests.
+	RejectedConcurrency = "concurrency"
+)
+
+// The results of a proxy request looked up in the proxy cache.
+const (
+	// CacheHit is a request served from the proxy cache.
+	CacheHit = "hit"
+	// CacheMiss is a request forwarded upstream.
+	CacheMiss = "miss"
+)
+
+var (
+	started        = time.Now()
+	lastStateFetch atomic.Int64
+
+	admissionRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zarf",
+		Subsystem: "agent",
+		Name:      "admission_requests_total",
+		Help:      "Admission requests handled by each hook of the agent by result (mutated, dry-run, skipped, denied or errored).",
+	}, []string{"hook", "result"})
+	admissionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "zarf",
+		Subsystem: "agent",
+		Name:      "admission_duration_seconds",
+		Help:      "Time taken by each hook of the agent to handle an admission request.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"hook"})
+	rejectedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zarf",
+		Subsystem: "agent",
+		Name:      "rejected_requests_total",
+		Help:      "Requests rejected by the request limits of the agent by reason (body-size, rate-limit or concurrency).",
+	}, []string{"reason"})
+	proxyCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zarf",
+		Subsystem: "agent",
+		Name:      "proxy_cache_requests_total",
+		Help:      "Cacheable requests handled by the proxy by result (hit or miss).",
+	}, []string{"result"})
+	stateFetches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "zarf",
+		Subsystem: "agent",
+		Name:      "state_fetches_total",
+		Help:      "Loads of the Zarf state from the cluster by result (success or error).",
+	}, []string{"result"})
+	stateFetchAge = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "zarf",
+		Subsystem: "agent",
+		Name:      "state_fetch_age_seconds",
+		Help:      "Seconds since the Zarf state was last loaded from the cluster, or since the agent started if it was never loaded.",
+	}, func() float64 {
+		last := started
+		if nanos := lastStateFetch.Load(); nanos != 0 {
+			last = time.Unix(0, nanos)
+		}
+		return time.Since(last).Seconds()
+	})
+)
+
+func init() {
+	prometheus.MustRegister(admissionRequests, admissionDuration, rejectedRequests, proxyCacheRequests, stateFetches, stateFetchAge)
+}
+
+// ObserveAdmission records an admission request handled by a hook.
+func ObserveAdmission(hook, result string, duration time.Duration) {
+	admissionRequests.WithLabelValues(hook, result).Inc()
+	admissionDuration.WithLabelValues(hook).Observe(duration.Seconds())
+}
+
+// ObserveRejectedRequest records a request rejected by the request limits.
+func ObserveRejectedRequest(reason string) {
+	rejectedRequests.WithLabelValues(reason).Inc()
+}
+
+// ObserveProxyCache records a cacheable request handled by the proxy.
+func ObserveProxyCache(result string) {
+	proxyCacheRequests.WithLabelValues(result).Inc()
+}
+
+// ObserveStateFetch records a load of the Zarf state from the cluster.
+func ObserveStateFetch(err error) {
+	if err != nil {
+		stateFetches.WithLabelValues("error").Inc()
+		return
+	}
+	stateFetches.WithLabelValues("success").Inc()
+	lastStateFetch.Store(time.Now().UnixNano())
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveAdmission(t *testing.T) {
+	t.Parallel()
+
+	ObserveAdmission("/mutate/test", ResultMutated, 500*time.Millisecond)
+	ObserveAdmission("/mutate/test", ResultMutated, 250*time.Millisecond)
+	ObserveAdmission("/mutate/test", ResultSkipped, 125*time.Millisecond)
+
+	require.InDelta(t, 2, testutil.ToFloat64(admissionRequests.WithLabelValues("/mutate/test", ResultMutated)), 0)
+	require.InDelta(t, 1, testutil.ToFloat64(admissionRequests.WithLabelValues("/mutate/test", ResultSkipped)), 0)
+	require.InDelta(t, 0, testutil.ToFloat64(admissionRequests.WithLabelValues("/mutate/test", ResultErrored)), 0)
+
+	expected := `
+# HELP zarf_agent_admission_duration_seconds Time taken by each hook of the agent to handle an admission request.
+# TYPE zarf_agent_admission_duration_seconds histogram
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="0.005"} 0
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="0.01"} 0
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="0.025"} 0
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="0.05"} 0
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="0.1"} 0
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="0.25"} 2
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="0.5"} 3
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="1"} 3
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="2.5"} 3
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="5"} 3
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="10"} 3
+zarf_agent_admission_duration_seconds_bucket{hook="/mutate/test",le="+Inf"} 3
+zarf_agent_admission_duration_seconds_sum{hook="/mutate/test"} 0.875
+zarf_agent_admission_duration_seconds_count{hook="/mutate/test"} 3
+`
+	require.NoError(t, testutil.CollectAndCompare(admissionDuration, strings.NewReader(expected)))
+}
+
+func TestObserveStateFetch(t *testing.T) {
+	t.Parallel()
+
+	ObserveStateFetch(errors.New("unable to load the state"))
+	require.InDelta(t, 1, testutil.ToFloat64(stateFetches.WithLabelValues("error")), 0)
+	require.InDelta(t, 0, testutil.ToFloat64(stateFetches.WithLabelValues("success")), 0)
+	require.Zero(t, lastStateFetch.Load())
+
+	ObserveStateFetch(nil)
+	require.InDelta(t, 1, testutil.ToFloat64(stateFetches.WithLabelValues("success")), 0)
+	require.Less(t, testutil.ToFloat64(stateFetchAge), 1.0)
+}
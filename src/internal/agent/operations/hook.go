@@ -16,6 +16,10 @@ type Result struct {
 	Allowed  bool
 	Msg      string
 	PatchOps []PatchOperation
+	// Warnings returned to the client that made the request
+	Warnings []string
+	// DryRun reports the patch operations without applying them to the resource
+	DryRun bool
 }
 
 // AdmitFunc defines how to process an admission request.
@@ -6,6 +6,7 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/audit"
 	"github.com/zarf-dev/zarf/src/internal/agent/hooks"
 	agentHttp "github.com/zarf-dev/zarf/src/internal/agent/http"
 	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
@@ -30,18 +32,37 @@ const (
 	httpPort = "8443"
 	tlsCert  = "/etc/certs/tls.crt"
 	tlsKey   = "/etc/certs/tls.key"
+
+	agentConfigSyncInterval = 10 * time.Second
+	// The cached Zarf state is refreshed in the background after stateRefreshAfter and is no longer served after
+	// stateMaxAge, when the API server could not be reached in the meantime
+	stateRefreshAfter = 30 * time.Second
+	stateMaxAge       = 5 * time.Minute
 )
 
-// StartWebhook launches the Zarf agent mutating webhook in the cluster.
-func StartWebhook(ctx context.Context, cluster *cluster.Cluster) error {
+// StartWebhook launches the Zarf agent mutating webhook in the cluster, recording every mutation to the audit log.
+func StartWebhook(ctx context.Context, cluster *cluster.Cluster, auditLog *audit.Logger, limits agentHttp.Limits) error {
+	stateCache := hooks.NewStateCache(cluster, stateRefreshAfter, stateMaxAge)
+	limits.ClientKey = agentHttp.AdmissionClientKey
+	agentConfig := hooks.NewAgentConfigStore()
+	if err := agentConfig.Reload(ctx, cluster); err != nil {
+		message.Warnf(lang.AgentWarnReloadConfig, err)
+	}
+
 	// Routers
-	admissionHandler := admission.NewHandler()
-	podsMutation := hooks.NewPodMutationHook(ctx, cluster)
-	fluxGitRepositoryMutation := hooks.NewGitRepositoryMutationHook(ctx, cluster)
-	argocdApplicationMutation := hooks.NewApplicationMutationHook(ctx, cluster)
-	argocdRepositoryMutation := hooks.NewRepositorySecretMutationHook(ctx, cluster)
-	fluxHelmRepositoryMutation := hooks.NewHelmRepositoryMutationHook(ctx, cluster)
-	fluxOCIRepositoryMutation := hooks.NewOCIRepositoryMutationHook(ctx, cluster)
+	admissionHandler := admission.NewHandler().WithAuditLog(auditLog)
+	podsMutation := agentConfig.Wrap(hooks.NewPodMutationHook(ctx, cluster))
+	fluxGitRepositoryMutation := agentConfig.Wrap(hooks.NewGitRepositoryMutationHook(ctx, cluster))
+	argocdApplicationMutation := agentConfig.Wrap(hooks.NewApplicationMutationHook(ctx, cluster))
+	argocdApplicationSetMutation := agentConfig.Wrap(hooks.NewApplicationSetMutationHook(ctx, cluster))
+	argocdRepositoryMutation := agentConfig.Wrap(hooks.NewRepositorySecretMutationHook(ctx, cluster))
+	fluxHelmRepositoryMutation := agentConfig.Wrap(hooks.NewHelmRepositoryMutationHook(ctx, cluster))
+	fluxOCIRepositoryMutation := agentConfig.Wrap(hooks.NewOCIRepositoryMutationHook(ctx, cluster))
+	fluxHelmReleaseMutation := agentConfig.Wrap(hooks.NewHelmReleaseMutationHook(ctx, cluster))
+	tektonTaskRunMutation := agentConfig.Wrap(hooks.NewTaskRunMutationHook(ctx, cluster))
+	tektonPipelineRunMutation := agentConfig.Wrap(hooks.NewPipelineRunMutationHook(ctx, cluster))
+	customResourceMutation := agentConfig.Wrap(hooks.NewCustomResourceMutationHook(ctx, cluster))
+	podsValidation := agentConfig.Wrap(hooks.NewPodValidationHook(ctx, cluster))
 
 	// Routers
 	mux := http.NewServeMux()
@@ -49,35 +70,78 @@ func StartWebhook(ctx context.Context, cluster *cluster.Cluster) error {
 	mux.Handle("/mutate/flux-gitrepository", admissionHandler.Serve(fluxGitRepositoryMutation))
 	mux.Handle("/mutate/flux-helmrepository", admissionHandler.Serve(fluxHelmRepositoryMutation))
 	mux.Handle("/mutate/flux-ocirepository", admissionHandler.Serve(fluxOCIRepositoryMutation))
+	mux.Handle("/mutate/flux-helmrelease", admissionHandler.Serve(fluxHelmReleaseMutation))
 	mux.Handle("/mutate/argocd-application", admissionHandler.Serve(argocdApplicationMutation))
+	mux.Handle("/mutate/argocd-applicationset", admissionHandler.Serve(argocdApplicationSetMutation))
 	mux.Handle("/mutate/argocd-repository", admissionHandler.Serve(argocdRepositoryMutation))
+	mux.Handle("/mutate/tekton-taskrun", admissionHandler.Serve(tektonTaskRunMutation))
+	mux.Handle("/mutate/tekton-pipelinerun", admissionHandler.Serve(tektonPipelineRunMutation))
+	mux.Handle("/mutate/custom-resource", admissionHandler.Serve(customResourceMutation))
+	mux.Handle("/validate/pod", admissionHandler.Serve(podsValidation))
+	mux.Handle("/status", agentHttp.StatusHandler(cluster))
 
-	return startServer(ctx, httpPort, mux)
+	certs := &certificateStore{}
+	go syncAgentConfig(ctx, cluster, agentConfig)
+	go stateCache.Run(ctx)
+	go rotateCertificate(ctx, cluster, certs)
+
+	return startServer(ctx, httpPort, mux, limits, certs.GetCertificate)
 }
 
-// StartHTTPProxy launches the zarf agent proxy in the cluster.
-func StartHTTPProxy(ctx context.Context, cluster *cluster.Cluster) error {
+// syncAgentConfig reloads the agent configuration and keeps the resources sent to the custom resource hook in line with
+// the image rules until the context is cancelled.
+func syncAgentConfig(ctx context.Context, cluster *cluster.Cluster, agentConfig *hooks.AgentConfigStore) {
+	ticker := time.NewTicker(agentConfigSyncInterval)
+	defer ticker.Stop()
+	for {
+		if err := hooks.SyncCustomResourceWebhook(ctx, cluster); err != nil {
+			message.Warnf(lang.AgentWarnSyncImageRules, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := agentConfig.Reload(ctx, cluster); err != nil {
+			message.Warnf(lang.AgentWarnReloadConfig, err)
+		}
+	}
+}
+
+// StartHTTPProxy launches the zarf agent proxy in the cluster, serving repeated upstream fetches from the cache when it
+// is not nil.
+func StartHTTPProxy(ctx context.Context, cluster *cluster.Cluster, limits agentHttp.Limits, cache *agentHttp.ProxyCache) error {
 	mux := http.NewServeMux()
-	mux.Handle("/", agentHttp.ProxyHandler(cluster))
-	return startServer(ctx, httpPort, mux)
+	mux.Handle("/", agentHttp.ProxyHandler(cluster, cache))
+	mux.Handle(agentHttp.GoModuleProxyPrefix, agentHttp.GoModuleProxyHandler(cluster))
+	return startServer(ctx, httpPort, mux, limits, nil)
 }
 
-func startServer(ctx context.Context, port string, mux *http.ServeMux) error {
-	mux.Handle("/metrics", promhttp.Handler())
-	mux.Handle("/healthz", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+// startServer serves the mux over TLS with the certificate returned by getCertificate, or the mounted certificate if it
+// is nil. The limits apply to every request but the metrics and health checks.
+func startServer(ctx context.Context, port string, mux *http.ServeMux, limits agentHttp.Limits, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) error {
+	root := http.NewServeMux()
+	root.Handle("/", agentHttp.LimitHandler(mux, limits))
+	root.Handle("/metrics", promhttp.Handler())
+	root.Handle("/healthz", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		//nolint: errcheck // ignore
 		w.Write([]byte("ok"))
 	}))
 	srv := &http.Server{
 		Addr:              fmt.Sprintf(":%s", port),
-		Handler:           mux,
+		Handler:           root,
 		ReadHeaderTimeout: 5 * time.Second, // Set ReadHeaderTimeout to avoid Slowloris attacks
 	}
+	certFile, keyFile := tlsCert, tlsKey
+	if getCertificate != nil {
+		srv.TLSConfig = &tls.Config{GetCertificate: getCertificate, MinVersion: tls.VersionTLS12}
+		certFile, keyFile = "", ""
+	}
 
 	g, gCtx := errgroup.WithContext(ctx)
 	g.Go(func() error {
-		err := srv.ListenAndServeTLS(tlsCert, tlsKey)
+		err := srv.ListenAndServeTLS(certFile, keyFile)
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			return err
 		}
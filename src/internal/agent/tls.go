@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package agent holds the mutating webhook server.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+const (
+	// The agent certificate is valid for a little over a year, renew it well before it expires.
+	certRenewBefore   = 30 * 24 * time.Hour
+	certCheckInterval = time.Hour
+	certRetryInterval = time.Minute
+)
+
+// certificateStore holds the certificate served by the agent so that it can be rotated without a restart.
+type certificateStore struct {
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	certPEM []byte
+}
+
+// GetCertificate returns the current certificate, falling back to the mounted certificate until one has been loaded
+// from the cluster.
+func (s *certificateStore) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.mu.RLock()
+	cert := s.cert
+	s.mu.RUnlock()
+	if cert != nil {
+		return cert, nil
+	}
+	mounted, err := tls.LoadX509KeyPair(tlsCert, tlsKey)
+	if err != nil {
+		return nil, err
+	}
+	return &mounted, nil
+}
+
+// set replaces the served certificate if it changed.
+func (s *certificateStore) set(certPEM, keyPEM []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cert != nil && bytes.Equal(s.certPEM, certPEM) {
+		return nil
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	s.cert = &cert
+	s.certPEM = certPEM
+	return nil
+}
+
+// rotateCertificate renews the agent certificate before it expires and serves the certificate in the Zarf state until
+// the context is cancelled.
+func rotateCertificate(ctx context.Context, cluster *cluster.Cluster, certs *certificateStore) {
+	for {
+		wait := certCheckInterval
+		agentTLS, err := cluster.RenewAgentTLS(ctx, certRenewBefore)
+		if err == nil {
+			err = certs.set(agentTLS.Cert, agentTLS.Key)
+		}
+		if err != nil {
+			message.Warnf(lang.AgentWarnRotateCertificate, err)
+			wait = certRetryInterval
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
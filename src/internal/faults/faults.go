@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package faults injects failures at defined points of the deploy flow so that retries can be tested deterministically.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// EnvVar is the environment variable that configures the faults to inject, e.g. "registry-push=error:2,chart-install=timeout".
+const EnvVar = "ZARF_FAULTS"
+
+// Point is a place in the deploy flow where a fault can be injected.
+type Point string
+
+// The points in the deploy flow where a fault can be injected.
+const (
+	RegistryPush  Point = "registry-push"
+	GitPush       Point = "git-push"
+	ChartInstall  Point = "chart-install"
+	TunnelConnect Point = "tunnel-connect"
+)
+
+// Kind is the kind of failure that is injected.
+type Kind string
+
+// The kinds of failure that can be injected.
+const (
+	Error   Kind = "error"
+	Timeout Kind = "timeout"
+	Drop    Kind = "drop"
+)
+
+// ErrInjected is wrapped by every error returned for an injected fault.
+var ErrInjected = errors.New("injected fault")
+
+var (
+	points = []Point{RegistryPush, GitPush, ChartInstall, TunnelConnect}
+	kinds  = []Kind{Error, Timeout, Drop}
+)
+
+// fault is a failure injected at a point, remaining is the number of times it is still injected or -1 for every time.
+type fault struct {
+	kind      Kind
+	remaining int
+}
+
+// Injector returns the configured faults when their points are reached.
+type Injector struct {
+	mu     sync.Mutex
+	faults map[Point]*fault
+}
+
+// Parse parses a comma separated list of point=kind[:count] faults, the count defaults to 1 and * injects the fault
+// every time the point is reached.
+func Parse(spec string) (*Injector, error) {
+	inj := &Injector{faults: map[Point]*fault{}}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		point, rest, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("fault %q must be in the form point=kind[:count]", entry)
+		}
+		if !slices.Contains(points, Point(point)) {
+			return nil, fmt.Errorf("unknown fault point %q, must be one of %v", point, points)
+		}
+		kind, count, hasCount := strings.Cut(rest, ":")
+		if !slices.Contains(kinds, Kind(kind)) {
+			return nil, fmt.Errorf("unknown fault kind %q, must be one of %v", kind, kinds)
+		}
+		remaining := 1
+		if hasCount {
+			if count == "*" {
+				remaining = -1
+			} else {
+				n, err := strconv.Atoi(count)
+				if err != nil || n < 1 {
+					return nil, fmt.Errorf("fault count %q must be a positive number or *", count)
+				}
+				remaining = n
+			}
+		}
+		inj.faults[Point(point)] = &fault{kind: Kind(kind), remaining: remaining}
+	}
+	return inj, nil
+}
+
+// Check returns the error of the fault configured for the point, or nil once the fault has been injected count times.
+func (inj *Injector) Check(point Point) error {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	f, ok := inj.faults[point]
+	if !ok || f.remaining == 0 {
+		return nil
+	}
+	if f.remaining > 0 {
+		f.remaining--
+	}
+	message.Debugf("Injecting a %s fault at %s", f.kind, point)
+
+	switch f.kind {
+	case Timeout:
+		return fmt.Errorf("%w: %s: %w", ErrInjected, point, context.DeadlineExceeded)
+	case Drop:
+		return fmt.Errorf("%w: %s: %w", ErrInjected, point, &net.OpError{Op: "write", Net: "tcp", Err: syscall.ECONNRESET})
+	default:
+		return fmt.Errorf("%w: %s failed", ErrInjected, point)
+	}
+}
+
+var (
+	defaultOnce     sync.Once
+	defaultInjector *Injector
+)
+
+// Inject returns the error of the fault configured with ZARF_FAULTS for the point, or nil if there is none.
+func Inject(point Point) error {
+	defaultOnce.Do(func() {
+		spec := os.Getenv(EnvVar)
+		if spec == "" {
+			return
+		}
+		inj, err := Parse(spec)
+		if err != nil {
+			message.Warnf("Ignoring %s: %s", EnvVar, err)
+			return
+		}
+		message.Warnf("Fault injection is enabled with %s=%s, this must not be used outside of testing", EnvVar, spec)
+		defaultInjector = inj
+	})
+	if defaultInjector == nil {
+		return nil
+	}
+	return defaultInjector.Check(point)
+}
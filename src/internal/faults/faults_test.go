@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package faults
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		spec        string
+		expectedErr string
+	}{
+		{
+			name: "empty",
+			spec: "",
+		},
+		{
+			name: "multiple faults",
+			spec: "registry-push=error:2, chart-install=timeout,git-push=drop:*",
+		},
+		{
+			name:        "missing kind",
+			spec:        "registry-push",
+			expectedErr: `fault "registry-push" must be in the form point=kind[:count]`,
+		},
+		{
+			name:        "unknown point",
+			spec:        "image-pull=error",
+			expectedErr: `unknown fault point "image-pull", must be one of [registry-push git-push chart-install tunnel-connect]`,
+		},
+		{
+			name:        "unknown kind",
+			spec:        "git-push=panic",
+			expectedErr: `unknown fault kind "panic", must be one of [error timeout drop]`,
+		},
+		{
+			name:        "invalid count",
+			spec:        "git-push=drop:0",
+			expectedErr: `fault count "0" must be a positive number or *`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.spec)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	inj, err := Parse("registry-push=error:2,chart-install=timeout,git-push=drop:*")
+	require.NoError(t, err)
+
+	// Faults are injected the configured number of times.
+	for range 2 {
+		require.ErrorIs(t, inj.Check(RegistryPush), ErrInjected)
+	}
+	require.NoError(t, inj.Check(RegistryPush))
+
+	err = inj.Check(ChartInstall)
+	require.ErrorIs(t, err, ErrInjected)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NoError(t, inj.Check(ChartInstall))
+
+	for range 5 {
+		err = inj.Check(GitPush)
+		var opErr *net.OpError
+		require.ErrorAs(t, err, &opErr)
+	}
+
+	require.NoError(t, inj.Check(TunnelConnect))
+}
@@ -0,0 +1,428 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+const (
+	lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+	// LFS pointer files are small, anything larger is regular content
+	lfsPointerMaxSize = 1024
+	lfsMediaType      = "application/vnd.git-lfs+json"
+	// The LFS batch API recommends sending at most 100 objects per request
+	lfsBatchSize = 100
+)
+
+var lfsOIDRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// lfsObject is an object stored in Git LFS.
+type lfsObject struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type lfsAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+type lfsBatchRequest struct {
+	Operation string      `json:"operation"`
+	Transfers []string    `json:"transfers"`
+	Objects   []lfsObject `json:"objects"`
+}
+
+type lfsBatchObject struct {
+	lfsObject
+	Actions map[string]lfsAction `json:"actions,omitempty"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type lfsBatchResponse struct {
+	Objects []lfsBatchObject `json:"objects"`
+}
+
+// parseLFSPointer returns the object an LFS pointer file points to.
+func parseLFSPointer(content []byte) (lfsObject, bool) {
+	if len(content) > lfsPointerMaxSize || !bytes.HasPrefix(content, []byte(lfsPointerVersion+"\n")) {
+		return lfsObject{}, false
+	}
+	obj := lfsObject{Size: -1}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			return lfsObject{}, false
+		}
+		switch key {
+		case "oid":
+			obj.OID = strings.TrimPrefix(value, "sha256:")
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return lfsObject{}, false
+			}
+			obj.Size = size
+		}
+	}
+	if !lfsOIDRegex.MatchString(obj.OID) || obj.Size < 0 {
+		return lfsObject{}, false
+	}
+	return obj, true
+}
+
+// lfsPointers returns the LFS objects pointed to by the files at the tip of every branch and tag of the repository.
+func lfsPointers(repo *git.Repository) ([]lfsObject, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	trees := map[plumbing.Hash]bool{}
+	objects := []lfsObject{}
+	seen := map[string]bool{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		commit, err := repo.CommitObject(ref.Hash())
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			// Annotated tags point to a tag object rather than a commit
+			tag, tagErr := repo.TagObject(ref.Hash())
+			if tagErr != nil {
+				return nil
+			}
+			commit, err = tag.Commit()
+		}
+		if err != nil {
+			return nil
+		}
+		if trees[commit.TreeHash] {
+			return nil
+		}
+		trees[commit.TreeHash] = true
+		tree, err := commit.Tree()
+		if err != nil {
+			return err
+		}
+		return tree.Files().ForEach(func(f *object.File) error {
+			if f.Size > lfsPointerMaxSize || !f.Mode.IsFile() {
+				return nil
+			}
+			contents, err := f.Contents()
+			if err != nil {
+				return err
+			}
+			obj, ok := parseLFSPointer([]byte(contents))
+			if !ok || seen[obj.OID] {
+				return nil
+			}
+			seen[obj.OID] = true
+			objects = append(objects, obj)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// lfsObjectPath returns the path an LFS object is stored at within a repository, the same location git-lfs uses.
+func lfsObjectPath(repoPath, oid string) string {
+	return filepath.Join(repoPath, ".git", "lfs", "objects", oid[0:2], oid[2:4], oid)
+}
+
+// lfsEndpoint returns the LFS server URL of a git remote.
+func lfsEndpoint(remoteURL string) (*url.URL, error) {
+	endpoint, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/")
+	if !strings.HasSuffix(endpoint.Path, ".git") {
+		endpoint.Path += ".git"
+	}
+	endpoint.Path += "/info/lfs"
+	return endpoint, nil
+}
+
+// fetchLFSObjects downloads the LFS objects referenced by the repository into it so that they are packaged with it.
+func (r *Repository) fetchLFSObjects(ctx context.Context, remoteURL string, auth *githttp.BasicAuth) error {
+	repo, err := git.PlainOpen(r.path)
+	if err != nil {
+		return fmt.Errorf("not a valid git repo or unable to open: %w", err)
+	}
+	pointers, err := lfsPointers(repo)
+	if err != nil {
+		return fmt.Errorf("unable to find the LFS objects in the repo: %w", err)
+	}
+	missing := []lfsObject{}
+	for _, obj := range pointers {
+		if _, err := os.Stat(lfsObjectPath(r.path, obj.OID)); err != nil {
+			missing = append(missing, obj)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	endpoint, err := lfsEndpoint(remoteURL)
+	if err != nil {
+		return err
+	}
+	message.Debugf("Downloading %d LFS objects from %s", len(missing), endpoint.Redacted())
+	for i := 0; i < len(missing); i += lfsBatchSize {
+		batch := missing[i:min(i+lfsBatchSize, len(missing))]
+		resp, err := lfsBatch(ctx, endpoint, "download", batch, auth)
+		if err != nil {
+			return err
+		}
+		for _, obj := range resp.Objects {
+			if obj.Error != nil {
+				return fmt.Errorf("unable to download the LFS object %s: %s", obj.OID, obj.Error.Message)
+			}
+			action, ok := obj.Actions["download"]
+			if !ok {
+				return fmt.Errorf("the LFS server did not return a download for the object %s", obj.OID)
+			}
+			if err := downloadLFSObject(ctx, endpoint, action, auth, obj.lfsObject, lfsObjectPath(r.path, obj.OID)); err != nil {
+				return fmt.Errorf("unable to download the LFS object %s: %w", obj.OID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// pushLFSObjects uploads the LFS objects stored in the repository to the LFS server of the remote.
+func (r *Repository) pushLFSObjects(ctx context.Context, remoteURL string, auth *githttp.BasicAuth) error {
+	objects := []lfsObject{}
+	objectsDir := filepath.Join(r.path, ".git", "lfs", "objects")
+	err := filepath.WalkDir(objectsDir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !lfsOIDRegex.MatchString(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, lfsObject{OID: d.Name(), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to find the LFS objects in the repo: %w", err)
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+
+	endpoint, err := lfsEndpoint(remoteURL)
+	if err != nil {
+		return err
+	}
+	message.Debugf("Uploading %d LFS objects to %s", len(objects), endpoint.Redacted())
+	for i := 0; i < len(objects); i += lfsBatchSize {
+		batch := objects[i:min(i+lfsBatchSize, len(objects))]
+		resp, err := lfsBatch(ctx, endpoint, "upload", batch, auth)
+		if err != nil {
+			return err
+		}
+		for _, obj := range resp.Objects {
+			if obj.Error != nil {
+				return fmt.Errorf("unable to upload the LFS object %s: %s", obj.OID, obj.Error.Message)
+			}
+			// The server already has the objects it returns no upload for
+			action, ok := obj.Actions["upload"]
+			if !ok {
+				continue
+			}
+			if err := uploadLFSObject(ctx, endpoint, action, auth, lfsObjectPath(r.path, obj.OID)); err != nil {
+				return fmt.Errorf("unable to upload the LFS object %s: %w", obj.OID, err)
+			}
+			if verify, ok := obj.Actions["verify"]; ok {
+				body, err := json.Marshal(obj.lfsObject)
+				if err != nil {
+					return err
+				}
+				resp, err := doLFSAction(ctx, http.MethodPost, endpoint, verify, auth, lfsMediaType, bytes.NewReader(body), int64(len(body)))
+				if err != nil {
+					return fmt.Errorf("unable to verify the LFS object %s: %w", obj.OID, err)
+				}
+				resp.Body.Close()
+			}
+		}
+	}
+	return nil
+}
+
+// lfsBatch requests the actions to transfer objects from the LFS batch API.
+func lfsBatch(ctx context.Context, endpoint *url.URL, operation string, objects []lfsObject, auth *githttp.BasicAuth) (*lfsBatchResponse, error) {
+	body, err := json.Marshal(lfsBatchRequest{
+		Operation: operation,
+		Transfers: []string{"basic"},
+		Objects:   objects,
+	})
+	if err != nil {
+		return nil, err
+	}
+	batchURL := endpoint.JoinPath("objects", "batch")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, batchURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	if auth != nil {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("LFS %s request to %s failed with status %s, is LFS enabled on the git server?", operation, batchURL.Redacted(), resp.Status)
+	}
+	batch := &lfsBatchResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(batch); err != nil {
+		return nil, fmt.Errorf("unable to parse the LFS %s response from %s: %w", operation, batchURL.Redacted(), err)
+	}
+	return batch, nil
+}
+
+// downloadLFSObject downloads an object to the given path, verifying its size and checksum.
+func downloadLFSObject(ctx context.Context, endpoint *url.URL, action lfsAction, auth *githttp.BasicAuth, obj lfsObject, path string) error {
+	resp, err := doLFSAction(ctx, http.MethodGet, endpoint, action, auth, "", nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Download next to the object first so that an interrupted download is never mistaken for the object
+	tmp, err := os.CreateTemp(filepath.Dir(path), obj.OID+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body)
+	if err != nil {
+		return err
+	}
+	if size != obj.Size {
+		return fmt.Errorf("expected %d bytes but got %d", obj.Size, size)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != obj.OID {
+		return fmt.Errorf("checksum mismatch, got sha256:%s", sum)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// uploadLFSObject uploads the object stored at the given path.
+func uploadLFSObject(ctx context.Context, endpoint *url.URL, action lfsAction, auth *githttp.BasicAuth, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	resp, err := doLFSAction(ctx, http.MethodPut, endpoint, action, auth, "application/octet-stream", f, info.Size())
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// doLFSAction sends the request of a transfer action returned by the LFS batch API.
+func doLFSAction(ctx context.Context, method string, endpoint *url.URL, action lfsAction, auth *githttp.BasicAuth, contentType string, body io.Reader, size int64) (*http.Response, error) {
+	target, err := lfsActionURL(endpoint, action.Href)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for key, value := range action.Header {
+		req.Header.Set(key, value)
+	}
+	// Objects served by the git server itself use the git credentials unless the action brings its own
+	if auth != nil && req.Header.Get("Authorization") == "" && target.Host == endpoint.Host {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s failed with status %s", method, target.Redacted(), resp.Status)
+	}
+	return resp, nil
+}
+
+// lfsActionURL returns the URL of a transfer action.
+//
+// A git server returns the objects it stores under the URL it knows itself by, which is not reachable when it is accessed
+// through a tunnel, so these are sent to the host of the LFS endpoint instead.
+func lfsActionURL(endpoint *url.URL, href string) (*url.URL, error) {
+	target, err := url.Parse(href)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LFS action URL: %w", err)
+	}
+	repoPath := strings.TrimSuffix(endpoint.Path, "/info/lfs")
+	if strings.HasPrefix(target.Path, repoPath+"/") {
+		target.Scheme = endpoint.Scheme
+		target.Host = endpoint.Host
+	}
+	return target, nil
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fluxcd/gitkit"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestParseLFSPointer(t *testing.T) {
+	t.Parallel()
+
+	oid := strings.Repeat("a", 64)
+	tests := []struct {
+		name     string
+		content  string
+		expected lfsObject
+		ok       bool
+	}{
+		{
+			name:     "pointer",
+			content:  fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize 12345\n", oid),
+			expected: lfsObject{OID: oid, Size: 12345},
+			ok:       true,
+		},
+		{
+			name:     "pointer with extensions",
+			content:  fmt.Sprintf("version https://git-lfs.github.com/spec/v1\next-0-foo sha256:%s\noid sha256:%s\nsize 0\n", oid, oid),
+			expected: lfsObject{OID: oid, Size: 0},
+			ok:       true,
+		},
+		{
+			name:    "regular file",
+			content: "Hello World",
+		},
+		{
+			name:    "invalid oid",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize 1\n",
+		},
+		{
+			name:    "missing size",
+			content: fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\n", oid),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			obj, ok := parseLFSPointer([]byte(tt.content))
+			require.Equal(t, tt.ok, ok)
+			if tt.ok {
+				require.Equal(t, tt.expected, obj)
+			}
+		})
+	}
+}
+
+func TestLFS(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	content := []byte("large binary content")
+	sum := sha256.Sum256(content)
+	oid := hex.EncodeToString(sum[:])
+	pointer := fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, len(content))
+
+	srcSrv, srcObjects := newTestLFSServer(t)
+	srcObjects.Store(oid, content)
+	initRepo, err := git.Init(memory.NewStorage(), memfs.New())
+	require.NoError(t, err)
+	w, err := initRepo.Worktree()
+	require.NoError(t, err)
+	f, err := w.Filesystem.Create("large.bin")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(pointer))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	_, err = w.Add("large.bin")
+	require.NoError(t, err)
+	_, err = w.Commit("Add large file", &git.CommitOptions{Author: &object.Signature{Email: "example@example.com"}})
+	require.NoError(t, err)
+	_, err = initRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{fmt.Sprintf("%s/test.git", srcSrv.URL)}})
+	require.NoError(t, err)
+	require.NoError(t, initRepo.Push(&git.PushOptions{RemoteName: "origin"}))
+
+	// The LFS objects are stored with the cloned repo
+	repo, err := Clone(ctx, t.TempDir(), fmt.Sprintf("%s/test.git", srcSrv.URL), false)
+	require.NoError(t, err)
+	b, err := os.ReadFile(lfsObjectPath(repo.Path(), oid))
+	require.NoError(t, err)
+	require.Equal(t, content, b)
+
+	// The LFS objects are uploaded with the pushed repo
+	dstSrv, dstObjects := newTestLFSServer(t)
+	// Push fetches from the remote first, which fails on the empty repo the test server creates
+	dstURL, err := transform.GitURL(dstSrv.URL, fmt.Sprintf("%s/test.git", srcSrv.URL), "push-user")
+	require.NoError(t, err)
+	_, err = initRepo.CreateRemote(&config.RemoteConfig{Name: "target", URLs: []string{dstURL.String()}})
+	require.NoError(t, err)
+	require.NoError(t, initRepo.Push(&git.PushOptions{RemoteName: "target"}))
+	require.NoError(t, repo.Push(ctx, dstSrv.URL, "push-user", "push-password"))
+	stored, ok := dstObjects.Load(oid)
+	require.True(t, ok)
+	require.Equal(t, content, stored)
+
+	// A corrupted object is rejected
+	srcObjects.Store(oid, []byte("something else"))
+	_, err = Clone(ctx, t.TempDir(), fmt.Sprintf("%s/test.git", srcSrv.URL), false)
+	require.ErrorContains(t, err, fmt.Sprintf("unable to download the LFS object %s", oid))
+}
+
+// newTestLFSServer starts a git server that also serves the LFS objects it stores keyed by oid.
+//
+// The object links it returns use a different host than the server, like a git server behind a tunnel.
+func newTestLFSServer(t *testing.T) (*httptest.Server, *sync.Map) {
+	t.Helper()
+
+	gitSrv := gitkit.New(gitkit.Config{
+		Dir:        t.TempDir(),
+		AutoCreate: true,
+	})
+	require.NoError(t, gitSrv.Setup())
+	objects := &sync.Map{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repoPath, lfsPath, ok := strings.Cut(r.URL.Path, "/info/lfs/objects/")
+		if !ok {
+			gitSrv.ServeHTTP(w, r)
+			return
+		}
+		switch {
+		case lfsPath == "batch":
+			batch := lfsBatchRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			resp := lfsBatchResponse{}
+			for _, obj := range batch.Objects {
+				action := lfsAction{Href: fmt.Sprintf("http://git.internal%s/info/lfs/objects/%s", repoPath, obj.OID)}
+				_, stored := objects.Load(obj.OID)
+				switch {
+				case batch.Operation == "download" && stored:
+					resp.Objects = append(resp.Objects, lfsBatchObject{lfsObject: obj, Actions: map[string]lfsAction{"download": action}})
+				case batch.Operation == "upload" && !stored:
+					resp.Objects = append(resp.Objects, lfsBatchObject{lfsObject: obj, Actions: map[string]lfsAction{"upload": action}})
+				default:
+					resp.Objects = append(resp.Objects, lfsBatchObject{lfsObject: obj})
+				}
+			}
+			w.Header().Set("Content-Type", lfsMediaType)
+			//nolint:errcheck // ignore
+			json.NewEncoder(w).Encode(resp)
+		case r.Method == http.MethodGet:
+			b, ok := objects.Load(lfsPath)
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			//nolint:errcheck // ignore
+			w.Write(b.([]byte))
+		case r.Method == http.MethodPut:
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			objects.Store(lfsPath, b)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, objects
+}
+
+func TestLFSActionURL(t *testing.T) {
+	t.Parallel()
+
+	endpoint, err := lfsEndpoint("http://127.0.0.1:41234/zarf-git-user/podinfo-1646971829.git")
+	require.NoError(t, err)
+	require.Equal(t, "http://127.0.0.1:41234/zarf-git-user/podinfo-1646971829.git/info/lfs", endpoint.String())
+
+	// Links to the git server itself are sent through the endpoint host
+	target, err := lfsActionURL(endpoint, "http://zarf-gitea-http.zarf.svc.cluster.local:3000/zarf-git-user/podinfo-1646971829.git/info/lfs/objects/abc")
+	require.NoError(t, err)
+	require.Equal(t, "http://127.0.0.1:41234/zarf-git-user/podinfo-1646971829.git/info/lfs/objects/abc", target.String())
+
+	// Links to other storage are kept
+	target, err = lfsActionURL(endpoint, "https://objects.example.com/abc?signature=xyz")
+	require.NoError(t, err)
+	require.Equal(t, "https://objects.example.com/abc?signature=xyz", target.String())
+
+	endpoint, err = lfsEndpoint("https://github.com/stefanprodan/podinfo/")
+	require.NoError(t, err)
+	require.Equal(t, "https://github.com/stefanprodan/podinfo.git/info/lfs", endpoint.String())
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -131,9 +132,139 @@ func Clone(ctx context.Context, rootPath, address string, shallow bool) (*Reposi
 		}
 	}
 
+	// Store the LFS objects with the repo as they can not be fetched from the remote once in the airgap
+	var lfsAuth *http.BasicAuth
+	if gitCred != nil {
+		lfsAuth = &gitCred.Auth
+	}
+	if err := r.fetchLFSObjects(ctx, gitURLNoRef, lfsAuth); err != nil {
+		return nil, err
+	}
+
 	return r, nil
 }
 
+// ResolveRef returns the commit SHA the ref of a git URL currently resolves to on the remote without cloning it.
+//
+// A URL without a ref resolves to the commit of the remote HEAD.
+func ResolveRef(ctx context.Context, address string) (string, error) {
+	gitURLNoRef, refPlain, err := transform.GitURLSplitRef(address)
+	if err != nil {
+		return "", err
+	}
+	if plumbing.IsHash(refPlain) {
+		return refPlain, nil
+	}
+
+	target := plumbing.HEAD
+	if refPlain != emptyRef {
+		target = ParseRef(refPlain)
+	}
+
+	remote := git.NewRemote(nil, &config.RemoteConfig{
+		Name: onlineRemoteName,
+		URLs: []string{gitURLNoRef},
+	})
+	listOpts := &git.ListOptions{
+		PeelingOption: git.AppendPeeled,
+	}
+	gitCred, err := utils.FindAuthForHost(gitURLNoRef)
+	if err != nil {
+		return "", err
+	}
+	if gitCred != nil {
+		listOpts.Auth = &gitCred.Auth
+	}
+	refs, err := remote.ListContext(ctx, listOpts)
+	if err != nil {
+		return "", fmt.Errorf("unable to list the refs of %s: %w", gitURLNoRef, err)
+	}
+
+	byName := map[plumbing.ReferenceName]*plumbing.Reference{}
+	for _, ref := range refs {
+		byName[ref.Name()] = ref
+	}
+	// Annotated tags must be peeled to the commit they point to.
+	if peeled, ok := byName[plumbing.ReferenceName(target.String()+"^{}")]; ok {
+		return peeled.Hash().String(), nil
+	}
+	ref, ok := byName[target]
+	if ok && ref.Type() == plumbing.SymbolicReference {
+		ref, ok = byName[ref.Target()]
+	}
+	if !ok {
+		return "", fmt.Errorf("unable to find ref %s in %s", target, gitURLNoRef)
+	}
+	return ref.Hash().String(), nil
+}
+
+// CloneWithCache clones a git repository to the given local path, reusing a previous clone from cacheDir when the
+// repository is pinned to a commit SHA and populating the cache when it is not there yet.
+//
+// Repositories referenced by a branch or tag can move and are always cloned from the remote. The returned bool reports
+// whether the clone was served from the cache.
+func CloneWithCache(ctx context.Context, cacheDir, rootPath, address string) (*Repository, bool, error) {
+	_, refPlain, err := transform.GitURLSplitRef(address)
+	if err != nil {
+		return nil, false, err
+	}
+	if !plumbing.IsHash(refPlain) {
+		r, err := Clone(ctx, rootPath, address, false)
+		return r, false, err
+	}
+
+	repoFolder, err := transform.GitURLtoFolderName(address)
+	if err != nil {
+		return nil, false, err
+	}
+	cachePath := filepath.Join(cacheDir, repoFolder)
+	r := &Repository{
+		path: filepath.Join(rootPath, repoFolder),
+	}
+
+	if !helpers.InvalidPath(cachePath) {
+		if err := helpers.CreatePathAndCopy(cachePath, r.path); err != nil {
+			return nil, false, fmt.Errorf("unable to copy cached repo %s: %w", address, err)
+		}
+		if err := utils.TouchCacheEntry(cachePath); err != nil {
+			message.Debugf("Unable to update the last used time of %s: %s", cachePath, err.Error())
+		}
+		return r, true, nil
+	}
+
+	r, err = Clone(ctx, rootPath, address, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := cacheRepository(r.path, cachePath); err != nil {
+		message.Debugf("Unable to cache repo %s: %s", address, err.Error())
+	}
+	return r, false, nil
+}
+
+// cacheRepository copies a cloned repository into the cache.
+//
+// The copy is made in a temporary directory first so that an interrupted copy is never mistaken for a complete clone.
+func cacheRepository(repoPath, cachePath string) error {
+	if err := helpers.CreateDirectory(filepath.Dir(cachePath), helpers.ReadWriteExecuteUser); err != nil {
+		return err
+	}
+	tmpPath, err := os.MkdirTemp(filepath.Dir(cachePath), fmt.Sprintf(".%s-", filepath.Base(cachePath)))
+	if err != nil {
+		return err
+	}
+	if err := helpers.CreatePathAndCopy(repoPath, tmpPath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return err
+	}
+	return nil
+}
+
 // Repository manages a local git repository.
 type Repository struct {
 	path string
@@ -144,6 +275,19 @@ func (r *Repository) Path() string {
 	return r.path
 }
 
+// Head returns the commit SHA the checked out HEAD of the repository points to.
+func (r *Repository) Head() (string, error) {
+	repo, err := git.PlainOpen(r.path)
+	if err != nil {
+		return "", fmt.Errorf("not a valid git repo or unable to open: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
 // Push pushes the repository to the remote git server.
 func (r *Repository) Push(ctx context.Context, address, username, password string) error {
 	repo, err := git.PlainOpen(r.path)
@@ -223,6 +367,10 @@ func (r *Repository) Push(ctx context.Context, address, username, password strin
 		return fmt.Errorf("unable to push repo to the gitops service: %s", err.Error())
 	}
 
+	if err := r.pushLFSObjects(ctx, targetURL.String(), &gitCred); err != nil {
+		return fmt.Errorf("unable to push the LFS objects of the repo to the gitops service: %w", err)
+	}
+
 	return nil
 }
 func (r *Repository) checkoutRefAsBranch(ref string, branch plumbing.ReferenceName) error {
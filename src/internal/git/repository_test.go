@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/require"
@@ -27,6 +29,83 @@ func TestRepository(t *testing.T) {
 	t.Parallel()
 	ctx := testutil.TestContext(t)
 
+	srv, _ := newTestRemote(t)
+
+	rootPath := t.TempDir()
+	repoName := "test"
+	repoAddress := fmt.Sprintf("%s/%s.git", srv.URL, repoName)
+	checksum := helpers.GetCRCHash(repoAddress)
+	expectedPath := fmt.Sprintf("%s-%d", repoName, checksum)
+
+	repo, err := Clone(ctx, rootPath, repoAddress, false)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(rootPath, expectedPath), repo.Path())
+
+	repo, err = Open(rootPath, repoAddress)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(rootPath, expectedPath), repo.Path())
+}
+
+func TestCloneWithCache(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	srv, hash := newTestRemote(t)
+	cacheDir := t.TempDir()
+
+	// Repos referenced by a branch are never cached.
+	repo, hit, err := CloneWithCache(ctx, cacheDir, t.TempDir(), fmt.Sprintf("%s/test.git@refs/heads/master", srv.URL))
+	require.NoError(t, err)
+	require.False(t, hit)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	require.Equal(t, hash.String(), head)
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	address := fmt.Sprintf("%s/test.git@%s", srv.URL, hash.String())
+	_, hit, err = CloneWithCache(ctx, cacheDir, t.TempDir(), address)
+	require.NoError(t, err)
+	require.False(t, hit)
+
+	// The cached clone is used without contacting the remote.
+	srv.Close()
+	repo, hit, err = CloneWithCache(ctx, cacheDir, t.TempDir(), address)
+	require.NoError(t, err)
+	require.True(t, hit)
+	head, err = repo.Head()
+	require.NoError(t, err)
+	require.Equal(t, hash.String(), head)
+	b, err := os.ReadFile(filepath.Join(repo.Path(), "test.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "Hello World", string(b))
+}
+
+func TestResolveRef(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	srv, hash := newTestRemote(t)
+
+	for _, address := range []string{
+		fmt.Sprintf("%s/test.git", srv.URL),
+		fmt.Sprintf("%s/test.git@refs/heads/master", srv.URL),
+		fmt.Sprintf("%s/test.git@%s", srv.URL, hash.String()),
+	} {
+		commit, err := ResolveRef(ctx, address)
+		require.NoError(t, err)
+		require.Equal(t, hash.String(), commit)
+	}
+
+	_, err := ResolveRef(ctx, fmt.Sprintf("%s/test.git@v1.0.0", srv.URL))
+	require.EqualError(t, err, fmt.Sprintf("unable to find ref refs/tags/v1.0.0 in %s/test.git", srv.URL))
+}
+
+// newTestRemote starts a git server with a single "test" repository containing one commit and returns the commit hash.
+func newTestRemote(t *testing.T) (*httptest.Server, plumbing.Hash) {
+	t.Helper()
+
 	cfg := gitkit.Config{
 		Dir:        t.TempDir(),
 		AutoCreate: true,
@@ -39,12 +118,6 @@ func TestRepository(t *testing.T) {
 		srv.Close()
 	})
 
-	rootPath := t.TempDir()
-	repoName := "test"
-	repoAddress := fmt.Sprintf("%s/%s.git", srv.URL, repoName)
-	checksum := helpers.GetCRCHash(repoAddress)
-	expectedPath := fmt.Sprintf("%s-%d", repoName, checksum)
-
 	storer := memory.NewStorage()
 	fs := memfs.New()
 	initRepo, err := git.Init(storer, fs)
@@ -59,7 +132,7 @@ func TestRepository(t *testing.T) {
 	newFile.Close()
 	_, err = w.Add(filePath)
 	require.NoError(t, err)
-	_, err = w.Commit("Initial commit", &git.CommitOptions{
+	hash, err := w.Commit("Initial commit", &git.CommitOptions{
 		Author: &object.Signature{
 			Email: "example@example.com",
 		},
@@ -67,7 +140,7 @@ func TestRepository(t *testing.T) {
 	require.NoError(t, err)
 	_, err = initRepo.CreateRemote(&config.RemoteConfig{
 		Name: "origin",
-		URLs: []string{repoAddress},
+		URLs: []string{fmt.Sprintf("%s/test.git", srv.URL)},
 	})
 	require.NoError(t, err)
 	err = initRepo.Push(&git.PushOptions{
@@ -75,11 +148,5 @@ func TestRepository(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	repo, err := Clone(ctx, rootPath, repoAddress, false)
-	require.NoError(t, err)
-	require.Equal(t, filepath.Join(rootPath, expectedPath), repo.Path())
-
-	repo, err = Open(rootPath, repoAddress)
-	require.NoError(t, err)
-	require.Equal(t, filepath.Join(rootPath, expectedPath), repo.Path())
+	return srv, hash
 }
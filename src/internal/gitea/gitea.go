@@ -193,3 +193,18 @@ func (g *Client) AddReadOnlyUserToRepository(ctx context.Context, repo, username
 	}
 	return nil
 }
+
+// DeleteRepository deletes a repository of the user, succeeding if it does not exist.
+func (g *Client) DeleteRepository(ctx context.Context, repo string) error {
+	_, statusCode, err := g.DoRequest(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/repos/%s/%s", g.username, repo), nil)
+	if err != nil {
+		return err
+	}
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if statusCode != http.StatusNoContent {
+		return fmt.Errorf("unable to delete the repo %s: unexpected status code %d", repo, statusCode)
+	}
+	return nil
+}
@@ -4,6 +4,9 @@
 package gitea
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -18,3 +21,28 @@ func TestNewClient(t *testing.T) {
 	require.Equal(t, "foo", c.username)
 	require.Equal(t, "bar", c.password)
 }
+
+func TestDeleteRepository(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ := r.BasicAuth()
+		switch {
+		case r.Method != http.MethodDelete || user != "foo":
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/api/v1/repos/foo/podinfo-1646971829":
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v1/repos/foo/locked":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL, "foo", "bar")
+	require.NoError(t, err)
+	require.NoError(t, c.DeleteRepository(context.Background(), "podinfo-1646971829"))
+	require.NoError(t, c.DeleteRepository(context.Background(), "missing"))
+	require.EqualError(t, c.DeleteRepository(context.Background(), "locked"), "unable to delete the repo locked: unexpected status code 500")
+}
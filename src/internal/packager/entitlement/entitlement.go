@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package entitlement verifies the signed entitlement tokens that gated components require on deploy.
+package entitlement
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PEMPrefix is the prefix of a PEM encoded public key, used to tell an inline key from a path to one.
+const PEMPrefix = "-----BEGIN"
+
+// ErrNoKey is returned when tokens are verified for a package without an entitlement key.
+var ErrNoKey = errors.New("the package does not have an entitlement key")
+
+// Claims are the claims of an entitlement token.
+type Claims struct {
+	// The entitlements granted by the token.
+	Entitlements []string `json:"entitlements"`
+	// The name of the package the token is limited to, any package with the same entitlement key if empty.
+	Package string `json:"package,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ParsePublicKey parses a PEM encoded ECDSA, Ed25519 or RSA public key.
+func ParsePublicKey(key string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, errors.New("the entitlement key is not PEM encoded")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the entitlement key: %w", err)
+	}
+	switch pub.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("unsupported entitlement key type %T", pub)
+	}
+}
+
+// ReadKey returns the given entitlement key if it is PEM encoded, otherwise the contents of the file at that path.
+func ReadKey(key string) (string, error) {
+	if strings.HasPrefix(strings.TrimSpace(key), PEMPrefix) {
+		return key, nil
+	}
+	b, err := os.ReadFile(key)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the entitlement key: %w", err)
+	}
+	return string(b), nil
+}
+
+// Verify verifies entitlement tokens offline against the PEM encoded key of a package and returns the entitlements they
+// grant. Each token can also be given as a path to a file containing it.
+func Verify(key string, pkgName string, tokens []string) (map[string]bool, error) {
+	if key == "" {
+		return nil, ErrNoKey
+	}
+	pub, err := ParsePublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	methods := []string{}
+	switch pub.(type) {
+	case *ecdsa.PublicKey:
+		methods = []string{"ES256", "ES384", "ES512"}
+	case ed25519.PublicKey:
+		methods = []string{"EdDSA"}
+	case *rsa.PublicKey:
+		methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	}
+
+	granted := map[string]bool{}
+	for i, token := range tokens {
+		if b, err := os.ReadFile(token); err == nil {
+			token = strings.TrimSpace(string(b))
+		}
+		claims := &Claims{}
+		_, err := jwt.ParseWithClaims(token, claims, func(_ *jwt.Token) (interface{}, error) {
+			return pub, nil
+		}, jwt.WithValidMethods(methods))
+		if err != nil {
+			return nil, fmt.Errorf("entitlement token %d is not valid for this package: %w", i+1, err)
+		}
+		if claims.Package != "" && claims.Package != pkgName {
+			return nil, fmt.Errorf("entitlement token %d is for package %q, not %q", i+1, claims.Package, pkgName)
+		}
+		for _, e := range claims.Entitlements {
+			granted[e] = true
+		}
+	}
+	return granted, nil
+}
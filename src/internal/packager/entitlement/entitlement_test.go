@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package entitlement verifies the signed entitlement tokens that gated components require on deploy.
+package entitlement
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key := encodePublicKey(t, pub)
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(signToken(t, jwt.SigningMethodEdDSA, priv, Claims{Entitlements: []string{"support"}})+"\n"), 0o600))
+
+	tests := []struct {
+		name     string
+		tokens   []string
+		expected map[string]bool
+		errMsg   string
+	}{
+		{
+			name:     "no tokens",
+			expected: map[string]bool{},
+		},
+		{
+			name: "tokens and token files",
+			tokens: []string{
+				signToken(t, jwt.SigningMethodEdDSA, priv, Claims{Entitlements: []string{"premium"}, Package: "vendor"}),
+				tokenFile,
+			},
+			expected: map[string]bool{"premium": true, "support": true},
+		},
+		{
+			name:   "token for another package",
+			tokens: []string{signToken(t, jwt.SigningMethodEdDSA, priv, Claims{Entitlements: []string{"premium"}, Package: "other"})},
+			errMsg: `entitlement token 1 is for package "other", not "vendor"`,
+		},
+		{
+			name:   "token signed by another key",
+			tokens: []string{signToken(t, jwt.SigningMethodEdDSA, otherPriv, Claims{Entitlements: []string{"premium"}})},
+			errMsg: "entitlement token 1 is not valid for this package",
+		},
+		{
+			name: "expired token",
+			tokens: []string{signToken(t, jwt.SigningMethodEdDSA, priv, Claims{
+				Entitlements:     []string{"premium"},
+				RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour))},
+			})},
+			errMsg: "token is expired",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			granted, err := Verify(key, "vendor", tt.tokens)
+			if tt.errMsg != "" {
+				require.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, granted)
+		})
+	}
+
+	_, err = Verify("", "vendor", nil)
+	require.ErrorIs(t, err, ErrNoKey)
+}
+
+func TestVerifyECDSA(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	key := encodePublicKey(t, &priv.PublicKey)
+
+	granted, err := Verify(key, "vendor", []string{signToken(t, jwt.SigningMethodES256, priv, Claims{Entitlements: []string{"premium"}})})
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"premium": true}, granted)
+
+	// A token signed with a method that does not match the key is rejected.
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{Entitlements: []string{"premium"}}).SignedString([]byte(key))
+	require.NoError(t, err)
+	_, err = Verify(key, "vendor", []string{hmac})
+	require.ErrorContains(t, err, "signing method HS256 is invalid")
+}
+
+func TestReadKey(t *testing.T) {
+	t.Parallel()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key := encodePublicKey(t, pub)
+	path := filepath.Join(t.TempDir(), "entitlement.pub")
+	require.NoError(t, os.WriteFile(path, []byte(key), 0o600))
+
+	inline, err := ReadKey(key)
+	require.NoError(t, err)
+	require.Equal(t, key, inline)
+	fromFile, err := ReadKey(path)
+	require.NoError(t, err)
+	require.Equal(t, key, fromFile)
+	_, err = ReadKey(filepath.Join(t.TempDir(), "missing.pub"))
+	require.Error(t, err)
+
+	_, err = ParsePublicKey("not a key")
+	require.EqualError(t, err, "the entitlement key is not PEM encoded")
+}
+
+// encodePublicKey PEM encodes a public key for tests.
+func encodePublicKey(t *testing.T, pub crypto.PublicKey) string {
+	t.Helper()
+	b, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+// signToken signs an entitlement token for tests.
+func signToken(t *testing.T, method jwt.SigningMethod, priv crypto.PrivateKey, claims Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(priv)
+	require.NoError(t, err)
+	return token
+}
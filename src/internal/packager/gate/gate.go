@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package gate contains functions for checking rendered manifests against Rego and CEL policies before they are applied.
+package gate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// RegoPackage is the package the Rego policies of a gate must be written in, objects are denied by the messages of its
+// deny rules.
+const RegoPackage = "zarf"
+
+// Violation is an object of a rendered manifest that is denied by a policy gate.
+type Violation struct {
+	Gate string
+	// The object, as {KIND} {NAMESPACE}/{NAME}
+	Object  string
+	Message string
+}
+
+// Gate is a set of compiled Rego or CEL policies.
+type Gate struct {
+	Name        string
+	query       *rego.PreparedEvalQuery
+	validations []validation
+}
+
+// validation is a CEL expression that must evaluate to true for an object to be allowed.
+type validation struct {
+	Expression string `json:"expression"`
+	Message    string `json:"message,omitempty"`
+	program    cel.Program
+}
+
+// celFile is a file of CEL validations, written like the validations of a Kubernetes ValidatingAdmissionPolicy.
+type celFile struct {
+	Validations []validation `json:"validations"`
+}
+
+// Load reads and compiles the policies of a gate from the given files, which are Rego modules for the rego engine and
+// YAML files of CEL validations for the cel engine.
+func Load(ctx context.Context, name string, engine v1alpha1.PolicyGateEngine, paths ...string) (*Gate, error) {
+	switch engine {
+	case v1alpha1.PolicyGateEngineRego:
+		return loadRego(ctx, name, paths)
+	case v1alpha1.PolicyGateEngineCEL:
+		return loadCEL(name, paths)
+	default:
+		return nil, fmt.Errorf("policy gate %s engine %q is not supported", name, engine)
+	}
+}
+
+// FileName returns the name of a file of a policy gate within a package, keeping the extension of its engine.
+func FileName(policyGate v1alpha1.ZarfPolicyGate, idx int) string {
+	ext := "yaml"
+	if policyGate.Engine == v1alpha1.PolicyGateEngineRego {
+		ext = "rego"
+	}
+	return fmt.Sprintf("%s-%d.%s", policyGate.Name, idx, ext)
+}
+
+// LoadPaths loads a gate for each of the local policy files or directories of policy files, choosing the engine of
+// each file by its extension: .rego files are Rego modules and .yaml or .yml files are CEL validations.
+func LoadPaths(ctx context.Context, paths []string) ([]*Gate, error) {
+	gates := []*Gate{}
+	for _, path := range paths {
+		files := []string{path}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if fi.IsDir() {
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return nil, err
+			}
+			files = []string{}
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+		regoFiles := []string{}
+		for _, file := range files {
+			switch filepath.Ext(file) {
+			case ".rego":
+				regoFiles = append(regoFiles, file)
+			case ".yaml", ".yml":
+				gate, err := loadCEL(file, []string{file})
+				if err != nil {
+					return nil, err
+				}
+				gates = append(gates, gate)
+			default:
+				if !fi.IsDir() {
+					return nil, fmt.Errorf("policy gate %s must be a .rego, .yaml or .yml file", file)
+				}
+			}
+		}
+		// Rego modules in the same directory are compiled together so that they can share rules.
+		if len(regoFiles) > 0 {
+			gate, err := loadRego(ctx, path, regoFiles)
+			if err != nil {
+				return nil, err
+			}
+			gates = append(gates, gate)
+		}
+	}
+	return gates, nil
+}
+
+func loadRego(ctx context.Context, name string, paths []string) (*Gate, error) {
+	options := []func(*rego.Rego){rego.Query(fmt.Sprintf("data.%s.deny", RegoPackage))}
+	hasPackage := false
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		module, err := ast.ParseModule(path, string(b))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse policy gate %s: %w", name, err)
+		}
+		if module.Package.Path.String() == fmt.Sprintf("data.%s", RegoPackage) {
+			hasPackage = true
+		}
+		options = append(options, rego.ParsedModule(module))
+	}
+	if !hasPackage {
+		return nil, fmt.Errorf("policy gate %s has no Rego policies in package %s", name, RegoPackage)
+	}
+	query, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile policy gate %s: %w", name, err)
+	}
+	return &Gate{Name: name, query: &query}, nil
+}
+
+func loadCEL(name string, paths []string) (*Gate, error) {
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+	gate := &Gate{Name: name, validations: []validation{}}
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f := celFile{}
+		if err := yaml.UnmarshalStrict(b, &f); err != nil {
+			return nil, fmt.Errorf("unable to parse policy gate %s: %w", name, err)
+		}
+		for _, v := range f.Validations {
+			compiled, issues := env.Compile(v.Expression)
+			if issues.Err() != nil {
+				return nil, fmt.Errorf("unable to compile policy gate %s expression %q: %w", name, v.Expression, issues.Err())
+			}
+			if outputType := compiled.OutputType(); !outputType.IsExactType(cel.BoolType) && !outputType.IsExactType(cel.DynType) {
+				return nil, fmt.Errorf("policy gate %s expression %q must evaluate to a bool", name, v.Expression)
+			}
+			v.program, err = env.Program(compiled)
+			if err != nil {
+				return nil, err
+			}
+			gate.validations = append(gate.validations, v)
+		}
+	}
+	if len(gate.validations) == 0 {
+		return nil, fmt.Errorf("policy gate %s has no CEL validations", name)
+	}
+	return gate, nil
+}
+
+// Evaluate returns the violations of the objects of a rendered manifest, in the order of the objects.
+func (g *Gate) Evaluate(ctx context.Context, manifest string) ([]Violation, error) {
+	objs, err := manifestObjects(manifest)
+	if err != nil {
+		return nil, err
+	}
+	violations := []Violation{}
+	for _, obj := range objs {
+		messages, err := g.evaluate(ctx, obj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to evaluate policy gate %s for %s: %w", g.Name, objectName(obj), err)
+		}
+		for _, msg := range messages {
+			violations = append(violations, Violation{Gate: g.Name, Object: objectName(obj), Message: msg})
+		}
+	}
+	return violations, nil
+}
+
+func (g *Gate) evaluate(ctx context.Context, obj *unstructured.Unstructured) ([]string, error) {
+	messages := []string{}
+	if g.query != nil {
+		rs, err := g.query.Eval(ctx, rego.EvalInput(obj.Object))
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range rs {
+			for _, expr := range result.Expressions {
+				denied, ok := expr.Value.([]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s.deny must be a set of messages", RegoPackage)
+				}
+				for _, msg := range denied {
+					messages = append(messages, fmt.Sprint(msg))
+				}
+			}
+		}
+		slices.Sort(messages)
+		return messages, nil
+	}
+
+	for _, v := range g.validations {
+		out, _, err := v.program.ContextEval(ctx, map[string]interface{}{"object": obj.Object})
+		if err != nil {
+			// Like an admission policy that fails closed, an expression that cannot be evaluated denies the object.
+			messages = append(messages, fmt.Sprintf("expression %q could not be evaluated: %s", v.Expression, err))
+			continue
+		}
+		allowed, ok := out.Value().(bool)
+		if !ok {
+			return nil, fmt.Errorf("expression %q must evaluate to a bool", v.Expression)
+		}
+		if allowed {
+			continue
+		}
+		msg := v.Message
+		if msg == "" {
+			msg = fmt.Sprintf("failed expression: %s", v.Expression)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// manifestObjects returns the objects of a rendered manifest, in the order they are rendered.
+func manifestObjects(manifest string) ([]*unstructured.Unstructured, error) {
+	manifests := releaseutil.SplitManifests(manifest)
+	keys := []string{}
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+	objs := []*unstructured.Unstructured{}
+	for _, key := range keys {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifests[key]), &obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		if obj.GetKind() == "" {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// objectName returns the name of an object as {KIND} {NAMESPACE}/{NAME}.
+func objectName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package gate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+const manifest = `---
+# Source: podinfo/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: podinfo
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: podinfo
+        image: ghcr.io/stefanprodan/podinfo:latest
+---
+# Source: podinfo/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: podinfo
+  namespace: podinfo
+`
+
+const regoPolicy = `package zarf
+
+import rego.v1
+
+deny contains msg if {
+	input.kind == "Deployment"
+	some container in input.spec.template.spec.containers
+	endswith(container.image, ":latest")
+	msg := sprintf("container %s must not use the latest tag", [container.name])
+}
+
+deny contains msg if {
+	input.kind == "Deployment"
+	input.spec.replicas > max_replicas
+	msg := sprintf("replicas must not be more than %d", [max_replicas])
+}
+`
+
+const regoHelpers = `package zarf
+
+max_replicas := 3
+`
+
+const celPolicy = `validations:
+- expression: "object.kind != 'Deployment' || object.spec.replicas <= 3"
+  message: replicas must not be more than 3
+- expression: "object.kind != 'Service' || object.metadata.labels.team != ''"
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	regoPath := writeFile(t, dir, "policy.rego", regoPolicy)
+	helpersPath := writeFile(t, dir, "helpers.rego", regoHelpers)
+	celPath := writeFile(t, dir, "policy.yaml", celPolicy)
+
+	tests := []struct {
+		name     string
+		engine   v1alpha1.PolicyGateEngine
+		paths    []string
+		expected []Violation
+	}{
+		{
+			name:   "rego",
+			engine: v1alpha1.PolicyGateEngineRego,
+			paths:  []string{regoPath, helpersPath},
+			expected: []Violation{
+				{Gate: "gate", Object: "Deployment podinfo/podinfo", Message: "container podinfo must not use the latest tag"},
+				{Gate: "gate", Object: "Deployment podinfo/podinfo", Message: "replicas must not be more than 3"},
+			},
+		},
+		{
+			name:   "cel",
+			engine: v1alpha1.PolicyGateEngineCEL,
+			paths:  []string{celPath},
+			expected: []Violation{
+				{Gate: "gate", Object: "Deployment podinfo/podinfo", Message: "replicas must not be more than 3"},
+				{Gate: "gate", Object: "Service podinfo/podinfo", Message: "expression \"object.kind != 'Service' || object.metadata.labels.team != ''\" could not be evaluated: no such key: labels"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.TestContext(t)
+			gate, err := Load(ctx, "gate", tt.engine, tt.paths...)
+			require.NoError(t, err)
+			violations, err := gate.Evaluate(ctx, manifest)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, violations)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tests := []struct {
+		name        string
+		engine      v1alpha1.PolicyGateEngine
+		file        string
+		content     string
+		expectedErr string
+	}{
+		{
+			name:        "rego without the zarf package",
+			engine:      v1alpha1.PolicyGateEngineRego,
+			file:        "other.rego",
+			content:     "package other\n\ndeny := [\"denied\"]\n",
+			expectedErr: "policy gate gate has no Rego policies in package zarf",
+		},
+		{
+			name:        "cel without validations",
+			engine:      v1alpha1.PolicyGateEngineCEL,
+			file:        "empty.yaml",
+			content:     "validations: []\n",
+			expectedErr: "policy gate gate has no CEL validations",
+		},
+		{
+			name:        "cel that is not a bool",
+			engine:      v1alpha1.PolicyGateEngineCEL,
+			file:        "string.yaml",
+			content:     "validations:\n- expression: \"'a' + 'b'\"\n",
+			expectedErr: "policy gate gate expression \"'a' + 'b'\" must evaluate to a bool",
+		},
+		{
+			name:        "unsupported engine",
+			engine:      "opa",
+			file:        "policy.rego",
+			content:     regoPolicy,
+			expectedErr: "policy gate gate engine \"opa\" is not supported",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeFile(t, dir, tt.file, tt.content)
+			_, err := Load(testutil.TestContext(t), "gate", tt.engine, path)
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestLoadPaths(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	dir := t.TempDir()
+	writeFile(t, dir, "policy.rego", regoPolicy)
+	writeFile(t, dir, "helpers.rego", regoHelpers)
+	writeFile(t, dir, "policy.yaml", celPolicy)
+	writeFile(t, dir, "README.md", "# Policies")
+	other := writeFile(t, t.TempDir(), "other.yaml", "validations:\n- expression: \"true\"\n")
+
+	gates, err := LoadPaths(ctx, []string{dir, other})
+	require.NoError(t, err)
+	names := []string{}
+	for _, gate := range gates {
+		names = append(names, gate.Name)
+	}
+	require.Equal(t, []string{filepath.Join(dir, "policy.yaml"), dir, other}, names)
+
+	_, err = LoadPaths(ctx, []string{filepath.Join(dir, "README.md")})
+	require.EqualError(t, err, "policy gate "+filepath.Join(dir, "README.md")+" must be a .rego, .yaml or .yml file")
+}
@@ -5,6 +5,7 @@
 package helm
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -22,9 +23,12 @@ import (
 	"helm.sh/helm/v3/pkg/releaseutil"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/yaml"
 
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/faults"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -62,22 +66,30 @@ func (h *Helm) InstallOrUpgradeChart(ctx context.Context) (types.ConnectStrings,
 	err = retry.Do(func() error {
 		var err error
 
+		if err = faults.Inject(faults.ChartInstall); err != nil {
+			return err
+		}
+
 		releases, histErr := histClient.Run(h.chart.ReleaseName)
 
 		spinner.Updatef("Checking for existing helm deployment")
 
+		// The hooks of the chart and the wait for its resources share the timeout of the chart.
+		deadline := time.Now().Add(h.timeout)
+
+		var rel *release.Release
 		if errors.Is(histErr, driver.ErrReleaseNotFound) {
 			// No prior release, try to install it.
 			spinner.Updatef("Attempting chart installation")
 
-			_, err = h.installChart(postRender)
+			rel, err = h.installChart(postRender)
 		} else if histErr == nil && len(releases) > 0 {
 			// Otherwise, there is a prior release so upgrade it.
 			spinner.Updatef("Attempting chart upgrade")
 
 			lastRelease := releases[len(releases)-1]
 
-			_, err = h.upgradeChart(lastRelease, postRender)
+			rel, err = h.upgradeChart(lastRelease, postRender)
 		} else {
 			// 😭 things aren't working
 			return fmt.Errorf("unable to verify the chart installation status: %w", histErr)
@@ -87,6 +99,13 @@ func (h *Helm) InstallOrUpgradeChart(ctx context.Context) (types.ConnectStrings,
 			return err
 		}
 
+		if h.waitsForReady() {
+			spinner.Updatef("Waiting for the resources of the chart to be ready")
+			if err := h.waitForReady(ctx, rel, deadline); err != nil {
+				return err
+			}
+		}
+
 		spinner.Success()
 		return nil
 	}, retry.Context(ctx), retry.Attempts(uint(h.retries)), retry.Delay(500*time.Millisecond))
@@ -165,6 +184,9 @@ func (h *Helm) TemplateChart(ctx context.Context) (manifest string, chartValues
 	if err != nil {
 		return "", nil, fmt.Errorf("unable to load chart data: %w", err)
 	}
+	if err := h.applyHookPolicy(loadedChart, chartValues, false); err != nil {
+		return "", nil, err
+	}
 
 	client.PostRenderer, err = h.newRenderer(ctx)
 	if err != nil {
@@ -198,6 +220,15 @@ func (h *Helm) RemoveChart(namespace string, name string, spinner *message.Spinn
 	return err
 }
 
+// RollbackChart rolls a chart back to a previous revision of its release.
+func (h *Helm) RollbackChart(namespace string, name string, revision int, spinner *message.Spinner) error {
+	// Establish a new actionConfig for the namespace.
+	if err := h.createActionConfig(namespace, spinner); err != nil {
+		return fmt.Errorf("unable to initialize the K8s client: %w", err)
+	}
+	return h.rollbackChart(name, revision)
+}
+
 // UpdateReleaseValues updates values for a given chart release
 // (note: this only works on single-deep charts, charts with dependencies (like loki-stack) will not work)
 func (h *Helm) UpdateReleaseValues(ctx context.Context, updatedValues map[string]interface{}) error {
@@ -262,7 +293,8 @@ func (h *Helm) installChart(postRender *renderer) (*release.Release, error) {
 	client.Timeout = h.timeout
 
 	// Default helm behavior for Zarf is to wait for the resources to deploy, NoWait overrides that for special cases (such as data-injection).
-	client.Wait = !h.chart.NoWait
+	// The resources are waited for with kstatus instead of by Helm when there is a cluster to watch.
+	client.Wait = !h.chart.NoWait && !h.waitsForReady()
 
 	// We need to include CRDs or operator installations will fail spectacularly.
 	client.SkipCRDs = false
@@ -280,6 +312,9 @@ func (h *Helm) installChart(postRender *renderer) (*release.Release, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to load chart data: %w", err)
 	}
+	if err := h.applyHookPolicy(loadedChart, chartValues, false); err != nil {
+		return nil, err
+	}
 
 	// Perform the loadedChart installation.
 	return client.Run(loadedChart, chartValues)
@@ -299,7 +334,8 @@ func (h *Helm) upgradeChart(lastRelease *release.Release, postRender *renderer)
 	client.Timeout = h.timeout
 
 	// Default helm behavior for Zarf is to wait for the resources to deploy, NoWait overrides that for special cases (such as data-injection).
-	client.Wait = !h.chart.NoWait
+	// The resources are waited for with kstatus instead of by Helm when there is a cluster to watch.
+	client.Wait = !h.chart.NoWait && !h.waitsForReady()
 
 	client.SkipCRDs = true
 
@@ -313,11 +349,42 @@ func (h *Helm) upgradeChart(lastRelease *release.Release, postRender *renderer)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load chart data: %w", err)
 	}
+	if err := h.applyHookPolicy(loadedChart, chartValues, true); err != nil {
+		return nil, err
+	}
 
 	// Perform the loadedChart upgrade.
 	return client.Run(h.chart.ReleaseName, loadedChart, chartValues)
 }
 
+// waitsForReady returns true if the resources of the chart are waited for with kstatus rather than by Helm.
+func (h *Helm) waitsForReady() bool {
+	return !h.chart.NoWait && h.cluster != nil
+}
+
+// waitForReady waits until the deadline for the objects of a release to be ready as computed by kstatus, which unlike
+// the wait of Helm covers custom resources with status conditions and reports why objects are not ready.
+func (h *Helm) waitForReady(ctx context.Context, rel *release.Release, deadline time.Time) error {
+	resources, err := h.actionConfig.KubeClient.Build(bytes.NewBufferString(rel.Manifest), false)
+	if err != nil {
+		return fmt.Errorf("unable to build the resources of the chart: %w", err)
+	}
+	objs := []object.ObjMetadata{}
+	for _, info := range resources {
+		objs = append(objs, object.ObjMetadata{
+			GroupKind: info.Mapping.GroupVersionKind.GroupKind(),
+			Namespace: info.Namespace,
+			Name:      info.Name,
+		})
+	}
+	waitCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	if err := cluster.WaitForObjectsReady(waitCtx, h.cluster.Watcher, objs); err != nil {
+		return fmt.Errorf("the resources of the chart are not ready: %w", err)
+	}
+	return nil
+}
+
 func (h *Helm) rollbackChart(name string, version int) error {
 	client := action.NewRollback(h.actionConfig)
 	client.CleanupOnFail = true
@@ -88,11 +88,7 @@ func NewFromZarfManifest(manifest v1alpha1.ZarfManifest, manifestPath, packageNa
 	tmpChart.Metadata = new(chart.Metadata)
 
 	// Generate a hashed chart name.
-	rawChartName := fmt.Sprintf("raw-%s-%s-%s", packageName, componentName, manifest.Name)
-	hasher := sha1.New()
-	hasher.Write([]byte(rawChartName))
-	tmpChart.Metadata.Name = rawChartName
-	sha1ReleaseName := hex.EncodeToString(hasher.Sum(nil))
+	tmpChart.Metadata.Name = fmt.Sprintf("raw-%s-%s-%s", packageName, componentName, manifest.Name)
 
 	// This is fun, increment forward in a semver-way using epoch so helm doesn't cry.
 	tmpChart.Metadata.Version = fmt.Sprintf("0.1.%d", config.GetStartTime())
@@ -117,9 +113,8 @@ func NewFromZarfManifest(manifest v1alpha1.ZarfManifest, manifestPath, packageNa
 	// Generate the struct to pass to InstallOrUpgradeChart().
 	h = &Helm{
 		chart: v1alpha1.ZarfChart{
-			Name: tmpChart.Metadata.Name,
-			// Preserve the zarf prefix for chart names to match v0.22.x and earlier behavior.
-			ReleaseName: fmt.Sprintf("zarf-%s", sha1ReleaseName),
+			Name:        tmpChart.Metadata.Name,
+			ReleaseName: ManifestReleaseName(packageName, componentName, manifest.Name),
 			Version:     tmpChart.Metadata.Version,
 			Namespace:   manifest.Namespace,
 			NoWait:      manifest.NoWait,
@@ -137,6 +132,22 @@ func NewFromZarfManifest(manifest v1alpha1.ZarfManifest, manifestPath, packageNa
 	return h, nil
 }
 
+// ReleaseName returns the name of the release of the chart, which defaults to the name of the chart.
+func (h *Helm) ReleaseName() string {
+	if h.chart.ReleaseName == "" {
+		return h.chart.Name
+	}
+	return h.chart.ReleaseName
+}
+
+// ManifestReleaseName returns the name of the release of the chart generated for a Zarf manifest.
+func ManifestReleaseName(packageName, componentName, manifestName string) string {
+	hasher := sha1.New()
+	hasher.Write([]byte(fmt.Sprintf("raw-%s-%s-%s", packageName, componentName, manifestName)))
+	// Preserve the zarf prefix for chart names to match v0.22.x and earlier behavior.
+	return fmt.Sprintf("zarf-%s", hex.EncodeToString(hasher.Sum(nil)))
+}
+
 // WithDeployInfo adds the necessary information to deploy a given chart
 func WithDeployInfo(cfg *types.PackagerConfig, variableConfig *variables.VariableConfig, state *types.ZarfState, cluster *cluster.Cluster, valuesOverrides map[string]any, timeout time.Duration, retries int) Modifier {
 	return func(h *Helm) {
@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package helm contains operations for working with helm charts.
+package helm
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// applyHookPolicy rewrites the templates of the chart that render hooks which are skipped, converted or limited by the
+// hooks of the Zarf chart. Helm does not pass hooks through the post-renderer, so these templates are rendered once
+// with the values of the chart and replaced by their rendered output.
+func (h *Helm) applyHookPolicy(loadedChart *chart.Chart, chartValues chartutil.Values, isUpgrade bool) error {
+	hooks := h.chart.Hooks
+	if len(hooks.Skip) == 0 && len(hooks.Convert) == 0 && hooks.Timeout == "" {
+		return nil
+	}
+	var deadline int64
+	if hooks.Timeout != "" {
+		timeout, err := time.ParseDuration(hooks.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid hook timeout %q: %w", hooks.Timeout, err)
+		}
+		deadline = int64(timeout.Seconds())
+	}
+
+	rendered, err := h.renderTemplates(loadedChart, chartValues, isUpgrade)
+	if err != nil {
+		return fmt.Errorf("unable to render the hooks of chart %s: %w", h.chart.Name, err)
+	}
+
+	templates := map[string]*chart.File{}
+	var collect func(c *chart.Chart)
+	collect = func(c *chart.Chart) {
+		for _, t := range c.Templates {
+			templates[path.Join(c.ChartFullPath(), t.Name)] = t
+		}
+		for _, dep := range c.Dependencies() {
+			collect(dep)
+		}
+	}
+	collect(loadedChart)
+
+	for name, content := range rendered {
+		t, ok := templates[name]
+		if !ok || strings.HasPrefix(path.Base(name), "_") || !strings.Contains(content, release.HookAnnotation) {
+			continue
+		}
+		docs, changed, err := applyHookPolicyToManifests(content, hooks.Skip, hooks.Convert, deadline)
+		if err != nil {
+			return fmt.Errorf("unable to apply the hook policy to %s: %w", name, err)
+		}
+		if !changed {
+			continue
+		}
+		message.Debugf("Applied the hook policy of chart %s to %s", h.chart.Name, name)
+		// Escape the rendered output so that it is not templated again.
+		t.Data = []byte("{{" + strconv.Quote(strings.Join(docs, "---\n")) + "}}")
+	}
+	return nil
+}
+
+// renderTemplates renders the templates of the chart the same way an install or upgrade would, without any lookups.
+func (h *Helm) renderTemplates(loadedChart *chart.Chart, chartValues chartutil.Values, isUpgrade bool) (map[string]string, error) {
+	if err := chartutil.ProcessDependenciesWithMerge(loadedChart, chartValues); err != nil {
+		return nil, err
+	}
+	caps := chartutil.DefaultCapabilities.Copy()
+	if h.kubeVersion != "" {
+		kubeVersion, err := chartutil.ParseKubeVersion(h.kubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kube version %s: %w", h.kubeVersion, err)
+		}
+		caps.KubeVersion = *kubeVersion
+	} else if h.cluster != nil {
+		serverVersion, err := h.cluster.Clientset.Discovery().ServerVersion()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the cluster version: %w", err)
+		}
+		caps.KubeVersion = chartutil.KubeVersion{Version: serverVersion.GitVersion, Major: serverVersion.Major, Minor: serverVersion.Minor}
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      h.chart.ReleaseName,
+		Namespace: h.chart.Namespace,
+		Revision:  1,
+		IsInstall: !isUpgrade,
+		IsUpgrade: isUpgrade,
+	}
+	valuesToRender, err := chartutil.ToRenderValues(loadedChart, chartValues, options, caps)
+	if err != nil {
+		return nil, err
+	}
+	return engine.Render(loadedChart, valuesToRender)
+}
+
+// applyHookPolicyToManifests removes the skipped events from the hooks in the given manifests, turns hooks with a
+// converted event into regular resources and sets the deadline of the Jobs and Pods that remain hooks. It returns the
+// resulting manifests and whether any of them changed.
+func applyHookPolicyToManifests(content string, skip, convert []string, deadline int64) ([]string, bool, error) {
+	manifests := releaseutil.SplitManifests(content)
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	docs := []string{}
+	changed := false
+	for _, key := range keys {
+		manifest := manifests[key]
+		doc, err := applyHookPolicyToManifest(manifest, skip, convert, deadline)
+		if err != nil {
+			return nil, false, err
+		}
+		if doc != manifest {
+			changed = true
+		}
+		if doc != "" {
+			docs = append(docs, doc+"\n")
+		}
+	}
+	return docs, changed, nil
+}
+
+// applyHookPolicyToManifest applies the hook policy to a single manifest, returning it unchanged if it is not affected
+// by the policy and empty if it is a hook whose events are all skipped.
+func applyHookPolicyToManifest(manifest string, skip, convert []string, deadline int64) (string, error) {
+	var meta struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(manifest), &meta); err != nil {
+		return "", err
+	}
+	hookEvents, ok := meta.Metadata.Annotations[release.HookAnnotation]
+	if !ok {
+		return manifest, nil
+	}
+
+	events := []string{}
+	converted := false
+	for _, event := range strings.Split(hookEvents, ",") {
+		event = strings.TrimSpace(event)
+		if slices.Contains(convert, event) {
+			converted = true
+		}
+		if !slices.Contains(skip, event) {
+			events = append(events, event)
+		}
+	}
+	if !converted && len(events) == 0 {
+		return "", nil
+	}
+
+	b, err := yaml.YAMLToJSON([]byte(manifest))
+	if err != nil {
+		return "", err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(b); err != nil {
+		return "", err
+	}
+	modified := false
+	annotations := obj.GetAnnotations()
+	if converted {
+		delete(annotations, release.HookAnnotation)
+		delete(annotations, release.HookWeightAnnotation)
+		delete(annotations, release.HookDeleteAnnotation)
+		modified = true
+	} else {
+		if len(events) != len(strings.Split(hookEvents, ",")) {
+			annotations[release.HookAnnotation] = strings.Join(events, ",")
+			modified = true
+		}
+		if deadline > 0 {
+			deadlineSet, err := setHookDeadline(obj, deadline)
+			if err != nil {
+				return "", err
+			}
+			modified = modified || deadlineSet
+		}
+	}
+	if !modified {
+		return manifest, nil
+	}
+	obj.SetAnnotations(annotations)
+	b, err = yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+// setHookDeadline limits how long a Job or Pod hook may run, keeping a shorter deadline set by the chart. It returns
+// whether the deadline was set.
+func setHookDeadline(obj *unstructured.Unstructured, deadline int64) (bool, error) {
+	if obj.GetKind() != "Job" && obj.GetKind() != "Pod" {
+		return false, nil
+	}
+	current, found, err := unstructured.NestedInt64(obj.Object, "spec", "activeDeadlineSeconds")
+	if err != nil {
+		return false, err
+	}
+	if found && current <= deadline {
+		return false, nil
+	}
+	return true, unstructured.SetNestedField(obj.Object, deadline, "spec", "activeDeadlineSeconds")
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package helm contains operations for working with helm charts.
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const hooksTemplate = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Release.Name }}-cleanup
+  annotations:
+    "helm.sh/hook": pre-delete
+spec:
+  template:
+    spec:
+      containers:
+        - name: cleanup
+          image: curlimages/curl
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Release.Name }}-migrate
+  annotations:
+    "helm.sh/hook": pre-install,pre-upgrade
+    "helm.sh/hook-weight": "1"
+spec:
+  template:
+    spec:
+      containers:
+        - name: migrate
+          image: {{ .Values.image }}
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-test
+  annotations:
+    "helm.sh/hook": test
+spec:
+  activeDeadlineSeconds: 30
+  containers:
+    - name: test
+      image: busybox
+`
+
+const configMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  message: "{{ "{{" }} not a template {{ "}}" }}"
+`
+
+func TestApplyHookPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		hooks    v1alpha1.ZarfChartHooks
+		expected map[string]string
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:  "skip",
+			hooks: v1alpha1.ZarfChartHooks{Skip: []string{"pre-delete", "pre-upgrade"}},
+			expected: map[string]string{
+				"podinfo-migrate": "pre-install",
+				"podinfo-test":    "test",
+			},
+		},
+		{
+			name:  "convert",
+			hooks: v1alpha1.ZarfChartHooks{Convert: []string{"pre-install"}},
+			expected: map[string]string{
+				"podinfo-cleanup": "pre-delete",
+				"podinfo-migrate": "",
+				"podinfo-test":    "test",
+			},
+		},
+		{
+			name:  "timeout",
+			hooks: v1alpha1.ZarfChartHooks{Skip: []string{"test"}, Timeout: "2m"},
+			expected: map[string]string{
+				"podinfo-cleanup": "pre-delete",
+				"podinfo-migrate": "pre-install,pre-upgrade",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			loadedChart := &chart.Chart{
+				Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "podinfo", Version: "1.0.0"},
+				Templates: []*chart.File{
+					{Name: "templates/hooks.yaml", Data: []byte(hooksTemplate)},
+					{Name: "templates/configmap.yaml", Data: []byte(configMapTemplate)},
+				},
+			}
+			values := chartutil.Values{"image": "ghcr.io/stefanprodan/podinfo:6.4.0"}
+			h := New(v1alpha1.ZarfChart{Name: "podinfo", ReleaseName: "podinfo", Namespace: "podinfo", Hooks: tt.hooks}, "", "")
+			require.NoError(t, h.applyHookPolicy(loadedChart, values, false))
+
+			if tt.expected == nil {
+				require.Equal(t, hooksTemplate, string(loadedChart.Templates[0].Data))
+				return
+			}
+			require.Equal(t, configMapTemplate, string(loadedChart.Templates[1].Data))
+
+			rendered, err := h.renderTemplates(loadedChart, values, false)
+			require.NoError(t, err)
+			hooks := map[string]string{}
+			for _, manifest := range releaseutil.SplitManifests(rendered["podinfo/templates/hooks.yaml"]) {
+				obj := &unstructured.Unstructured{}
+				require.NoError(t, yaml.Unmarshal([]byte(manifest), &obj.Object))
+				hooks[obj.GetName()] = obj.GetAnnotations()["helm.sh/hook"]
+				if tt.hooks.Timeout != "" && obj.GetKind() == "Job" {
+					require.Contains(t, manifest, "activeDeadlineSeconds: 120")
+				}
+				if obj.GetName() == "podinfo-migrate" {
+					require.Contains(t, manifest, "ghcr.io/stefanprodan/podinfo:6.4.0")
+				}
+			}
+			require.Equal(t, tt.expected, hooks)
+		})
+	}
+}
+
+func TestApplyHookPolicyKeepsShorterDeadline(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+  annotations:
+    "helm.sh/hook": test
+spec:
+  activeDeadlineSeconds: 30`
+	doc, err := applyHookPolicyToManifest(manifest, nil, nil, 120)
+	require.NoError(t, err)
+	require.Equal(t, manifest, doc)
+
+	doc, err = applyHookPolicyToManifest(manifest, nil, nil, 10)
+	require.NoError(t, err)
+	require.Contains(t, doc, "activeDeadlineSeconds: 10")
+}
@@ -203,6 +203,11 @@ func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 				message.WarnErrf(err, "Problem creating git server secret for the %s namespace", name)
 			}
 		}
+
+		// Distribute the trust of a private CA to the namespace
+		if err := c.ApplyCABundle(ctx, name, r.state.CABundle); err != nil {
+			message.WarnErrf(err, "Problem creating the CA bundle for the %s namespace", name)
+		}
 	}
 	return nil
 }
@@ -16,6 +16,7 @@ import (
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
@@ -81,7 +82,7 @@ func (h *Helm) PackageChartFromLocalFiles(ctx context.Context, cosignKeyPath str
 	var saved string
 	temp := filepath.Join(h.chartPath, "temp")
 	if _, ok := cl.(loader.DirLoader); ok {
-		err = h.buildChartDependencies()
+		err = h.buildChartDependencies(h.chart.LocalPath)
 		if err != nil {
 			return fmt.Errorf("unable to build dependencies for the chart: %w", err)
 		}
@@ -93,6 +94,9 @@ func (h *Helm) PackageChartFromLocalFiles(ctx context.Context, cosignKeyPath str
 	} else {
 		saved = filepath.Join(temp, filepath.Base(h.chart.LocalPath))
 		err = helpers.CreatePathAndCopy(h.chart.LocalPath, saved)
+		if err == nil {
+			saved, err = h.vendorChartDependencies(saved)
+		}
 	}
 	defer os.RemoveAll(temp)
 
@@ -209,6 +213,11 @@ func (h *Helm) DownloadPublishedChart(ctx context.Context, cosignKeyPath string)
 		return fmt.Errorf("unable to download the helm chart: %w", err)
 	}
 
+	saved, err = h.vendorChartDependencies(saved)
+	if err != nil {
+		return err
+	}
+
 	// Validate the chart
 	_, _, err = h.loadAndValidateChart(saved)
 	if err != nil {
@@ -272,8 +281,44 @@ func (h *Helm) packageValues(ctx context.Context, cosignKeyPath string) error {
 	return nil
 }
 
-// buildChartDependencies builds the helm chart dependencies
-func (h *Helm) buildChartDependencies() error {
+// vendorChartDependencies downloads the dependencies declared in the Chart.yaml of the chart archive at saved that are
+// missing from its charts directory and repackages it next to saved, so that the dependencies do not need to be
+// updated in the air gap.
+func (h *Helm) vendorChartDependencies(saved string) (string, error) {
+	c, err := loader.Load(saved)
+	if err != nil {
+		return "", fmt.Errorf("unable to load the chart from %s: %w", saved, err)
+	}
+	if action.CheckDependencies(c, c.Metadata.Dependencies) == nil {
+		return saved, nil
+	}
+	message.Debugf("Vendoring the missing dependencies of the chart %s", c.Name())
+
+	tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	if err := chartutil.ExpandFile(tmp, saved); err != nil {
+		return "", fmt.Errorf("unable to extract the chart %s: %w", c.Name(), err)
+	}
+	chartDir := filepath.Join(tmp, c.Name())
+	if err := h.buildChartDependencies(chartDir); err != nil {
+		return "", fmt.Errorf("unable to vendor the dependencies of the chart %s: %w", c.Name(), err)
+	}
+
+	client := action.NewPackage()
+	client.Destination = filepath.Dir(saved)
+	vendored, err := client.Run(chartDir, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to package the chart %s with its dependencies: %w", c.Name(), err)
+	}
+	return vendored, nil
+}
+
+// buildChartDependencies downloads the dependencies of the chart directory at chartPath into its charts directory,
+// using the repositories and credentials configured with `zarf tools helm repo add` and `zarf tools registry login`.
+func (h *Helm) buildChartDependencies(chartPath string) error {
 	// Download and build the specified dependencies
 	regClient, err := registry.NewClient(registry.ClientOptEnableCache(true))
 	if err != nil {
@@ -288,7 +333,7 @@ func (h *Helm) buildChartDependencies() error {
 
 	man := &downloader.Manager{
 		Out:            &message.DebugWriter{},
-		ChartPath:      h.chart.LocalPath,
+		ChartPath:      chartPath,
 		Getters:        getter.All(h.settings),
 		RegistryClient: regClient,
 
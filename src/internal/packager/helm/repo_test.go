@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package helm contains operations for working with helm charts.
+package helm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestVendorChartDependencies(t *testing.T) {
+	// Helm reads its repository config and cache locations from the environment.
+	t.Setenv("HELM_REPOSITORY_CONFIG", filepath.Join(t.TempDir(), "repositories.yaml"))
+	t.Setenv("HELM_REPOSITORY_CACHE", t.TempDir())
+
+	repoDir := t.TempDir()
+	srv := httptest.NewServer(http.FileServer(http.Dir(repoDir)))
+	t.Cleanup(srv.Close)
+
+	sub := &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "sub", Version: "1.0.0"}}
+	_, err := chartutil.Save(sub, repoDir)
+	require.NoError(t, err)
+	index, err := repo.IndexDirectory(repoDir, srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, index.WriteFile(filepath.Join(repoDir, "index.yaml"), 0o644))
+
+	parent := &chart.Chart{Metadata: &chart.Metadata{
+		APIVersion: chart.APIVersionV2,
+		Name:       "parent",
+		Version:    "0.1.0",
+		Dependencies: []*chart.Dependency{
+			{Name: "sub", Version: "~1.0.0", Repository: srv.URL},
+		},
+	}}
+	dir := t.TempDir()
+	saved, err := chartutil.Save(parent, dir)
+	require.NoError(t, err)
+
+	h := New(v1alpha1.ZarfChart{}, dir, "")
+	vendored, err := h.vendorChartDependencies(saved)
+	require.NoError(t, err)
+	require.Equal(t, dir, filepath.Dir(vendored))
+	c, err := loader.Load(vendored)
+	require.NoError(t, err)
+	require.Len(t, c.Dependencies(), 1)
+	require.Equal(t, "sub", c.Dependencies()[0].Name())
+	require.NotNil(t, c.Lock)
+
+	// A chart that already contains its dependencies is used as is.
+	again, err := h.vendorChartDependencies(vendored)
+	require.NoError(t, err)
+	require.Equal(t, vendored, again)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -65,6 +66,13 @@ func (h *Helm) createActionConfig(namespace string, spinner *message.Spinner) er
 	actionConfig := new(action.Configuration)
 	// Set the settings for the helm SDK
 	h.settings = cli.New()
+	// Connect to the same cluster as Zarf
+	if config.CommonOptions.KubeConfig != "" {
+		h.settings.KubeConfig = config.CommonOptions.KubeConfig
+	}
+	if h.cluster != nil && h.cluster.KubeContext() != "" {
+		h.settings.KubeContext = h.cluster.KubeContext()
+	}
 
 	// Set the namespace for helm
 	h.settings.SetNamespace(namespace)
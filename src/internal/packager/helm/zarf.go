@@ -13,11 +13,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/cli-utils/pkg/object"
 
-	pkgkubernetes "github.com/defenseunicorns/pkg/kubernetes"
-
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/agent/hooks"
 	"github.com/zarf-dev/zarf/src/internal/packager/template"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -61,7 +61,7 @@ func (h *Helm) UpdateZarfRegistryValues(ctx context.Context) error {
 	}
 	waitCtx, waitCancel := context.WithTimeout(ctx, 60*time.Second)
 	defer waitCancel()
-	err = pkgkubernetes.WaitForReady(waitCtx, h.cluster.Watcher, objs)
+	err = cluster.WaitForObjectsReady(waitCtx, h.cluster.Watcher, objs)
 	if err != nil {
 		return err
 	}
@@ -112,6 +112,19 @@ func (h *Helm) UpdateZarfAgentValues(ctx context.Context) error {
 					Value: agentImage.Tag,
 				},
 			})
+			// Keep the image policy that the agent was deployed with
+			policy, err := h.cluster.Clientset.CoreV1().ConfigMaps(cluster.ZarfNamespaceName).Get(ctx, hooks.ImagePolicyConfigMapName, metav1.GetOptions{})
+			if err != nil && !kerrors.IsNotFound(err) {
+				return err
+			}
+			mode := string(hooks.ImagePolicyOff)
+			allowlist := ""
+			if err == nil {
+				mode = policy.Data[hooks.ImagePolicyModeKey]
+				allowlist = policy.Data[hooks.ImagePolicyAllowlistKey]
+			}
+			h.variableConfig.SetVariable("AGENT_IMAGE_POLICY", mode, false, false, v1alpha1.RawVariableType)
+			h.variableConfig.SetVariable("AGENT_IMAGE_POLICY_ALLOWLIST", allowlist, false, false, v1alpha1.RawVariableType)
 			applicationTemplates, err := template.GetZarfTemplates("zarf-agent", h.state)
 			if err != nil {
 				return fmt.Errorf("error setting up the templates: %w", err)
@@ -157,7 +170,7 @@ func (h *Helm) UpdateZarfAgentValues(ctx context.Context) error {
 	}
 	waitCtx, waitCancel := context.WithTimeout(ctx, 60*time.Second)
 	defer waitCancel()
-	err = pkgkubernetes.WaitForReady(waitCtx, h.cluster.Watcher, objs)
+	err = cluster.WaitForObjectsReady(waitCtx, h.cluster.Watcher, objs)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package helm contains operations for working with helm charts.
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// renderRegistryChart renders the chart of the Zarf registry with the given values on top of its defaults.
+func renderRegistryChart(t *testing.T, values map[string]any) map[string]string {
+	t.Helper()
+
+	loadedChart, err := loader.Load("../../../../packages/zarf-registry/chart")
+	require.NoError(t, err)
+	chartValues := map[string]any{
+		"secrets": map[string]any{
+			"htpasswd": "push:hash\npull:hash",
+			"configData": map[string]any{
+				"http": map[string]any{"secret": "http-secret"},
+			},
+		},
+	}
+	for k, v := range values {
+		chartValues[k] = v
+	}
+	options := chartutil.ReleaseOptions{Name: "zarf-docker-registry", Namespace: "zarf", Revision: 1, IsInstall: true}
+	valuesToRender, err := chartutil.ToRenderValues(loadedChart, chartValues, options, chartutil.DefaultCapabilities)
+	require.NoError(t, err)
+	rendered, err := engine.Render(loadedChart, valuesToRender)
+	require.NoError(t, err)
+	return rendered
+}
+
+// registryConfig returns the deployment and the registry configuration in the secret of a rendered registry chart.
+func registryConfig(t *testing.T, rendered map[string]string) (appsv1.Deployment, map[string]any) {
+	t.Helper()
+
+	deployment := appsv1.Deployment{}
+	require.NoError(t, yaml.Unmarshal([]byte(rendered["docker-registry/templates/deployment.yaml"]), &deployment))
+	secret := corev1.Secret{}
+	require.NoError(t, yaml.Unmarshal([]byte(rendered["docker-registry/templates/secret.yaml"]), &secret))
+	configData := map[string]any{}
+	require.NoError(t, yaml.Unmarshal(secret.Data["configData"], &configData))
+	return deployment, configData
+}
+
+func TestRegistryChartStorage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("filesystem", func(t *testing.T) {
+		t.Parallel()
+
+		rendered := renderRegistryChart(t, nil)
+		require.Contains(t, rendered["docker-registry/templates/pvc.yaml"], "kind: PersistentVolumeClaim")
+		require.Contains(t, rendered["docker-registry/templates/pvc.yaml"], `storage: "20Gi"`)
+
+		deployment, configData := registryConfig(t, rendered)
+		require.Equal(t, map[string]any{"cache": map[string]any{"blobdescriptor": "inmemory"}}, configData["storage"])
+		container := deployment.Spec.Template.Spec.Containers[0]
+		require.Contains(t, container.Env, corev1.EnvVar{Name: "REGISTRY_STORAGE_FILESYSTEM_ROOTDIRECTORY", Value: "/var/lib/registry"})
+		require.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "data", MountPath: "/var/lib/registry/"})
+		volumes := map[string]corev1.Volume{}
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			volumes[volume.Name] = volume
+		}
+		require.NotNil(t, volumes["data"].PersistentVolumeClaim)
+		require.Equal(t, "zarf-docker-registry", volumes["data"].PersistentVolumeClaim.ClaimName)
+		require.NotNil(t, deployment.Spec.Template.Spec.Affinity.PodAffinity)
+	})
+
+	t.Run("s3", func(t *testing.T) {
+		t.Parallel()
+
+		rendered := renderRegistryChart(t, map[string]any{
+			"s3": map[string]any{
+				"enabled":        true,
+				"bucket":         "zarf-registry",
+				"region":         "us-east-1",
+				"regionEndpoint": "https://minio.example.com",
+				"accessKey":      "access",
+				"secretKey":      "secret",
+			},
+		})
+		require.Empty(t, strings.TrimSpace(rendered["docker-registry/templates/pvc.yaml"]))
+
+		deployment, configData := registryConfig(t, rendered)
+		storage, ok := configData["storage"].(map[string]any)
+		require.True(t, ok)
+		expectedS3 := map[string]any{
+			"bucket":         "zarf-registry",
+			"region":         "us-east-1",
+			"regionendpoint": "https://minio.example.com",
+			"secure":         true,
+			"accesskey":      "access",
+			"secretkey":      "secret",
+		}
+		require.Equal(t, expectedS3, storage["s3"])
+		require.Equal(t, map[string]any{"disable": true}, storage["redirect"])
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		for _, env := range container.Env {
+			require.NotEqual(t, "REGISTRY_STORAGE_FILESYSTEM_ROOTDIRECTORY", env.Name)
+		}
+		// The credentials are read from the registry configuration that is mounted from the secret.
+		require.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "config", MountPath: "/etc/docker/registry"})
+		volumes := map[string]corev1.Volume{}
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			volumes[volume.Name] = volume
+		}
+		require.NotNil(t, volumes["config"].Secret)
+		require.Equal(t, "zarf-docker-registry-secret", volumes["config"].Secret.SecretName)
+		require.Contains(t, volumes["config"].Secret.Items, corev1.KeyToPath{Key: "configData", Path: "config.yml"})
+		require.Nil(t, volumes["data"].PersistentVolumeClaim)
+		require.NotNil(t, volumes["data"].EmptyDir)
+		require.NotNil(t, deployment.Spec.Template.Spec.Affinity.PodAntiAffinity)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/crane"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/types"
@@ -26,6 +27,10 @@ type PullConfig struct {
 
 	Arch string
 
+	// RecordPlatform records Arch as the platform of every pulled image so that the images of multiple architectures
+	// can be pulled into the same directory.
+	RecordPlatform bool
+
 	RegistryOverrides map[string]string
 
 	CacheDirectory string
@@ -44,6 +49,9 @@ type PushConfig struct {
 	Arch string
 
 	Retries int
+
+	// Cluster to push to the registry of, which is connected to from the Zarf options when it is nil
+	Cluster *cluster.Cluster
 }
 
 // NoopOpt is a no-op option for crane.
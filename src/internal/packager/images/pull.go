@@ -21,16 +21,13 @@ import (
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/logs"
-	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/cache"
-	"github.com/google/go-containerregistry/pkg/v1/daemon"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	clayout "github.com/google/go-containerregistry/pkg/v1/layout"
 	"github.com/google/go-containerregistry/pkg/v1/partial"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/types"
-	"github.com/moby/moby/client"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
@@ -75,9 +72,18 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 		return nil, fmt.Errorf("failed to create image path %s: %w", cfg.DestinationDirectory, err)
 	}
 
-	cranePath, err := clayout.Write(cfg.DestinationDirectory, empty.Index)
+	// Images for other architectures may already have been pulled into the destination.
+	cranePath, err := clayout.FromPath(cfg.DestinationDirectory)
 	if err != nil {
-		return nil, err
+		cranePath, err = clayout.Write(cfg.DestinationDirectory, empty.Index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var platform *v1.Platform
+	if cfg.RecordPlatform {
+		platform = &v1.Platform{OS: "linux", Architecture: cfg.Arch}
 	}
 
 	spinner := message.NewProgressSpinner("Fetching info for %d images. %s", imageCount, longer)
@@ -86,7 +92,7 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 	logs.Warn.SetOutput(&message.DebugWriter{})
 	logs.Progress.SetOutput(&message.DebugWriter{})
 
-	eg, ectx := errgroup.WithContext(ctx)
+	eg, _ := errgroup.WithContext(ctx)
 	eg.SetLimit(10)
 
 	var shaLock sync.Mutex
@@ -95,7 +101,14 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 
 	fetched := map[transform.Image]v1.Image{}
 
-	var counter, totalBytes atomic.Int64
+	runtimes := &runtimeClients{}
+	defer func() {
+		if err := runtimes.Close(); err != nil {
+			message.Debugf("Unable to close the connections to the local container runtimes: %s", err.Error())
+		}
+	}()
+
+	var counter, totalBytes, cachedLayers, cachedBytes, sharedLayers, sharedBytes atomic.Int64
 
 	for _, refInfo := range cfg.ImageList {
 		refInfo := refInfo
@@ -113,17 +126,19 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 			var img v1.Image
 			var desc *remote.Descriptor
 
-			// load from local fs if it's a tarball
-			if strings.HasSuffix(ref, ".tar") || strings.HasSuffix(ref, ".tar.gz") || strings.HasSuffix(ref, ".tgz") {
+			if refInfo.Runtime != "" {
+				// Load locally built images straight from the container runtime they were built with.
+				img, err = runtimes.Load(ctx, refInfo, cfg.Arch)
+				if err != nil {
+					return fmt.Errorf("unable to load %s from %s: %w", refInfo.Reference, refInfo.Runtime, err)
+				}
+			} else if strings.HasSuffix(ref, ".tar") || strings.HasSuffix(ref, ".tar.gz") || strings.HasSuffix(ref, ".tgz") {
+				// load from local fs if it's a tarball
 				img, err = crane.Load(ref, opts...)
 				if err != nil {
 					return fmt.Errorf("unable to load %s: %w", refInfo.Reference, err)
 				}
 			} else {
-				reference, err := name.ParseReference(ref)
-				if err != nil {
-					return fmt.Errorf("failed to parse reference: %w", err)
-				}
 				desc, err = crane.Get(ref, opts...)
 				if err != nil {
 					if strings.Contains(err.Error(), "unexpected status code 429 Too Many Requests") {
@@ -132,32 +147,13 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 
 					message.Warnf("Falling back to local 'docker', failed to find the manifest on a remote: %s", err.Error())
 
-					// Attempt to connect to the local docker daemon.
-					cli, err := client.NewClientWithOpts(client.FromEnv)
-					if err != nil {
-						return fmt.Errorf("docker not available: %w", err)
-					}
-					cli.NegotiateAPIVersion(ectx)
-
-					// Inspect the image to get the size.
-					rawImg, _, err := cli.ImageInspectWithRaw(ectx, ref)
+					fallback := refInfo
+					fallback.Reference = ref
+					fallback.Runtime = transform.DockerDaemon
+					img, err = runtimes.Load(ctx, fallback, cfg.Arch)
 					if err != nil {
 						return err
 					}
-
-					// Warn the user if the image is large.
-					if rawImg.Size > 750*1000*1000 {
-						message.Warnf("%s is %s and may take a very long time to load via docker. "+
-							"See https://docs.zarf.dev/faq for suggestions on how to improve large local image loading operations.",
-							ref, utils.ByteFormat(float64(rawImg.Size), 2))
-					}
-
-					// Use unbuffered opener to avoid OOM Kill issues https://github.com/zarf-dev/zarf/issues/1214.
-					// This will also take forever to load large images.
-					img, err = daemon.Image(reference, daemon.WithUnbufferedOpener())
-					if err != nil {
-						return fmt.Errorf("failed to load from docker daemon: %w", err)
-					}
 				} else {
 					img, err = crane.Pull(ref, opts...)
 					if err != nil {
@@ -204,6 +200,26 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 						return fmt.Errorf("unable to get size for image layer: %w", err)
 					}
 					totalBytes.Add(size)
+
+					// Layers are cached by digest, so a complete cached layer will not be downloaded again.
+					if cacheImg {
+						location := filepath.Join(cfg.CacheDirectory, digest.String())
+						if info, err := os.Stat(location); err == nil && info.Size() == size {
+							cachedLayers.Add(1)
+							cachedBytes.Add(size)
+							if err := utils.TouchCacheEntry(location); err != nil {
+								message.Debugf("Unable to update the last used time of %s: %s", location, err.Error())
+							}
+						}
+					}
+				} else {
+					// Blobs are stored by digest, so layers shared with other images are only stored once in the package.
+					size, err := layer.Size()
+					if err != nil {
+						return fmt.Errorf("unable to get size for image layer: %w", err)
+					}
+					sharedLayers.Add(1)
+					sharedBytes.Add(size)
 				}
 			}
 
@@ -222,6 +238,12 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 	}
 
 	spinner.Successf("Fetched info for %d images", imageCount)
+	if cachedLayers.Load() > 0 {
+		message.Infof("Reusing %d of %d image layers (%s) from the cache", cachedLayers.Load(), len(shas), utils.ByteFormat(float64(cachedBytes.Load()), 2))
+	}
+	if sharedLayers.Load() > 0 {
+		message.Infof("%d image layers (%s) are shared between images and will only be stored once", sharedLayers.Load(), utils.ByteFormat(float64(sharedBytes.Load()), 2))
+	}
 
 	doneSaving := make(chan error)
 	updateText := fmt.Sprintf("Pulling %d images", imageCount)
@@ -230,7 +252,7 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 	toPull := maps.Clone(fetched)
 
 	err = retry.Do(func() error {
-		saved, err := SaveConcurrent(ctx, cranePath, toPull)
+		saved, err := SaveConcurrent(ctx, cranePath, toPull, platform)
 		for k := range saved {
 			delete(toPull, k)
 		}
@@ -239,7 +261,7 @@ func Pull(ctx context.Context, cfg PullConfig) (map[transform.Image]v1.Image, er
 	if err != nil {
 		message.Warnf("Failed to save images in parallel, falling back to sequential save: %s", err.Error())
 		err = retry.Do(func() error {
-			saved, err := SaveSequential(ctx, cranePath, toPull)
+			saved, err := SaveSequential(ctx, cranePath, toPull, platform)
 			for k := range saved {
 				delete(toPull, k)
 			}
@@ -320,14 +342,18 @@ func CleanupInProgressLayers(ctx context.Context, img v1.Image) error {
 	return eg.Wait()
 }
 
-// SaveSequential saves images sequentially.
-func SaveSequential(ctx context.Context, cl clayout.Path, m map[transform.Image]v1.Image) (map[transform.Image]v1.Image, error) {
+// SaveSequential saves images sequentially, recording platform as the platform of each image if it is not nil.
+func SaveSequential(ctx context.Context, cl clayout.Path, m map[transform.Image]v1.Image, platform *v1.Platform) (map[transform.Image]v1.Image, error) {
 	saved := map[transform.Image]v1.Image{}
 	for info, img := range m {
 		annotations := map[string]string{
 			ocispec.AnnotationBaseImageName: info.Reference,
 		}
-		if err := cl.AppendImage(img, clayout.WithAnnotations(annotations)); err != nil {
+		opts := []clayout.Option{clayout.WithAnnotations(annotations)}
+		if platform != nil {
+			opts = append(opts, clayout.WithPlatform(*platform))
+		}
+		if err := cl.AppendImage(img, opts...); err != nil {
 			if err := CleanupInProgressLayers(ctx, img); err != nil {
 				message.WarnErr(err, "failed to clean up in-progress layers, please run `zarf tools clear-cache`")
 			}
@@ -338,8 +364,9 @@ func SaveSequential(ctx context.Context, cl clayout.Path, m map[transform.Image]
 	return saved, nil
 }
 
-// SaveConcurrent saves images in a concurrent, bounded manner.
-func SaveConcurrent(ctx context.Context, cl clayout.Path, m map[transform.Image]v1.Image) (map[transform.Image]v1.Image, error) {
+// SaveConcurrent saves images in a concurrent, bounded manner, recording platform as the platform of each image if it is
+// not nil.
+func SaveConcurrent(ctx context.Context, cl clayout.Path, m map[transform.Image]v1.Image, platform *v1.Platform) (map[transform.Image]v1.Image, error) {
 	saved := map[transform.Image]v1.Image{}
 
 	var mu sync.Mutex
@@ -372,6 +399,9 @@ func SaveConcurrent(ctx context.Context, cl clayout.Path, m map[transform.Image]
 					ocispec.AnnotationBaseImageName: info.Reference,
 				}
 				desc.Annotations = annotations
+				if platform != nil {
+					desc.Platform = platform
+				}
 				if err := cl.AppendDescriptor(*desc); err != nil {
 					return err
 				}
@@ -384,3 +414,97 @@ func SaveConcurrent(ctx context.Context, cl clayout.Path, m map[transform.Image]
 
 	return saved, eg.Wait()
 }
+
+// Resolve returns the manifest digest each image in the given config resolves to for its architecture without pulling
+// the layers of the image.
+func Resolve(ctx context.Context, cfg PullConfig) (map[transform.Image]string, error) {
+	spinner := message.NewProgressSpinner("Resolving %d images", len(cfg.ImageList))
+	defer spinner.Stop()
+
+	logs.Warn.SetOutput(&message.DebugWriter{})
+	logs.Progress.SetOutput(&message.DebugWriter{})
+
+	eg, ectx := errgroup.WithContext(ctx)
+	eg.SetLimit(10)
+
+	opts := CommonOpts(cfg.Arch)
+
+	runtimes := &runtimeClients{}
+	defer func() {
+		if err := runtimes.Close(); err != nil {
+			message.Debugf("Unable to close the connections to the local container runtimes: %s", err.Error())
+		}
+	}()
+
+	var mu sync.Mutex
+	resolved := map[transform.Image]string{}
+
+	for _, refInfo := range cfg.ImageList {
+		refInfo := refInfo
+		eg.Go(func() error {
+			ref := refInfo.Reference
+			for k, v := range cfg.RegistryOverrides {
+				if strings.HasPrefix(refInfo.Reference, k) {
+					ref = strings.Replace(refInfo.Reference, k, v, 1)
+				}
+			}
+
+			var digest string
+			switch {
+			case refInfo.Runtime != "":
+				img, err := runtimes.Load(ectx, refInfo, cfg.Arch)
+				if err != nil {
+					return fmt.Errorf("unable to load %s from %s: %w", refInfo.Reference, refInfo.Runtime, err)
+				}
+				h, err := img.Digest()
+				if err != nil {
+					return fmt.Errorf("unable to get digest for image %s: %w", refInfo.Reference, err)
+				}
+				digest = h.String()
+			case strings.HasSuffix(ref, ".tar") || strings.HasSuffix(ref, ".tar.gz") || strings.HasSuffix(ref, ".tgz"):
+				img, err := crane.Load(ref, opts...)
+				if err != nil {
+					return fmt.Errorf("unable to load %s: %w", refInfo.Reference, err)
+				}
+				h, err := img.Digest()
+				if err != nil {
+					return fmt.Errorf("unable to get digest for image %s: %w", refInfo.Reference, err)
+				}
+				digest = h.String()
+			default:
+				desc, err := crane.Get(ref, append(opts, crane.WithContext(ectx))...)
+				if err != nil {
+					return fmt.Errorf("unable to resolve image %s: %w", refInfo.Reference, err)
+				}
+				if err := checkForIndex(refInfo, desc); err != nil {
+					return err
+				}
+				digest = desc.Digest.String()
+				// The digest of the platform image is recorded for an index, the same as when the image is pulled.
+				if types.MediaType(desc.MediaType).IsIndex() {
+					img, err := desc.Image()
+					if err != nil {
+						return fmt.Errorf("unable to resolve image %s for %s: %w", refInfo.Reference, cfg.Arch, err)
+					}
+					h, err := img.Digest()
+					if err != nil {
+						return fmt.Errorf("unable to get digest for image %s: %w", refInfo.Reference, err)
+					}
+					digest = h.String()
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resolved[refInfo] = digest
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	spinner.Successf("Resolved %d images", len(cfg.ImageList))
+	return resolved, nil
+}
@@ -8,14 +8,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
 func TestCheckForIndex(t *testing.T) {
@@ -106,3 +119,138 @@ func TestPull(t *testing.T) {
 		require.Empty(t, dir)
 	})
 }
+
+func TestPullSharedLayers(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	base, err := random.Layer(1024, types.OCILayer)
+	require.NoError(t, err)
+	imageList := []transform.Image{}
+	for _, name := range []string{"podinfo", "game"} {
+		layer, err := random.Layer(512, types.OCILayer)
+		require.NoError(t, err)
+		img, err := mutate.AppendLayers(empty.Image, base, layer)
+		require.NoError(t, err)
+		ref := fmt.Sprintf("%s/%s:1.0.0", host, name)
+		require.NoError(t, crane.Push(img, ref))
+		refInfo, err := transform.ParseImageRef(ref)
+		require.NoError(t, err)
+		imageList = append(imageList, refInfo)
+	}
+
+	destDir := t.TempDir()
+	pulled, err := Pull(context.Background(), PullConfig{
+		DestinationDirectory: destDir,
+		CacheDirectory:       t.TempDir(),
+		ImageList:            imageList,
+		Arch:                 "amd64",
+	})
+	require.NoError(t, err)
+	require.Len(t, pulled, 2)
+
+	// The shared base layer is stored once next to the unique layer, config and manifest of each image.
+	blobs, err := os.ReadDir(filepath.Join(destDir, "blobs", "sha256"))
+	require.NoError(t, err)
+	require.Len(t, blobs, 7)
+	digest, err := base.Digest()
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(destDir, "blobs", "sha256", digest.Hex))
+}
+
+func TestPullArtifact(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	// A Helm OCI chart is an artifact with a chart config and content layer instead of an image config and layers.
+	chart := static.NewLayer([]byte("chart"), "application/vnd.cncf.helm.chart.content.v1.tar+gzip")
+	artifact, err := mutate.AppendLayers(mutate.MediaType(empty.Image, types.OCIManifestSchema1), chart)
+	require.NoError(t, err)
+	artifact = mutate.ConfigMediaType(artifact, "application/vnd.cncf.helm.config.v1+json")
+	ref := fmt.Sprintf("%s/charts/podinfo:6.4.0", host)
+	require.NoError(t, crane.Push(artifact, ref))
+	refInfo, err := transform.ParseImageRef(ref)
+	require.NoError(t, err)
+
+	destDir := t.TempDir()
+	cacheDir := t.TempDir()
+	pulled, err := Pull(context.Background(), PullConfig{
+		DestinationDirectory: destDir,
+		CacheDirectory:       cacheDir,
+		ImageList:            []transform.Image{refInfo},
+		Arch:                 "amd64",
+	})
+	require.NoError(t, err)
+	require.Len(t, pulled, 1)
+
+	// Artifacts are not cached as image layers and are stored without a platform.
+	cached, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Empty(t, cached)
+	imgs, err := utils.LoadOCIImages(destDir, refInfo)
+	require.NoError(t, err)
+	require.Contains(t, imgs, "")
+	manifest, err := imgs[""].Manifest()
+	require.NoError(t, err)
+	require.Equal(t, types.MediaType("application/vnd.cncf.helm.config.v1+json"), manifest.Config.MediaType)
+	expected, err := artifact.Digest()
+	require.NoError(t, err)
+	actual, err := imgs[""].Digest()
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	// A multi-platform index resolves to the image of the requested architecture.
+	idx := v1.ImageIndex(empty.Index)
+	platformDigests := map[string]string{}
+	for _, arch := range []string{"amd64", "arm64"} {
+		img, err := random.Image(512, 1)
+		require.NoError(t, err)
+		idx = mutate.AppendManifests(idx, mutate.IndexAddendum{
+			Add:        img,
+			Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: arch}},
+		})
+		digest, err := img.Digest()
+		require.NoError(t, err)
+		platformDigests[arch] = digest.String()
+	}
+	indexRef := fmt.Sprintf("%s/podinfo:6.4.0", host)
+	ref, err := name.ParseReference(indexRef)
+	require.NoError(t, err)
+	require.NoError(t, remote.WriteIndex(ref, idx))
+
+	img, err := random.Image(512, 1)
+	require.NoError(t, err)
+	imageRef := fmt.Sprintf("%s/game:1.0.0", host)
+	require.NoError(t, crane.Push(img, imageRef))
+	imageDigest, err := img.Digest()
+	require.NoError(t, err)
+
+	indexInfo, err := transform.ParseImageRef(indexRef)
+	require.NoError(t, err)
+	imageInfo, err := transform.ParseImageRef(imageRef)
+	require.NoError(t, err)
+
+	resolved, err := Resolve(context.Background(), PullConfig{
+		ImageList: []transform.Image{indexInfo, imageInfo},
+		Arch:      "arm64",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[transform.Image]string{
+		indexInfo: platformDigests["arm64"],
+		imageInfo: imageDigest.String(),
+	}, resolved)
+}
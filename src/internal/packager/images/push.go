@@ -7,14 +7,21 @@ package images
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/avast/retry-go/v4"
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/logs"
+	gcrname "github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 
+	"github.com/zarf-dev/zarf/src/internal/faults"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
@@ -26,20 +33,30 @@ func Push(ctx context.Context, cfg PushConfig) error {
 	logs.Warn.SetOutput(&message.DebugWriter{})
 	logs.Progress.SetOutput(&message.DebugWriter{})
 
-	toPush := map[transform.Image]v1.Image{}
+	toPush := map[transform.Image]remote.Taggable{}
+	sizes := map[transform.Image]int64{}
 	var totalSize int64
 	// Build an image list from the references
 	for _, refInfo := range cfg.ImageList {
-		img, err := utils.LoadOCIImage(cfg.SourceDirectory, refInfo)
+		imgs, err := utils.LoadOCIImages(cfg.SourceDirectory, refInfo)
 		if err != nil {
 			return err
 		}
-		toPush[refInfo] = img
-		imgSize, err := calcImgSize(img)
-		if err != nil {
-			return err
+		for _, img := range imgs {
+			imgSize, err := calcImgSize(img)
+			if err != nil {
+				return err
+			}
+			sizes[refInfo] += imgSize
 		}
-		totalSize += imgSize
+		totalSize += sizes[refInfo]
+
+		// Images of multi-architecture packages are pushed as an index of every architecture in the package.
+		if img, ok := imgs[""]; ok && len(imgs) == 1 {
+			toPush[refInfo] = img
+			continue
+		}
+		toPush[refInfo] = imageIndex(imgs)
 	}
 
 	// If this is not a no checksum image push we will be pushing two images (the second will go faster as it checks the same layers)
@@ -54,7 +71,10 @@ func Push(ctx context.Context, cfg PushConfig) error {
 	)
 
 	err = retry.Do(func() error {
-		c, _ := cluster.NewCluster()
+		c := cfg.Cluster
+		if c == nil {
+			c, _ = cluster.NewCluster()
+		}
 		if c != nil {
 			registryURL, tunnel, err = c.ConnectToZarfRegistryEndpoint(ctx, cfg.RegInfo)
 			if err != nil {
@@ -69,12 +89,26 @@ func Push(ctx context.Context, cfg PushConfig) error {
 		defer progress.Close()
 		pushOptions := createPushOpts(cfg, progress)
 
-		pushImage := func(img v1.Image, name string) error {
+		pushImage := func(t remote.Taggable, name string) error {
+			push := func() error {
+				if err := faults.Inject(faults.RegistryPush); err != nil {
+					return err
+				}
+				if img, ok := t.(v1.Image); ok {
+					return crane.Push(img, name, pushOptions...)
+				}
+				o := crane.GetOptions(pushOptions...)
+				ref, err := gcrname.ParseReference(name, o.Name...)
+				if err != nil {
+					return err
+				}
+				return remote.WriteIndex(ref, t.(v1.ImageIndex), o.Remote...)
+			}
 			if tunnel != nil {
-				return tunnel.Wrap(func() error { return crane.Push(img, name, pushOptions...) })
+				return tunnel.Wrap(push)
 			}
 
-			return crane.Push(img, name, pushOptions...)
+			return push()
 		}
 
 		pushed := []transform.Image{}
@@ -87,13 +121,11 @@ func Push(ctx context.Context, cfg PushConfig) error {
 			refTruncated := helpers.Truncate(refInfo.Reference, 55, true)
 			progress.Updatef(fmt.Sprintf("Pushing %s", refTruncated))
 
-			size, err := calcImgSize(img)
-			if err != nil {
-				return err
-			}
+			size := sizes[refInfo]
 
 			// If this is not a no checksum image push it for use with the Zarf agent
-			if !cfg.NoChecksum {
+			// (cosign artifacts are only looked up by tag next to their image so they are never checksummed)
+			if !cfg.NoChecksum && !utils.IsCosignArtifact(refInfo.Reference) {
 				offlineNameCRC, err := transform.ImageTransformHost(registryURL, refInfo.Reference)
 				if err != nil {
 					return err
@@ -132,6 +164,25 @@ func Push(ctx context.Context, cfg PushConfig) error {
 	return nil
 }
 
+// imageIndex returns an OCI image index of the images keyed by architecture.
+func imageIndex(imgs map[string]v1.Image) v1.ImageIndex {
+	archs := []string{}
+	for arch := range imgs {
+		archs = append(archs, arch)
+	}
+	slices.Sort(archs)
+	idx := mutate.IndexMediaType(empty.Index, types.OCIImageIndex)
+	for _, arch := range archs {
+		idx = mutate.AppendManifests(idx, mutate.IndexAddendum{
+			Add: imgs[arch],
+			Descriptor: v1.Descriptor{
+				Platform: &v1.Platform{OS: "linux", Architecture: arch},
+			},
+		})
+	}
+	return idx
+}
+
 func calcImgSize(img v1.Image) (int64, error) {
 	size, err := img.Size()
 	if err != nil {
@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/content"
+	cimages "github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+const (
+	defaultPodmanSocket     = "unix:///run/podman/podman.sock"
+	defaultContainerdSocket = "/run/containerd/containerd.sock"
+	defaultContainerdNS     = "default"
+)
+
+// runtimeClients lazily connects to the local container runtimes that images are loaded from.
+type runtimeClients struct {
+	mu         sync.Mutex
+	docker     *client.Client
+	podman     *client.Client
+	containerd *containerd.Client
+}
+
+// Close closes the connections to every runtime that was used.
+func (rc *runtimeClients) Close() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	var err error
+	for _, cli := range []*client.Client{rc.docker, rc.podman} {
+		if cli != nil {
+			err = errors.Join(err, cli.Close())
+		}
+	}
+	if rc.containerd != nil {
+		err = errors.Join(err, rc.containerd.Close())
+	}
+	return err
+}
+
+// Load loads the image for the given architecture from the local container runtime of refInfo, ctx must outlive the
+// returned image as the layers of the image are read from the runtime when it is saved.
+func (rc *runtimeClients) Load(ctx context.Context, refInfo transform.Image, arch string) (v1.Image, error) {
+	reference, err := name.ParseReference(refInfo.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reference: %w", err)
+	}
+	switch refInfo.Runtime {
+	case transform.DockerDaemon:
+		cli, err := rc.dockerClient()
+		if err != nil {
+			return nil, fmt.Errorf("docker not available: %w", err)
+		}
+		return loadFromDaemon(ctx, cli, reference, arch)
+	case transform.Podman:
+		cli, err := rc.podmanClient()
+		if err != nil {
+			return nil, fmt.Errorf("podman not available: %w", err)
+		}
+		return loadFromDaemon(ctx, cli, reference, arch)
+	case transform.Containerd:
+		cli, err := rc.containerdClient()
+		if err != nil {
+			return nil, fmt.Errorf("containerd not available: %w", err)
+		}
+		return loadFromContainerd(ctx, cli, refInfo.Reference, arch)
+	default:
+		return nil, fmt.Errorf("unsupported container runtime %q", refInfo.Runtime)
+	}
+}
+
+func (rc *runtimeClients) dockerClient() (*client.Client, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.docker == nil {
+		cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+		if err != nil {
+			return nil, err
+		}
+		rc.docker = cli
+	}
+	return rc.docker, nil
+}
+
+func (rc *runtimeClients) podmanClient() (*client.Client, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.podman == nil {
+		cli, err := client.NewClientWithOpts(client.WithHost(podmanHost()), client.WithAPIVersionNegotiation())
+		if err != nil {
+			return nil, err
+		}
+		rc.podman = cli
+	}
+	return rc.podman, nil
+}
+
+func (rc *runtimeClients) containerdClient() (*containerd.Client, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.containerd == nil {
+		address := os.Getenv("CONTAINERD_ADDRESS")
+		if address == "" {
+			address = defaultContainerdSocket
+		}
+		namespace := os.Getenv("CONTAINERD_NAMESPACE")
+		if namespace == "" {
+			namespace = defaultContainerdNS
+		}
+		cli, err := containerd.New(address, containerd.WithDefaultNamespace(namespace))
+		if err != nil {
+			return nil, err
+		}
+		rc.containerd = cli
+	}
+	return rc.containerd, nil
+}
+
+// podmanHost returns the address of the podman API socket, preferring the rootless socket of the current user.
+func podmanHost() string {
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		return host
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		socket := filepath.Join(runtimeDir, "podman", "podman.sock")
+		if _, err := os.Stat(socket); err == nil {
+			return "unix://" + socket
+		}
+	}
+	return defaultPodmanSocket
+}
+
+// loadFromDaemon loads an image from the docker compatible API of a docker daemon or podman service.
+func loadFromDaemon(ctx context.Context, cli *client.Client, reference name.Reference, arch string) (v1.Image, error) {
+	cli.NegotiateAPIVersion(ctx)
+
+	// Inspect the image to get the size.
+	rawImg, _, err := cli.ImageInspectWithRaw(ctx, reference.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Warn the user if the image is large.
+	if rawImg.Size > 750*1000*1000 {
+		message.Warnf("%s is %s and may take a very long time to load via docker. "+
+			"See https://docs.zarf.dev/faq for suggestions on how to improve large local image loading operations.",
+			reference, utils.ByteFormat(float64(rawImg.Size), 2))
+	}
+	if rawImg.Architecture != "" && rawImg.Architecture != arch {
+		message.Warnf("%s is built for the %s architecture, not %s", reference, rawImg.Architecture, arch)
+	}
+
+	// Use unbuffered opener to avoid OOM Kill issues https://github.com/zarf-dev/zarf/issues/1214.
+	// This will also take forever to load large images.
+	img, err := daemon.Image(reference, daemon.WithUnbufferedOpener(), daemon.WithClient(cli), daemon.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load from docker daemon: %w", err)
+	}
+	return img, nil
+}
+
+// loadFromContainerd loads the image for the given architecture from the content store of containerd.
+func loadFromContainerd(ctx context.Context, cli *containerd.Client, reference string, arch string) (v1.Image, error) {
+	ctrImg, err := cli.ImageService().Get(ctx, reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s in containerd: %w", reference, err)
+	}
+	store := cli.ContentStore()
+
+	// Resolve image indexes to the manifest of the requested architecture.
+	platform := ocispec.Platform{OS: "linux", Architecture: arch}
+	matcher := platforms.Only(platform)
+	desc := ctrImg.Target
+	for cimages.IsIndexType(desc.MediaType) {
+		b, err := content.ReadBlob(ctx, store, desc)
+		if err != nil {
+			return nil, err
+		}
+		var index ocispec.Index
+		if err := json.Unmarshal(b, &index); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal the image index of %s: %w", reference, err)
+		}
+		found := false
+		for _, manifest := range index.Manifests {
+			if manifest.Platform != nil && matcher.Match(*manifest.Platform) {
+				desc = manifest
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s in containerd does not have an image for %s", reference, platforms.Format(platform))
+		}
+	}
+
+	rawManifest, err := content.ReadBlob(ctx, store, desc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the manifest of %s from containerd: %w", reference, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(rawManifest, &manifest); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal the manifest of %s: %w", reference, err)
+	}
+	img, err := partial.CompressedToImage(&containerdImage{
+		ctx:         ctx,
+		store:       store,
+		mediaType:   types.MediaType(desc.MediaType),
+		rawManifest: rawManifest,
+		manifest:    manifest,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the config of %s from containerd: %w", reference, err)
+	}
+	if cfg.Architecture != "" && cfg.Architecture != arch {
+		message.Warnf("%s is built for the %s architecture, not %s", reference, cfg.Architecture, arch)
+	}
+	return img, nil
+}
+
+// containerdImage reads the manifest, config and layers of an image from the content store of containerd.
+type containerdImage struct {
+	ctx         context.Context
+	store       content.Store
+	mediaType   types.MediaType
+	rawManifest []byte
+	manifest    ocispec.Manifest
+}
+
+func (i *containerdImage) RawManifest() ([]byte, error) {
+	return i.rawManifest, nil
+}
+
+func (i *containerdImage) MediaType() (types.MediaType, error) {
+	return i.mediaType, nil
+}
+
+func (i *containerdImage) RawConfigFile() ([]byte, error) {
+	return content.ReadBlob(i.ctx, i.store, i.manifest.Config)
+}
+
+func (i *containerdImage) LayerByDigest(h v1.Hash) (partial.CompressedLayer, error) {
+	for _, desc := range append([]ocispec.Descriptor{i.manifest.Config}, i.manifest.Layers...) {
+		if desc.Digest.String() == h.String() {
+			return &containerdLayer{image: i, desc: desc}, nil
+		}
+	}
+	return nil, fmt.Errorf("layer %s not found in the image manifest", h)
+}
+
+// containerdLayer streams a single blob from the content store of containerd.
+type containerdLayer struct {
+	image *containerdImage
+	desc  ocispec.Descriptor
+}
+
+func (l *containerdLayer) Digest() (v1.Hash, error) {
+	return v1.NewHash(l.desc.Digest.String())
+}
+
+func (l *containerdLayer) Size() (int64, error) {
+	return l.desc.Size, nil
+}
+
+func (l *containerdLayer) MediaType() (types.MediaType, error) {
+	return types.MediaType(l.desc.MediaType), nil
+}
+
+func (l *containerdLayer) Compressed() (io.ReadCloser, error) {
+	ra, err := l.image.store.ReaderAt(l.image.ctx, l.desc)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.NewSectionReader(ra, 0, ra.Size()), ra}, nil
+}
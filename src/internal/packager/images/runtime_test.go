@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package images
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodmanHost(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("CONTAINER_HOST", "")
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	require.Equal(t, defaultPodmanSocket, podmanHost())
+
+	socket := filepath.Join(runtimeDir, "podman", "podman.sock")
+	require.NoError(t, os.MkdirAll(filepath.Dir(socket), 0o700))
+	require.NoError(t, os.WriteFile(socket, nil, 0o600))
+	require.Equal(t, "unix://"+socket, podmanHost())
+
+	t.Setenv("CONTAINER_HOST", "tcp://localhost:8888")
+	require.Equal(t, "tcp://localhost:8888", podmanHost())
+}
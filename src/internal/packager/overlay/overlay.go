@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package overlay selects the per-cluster variable overlay files that apply to the cluster a package is deployed to.
+package overlay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// Overlay is a file of variable values that is applied when deploying to the clusters it selects.
+type Overlay struct {
+	// The name of the cluster the overlay applies to, from the cluster of the current kubeconfig context.
+	Cluster string `json:"cluster,omitempty"`
+	// Labels of the kube-system namespace that must all be present for the overlay to apply.
+	Labels map[string]string `json:"labels,omitempty"`
+	// The variable values set by the overlay.
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// The name of the file the overlay was read from.
+	name string
+}
+
+// Name returns the name of the file the overlay was read from.
+func (o Overlay) Name() string {
+	return o.name
+}
+
+// Matches returns whether the overlay applies to the cluster with the given name and labels. An overlay without a
+// cluster name or labels applies to every cluster.
+func (o Overlay) Matches(cluster string, labels map[string]string) bool {
+	if o.Cluster != "" && o.Cluster != cluster {
+		return false
+	}
+	for k, v := range o.Labels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// precedence orders overlays from the least to the most specific selector.
+func (o Overlay) precedence() int {
+	p := 0
+	if len(o.Labels) > 0 {
+		p++
+	}
+	if o.Cluster != "" {
+		p += 2
+	}
+	return p
+}
+
+// Load reads the YAML overlay files in dir, sorted by file name.
+func Load(dir string) ([]Overlay, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the variable overlays directory %s: %w", dir, err)
+	}
+	overlays := []Overlay{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		var o Overlay
+		if err := utils.ReadYaml(filepath.Join(dir, entry.Name()), &o); err != nil {
+			return nil, fmt.Errorf("unable to read the variable overlay %s: %w", entry.Name(), err)
+		}
+		o.name = entry.Name()
+		overlays = append(overlays, o)
+	}
+	return overlays, nil
+}
+
+// Select returns the overlays that apply to the cluster with the given name and labels in the order they are applied:
+// overlays that apply to every cluster, then overlays selected by labels, then overlays selected by cluster name, and
+// then overlays selected by both. Overlays with the same kind of selector are applied in file name order.
+func Select(overlays []Overlay, cluster string, labels map[string]string) []Overlay {
+	selected := []Overlay{}
+	for _, o := range overlays {
+		if o.Matches(cluster, labels) {
+			selected = append(selected, o)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		if selected[i].precedence() != selected[j].precedence() {
+			return selected[i].precedence() < selected[j].precedence()
+		}
+		return selected[i].name < selected[j].name
+	})
+	return selected
+}
+
+// Merge merges the variables of the given overlays in order, so later overlays take precedence, and then the given set
+// variables on top of them. Variable names are uppercased.
+func Merge(overlays []Overlay, set map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, o := range overlays {
+		for k, v := range o.Variables {
+			merged[strings.ToUpper(k)] = v
+		}
+	}
+	for k, v := range set {
+		merged[strings.ToUpper(k)] = v
+	}
+	return merged
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package overlay selects the per-cluster variable overlay files that apply to the cluster a package is deployed to.
+package overlay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"defaults.yaml": "variables:\n  domain: example.com\n",
+		"east.yml":      "labels:\n  topology.zarf.dev/region: east\nvariables:\n  DOMAIN: east.example.com\n",
+		"README.md":     "not an overlay",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested.yaml"), 0o700))
+
+	overlays, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, overlays, 2)
+	require.Equal(t, "defaults.yaml", overlays[0].Name())
+	require.Equal(t, map[string]string{"domain": "example.com"}, overlays[0].Variables)
+	require.Equal(t, "east.yml", overlays[1].Name())
+	require.Equal(t, map[string]string{"topology.zarf.dev/region": "east"}, overlays[1].Labels)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("variables: [\n"), 0o600))
+	_, err = Load(dir)
+	require.ErrorContains(t, err, "broken.yaml")
+
+	_, err = Load(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	overlays := []Overlay{
+		{name: "z-site.yaml", Cluster: "edge-1", Variables: map[string]string{"DOMAIN": "edge-1.example.com"}},
+		{name: "a-site-east.yaml", Cluster: "edge-1", Labels: map[string]string{"region": "east"}, Variables: map[string]string{"REPLICAS": "1"}},
+		{name: "east.yaml", Labels: map[string]string{"region": "east"}, Variables: map[string]string{"DOMAIN": "east.example.com", "REPLICAS": "2"}},
+		{name: "west.yaml", Labels: map[string]string{"region": "west"}, Variables: map[string]string{"DOMAIN": "west.example.com"}},
+		{name: "other.yaml", Cluster: "edge-2", Variables: map[string]string{"DOMAIN": "edge-2.example.com"}},
+		{name: "defaults.yaml", Variables: map[string]string{"DOMAIN": "example.com", "REPLICAS": "3"}},
+	}
+
+	tests := []struct {
+		name     string
+		cluster  string
+		labels   map[string]string
+		expected []string
+	}{
+		{
+			name:     "defaults only",
+			cluster:  "edge-3",
+			expected: []string{"defaults.yaml"},
+		},
+		{
+			name:     "labels",
+			cluster:  "edge-3",
+			labels:   map[string]string{"region": "west", "tier": "edge"},
+			expected: []string{"defaults.yaml", "west.yaml"},
+		},
+		{
+			name:     "cluster name and labels",
+			cluster:  "edge-1",
+			labels:   map[string]string{"region": "east"},
+			expected: []string{"defaults.yaml", "east.yaml", "z-site.yaml", "a-site-east.yaml"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			names := []string{}
+			for _, o := range Select(overlays, tt.cluster, tt.labels) {
+				names = append(names, o.Name())
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	overlays := []Overlay{
+		{Variables: map[string]string{"domain": "example.com", "REPLICAS": "3"}},
+		{Variables: map[string]string{"DOMAIN": "east.example.com"}},
+	}
+	merged := Merge(overlays, map[string]string{"replicas": "5"})
+	require.Equal(t, map[string]string{"DOMAIN": "east.example.com", "REPLICAS": "5"}, merged)
+}
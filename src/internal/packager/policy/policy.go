@@ -0,0 +1,451 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package policy contains functions for validating and reporting on Kyverno and Gatekeeper policy bundles.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+const (
+	kyvernoGroup               = "kyverno.io"
+	gatekeeperTemplatesGroup   = "templates.gatekeeper.sh"
+	gatekeeperConstraintsGroup = "constraints.gatekeeper.sh"
+	gatekeeperGroupSuffix      = ".gatekeeper.sh"
+)
+
+var (
+	kyvernoKinds = []string{"ClusterPolicy", "Policy", "PolicyException", "ClusterCleanupPolicy", "CleanupPolicy"}
+
+	policyReportGVR        = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "policyreports"}
+	clusterPolicyReportGVR = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "clusterpolicyreports"}
+)
+
+// Finding is a single policy violation reported by a policy engine.
+type Finding struct {
+	Engine    v1alpha1.PolicyEngine
+	Policy    string
+	Rule      string
+	Kind      string
+	Namespace string
+	Name      string
+	Message   string
+}
+
+// Resource returns the violating resource in kind/namespace/name form.
+func (f Finding) Resource() string {
+	parts := []string{}
+	for _, part := range []string{f.Kind, f.Namespace, f.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// ReadFiles reads and parses the policy documents in the given files.
+func ReadFiles(paths ...string) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+	for _, path := range paths {
+		fileObjs, err := utils.SplitYAMLFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse policy file %s: %w", path, err)
+		}
+		objs = append(objs, fileObjs...)
+	}
+	return objs, nil
+}
+
+// Validate checks that every document in objs is a well formed policy resource for the given engine.
+func Validate(engine v1alpha1.PolicyEngine, objs []*unstructured.Unstructured) error {
+	if len(objs) == 0 {
+		return errors.New("no policy documents found")
+	}
+	var err error
+	for _, obj := range objs {
+		var objErr error
+		switch engine {
+		case v1alpha1.PolicyEngineKyverno:
+			objErr = validateKyverno(obj)
+		case v1alpha1.PolicyEngineGatekeeper:
+			objErr = validateGatekeeper(obj)
+		default:
+			return fmt.Errorf("policy engine %q is not supported", engine)
+		}
+		if objErr != nil {
+			err = errors.Join(err, fmt.Errorf("%s %q: %w", obj.GetKind(), obj.GetName(), objErr))
+		}
+	}
+	return err
+}
+
+func validateKyverno(obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != kyvernoGroup || !slices.Contains(kyvernoKinds, gvk.Kind) {
+		return fmt.Errorf("is not a kyverno policy resource, must be one of %v in the %s group", kyvernoKinds, kyvernoGroup)
+	}
+	if obj.GetName() == "" {
+		return errors.New("must have a name")
+	}
+	if gvk.Kind != "ClusterPolicy" && gvk.Kind != "Policy" {
+		return nil
+	}
+	rules, _, err := unstructured.NestedSlice(obj.Object, "spec", "rules")
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return errors.New("must have at least one rule")
+	}
+	for i, rule := range rules {
+		r, ok := rule.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("rule %d is not an object", i)
+		}
+		if name, _ := r["name"].(string); name == "" {
+			return fmt.Errorf("rule %d must have a name", i)
+		}
+	}
+	return nil
+}
+
+func validateGatekeeper(obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	if !strings.HasSuffix(gvk.Group, gatekeeperGroupSuffix) {
+		return fmt.Errorf("is not a gatekeeper policy resource, must be in a *%s group", gatekeeperGroupSuffix)
+	}
+	if obj.GetName() == "" {
+		return errors.New("must have a name")
+	}
+	if gvk.Group != gatekeeperTemplatesGroup {
+		return nil
+	}
+	if gvk.Kind != "ConstraintTemplate" {
+		return fmt.Errorf("kind must be ConstraintTemplate in the %s group", gatekeeperTemplatesGroup)
+	}
+	kind, _, err := unstructured.NestedString(obj.Object, "spec", "crd", "spec", "names", "kind")
+	if err != nil {
+		return err
+	}
+	if kind == "" {
+		return errors.New("must define spec.crd.spec.names.kind")
+	}
+	targets, _, err := unstructured.NestedSlice(obj.Object, "spec", "targets")
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return errors.New("must have at least one target")
+	}
+	return nil
+}
+
+// Violations returns the violations the cluster currently reports for the policies in objs.
+//
+// Kyverno violations are read from policy reports and Gatekeeper violations from the audit status of each constraint.
+// Missing policy report or constraint CRDs are not treated as errors as the engine may not have reconciled yet.
+func Violations(ctx context.Context, client dynamic.Interface, engine v1alpha1.PolicyEngine, objs []*unstructured.Unstructured) ([]Finding, error) {
+	switch engine {
+	case v1alpha1.PolicyEngineKyverno:
+		return kyvernoViolations(ctx, client, objs)
+	case v1alpha1.PolicyEngineGatekeeper:
+		return gatekeeperViolations(ctx, client, objs)
+	default:
+		return nil, fmt.Errorf("policy engine %q is not supported", engine)
+	}
+}
+
+// Audited reports whether the engine has audited the cluster against every policy in objs since the given time.
+//
+// Gatekeeper records when it last audited each constraint. Kyverno only reports a policy once it matched a resource, so
+// a Kyverno policy counts as audited once it is ready and every policy report result for it is newer than since.
+func Audited(ctx context.Context, client dynamic.Interface, engine v1alpha1.PolicyEngine, objs []*unstructured.Unstructured, since time.Time) (bool, error) {
+	switch engine {
+	case v1alpha1.PolicyEngineKyverno:
+		return kyvernoAudited(ctx, client, objs, since)
+	case v1alpha1.PolicyEngineGatekeeper:
+		return gatekeeperAudited(ctx, client, objs, since)
+	default:
+		return false, fmt.Errorf("policy engine %q is not supported", engine)
+	}
+}
+
+func kyvernoAudited(ctx context.Context, client dynamic.Interface, objs []*unstructured.Unstructured, since time.Time) (bool, error) {
+	policies := kyvernoPolicyKeys(objs)
+	if len(policies) == 0 {
+		return true, nil
+	}
+	for _, obj := range objs {
+		if obj.GetKind() != "ClusterPolicy" && obj.GetKind() != "Policy" {
+			continue
+		}
+		ready, err := kyvernoReady(ctx, client, obj)
+		if err != nil {
+			return false, err
+		}
+		if !ready {
+			return false, nil
+		}
+	}
+
+	// Results from before the policies were installed are left over from an earlier audit.
+	since = since.Truncate(time.Second)
+	for _, gvr := range []schema.GroupVersionResource{policyReportGVR, clusterPolicyReportGVR} {
+		reports, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, report := range reports.Items {
+			results, _, _ := unstructured.NestedSlice(report.Object, "results")
+			for _, r := range results {
+				result, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				policy, _, _ := unstructured.NestedString(result, "policy")
+				if policies[policy] && kyvernoResultTime(report, result).Before(since) {
+					return false, nil
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+// kyvernoPolicyKeys returns the names policy reports may refer to the policies in objs by. Namespaced policies are
+// reported as namespace/name by recent Kyverno releases and by their name alone by older ones.
+func kyvernoPolicyKeys(objs []*unstructured.Unstructured) map[string]bool {
+	policies := map[string]bool{}
+	for _, obj := range objs {
+		switch obj.GetKind() {
+		case "ClusterPolicy":
+			policies[obj.GetName()] = true
+		case "Policy":
+			policies[obj.GetName()] = true
+			if obj.GetNamespace() != "" {
+				policies[obj.GetNamespace()+"/"+obj.GetName()] = true
+			}
+		}
+	}
+	return policies
+}
+
+// kyvernoReady reports whether Kyverno has loaded the given policy. Policies without a namespace are looked up in
+// every namespace, as they are installed into the namespace of their bundle.
+func kyvernoReady(ctx context.Context, client dynamic.Interface, obj *unstructured.Unstructured) (bool, error) {
+	resource := "clusterpolicies"
+	if obj.GetKind() == "Policy" {
+		resource = "policies"
+	}
+	gvr := obj.GroupVersionKind().GroupVersion().WithResource(resource)
+
+	var live *unstructured.Unstructured
+	if obj.GetKind() == "Policy" && obj.GetNamespace() == "" {
+		list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		for i := range list.Items {
+			if list.Items[i].GetName() == obj.GetName() {
+				live = &list.Items[i]
+				break
+			}
+		}
+	} else {
+		var err error
+		live, err = client.Resource(gvr).Namespace(obj.GetNamespace()).Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	if live == nil {
+		return false, nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(live.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Ready" {
+			return condition["status"] == string(metav1.ConditionTrue), nil
+		}
+	}
+	// Kyverno releases before 1.9 only set status.ready.
+	ready, _, _ := unstructured.NestedBool(live.Object, "status", "ready")
+	return ready, nil
+}
+
+// kyvernoResultTime returns when a policy report result was produced, falling back to when its report was created.
+func kyvernoResultTime(report unstructured.Unstructured, result map[string]interface{}) time.Time {
+	seconds, found, _ := unstructured.NestedInt64(result, "timestamp", "seconds")
+	if found {
+		return time.Unix(seconds, 0)
+	}
+	return report.GetCreationTimestamp().Time
+}
+
+func gatekeeperAudited(ctx context.Context, client dynamic.Interface, objs []*unstructured.Unstructured, since time.Time) (bool, error) {
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != gatekeeperConstraintsGroup {
+			continue
+		}
+		gvr := gvk.GroupVersion().WithResource(strings.ToLower(gvk.Kind))
+		constraint, err := client.Resource(gvr).Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		auditTimestamp, _, _ := unstructured.NestedString(constraint.Object, "status", "auditTimestamp")
+		audited, err := time.Parse(time.RFC3339, auditTimestamp)
+		if err != nil || audited.Before(since.Truncate(time.Second)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func kyvernoViolations(ctx context.Context, client dynamic.Interface, objs []*unstructured.Unstructured) ([]Finding, error) {
+	policies := kyvernoPolicyKeys(objs)
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	findings := []Finding{}
+	for _, gvr := range []schema.GroupVersionResource{policyReportGVR, clusterPolicyReportGVR} {
+		reports, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, report := range reports.Items {
+			findings = append(findings, kyvernoReportFindings(report, policies)...)
+		}
+	}
+	return findings, nil
+}
+
+func kyvernoReportFindings(report unstructured.Unstructured, policies map[string]bool) []Finding {
+	results, _, _ := unstructured.NestedSlice(report.Object, "results")
+	scope, _, _ := unstructured.NestedMap(report.Object, "scope")
+
+	findings := []Finding{}
+	for _, r := range results {
+		result, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		policy, _, _ := unstructured.NestedString(result, "policy")
+		status, _, _ := unstructured.NestedString(result, "result")
+		if !policies[policy] || (status != "fail" && status != "error") {
+			continue
+		}
+		rule, _, _ := unstructured.NestedString(result, "rule")
+		msg, _, _ := unstructured.NestedString(result, "message")
+
+		resources, _, _ := unstructured.NestedSlice(result, "resources")
+		if len(resources) == 0 && scope != nil {
+			resources = []interface{}{scope}
+		}
+		if len(resources) == 0 {
+			findings = append(findings, Finding{Engine: v1alpha1.PolicyEngineKyverno, Policy: policy, Rule: rule, Message: msg})
+			continue
+		}
+		for _, res := range resources {
+			ref, ok := res.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			kind, _, _ := unstructured.NestedString(ref, "kind")
+			namespace, _, _ := unstructured.NestedString(ref, "namespace")
+			name, _, _ := unstructured.NestedString(ref, "name")
+			findings = append(findings, Finding{
+				Engine:    v1alpha1.PolicyEngineKyverno,
+				Policy:    policy,
+				Rule:      rule,
+				Kind:      kind,
+				Namespace: namespace,
+				Name:      name,
+				Message:   msg,
+			})
+		}
+	}
+	return findings
+}
+
+func gatekeeperViolations(ctx context.Context, client dynamic.Interface, objs []*unstructured.Unstructured) ([]Finding, error) {
+	findings := []Finding{}
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != gatekeeperConstraintsGroup {
+			continue
+		}
+		// Gatekeeper names constraint CRDs after the lowercased kind.
+		gvr := gvk.GroupVersion().WithResource(strings.ToLower(gvk.Kind))
+		constraint, err := client.Resource(gvr).Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, gatekeeperConstraintFindings(*constraint)...)
+	}
+	return findings, nil
+}
+
+func gatekeeperConstraintFindings(constraint unstructured.Unstructured) []Finding {
+	violations, _, _ := unstructured.NestedSlice(constraint.Object, "status", "violations")
+
+	findings := []Finding{}
+	for _, v := range violations {
+		violation, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(violation, "kind")
+		namespace, _, _ := unstructured.NestedString(violation, "namespace")
+		name, _, _ := unstructured.NestedString(violation, "name")
+		msg, _, _ := unstructured.NestedString(violation, "message")
+		findings = append(findings, Finding{
+			Engine:    v1alpha1.PolicyEngineGatekeeper,
+			Policy:    constraint.GetName(),
+			Rule:      constraint.GetKind(),
+			Kind:      kind,
+			Namespace: namespace,
+			Name:      name,
+			Message:   msg,
+		})
+	}
+	return findings
+}
@@ -0,0 +1,384 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package policy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+const kyvernoPolicy = `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: require-labels
+spec:
+  validationFailureAction: Audit
+  rules:
+  - name: check-team
+    match:
+      any:
+      - resources:
+          kinds: ["Pod"]
+`
+
+const gatekeeperBundle = `
+apiVersion: templates.gatekeeper.sh/v1
+kind: ConstraintTemplate
+metadata:
+  name: k8srequiredlabels
+spec:
+  crd:
+    spec:
+      names:
+        kind: K8sRequiredLabels
+  targets:
+  - target: admission.k8s.gatekeeper.sh
+    rego: package k8srequiredlabels
+---
+apiVersion: constraints.gatekeeper.sh/v1beta1
+kind: K8sRequiredLabels
+metadata:
+  name: ns-must-have-owner
+`
+
+func parse(t *testing.T, s string) []*unstructured.Unstructured {
+	t.Helper()
+	objs, err := utils.SplitYAML([]byte(s))
+	require.NoError(t, err)
+	return objs
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		engine      v1alpha1.PolicyEngine
+		docs        string
+		expectedErr string
+	}{
+		{
+			name:   "valid kyverno",
+			engine: v1alpha1.PolicyEngineKyverno,
+			docs:   kyvernoPolicy,
+		},
+		{
+			name:   "valid gatekeeper",
+			engine: v1alpha1.PolicyEngineGatekeeper,
+			docs:   gatekeeperBundle,
+		},
+		{
+			name:        "empty",
+			engine:      v1alpha1.PolicyEngineKyverno,
+			docs:        "",
+			expectedErr: "no policy documents found",
+		},
+		{
+			name:        "kyverno policy for gatekeeper",
+			engine:      v1alpha1.PolicyEngineGatekeeper,
+			docs:        kyvernoPolicy,
+			expectedErr: `ClusterPolicy "require-labels": is not a gatekeeper policy resource, must be in a *.gatekeeper.sh group`,
+		},
+		{
+			name:   "kyverno policy without rules",
+			engine: v1alpha1.PolicyEngineKyverno,
+			docs: `
+apiVersion: kyverno.io/v1
+kind: Policy
+metadata:
+  name: empty
+spec: {}
+`,
+			expectedErr: `Policy "empty": must have at least one rule`,
+		},
+		{
+			name:   "non policy resource",
+			engine: v1alpha1.PolicyEngineKyverno,
+			docs: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`,
+			expectedErr: `ConfigMap "cm": is not a kyverno policy resource, must be one of [ClusterPolicy Policy PolicyException ClusterCleanupPolicy CleanupPolicy] in the kyverno.io group`,
+		},
+		{
+			name:   "constraint template without kind",
+			engine: v1alpha1.PolicyEngineGatekeeper,
+			docs: `
+apiVersion: templates.gatekeeper.sh/v1
+kind: ConstraintTemplate
+metadata:
+  name: broken
+spec:
+  targets:
+  - target: admission.k8s.gatekeeper.sh
+`,
+			expectedErr: `ConstraintTemplate "broken": must define spec.crd.spec.names.kind`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate(tt.engine, parse(t, tt.docs))
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestViolations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	report := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "wgpolicyk8s.io/v1alpha2",
+		"kind":       "PolicyReport",
+		"metadata":   map[string]interface{}{"name": "report", "namespace": "podinfo"},
+		"scope":      map[string]interface{}{"kind": "Pod", "namespace": "podinfo", "name": "podinfo-abc"},
+		"results": []interface{}{
+			map[string]interface{}{"policy": "require-labels", "rule": "check-team", "result": "fail", "message": "label team is required"},
+			map[string]interface{}{"policy": "require-labels", "rule": "check-owner", "result": "pass"},
+			map[string]interface{}{"policy": "someone-elses", "rule": "other", "result": "fail"},
+		},
+	}}
+	constraint := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+		"kind":       "K8sRequiredLabels",
+		"metadata":   map[string]interface{}{"name": "ns-must-have-owner"},
+		"status": map[string]interface{}{
+			"violations": []interface{}{
+				map[string]interface{}{"kind": "Namespace", "name": "podinfo", "message": "you must provide labels: {\"owner\"}"},
+			},
+		},
+	}}
+
+	scheme := runtime.NewScheme()
+	constraintGVR := schema.GroupVersionResource{Group: "constraints.gatekeeper.sh", Version: "v1beta1", Resource: "k8srequiredlabels"}
+	listKinds := map[schema.GroupVersionResource]string{
+		policyReportGVR:        "PolicyReportList",
+		clusterPolicyReportGVR: "ClusterPolicyReportList",
+		constraintGVR:          "K8sRequiredLabelsList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, report)
+	// Gatekeeper's plural resource name is not the one the fake client would guess from the kind.
+	_, err := client.Resource(constraintGVR).Create(ctx, constraint, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	findings, err := Violations(ctx, client, v1alpha1.PolicyEngineKyverno, parse(t, kyvernoPolicy))
+	require.NoError(t, err)
+	require.Equal(t, []Finding{{
+		Engine:    v1alpha1.PolicyEngineKyverno,
+		Policy:    "require-labels",
+		Rule:      "check-team",
+		Kind:      "Pod",
+		Namespace: "podinfo",
+		Name:      "podinfo-abc",
+		Message:   "label team is required",
+	}}, findings)
+	require.Equal(t, "Pod/podinfo/podinfo-abc", findings[0].Resource())
+
+	findings, err = Violations(ctx, client, v1alpha1.PolicyEngineGatekeeper, parse(t, gatekeeperBundle))
+	require.NoError(t, err)
+	require.Equal(t, []Finding{{
+		Engine:  v1alpha1.PolicyEngineGatekeeper,
+		Policy:  "ns-must-have-owner",
+		Rule:    "K8sRequiredLabels",
+		Kind:    "Namespace",
+		Name:    "podinfo",
+		Message: "you must provide labels: {\"owner\"}",
+	}}, findings)
+	require.Equal(t, "Namespace/podinfo", findings[0].Resource())
+}
+
+func TestAuditedGatekeeper(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	installedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	constraintGVR := schema.GroupVersionResource{Group: "constraints.gatekeeper.sh", Version: "v1beta1", Resource: "k8srequiredlabels"}
+	listKinds := map[schema.GroupVersionResource]string{
+		constraintGVR: "K8sRequiredLabelsList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+
+	// Nothing has been audited before the engine reports on the constraints.
+	audited, err := Audited(ctx, client, v1alpha1.PolicyEngineGatekeeper, parse(t, gatekeeperBundle), installedAt)
+	require.NoError(t, err)
+	require.False(t, audited)
+
+	// An audit from before the bundle was installed does not count.
+	constraint := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+		"kind":       "K8sRequiredLabels",
+		"metadata":   map[string]interface{}{"name": "ns-must-have-owner"},
+		"status":     map[string]interface{}{"auditTimestamp": "2024-06-01T11:59:00Z"},
+	}}
+	_, err = client.Resource(constraintGVR).Create(ctx, constraint, metav1.CreateOptions{})
+	require.NoError(t, err)
+	audited, err = Audited(ctx, client, v1alpha1.PolicyEngineGatekeeper, parse(t, gatekeeperBundle), installedAt)
+	require.NoError(t, err)
+	require.False(t, audited)
+
+	require.NoError(t, unstructured.SetNestedField(constraint.Object, "2024-06-01T12:01:00Z", "status", "auditTimestamp"))
+	_, err = client.Resource(constraintGVR).Update(ctx, constraint, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	audited, err = Audited(ctx, client, v1alpha1.PolicyEngineGatekeeper, parse(t, gatekeeperBundle), installedAt)
+	require.NoError(t, err)
+	require.True(t, audited)
+}
+
+const kyvernoNamespacedPolicy = `
+apiVersion: kyverno.io/v1
+kind: Policy
+metadata:
+  name: require-labels
+  namespace: podinfo
+spec:
+  rules:
+  - name: check-team
+`
+
+// kyvernoResource returns the given Kyverno policy with a Ready condition of the given status.
+func kyvernoResource(t *testing.T, doc string, ready metav1.ConditionStatus) *unstructured.Unstructured {
+	t.Helper()
+	obj := parse(t, doc)[0]
+	conditions := []interface{}{map[string]interface{}{"type": "Ready", "status": string(ready)}}
+	require.NoError(t, unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions"))
+	return obj
+}
+
+// kyvernoReport returns a policy report with a passing result for the given policy at the given time.
+func kyvernoReport(policy string, at time.Time) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "wgpolicyk8s.io/v1alpha2",
+		"kind":       "PolicyReport",
+		"metadata":   map[string]interface{}{"name": "report", "namespace": "podinfo"},
+		"results": []interface{}{
+			map[string]interface{}{
+				"policy":    policy,
+				"rule":      "check-team",
+				"result":    "pass",
+				"timestamp": map[string]interface{}{"seconds": at.Unix(), "nanos": int64(0)},
+			},
+		},
+	}}
+}
+
+func TestAuditedKyverno(t *testing.T) {
+	t.Parallel()
+
+	installedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clusterPolicyGVR := schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "clusterpolicies"}
+	policyGVR := schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "policies"}
+
+	tests := []struct {
+		name     string
+		doc      string
+		policy   *unstructured.Unstructured
+		report   *unstructured.Unstructured
+		expected bool
+	}{
+		{
+			name:     "policy not created yet",
+			doc:      kyvernoPolicy,
+			expected: false,
+		},
+		{
+			name:     "policy not ready",
+			doc:      kyvernoPolicy,
+			policy:   kyvernoResource(t, kyvernoPolicy, metav1.ConditionFalse),
+			expected: false,
+		},
+		{
+			name:     "ready policy that matches no resources",
+			doc:      kyvernoPolicy,
+			policy:   kyvernoResource(t, kyvernoPolicy, metav1.ConditionTrue),
+			expected: true,
+		},
+		{
+			name:     "result from before the install",
+			doc:      kyvernoPolicy,
+			policy:   kyvernoResource(t, kyvernoPolicy, metav1.ConditionTrue),
+			report:   kyvernoReport("require-labels", installedAt.Add(-time.Minute)),
+			expected: false,
+		},
+		{
+			name:     "result from after the install",
+			doc:      kyvernoPolicy,
+			policy:   kyvernoResource(t, kyvernoPolicy, metav1.ConditionTrue),
+			report:   kyvernoReport("require-labels", installedAt.Add(time.Minute)),
+			expected: true,
+		},
+		{
+			name:     "namespaced result from before the install",
+			doc:      kyvernoNamespacedPolicy,
+			policy:   kyvernoResource(t, kyvernoNamespacedPolicy, metav1.ConditionTrue),
+			report:   kyvernoReport("podinfo/require-labels", installedAt.Add(-time.Minute)),
+			expected: false,
+		},
+		{
+			name:     "namespaced result from after the install",
+			doc:      kyvernoNamespacedPolicy,
+			policy:   kyvernoResource(t, kyvernoNamespacedPolicy, metav1.ConditionTrue),
+			report:   kyvernoReport("podinfo/require-labels", installedAt.Add(time.Minute)),
+			expected: true,
+		},
+		{
+			name:     "policy installed into the namespace of its bundle",
+			doc:      strings.Replace(kyvernoNamespacedPolicy, "  namespace: podinfo\n", "", 1),
+			policy:   kyvernoResource(t, kyvernoNamespacedPolicy, metav1.ConditionTrue),
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			listKinds := map[schema.GroupVersionResource]string{
+				policyReportGVR:        "PolicyReportList",
+				clusterPolicyReportGVR: "ClusterPolicyReportList",
+				clusterPolicyGVR:       "ClusterPolicyList",
+				policyGVR:              "PolicyList",
+			}
+			client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+			if tt.policy != nil {
+				gvr := clusterPolicyGVR
+				if tt.policy.GetKind() == "Policy" {
+					gvr = policyGVR
+				}
+				_, err := client.Resource(gvr).Namespace(tt.policy.GetNamespace()).Create(ctx, tt.policy, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			if tt.report != nil {
+				_, err := client.Resource(policyReportGVR).Namespace(tt.report.GetNamespace()).Create(ctx, tt.report, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			audited, err := Audited(ctx, client, v1alpha1.PolicyEngineKyverno, parse(t, tt.doc), installedAt)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, audited)
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/anchore/stereoscope/pkg/file"
 	"github.com/anchore/stereoscope/pkg/image"
@@ -25,6 +27,7 @@ import (
 	"github.com/anchore/syft/syft/source"
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -38,6 +41,7 @@ type Builder struct {
 	cachePath  string
 	imagesPath string
 	outputDir  string
+	formats    []string
 	jsonList   []byte
 }
 
@@ -47,15 +51,44 @@ var transformRegex = regexp.MustCompile(`(?m)[^a-zA-Z0-9\.\-]`)
 
 var componentPrefix = "zarf-component-"
 
-// Catalog catalogs the given components and images to create an SBOM.
-func Catalog(componentSBOMs map[string]*layout.ComponentSBOM, imageList []transform.Image, paths *layout.PackagePaths) error {
-	imageCount := len(imageList)
+// imageSBOM is a single platform of an image to create an SBOM for.
+type imageSBOM struct {
+	refInfo transform.Image
+	// The identifier of the SBOM, the image reference followed by the architecture for images with multiple platforms.
+	identifier string
+	img        v1.Image
+}
+
+// Catalog catalogs the given components and images to create an SBOM, applying any redaction rules in imageRules
+// (keyed by image reference) to the image SBOMs. The SBOMs are created in syft JSON and in any other given formats.
+func Catalog(componentSBOMs map[string]*layout.ComponentSBOM, imageList []transform.Image, imageRules map[string]v1alpha1.ZarfComponentSBOM, formats []string, paths *layout.PackagePaths) error {
+	// Create an SBOM for every platform of the images in multi-architecture packages
+	imageSBOMs := []imageSBOM{}
+	for _, refInfo := range imageList {
+		imgs, err := utils.LoadOCIImages(paths.Images.Base, refInfo)
+		if err != nil {
+			return fmt.Errorf("unable to load the image to generate an SBOM: %w", err)
+		}
+		for arch, img := range imgs {
+			identifier := refInfo.Reference
+			if arch != "" {
+				identifier = fmt.Sprintf("%s-%s", refInfo.Reference, arch)
+			}
+			imageSBOMs = append(imageSBOMs, imageSBOM{refInfo: refInfo, identifier: identifier, img: img})
+		}
+	}
+	slices.SortFunc(imageSBOMs, func(a, b imageSBOM) int {
+		return strings.Compare(a.identifier, b.identifier)
+	})
+
+	imageCount := len(imageSBOMs)
 	componentCount := len(componentSBOMs)
 	builder := Builder{
-		spinner:    message.NewProgressSpinner("Creating SBOMs for %d images and %d components with files.", imageCount, componentCount),
+		spinner:    message.NewProgressSpinner("Creating SBOMs for %d images and %d components with files or repos.", imageCount, componentCount),
 		cachePath:  config.GetAbsCachePath(),
 		imagesPath: paths.Images.Base,
 		outputDir:  paths.SBOMs.Path,
+		formats:    formats,
 	}
 	defer builder.spinner.Stop()
 
@@ -63,7 +96,7 @@ func Catalog(componentSBOMs map[string]*layout.ComponentSBOM, imageList []transf
 	_ = helpers.CreateDirectory(builder.outputDir, helpers.ReadWriteExecuteUser)
 
 	// Generate a list of images and files for the sbom viewer
-	json, err := builder.generateJSONList(componentSBOMs, imageList)
+	json, err := builder.generateJSONList(componentSBOMs, imageSBOMs)
 	if err != nil {
 		builder.spinner.Errorf(err, "Unable to generate the SBOM image list")
 		return err
@@ -72,24 +105,22 @@ func Catalog(componentSBOMs map[string]*layout.ComponentSBOM, imageList []transf
 
 	// Generate SBOM for each image
 	currImage := 1
-	for _, refInfo := range imageList {
-		builder.spinner.Updatef("Creating image SBOMs (%d of %d): %s", currImage, imageCount, refInfo.Reference)
+	for _, image := range imageSBOMs {
+		builder.spinner.Updatef("Creating image SBOMs (%d of %d): %s", currImage, imageCount, image.identifier)
 
-		// Get the image that we are creating an SBOM for
-		img, err := utils.LoadOCIImage(paths.Images.Base, refInfo)
+		jsonData, err := builder.createImageSBOM(image.img, image.refInfo.Reference, image.identifier, imageRules[image.refInfo.Reference])
 		if err != nil {
-			builder.spinner.Errorf(err, "Unable to load the image to generate an SBOM")
+			builder.spinner.Errorf(err, "Unable to create SBOM for image %s", image.identifier)
 			return err
 		}
 
-		jsonData, err := builder.createImageSBOM(img, refInfo.Reference)
-		if err != nil {
-			builder.spinner.Errorf(err, "Unable to create SBOM for image %s", refInfo.Reference)
+		if err = builder.createFormatSBOMs(image.identifier, jsonData); err != nil {
+			builder.spinner.Errorf(err, "Unable to create SBOM formats for image %s", image.identifier)
 			return err
 		}
 
-		if err = builder.createSBOMViewerAsset(refInfo.Reference, jsonData); err != nil {
-			builder.spinner.Errorf(err, "Unable to create SBOM viewer for image %s", refInfo.Reference)
+		if err = builder.createSBOMViewerAsset(image.identifier, jsonData); err != nil {
+			builder.spinner.Errorf(err, "Unable to create SBOM viewer for image %s", image.identifier)
 			return err
 		}
 
@@ -100,7 +131,7 @@ func Catalog(componentSBOMs map[string]*layout.ComponentSBOM, imageList []transf
 
 	// Generate SBOM for each component
 	for component := range componentSBOMs {
-		builder.spinner.Updatef("Creating component file SBOMs (%d of %d): %s", currComponent, componentCount, component)
+		builder.spinner.Updatef("Creating component file and repo SBOMs (%d of %d): %s", currComponent, componentCount, component)
 
 		if componentSBOMs[component] == nil {
 			message.Debugf("Component %s has invalid SBOM, skipping", component)
@@ -113,6 +144,11 @@ func Catalog(componentSBOMs map[string]*layout.ComponentSBOM, imageList []transf
 			return err
 		}
 
+		if err = builder.createFormatSBOMs(fmt.Sprintf("%s%s", componentPrefix, component), jsonData); err != nil {
+			builder.spinner.Errorf(err, "Unable to create SBOM formats for component %s", component)
+			return err
+		}
+
 		if err = builder.createSBOMViewerAsset(fmt.Sprintf("%s%s", componentPrefix, component), jsonData); err != nil {
 			builder.spinner.Errorf(err, "Unable to create SBOM viewer for component %s", component)
 			return err
@@ -141,7 +177,7 @@ func Catalog(componentSBOMs map[string]*layout.ComponentSBOM, imageList []transf
 
 // createImageSBOM uses syft to generate SBOM for an image,
 // some code/structure migrated from https://github.com/testifysec/go-witness/blob/v0.1.12/attestation/syft/syft.go.
-func (b *Builder) createImageSBOM(img v1.Image, src string) ([]byte, error) {
+func (b *Builder) createImageSBOM(img v1.Image, src, identifier string, rules v1alpha1.ZarfComponentSBOM) ([]byte, error) {
 	// Get the image reference.
 	refInfo, err := transform.ParseImageRef(src)
 	if err != nil {
@@ -187,9 +223,13 @@ func (b *Builder) createImageSBOM(img v1.Image, src string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	jsonData, err = redact(jsonData, rules, "")
+	if err != nil {
+		return nil, err
+	}
 
-	// Write the sbom to disk using the image ref as the filename
-	filename := fmt.Sprintf("%s.json", refInfo.Reference)
+	// Write the sbom to disk using the identifier (the image ref and any architecture) as the filename
+	filename := fmt.Sprintf("%s.json", identifier)
 	sbomFile, err := b.createSBOMFile(filename)
 	if err != nil {
 		return nil, err
@@ -204,7 +244,7 @@ func (b *Builder) createImageSBOM(img v1.Image, src string) ([]byte, error) {
 	return jsonData, nil
 }
 
-// createPathSBOM uses syft to generate SBOM for a filepath.
+// createFileSBOM uses syft to generate SBOM for the files, data injections and git repositories of a component.
 func (b *Builder) createFileSBOM(componentSBOM layout.ComponentSBOM, component string) ([]byte, error) {
 	catalog := pkg.NewCollection()
 	relationships := []artifact.Relationship{}
@@ -214,6 +254,11 @@ func (b *Builder) createFileSBOM(componentSBOM layout.ComponentSBOM, component s
 	}
 
 	for _, sbomFile := range componentSBOM.Files {
+		// Leave excluded files out of the catalog entirely so their contents are never scanned
+		if excluded(componentSBOM.Rules, componentSBOM.Component.Base, sbomFile) {
+			continue
+		}
+
 		// Create the sbom source
 		fileSource, err := source.NewFromFile(source.FileConfig{Path: sbomFile})
 		if err != nil {
@@ -255,6 +300,46 @@ func (b *Builder) createFileSBOM(componentSBOM layout.ComponentSBOM, component s
 		}
 	}
 
+	for _, repoPath := range componentSBOM.Repos {
+		if excluded(componentSBOM.Rules, componentSBOM.Component.Base, repoPath) {
+			continue
+		}
+
+		// Catalog the working tree of the repository, leaving out its git objects
+		repoSource, err := source.NewFromDirectory(source.DirectoryConfig{
+			Path:    repoPath,
+			Exclude: source.ExcludeConfig{Paths: []string{"**/.git/**"}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		cat, rel, _, err := syft.CatalogPackages(repoSource, cataloger.DefaultConfig())
+		if err != nil {
+			return nil, err
+		}
+
+		for pkg := range cat.Enumerate() {
+			// Locations are relative to the root of the repository so make them match the paths of the component's files
+			locations := []syftFile.Location{}
+			for _, location := range pkg.Locations.ToSlice() {
+				locations = append(locations, syftFile.NewLocation(filepath.Join(repoPath, location.RealPath)))
+			}
+			pkg.Locations = syftFile.NewLocationSet(locations...)
+
+			catalog.Add(pkg)
+		}
+
+		for _, r := range rel {
+			relationships = append(relationships, artifact.Relationship{
+				From: parentSource,
+				To:   r.To,
+				Type: r.Type,
+				Data: r.Data,
+			})
+		}
+	}
+
 	artifact := sbom.SBOM{
 		Descriptor: sbom.Descriptor{
 			Name: "zarf",
@@ -271,6 +356,10 @@ func (b *Builder) createFileSBOM(componentSBOM layout.ComponentSBOM, component s
 	if err != nil {
 		return nil, err
 	}
+	jsonData, err = redact(jsonData, componentSBOM.Rules, componentSBOM.Component.Base)
+	if err != nil {
+		return nil, err
+	}
 
 	// Write the sbom to disk using the component prefix and name as the filename
 	filename := fmt.Sprintf("%s%s.json", componentPrefix, component)
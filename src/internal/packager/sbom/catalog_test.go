@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sbom
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+)
+
+const testGoMod = `module github.com/zarf-dev/podinfo
+
+go 1.22
+
+require %s v1.8.0
+`
+
+func TestCreateFileSBOMRepos(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	repoPath := filepath.Join(base, layout.ReposDir, "podinfo-1646971829")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, ".git", "modules"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "go.mod"), []byte(fmt.Sprintf(testGoMod, "github.com/spf13/cobra")), 0o600))
+	// Anything in the git directory must not be cataloged.
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, ".git", "modules", "go.mod"), []byte(fmt.Sprintf(testGoMod, "github.com/zarf-dev/hidden")), 0o600))
+
+	b := Builder{outputDir: t.TempDir()}
+	jsonData, err := b.createFileSBOM(layout.ComponentSBOM{
+		Repos:     []string{repoPath},
+		Component: &layout.ComponentPaths{Base: base},
+	}, "podinfo")
+	require.NoError(t, err)
+
+	sbom := string(jsonData)
+	require.Contains(t, sbom, `"name":"github.com/spf13/cobra"`)
+	require.Contains(t, sbom, filepath.ToSlash(filepath.Join(repoPath, "go.mod")))
+	require.NotContains(t, sbom, "hidden")
+	require.FileExists(t, filepath.Join(b.outputDir, "zarf-component-podinfo.json"))
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sbom contains tools for generating SBOMs.
+package sbom
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/anchore/syft/syft/format"
+	"github.com/anchore/syft/syft/format/cyclonedxjson"
+	"github.com/anchore/syft/syft/format/spdxjson"
+	"github.com/anchore/syft/syft/format/syftjson"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// The formats SBOMs can be created in, syft JSON is always created as it is what the SBOM viewer displays.
+const (
+	FormatSyftJSON      = "syft-json"
+	FormatSPDXJSON      = "spdx-json"
+	FormatCycloneDXJSON = "cyclonedx-json"
+)
+
+// Formats are the formats SBOMs can be created in.
+var Formats = []string{FormatSyftJSON, FormatSPDXJSON, FormatCycloneDXJSON}
+
+// formatExtensions are the file extensions of the SBOMs created in each format.
+var formatExtensions = map[string]string{
+	FormatSyftJSON:      ".json",
+	FormatSPDXJSON:      ".spdx.json",
+	FormatCycloneDXJSON: ".cdx.json",
+}
+
+// ValidateFormats returns an error if any of the given SBOM formats are not supported.
+func ValidateFormats(formats []string) error {
+	for _, f := range formats {
+		if _, ok := formatExtensions[f]; !ok {
+			return fmt.Errorf("unsupported SBOM format %q, must be one of %v", f, Formats)
+		}
+	}
+	return nil
+}
+
+func newFormatEncoder(f string) (sbom.FormatEncoder, error) {
+	switch f {
+	case FormatSPDXJSON:
+		return spdxjson.NewFormatEncoderWithConfig(spdxjson.DefaultEncoderConfig())
+	case FormatCycloneDXJSON:
+		return cyclonedxjson.NewFormatEncoderWithConfig(cyclonedxjson.DefaultEncoderConfig())
+	default:
+		return syftjson.NewFormatEncoder(), nil
+	}
+}
+
+// createFormatSBOMs writes the SBOM in each of the additional formats, converting from the redacted syft JSON so that
+// the redaction rules apply to every format.
+func (b *Builder) createFormatSBOMs(identifier string, jsonData []byte) error {
+	var artifact *sbom.SBOM
+	for _, f := range b.formats {
+		if f == FormatSyftJSON {
+			continue
+		}
+		if artifact == nil {
+			var err error
+			artifact, _, _, err = syftjson.NewFormatDecoder().Decode(bytes.NewReader(jsonData))
+			if err != nil {
+				return fmt.Errorf("unable to read the SBOM of %s: %w", identifier, err)
+			}
+		}
+		encoder, err := newFormatEncoder(f)
+		if err != nil {
+			return err
+		}
+		data, err := format.Encode(*artifact, encoder)
+		if err != nil {
+			return fmt.Errorf("unable to convert the SBOM of %s to %s: %w", identifier, f, err)
+		}
+		sbomFile, err := b.createSBOMFile(identifier + formatExtensions[f])
+		if err != nil {
+			return err
+		}
+		_, err = sbomFile.Write(data)
+		sbomFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sbom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/syft/syft/format"
+	"github.com/anchore/syft/syft/format/syftjson"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFormats(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateFormats(nil))
+	require.NoError(t, ValidateFormats([]string{FormatSyftJSON, FormatSPDXJSON, FormatCycloneDXJSON}))
+	require.EqualError(t, ValidateFormats([]string{"spdx-tag-value"}), `unsupported SBOM format "spdx-tag-value", must be one of [syft-json spdx-json cyclonedx-json]`)
+}
+
+func TestCreateFormatSBOMs(t *testing.T) {
+	t.Parallel()
+
+	collection := pkg.NewCollection(pkg.Package{Name: "openssl", Version: "3.1.4", Type: pkg.ApkPkg})
+	jsonData, err := format.Encode(sbom.SBOM{
+		Descriptor: sbom.Descriptor{Name: "zarf"},
+		Source:     source.Description{ID: "doom", Name: "ghcr.io/zarf-dev/doom:1.0.0", Metadata: source.StereoscopeImageSourceMetadata{UserInput: "ghcr.io/zarf-dev/doom:1.0.0"}},
+		Artifacts:  sbom.Artifacts{Packages: collection},
+	}, syftjson.NewFormatEncoder())
+	require.NoError(t, err)
+
+	b := Builder{outputDir: t.TempDir(), formats: []string{FormatSyftJSON, FormatSPDXJSON, FormatCycloneDXJSON}}
+	require.NoError(t, b.createFormatSBOMs("ghcr.io/zarf-dev/doom:1.0.0", jsonData))
+
+	spdx, err := os.ReadFile(filepath.Join(b.outputDir, "ghcr.io_zarf-dev_doom_1.0.0.spdx.json"))
+	require.NoError(t, err)
+	require.Contains(t, string(spdx), `"spdxVersion"`)
+	require.Contains(t, string(spdx), `"openssl"`)
+
+	cdx, err := os.ReadFile(filepath.Join(b.outputDir, "ghcr.io_zarf-dev_doom_1.0.0.cdx.json"))
+	require.NoError(t, err)
+	require.Contains(t, string(cdx), `"bomFormat":"CycloneDX"`)
+	require.Contains(t, string(cdx), `"openssl"`)
+
+	// The syft JSON SBOM is written by the catalog itself.
+	require.NoFileExists(t, filepath.Join(b.outputDir, "ghcr.io_zarf-dev_doom_1.0.0.json"))
+}
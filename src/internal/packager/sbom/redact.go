@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sbom
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// digestKeys are the syft JSON keys that hold file hashes in packages and files.
+var digestKeys = []string{"digest", "digests", "h1Digest"}
+
+// excluded returns if path matches any of the rules' exclude patterns once made relative to root.
+func excluded(rules v1alpha1.ZarfComponentSBOM, root, path string) bool {
+	if root != "" {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return false
+		}
+		path = rel
+	}
+	path = filepath.ToSlash(path)
+	for _, pattern := range rules.ExcludePaths {
+		if ok, _ := doublestar.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redact applies the redaction rules to a syft JSON SBOM, returning the SBOM unchanged if there are no rules.
+//
+// Packages located only in excluded paths are removed along with their relationships, excluded files are removed,
+// license entries are emptied when stripping licenses and any digests are removed when omitting file hashes.
+// Paths are made relative to root before being matched against the exclude patterns.
+func redact(jsonData []byte, rules v1alpha1.ZarfComponentSBOM, root string) ([]byte, error) {
+	if !rules.IsRedacted() {
+		return jsonData, nil
+	}
+
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, err
+	}
+
+	removed := map[string]bool{}
+	artifacts := []interface{}{}
+	for _, a := range asSlice(doc["artifacts"]) {
+		artifact, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		locations := asSlice(artifact["locations"])
+		isExcluded := len(rules.ExcludePaths) > 0 && len(locations) > 0
+		for _, l := range locations {
+			location, _ := l.(map[string]interface{})
+			path, _ := location["path"].(string)
+			if !excluded(rules, root, path) {
+				isExcluded = false
+				break
+			}
+		}
+		if isExcluded {
+			id, _ := artifact["id"].(string)
+			removed[id] = true
+			continue
+		}
+		if rules.StripLicenses {
+			artifact["licenses"] = []interface{}{}
+		}
+		if rules.OmitFileHashes {
+			deleteKeys(artifact["metadata"], digestKeys...)
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	doc["artifacts"] = artifacts
+
+	if files, ok := doc["files"]; ok {
+		kept := []interface{}{}
+		for _, f := range asSlice(files) {
+			file, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			location, _ := file["location"].(map[string]interface{})
+			path, _ := location["path"].(string)
+			if excluded(rules, root, path) {
+				id, _ := file["id"].(string)
+				removed[id] = true
+				continue
+			}
+			if rules.OmitFileHashes {
+				deleteKeys(file, digestKeys...)
+			}
+			kept = append(kept, file)
+		}
+		doc["files"] = kept
+	}
+
+	if relationships, ok := doc["artifactRelationships"]; ok {
+		kept := []interface{}{}
+		for _, r := range asSlice(relationships) {
+			relationship, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			parent, _ := relationship["parent"].(string)
+			child, _ := relationship["child"].(string)
+			if removed[parent] || removed[child] {
+				continue
+			}
+			kept = append(kept, relationship)
+		}
+		doc["artifactRelationships"] = kept
+	}
+
+	return json.Marshal(doc)
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+// deleteKeys removes the given keys from every object nested in v.
+func deleteKeys(v interface{}, keys ...string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, key := range keys {
+			delete(t, key)
+		}
+		for _, child := range t {
+			deleteKeys(child, keys...)
+		}
+	case []interface{}:
+		for _, child := range t {
+			deleteKeys(child, keys...)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+const testSBOM = `{
+  "artifacts": [
+    {"id": "a", "name": "openssl", "licenses": [{"value": "Apache-2.0"}], "locations": [{"path": "/lib/apk/db/installed"}],
+     "metadata": {"files": [{"path": "/usr/lib/libssl.so", "digest": {"algorithm": "sha1", "value": "abc"}}]}},
+    {"id": "b", "name": "secret-lib", "licenses": [], "locations": [{"path": "/opt/classified/lib.jar"}],
+     "metadata": {"digest": [{"algorithm": "sha1", "value": "def"}]}}
+  ],
+  "artifactRelationships": [
+    {"parent": "a", "child": "f1", "type": "contains"},
+    {"parent": "b", "child": "f2", "type": "contains"}
+  ],
+  "files": [
+    {"id": "f1", "location": {"path": "/usr/lib/libssl.so"}, "digests": [{"algorithm": "sha1", "value": "abc"}]},
+    {"id": "f2", "location": {"path": "/opt/classified/lib.jar"}, "digests": [{"algorithm": "sha1", "value": "def"}]}
+  ],
+  "source": {"id": "src"}
+}`
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rules    v1alpha1.ZarfComponentSBOM
+		root     string
+		expected string
+	}{
+		{
+			name:     "no rules",
+			rules:    v1alpha1.ZarfComponentSBOM{},
+			expected: testSBOM,
+		},
+		{
+			name:  "exclude paths",
+			rules: v1alpha1.ZarfComponentSBOM{ExcludePaths: []string{"/opt/classified/**"}},
+			expected: `{
+  "artifacts": [
+    {"id": "a", "name": "openssl", "licenses": [{"value": "Apache-2.0"}], "locations": [{"path": "/lib/apk/db/installed"}],
+     "metadata": {"files": [{"path": "/usr/lib/libssl.so", "digest": {"algorithm": "sha1", "value": "abc"}}]}}
+  ],
+  "artifactRelationships": [{"parent": "a", "child": "f1", "type": "contains"}],
+  "files": [{"id": "f1", "location": {"path": "/usr/lib/libssl.so"}, "digests": [{"algorithm": "sha1", "value": "abc"}]}],
+  "source": {"id": "src"}
+}`,
+		},
+		{
+			name:  "exclude paths relative to root",
+			rules: v1alpha1.ZarfComponentSBOM{ExcludePaths: []string{"classified/*"}},
+			root:  "/opt",
+			expected: `{
+  "artifacts": [
+    {"id": "a", "name": "openssl", "licenses": [{"value": "Apache-2.0"}], "locations": [{"path": "/lib/apk/db/installed"}],
+     "metadata": {"files": [{"path": "/usr/lib/libssl.so", "digest": {"algorithm": "sha1", "value": "abc"}}]}}
+  ],
+  "artifactRelationships": [{"parent": "a", "child": "f1", "type": "contains"}],
+  "files": [{"id": "f1", "location": {"path": "/usr/lib/libssl.so"}, "digests": [{"algorithm": "sha1", "value": "abc"}]}],
+  "source": {"id": "src"}
+}`,
+		},
+		{
+			name:  "strip licenses and omit file hashes",
+			rules: v1alpha1.ZarfComponentSBOM{StripLicenses: true, OmitFileHashes: true},
+			expected: `{
+  "artifacts": [
+    {"id": "a", "name": "openssl", "licenses": [], "locations": [{"path": "/lib/apk/db/installed"}],
+     "metadata": {"files": [{"path": "/usr/lib/libssl.so"}]}},
+    {"id": "b", "name": "secret-lib", "licenses": [], "locations": [{"path": "/opt/classified/lib.jar"}], "metadata": {}}
+  ],
+  "artifactRelationships": [
+    {"parent": "a", "child": "f1", "type": "contains"},
+    {"parent": "b", "child": "f2", "type": "contains"}
+  ],
+  "files": [
+    {"id": "f1", "location": {"path": "/usr/lib/libssl.so"}},
+    {"id": "f2", "location": {"path": "/opt/classified/lib.jar"}}
+  ],
+  "source": {"id": "src"}
+}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			redacted, err := redact([]byte(testSBOM), tt.rules, tt.root)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(redacted))
+		})
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"html/template"
 
 	"github.com/zarf-dev/zarf/src/pkg/layout"
-	"github.com/zarf-dev/zarf/src/pkg/transform"
 )
 
 func (b *Builder) createSBOMViewerAsset(identifier string, jsonData []byte) error {
@@ -73,11 +72,11 @@ func (b *Builder) loadFileJS(name string) template.JS {
 }
 
 // This could be optimized, but loop over all the images and components to create a list of json files.
-func (b *Builder) generateJSONList(componentToFiles map[string]*layout.ComponentSBOM, imageList []transform.Image) ([]byte, error) {
+func (b *Builder) generateJSONList(componentToFiles map[string]*layout.ComponentSBOM, imageSBOMs []imageSBOM) ([]byte, error) {
 	var jsonList []string
 
-	for _, refInfo := range imageList {
-		normalized := b.getNormalizedFileName(refInfo.Reference)
+	for _, image := range imageSBOMs {
+		normalized := b.getNormalizedFileName(image.identifier)
 		jsonList = append(jsonList, normalized)
 	}
 
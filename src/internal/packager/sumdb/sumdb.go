@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sumdb records the sha256 of remote files the first time they are fetched so later fetches can be verified.
+package sumdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
+)
+
+// FileName is the name of the checksum database in the Zarf cache.
+const FileName = "checksums.json"
+
+// ErrMismatch is returned when a fetched file does not match the sha256 recorded for its URL.
+var ErrMismatch = errors.New("checksum does not match the checksum database")
+
+// DB maps remote file URLs to the sha256 of their contents when they were first fetched.
+type DB struct {
+	// The sha256 of each remote file keyed by its URL.
+	Files map[string]string `json:"files"`
+
+	path string
+}
+
+// Path returns the path of the checksum database in the Zarf cache.
+func Path() string {
+	return filepath.Join(config.GetAbsCachePath(), FileName)
+}
+
+// Load reads the checksum database at path, returning an empty database if it does not exist yet.
+func Load(path string) (*DB, error) {
+	db := &DB{Files: map[string]string{}, path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, db); err != nil {
+		return nil, fmt.Errorf("unable to read the checksum database %s: %w", path, err)
+	}
+	if db.Files == nil {
+		db.Files = map[string]string{}
+	}
+	return db, nil
+}
+
+// Lookup returns the sha256 recorded for the given URL. A nil database has no records.
+func (db *DB) Lookup(url string) (string, bool) {
+	if db == nil {
+		return "", false
+	}
+	sum, ok := db.Files[url]
+	return sum, ok
+}
+
+// Verify checks the file at path fetched from url against the sha256 recorded for the URL, recording it if the URL has
+// not been fetched before.
+func (db *DB) Verify(url, path string) error {
+	sum, err := helpers.GetSHA256OfFile(path)
+	if err != nil {
+		return err
+	}
+	recorded, ok := db.Files[url]
+	if !ok {
+		db.Files[url] = sum
+		return nil
+	}
+	if recorded != sum {
+		return fmt.Errorf("%w: %s has a sha256 of %s but %s was recorded in %s", ErrMismatch, url, sum, recorded, db.path)
+	}
+	return nil
+}
+
+// Save writes the checksum database back to the path it was loaded from.
+func (db *DB) Save() error {
+	b, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := helpers.CreateParentDirectory(db.path); err != nil {
+		return err
+	}
+	// Write to a temporary file first so that a concurrent create never reads a partially written database.
+	tmp, err := os.CreateTemp(filepath.Dir(db.path), FileName+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), db.path)
+}
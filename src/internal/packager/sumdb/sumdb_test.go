@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sumdb records the sha256 of remote files the first time they are fetched so later fetches can be verified.
+package sumdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache", FileName)
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, []byte("known good"), 0o600))
+
+	db, err := Load(path)
+	require.NoError(t, err)
+	_, ok := db.Lookup("https://example.com/file")
+	require.False(t, ok)
+
+	// The first fetch of a URL is recorded and later fetches are verified against it.
+	require.NoError(t, db.Verify("https://example.com/file", file))
+	require.NoError(t, db.Save())
+	db, err = Load(path)
+	require.NoError(t, err)
+	require.NoError(t, db.Verify("https://example.com/file", file))
+
+	require.NoError(t, os.WriteFile(file, []byte("tampered"), 0o600))
+	err = db.Verify("https://example.com/file", file)
+	require.ErrorIs(t, err, ErrMismatch)
+
+	var nilDB *DB
+	_, ok = nilDB.Lookup("https://example.com/file")
+	require.False(t, ok)
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log/slog"
+	"net"
 	"strings"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
@@ -75,13 +76,23 @@ func GetZarfTemplates(componentName string, state *types.ZarfState) (templateMap
 			builtinMap["AGENT_CA"] = base64.StdEncoding.EncodeToString(agentTLS.CA)
 
 		case "zarf-seed-registry", "zarf-registry":
-			builtinMap["SEED_REGISTRY"] = fmt.Sprintf("%s:%s", helpers.IPV4Localhost, config.ZarfSeedPort)
+			// The seed registry is reached on the same loopback address as the registry NodePort
+			seedHost := helpers.IPV4Localhost
+			if host, _, err := net.SplitHostPort(regInfo.Address); err == nil && host == types.IPV6Localhost {
+				seedHost = host
+			}
+			builtinMap["SEED_REGISTRY"] = net.JoinHostPort(seedHost, config.ZarfSeedPort)
 			htpasswd, err := generateHtpasswd(&regInfo)
 			if err != nil {
 				return templateMap, err
 			}
 			builtinMap["HTPASSWD"] = htpasswd
 			builtinMap["REGISTRY_SECRET"] = regInfo.Secret
+			// Registries initialized before the registry type was recorded run distribution
+			builtinMap["REGISTRY_TYPE"] = string(types.RegistryTypeDistribution)
+			if regInfo.Type != "" {
+				builtinMap["REGISTRY_TYPE"] = string(regInfo.Type)
+			}
 		}
 
 		// Iterate over any custom variables and add them to the mappings for templating
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// KubeContextResult is the result of an operation against the cluster of one kubeconfig context.
+type KubeContextResult struct {
+	KubeContext string
+	Duration    time.Duration
+	Err         error
+}
+
+// ForEachKubeContext runs fn with each kubeconfig context in turn, moving on to the next context when it fails, and
+// returns the result for each context along with the errors of the contexts that failed.
+func ForEachKubeContext(ctx context.Context, kubeContexts []string, fn func(ctx context.Context, kubeContext string) error) ([]KubeContextResult, error) {
+	results := []KubeContextResult{}
+	errs := []error{}
+	for _, kubeContext := range kubeContexts {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		start := time.Now()
+		err := fn(ctx, kubeContext)
+		results = append(results, KubeContextResult{KubeContext: kubeContext, Duration: time.Since(start), Err: err})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", kubeContext, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
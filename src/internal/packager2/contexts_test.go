@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager2
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestForEachKubeContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	deployed := []string{}
+	results, err := ForEachKubeContext(ctx, []string{"dev", "staging", "prod"}, func(_ context.Context, kubeContext string) error {
+		deployed = append(deployed, kubeContext)
+		if kubeContext == "staging" {
+			return errors.New("deploy failed")
+		}
+		return nil
+	})
+	require.EqualError(t, err, "staging: deploy failed")
+	require.Equal(t, []string{"dev", "staging", "prod"}, deployed)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.EqualError(t, results[1].Err, "deploy failed")
+	require.NoError(t, results[2].Err)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	results, err = ForEachKubeContext(cancelled, []string{"dev"}, func(_ context.Context, _ string) error {
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, results)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/defenseunicorns/pkg/oci"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/test/testutil"
@@ -83,3 +84,39 @@ func TestSupportsFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestNameFromMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		builder  *testutil.PackageBuilder
+		expected string
+	}{
+		{
+			name:     "package without version",
+			builder:  testutil.NewPackageBuilder("test").WithArchitecture("arm64"),
+			expected: "zarf-package-test-arm64.tar.zst",
+		},
+		{
+			name:     "package with version",
+			builder:  testutil.NewPackageBuilder("test").WithVersion("1.0.0"),
+			expected: "zarf-package-test-amd64-1.0.0.tar.zst",
+		},
+		{
+			name:     "init package",
+			builder:  testutil.NewPackageBuilder("init").WithKind(v1alpha1.ZarfInitConfig),
+			expected: "zarf-init-amd64.tar.zst",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tarPath := tt.builder.WriteTarball(t, t.TempDir())
+			name, err := nameFromMetadata(tarPath)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, name)
+		})
+	}
+}
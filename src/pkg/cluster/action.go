@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+const (
+	// actionCopyContainerName is the name of the init container the working directory of an action is copied into.
+	actionCopyContainerName = "copy"
+	// actionContainerName is the name of the container that runs the command of an action.
+	actionContainerName = "action"
+	// actionReadyMarker is the file that is written once the working directory of an action has been copied.
+	actionReadyMarker = ".zarf-action-ready"
+)
+
+// ActionJobOptions are the options for running the command of a component action in a Job.
+type ActionJobOptions struct {
+	// The image to run the command in
+	Image string
+	// The absolute local directory that is copied into the Job to run the command in, at the same path
+	Dir string
+	// The command to run in the sh of the image
+	Cmd string
+	// Environment variables for the command in the form KEY=VALUE
+	Env []string
+}
+
+// actionJob returns the Job that runs the command of an action once its working directory is copied into the init
+// container, reading its environment from the given secret.
+func actionJob(opts ActionJobOptions, image, secretName string, pullSecrets []corev1.LocalObjectReference) *batchv1.Job {
+	backoffLimit := int32(0)
+	volumeMounts := []corev1.VolumeMount{{Name: "workdir", MountPath: opts.Dir}}
+	waitForCopy := fmt.Sprintf(`until [ -f %[1]q ]; do sleep 1; done; rm %[1]q`, path.Join(opts.Dir, actionReadyMarker))
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "zarf-action-",
+			Namespace:    ZarfNamespaceName,
+			Labels:       map[string]string{ZarfManagedByLabel: "zarf"},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{ZarfManagedByLabel: "zarf"},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: pullSecrets,
+					InitContainers: []corev1.Container{
+						{
+							Name:         actionCopyContainerName,
+							Image:        image,
+							Command:      []string{"sh", "-c", waitForCopy},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:         actionContainerName,
+							Image:        image,
+							Command:      []string{"sh", "-e", "-c", opts.Cmd},
+							WorkingDir:   opts.Dir,
+							EnvFrom:      []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secretName}}}},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name:         "workdir",
+							VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// actionImage returns the image an action is run in and the secrets to pull it with, which is the image in the Zarf
+// registry once the cluster has been initialized and the image as given before then.
+func (c *Cluster) actionImage(ctx context.Context, image string) (string, []corev1.LocalObjectReference, error) {
+	state, err := c.LoadZarfState(ctx)
+	if err != nil || state.RegistryInfo.Address == "" {
+		message.Debugf("Running the action image %s as given as the cluster has not been initialized", image)
+		return image, nil, nil
+	}
+	image, err = transform.ImageTransformHost(state.RegistryInfo.Address, image)
+	if err != nil {
+		return "", nil, err
+	}
+	return image, []corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}}, nil
+}
+
+// RunActionJob runs the command of an action in a Job in the Zarf namespace with a copy of its working directory,
+// writing the output of the command to out and returning it. The image must provide sh and tar.
+func (c *Cluster) RunActionJob(ctx context.Context, opts ActionJobOptions, out io.Writer) (string, error) {
+	image, pullSecrets, err := c.actionImage(ctx, opts.Image)
+	if err != nil {
+		return "", err
+	}
+
+	env := map[string][]byte{}
+	for _, e := range opts.Env {
+		key, value, _ := strings.Cut(e, "=")
+		env[key] = []byte(value)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "zarf-action-",
+			Namespace:    ZarfNamespaceName,
+			Labels:       map[string]string{ZarfManagedByLabel: "zarf"},
+		},
+		Data: env,
+	}
+	secret, err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to create the action environment: %w", err)
+	}
+	job, err := c.Clientset.BatchV1().Jobs(ZarfNamespaceName).Create(ctx, actionJob(opts, image, secret.Name, pullSecrets), metav1.CreateOptions{})
+	// The Job and its environment are removed even if the action is cancelled.
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		if job != nil && job.Name != "" {
+			err := c.Clientset.BatchV1().Jobs(ZarfNamespaceName).Delete(context.Background(), job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil {
+				message.Debugf("unable to delete the action job %s: %s", job.Name, err.Error())
+			}
+		}
+		if err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Delete(context.Background(), secret.Name, metav1.DeleteOptions{}); err != nil {
+			message.Debugf("unable to delete the action secret %s: %s", secret.Name, err.Error())
+		}
+	}()
+	if err != nil {
+		return "", fmt.Errorf("unable to create the action job: %w", err)
+	}
+
+	selector := fmt.Sprintf("job-name=%s", job.Name)
+	pods, err := waitForPodsAndContainers(ctx, c.Clientset, podLookup{Namespace: ZarfNamespaceName, Selector: selector, Container: actionCopyContainerName}, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to start the action job %s: %w", job.Name, err)
+	}
+	pod := pods[0]
+	if err := c.copyActionDir(ctx, pod, opts.Dir); err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := c.streamActionLogs(ctx, pod, io.MultiWriter(buf, out)); err != nil {
+		return "", err
+	}
+	exitCode, err := c.actionExitCode(ctx, pod)
+	if err != nil {
+		return "", err
+	}
+	if exitCode != 0 {
+		return buf.String(), fmt.Errorf("the action job %s exited with code %d", job.Name, exitCode)
+	}
+	return buf.String(), nil
+}
+
+// copyActionDir copies a local directory into the init container of an action and writes the marker that starts it.
+func (c *Cluster) copyActionDir(ctx context.Context, pod corev1.Pod, dir string) error {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeActionTar(writer, dir))
+	}()
+	// Closing the reader stops the writer if the copy fails before it has read the whole directory.
+	defer reader.Close()
+
+	untar := fmt.Sprintf(`tar -x -f - -C %[1]q && touch %[2]q`, dir, path.Join(dir, actionReadyMarker))
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: actionCopyContainerName,
+			Command:   []string{"sh", "-c", untar},
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(c.RestConfig, http.MethodPost, req.URL())
+	if err != nil {
+		return err
+	}
+	stderr := &bytes.Buffer{}
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdin: reader, Stdout: io.Discard, Stderr: stderr})
+	if err != nil {
+		return fmt.Errorf("unable to copy %s into the action pod %s: %w: %s", dir, pod.Name, err, stderr.String())
+	}
+	return nil
+}
+
+// writeActionTar writes the regular files, directories and symlinks of a directory to a tar stream.
+func writeActionTar(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		if rel == "." || !(fi.Mode().IsRegular() || fi.IsDir() || fi.Mode()&os.ModeSymlink != 0) {
+			return nil
+		}
+		link := ""
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// streamActionLogs follows the logs of the action container until it exits.
+func (c *Cluster) streamActionLogs(ctx context.Context, pod corev1.Pod, out io.Writer) error {
+	// The logs can only be followed once the container has started.
+	err := retry.Do(func() error {
+		logs, err := c.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: actionContainerName, Follow: true}).Stream(ctx)
+		if err != nil {
+			if reason := actionWaitingReason(ctx, c, pod); reason == "ErrImagePull" || reason == "ImagePullBackOff" {
+				return retry.Unrecoverable(fmt.Errorf("unable to pull the action image: %s", reason))
+			}
+			return err
+		}
+		defer logs.Close()
+		_, err = io.Copy(out, logs)
+		return err
+	}, retry.Context(ctx), retry.Attempts(0), retry.DelayType(retry.FixedDelay), retry.Delay(time.Second), retry.LastErrorOnly(true))
+	if err != nil {
+		return fmt.Errorf("unable to get the output of the action pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// actionWaitingReason returns the reason the action container is waiting to start, if it is.
+func actionWaitingReason(ctx context.Context, c *Cluster, pod corev1.Pod) string {
+	current, err := c.Clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	for _, status := range current.Status.ContainerStatuses {
+		if status.Name == actionContainerName && status.State.Waiting != nil {
+			return status.State.Waiting.Reason
+		}
+	}
+	return ""
+}
+
+// actionExitCode waits for the action container to exit and returns its exit code.
+func (c *Cluster) actionExitCode(ctx context.Context, pod corev1.Pod) (int32, error) {
+	return retry.DoWithData(func() (int32, error) {
+		current, err := c.Clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		for _, status := range current.Status.ContainerStatuses {
+			if status.Name == actionContainerName && status.State.Terminated != nil {
+				return status.State.Terminated.ExitCode, nil
+			}
+		}
+		return 0, errors.New("the action container has not exited")
+	}, retry.Context(ctx), retry.Attempts(0), retry.DelayType(retry.FixedDelay), retry.Delay(time.Second), retry.LastErrorOnly(true))
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestActionJob(t *testing.T) {
+	t.Parallel()
+
+	opts := ActionJobOptions{Image: "alpine:3.20", Dir: "/tmp/package", Cmd: "ls"}
+	pullSecrets := []corev1.LocalObjectReference{{Name: "private-registry"}}
+	job := actionJob(opts, "127.0.0.1:31999/library/alpine:3.20-zarf-1234", "zarf-action-abcd", pullSecrets)
+
+	require.Equal(t, ZarfNamespaceName, job.Namespace)
+	require.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	spec := job.Spec.Template.Spec
+	require.Equal(t, corev1.RestartPolicyNever, spec.RestartPolicy)
+	require.Equal(t, pullSecrets, spec.ImagePullSecrets)
+	require.Len(t, spec.InitContainers, 1)
+	require.Equal(t, []string{"sh", "-c", `until [ -f "/tmp/package/.zarf-action-ready" ]; do sleep 1; done; rm "/tmp/package/.zarf-action-ready"`}, spec.InitContainers[0].Command)
+	require.Len(t, spec.Containers, 1)
+	container := spec.Containers[0]
+	require.Equal(t, "127.0.0.1:31999/library/alpine:3.20-zarf-1234", container.Image)
+	require.Equal(t, []string{"sh", "-e", "-c", "ls"}, container.Command)
+	require.Equal(t, "/tmp/package", container.WorkingDir)
+	require.Equal(t, "zarf-action-abcd", container.EnvFrom[0].SecretRef.Name)
+	require.Equal(t, []corev1.VolumeMount{{Name: "workdir", MountPath: "/tmp/package"}}, container.VolumeMounts)
+	require.Equal(t, container.VolumeMounts, spec.InitContainers[0].VolumeMounts)
+}
+
+func TestActionImage(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	image, pullSecrets, err := c.actionImage(ctx, "alpine:3.20")
+	require.NoError(t, err)
+	require.Equal(t, "alpine:3.20", image)
+	require.Empty(t, pullSecrets)
+}
+
+func TestWriteActionTar(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "scripts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("echo hello"), 0o755))
+	require.NoError(t, os.Symlink("scripts/run.sh", filepath.Join(dir, "run.sh")))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeActionTar(buf, dir))
+
+	entries := map[string]*tar.Header{}
+	contents := map[string]string{}
+	tr := tar.NewReader(buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		entries[hdr.Name] = hdr
+		b, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		contents[hdr.Name] = string(b)
+	}
+	require.Len(t, entries, 3)
+	require.Equal(t, byte(tar.TypeDir), entries["scripts"].Typeflag)
+	require.Equal(t, "echo hello", contents["scripts/run.sh"])
+	require.Equal(t, int64(0o755), entries["scripts/run.sh"].Mode&0o777)
+	require.Equal(t, byte(tar.TypeSymlink), entries["run.sh"].Typeflag)
+	require.Equal(t, "scripts/run.sh", entries["run.sh"].Linkname)
+}
@@ -14,11 +14,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 
 	"github.com/avast/retry-go/v4"
 	pkgkubernetes "github.com/defenseunicorns/pkg/kubernetes"
 
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 )
 
@@ -34,14 +37,26 @@ type Cluster struct {
 	Clientset  kubernetes.Interface
 	RestConfig *rest.Config
 	Watcher    watcher.StatusWatcher
+
+	kubeContext string
+}
+
+// Modifier is a function that modifies the connection to the cluster.
+type Modifier func(*Cluster)
+
+// WithKubeContext connects to the cluster of the given kubeconfig context instead of the current context.
+func WithKubeContext(kubeContext string) Modifier {
+	return func(c *Cluster) {
+		c.kubeContext = kubeContext
+	}
 }
 
 // NewClusterWithWait creates a new Cluster instance and waits for the given timeout for the cluster to be ready.
-func NewClusterWithWait(ctx context.Context) (*Cluster, error) {
+func NewClusterWithWait(ctx context.Context, mods ...Modifier) (*Cluster, error) {
 	spinner := message.NewProgressSpinner("Waiting for cluster connection")
 	defer spinner.Stop()
 
-	c, err := NewCluster()
+	c, err := NewCluster(mods...)
 	if err != nil {
 		return nil, err
 	}
@@ -74,21 +89,30 @@ func NewClusterWithWait(ctx context.Context) (*Cluster, error) {
 }
 
 // NewCluster creates a new Cluster instance and validates connection to the cluster by fetching the Kubernetes version.
-func NewCluster() (*Cluster, error) {
+func NewCluster(mods ...Modifier) (*Cluster, error) {
+	c := &Cluster{}
+	for _, mod := range mods {
+		mod(c)
+	}
+
 	clusterErr := errors.New("unable to connect to the cluster")
-	clientset, config, err := pkgkubernetes.ClientAndConfig()
+	restConfig, err := kubeClientConfig(c.kubeContext).ClientConfig()
 	if err != nil {
 		return nil, errors.Join(clusterErr, err)
 	}
-	watcher, err := pkgkubernetes.WatcherForConfig(config)
+	// Limit the rate of requests so that large deploys do not overwhelm small API servers.
+	throttle(restConfig, config.CommonOptions.KubeQPS, config.CommonOptions.KubeBurst)
+	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, errors.Join(clusterErr, err)
 	}
-	c := &Cluster{
-		Clientset:  clientset,
-		RestConfig: config,
-		Watcher:    watcher,
+	watcher, err := pkgkubernetes.WatcherForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Join(clusterErr, err)
 	}
+	c.Clientset = clientset
+	c.RestConfig = restConfig
+	c.Watcher = watcher
 	// Dogsled the version output. We just want to ensure no errors were returned to validate cluster connection.
 	_, err = c.Clientset.Discovery().ServerVersion()
 	if err != nil {
@@ -96,3 +120,56 @@ func NewCluster() (*Cluster, error) {
 	}
 	return c, nil
 }
+
+// kubeClientConfig returns the kubeconfig from --kubeconfig or the default locations, at the given context or the current
+// context if it is empty.
+func kubeClientConfig(kubeContext string) clientcmd.ClientConfig {
+	loader := clientcmd.NewDefaultClientConfigLoadingRules()
+	loader.ExplicitPath = config.CommonOptions.KubeConfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+}
+
+// KubeContext returns the name of the kubeconfig context the cluster is connected with, which is empty for the current
+// context.
+func (c *Cluster) KubeContext() string {
+	return c.kubeContext
+}
+
+// WriteKubeconfig writes a kubeconfig that only holds the given context to path, so that commands run for the cluster of
+// the context, such as those of actions, connect to it instead of the current context.
+func WriteKubeconfig(path, kubeContext string) error {
+	rawConfig, err := kubeClientConfig(kubeContext).RawConfig()
+	if err != nil {
+		return err
+	}
+	if kubeContext != "" {
+		rawConfig.CurrentContext = kubeContext
+	}
+	if err := clientcmdapi.MinifyConfig(&rawConfig); err != nil {
+		return err
+	}
+	return clientcmd.WriteToFile(rawConfig, path)
+}
+
+// GetIdentity returns the name of the cluster of the kubeconfig context Zarf connects with and the labels of the
+// kube-system namespace, which together identify the cluster when selecting per-cluster configuration.
+func (c *Cluster) GetIdentity(ctx context.Context) (string, map[string]string, error) {
+	rawConfig, err := kubeClientConfig(c.kubeContext).RawConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	currentContext := rawConfig.CurrentContext
+	if c.kubeContext != "" {
+		currentContext = c.kubeContext
+	}
+	name := ""
+	if kubeContext, ok := rawConfig.Contexts[currentContext]; ok {
+		name = kubeContext.Cluster
+	}
+	namespace, err := c.Clientset.CoreV1().Namespaces().Get(ctx, metav1.NamespaceSystem, metav1.GetOptions{})
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to get the labels of the %s namespace: %w", metav1.NamespaceSystem, err)
+	}
+	return name, namespace.Labels, nil
+}
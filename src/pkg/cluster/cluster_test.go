@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/zarf-dev/zarf/src/config"
+)
+
+// Not parallel as the kubeconfig is selected through the global options
+func TestKubeClientConfig(t *testing.T) {
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev
+- name: prod
+  context:
+    cluster: prod
+`
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0o600))
+	config.CommonOptions.KubeConfig = path
+	t.Cleanup(func() {
+		config.CommonOptions.KubeConfig = ""
+	})
+
+	restConfig, err := kubeClientConfig("").ClientConfig()
+	require.NoError(t, err)
+	require.Equal(t, "https://dev.example.com", restConfig.Host)
+
+	restConfig, err = kubeClientConfig("prod").ClientConfig()
+	require.NoError(t, err)
+	require.Equal(t, "https://prod.example.com", restConfig.Host)
+
+	_, err = kubeClientConfig("missing").ClientConfig()
+	require.Error(t, err)
+
+	// Commands run for the cluster only see the context it is connected with
+	c := &Cluster{}
+	WithKubeContext("prod")(c)
+	require.Equal(t, "prod", c.KubeContext())
+	minified := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, WriteKubeconfig(minified, c.KubeContext()))
+	written, err := clientcmd.LoadFromFile(minified)
+	require.NoError(t, err)
+	require.Equal(t, "prod", written.CurrentContext)
+	require.Len(t, written.Contexts, 1)
+	require.Len(t, written.Clusters, 1)
+	require.Equal(t, "https://prod.example.com", written.Clusters["prod"].Server)
+}
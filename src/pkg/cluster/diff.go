@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// ObjectChange is how installing a helm release would change an object in the cluster.
+type ObjectChange string
+
+// All the different changes to an object in the cluster.
+const (
+	// ObjectChangeCreate means that the object does not exist in the cluster.
+	ObjectChangeCreate ObjectChange = "Create"
+	// ObjectChangeUpdate means that the object exists in the cluster and would be changed.
+	ObjectChangeUpdate ObjectChange = "Update"
+	// ObjectChangeDelete means that the object is in the current revision of the release but not in the new manifest.
+	ObjectChangeDelete ObjectChange = "Delete"
+)
+
+// ObjectDiff is the change to an object in the cluster, with the lines of its YAML that would be removed or added.
+type ObjectDiff struct {
+	// The object, as {KIND} {NAMESPACE}/{NAME}
+	Object string       `json:"object"`
+	Change ObjectChange `json:"change"`
+	Diff   string       `json:"diff,omitempty"`
+}
+
+// DiffRelease diffs the manifest of a helm release against the objects in the cluster, without changing them.
+//
+// Objects that exist are applied with a server-side dry-run so that defaults and mutating webhooks, such as the Zarf
+// agent, are taken into account. Objects that would not change are not returned.
+func (c *Cluster) DiffRelease(ctx context.Context, namespace, releaseName, manifest string) ([]ObjectDiff, error) {
+	dc, err := dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(c.Clientset.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+	return c.diffRelease(ctx, dc, mapper, namespace, releaseName, manifest)
+}
+
+func (c *Cluster) diffRelease(ctx context.Context, dc dynamic.Interface, mapper meta.RESTMapper, namespace, releaseName, manifest string) ([]ObjectDiff, error) {
+	objs, err := manifestObjects(mapper, namespace, manifest)
+	if err != nil {
+		return nil, err
+	}
+	diffs := []ObjectDiff{}
+	desired := map[string]bool{}
+	for _, obj := range objs {
+		desired[objectName(obj)] = true
+		diff, err := diffObject(ctx, dc, mapper, obj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to diff %s: %w", objectName(obj), err)
+		}
+		if diff != nil {
+			diffs = append(diffs, *diff)
+		}
+	}
+
+	// Objects of the deployed revision of the release that are not in the manifest are removed by the upgrade.
+	rel, err := c.latestRelease(types.InstalledChart{Namespace: namespace, ChartName: releaseName})
+	if err != nil {
+		return nil, err
+	}
+	if rel == nil || rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+		return diffs, nil
+	}
+	current, err := manifestObjects(mapper, namespace, rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range current {
+		if desired[objectName(obj)] {
+			continue
+		}
+		diffs = append(diffs, ObjectDiff{Object: objectName(obj), Change: ObjectChangeDelete, Diff: lineDiff(objectYAML(obj), "")})
+	}
+	return diffs, nil
+}
+
+// diffObject returns the change to an object in the cluster, or nil if applying it would not change it.
+func diffObject(ctx context.Context, dc dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured) (*ObjectDiff, error) {
+	created := &ObjectDiff{Object: objectName(obj), Change: ObjectChangeCreate, Diff: lineDiff("", objectYAML(obj))}
+
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// The API of the object, such as a custom resource definition, is installed with it.
+		return created, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ri dynamic.ResourceInterface = dc.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ri = dc.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	}
+
+	existing, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return created, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	applied, err := ri.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+		FieldManager: "zarf",
+		Force:        true,
+		DryRun:       []string{metav1.DryRunAll},
+	})
+	if err != nil {
+		return nil, err
+	}
+	before, after := objectYAML(existing), objectYAML(applied)
+	if before == after {
+		return nil, nil
+	}
+	return &ObjectDiff{Object: objectName(obj), Change: ObjectChangeUpdate, Diff: lineDiff(before, after)}, nil
+}
+
+// manifestObjects returns the objects of a helm release manifest, defaulting the namespace of namespaced objects to the
+// release namespace.
+func manifestObjects(mapper meta.RESTMapper, namespace, manifest string) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+	for _, content := range releaseutil.SplitManifests(manifest) {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(content), obj); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil && !meta.IsNoMatchError(err) {
+			return nil, err
+		}
+		switch {
+		case mapping != nil && mapping.Scope.Name() != meta.RESTScopeNameNamespace:
+			obj.SetNamespace("")
+		case obj.GetNamespace() == "":
+			obj.SetNamespace(namespace)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// objectYAML returns the YAML of an object without the fields that the cluster sets on every write.
+func objectYAML(obj *unstructured.Unstructured) string {
+	obj = obj.DeepCopy()
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+	b, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// lineDiff returns the lines that differ between two texts, prefixed with - if they were removed and + if they were
+// added.
+func lineDiff(before, after string) string {
+	dmp := diffmatchpatch.New()
+	beforeChars, afterChars, lines := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(beforeChars, afterChars, false), lines)
+
+	var sb strings.Builder
+	for _, diff := range diffs {
+		prefix := ""
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "- "
+		case diffmatchpatch.DiffInsert:
+			prefix = "+ "
+		default:
+			continue
+		}
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			sb.WriteString(prefix + strings.TrimSuffix(line, "\n") + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestDiffRelease(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(),
+	}
+	rel := &release.Release{
+		Name:      "podinfo",
+		Namespace: "podinfo",
+		Version:   1,
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Manifest: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo
+data:
+  color: blue
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: podinfo
+`,
+	}
+	secrets := driver.NewSecrets(c.Clientset.CoreV1().Secrets(rel.Namespace))
+	err := secrets.Create("sh.helm.release.v1.podinfo.v1", rel)
+	require.NoError(t, err)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Service"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "podinfo"},
+		Data:       map[string]string{"color": "blue"},
+	}
+	dc := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, configMap)
+	// The fake client does not support server-side apply or dry-runs, so return the applied object without storing it.
+	dc.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := &unstructured.Unstructured{}
+		return true, obj, json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), obj)
+	})
+
+	manifest := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo
+data:
+  color: green
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: podinfo
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: podinfo
+`
+	diffs, err := c.diffRelease(ctx, dc, mapper, "podinfo", "podinfo", manifest)
+	require.NoError(t, err)
+	expected := []ObjectDiff{
+		{
+			Object: "ConfigMap podinfo/podinfo",
+			Change: ObjectChangeUpdate,
+			Diff:   "-   color: blue\n+   color: green\n",
+		},
+		{
+			Object: "ClusterRole podinfo",
+			Change: ObjectChangeCreate,
+			Diff:   "+ apiVersion: rbac.authorization.k8s.io/v1\n+ kind: ClusterRole\n+ metadata:\n+   name: podinfo\n",
+		},
+		{
+			Object: "Widget podinfo/podinfo",
+			Change: ObjectChangeCreate,
+			Diff:   "+ apiVersion: example.com/v1\n+ kind: Widget\n+ metadata:\n+   name: podinfo\n+   namespace: podinfo\n",
+		},
+		{
+			Object: "Service podinfo/podinfo",
+			Change: ObjectChangeDelete,
+			Diff:   "- apiVersion: v1\n- kind: Service\n- metadata:\n-   name: podinfo\n-   namespace: podinfo\n",
+		},
+	}
+	require.Equal(t, expected, diffs)
+
+	// Objects that would not change are not returned.
+	diffs, err = c.diffRelease(ctx, dc, mapper, "podinfo", "podinfo", rel.Manifest)
+	require.NoError(t, err)
+	require.Equal(t, []ObjectDiff{{Object: "Service podinfo/podinfo", Change: ObjectChangeCreate, Diff: "+ apiVersion: v1\n+ kind: Service\n+ metadata:\n+   name: podinfo\n+   namespace: podinfo\n"}}, diffs)
+}
+
+func TestLineDiff(t *testing.T) {
+	t.Parallel()
+
+	before := "a: 1\nb: 2\nc: 3\n"
+	after := "a: 1\nb: 4\nc: 3\nd: 5\n"
+	require.Equal(t, "- b: 2\n+ b: 4\n+ d: 5\n", lineDiff(before, after))
+	require.Empty(t, lineDiff(before, before))
+}
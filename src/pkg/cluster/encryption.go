@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/pkg/kms"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+const (
+	// SecretKeyScheme is the URI scheme of keys kept in a cluster secret, as k8s://NAMESPACE/NAME.
+	SecretKeyScheme = "k8s://"
+	// stateKeyDataKey is the data key of the key in a state key secret.
+	stateKeyDataKey = "key"
+)
+
+// stateSecrets holds the credentials and private keys of the Zarf state that are encrypted.
+type stateSecrets struct {
+	GitPushPassword      string `json:"gitPushPassword,omitempty"`
+	GitPullPassword      string `json:"gitPullPassword,omitempty"`
+	RegistryPushPassword string `json:"registryPushPassword,omitempty"`
+	RegistryPullPassword string `json:"registryPullPassword,omitempty"`
+	RegistrySecret       string `json:"registrySecret,omitempty"`
+	ArtifactPushToken    string `json:"artifactPushToken,omitempty"`
+	AgentTLSKey          []byte `json:"agentTLSKey,omitempty"`
+}
+
+// secretKey is a key kept in a cluster secret, so that it can be protected and backed up separately from the state.
+type secretKey struct {
+	c         *Cluster
+	namespace string
+	name      string
+}
+
+// newSecretKey returns the key referenced by NAMESPACE/NAME.
+func (c *Cluster) newSecretKey(ref string) (*secretKey, error) {
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid cluster secret key reference %s, expected %sNAMESPACE/NAME", ref, SecretKeyScheme)
+	}
+	return &secretKey{c: c, namespace: namespace, name: name}, nil
+}
+
+// key returns the key in the secret, generating the secret if it does not exist and create is set.
+func (k *secretKey) key(ctx context.Context, create bool) ([]byte, error) {
+	secret, err := k.c.Clientset.CoreV1().Secrets(k.namespace).Get(ctx, k.name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) && create {
+		key, err := kms.GenerateDataKey()
+		if err != nil {
+			return nil, err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      k.name,
+				Namespace: k.namespace,
+				Labels: map[string]string{
+					ZarfManagedByLabel: "zarf",
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				stateKeyDataKey: key,
+			},
+		}
+		_, err = k.c.Clientset.CoreV1().Secrets(k.namespace).Create(ctx, secret, metav1.CreateOptions{})
+		if kerrors.IsAlreadyExists(err) {
+			return k.key(ctx, false)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the key secret %s/%s: %w", k.namespace, k.name, err)
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the key secret %s/%s: %w", k.namespace, k.name, err)
+	}
+	return secret.Data[stateKeyDataKey], nil
+}
+
+func (k *secretKey) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	key, err := k.key(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return kms.Seal(key, plaintext)
+}
+
+func (k *secretKey) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	key, err := k.key(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return kms.Open(key, ciphertext)
+}
+
+// keyEncrypter returns the key with the given URI.
+func (c *Cluster) keyEncrypter(ctx context.Context, uri string) (kms.KeyEncrypter, error) {
+	if strings.HasPrefix(uri, SecretKeyScheme) {
+		return c.newSecretKey(strings.TrimPrefix(uri, SecretKeyScheme))
+	}
+	return kms.New(ctx, uri)
+}
+
+// ValidateStateEncryptionKey returns an error if the URI does not reference a supported key.
+func ValidateStateEncryptionKey(uri string) error {
+	if strings.HasPrefix(uri, SecretKeyScheme) || kms.IsKeyURI(uri) {
+		return nil
+	}
+	return fmt.Errorf("unsupported state encryption key %s, expected a k8s://, awskms://, gcpkms://, azurekms:// or hashivault:// URI", uri)
+}
+
+// ValidateAgentStateEncryptionKey returns an error if the Zarf agent cannot read the key, as it decrypts the state on
+// admission requests with only its own credentials and its access to the secrets in the Zarf namespace.
+func ValidateAgentStateEncryptionKey(uri string) error {
+	namespace, _, _ := strings.Cut(strings.TrimPrefix(uri, SecretKeyScheme), "/")
+	if !strings.HasPrefix(uri, SecretKeyScheme) || namespace != ZarfNamespaceName {
+		return fmt.Errorf("state encryption key %s can not be read by the Zarf agent, use a key in the %s namespace such as %s%s/zarf-state-key", uri, ZarfNamespaceName, SecretKeyScheme, ZarfNamespaceName)
+	}
+	return nil
+}
+
+// encryptState returns a copy of the state with its credentials and private keys encrypted, if it has an encryption key.
+func (c *Cluster) encryptState(ctx context.Context, state *types.ZarfState) (*types.ZarfState, error) {
+	if state.Encryption == nil || state.Encryption.KeyURI == "" {
+		return state, nil
+	}
+	encrypter, err := c.keyEncrypter(ctx, state.Encryption.KeyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := *state
+	secrets := stateSecrets{
+		GitPushPassword:      state.GitServer.PushPassword,
+		GitPullPassword:      state.GitServer.PullPassword,
+		RegistryPushPassword: state.RegistryInfo.PushPassword,
+		RegistryPullPassword: state.RegistryInfo.PullPassword,
+		RegistrySecret:       state.RegistryInfo.Secret,
+		ArtifactPushToken:    state.ArtifactServer.PushToken,
+		AgentTLSKey:          state.AgentTLS.Key,
+	}
+	encrypted.GitServer.PushPassword = ""
+	encrypted.GitServer.PullPassword = ""
+	encrypted.RegistryInfo.PushPassword = ""
+	encrypted.RegistryInfo.PullPassword = ""
+	encrypted.RegistryInfo.Secret = ""
+	encrypted.ArtifactServer.PushToken = ""
+	encrypted.AgentTLS.Key = nil
+
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := kms.GenerateDataKey()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := kms.Seal(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	encryptedDataKey, err := encrypter.Encrypt(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encrypt the state data key with %s: %w", state.Encryption.KeyURI, err)
+	}
+	encrypted.Encryption = &types.StateEncryption{
+		KeyURI:     state.Encryption.KeyURI,
+		DataKey:    encryptedDataKey,
+		Ciphertext: ciphertext,
+	}
+	return &encrypted, nil
+}
+
+// decryptState decrypts the credentials and private keys of the state in place, if they are encrypted.
+func (c *Cluster) decryptState(ctx context.Context, state *types.ZarfState) error {
+	if state.Encryption == nil || len(state.Encryption.Ciphertext) == 0 {
+		return nil
+	}
+	encrypter, err := c.keyEncrypter(ctx, state.Encryption.KeyURI)
+	if err != nil {
+		return err
+	}
+	dataKey, err := encrypter.Decrypt(ctx, state.Encryption.DataKey)
+	if err != nil {
+		return fmt.Errorf("unable to decrypt the state data key with %s: %w", state.Encryption.KeyURI, err)
+	}
+	plaintext, err := kms.Open(dataKey, state.Encryption.Ciphertext)
+	if err != nil {
+		return err
+	}
+	secrets := stateSecrets{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return err
+	}
+
+	state.GitServer.PushPassword = secrets.GitPushPassword
+	state.GitServer.PullPassword = secrets.GitPullPassword
+	state.RegistryInfo.PushPassword = secrets.RegistryPushPassword
+	state.RegistryInfo.PullPassword = secrets.RegistryPullPassword
+	state.RegistryInfo.Secret = secrets.RegistrySecret
+	state.ArtifactServer.PushToken = secrets.ArtifactPushToken
+	state.AgentTLS.Key = secrets.AgentTLSKey
+	// Only the key is kept so that the state is encrypted again with a new data key when it is saved
+	state.Encryption = &types.StateEncryption{KeyURI: state.Encryption.KeyURI}
+	return nil
+}
+
+// unmarshalZarfState decodes and decrypts the state in the data of the zarf-state secret.
+func (c *Cluster) unmarshalZarfState(ctx context.Context, secret *corev1.Secret) (*types.ZarfState, error) {
+	state := &types.ZarfState{}
+	if err := json.Unmarshal(secret.Data[ZarfStateDataKey], state); err != nil {
+		return nil, err
+	}
+	if err := c.decryptState(ctx, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// marshalZarfState encrypts and encodes the state for the data of the zarf-state secret.
+func (c *Cluster) marshalZarfState(ctx context.Context, state *types.ZarfState) ([]byte, error) {
+	encrypted, err := c.encryptState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encrypted)
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestStateEncryption(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	state := &types.ZarfState{
+		AgentTLS:     types.GeneratedPKI{CA: []byte("ca"), Cert: []byte("cert"), Key: []byte("agent-key")},
+		GitServer:    types.GitServerInfo{PushUsername: "zarf-git-user", PushPassword: "git-push", PullPassword: "git-pull"},
+		RegistryInfo: types.RegistryInfo{PushUsername: "zarf-push", PushPassword: "registry-push", PullPassword: "registry-pull", Secret: "registry-secret"},
+		ArtifactServer: types.ArtifactServerInfo{
+			PushUsername: "zarf-git-user",
+			PushToken:    "artifact-token",
+		},
+		Encryption: &types.StateEncryption{KeyURI: "k8s://zarf/zarf-state-key"},
+	}
+	require.NoError(t, c.SaveZarfState(ctx, state))
+
+	// The saved state does not reveal the credentials or private keys
+	secret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	for _, value := range []string{"agent-key", "git-push", "git-pull", "registry-push", "registry-pull", "registry-secret", "artifact-token"} {
+		require.NotContains(t, string(secret.Data[ZarfStateDataKey]), value)
+	}
+	require.Contains(t, string(secret.Data[ZarfStateDataKey]), "zarf-push")
+	// The state passed in is not modified
+	require.Equal(t, "git-push", state.GitServer.PushPassword)
+	require.Equal(t, []byte("agent-key"), state.AgentTLS.Key)
+
+	keySecret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, "zarf-state-key", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, keySecret.Data["key"], 32)
+
+	loaded, err := c.LoadZarfState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+
+	// The state can not be read without its key
+	err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Delete(ctx, "zarf-state-key", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	_, err = c.LoadZarfState(ctx)
+	require.ErrorContains(t, err, "unable to get the key secret zarf/zarf-state-key")
+}
+
+func TestValidateStateEncryptionKey(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateStateEncryptionKey("k8s://zarf/zarf-state-key"))
+	require.NoError(t, ValidateStateEncryptionKey("awskms:///alias/zarf"))
+	require.EqualError(t, ValidateStateEncryptionKey("zarf-state-key"), "unsupported state encryption key zarf-state-key, expected a k8s://, awskms://, gcpkms://, azurekms:// or hashivault:// URI")
+
+	require.NoError(t, ValidateAgentStateEncryptionKey("k8s://zarf/zarf-state-key"))
+	require.EqualError(t, ValidateAgentStateEncryptionKey("k8s://default/zarf-state-key"), "state encryption key k8s://default/zarf-state-key can not be read by the Zarf agent, use a key in the zarf namespace such as k8s://zarf/zarf-state-key")
+	require.EqualError(t, ValidateAgentStateEncryptionKey("awskms:///alias/zarf"), "state encryption key awskms:///alias/zarf can not be read by the Zarf agent, use a key in the zarf namespace such as k8s://zarf/zarf-state-key")
+
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	_, err := c.newSecretKey("zarf")
+	require.EqualError(t, err, "invalid cluster secret key reference zarf, expected k8s://NAMESPACE/NAME")
+}
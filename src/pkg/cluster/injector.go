@@ -81,6 +81,7 @@ func (c *Cluster) StartInjection(ctx context.Context, tmpDir, imagesDir string,
 		return err
 	}
 
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: corev1.SchemeGroupVersion.String(),
@@ -92,6 +93,8 @@ func (c *Cluster) StartInjection(ctx context.Context, tmpDir, imagesDir string,
 		},
 		Spec: corev1.ServiceSpec{
 			Type: corev1.ServiceTypeNodePort,
+			// The NodePort is served on the IPv4 loopback address of the nodes of a dual-stack cluster that prefers IPv6
+			IPFamilyPolicy: &preferDualStack,
 			Ports: []corev1.ServicePort{
 				{
 					Port: int32(5000),
@@ -260,10 +263,35 @@ func (c *Cluster) createPayloadConfigMaps(ctx context.Context, spinner *message.
 	return cmNames, shasum, nil
 }
 
+// InjectorStorage returns the ephemeral storage in bytes the injector needs for the seed images, which are held on its
+// node both as the payload archive and as the extracted image layout.
+func InjectorStorage(imagesDir string, injectorSeedSrcs []string) (int64, error) {
+	var size int64
+	for _, src := range injectorSeedSrcs {
+		ref, err := transform.ParseImageRef(src)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create ref for image %s: %w", src, err)
+		}
+		img, err := utils.LoadOCIImage(imagesDir, ref)
+		if err != nil {
+			return 0, err
+		}
+		manifest, err := img.Manifest()
+		if err != nil {
+			return 0, err
+		}
+		size += manifest.Config.Size
+		for _, layer := range manifest.Layers {
+			size += layer.Size
+		}
+	}
+	return 2 * size, nil
+}
+
 // getImagesAndNodesForInjection checks for images on schedulable nodes within a cluster.
 func (c *Cluster) getInjectorImageAndNode(ctx context.Context, resReq corev1.ResourceRequirements) (string, string, error) {
 	// Regex for Zarf seed image
-	zarfImageRegex, err := regexp.Compile(`(?m)^127\.0\.0\.1:`)
+	zarfImageRegex, err := regexp.Compile(`(?m)^(127\.0\.0\.1|\[::1\]):`)
 	if err != nil {
 		return "", "", err
 	}
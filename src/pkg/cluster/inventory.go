@@ -0,0 +1,346 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// InventoryStatus is how a deployed package compares to the objects in the cluster.
+type InventoryStatus string
+
+// All the different status options for the inventory of a deployed package.
+const (
+	// InventoryStatusSynced means that everything the package deployed is still in the cluster.
+	InventoryStatusSynced InventoryStatus = "Synced"
+	// InventoryStatusDrifted means that objects of the package were removed or its images are not running.
+	InventoryStatusDrifted InventoryStatus = "Drifted"
+	// InventoryStatusPartiallyRemoved means that helm releases of the package were removed.
+	InventoryStatusPartiallyRemoved InventoryStatus = "PartiallyRemoved"
+	// InventoryStatusUnknown means that the package could not be compared to the cluster, as the user is not allowed to
+	// read the helm releases, objects or pods of the package.
+	InventoryStatusUnknown InventoryStatus = "Unknown"
+)
+
+// PackageInventory compares the components recorded for a deployed package to the objects in the cluster.
+type PackageInventory struct {
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	Status     InventoryStatus      `json:"status"`
+	Components []ComponentInventory `json:"components"`
+	// Why the package could not be compared to the cluster when the status is Unknown
+	Error string `json:"error,omitempty"`
+}
+
+// ComponentInventory is the drift of a deployed component from the objects in the cluster.
+type ComponentInventory struct {
+	Name string `json:"name"`
+	// Helm releases of the charts and manifests of the component that were removed or are not deployed
+	MissingReleases []string `json:"missingReleases,omitempty"`
+	// Objects of the helm releases of the component that were removed, as {KIND} {NAMESPACE}/{NAME}
+	MissingObjects []string `json:"missingObjects,omitempty"`
+	// Images of the deployments, stateful sets and daemon sets of the component that no pod in the cluster runs
+	ImagesNotRunning []string `json:"imagesNotRunning,omitempty"`
+}
+
+// Drifted returns true if anything the component deployed is missing from the cluster.
+func (ci ComponentInventory) Drifted() bool {
+	return len(ci.MissingReleases) > 0 || len(ci.MissingObjects) > 0 || len(ci.ImagesNotRunning) > 0
+}
+
+// PackageInventories compares the deployed components of the packages to the helm releases, objects and running images
+// in the cluster. Packages that the user is not allowed to compare to the cluster get the Unknown status.
+func (c *Cluster) PackageInventories(ctx context.Context, deployedPackages []types.DeployedPackage) ([]PackageInventory, error) {
+	dc, err := dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return nil, err
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(c.Clientset.Discovery())
+	if isPermissionError(err) {
+		return unknownInventories(deployedPackages, err), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+	var pods []corev1.Pod
+	podList, podsErr := c.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if podsErr != nil && !isPermissionError(podsErr) {
+		return nil, podsErr
+	}
+	if podsErr == nil {
+		pods = podList.Items
+	}
+
+	inventories := []PackageInventory{}
+	for _, deployedPackage := range deployedPackages {
+		inventory, err := c.packageInventory(ctx, dc, mapper, pods, podsErr, deployedPackage)
+		if isPermissionError(err) {
+			inventories = append(inventories, unknownInventories([]types.DeployedPackage{deployedPackage}, err)...)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to check package %s for drift: %w", deployedPackage.Name, err)
+		}
+		inventories = append(inventories, inventory)
+	}
+	return inventories, nil
+}
+
+// isPermissionError returns true if the user is not allowed to read an object of the cluster.
+func isPermissionError(err error) bool {
+	return kerrors.IsForbidden(err) || kerrors.IsUnauthorized(err)
+}
+
+func unknownInventories(deployedPackages []types.DeployedPackage, err error) []PackageInventory {
+	inventories := []PackageInventory{}
+	for _, deployedPackage := range deployedPackages {
+		components := []ComponentInventory{}
+		for _, deployedComponent := range deployedPackage.DeployedComponents {
+			components = append(components, ComponentInventory{Name: deployedComponent.Name})
+		}
+		inventories = append(inventories, PackageInventory{
+			Name:       deployedPackage.Name,
+			Version:    deployedPackage.Data.Metadata.Version,
+			Status:     InventoryStatusUnknown,
+			Components: components,
+			Error:      err.Error(),
+		})
+	}
+	return inventories
+}
+
+// packageInventory compares a deployed package to the cluster. The pods are only read when the package has workloads,
+// so podsErr is returned if the pods could not be listed and they are needed.
+func (c *Cluster) packageInventory(ctx context.Context, dc dynamic.Interface, mapper meta.RESTMapper, pods []corev1.Pod, podsErr error, deployedPackage types.DeployedPackage) (PackageInventory, error) {
+	inventory := PackageInventory{
+		Name:       deployedPackage.Name,
+		Version:    deployedPackage.Data.Metadata.Version,
+		Status:     InventoryStatusSynced,
+		Components: []ComponentInventory{},
+	}
+	for _, deployedComponent := range deployedPackage.DeployedComponents {
+		ci := ComponentInventory{Name: deployedComponent.Name}
+		releaseImages := []string{}
+		for _, chart := range deployedComponent.InstalledCharts {
+			rel, err := c.latestRelease(chart)
+			if err != nil {
+				return PackageInventory{}, err
+			}
+			if rel == nil || rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+				ci.MissingReleases = append(ci.MissingReleases, chart.ChartName)
+				continue
+			}
+			missing, err := missingObjects(ctx, dc, mapper, chart.Namespace, rel.Manifest)
+			if err != nil {
+				return PackageInventory{}, err
+			}
+			ci.MissingObjects = append(ci.MissingObjects, missing...)
+			images, err := workloadImages(rel.Manifest)
+			if err != nil {
+				return PackageInventory{}, err
+			}
+			releaseImages = append(releaseImages, images...)
+		}
+		component := helpers.Find(deployedPackage.Data.Components, func(c v1alpha1.ZarfComponent) bool {
+			return c.Name == deployedComponent.Name
+		})
+		if len(releaseImages) > 0 && podsErr != nil {
+			return PackageInventory{}, podsErr
+		}
+		notRunning, err := imagesNotRunning(component.Images, releaseImages, pods)
+		if err != nil {
+			return PackageInventory{}, err
+		}
+		ci.ImagesNotRunning = notRunning
+
+		switch {
+		case len(ci.MissingReleases) > 0:
+			inventory.Status = InventoryStatusPartiallyRemoved
+		case ci.Drifted() && inventory.Status == InventoryStatusSynced:
+			inventory.Status = InventoryStatusDrifted
+		}
+		inventory.Components = append(inventory.Components, ci)
+	}
+	return inventory, nil
+}
+
+// latestRelease returns the latest revision of the helm release of an installed chart, or nil if it was removed.
+func (c *Cluster) latestRelease(chart types.InstalledChart) (*release.Release, error) {
+	secrets := driver.NewSecrets(c.Clientset.CoreV1().Secrets(chart.Namespace))
+	releases, err := secrets.Query(map[string]string{"name": chart.ChartName, "owner": "helm"})
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	releaseutil.Reverse(releases, releaseutil.SortByRevision)
+	return releases[0], nil
+}
+
+// ReleaseRevision returns the latest revision of a helm release, or 0 if the release is not installed.
+func (c *Cluster) ReleaseRevision(namespace, name string) (int, error) {
+	rel, err := c.latestRelease(types.InstalledChart{Namespace: namespace, ChartName: name})
+	if err != nil {
+		return 0, err
+	}
+	if rel == nil {
+		return 0, nil
+	}
+	return rel.Version, nil
+}
+
+// missingObjects returns the objects of a helm release manifest that no longer exist in the cluster.
+func missingObjects(ctx context.Context, dc dynamic.Interface, mapper meta.RESTMapper, namespace, manifest string) ([]string, error) {
+	missing := []string{}
+	for _, content := range releaseutil.SplitManifests(manifest) {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(content), obj); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			// The API of the object, such as a custom resource definition, was removed with the object.
+			missing = append(missing, objectName(obj))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var ri dynamic.ResourceInterface = dc.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(namespace)
+			}
+			ri = dc.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+		}
+		_, err = ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			missing = append(missing, objectName(obj))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(missing)
+	return missing, nil
+}
+
+func objectName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// workloadImages returns the images of the containers of the deployments, stateful sets and daemon sets of a helm
+// release manifest. Only these controllers keep their pods running, the pods of jobs and cron jobs and the init
+// containers of pods complete, and the images of a package that no workload uses, such as the unused registry of the
+// init package, are never expected to run.
+func workloadImages(manifest string) ([]string, error) {
+	images := []string{}
+	for _, content := range releaseutil.SplitManifests(manifest) {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(content), obj); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		if obj.GroupVersionKind().Group != "apps" || !slices.Contains([]string{"Deployment", "StatefulSet", "DaemonSet"}, obj.GetKind()) {
+			continue
+		}
+		containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		if err != nil {
+			return nil, err
+		}
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if image, ok := containerMap["image"].(string); ok && image != "" {
+				images = append(images, image)
+			}
+		}
+	}
+	return images, nil
+}
+
+// imagesNotRunning returns the images of the workloads that no container of the pods runs.
+//
+// The registry of the images is ignored, as the agent points the pods at the Zarf registry and adds a checksum to the
+// tags of the images.
+func imagesNotRunning(images, workloadImages []string, pods []corev1.Pod) ([]string, error) {
+	expected := map[string]bool{}
+	for _, image := range workloadImages {
+		ref, err := transform.ParseImageRef(image)
+		if err != nil {
+			continue
+		}
+		expected[ref.Path+ref.TagOrDigest] = true
+	}
+
+	running := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			ref, err := transform.ParseImageRef(container.Image)
+			if err != nil {
+				continue
+			}
+			running[ref.Path+ref.TagOrDigest] = true
+		}
+	}
+
+	notRunning := []string{}
+	for _, image := range images {
+		ref, err := transform.ParseImageRef(image)
+		if err != nil {
+			return nil, err
+		}
+		if !expected[ref.Path+ref.TagOrDigest] {
+			continue
+		}
+		withChecksum, err := transform.ImageTransformHost("", image)
+		if err != nil {
+			return nil, err
+		}
+		withoutChecksum, err := transform.ImageTransformHostWithoutChecksum("", image)
+		if err != nil {
+			return nil, err
+		}
+		if running[strings.TrimPrefix(withChecksum, "/")] || running[strings.TrimPrefix(withoutChecksum, "/")] {
+			continue
+		}
+		notRunning = append(notRunning, image)
+	}
+	return notRunning, nil
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestPackageInventory(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(),
+	}
+	releases := []*release.Release{
+		{
+			Name:      "podinfo",
+			Namespace: "podinfo",
+			Version:   1,
+			Info:      &release.Info{Status: release.StatusSuperseded},
+		},
+		{
+			Name:      "podinfo",
+			Namespace: "podinfo",
+			Version:   2,
+			Info:      &release.Info{Status: release.StatusDeployed},
+			Manifest: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: podinfo
+spec:
+  template:
+    spec:
+      containers:
+      - name: podinfo
+        image: ghcr.io/stefanprodan/podinfo:6.4.0
+      - name: podinfo-next
+        image: ghcr.io/stefanprodan/podinfo:6.5.0
+`,
+		},
+		{
+			Name:      "failed",
+			Namespace: "podinfo",
+			Version:   1,
+			Info:      &release.Info{Status: release.StatusFailed},
+		},
+	}
+	for _, rel := range releases {
+		secrets := driver.NewSecrets(c.Clientset.CoreV1().Secrets(rel.Namespace))
+		err := secrets.Create(fmt.Sprintf("sh.helm.release.v1.%s.v%d", rel.Name, rel.Version), rel)
+		require.NoError(t, err)
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "podinfo"},
+	}
+	dc := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, configMap)
+
+	pods := []corev1.Pod{
+		{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Image: "127.0.0.1:31999/stefanprodan/podinfo:6.4.0-zarf-2985051089"}},
+			},
+		},
+	}
+
+	deployedPackage := types.DeployedPackage{
+		Name: "podinfo",
+		Data: v1alpha1.ZarfPackage{
+			Metadata: v1alpha1.ZarfMetadata{Version: "1.0.0"},
+			Components: []v1alpha1.ZarfComponent{
+				{
+					Name:   "podinfo",
+					Images: []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "ghcr.io/stefanprodan/podinfo:6.5.0"},
+				},
+				{
+					Name: "failed",
+				},
+			},
+		},
+		DeployedComponents: []types.DeployedComponent{
+			{
+				Name:            "podinfo",
+				InstalledCharts: []types.InstalledChart{{Namespace: "podinfo", ChartName: "podinfo"}},
+			},
+		},
+	}
+	inventory, err := c.packageInventory(ctx, dc, mapper, pods, nil, deployedPackage)
+	require.NoError(t, err)
+	expected := PackageInventory{
+		Name:    "podinfo",
+		Version: "1.0.0",
+		Status:  InventoryStatusDrifted,
+		Components: []ComponentInventory{
+			{
+				Name:             "podinfo",
+				MissingObjects:   []string{"Deployment podinfo/podinfo"},
+				ImagesNotRunning: []string{"ghcr.io/stefanprodan/podinfo:6.5.0"},
+			},
+		},
+	}
+	require.Equal(t, expected, inventory)
+
+	deployedPackage.DeployedComponents = append(deployedPackage.DeployedComponents, types.DeployedComponent{
+		Name: "failed",
+		InstalledCharts: []types.InstalledChart{
+			{Namespace: "podinfo", ChartName: "failed"},
+			{Namespace: "podinfo", ChartName: "removed"},
+		},
+	})
+	inventory, err = c.packageInventory(ctx, dc, mapper, pods, nil, deployedPackage)
+	require.NoError(t, err)
+	require.Equal(t, InventoryStatusPartiallyRemoved, inventory.Status)
+	require.Equal(t, []string{"failed", "removed"}, inventory.Components[1].MissingReleases)
+
+	// The pods are needed to check the images of the workloads of the package
+	podsErr := kerrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("not allowed"))
+	_, err = c.packageInventory(ctx, dc, mapper, nil, podsErr, deployedPackage)
+	require.True(t, isPermissionError(err))
+}
+
+func TestUnknownInventories(t *testing.T) {
+	t.Parallel()
+
+	deployedPackages := []types.DeployedPackage{
+		{
+			Name:               "podinfo",
+			Data:               v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Version: "1.0.0"}},
+			DeployedComponents: []types.DeployedComponent{{Name: "podinfo"}},
+		},
+	}
+	err := kerrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", errors.New("not allowed"))
+	require.True(t, isPermissionError(fmt.Errorf("failed to query with labels: %w", err)))
+	expected := []PackageInventory{
+		{
+			Name:       "podinfo",
+			Version:    "1.0.0",
+			Status:     InventoryStatusUnknown,
+			Components: []ComponentInventory{{Name: "podinfo"}},
+			Error:      err.Error(),
+		},
+	}
+	require.Equal(t, expected, unknownInventories(deployedPackages, err))
+}
+
+func TestImagesNotRunning(t *testing.T) {
+	t.Parallel()
+
+	pods := []corev1.Pod{
+		{
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Image: "busybox:1.36"}},
+				Containers:     []corev1.Container{{Image: "127.0.0.1:31999/library/nginx:1.25-zarf-3793515731"}},
+			},
+		},
+		{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Image: "registry.example.com/library/redis:7"}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+	images := []string{"nginx:1.25", "docker.io/library/busybox:1.36", "redis:7", "alpine:3.20", "ghcr.io/project-zot/zot:v2.1.0"}
+	// Only the images of long-running workloads are compared, the init containers and the pods of jobs complete, and
+	// the images that no workload uses, such as the unused registry of the init package, never run
+	workloadImages := []string{"nginx:1.25", "busybox:1.36", "127.0.0.1:31999/library/redis:7", "alpine:3.20"}
+	notRunning, err := imagesNotRunning(images, workloadImages, pods)
+	require.NoError(t, err)
+	require.Equal(t, []string{"docker.io/library/busybox:1.36", "redis:7", "alpine:3.20"}, notRunning)
+}
@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// isIPv6Only returns true if the nodes only have IPv6 internal addresses, in which case the NodePorts of the cluster are
+// not reachable on the IPv4 loopback address of the nodes. Dual-stack clusters still serve them on the IPv4 loopback
+// address.
+func isIPv6Only(nodes []corev1.Node) bool {
+	hasIPv6 := false
+	for _, node := range nodes {
+		for _, address := range node.Status.Addresses {
+			if address.Type != corev1.NodeInternalIP {
+				continue
+			}
+			ip := net.ParseIP(address.Address)
+			if ip == nil {
+				continue
+			}
+			if ip.To4() != nil {
+				return false
+			}
+			hasIPv6 = true
+		}
+	}
+	return hasIPv6
+}
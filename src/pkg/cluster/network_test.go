@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIsIPv6Only(t *testing.T) {
+	t.Parallel()
+
+	node := func(addresses ...corev1.NodeAddress) corev1.Node {
+		return corev1.Node{Status: corev1.NodeStatus{Addresses: addresses}}
+	}
+	internalIP := func(ip string) corev1.NodeAddress {
+		return corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: ip}
+	}
+
+	tests := []struct {
+		name     string
+		nodes    []corev1.Node
+		expected bool
+	}{
+		{
+			name:  "no addresses",
+			nodes: []corev1.Node{node()},
+		},
+		{
+			name:  "IPv4",
+			nodes: []corev1.Node{node(internalIP("10.0.0.1"), corev1.NodeAddress{Type: corev1.NodeHostName, Address: "node"})},
+		},
+		{
+			name:  "dual-stack",
+			nodes: []corev1.Node{node(internalIP("fd00::1"), internalIP("10.0.0.1"))},
+		},
+		{
+			name:     "IPv6",
+			nodes:    []corev1.Node{node(internalIP("fd00::1")), node(internalIP("fd00::2"), corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.1"})},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, isIPv6Only(tt.nodes))
+		})
+	}
+}
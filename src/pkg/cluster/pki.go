@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/pki"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+const (
+	// CABundleKey is the key in the zarf-ca-bundle config map that holds the PEM encoded private CA certificates.
+	CABundleKey = "ca.crt"
+	// AgentTLSSecretName is the name of the secret holding the certificate served by the agent.
+	AgentTLSSecretName = "agent-hook-tls"
+	// agentWebhookConfigName is the name of the mutating and validating webhook configurations of the agent.
+	agentWebhookConfigName = "zarf"
+)
+
+// ErrPrivateCARequired is returned when a certificate must be reissued for a cluster that uses a private CA that was
+// not provided.
+var ErrPrivateCARequired = errors.New("the cluster uses a private CA, provide it with --ca-cert and --ca-key to reissue its certificates")
+
+// LoadCertificateAuthority reads the private CA from the init options, returning nil if one was not provided.
+func LoadCertificateAuthority(initOptions types.ZarfInitOptions) (*pki.CertificateAuthority, error) {
+	if initOptions.CACertPath == "" && initOptions.CAKeyPath == "" {
+		return nil, nil
+	}
+	if initOptions.CACertPath == "" || initOptions.CAKeyPath == "" {
+		return nil, errors.New("both --ca-cert and --ca-key must be provided to use a private CA")
+	}
+	certPEM, err := os.ReadFile(initOptions.CACertPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the CA certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(initOptions.CAKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the CA private key: %w", err)
+	}
+	return pki.LoadCA(certPEM, keyPEM)
+}
+
+// issueCertificates issues the certificate of the agent into the state if it is one of the given services.
+//
+// Without a private CA the agent is given a certificate from a new self-signed CA. With a private CA the agent is
+// issued a certificate from it and the CA is recorded so that its trust can be distributed to namespaces. The registry
+// and git server serve plain HTTP inside the cluster, so they are not issued certificates.
+func issueCertificates(state *types.ZarfState, ca *pki.CertificateAuthority, subjectAltNames []string, services []string) error {
+	if ca == nil {
+		if !slices.Contains(services, message.AgentKey) {
+			return nil
+		}
+		if len(state.CABundle) > 0 {
+			return ErrPrivateCARequired
+		}
+		agentTLS, err := pki.GeneratePKI(config.ZarfAgentHost)
+		if err != nil {
+			return err
+		}
+		state.AgentTLS = agentTLS
+		return nil
+	}
+
+	// A new CA invalidates the certificate issued by the previous one.
+	caBundleChanged := string(state.CABundle) != string(ca.Bundle())
+	state.CABundle = ca.Bundle()
+	if caBundleChanged || slices.Contains(services, message.AgentKey) {
+		agentTLS, err := ca.Issue(config.ZarfAgentHost, subjectAltNames...)
+		if err != nil {
+			return err
+		}
+		state.AgentTLS = agentTLS
+	}
+	return nil
+}
+
+// GenerateCABundle generates a config map containing the private CA certificates for workloads to trust.
+func (c *Cluster) GenerateCABundle(namespace string, caBundle []byte) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.ZarfCABundleName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				ZarfManagedByLabel: "zarf",
+			},
+		},
+		Data: map[string]string{
+			CABundleKey: string(caBundle),
+		},
+	}
+}
+
+// ApplyCABundle creates or updates the config map containing the private CA certificates in the namespace, doing
+// nothing if the cluster does not use a private CA.
+func (c *Cluster) ApplyCABundle(ctx context.Context, namespace string, caBundle []byte) error {
+	if len(caBundle) == 0 {
+		return nil
+	}
+	cm := c.GenerateCABundle(namespace, caBundle)
+	_, err := c.Clientset.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	if err == nil {
+		return nil
+	}
+	_, err = c.Clientset.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateZarfManagedCABundles updates the private CA certificates in every namespace that Zarf manages.
+func (c *Cluster) UpdateZarfManagedCABundles(ctx context.Context, state *types.ZarfState) error {
+	if len(state.CABundle) == 0 {
+		return nil
+	}
+
+	spinner := message.NewProgressSpinner("Updating existing Zarf-managed CA bundles")
+	defer spinner.Stop()
+
+	namespaceList, err := c.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=zarf", ZarfManagedByLabel)})
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaceList.Items {
+		if err := c.ApplyCABundle(ctx, namespace.Name, state.CABundle); err != nil {
+			message.WarnErrf(err, "Problem updating the CA bundle for the %s namespace", namespace.Name)
+			continue
+		}
+		spinner.Updatef("Updated the CA bundle for the %s namespace", namespace.Name)
+	}
+	spinner.Success()
+	return nil
+}
+
+// RenewAgentTLS reissues the agent certificate in the state once it expires within renewBefore and makes sure that the
+// agent secret and webhook configurations are in line with the state, returning the current agent certificate.
+//
+// The state is updated with the version it was read at, so that only one agent replica rotates the certificate and the
+// others pick it up. The webhooks keep trusting the previous CA alongside the new one, so that replicas still serving
+// the previous certificate keep working until they do. A certificate issued from a private CA can not be reissued as
+// the CA key is not in the cluster.
+func (c *Cluster) RenewAgentTLS(ctx context.Context, renewBefore time.Duration) (types.GeneratedPKI, error) {
+	secret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, ZarfStateSecretName, metav1.GetOptions{})
+	if err != nil {
+		return types.GeneratedPKI{}, err
+	}
+	state, err := c.unmarshalZarfState(ctx, secret)
+	if err != nil {
+		return types.GeneratedPKI{}, err
+	}
+
+	notAfter, err := certificateExpiry(state.AgentTLS.Cert)
+	if err != nil {
+		return types.GeneratedPKI{}, fmt.Errorf("unable to parse the agent certificate: %w", err)
+	}
+	caBundle := state.AgentTLS.CA
+	if time.Until(notAfter) < renewBefore {
+		if len(state.CABundle) > 0 {
+			message.Warnf("The agent certificate expires at %s and was issued from a private CA, reissue it with zarf tools update-creds agent --ca-cert and --ca-key", notAfter.Format(time.RFC3339))
+		} else {
+			agentTLS, err := pki.GeneratePKI(config.ZarfAgentHost)
+			if err != nil {
+				return types.GeneratedPKI{}, err
+			}
+			caBundle = append(append([]byte{}, agentTLS.CA...), state.AgentTLS.CA...)
+			state.AgentTLS = agentTLS
+			data, err := c.marshalZarfState(ctx, state)
+			if err != nil {
+				return types.GeneratedPKI{}, err
+			}
+			secret.Data[ZarfStateDataKey] = data
+			// Fails with a conflict if another replica rotated the certificate since the state was read
+			if _, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+				return types.GeneratedPKI{}, fmt.Errorf("unable to update the zarf state secret: %w", err)
+			}
+			message.Infof("Rotated the agent certificate that expired at %s", notAfter.Format(time.RFC3339))
+		}
+	}
+
+	if err := c.syncAgentTLS(ctx, state.AgentTLS, caBundle); err != nil {
+		return types.GeneratedPKI{}, err
+	}
+	return state.AgentTLS, nil
+}
+
+// syncAgentTLS updates the agent secret to the given certificate and sets the CA bundle of the agent webhooks that do
+// not trust its CA.
+func (c *Cluster) syncAgentTLS(ctx context.Context, agentTLS types.GeneratedPKI, caBundle []byte) error {
+	tlsSecret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, AgentTLSSecretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(tlsSecret.Data[corev1.TLSCertKey], agentTLS.Cert) || !bytes.Equal(tlsSecret.Data[corev1.TLSPrivateKeyKey], agentTLS.Key) {
+		tlsSecret.Data = map[string][]byte{
+			corev1.TLSCertKey:       agentTLS.Cert,
+			corev1.TLSPrivateKeyKey: agentTLS.Key,
+		}
+		if _, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Update(ctx, tlsSecret, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	mutating, err := c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, agentWebhookConfigName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		changed := false
+		for i := range mutating.Webhooks {
+			changed = setCABundle(&mutating.Webhooks[i].ClientConfig, agentTLS.CA, caBundle) || changed
+		}
+		if changed {
+			if _, err := c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(ctx, mutating, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	validating, err := c.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, agentWebhookConfigName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		changed := false
+		for i := range validating.Webhooks {
+			changed = setCABundle(&validating.Webhooks[i].ClientConfig, agentTLS.CA, caBundle) || changed
+		}
+		if changed {
+			if _, err := c.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(ctx, validating, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setCABundle sets the CA bundle of a webhook served by the agent if it does not trust the CA, returning whether it
+// changed.
+func setCABundle(clientConfig *admissionregistrationv1.WebhookClientConfig, ca, caBundle []byte) bool {
+	if clientConfig.Service == nil || bytes.Contains(clientConfig.CABundle, ca) {
+		return false
+	}
+	clientConfig.CABundle = caBundle
+	return true
+}
+
+// certificateExpiry returns when the first certificate of the PEM encoded chain expires.
+func certificateExpiry(certPEM []byte) (time.Time, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return time.Time{}, errors.New("no PEM encoded certificate was found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/pki"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestRenewAgentTLS(t *testing.T) {
+	t.Parallel()
+
+	agentTLS, err := pki.GeneratePKI(config.ZarfAgentHost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		renewBefore time.Duration
+		caBundle    []byte
+		rotated     bool
+	}{
+		{
+			name:        "certificate that is not expiring",
+			renewBefore: 24 * time.Hour,
+		},
+		{
+			name:        "certificate that is expiring",
+			renewBefore: 400 * 24 * time.Hour,
+			rotated:     true,
+		},
+		{
+			name:        "expiring certificate from a private CA",
+			renewBefore: 400 * 24 * time.Hour,
+			caBundle:    agentTLS.CA,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			state := &types.ZarfState{AgentTLS: agentTLS, CABundle: tt.caBundle}
+			data, err := json.Marshal(state)
+			require.NoError(t, err)
+			service := &admissionregistrationv1.ServiceReference{Namespace: ZarfNamespaceName, Name: "agent-hook"}
+			c := &Cluster{Clientset: fake.NewSimpleClientset(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: ZarfStateSecretName, Namespace: ZarfNamespaceName},
+					Data:       map[string][]byte{ZarfStateDataKey: data},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: AgentTLSSecretName, Namespace: ZarfNamespaceName},
+					Data:       map[string][]byte{corev1.TLSCertKey: []byte("stale"), corev1.TLSPrivateKeyKey: []byte("stale")},
+				},
+				&admissionregistrationv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: agentWebhookConfigName},
+					Webhooks: []admissionregistrationv1.MutatingWebhook{
+						{Name: "agent-pod.zarf.dev", ClientConfig: admissionregistrationv1.WebhookClientConfig{Service: service, CABundle: agentTLS.CA}},
+						{Name: "external.example.com", ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: []byte("external")}},
+					},
+				},
+				&admissionregistrationv1.ValidatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: agentWebhookConfigName},
+					Webhooks: []admissionregistrationv1.ValidatingWebhook{
+						{Name: "agent-pod-validation.zarf.dev", ClientConfig: admissionregistrationv1.WebhookClientConfig{Service: service, CABundle: []byte("stale")}},
+					},
+				},
+			)}
+
+			current, err := c.RenewAgentTLS(ctx, tt.renewBefore)
+			require.NoError(t, err)
+			if tt.rotated {
+				require.NotEqual(t, agentTLS, current)
+			} else {
+				require.Equal(t, agentTLS, current)
+			}
+
+			saved, err := c.LoadZarfState(ctx)
+			require.NoError(t, err)
+			require.Equal(t, current, saved.AgentTLS)
+
+			tlsSecret, err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Get(ctx, AgentTLSSecretName, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, current.Cert, tlsSecret.Data[corev1.TLSCertKey])
+			require.Equal(t, current.Key, tlsSecret.Data[corev1.TLSPrivateKeyKey])
+
+			mutating, err := c.Clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, agentWebhookConfigName, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.True(t, bytes.Contains(mutating.Webhooks[0].ClientConfig.CABundle, current.CA))
+			// the previous CA is trusted until every replica serves the new certificate
+			require.True(t, bytes.Contains(mutating.Webhooks[0].ClientConfig.CABundle, agentTLS.CA))
+			require.Equal(t, []byte("external"), mutating.Webhooks[1].ClientConfig.CABundle)
+			validating, err := c.Clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, agentWebhookConfigName, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.True(t, bytes.Contains(validating.Webhooks[0].ClientConfig.CABundle, current.CA))
+
+			// the certificate is only rotated once
+			again, err := c.RenewAgentTLS(ctx, 24*time.Hour)
+			require.NoError(t, err)
+			require.Equal(t, current, again)
+		})
+	}
+}
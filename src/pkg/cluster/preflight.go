@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MinimumKubeVersion is the oldest Kubernetes version Zarf supports deploying to.
+const MinimumKubeVersion = "1.23.0"
+
+// Names of the preflight checks.
+const (
+	PreflightKubeVersion     = "Kubernetes version"
+	PreflightStorageClass    = "Storage class"
+	PreflightArchitecture    = "Node architecture"
+	PreflightInjectorStorage = "Injector storage"
+	PreflightPodSecurity     = "Pod security"
+)
+
+const (
+	podSecurityEnforceLabel           = "pod-security.kubernetes.io/enforce"
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// PreflightStatus is the outcome of a preflight check.
+type PreflightStatus string
+
+// Outcomes of a preflight check, a failed check halts the deployment while a warning is only reported.
+const (
+	PreflightPass PreflightStatus = "pass"
+	PreflightWarn PreflightStatus = "warn"
+	PreflightFail PreflightStatus = "fail"
+)
+
+// PreflightResult is the result of a preflight check.
+type PreflightResult struct {
+	Check   string
+	Status  PreflightStatus
+	Message string
+}
+
+// PreflightOptions describe what a package needs from the cluster it is deployed to.
+type PreflightOptions struct {
+	// Storage class the package uses, the default storage class is checked for if it is empty
+	StorageClass string
+	// Whether the package creates persistent volume claims, the storage class is only checked if it does
+	RequiresStorage bool
+	// Ephemeral storage in bytes the injector needs on its node, or zero if the package does not start the injector
+	InjectorStorage int64
+	// Namespaces the package deploys into
+	Namespaces []string
+}
+
+// RunPreflightChecks checks that the cluster meets the needs of a package before it is deployed.
+func (c *Cluster) RunPreflightChecks(ctx context.Context, opts PreflightOptions) []PreflightResult {
+	results := []PreflightResult{c.checkKubeVersion()}
+	if opts.RequiresStorage {
+		results = append(results, c.checkStorageClass(ctx, opts.StorageClass))
+	}
+	if opts.InjectorStorage > 0 {
+		results = append(results, c.checkInjectorStorage(ctx, opts.InjectorStorage))
+	}
+	results = append(results, c.checkPodSecurity(ctx, opts.Namespaces))
+	return results
+}
+
+// PreflightError returns an error naming the failed checks of the results, or nil if none failed.
+func PreflightError(results []PreflightResult) error {
+	failed := []string{}
+	for _, result := range results {
+		if result.Status == PreflightFail {
+			failed = append(failed, fmt.Sprintf("%s: %s", strings.ToLower(result.Check), result.Message))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d preflight check(s) failed: %s", len(failed), strings.Join(failed, "; "))
+}
+
+// checkKubeVersion checks that the version of the cluster is at least the minimum supported version.
+func (c *Cluster) checkKubeVersion() PreflightResult {
+	result := PreflightResult{Check: PreflightKubeVersion}
+	info, err := c.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		result.Status, result.Message = PreflightWarn, fmt.Sprintf("unable to get the Kubernetes version: %s", err)
+		return result
+	}
+	version, err := semver.NewVersion(info.GitVersion)
+	if err != nil {
+		result.Status, result.Message = PreflightWarn, fmt.Sprintf("unable to parse the Kubernetes version %s: %s", info.GitVersion, err)
+		return result
+	}
+	// Pre-release and build metadata such as -eks or +k3s1 are ignored so distribution versions compare as releases
+	release := semver.New(version.Major(), version.Minor(), version.Patch(), "", "")
+	if release.LessThan(semver.MustParse(MinimumKubeVersion)) {
+		result.Status, result.Message = PreflightFail, fmt.Sprintf("%s is older than the minimum supported version %s", info.GitVersion, MinimumKubeVersion)
+		return result
+	}
+	result.Status, result.Message = PreflightPass, info.GitVersion
+	return result
+}
+
+// checkStorageClass checks that the given storage class exists, or that there is a default storage class if none is given.
+func (c *Cluster) checkStorageClass(ctx context.Context, name string) PreflightResult {
+	result := PreflightResult{Check: PreflightStorageClass}
+	if name != "" {
+		_, err := c.Clientset.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case kerrors.IsNotFound(err):
+			result.Status, result.Message = PreflightFail, fmt.Sprintf("the storage class %s does not exist", name)
+		case err != nil:
+			result.Status, result.Message = PreflightWarn, fmt.Sprintf("unable to get the storage class %s: %s", name, err)
+		default:
+			result.Status, result.Message = PreflightPass, name
+		}
+		return result
+	}
+
+	storageClasses, err := c.Clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		result.Status, result.Message = PreflightWarn, fmt.Sprintf("unable to list the storage classes: %s", err)
+		return result
+	}
+	for _, sc := range storageClasses.Items {
+		if sc.Annotations[defaultStorageClassAnnotation] == "true" || sc.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+			result.Status, result.Message = PreflightPass, fmt.Sprintf("%s (default)", sc.Name)
+			return result
+		}
+	}
+	result.Status, result.Message = PreflightWarn, "there is no default storage class, persistent volume claims will not be bound unless one is set with --storage-class"
+	return result
+}
+
+// checkInjectorStorage checks that a node the injector can be scheduled on has enough ephemeral storage for its payload.
+func (c *Cluster) checkInjectorStorage(ctx context.Context, required int64) PreflightResult {
+	result := PreflightResult{Check: PreflightInjectorStorage}
+	requiredQuantity := resource.NewQuantity(required, resource.BinarySI)
+	nodeList, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		result.Status, result.Message = PreflightWarn, fmt.Sprintf("unable to list the nodes: %s", err)
+		return result
+	}
+
+	reported := false
+	for _, node := range nodeList.Items {
+		if hasBlockingTaints(node.Spec.Taints) || hasDiskPressure(node) {
+			continue
+		}
+		allocatable, ok := node.Status.Allocatable[corev1.ResourceEphemeralStorage]
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+		reported = true
+		if allocatable.Cmp(*requiredQuantity) >= 0 {
+			result.Status, result.Message = PreflightPass, fmt.Sprintf("node %s has %s available, %s is needed", node.Name, allocatable.String(), requiredQuantity.String())
+			return result
+		}
+	}
+	if !reported {
+		result.Status, result.Message = PreflightWarn, fmt.Sprintf("no schedulable node reports its ephemeral storage, %s is needed", requiredQuantity.String())
+		return result
+	}
+	result.Status, result.Message = PreflightFail, fmt.Sprintf("no schedulable node has the %s of ephemeral storage the injector needs", requiredQuantity.String())
+	return result
+}
+
+func hasDiskPressure(node corev1.Node) bool {
+	return slices.ContainsFunc(node.Status.Conditions, func(condition corev1.NodeCondition) bool {
+		return condition.Type == corev1.NodeDiskPressure && condition.Status == corev1.ConditionTrue
+	})
+}
+
+// checkPodSecurity checks for pod security admission and pod security policies that may reject the pods of the package.
+func (c *Cluster) checkPodSecurity(ctx context.Context, namespaces []string) PreflightResult {
+	result := PreflightResult{Check: PreflightPodSecurity}
+	warnings := []string{}
+	for _, name := range namespaces {
+		namespace, err := c.Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("unable to get the namespace %s: %s", name, err))
+			continue
+		}
+		if namespace.Labels[podSecurityEnforceLabel] == "restricted" {
+			warnings = append(warnings, fmt.Sprintf("the namespace %s enforces the restricted pod security standard", name))
+		}
+	}
+
+	resources, err := c.Clientset.Discovery().ServerResourcesForGroupVersion("policy/v1beta1")
+	if err != nil && !kerrors.IsNotFound(err) {
+		warnings = append(warnings, fmt.Sprintf("unable to check for pod security policies: %s", err))
+	}
+	if err == nil && slices.ContainsFunc(resources.APIResources, func(r metav1.APIResource) bool { return r.Name == "podsecuritypolicies" }) {
+		warnings = append(warnings, "pod security policies are enabled, pods must be admitted by a policy")
+	}
+
+	if len(warnings) > 0 {
+		result.Status, result.Message = PreflightWarn, strings.Join(warnings, "; ")
+		return result
+	}
+	result.Status, result.Message = PreflightPass, "no restrictions found"
+	return result
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestRunPreflightChecks(t *testing.T) {
+	t.Parallel()
+
+	node := func(name, storage string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{Taints: taints},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse(storage)},
+			},
+		}
+	}
+	defaultStorageClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "local-path",
+			Annotations: map[string]string{defaultStorageClassAnnotation: "true"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		kubeVersion string
+		objects     []runtime.Object
+		psp         bool
+		opts        PreflightOptions
+		expected    map[string]PreflightStatus
+	}{
+		{
+			name:        "all checks pass",
+			kubeVersion: "v1.30.2+k3s1",
+			objects: []runtime.Object{
+				defaultStorageClass,
+				node("small", "1Gi"),
+				node("large", "10Gi"),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "zarf", Labels: map[string]string{podSecurityEnforceLabel: "baseline"}}},
+			},
+			opts: PreflightOptions{RequiresStorage: true, InjectorStorage: 2 << 30, Namespaces: []string{"zarf", "podinfo"}},
+			expected: map[string]PreflightStatus{
+				PreflightKubeVersion:     PreflightPass,
+				PreflightStorageClass:    PreflightPass,
+				PreflightInjectorStorage: PreflightPass,
+				PreflightPodSecurity:     PreflightPass,
+			},
+		},
+		{
+			name:        "old cluster",
+			kubeVersion: "v1.22.17-eks-1234",
+			expected: map[string]PreflightStatus{
+				PreflightKubeVersion: PreflightFail,
+				PreflightPodSecurity: PreflightPass,
+			},
+		},
+		{
+			name:        "missing storage class",
+			kubeVersion: "v1.29.0",
+			objects:     []runtime.Object{defaultStorageClass},
+			opts:        PreflightOptions{RequiresStorage: true, StorageClass: "fast"},
+			expected: map[string]PreflightStatus{
+				PreflightKubeVersion:  PreflightPass,
+				PreflightStorageClass: PreflightFail,
+				PreflightPodSecurity:  PreflightPass,
+			},
+		},
+		{
+			name:        "no default storage class",
+			kubeVersion: "v1.29.0",
+			opts:        PreflightOptions{RequiresStorage: true},
+			expected: map[string]PreflightStatus{
+				PreflightKubeVersion:  PreflightPass,
+				PreflightStorageClass: PreflightWarn,
+				PreflightPodSecurity:  PreflightPass,
+			},
+		},
+		{
+			name:        "injector does not fit on a schedulable node",
+			kubeVersion: "v1.29.0",
+			objects: []runtime.Object{
+				node("small", "1Gi"),
+				node("control-plane", "100Gi", corev1.Taint{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule}),
+			},
+			opts: PreflightOptions{InjectorStorage: 2 << 30},
+			expected: map[string]PreflightStatus{
+				PreflightKubeVersion:     PreflightPass,
+				PreflightInjectorStorage: PreflightFail,
+				PreflightPodSecurity:     PreflightPass,
+			},
+		},
+		{
+			name:        "restricted namespace and pod security policies",
+			kubeVersion: "v1.24.0",
+			objects: []runtime.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Labels: map[string]string{podSecurityEnforceLabel: "restricted"}}},
+			},
+			psp:  true,
+			opts: PreflightOptions{Namespaces: []string{"podinfo"}},
+			expected: map[string]PreflightStatus{
+				PreflightKubeVersion: PreflightPass,
+				PreflightPodSecurity: PreflightWarn,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.TestContext(t)
+
+			cs := fake.NewSimpleClientset(tt.objects...)
+			discovery, ok := cs.Discovery().(*fakediscovery.FakeDiscovery)
+			require.True(t, ok)
+			discovery.FakedServerVersion = &version.Info{GitVersion: tt.kubeVersion}
+			if tt.psp {
+				discovery.Resources = []*metav1.APIResourceList{
+					{GroupVersion: "policy/v1beta1", APIResources: []metav1.APIResource{{Name: "podsecuritypolicies"}}},
+				}
+			}
+			c := &Cluster{Clientset: cs}
+
+			results := c.RunPreflightChecks(ctx, tt.opts)
+			statuses := map[string]PreflightStatus{}
+			for _, result := range results {
+				statuses[result.Check] = result.Status
+			}
+			require.Equal(t, tt.expected, statuses)
+
+			failed := false
+			for _, status := range tt.expected {
+				failed = failed || status == PreflightFail
+			}
+			if failed {
+				require.Error(t, PreflightError(results))
+			} else {
+				require.NoError(t, PreflightError(results))
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"k8s.io/client-go/rest"
+)
+
+// throttle limits the rate of requests made with the rest config to qps with the given burst, a qps or burst of zero
+// keeps the client defaults. Requests the API server rejects as too many requests are retried by the rest client after
+// the delay the API server asks for.
+func throttle(cfg *rest.Config, qps float32, burst int) {
+	if qps > 0 {
+		cfg.QPS = qps
+	}
+	if burst > 0 {
+		cfg.Burst = burst
+	}
+}
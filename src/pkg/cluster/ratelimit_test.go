@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestThrottle(t *testing.T) {
+	t.Parallel()
+
+	cfg := &rest.Config{}
+	throttle(cfg, 0, 0)
+	require.Zero(t, cfg.QPS)
+	require.Zero(t, cfg.Burst)
+	require.Nil(t, cfg.WrapTransport)
+
+	throttle(cfg, 2.5, 4)
+	require.Equal(t, float32(2.5), cfg.QPS)
+	require.Equal(t, 4, cfg.Burst)
+}
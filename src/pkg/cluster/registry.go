@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// registryContainerName is the name of the container that runs the registry in the Zarf registry pods.
+const registryContainerName = "docker-registry"
+
+// RegistryGarbageCollectOptions are the options for garbage collecting the Zarf registry.
+type RegistryGarbageCollectOptions struct {
+	// DryRun lists the blobs that would be removed without removing them
+	DryRun bool
+	// DeleteUntagged also removes manifests that are not referenced by a tag
+	DeleteUntagged bool
+}
+
+// registryGarbageCollectCommand returns the command that runs the distribution garbage collector in a registry container.
+func registryGarbageCollectCommand(opts RegistryGarbageCollectOptions) []string {
+	cmd := []string{"/bin/registry", "garbage-collect"}
+	if opts.DryRun {
+		cmd = append(cmd, "--dry-run")
+	}
+	if opts.DeleteUntagged {
+		cmd = append(cmd, "--delete-untagged")
+	}
+	return append(cmd, "/etc/docker/registry/config.yml")
+}
+
+// registryPod returns a running pod of the Zarf registry.
+func (c *Cluster) registryPod(ctx context.Context) (corev1.Pod, error) {
+	listOpts := metav1.ListOptions{LabelSelector: "app=docker-registry"}
+	podList, err := c.Clientset.CoreV1().Pods(ZarfNamespaceName).List(ctx, listOpts)
+	if err != nil {
+		return corev1.Pod{}, err
+	}
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			return pod, nil
+		}
+	}
+	return corev1.Pod{}, errors.New("unable to find a running Zarf registry pod")
+}
+
+// GarbageCollectRegistry runs the distribution garbage collector in a pod of the Zarf registry, writing its output to out.
+//
+// The registries share their storage so the garbage collector is only run in one of them. The registry should not
+// receive pushes while it runs, as layers uploaded during garbage collection may be removed.
+func (c *Cluster) GarbageCollectRegistry(ctx context.Context, opts RegistryGarbageCollectOptions, out io.Writer) error {
+	pod, err := c.registryPod(ctx)
+	if err != nil {
+		return err
+	}
+	req := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: registryContainerName,
+			Command:   registryGarbageCollectCommand(opts),
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(c.RestConfig, http.MethodPost, req.URL())
+	if err != nil {
+		return err
+	}
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: out, Stderr: out})
+	if err != nil {
+		return fmt.Errorf("unable to garbage collect the registry in pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// RestartRegistry triggers a rolling update of the Zarf registry.
+//
+// The registries cache the descriptors of the layers they have served, which must be cleared once garbage collection
+// removes layers so that pushes do not skip uploading them again.
+func (c *Cluster) RestartRegistry(ctx context.Context) error {
+	return c.RestartDeployment(ctx, ZarfNamespaceName, ZarfRegistryName)
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestRegistryGarbageCollectCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     RegistryGarbageCollectOptions
+		expected []string
+	}{
+		{
+			name:     "default",
+			expected: []string{"/bin/registry", "garbage-collect", "/etc/docker/registry/config.yml"},
+		},
+		{
+			name:     "dry run",
+			opts:     RegistryGarbageCollectOptions{DryRun: true},
+			expected: []string{"/bin/registry", "garbage-collect", "--dry-run", "/etc/docker/registry/config.yml"},
+		},
+		{
+			name:     "delete untagged",
+			opts:     RegistryGarbageCollectOptions{DryRun: true, DeleteUntagged: true},
+			expected: []string{"/bin/registry", "garbage-collect", "--dry-run", "--delete-untagged", "/etc/docker/registry/config.yml"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, registryGarbageCollectCommand(tt.opts))
+		})
+	}
+}
+
+func TestRegistryPod(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	_, err := c.registryPod(ctx)
+	require.EqualError(t, err, "unable to find a running Zarf registry pod")
+
+	deleted := metav1.Now()
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: ZarfNamespaceName, Labels: map[string]string{"app": "docker-registry"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "terminating", Namespace: ZarfNamespaceName, Labels: map[string]string{"app": "docker-registry"}, DeletionTimestamp: &deleted},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: ZarfNamespaceName, Labels: map[string]string{"app": "agent-hook"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: ZarfNamespaceName, Labels: map[string]string{"app": "docker-registry"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	}
+	for _, pod := range pods {
+		_, err := c.Clientset.CoreV1().Pods(ZarfNamespaceName).Create(ctx, pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	pod, err := c.registryPod(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "running", pod.Name)
+}
+
+func TestRestartRegistry(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &Cluster{Clientset: fake.NewSimpleClientset()}
+	require.Error(t, c.RestartRegistry(ctx))
+
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: ZarfRegistryName, Namespace: ZarfNamespaceName}}
+	_, err := c.Clientset.AppsV1().Deployments(ZarfNamespaceName).Create(ctx, deployment, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, c.RestartRegistry(ctx))
+	deployment, err = c.Clientset.AppsV1().Deployments(ZarfNamespaceName).Get(ctx, ZarfRegistryName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, deployment.Spec.Template.Annotations["zarf.dev/restartedAt"])
+}
@@ -11,10 +11,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"net"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -56,7 +58,7 @@ func (c *Cluster) GenerateRegistryPullCreds(ctx context.Context, namespace, name
 	// Build zarf-docker-registry service address string
 	svc, port, err := serviceInfoFromNodePortURL(serviceList.Items, registryInfo.Address)
 	if err == nil {
-		kubeDNSRegistryURL := fmt.Sprintf("%s:%d", svc.Spec.ClusterIP, port)
+		kubeDNSRegistryURL := net.JoinHostPort(svc.Spec.ClusterIP, strconv.Itoa(port))
 		dockerConfigJSON.Auths[kubeDNSRegistryURL] = DockerConfigEntryWithAuth{
 			Auth: authEncodedValue,
 		}
@@ -121,15 +123,16 @@ func (c *Cluster) UpdateZarfManagedImageSecrets(ctx context.Context, state *type
 	if err != nil {
 		return err
 	}
+	registrySecrets, err := c.secretsByNamespace(ctx, config.ZarfImagePullSecretName)
+	if err != nil {
+		return err
+	}
 	// Update all image pull secrets
 	for _, namespace := range namespaceList.Items {
-		currentRegistrySecret, err := c.Clientset.CoreV1().Secrets(namespace.Name).Get(ctx, config.ZarfImagePullSecretName, metav1.GetOptions{})
-		if kerrors.IsNotFound(err) {
+		currentRegistrySecret, ok := registrySecrets[namespace.Name]
+		if !ok {
 			continue
 		}
-		if err != nil {
-			return err
-		}
 		// Skip if namespace is skipped and secret is not managed by Zarf.
 		if currentRegistrySecret.Labels[ZarfManagedByLabel] != "zarf" && (namespace.Labels[AgentLabel] == "skip" || namespace.Labels[AgentLabel] == "ignore") {
 			continue
@@ -161,12 +164,13 @@ func (c *Cluster) UpdateZarfManagedGitSecrets(ctx context.Context, state *types.
 	if err != nil {
 		return err
 	}
+	gitSecrets, err := c.secretsByNamespace(ctx, config.ZarfGitServerSecretName)
+	if err != nil {
+		return err
+	}
 	for _, namespace := range namespaceList.Items {
-		currentGitSecret, err := c.Clientset.CoreV1().Secrets(namespace.Name).Get(ctx, config.ZarfGitServerSecretName, metav1.GetOptions{})
-		if kerrors.IsNotFound(err) {
-			continue
-		}
-		if err != nil {
+		currentGitSecret, ok := gitSecrets[namespace.Name]
+		if !ok {
 			continue
 		}
 		// Skip if namespace is skipped and secret is not managed by Zarf.
@@ -188,6 +192,26 @@ func (c *Cluster) UpdateZarfManagedGitSecrets(ctx context.Context, state *types.
 	return nil
 }
 
+// secretsByNamespace returns the secrets with the given name keyed by their namespace, listing them across all
+// namespaces in a single request instead of getting them one namespace at a time.
+func (c *Cluster) secretsByNamespace(ctx context.Context, name string) (map[string]*corev1.Secret, error) {
+	secretList, err := c.Clientset.CoreV1().Secrets(corev1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	secrets := map[string]*corev1.Secret{}
+	for i := range secretList.Items {
+		// Not every client honors field selectors.
+		if secretList.Items[i].Name != name {
+			continue
+		}
+		secrets[secretList.Items[i].Namespace] = &secretList.Items[i]
+	}
+	return secrets, nil
+}
+
 // GetServiceInfoFromRegistryAddress gets the service info for a registry address if it is a NodePort
 func (c *Cluster) GetServiceInfoFromRegistryAddress(ctx context.Context, stateRegistryAddress string) (string, error) {
 	serviceList, err := c.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{})
@@ -202,5 +226,6 @@ func (c *Cluster) GetServiceInfoFromRegistryAddress(ctx context.Context, stateRe
 		return stateRegistryAddress, nil
 	}
 
-	return fmt.Sprintf("%s:%d", svc.Spec.ClusterIP, port), nil
+	// IPv6 cluster IPs are bracketed so that the address can be used in image references and URLs
+	return net.JoinHostPort(svc.Spec.ClusterIP, strconv.Itoa(port)), nil
 }
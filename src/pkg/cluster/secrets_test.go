@@ -205,3 +205,56 @@ func TestUpdateZarfManagedSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestGetServiceInfoFromRegistryAddress(t *testing.T) {
+	t.Parallel()
+
+	registryService := func(clusterIP string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "zarf-docker-registry", Namespace: ZarfNamespaceName},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeNodePort,
+				ClusterIP: clusterIP,
+				Ports:     []corev1.ServicePort{{NodePort: 31999, Port: 5000}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		service  *corev1.Service
+		address  string
+		expected string
+	}{
+		{
+			name:     "IPv4",
+			service:  registryService("10.43.0.10"),
+			address:  "127.0.0.1:31999",
+			expected: "10.43.0.10:5000",
+		},
+		{
+			name:     "IPv6",
+			service:  registryService("fd00::10"),
+			address:  "[::1]:31999",
+			expected: "[fd00::10]:5000",
+		},
+		{
+			name:     "external registry",
+			service:  registryService("fd00::10"),
+			address:  "[2001:db8::1]:5000",
+			expected: "[2001:db8::1]:5000",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.TestContext(t)
+
+			c := &Cluster{Clientset: fake.NewSimpleClientset(tt.service)}
+			address, err := c.GetServiceInfoFromRegistryAddress(ctx, tt.address)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, address)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/kms"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// StateSnapshotKind is the kind of an exported state snapshot file.
+const StateSnapshotKind = "ZarfStateSnapshot"
+
+// scrypt parameters for deriving the data key of a snapshot from a password.
+const (
+	scryptN    = 1 << 15
+	scryptR    = 8
+	scryptP    = 1
+	scryptSalt = 16
+)
+
+// StateSnapshot is a copy of the Zarf state and the records of the deployed packages of a cluster, which can be
+// imported into the same or another cluster.
+type StateSnapshot struct {
+	// Version of the Zarf CLI that exported the snapshot
+	CLIVersion string `json:"cliVersion"`
+	// Time the snapshot was exported at
+	Created time.Time `json:"created"`
+	// The Zarf state, including the credentials of the registry, git server and artifact server
+	State *types.ZarfState `json:"state"`
+	// The deployed packages, including their deployment history
+	DeployedPackages []types.DeployedPackage `json:"deployedPackages"`
+}
+
+// SnapshotEncryptionOptions are the options for encrypting an exported state snapshot, a snapshot is not encrypted when
+// neither is set.
+type SnapshotEncryptionOptions struct {
+	// URI of a key in a key management service to encrypt the snapshot with
+	KeyURI string
+	// Password to encrypt the snapshot with
+	Password string
+}
+
+// snapshotEncryption describes how the data key of an encrypted snapshot is recovered.
+type snapshotEncryption struct {
+	// URI of the key that encrypts the data key, if the snapshot is encrypted with a key management service
+	KeyURI string `json:"keyURI,omitempty"`
+	// The data key, encrypted with the key
+	DataKey []byte `json:"dataKey,omitempty"`
+	// Salt to derive the data key from the password with, if the snapshot is encrypted with a password
+	Salt []byte `json:"salt,omitempty"`
+}
+
+// stateSnapshotFile is the format of an exported snapshot, which holds either the snapshot or its ciphertext.
+type stateSnapshotFile struct {
+	Kind       string              `json:"kind"`
+	Encryption *snapshotEncryption `json:"encryption,omitempty"`
+	Ciphertext []byte              `json:"ciphertext,omitempty"`
+	Snapshot   *StateSnapshot      `json:"snapshot,omitempty"`
+}
+
+// ExportStateSnapshot returns a snapshot of the Zarf state and the deployed packages of the cluster.
+func (c *Cluster) ExportStateSnapshot(ctx context.Context) (*StateSnapshot, error) {
+	state, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deployedPackages, err := c.GetDeployedZarfPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &StateSnapshot{
+		CLIVersion:       config.CLIVersion,
+		Created:          time.Now().UTC(),
+		State:            state,
+		DeployedPackages: deployedPackages,
+	}, nil
+}
+
+// ImportStateSnapshot saves the Zarf state and the deployed packages of the snapshot to the cluster, replacing the
+// existing state and the records of packages with the same name.
+func (c *Cluster) ImportStateSnapshot(ctx context.Context, snapshot *StateSnapshot) error {
+	if snapshot.State == nil {
+		return errors.New("the snapshot does not include a Zarf state")
+	}
+	spinner := message.NewProgressSpinner("Importing the Zarf state")
+	defer spinner.Stop()
+
+	// The existing namespaces of a new cluster are ignored by the agent in the same way as on init
+	_, err := c.Clientset.CoreV1().Namespaces().Get(ctx, ZarfNamespaceName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		if err := c.ignoreExistingNamespaces(ctx, spinner); err != nil {
+			return err
+		}
+		_, err = c.Clientset.CoreV1().Namespaces().Create(ctx, NewZarfManagedNamespace(ZarfNamespaceName), metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to create the Zarf namespace: %w", err)
+	}
+	if err := c.SaveZarfState(ctx, snapshot.State); err != nil {
+		return fmt.Errorf("unable to save the Zarf state: %w", err)
+	}
+	for _, deployedPackage := range snapshot.DeployedPackages {
+		deployedPackage := deployedPackage
+		spinner.Updatef("Importing the deployed package %s", deployedPackage.Name)
+		if _, err := c.saveDeployedPackage(ctx, &deployedPackage); err != nil {
+			return err
+		}
+	}
+	spinner.Success()
+	return nil
+}
+
+// WriteStateSnapshot writes the snapshot to w, encrypted with the key or password of the options if one is set.
+func WriteStateSnapshot(ctx context.Context, w io.Writer, snapshot *StateSnapshot, opts SnapshotEncryptionOptions) error {
+	file := stateSnapshotFile{Kind: StateSnapshotKind}
+	switch {
+	case opts.KeyURI != "" && opts.Password != "":
+		return errors.New("a snapshot can be encrypted with either a key or a password, not both")
+	case opts.KeyURI == "" && opts.Password == "":
+		file.Snapshot = snapshot
+	default:
+		plaintext, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+		var dataKey []byte
+		file.Encryption = &snapshotEncryption{}
+		if opts.KeyURI != "" {
+			encrypter, err := kms.New(ctx, opts.KeyURI)
+			if err != nil {
+				return err
+			}
+			if dataKey, err = kms.GenerateDataKey(); err != nil {
+				return err
+			}
+			file.Encryption.KeyURI = opts.KeyURI
+			file.Encryption.DataKey, err = encrypter.Encrypt(ctx, dataKey)
+			if err != nil {
+				return fmt.Errorf("unable to encrypt the snapshot data key with %s: %w", opts.KeyURI, err)
+			}
+		} else {
+			file.Encryption.Salt = make([]byte, scryptSalt)
+			if _, err := io.ReadFull(rand.Reader, file.Encryption.Salt); err != nil {
+				return err
+			}
+			if dataKey, err = passwordKey(opts.Password, file.Encryption.Salt); err != nil {
+				return err
+			}
+		}
+		if file.Ciphertext, err = kms.Seal(dataKey, plaintext); err != nil {
+			return err
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(file)
+}
+
+// ReadStateSnapshot reads a snapshot written by WriteStateSnapshot, decrypting it with its key or the given password.
+func ReadStateSnapshot(ctx context.Context, r io.Reader, password string) (*StateSnapshot, error) {
+	file := stateSnapshotFile{}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("unable to read the snapshot: %w", err)
+	}
+	if file.Kind != StateSnapshotKind {
+		return nil, fmt.Errorf("expected a %s but got kind %q", StateSnapshotKind, file.Kind)
+	}
+	if file.Encryption == nil {
+		if file.Snapshot == nil {
+			return nil, errors.New("the snapshot file is empty")
+		}
+		return file.Snapshot, nil
+	}
+
+	var dataKey []byte
+	var err error
+	if file.Encryption.KeyURI != "" {
+		encrypter, err := kms.New(ctx, file.Encryption.KeyURI)
+		if err != nil {
+			return nil, err
+		}
+		dataKey, err = encrypter.Decrypt(ctx, file.Encryption.DataKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt the snapshot data key with %s: %w", file.Encryption.KeyURI, err)
+		}
+	} else {
+		if password == "" {
+			return nil, errors.New("the snapshot is encrypted with a password, but none was given")
+		}
+		if dataKey, err = passwordKey(password, file.Encryption.Salt); err != nil {
+			return nil, err
+		}
+	}
+	plaintext, err := kms.Open(dataKey, file.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &StateSnapshot{}
+	if err := json.Unmarshal(plaintext, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// passwordKey derives the data key of a snapshot from a password.
+func passwordKey(password string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, kms.KeySize)
+}
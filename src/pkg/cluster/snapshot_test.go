@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestStateSnapshotFile(t *testing.T) {
+	t.Parallel()
+
+	snapshot := &StateSnapshot{
+		CLIVersion: "v0.0.0",
+		Created:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		State: &types.ZarfState{
+			Distro:       "k3s",
+			RegistryInfo: types.RegistryInfo{PushUsername: "zarf-push", PushPassword: "registry-push"},
+		},
+		DeployedPackages: []types.DeployedPackage{{Name: "podinfo", Generation: 3}},
+	}
+
+	tests := []struct {
+		name         string
+		opts         SnapshotEncryptionOptions
+		readPassword string
+		expectedErr  string
+		encrypted    bool
+	}{
+		{
+			name: "unencrypted",
+		},
+		{
+			name:         "password",
+			opts:         SnapshotEncryptionOptions{Password: "correct horse"},
+			readPassword: "correct horse",
+			encrypted:    true,
+		},
+		{
+			name:         "wrong password",
+			opts:         SnapshotEncryptionOptions{Password: "correct horse"},
+			readPassword: "battery staple",
+			expectedErr:  "unable to decrypt, the data key may be wrong",
+			encrypted:    true,
+		},
+		{
+			name:        "missing password",
+			opts:        SnapshotEncryptionOptions{Password: "correct horse"},
+			expectedErr: "the snapshot is encrypted with a password, but none was given",
+			encrypted:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.TestContext(t)
+			buf := &bytes.Buffer{}
+			require.NoError(t, WriteStateSnapshot(ctx, buf, snapshot, tt.opts))
+			require.Equal(t, !tt.encrypted, strings.Contains(buf.String(), "registry-push"))
+			read, err := ReadStateSnapshot(ctx, buf, tt.readPassword)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, snapshot, read)
+		})
+	}
+}
+
+func TestStateSnapshotFileErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	err := WriteStateSnapshot(ctx, &bytes.Buffer{}, &StateSnapshot{}, SnapshotEncryptionOptions{KeyURI: "awskms:///alias/zarf", Password: "password"})
+	require.EqualError(t, err, "a snapshot can be encrypted with either a key or a password, not both")
+	_, err = ReadStateSnapshot(ctx, strings.NewReader(`{"kind":"ZarfPackageConfig"}`), "")
+	require.EqualError(t, err, `expected a ZarfStateSnapshot but got kind "ZarfPackageConfig"`)
+	_, err = ReadStateSnapshot(ctx, strings.NewReader(`{"kind":"ZarfStateSnapshot"}`), "")
+	require.EqualError(t, err, "the snapshot file is empty")
+}
+
+func TestExportImportStateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	src := &Cluster{Clientset: fake.NewSimpleClientset()}
+	state := &types.ZarfState{
+		Distro:       "k3s",
+		GitServer:    types.GitServerInfo{PushUsername: "zarf-git-user", PushPassword: "git-push"},
+		RegistryInfo: types.RegistryInfo{PushUsername: "zarf-push", PushPassword: "registry-push"},
+	}
+	require.NoError(t, src.SaveZarfState(ctx, state))
+	pkg := v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Name: "podinfo"}}
+	_, err := src.RecordPackageDeployment(ctx, pkg, []types.DeployedComponent{{Name: "podinfo"}}, 2, nil)
+	require.NoError(t, err)
+
+	snapshot, err := src.ExportStateSnapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, state, snapshot.State)
+	require.Len(t, snapshot.DeployedPackages, 1)
+
+	// A new cluster has its existing namespaces ignored by the agent
+	dst := &Cluster{Clientset: fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})}
+	require.NoError(t, dst.ImportStateSnapshot(ctx, snapshot))
+	namespace, err := dst.Clientset.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "ignore", namespace.Labels[AgentLabel])
+	_, err = dst.Clientset.CoreV1().Namespaces().Get(ctx, ZarfNamespaceName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	imported, err := dst.LoadZarfState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, state, imported)
+	deployedPackage, err := dst.GetDeployedPackage(ctx, "podinfo")
+	require.NoError(t, err)
+	require.Equal(t, snapshot.DeployedPackages[0], *deployedPackage)
+	require.Equal(t, 2, deployedPackage.Generation)
+}
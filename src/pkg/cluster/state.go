@@ -9,7 +9,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"slices"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -18,10 +20,8 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	"github.com/defenseunicorns/pkg/helpers/v2"
-	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/message"
-	"github.com/zarf-dev/zarf/src/pkg/pki"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -32,6 +32,8 @@ const (
 	ZarfStateSecretName  = "zarf-state"
 	ZarfStateDataKey     = "state"
 	ZarfPackageInfoLabel = "package-deploy-info"
+	// CorrelationIDAnnotation records the correlation ID of the Zarf operation that created a resource.
+	CorrelationIDAnnotation = "zarf.dev/correlation-id"
 )
 
 // InitZarfState initializes the Zarf state with the given temporary directory and init configs.
@@ -70,38 +72,24 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 				return err
 			}
 			state.Distro = detectDistro(nodeList.Items[0], namespaceList.Items)
+
+			// The internal registry is reached on the IPv6 loopback address of the nodes of an IPv6-only cluster
+			if initOptions.RegistryInfo.Address == "" && isIPv6Only(nodeList.Items) {
+				spinner.Updatef("Detected an IPv6-only cluster")
+				if initOptions.RegistryInfo.NodePort == 0 {
+					initOptions.RegistryInfo.NodePort = types.ZarfInClusterContainerRegistryNodePort
+				}
+				initOptions.RegistryInfo.Address = net.JoinHostPort(types.IPV6Localhost, strconv.Itoa(initOptions.RegistryInfo.NodePort))
+			}
 		}
 
 		if state.Distro != DistroIsUnknown {
 			spinner.Updatef("Detected K8s distro %s", state.Distro)
 		}
 
-		// Setup zarf agent PKI
-		agentTLS, err := pki.GeneratePKI(config.ZarfAgentHost)
-		if err != nil {
+		if err := c.ignoreExistingNamespaces(ctx, spinner); err != nil {
 			return err
 		}
-		state.AgentTLS = agentTLS
-
-		namespaceList, err := c.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
-		if err != nil {
-			return fmt.Errorf("unable to get the Kubernetes namespaces: %w", err)
-		}
-		// Mark existing namespaces as ignored for the zarf agent to prevent mutating resources we don't own.
-		for _, namespace := range namespaceList.Items {
-			spinner.Updatef("Marking existing namespace %s as ignored by Zarf Agent", namespace.Name)
-			if namespace.Labels == nil {
-				// Ensure label map exists to avoid nil panic
-				namespace.Labels = make(map[string]string)
-			}
-			// This label will tell the Zarf Agent to ignore this namespace.
-			namespace.Labels[AgentLabel] = "ignore"
-			namespaceCopy := namespace
-			_, err := c.Clientset.CoreV1().Namespaces().Update(ctx, &namespaceCopy, metav1.UpdateOptions{})
-			if err != nil {
-				return fmt.Errorf("unable to mark the namespace %s as ignored by Zarf Agent: %w", namespace.Name, err)
-			}
-		}
 
 		// Try to create the zarf namespace.
 		spinner.Updatef("Creating the Zarf namespace")
@@ -152,6 +140,19 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 		state.RegistryInfo = initOptions.RegistryInfo
 		initOptions.ArtifactServer.FillInEmptyValues()
 		state.ArtifactServer = initOptions.ArtifactServer
+
+		// Setup the PKI for the agent and, with a private CA, the registry and git server
+		ca, err := LoadCertificateAuthority(initOptions)
+		if err != nil {
+			return err
+		}
+		err = issueCertificates(state, ca, initOptions.TLSSubjectAltNames, []string{message.AgentKey})
+		if err != nil {
+			return err
+		}
+		if err := c.ApplyCABundle(ctx, ZarfNamespaceName, state.CABundle); err != nil {
+			return fmt.Errorf("unable to create the CA bundle in the Zarf namespace: %w", err)
+		}
 	} else {
 		if helpers.IsNotZeroAndNotEqual(initOptions.GitServer, state.GitServer) {
 			message.Warn("Detected a change in Git Server init options on a re-init. Ignoring... To update run:")
@@ -165,6 +166,10 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 			message.Warn("Detected a change in Artifact Server init options on a re-init. Ignoring... To update run:")
 			message.ZarfCommand("tools update-creds artifact")
 		}
+		if initOptions.CACertPath != "" || initOptions.CAKeyPath != "" {
+			message.Warn("Detected a private CA in init options on a re-init. Ignoring... To update run:")
+			message.ZarfCommand("tools update-creds --ca-cert <cert> --ca-key <key>")
+		}
 	}
 
 	switch state.Distro {
@@ -182,6 +187,14 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 		state.StorageClass = initOptions.StorageClass
 	}
 
+	// Setting the key on a re-init encrypts an unencrypted state or moves an encrypted state to the new key
+	if initOptions.StateEncryptionKey != "" {
+		if err := ValidateStateEncryptionKey(initOptions.StateEncryptionKey); err != nil {
+			return err
+		}
+		state.Encryption = &types.StateEncryption{KeyURI: initOptions.StateEncryptionKey}
+	}
+
 	spinner.Success()
 
 	// Save the state back to K8s
@@ -192,6 +205,34 @@ func (c *Cluster) InitZarfState(ctx context.Context, initOptions types.ZarfInitO
 	return nil
 }
 
+// ignoreExistingNamespaces marks the existing namespaces as ignored for the zarf agent to prevent mutating resources we
+// don't own.
+func (c *Cluster) ignoreExistingNamespaces(ctx context.Context, spinner *message.Spinner) error {
+	namespaceList, err := c.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to get the Kubernetes namespaces: %w", err)
+	}
+	for _, namespace := range namespaceList.Items {
+		// Skip the write if the namespace is already ignored.
+		if namespace.Labels[AgentLabel] == "ignore" {
+			continue
+		}
+		spinner.Updatef("Marking existing namespace %s as ignored by Zarf Agent", namespace.Name)
+		if namespace.Labels == nil {
+			// Ensure label map exists to avoid nil panic
+			namespace.Labels = make(map[string]string)
+		}
+		// This label will tell the Zarf Agent to ignore this namespace.
+		namespace.Labels[AgentLabel] = "ignore"
+		namespaceCopy := namespace
+		_, err := c.Clientset.CoreV1().Namespaces().Update(ctx, &namespaceCopy, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to mark the namespace %s as ignored by Zarf Agent: %w", namespace.Name, err)
+		}
+	}
+	return nil
+}
+
 // LoadZarfState returns the current zarf/zarf-state secret data or an empty ZarfState.
 func (c *Cluster) LoadZarfState(ctx context.Context) (*types.ZarfState, error) {
 	stateErr := errors.New("failed to load the Zarf State from the cluster, has Zarf been initiated?")
@@ -200,8 +241,7 @@ func (c *Cluster) LoadZarfState(ctx context.Context) (*types.ZarfState, error) {
 		return nil, fmt.Errorf("%w: %w", stateErr, err)
 	}
 
-	state := &types.ZarfState{}
-	err = json.Unmarshal(secret.Data[ZarfStateDataKey], &state)
+	state, err := c.unmarshalZarfState(ctx, secret)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", stateErr, err)
 	}
@@ -209,35 +249,39 @@ func (c *Cluster) LoadZarfState(ctx context.Context) (*types.ZarfState, error) {
 	return state, nil
 }
 
-func (c *Cluster) sanitizeZarfState(state *types.ZarfState) *types.ZarfState {
+// SanitizeZarfState returns a copy of the state with its credentials and private keys sanitized.
+func SanitizeZarfState(state *types.ZarfState) *types.ZarfState {
+	if state == nil {
+		return nil
+	}
+	// this is a shallow copy, nested pointers WILL NOT be copied
+	sanitized := *state
+
 	// Overwrite the AgentTLS information
-	state.AgentTLS.CA = []byte("**sanitized**")
-	state.AgentTLS.Cert = []byte("**sanitized**")
-	state.AgentTLS.Key = []byte("**sanitized**")
+	sanitized.AgentTLS.CA = []byte("**sanitized**")
+	sanitized.AgentTLS.Cert = []byte("**sanitized**")
+	sanitized.AgentTLS.Key = []byte("**sanitized**")
 
 	// Overwrite the GitServer passwords
-	state.GitServer.PushPassword = "**sanitized**"
-	state.GitServer.PullPassword = "**sanitized**"
+	sanitized.GitServer.PushPassword = "**sanitized**"
+	sanitized.GitServer.PullPassword = "**sanitized**"
 
 	// Overwrite the RegistryInfo passwords
-	state.RegistryInfo.PushPassword = "**sanitized**"
-	state.RegistryInfo.PullPassword = "**sanitized**"
-	state.RegistryInfo.Secret = "**sanitized**"
+	sanitized.RegistryInfo.PushPassword = "**sanitized**"
+	sanitized.RegistryInfo.PullPassword = "**sanitized**"
+	sanitized.RegistryInfo.Secret = "**sanitized**"
 
 	// Overwrite the ArtifactServer secret
-	state.ArtifactServer.PushToken = "**sanitized**"
+	sanitized.ArtifactServer.PushToken = "**sanitized**"
 
-	return state
+	return &sanitized
 }
 
 func (c *Cluster) debugPrintZarfState(state *types.ZarfState) {
 	if state == nil {
 		return
 	}
-	// this is a shallow copy, nested pointers WILL NOT be copied
-	oldState := *state
-	sanitized := c.sanitizeZarfState(&oldState)
-	b, err := json.MarshalIndent(sanitized, "", "  ")
+	b, err := json.MarshalIndent(SanitizeZarfState(state), "", "  ")
 	if err != nil {
 		return
 	}
@@ -248,7 +292,7 @@ func (c *Cluster) debugPrintZarfState(state *types.ZarfState) {
 func (c *Cluster) SaveZarfState(ctx context.Context, state *types.ZarfState) error {
 	c.debugPrintZarfState(state)
 
-	data, err := json.Marshal(&state)
+	data, err := c.marshalZarfState(ctx, state)
 	if err != nil {
 		return err
 	}
@@ -330,12 +374,12 @@ func MergeZarfState(oldState *types.ZarfState, initOptions types.ZarfInitOptions
 			newState.ArtifactServer.PushToken = ""
 		}
 	}
-	if slices.Contains(services, message.AgentKey) {
-		agentTLS, err := pki.GeneratePKI(config.ZarfAgentHost)
-		if err != nil {
-			return nil, err
-		}
-		newState.AgentTLS = agentTLS
+	ca, err := LoadCertificateAuthority(initOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err := issueCertificates(&newState, ca, initOptions.TLSSubjectAltNames, services); err != nil {
+		return nil, err
 	}
 
 	return &newState, nil
@@ -6,8 +6,17 @@ package cluster
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -18,6 +27,7 @@ import (
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/pki"
 	"github.com/zarf-dev/zarf/src/types"
@@ -488,3 +498,84 @@ func TestMergeZarfStateAgent(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEqual(t, oldState.AgentTLS, newState.AgentTLS)
 }
+
+func TestMergeZarfStatePrivateCA(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Private CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	caCert := filepath.Join(dir, "ca.crt")
+	caKey := filepath.Join(dir, "ca.key")
+	require.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(caKey, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	registryInfo := types.RegistryInfo{}
+	require.NoError(t, registryInfo.FillInEmptyValues())
+	oldState := &types.ZarfState{
+		RegistryInfo: registryInfo,
+		GitServer:    types.GitServerInfo{Address: "https://git.example.com"},
+	}
+
+	_, err = MergeZarfState(oldState, types.ZarfInitOptions{CACertPath: caCert}, []string{message.RegistryKey})
+	require.EqualError(t, err, "both --ca-cert and --ca-key must be provided to use a private CA")
+
+	// Adopting a private CA reissues the agent certificate from it, even when the agent was not asked to be updated.
+	initOptions := types.ZarfInitOptions{CACertPath: caCert, CAKeyPath: caKey, TLSSubjectAltNames: []string{"agent.example.com"}}
+	newState, err := MergeZarfState(oldState, initOptions, []string{message.RegistryKey})
+	require.NoError(t, err)
+	require.NotEmpty(t, newState.CABundle)
+	require.Equal(t, newState.CABundle, newState.AgentTLS.CA)
+
+	block, _ := pem.Decode(newState.AgentTLS.Cert)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	require.Equal(t, []string{config.ZarfAgentHost, "agent.example.com"}, cert.DNSNames)
+
+	// The agent certificate can not be reissued without the private CA once the cluster uses one.
+	_, err = MergeZarfState(newState, types.ZarfInitOptions{}, []string{message.AgentKey})
+	require.ErrorIs(t, err, ErrPrivateCARequired)
+	unchanged, err := MergeZarfState(newState, types.ZarfInitOptions{}, []string{message.RegistryKey})
+	require.NoError(t, err)
+	require.Equal(t, newState.AgentTLS, unchanged.AgentTLS)
+}
+
+func TestSanitizeZarfState(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, SanitizeZarfState(nil))
+
+	state := &types.ZarfState{
+		Distro:         "k3s",
+		AgentTLS:       types.GeneratedPKI{CA: []byte("ca"), Cert: []byte("cert"), Key: []byte("key")},
+		GitServer:      types.GitServerInfo{Address: "https://git.example.com", PushPassword: "push", PullPassword: "pull"},
+		RegistryInfo:   types.RegistryInfo{Address: "registry.example.com", PushPassword: "push", PullPassword: "pull", Secret: "secret"},
+		ArtifactServer: types.ArtifactServerInfo{Address: "https://artifacts.example.com", PushToken: "token"},
+	}
+	sanitized := SanitizeZarfState(state)
+	require.Equal(t, "k3s", sanitized.Distro)
+	require.Equal(t, "registry.example.com", sanitized.RegistryInfo.Address)
+	require.Equal(t, []byte("**sanitized**"), sanitized.AgentTLS.Key)
+	require.Equal(t, "**sanitized**", sanitized.GitServer.PushPassword)
+	require.Equal(t, "**sanitized**", sanitized.RegistryInfo.PullPassword)
+	require.Equal(t, "**sanitized**", sanitized.RegistryInfo.Secret)
+	require.Equal(t, "**sanitized**", sanitized.ArtifactServer.PushToken)
+
+	// The state itself is left as it was.
+	require.Equal(t, []byte("key"), state.AgentTLS.Key)
+	require.Equal(t, "push", state.GitServer.PushPassword)
+	require.Equal(t, "secret", state.RegistryInfo.Secret)
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// Zarf deploy status constants.
+const (
+	// ZarfDeployStatusLabel labels the config maps that hold the deploy status of a package with the name of the package.
+	ZarfDeployStatusLabel   = "zarf.dev/deploy-status"
+	ZarfDeployStatusPrefix  = "zarf-deploy-status-"
+	ZarfDeployStatusDataKey = "status"
+)
+
+// PublishDeployStatus saves the deploy status of a package to the cluster so that it can be observed through the Zarf
+// agent by operators that are not running the deploy.
+func (c *Cluster) PublishDeployStatus(ctx context.Context, status types.DeployStatus) error {
+	b, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ZarfDeployStatusPrefix + status.Package,
+			Namespace: ZarfNamespaceName,
+			Labels: map[string]string{
+				ZarfManagedByLabel:    "zarf",
+				ZarfDeployStatusLabel: status.Package,
+			},
+		},
+		Data: map[string]string{
+			ZarfDeployStatusDataKey: string(b),
+		},
+	}
+	_, err = c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Create(ctx, cm, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		_, err = c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// GetDeployStatuses returns the published deploy status of every package, sorted by package name.
+func (c *Cluster) GetDeployStatuses(ctx context.Context) ([]types.DeployStatus, error) {
+	listOpts := metav1.ListOptions{LabelSelector: ZarfDeployStatusLabel}
+	cms, err := c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []types.DeployStatus{}
+	var errs []error
+	for _, cm := range cms.Items {
+		var status types.DeployStatus
+		if err := json.Unmarshal([]byte(cm.Data[ZarfDeployStatusDataKey]), &status); err != nil {
+			errs = append(errs, fmt.Errorf("unable to read the deploy status in %s: %w", cm.Name, err))
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	slices.SortFunc(statuses, func(a, b types.DeployStatus) int {
+		return strings.Compare(a.Package, b.Package)
+	})
+	return statuses, errors.Join(errs...)
+}
+
+// AgentClient talks to the Zarf agent through a tunnel, trusting only the certificate the agent was issued at init.
+type AgentClient struct {
+	tunnel   *Tunnel
+	client   *http.Client
+	endpoint string
+}
+
+// NewAgentClient opens a tunnel to the Zarf agent and returns a client for it.
+func (c *Cluster) NewAgentClient(ctx context.Context) (*AgentClient, error) {
+	state, err := c.LoadZarfState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(state.AgentTLS.CA) {
+		return nil, errors.New("unable to read the certificate authority of the Zarf agent from the Zarf state")
+	}
+
+	tunnel, err := c.NewTunnel(ZarfNamespaceName, SvcResource, ZarfAgentName, "", 0, ZarfAgentPort)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tunnel.Connect(ctx); err != nil {
+		return nil, err
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			// The tunnel is reached through localhost, so verify the agent by the name it has within the cluster.
+			TLSClientConfig: &tls.Config{RootCAs: pool, ServerName: config.ZarfAgentHost, MinVersion: tls.VersionTLS12},
+		},
+	}
+	return &AgentClient{
+		tunnel:   tunnel,
+		client:   client,
+		endpoint: "https://" + tunnel.Endpoint(),
+	}, nil
+}
+
+// DeployStatuses returns the deploy status of every package that the agent serves, or only that of the given package.
+func (a *AgentClient) DeployStatuses(ctx context.Context, packageName string) ([]types.DeployStatus, error) {
+	u := a.endpoint + "/status"
+	if packageName != "" {
+		u += "?" + url.Values{"package": {packageName}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get the deploy status from the Zarf agent: %s", resp.Status)
+	}
+	statuses := []types.DeployStatus{}
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, fmt.Errorf("unable to read the deploy status from the Zarf agent: %w", err)
+	}
+	return statuses, nil
+}
+
+// Close closes the tunnel to the agent.
+func (a *AgentClient) Close() {
+	if a.tunnel != nil {
+		a.tunnel.Close()
+	}
+}
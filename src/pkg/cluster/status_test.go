@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestDeployStatus(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(),
+	}
+
+	// Config maps without the deploy status label are ignored.
+	_, err := c.Clientset.CoreV1().ConfigMaps(ZarfNamespaceName).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: ZarfNamespaceName},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	started := time.Now().UTC().Truncate(time.Second)
+	podinfo := types.DeployStatus{Package: "podinfo", Phase: types.ComponentStatusDeploying, Component: "podinfo", Step: "Pushing images", ComponentsTotal: 2, Started: started, Updated: started}
+	require.NoError(t, c.PublishDeployStatus(ctx, podinfo))
+	game := types.DeployStatus{Package: "game", Phase: types.ComponentStatusSucceeded, ComponentsCompleted: 1, ComponentsTotal: 1, Started: started, Updated: started}
+	require.NoError(t, c.PublishDeployStatus(ctx, game))
+
+	// Publishing again updates the status of the package.
+	podinfo.ComponentsCompleted = 1
+	podinfo.Step = "Installing charts and manifests"
+	require.NoError(t, c.PublishDeployStatus(ctx, podinfo))
+
+	statuses, err := c.GetDeployStatuses(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []types.DeployStatus{game, podinfo}, statuses)
+
+	// The agent client reads the statuses that the agent serves.
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/status", r.URL.Path)
+		require.Equal(t, "podinfo", r.URL.Query().Get("package"))
+		//nolint: errcheck // ignore
+		json.NewEncoder(w).Encode([]types.DeployStatus{podinfo})
+	}))
+	t.Cleanup(srv.Close)
+	agent := &AgentClient{client: srv.Client(), endpoint: srv.URL}
+	defer agent.Close()
+	statuses, err = agent.DeployStatuses(ctx, "podinfo")
+	require.NoError(t, err)
+	require.Equal(t, []types.DeployStatus{podinfo}, statuses)
+}
@@ -16,6 +16,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
@@ -23,6 +24,8 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/faults"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -43,6 +46,8 @@ const (
 	ZarfRegistryPort  = 5000
 	ZarfGitServerName = "zarf-gitea-http"
 	ZarfGitServerPort = 3000
+	ZarfAgentName     = "agent-hook"
+	ZarfAgentPort     = 8443
 )
 
 // TunnelInfo is a struct that contains the necessary info to create a new Tunnel
@@ -78,9 +83,43 @@ func (c *Cluster) ListConnections(ctx context.Context) (types.ConnectStrings, er
 			URL:         svc.Annotations[ZarfConnectAnnotationURL],
 		}
 	}
+	deployedPackages, err := c.GetDeployedZarfPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Services labeled in the cluster take precedence over the connect targets of the packages.
+	for name, target := range packageConnections(deployedPackages) {
+		if _, ok := connections[name]; ok {
+			continue
+		}
+		connections[name] = types.ConnectString{
+			Description: target.Description,
+			URL:         target.URL,
+		}
+	}
 	return connections, nil
 }
 
+// packageConnections returns the connect targets of the deployed components of the packages by name.
+func packageConnections(deployedPackages []types.DeployedPackage) map[string]v1alpha1.ZarfComponentConnect {
+	connections := map[string]v1alpha1.ZarfComponentConnect{}
+	for _, deployedPackage := range deployedPackages {
+		deployed := map[string]bool{}
+		for _, component := range deployedPackage.DeployedComponents {
+			deployed[component.Name] = true
+		}
+		for _, component := range deployedPackage.Data.Components {
+			if !deployed[component.Name] {
+				continue
+			}
+			for _, target := range component.Connect {
+				connections[target.Name] = target
+			}
+		}
+	}
+	return connections
+}
+
 // NewTargetTunnelInfo returns a new TunnelInfo object for the specified target.
 func (c *Cluster) NewTargetTunnelInfo(ctx context.Context, target string) (TunnelInfo, error) {
 	zt := TunnelInfo{
@@ -210,7 +249,7 @@ func (c *Cluster) checkForZarfConnectLabel(ctx context.Context, name string) (Tu
 		// if targetPort == 0, look for Port (which is required)
 		if zt.RemotePort == 0 {
 			// TODO: Need a check for if container port is not found
-			remotePort, err := c.findPodContainerPort(ctx, svc)
+			remotePort, err := c.findPodContainerPort(ctx, svc, svc.Spec.Ports[0].TargetPort.String())
 			if err != nil {
 				return TunnelInfo{}, err
 			}
@@ -222,13 +261,68 @@ func (c *Cluster) checkForZarfConnectLabel(ctx context.Context, name string) (Tu
 
 		message.Debugf("tunnel connection match: %s/%s on port %d", svc.Namespace, svc.Name, zt.RemotePort)
 	} else {
-		return zt, fmt.Errorf("no matching services found for %s", name)
+		// Fall back to the connect targets of the deployed packages.
+		return c.checkForPackageConnect(ctx, name)
 	}
 
 	return zt, nil
 }
 
-func (c *Cluster) findPodContainerPort(ctx context.Context, svc corev1.Service) (int, error) {
+// checkForPackageConnect looks in the deployed packages for a connect target that matches the name
+func (c *Cluster) checkForPackageConnect(ctx context.Context, name string) (TunnelInfo, error) {
+	deployedPackages, err := c.GetDeployedZarfPackages(ctx)
+	if err != nil {
+		return TunnelInfo{}, err
+	}
+	target, ok := packageConnections(deployedPackages)[name]
+	if !ok {
+		return TunnelInfo{}, fmt.Errorf("no matching services found for %s", name)
+	}
+	svc, err := c.Clientset.CoreV1().Services(target.Namespace).Get(ctx, target.Service, metav1.GetOptions{})
+	if err != nil {
+		return TunnelInfo{}, err
+	}
+	remotePort, err := c.serviceTargetPort(ctx, *svc, target.Port)
+	if err != nil {
+		return TunnelInfo{}, err
+	}
+
+	message.Debugf("tunnel connection match: %s/%s on port %d", svc.Namespace, svc.Name, remotePort)
+	return TunnelInfo{
+		Namespace:    svc.Namespace,
+		ResourceType: SvcResource,
+		ResourceName: svc.Name,
+		RemotePort:   remotePort,
+		urlSuffix:    target.URL,
+	}, nil
+}
+
+// serviceTargetPort returns the port of the pods of the service that the service port forwards to.
+func (c *Cluster) serviceTargetPort(ctx context.Context, svc corev1.Service, servicePort int) (int, error) {
+	for _, port := range svc.Spec.Ports {
+		if int(port.Port) != servicePort {
+			continue
+		}
+		if port.TargetPort.Type == intstr.String {
+			remotePort, err := c.findPodContainerPort(ctx, svc, port.TargetPort.StrVal)
+			if err != nil {
+				return 0, err
+			}
+			if remotePort == 0 {
+				return 0, fmt.Errorf("no pod of service %s/%s has a container port named %s", svc.Namespace, svc.Name, port.TargetPort.StrVal)
+			}
+			return remotePort, nil
+		}
+		// The target port defaults to the port of the service.
+		if port.TargetPort.IntValue() == 0 {
+			return servicePort, nil
+		}
+		return port.TargetPort.IntValue(), nil
+	}
+	return 0, fmt.Errorf("service %s/%s does not have port %d", svc.Namespace, svc.Name, servicePort)
+}
+
+func (c *Cluster) findPodContainerPort(ctx context.Context, svc corev1.Service, portName string) (int, error) {
 	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: svc.Spec.Selector})
 	if err != nil {
 		return 0, err
@@ -241,7 +335,7 @@ func (c *Cluster) findPodContainerPort(ctx context.Context, svc corev1.Service)
 		// Find the matching name on the port in the pod
 		for _, container := range pod.Spec.Containers {
 			for _, port := range container.Ports {
-				if port.Name == svc.Spec.Ports[0].TargetPort.String() {
+				if port.Name == portName {
 					return int(port.ContainerPort), nil
 				}
 			}
@@ -263,7 +357,7 @@ func serviceInfoFromNodePortURL(services []corev1.Service, nodePortURL string) (
 
 	// Match hostname against localhost ip/hostnames
 	hostname := parsedURL.Hostname()
-	if hostname != helpers.IPV4Localhost && hostname != "localhost" {
+	if hostname != helpers.IPV4Localhost && hostname != types.IPV6Localhost && hostname != "localhost" {
 		return corev1.Service{}, 0, fmt.Errorf("node port services should be on localhost")
 	}
 
@@ -353,6 +447,9 @@ func (tunnel *Tunnel) Wrap(function func() error) error {
 // Connect will establish a tunnel to the specified target.
 func (tunnel *Tunnel) Connect(ctx context.Context) (string, error) {
 	url, err := retry.DoWithData(func() (string, error) {
+		if err := faults.Inject(faults.TunnelConnect); err != nil {
+			return "", err
+		}
 		url, err := tunnel.establish(ctx)
 		if err != nil {
 			return "", err
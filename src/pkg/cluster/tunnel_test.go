@@ -5,13 +5,18 @@ package cluster
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/test/testutil"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -37,6 +42,25 @@ func TestListConnections(t *testing.T) {
 	}
 	_, err := c.Clientset.CoreV1().Services(svc.ObjectMeta.Namespace).Create(context.Background(), &svc, metav1.CreateOptions{})
 	require.NoError(t, err)
+	createDeployedPackage(t, c, types.DeployedPackage{
+		Name: "package",
+		Data: v1alpha1.ZarfPackage{
+			Components: []v1alpha1.ZarfComponent{
+				{
+					Name: "deployed",
+					Connect: []v1alpha1.ZarfComponentConnect{
+						{Name: "podinfo", Description: "podinfo description", Namespace: "podinfo", Service: "podinfo", Port: 9898, URL: "/healthz"},
+						{Name: "connect name", Description: "shadowed", Namespace: "default", Service: "connect", Port: 80},
+					},
+				},
+				{
+					Name:    "not-deployed",
+					Connect: []v1alpha1.ZarfComponentConnect{{Name: "skipped", Namespace: "skipped", Service: "skipped", Port: 80}},
+				},
+			},
+		},
+		DeployedComponents: []types.DeployedComponent{{Name: "deployed"}},
+	})
 
 	connections, err := c.ListConnections(context.Background())
 	require.NoError(t, err)
@@ -45,10 +69,139 @@ func TestListConnections(t *testing.T) {
 			Description: "description",
 			URL:         "url",
 		},
+		"podinfo": types.ConnectString{
+			Description: "podinfo description",
+			URL:         "/healthz",
+		},
 	}
 	require.Equal(t, expectedConnections, connections)
 }
 
+func TestNewTargetTunnelInfoFromPackage(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(),
+	}
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "podinfo",
+			Name:      "podinfo",
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "podinfo"},
+			Ports: []corev1.ServicePort{
+				{Port: 80, TargetPort: intstr.FromString("http")},
+				{Port: 9797, TargetPort: intstr.FromInt32(9797)},
+				{Port: 9999},
+			},
+		},
+	}
+	_, err := c.Clientset.CoreV1().Services(svc.Namespace).Create(ctx, &svc, metav1.CreateOptions{})
+	require.NoError(t, err)
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "podinfo",
+			Name:      "podinfo",
+			Labels:    map[string]string{"app": "podinfo"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 9898}}}},
+		},
+	}
+	_, err = c.Clientset.CoreV1().Pods(pod.Namespace).Create(ctx, &pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+	createDeployedPackage(t, c, types.DeployedPackage{
+		Name: "podinfo",
+		Data: v1alpha1.ZarfPackage{
+			Components: []v1alpha1.ZarfComponent{
+				{
+					Name: "podinfo",
+					Connect: []v1alpha1.ZarfComponentConnect{
+						{Name: "podinfo", Namespace: "podinfo", Service: "podinfo", Port: 80, URL: "/healthz"},
+						{Name: "podinfo-metrics", Namespace: "podinfo", Service: "podinfo", Port: 9797},
+						{Name: "podinfo-grpc", Namespace: "podinfo", Service: "podinfo", Port: 9999},
+						{Name: "podinfo-missing", Namespace: "podinfo", Service: "podinfo", Port: 8080},
+					},
+				},
+			},
+		},
+		DeployedComponents: []types.DeployedComponent{{Name: "podinfo"}},
+	})
+
+	tests := []struct {
+		target       string
+		expectedPort int
+		expectedURL  string
+		expectedErr  string
+	}{
+		{
+			target:       "podinfo",
+			expectedPort: 9898,
+			expectedURL:  "/healthz",
+		},
+		{
+			target:       "podinfo-metrics",
+			expectedPort: 9797,
+		},
+		{
+			target:       "podinfo-grpc",
+			expectedPort: 9999,
+		},
+		{
+			target:      "podinfo-missing",
+			expectedErr: "problem looking for a zarf connect label in the cluster: service podinfo/podinfo does not have port 8080",
+		},
+		{
+			target:      "unknown",
+			expectedErr: "problem looking for a zarf connect label in the cluster: no matching services found for unknown",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.target, func(t *testing.T) {
+			t.Parallel()
+
+			zt, err := c.NewTargetTunnelInfo(ctx, tt.target)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			expected := TunnelInfo{
+				RemotePort:   tt.expectedPort,
+				Namespace:    "podinfo",
+				ResourceType: SvcResource,
+				ResourceName: "podinfo",
+				urlSuffix:    tt.expectedURL,
+			}
+			require.Equal(t, expected, zt)
+		})
+	}
+}
+
+func createDeployedPackage(t *testing.T, c *Cluster, deployedPackage types.DeployedPackage) {
+	t.Helper()
+
+	b, err := json.Marshal(deployedPackage)
+	require.NoError(t, err)
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.ZarfPackagePrefix + deployedPackage.Name,
+			Namespace: ZarfNamespaceName,
+			Labels: map[string]string{
+				ZarfPackageInfoLabel: deployedPackage.Name,
+			},
+		},
+		Data: map[string][]byte{
+			"data": b,
+		},
+	}
+	_, err = c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Create(context.Background(), &secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+}
+
 func TestServiceInfoFromNodePortURL(t *testing.T) {
 	t.Parallel()
 
@@ -148,6 +301,32 @@ func TestServiceInfoFromNodePortURL(t *testing.T) {
 			expectedIP:        "good-ip",
 			expectedPort:      3333,
 		},
+		{
+			name:        "found service on the IPv6 loopback address",
+			nodePortURL: "[::1]:31999",
+			services: []corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "zarf-docker-registry",
+						Namespace: "zarf",
+					},
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeNodePort,
+						Ports: []corev1.ServicePort{
+							{
+								NodePort: 31999,
+								Port:     5000,
+							},
+						},
+						ClusterIP: "fd00::10",
+					},
+				},
+			},
+			expectedNamespace: "zarf",
+			expectedName:      "zarf-docker-registry",
+			expectedIP:        "fd00::10",
+			expectedPort:      5000,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/aggregator"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/collector"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// WaitForObjectsReady waits for all of the objects to reach the current status computed by kstatus, which covers
+// workloads such as Deployments and StatefulSets, custom resource definitions and custom resources with status
+// conditions.
+//
+// If the context is done before the objects are ready, the error lists the objects that are not ready along with their
+// status and the reason kstatus gives for it.
+func WaitForObjectsReady(ctx context.Context, sw watcher.StatusWatcher, objs []object.ObjMetadata) error {
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	eventCh := sw.Watch(cancelCtx, objs, watcher.Options{})
+	statusCollector := collector.NewResourceStatusCollector(objs)
+	done := statusCollector.ListenWithObserver(eventCh, collector.ObserverFunc(
+		func(statusCollector *collector.ResourceStatusCollector, _ event.Event) {
+			rss := []*event.ResourceStatus{}
+			for _, rs := range statusCollector.ResourceStatuses {
+				if rs == nil {
+					continue
+				}
+				rss = append(rss, rs)
+			}
+			if aggregator.AggregateStatus(rss, status.CurrentStatus) == status.CurrentStatus {
+				cancel()
+			}
+		}),
+	)
+	<-done
+	if statusCollector.Error != nil {
+		return statusCollector.Error
+	}
+	// Only check the parent context, as the watch is cancelled once the objects are ready.
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	notReady := []string{}
+	for _, obj := range objs {
+		rs := statusCollector.ResourceStatuses[obj]
+		if rs != nil && rs.Status == status.CurrentStatus {
+			continue
+		}
+		name := obj.Name
+		if obj.Namespace != "" {
+			name = fmt.Sprintf("%s/%s", obj.Namespace, obj.Name)
+		}
+		line := fmt.Sprintf("%s %s is %s", obj.GroupKind.Kind, name, status.UnknownStatus)
+		if rs != nil {
+			line = fmt.Sprintf("%s %s is %s", obj.GroupKind.Kind, name, rs.Status)
+			if rs.Message != "" {
+				line = fmt.Sprintf("%s: %s", line, rs.Message)
+			}
+		}
+		notReady = append(notReady, line)
+	}
+	return fmt.Errorf("%w: %d of %d objects are not ready:\n  - %s", ctx.Err(), len(notReady), len(objs), strings.Join(notReady, "\n  - "))
+}
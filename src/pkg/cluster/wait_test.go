@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/polling/event"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
+	"sigs.k8s.io/cli-utils/pkg/object"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+// statusWatcher reports a fixed status for some of the watched objects and keeps the watch open until it is cancelled.
+type statusWatcher struct {
+	statuses map[object.ObjMetadata]*event.ResourceStatus
+}
+
+func (w statusWatcher) Watch(ctx context.Context, objs object.ObjMetadataSet, _ watcher.Options) <-chan event.Event {
+	eventCh := make(chan event.Event, len(objs))
+	for _, obj := range objs {
+		if rs, ok := w.statuses[obj]; ok {
+			eventCh <- event.Event{Type: event.ResourceUpdateEvent, Resource: rs}
+		}
+	}
+	go func() {
+		<-ctx.Done()
+		close(eventCh)
+	}()
+	return eventCh
+}
+
+func TestWaitForObjectsReady(t *testing.T) {
+	t.Parallel()
+
+	deployment := object.ObjMetadata{GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"}, Namespace: "podinfo", Name: "podinfo"}
+	crd := object.ObjMetadata{GroupKind: schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}, Name: "widgets.example.com"}
+	widget := object.ObjMetadata{GroupKind: schema.GroupKind{Group: "example.com", Kind: "Widget"}, Namespace: "podinfo", Name: "podinfo"}
+	objs := []object.ObjMetadata{deployment, crd, widget}
+
+	tests := []struct {
+		name        string
+		statuses    map[object.ObjMetadata]*event.ResourceStatus
+		expectedErr string
+	}{
+		{
+			name: "ready",
+			statuses: map[object.ObjMetadata]*event.ResourceStatus{
+				deployment: {Identifier: deployment, Status: status.CurrentStatus},
+				crd:        {Identifier: crd, Status: status.CurrentStatus},
+				widget:     {Identifier: widget, Status: status.CurrentStatus},
+			},
+		},
+		{
+			name: "not ready",
+			statuses: map[object.ObjMetadata]*event.ResourceStatus{
+				deployment: {Identifier: deployment, Status: status.InProgressStatus, Message: "Available: 0/1"},
+				crd:        {Identifier: crd, Status: status.CurrentStatus},
+			},
+			expectedErr: "context deadline exceeded: 2 of 3 objects are not ready:\n  - Deployment podinfo/podinfo is InProgress: Available: 0/1\n  - Widget podinfo/podinfo is Unknown",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(testutil.TestContext(t), time.Second)
+			defer cancel()
+			err := WaitForObjectsReady(ctx, statusWatcher{statuses: tt.statuses}, objs)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
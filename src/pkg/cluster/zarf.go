@@ -138,12 +138,37 @@ func (c *Cluster) PackageSecretNeedsWait(deployedPackage *types.DeployedPackage,
 }
 
 // RecordPackageDeploymentAndWait records the deployment of a package to the cluster and waits for any webhooks to complete.
-func (c *Cluster) RecordPackageDeploymentAndWait(ctx context.Context, pkg v1alpha1.ZarfPackage, components []types.DeployedComponent, generation int, component v1alpha1.ZarfComponent, skipWebhooks bool) (*types.DeployedPackage, error) {
-	deployedPackage, err := c.RecordPackageDeployment(ctx, pkg, components, generation)
+func (c *Cluster) RecordPackageDeploymentAndWait(ctx context.Context, pkg v1alpha1.ZarfPackage, components []types.DeployedComponent, generation int, variableOverlays []string, component v1alpha1.ZarfComponent, skipWebhooks bool) (*types.DeployedPackage, error) {
+	deployedPackage, err := c.RecordPackageDeployment(ctx, pkg, components, generation, variableOverlays)
 	if err != nil {
 		return nil, err
 	}
+	for _, deployedComponent := range components {
+		if deployedComponent.Name != component.Name {
+			continue
+		}
+		if err := c.RecordComponentEvent(ctx, pkg.Metadata.Name, deployedComponent); err != nil {
+			message.Debugf("Unable to record an event for component %s: %s", component.Name, err.Error())
+		}
+	}
+	return c.WaitForPackageWebhooks(ctx, deployedPackage, component, skipWebhooks)
+}
 
+// RecordComponentEvent records the status of a deployed component as an Event on the secret of its package.
+func (c *Cluster) RecordComponentEvent(ctx context.Context, packageName string, deployedComponent types.DeployedComponent) error {
+	eventType, reason := corev1.EventTypeNormal, "ComponentDeploying"
+	switch deployedComponent.Status {
+	case types.ComponentStatusSucceeded:
+		reason = "ComponentDeployed"
+	case types.ComponentStatusFailed:
+		eventType, reason = corev1.EventTypeWarning, "ComponentFailed"
+	}
+	msg := fmt.Sprintf("Component %s of package %s is %s", deployedComponent.Name, packageName, strings.ToLower(string(deployedComponent.Status)))
+	return c.RecordPackageEvent(ctx, packageName, eventType, reason, msg)
+}
+
+// WaitForPackageWebhooks waits for any running webhooks of the component of a recorded package to complete.
+func (c *Cluster) WaitForPackageWebhooks(ctx context.Context, deployedPackage *types.DeployedPackage, component v1alpha1.ZarfComponent, skipWebhooks bool) (*types.DeployedPackage, error) {
 	packageNeedsWait, waitSeconds, hookName := c.PackageSecretNeedsWait(deployedPackage, component, skipWebhooks)
 	// If no webhooks need to complete, we can return immediately.
 	if !packageNeedsWait {
@@ -159,8 +184,8 @@ func (c *Cluster) RecordPackageDeploymentAndWait(ctx context.Context, pkg v1alph
 	}
 	waitCtx, cancel := context.WithTimeout(ctx, waitDuration)
 	defer cancel()
-	deployedPackage, err = retry.DoWithData(func() (*types.DeployedPackage, error) {
-		deployedPackage, err = c.GetDeployedPackage(waitCtx, deployedPackage.Name)
+	deployedPackage, err := retry.DoWithData(func() (*types.DeployedPackage, error) {
+		deployedPackage, err := c.GetDeployedPackage(waitCtx, deployedPackage.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -177,8 +202,45 @@ func (c *Cluster) RecordPackageDeploymentAndWait(ctx context.Context, pkg v1alph
 	return deployedPackage, nil
 }
 
-// RecordPackageDeployment saves metadata about a package that has been deployed to the cluster.
-func (c *Cluster) RecordPackageDeployment(ctx context.Context, pkg v1alpha1.ZarfPackage, components []types.DeployedComponent, generation int) (*types.DeployedPackage, error) {
+// RecordPackageEvent records a Kubernetes Event on the secret of the given package that is annotated with the correlation
+// ID of the current operation, so that cluster activity can be lined up with the Zarf log file.
+func (c *Cluster) RecordPackageEvent(ctx context.Context, packageName, eventType, reason, msg string) error {
+	secretName := config.ZarfPackagePrefix + packageName
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: secretName + ".",
+			Namespace:    ZarfNamespaceName,
+			Labels: map[string]string{
+				ZarfManagedByLabel:   "zarf",
+				ZarfPackageInfoLabel: packageName,
+			},
+			Annotations: map[string]string{
+				CorrelationIDAnnotation: message.CorrelationID(),
+			},
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+			Name:       secretName,
+			Namespace:  ZarfNamespaceName,
+		},
+		Type:                eventType,
+		Reason:              reason,
+		Message:             fmt.Sprintf("%s (correlation ID %s)", msg, message.CorrelationID()),
+		Source:              corev1.EventSource{Component: "zarf"},
+		ReportingController: "zarf",
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+	_, err := c.Clientset.CoreV1().Events(ZarfNamespaceName).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
+
+// RecordPackageDeployment saves metadata about a package that has been deployed to the cluster, along with the names of
+// the variable overlays that were applied to it.
+func (c *Cluster) RecordPackageDeployment(ctx context.Context, pkg v1alpha1.ZarfPackage, components []types.DeployedComponent, generation int, variableOverlays []string) (*types.DeployedPackage, error) {
 	packageName := pkg.Metadata.Name
 
 	// Attempt to load information about webhooks for the package
@@ -209,8 +271,15 @@ func (c *Cluster) RecordPackageDeployment(ctx context.Context, pkg v1alpha1.Zarf
 		ConnectStrings:     connectStrings,
 		Generation:         generation,
 		ComponentWebhooks:  componentWebhooks,
+		VariableOverlays:   variableOverlays,
+		CorrelationID:      message.CorrelationID(),
 	}
+	return c.saveDeployedPackage(ctx, deployedPackage)
+}
 
+// saveDeployedPackage creates or updates the secret that records a deployed package.
+func (c *Cluster) saveDeployedPackage(ctx context.Context, deployedPackage *types.DeployedPackage) (*types.DeployedPackage, error) {
+	packageName := deployedPackage.Name
 	packageData, err := json.Marshal(deployedPackage)
 	if err != nil {
 		return nil, err
@@ -258,6 +327,20 @@ func (c *Cluster) RecordPackageDeployment(ctx context.Context, pkg v1alpha1.Zarf
 	return deployedPackage, nil
 }
 
+// RestoreDeployedPackage sets the secret of a package back to a previously deployed package, or deletes it if the
+// package was not deployed before.
+func (c *Cluster) RestoreDeployedPackage(ctx context.Context, packageName string, deployedPackage *types.DeployedPackage) error {
+	if deployedPackage == nil {
+		err := c.Clientset.CoreV1().Secrets(ZarfNamespaceName).Delete(ctx, config.ZarfPackagePrefix+packageName, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	_, err := c.saveDeployedPackage(ctx, deployedPackage)
+	return err
+}
+
 // EnableRegHPAScaleDown enables the HPA scale down for the Zarf Registry.
 func (c *Cluster) EnableRegHPAScaleDown(ctx context.Context) error {
 	hpa, err := c.Clientset.AutoscalingV2().HorizontalPodAutoscalers(ZarfNamespaceName).Get(ctx, "zarf-docker-registry", metav1.GetOptions{})
@@ -368,6 +451,23 @@ func (c *Cluster) UpdateInternalGitServerSecret(ctx context.Context, oldGitServe
 	return nil
 }
 
+// RestartDeployment triggers a rolling update of the deployment, in the same way as kubectl rollout restart.
+func (c *Cluster) RestartDeployment(ctx context.Context, namespace, name string) error {
+	deployment, err := c.Clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.Annotations["zarf.dev/restartedAt"] = time.Now().UTC().Format(time.RFC3339)
+	_, err = c.Clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // InternalGitServerExists checks if the Zarf internal git server exists in the cluster.
 func (c *Cluster) InternalGitServerExists(ctx context.Context) (bool, error) {
 	_, err := c.Clientset.CoreV1().Services(ZarfNamespaceName).Get(ctx, ZarfGitServerName, metav1.GetOptions{})
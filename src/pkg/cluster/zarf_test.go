@@ -13,11 +13,13 @@ import (
 	"github.com/stretchr/testify/require"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -253,6 +255,61 @@ func TestGetDeployedPackage(t *testing.T) {
 	require.ElementsMatch(t, packages, actualList)
 }
 
+func TestRecordPackageDeployment(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(),
+	}
+
+	pkg := v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Name: "podinfo"}}
+	component := v1alpha1.ZarfComponent{Name: "podinfo"}
+	components := []types.DeployedComponent{{Name: component.Name, Status: types.ComponentStatusFailed}}
+	deployedPackage, err := c.RecordPackageDeploymentAndWait(ctx, pkg, components, 1, []string{"edge-1.yaml"}, component, true)
+	require.NoError(t, err)
+	require.Equal(t, message.CorrelationID(), deployedPackage.CorrelationID)
+	require.Equal(t, []string{"edge-1.yaml"}, deployedPackage.VariableOverlays)
+
+	events, err := c.Clientset.CoreV1().Events(ZarfNamespaceName).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1)
+	event := events.Items[0]
+	require.Equal(t, corev1.EventTypeWarning, event.Type)
+	require.Equal(t, "ComponentFailed", event.Reason)
+	require.Equal(t, config.ZarfPackagePrefix+"podinfo", event.InvolvedObject.Name)
+	require.Equal(t, message.CorrelationID(), event.Annotations[CorrelationIDAnnotation])
+	require.Contains(t, event.Message, message.CorrelationID())
+}
+
+func TestRestoreDeployedPackage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := &Cluster{
+		Clientset: fake.NewSimpleClientset(),
+	}
+
+	pkg := v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Name: "podinfo"}}
+	previous, err := c.RecordPackageDeployment(ctx, pkg, []types.DeployedComponent{{Name: "podinfo"}}, 1, nil)
+	require.NoError(t, err)
+	_, err = c.RecordPackageDeployment(ctx, pkg, []types.DeployedComponent{{Name: "podinfo"}, {Name: "redis"}}, 2, nil)
+	require.NoError(t, err)
+
+	err = c.RestoreDeployedPackage(ctx, "podinfo", previous)
+	require.NoError(t, err)
+	deployedPackage, err := c.GetDeployedPackage(ctx, "podinfo")
+	require.NoError(t, err)
+	require.Equal(t, 1, deployedPackage.Generation)
+	require.Equal(t, []types.DeployedComponent{{Name: "podinfo"}}, deployedPackage.DeployedComponents)
+
+	err = c.RestoreDeployedPackage(ctx, "podinfo", nil)
+	require.NoError(t, err)
+	_, err = c.GetDeployedPackage(ctx, "podinfo")
+	require.True(t, kerrors.IsNotFound(err))
+	// Restoring a package that was not deployed is a no-op.
+	err = c.RestoreDeployedPackage(ctx, "podinfo", nil)
+	require.NoError(t, err)
+}
+
 func TestRegistryHPA(t *testing.T) {
 	ctx := context.Background()
 	cs := fake.NewSimpleClientset()
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package interactive contains functions for interacting with the user via STDIN.
+package interactive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/zarf-dev/zarf/src/types"
+	"golang.org/x/term"
+)
+
+var (
+	// ErrConfirmationRequired is returned when an operation must be confirmed but there is no terminal to prompt on.
+	ErrConfirmationRequired = errors.New("confirmation required: review the planned changes and run again with --confirm")
+	// ErrDeclined is returned when the user declines an operation.
+	ErrDeclined = errors.New("declined by the user")
+)
+
+// Plan is the change set of a destructive operation that is awaiting confirmation.
+type Plan struct {
+	// The operation that would be run, such as Deploy, Mirror or Remove.
+	Operation string `json:"operation"`
+	// The kind of the package, which tells an init apart from the deploy of a regular package.
+	Kind string `json:"kind,omitempty"`
+	// The name of the package.
+	Package string `json:"package,omitempty"`
+	// The version of the package.
+	Version string `json:"version,omitempty"`
+	// Where the package is read from.
+	Source string `json:"source,omitempty"`
+	// The components the operation would act on.
+	Components []PlanComponent `json:"components"`
+	// The variables the package would be deployed with, with sensitive values sanitized.
+	Variables map[string]string `json:"variables,omitempty"`
+	// The warnings flagged while reading the package.
+	Warnings []string `json:"warnings,omitempty"`
+	// The Zarf state that the operation would replace, if it changes the state of the cluster.
+	State *PlanState `json:"state,omitempty"`
+	// The credentials that the operation would change.
+	Credentials []PlanCredential `json:"credentials,omitempty"`
+	// The deployments that the operation would restart.
+	Restarts []PlanResource `json:"restarts,omitempty"`
+}
+
+// PlanState is the Zarf state of a cluster before and after an operation, with credentials and private keys sanitized.
+type PlanState struct {
+	// The state in the cluster, if the cluster has one.
+	Current *types.ZarfState `json:"current,omitempty"`
+	// The state that would replace it.
+	Incoming *types.ZarfState `json:"incoming"`
+}
+
+// PlanCredential is the change to the credentials of a single service, such as the registry or git server.
+type PlanCredential struct {
+	Service string `json:"service"`
+	// The credentials of the service that change, such as pushPassword.
+	Changed []string `json:"changed"`
+}
+
+// PlanComponent is the part of a plan that belongs to a single component.
+type PlanComponent struct {
+	Name           string         `json:"name"`
+	Charts         []PlanResource `json:"charts,omitempty"`
+	Manifests      []PlanResource `json:"manifests,omitempty"`
+	Images         []string       `json:"images,omitempty"`
+	Repos          []string       `json:"repos,omitempty"`
+	Files          []string       `json:"files,omitempty"`
+	DataInjections []string       `json:"dataInjections,omitempty"`
+}
+
+// PlanResource is a chart or manifest that would be installed, upgraded or removed.
+type PlanResource struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+// Confirmer asks for the confirmation of destructive operations.
+type Confirmer struct {
+	// Whether operations are confirmed without asking, as with --confirm.
+	AutoConfirm bool
+	// Whether there is a terminal to prompt on.
+	Interactive bool
+	// Where the plan is written when it cannot be confirmed by a prompt.
+	Out io.Writer
+}
+
+// NewConfirmer returns a Confirmer that prompts on STDIN when it is a terminal and otherwise writes plans to STDOUT.
+func NewConfirmer(autoConfirm bool) *Confirmer {
+	return &Confirmer{
+		AutoConfirm: autoConfirm,
+		Interactive: term.IsTerminal(int(os.Stdin.Fd())),
+		Out:         os.Stdout,
+	}
+}
+
+// Prompts returns whether the confirmer will show a prompt or confirm automatically, rather than write the plan.
+func (c *Confirmer) Prompts() bool {
+	return c.AutoConfirm || c.Interactive
+}
+
+// Confirm returns nil if the operation described by the plan is confirmed. Without a terminal to prompt on, the plan is
+// written as JSON so that external tooling can approve it and ErrConfirmationRequired is returned.
+func (c *Confirmer) Confirm(plan Plan, question string) error {
+	if c.AutoConfirm {
+		return nil
+	}
+	if !c.Interactive {
+		b, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to write the plan: %w", err)
+		}
+		if _, err := fmt.Fprintln(c.Out, string(b)); err != nil {
+			return fmt.Errorf("unable to write the plan: %w", err)
+		}
+		return ErrConfirmationRequired
+	}
+
+	prompt := &survey.Confirm{
+		Message: question,
+	}
+	var confirm bool
+	if err := survey.AskOne(prompt, &confirm); err != nil {
+		return err
+	}
+	if !confirm {
+		return ErrDeclined
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package interactive
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirm(t *testing.T) {
+	t.Parallel()
+
+	plan := Plan{
+		Operation: "Remove",
+		Kind:      "ZarfPackageConfig",
+		Package:   "podinfo",
+		Components: []PlanComponent{
+			{Name: "podinfo", Charts: []PlanResource{{Name: "podinfo", Namespace: "podinfo"}}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		confirmer   Confirmer
+		expectedErr error
+		writesPlan  bool
+	}{
+		{
+			name:      "auto confirmed",
+			confirmer: Confirmer{AutoConfirm: true},
+		},
+		{
+			name:        "without a terminal",
+			confirmer:   Confirmer{},
+			expectedErr: ErrConfirmationRequired,
+			writesPlan:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out := &bytes.Buffer{}
+			tt.confirmer.Out = out
+			err := tt.confirmer.Confirm(plan, "Remove this Zarf package?")
+			require.ErrorIs(t, err, tt.expectedErr)
+			if !tt.writesPlan {
+				require.Empty(t, out.String())
+				return
+			}
+			written := Plan{}
+			require.NoError(t, json.Unmarshal(out.Bytes(), &written))
+			require.Equal(t, plan, written)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package kms encrypts data keys with keys held in an external key management service.
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeySize is the size of the data keys that are encrypted with a KeyEncrypter.
+const KeySize = 32
+
+// KeyEncrypter encrypts and decrypts data keys with a key that does not leave its key management service.
+type KeyEncrypter interface {
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// Supported key URI schemes, matching the URIs of the keys used to sign packages.
+const (
+	AWSScheme        = "awskms://"
+	GCPScheme        = "gcpkms://"
+	AzureScheme      = "azurekms://"
+	HashiVaultScheme = "hashivault://"
+)
+
+// IsKeyURI returns whether the URI references a key in one of the supported key management services.
+func IsKeyURI(uri string) bool {
+	for _, scheme := range []string{AWSScheme, GCPScheme, AzureScheme, HashiVaultScheme} {
+		if strings.HasPrefix(uri, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// New returns a KeyEncrypter for the key with the given URI:
+//
+//   - awskms://[ENDPOINT]/KEY_ID for a key ID, ARN or alias in AWS KMS
+//   - gcpkms://projects/PROJECT/locations/LOCATION/keyRings/KEYRING/cryptoKeys/KEY in Google Cloud KMS
+//   - azurekms://VAULT_NAME.vault.azure.net/KEY for an RSA key in Azure Key Vault
+//   - hashivault://KEY for a key in the transit secrets engine of HashiCorp Vault
+//
+// Credentials are read from the environment in the same way as when signing packages with these keys.
+func New(ctx context.Context, uri string) (KeyEncrypter, error) {
+	switch {
+	case strings.HasPrefix(uri, AWSScheme):
+		return newAWSKey(ctx, strings.TrimPrefix(uri, AWSScheme))
+	case strings.HasPrefix(uri, GCPScheme):
+		return newGCPKey(strings.TrimPrefix(uri, GCPScheme))
+	case strings.HasPrefix(uri, AzureScheme):
+		return newAzureKey(strings.TrimPrefix(uri, AzureScheme))
+	case strings.HasPrefix(uri, HashiVaultScheme):
+		return newHashiVaultKey(strings.TrimPrefix(uri, HashiVaultScheme))
+	default:
+		return nil, fmt.Errorf("unsupported key URI %s", uri)
+	}
+}
+
+// GenerateDataKey returns a random data key.
+func GenerateDataKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Seal encrypts and authenticates the plaintext with AES-GCM under the data key, prefixing the ciphertext with its nonce.
+func Seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts and authenticates a ciphertext returned by Seal.
+func Open(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt, the data key may be wrong: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("data key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
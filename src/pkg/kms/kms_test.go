@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package kms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestSealOpen(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateDataKey()
+	require.NoError(t, err)
+	require.Len(t, key, KeySize)
+
+	ciphertext, err := Seal(key, []byte("hello world"))
+	require.NoError(t, err)
+	require.NotContains(t, string(ciphertext), "hello world")
+	plaintext, err := Open(key, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(plaintext))
+
+	otherKey, err := GenerateDataKey()
+	require.NoError(t, err)
+	_, err = Open(otherKey, ciphertext)
+	require.Error(t, err)
+	_, err = Open(key, ciphertext[:4])
+	require.EqualError(t, err, "ciphertext is too short")
+	_, err = Seal([]byte("short"), []byte("hello world"))
+	require.EqualError(t, err, "data key must be 32 bytes, got 5")
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		uri         string
+		expectedErr string
+	}{
+		{
+			name:        "unsupported scheme",
+			uri:         "file:///tmp/key",
+			expectedErr: "unsupported key URI file:///tmp/key",
+		},
+		{
+			name:        "aws without key",
+			uri:         "awskms://",
+			expectedErr: "invalid AWS KMS key reference , expected awskms://[ENDPOINT]/KEY_ID",
+		},
+		{
+			name:        "gcp without key",
+			uri:         "gcpkms://projects/zarf",
+			expectedErr: "invalid Google Cloud KMS key reference projects/zarf, expected gcpkms://projects/PROJECT/locations/LOCATION/keyRings/KEYRING/cryptoKeys/KEY",
+		},
+		{
+			name:        "azure without key",
+			uri:         "azurekms://zarf.vault.azure.net",
+			expectedErr: "invalid Azure Key Vault key reference zarf.vault.azure.net, expected azurekms://VAULT_NAME.vault.azure.net/KEY",
+		},
+		{
+			name:        "vault with path",
+			uri:         "hashivault://transit/zarf",
+			expectedErr: "invalid HashiCorp Vault key reference transit/zarf, expected hashivault://KEY",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.uri != "file:///tmp/key", IsKeyURI(tt.uri))
+			_, err := New(testutil.TestContext(t), tt.uri)
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
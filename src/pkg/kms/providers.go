@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	gcpkms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	awskms "github.com/aws/aws-sdk-go-v2/service/kms"
+	vault "github.com/hashicorp/vault/api"
+)
+
+// awsKey is a key in AWS KMS.
+type awsKey struct {
+	client *awskms.Client
+	keyID  string
+}
+
+// newAWSKey returns the key referenced by [ENDPOINT]/KEY_ID, using the default AWS credential chain.
+func newAWSKey(ctx context.Context, ref string) (*awsKey, error) {
+	endpoint, keyID, ok := strings.Cut(ref, "/")
+	if !ok || keyID == "" {
+		return nil, fmt.Errorf("invalid AWS KMS key reference %s, expected awskms://[ENDPOINT]/KEY_ID", ref)
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the AWS configuration: %w", err)
+	}
+	client := awskms.NewFromConfig(cfg, func(o *awskms.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String("https://" + endpoint)
+		}
+	})
+	return &awsKey{client: client, keyID: keyID}, nil
+}
+
+func (k *awsKey) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	out, err := k.client.Encrypt(ctx, &awskms.EncryptInput{KeyId: aws.String(k.keyID), Plaintext: plaintext})
+	if err != nil {
+		return nil, err
+	}
+	return out.CiphertextBlob, nil
+}
+
+func (k *awsKey) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	out, err := k.client.Decrypt(ctx, &awskms.DecryptInput{KeyId: aws.String(k.keyID), CiphertextBlob: ciphertext})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+// gcpKey is a symmetric key in Google Cloud KMS.
+type gcpKey struct {
+	name string
+}
+
+// newGCPKey returns the key with the given resource name, using the application default credentials.
+func newGCPKey(name string) (*gcpKey, error) {
+	if !strings.HasPrefix(name, "projects/") || !strings.Contains(name, "/cryptoKeys/") {
+		return nil, fmt.Errorf("invalid Google Cloud KMS key reference %s, expected gcpkms://projects/PROJECT/locations/LOCATION/keyRings/KEYRING/cryptoKeys/KEY", name)
+	}
+	return &gcpKey{name: name}, nil
+}
+
+func (k *gcpKey) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	client, err := gcpkms.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	resp, err := client.Encrypt(ctx, &kmspb.EncryptRequest{Name: k.name, Plaintext: plaintext})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Ciphertext, nil
+}
+
+func (k *gcpKey) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	client, err := gcpkms.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	// Decryption is by the key name as Cloud KMS picks the key version from the ciphertext
+	resp, err := client.Decrypt(ctx, &kmspb.DecryptRequest{Name: k.name, Ciphertext: ciphertext})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+// azureKey is an RSA key in Azure Key Vault.
+type azureKey struct {
+	client *azkeys.Client
+	name   string
+}
+
+// newAzureKey returns the key referenced by VAULT_HOST/KEY, using the default Azure credential chain.
+func newAzureKey(ref string) (*azureKey, error) {
+	host, name, ok := strings.Cut(ref, "/")
+	if !ok || host == "" || name == "" {
+		return nil, fmt.Errorf("invalid Azure Key Vault key reference %s, expected azurekms://VAULT_NAME.vault.azure.net/KEY", ref)
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	client, err := azkeys.NewClient("https://"+host, cred, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &azureKey{client: client, name: name}, nil
+}
+
+func (k *azureKey) params(value []byte) azkeys.KeyOperationParameters {
+	alg := azkeys.EncryptionAlgorithmRSAOAEP256
+	return azkeys.KeyOperationParameters{Algorithm: &alg, Value: value}
+}
+
+func (k *azureKey) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	// The latest version of the key is used, its ID is embedded in the result
+	resp, err := k.client.WrapKey(ctx, k.name, "", k.params(plaintext), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.KID == nil {
+		return nil, errors.New("the wrapped key does not include the ID of the key version")
+	}
+	return []byte(string(*resp.KID) + "\n" + base64.StdEncoding.EncodeToString(resp.Result)), nil
+}
+
+func (k *azureKey) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	kid, encoded, ok := strings.Cut(string(ciphertext), "\n")
+	if !ok {
+		return nil, errors.New("invalid Azure Key Vault wrapped key")
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	version := azkeys.ID(kid)
+	resp, err := k.client.UnwrapKey(ctx, k.name, version.Version(), k.params(wrapped), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// hashiVaultKey is a key in the transit secrets engine of HashiCorp Vault.
+type hashiVaultKey struct {
+	client *vault.Client
+	mount  string
+	name   string
+}
+
+// newHashiVaultKey returns the key with the given name, using the address and token in VAULT_ADDR and VAULT_TOKEN and
+// the transit engine mounted at TRANSIT_SECRET_ENGINE_PATH, which defaults to transit.
+func newHashiVaultKey(name string) (*hashiVaultKey, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid HashiCorp Vault key reference %s, expected hashivault://KEY", name)
+	}
+	client, err := vault.NewClient(vault.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	mount := os.Getenv("TRANSIT_SECRET_ENGINE_PATH")
+	if mount == "" {
+		mount = "transit"
+	}
+	return &hashiVaultKey{client: client, mount: strings.Trim(mount, "/"), name: name}, nil
+}
+
+// transit writes to an endpoint of the transit engine for the key and returns the given field of the response.
+func (k *hashiVaultKey) transit(ctx context.Context, op string, data map[string]interface{}, field string) (string, error) {
+	secret, err := k.client.Logical().WriteWithContext(ctx, fmt.Sprintf("%s/%s/%s", k.mount, op, k.name), data)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", fmt.Errorf("empty response from the transit %s endpoint", op)
+	}
+	value, ok := secret.Data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("the transit %s response does not include %s", op, field)
+	}
+	return value, nil
+}
+
+func (k *hashiVaultKey) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	ciphertext, err := k.transit(ctx, "encrypt", map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}, "ciphertext")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(ciphertext), nil
+}
+
+func (k *hashiVaultKey) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	plaintext, err := k.transit(ctx, "decrypt", map[string]interface{}{"ciphertext": string(ciphertext)}, "plaintext")
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(plaintext)
+}
@@ -25,6 +25,8 @@ type ComponentPaths struct {
 	Values         string
 	Repos          string
 	Manifests      string
+	Policies       string
+	PolicyGates    string
 	DataInjections string
 }
 
@@ -115,6 +117,12 @@ func (c *Components) Unarchive(component v1alpha1.ZarfComponent) error {
 	if len(component.Manifests) > 0 {
 		cs.Manifests = filepath.Join(cs.Base, ManifestsDir)
 	}
+	if len(component.Policies) > 0 {
+		cs.Policies = filepath.Join(cs.Base, PoliciesDir)
+	}
+	if len(component.PolicyGates) > 0 {
+		cs.PolicyGates = filepath.Join(cs.Base, PolicyGatesDir)
+	}
 	if len(component.DataInjections) > 0 {
 		cs.DataInjections = filepath.Join(cs.Base, DataInjectionsDir)
 	}
@@ -206,6 +214,20 @@ func (c *Components) Create(component v1alpha1.ZarfComponent) (*ComponentPaths,
 		}
 	}
 
+	if len(component.Policies) > 0 {
+		cp.Policies = filepath.Join(base, PoliciesDir)
+		if err := helpers.CreateDirectory(cp.Policies, helpers.ReadWriteExecuteUser); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(component.PolicyGates) > 0 {
+		cp.PolicyGates = filepath.Join(base, PolicyGatesDir)
+		if err := helpers.CreateDirectory(cp.PolicyGates, helpers.ReadWriteExecuteUser); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(component.DataInjections) > 0 {
 		cp.DataInjections = filepath.Join(base, DataInjectionsDir)
 		if err := helpers.CreateDirectory(cp.DataInjections, helpers.ReadWriteExecuteUser); err != nil {
@@ -13,19 +13,30 @@ const (
 	ChartsDir         = "charts"
 	ReposDir          = "repos"
 	ManifestsDir      = "manifests"
+	PoliciesDir       = "policies"
+	PolicyGatesDir    = "gates"
 	DataInjectionsDir = "data"
 	ValuesDir         = "values"
 
 	ZarfYAML  = "zarf.yaml"
+	ZarfLock  = "zarf-lock.yaml"
 	Signature = "zarf.yaml.sig"
+	Bundle    = "zarf.yaml.bundle"
 	Checksums = "checksums.txt"
 
+	Provenance = "provenance.json"
+
+	ZarfWorkspace = "zarf-workspace.yaml"
+
 	ImagesDir     = "images"
 	ComponentsDir = "components"
 
 	SBOMDir = "zarf-sbom"
 	SBOMTar = "sboms.tar"
 
+	DocsDir = "docs"
+	DocsTar = "docs.tar"
+
 	IndexJSON = "index.json"
 	OCILayout = "oci-layout"
 )
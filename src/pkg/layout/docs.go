@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package layout contains functions for interacting with Zarf's package layout on disk.
+package layout
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/mholt/archiver/v3"
+)
+
+// AddDocs copies the given documentation files and directories into the package as a single docs tarball.
+func (pp *PackagePaths) AddDocs(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	dir := filepath.Join(pp.Base, DocsDir)
+	for _, path := range paths {
+		dst := filepath.Join(dir, filepath.Base(path))
+		if !helpers.InvalidPath(dst) {
+			return fmt.Errorf("unable to add docs %q, more than one docs path is named %q", path, filepath.Base(path))
+		}
+		if err := helpers.CreatePathAndCopy(path, dst); err != nil {
+			return fmt.Errorf("unable to add docs %q: %w", path, err)
+		}
+	}
+	tb := filepath.Join(pp.Base, DocsTar)
+	if err := helpers.CreateReproducibleTarballFromDir(dir, "", tb); err != nil {
+		return err
+	}
+	pp.Docs = tb
+	return os.RemoveAll(dir)
+}
+
+// ExtractDocs extracts the package's docs into the given directory.
+func (pp *PackagePaths) ExtractDocs(dir string) error {
+	if pp.Docs == "" || helpers.InvalidPath(pp.Docs) {
+		return &fs.PathError{
+			Op:   "stat",
+			Path: pp.Docs,
+			Err:  fs.ErrNotExist,
+		}
+	}
+	return archiver.Unarchive(pp.Docs, dir)
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package layout contains functions for interacting with Zarf's package layout on disk.
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocs(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "README.md"), []byte("# Operating instructions"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "runbooks"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "runbooks", "upgrade.md"), []byte("# Upgrade"), 0o600))
+
+	pp := New(t.TempDir())
+	require.NoError(t, pp.AddDocs(nil))
+	require.Empty(t, pp.Docs)
+
+	require.NoError(t, pp.AddDocs([]string{filepath.Join(src, "README.md"), filepath.Join(src, "runbooks")}))
+	require.Equal(t, filepath.Join(pp.Base, DocsTar), pp.Docs)
+	require.NoDirExists(t, filepath.Join(pp.Base, DocsDir))
+	require.Contains(t, pp.Files(), DocsTar)
+
+	loaded := New(pp.Base)
+	loaded.SetFromPaths([]string{DocsTar})
+	require.Equal(t, pp.Docs, loaded.Docs)
+
+	out := t.TempDir()
+	require.NoError(t, loaded.ExtractDocs(out))
+	b, err := os.ReadFile(filepath.Join(out, "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "# Operating instructions", string(b))
+	b, err = os.ReadFile(filepath.Join(out, "runbooks", "upgrade.md"))
+	require.NoError(t, err)
+	require.Equal(t, "# Upgrade", string(b))
+
+	require.ErrorIs(t, New(t.TempDir()).ExtractDocs(out), os.ErrNotExist)
+
+	duplicate := New(t.TempDir())
+	err = duplicate.AddDocs([]string{filepath.Join(src, "README.md"), filepath.Join(src, "README.md")})
+	require.ErrorContains(t, err, "more than one docs path is named")
+}
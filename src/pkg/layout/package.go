@@ -5,6 +5,7 @@
 package layout
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +22,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/deprecated"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 // PackagePaths is the default package layout.
@@ -30,11 +32,20 @@ type PackagePaths struct {
 	Checksums string
 
 	Signature string
+	Bundle    string
+
+	Provenance string
+
+	// Docs is the path to the tarball of documentation that can be pulled without the rest of the package.
+	Docs string
 
 	Components Components
 	SBOMs      SBOMs
 	Images     Images
 
+	// Pushed maps the files of the package that were pushed to a registry and removed from disk to their sha256.
+	Pushed map[string]string
+
 	isLegacyLayout bool
 }
 
@@ -82,7 +93,7 @@ func (pp *PackagePaths) MigrateLegacy() error {
 	base := pp.Base
 
 	// legacy layout does not contain a checksums file, nor a signature
-	if helpers.InvalidPath(pp.Checksums) && pp.Signature == "" {
+	if helpers.InvalidPath(pp.Checksums) && pp.Signature == "" && pp.Bundle == "" {
 		if err := utils.ReadYaml(pp.ZarfYAML, &pkg); err != nil {
 			return err
 		}
@@ -164,14 +175,44 @@ func (pp *PackagePaths) IsLegacyLayout() bool {
 	return pp.isLegacyLayout
 }
 
-// SignPackage signs the zarf.yaml in a Zarf package.
-func (pp *PackagePaths) SignPackage(signingKeyPath, signingKeyPassword string, isInteractive bool) error {
-	if signingKeyPath == "" {
+// SignPackage signs the zarf.yaml in a Zarf package with either a key-pair or keyless.
+func (pp *PackagePaths) SignPackage(signingKeyPath, signingKeyPassword string, keyless types.ZarfKeylessSigningOptions, isInteractive bool) error {
+	if signingKeyPath != "" && keyless.Enabled {
+		return errors.New("a package can be signed with either a signing key or keyless, not both")
+	}
+	if signingKeyPath == "" && !keyless.Enabled {
 		return nil
 	}
 
 	pp.Signature = filepath.Join(pp.Base, Signature)
 
+	if keyless.Enabled {
+		pp.Bundle = filepath.Join(pp.Base, Bundle)
+		opts := utils.CosignKeylessOptions{
+			FulcioURL:        keyless.FulcioURL,
+			RekorURL:         keyless.RekorURL,
+			OIDCIssuer:       keyless.OIDCIssuer,
+			IDToken:          keyless.IdentityToken,
+			SkipConfirmation: !isInteractive,
+		}
+		if _, err := utils.CosignSignBlobKeyless(pp.ZarfYAML, pp.Signature, pp.Bundle, opts); err != nil {
+			return fmt.Errorf("unable to sign the package keyless: %w", err)
+		}
+		return nil
+	}
+
+	// A bundle left from a previous keyless signature would no longer match the signature
+	if pp.Bundle != "" {
+		if err := os.Remove(pp.Bundle); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		pp.Bundle = ""
+	}
+
+	if utils.IsKMSKeyRef(signingKeyPath) && signingKeyPassword != "" {
+		message.Warn("The signing key password is ignored when signing with a KMS or PKCS#11 key")
+	}
+
 	passwordFunc := func(_ bool) ([]byte, error) {
 		if signingKeyPassword != "" {
 			return []byte(signingKeyPassword), nil
@@ -208,6 +249,9 @@ func (pp *PackagePaths) GenerateChecksums() (string, error) {
 		}
 		checksumsData = append(checksumsData, fmt.Sprintf("%s %s", sum, rel))
 	}
+	for rel, sum := range pp.Pushed {
+		checksumsData = append(checksumsData, fmt.Sprintf("%s %s", sum, rel))
+	}
 	slices.Sort(checksumsData)
 
 	// Create the checksums file
@@ -219,8 +263,30 @@ func (pp *PackagePaths) GenerateChecksums() (string, error) {
 	return helpers.GetSHA256OfFile(pp.Checksums)
 }
 
-// ArchivePackage creates an archive for a Zarf package.
-func (pp *PackagePaths) ArchivePackage(destinationTarball string, maxPackageSizeMB int) error {
+// RemovePushed removes a file of the package from disk once it has been pushed to a registry, keeping the sha256 of its
+// contents so that it is still included in the package checksums.
+func (pp *PackagePaths) RemovePushed(path string, sha string) error {
+	rel, err := filepath.Rel(pp.Base, path)
+	if err != nil {
+		return err
+	}
+	if pp.Pushed == nil {
+		pp.Pushed = map[string]string{}
+	}
+	pp.Pushed[filepath.ToSlash(rel)] = sha
+
+	pp.Images.Blobs = slices.DeleteFunc(pp.Images.Blobs, func(blob string) bool { return blob == path })
+	for name, tarball := range pp.Components.Tarballs {
+		if tarball == path {
+			delete(pp.Components.Tarballs, name)
+		}
+	}
+	return os.Remove(path)
+}
+
+// ArchivePackage creates an archive for a Zarf package, encrypting it if an encryption secret is provided and splitting
+// it into parts of at most maxPartSize bytes if it is larger than that.
+func (pp *PackagePaths) ArchivePackage(destinationTarball string, maxPartSize int64, encryptionSecret []byte) error {
 	spinner := message.NewProgressSpinner("Writing %s to %s", pp.Base, destinationTarball)
 	defer spinner.Stop()
 
@@ -231,22 +297,31 @@ func (pp *PackagePaths) ArchivePackage(destinationTarball string, maxPackageSize
 	}
 	spinner.Updatef("Wrote %s to %s", pp.Base, destinationTarball)
 
+	// Encrypt the archive before it is split so that every part is encrypted
+	if encryptionSecret != nil {
+		spinner.Updatef("Encrypting %s", destinationTarball)
+		encrypted := destinationTarball + ".encrypting"
+		if err := utils.EncryptFile(destinationTarball, encrypted, encryptionSecret); err != nil {
+			return fmt.Errorf("unable to encrypt the package archive: %w", err)
+		}
+		if err := os.Rename(encrypted, destinationTarball); err != nil {
+			return fmt.Errorf("unable to encrypt the package archive: %w", err)
+		}
+	}
+
 	fi, err := os.Stat(destinationTarball)
 	if err != nil {
 		return fmt.Errorf("unable to read the package archive: %w", err)
 	}
 	spinner.Successf("Package saved to %q", destinationTarball)
 
-	// Convert Megabytes to bytes.
-	chunkSize := maxPackageSizeMB * 1000 * 1000
-
-	// If a chunk size was specified and the package is larger than the chunk size, split it into chunks.
-	if maxPackageSizeMB > 0 && fi.Size() > int64(chunkSize) {
-		if fi.Size()/int64(chunkSize) > 999 {
+	// If a part size was specified and the package is larger than the part size, split it into parts.
+	if maxPartSize > 0 && fi.Size() > maxPartSize {
+		if fi.Size()/maxPartSize > 999 {
 			return fmt.Errorf("unable to split the package archive into multiple files: must be less than 1,000 files")
 		}
-		message.Notef("Package is larger than %dMB, splitting into multiple files", maxPackageSizeMB)
-		err := splitFile(destinationTarball, chunkSize)
+		message.Notef("Package is larger than %s, splitting into multiple files", utils.ByteFormat(float64(maxPartSize), 2))
+		err := splitFile(destinationTarball, maxPartSize)
 		if err != nil {
 			return fmt.Errorf("unable to split the package archive into multiple files: %w", err)
 		}
@@ -290,10 +365,16 @@ func (pp *PackagePaths) SetFromPaths(paths []string) {
 			pp.ZarfYAML = filepath.Join(pp.Base, path)
 		case path == Signature:
 			pp.Signature = filepath.Join(pp.Base, path)
+		case path == Bundle:
+			pp.Bundle = filepath.Join(pp.Base, path)
 		case path == Checksums:
 			pp.Checksums = filepath.Join(pp.Base, path)
+		case path == Provenance:
+			pp.Provenance = filepath.Join(pp.Base, path)
 		case path == SBOMTar:
 			pp.SBOMs.Path = filepath.Join(pp.Base, path)
+		case path == DocsTar:
+			pp.Docs = filepath.Join(pp.Base, path)
 		case path == OCILayoutPath:
 			pp.Images.OCILayout = filepath.Join(pp.Base, path)
 		case path == IndexPath:
@@ -337,7 +418,9 @@ func (pp *PackagePaths) Files() map[string]string {
 
 	add(pp.ZarfYAML)
 	add(pp.Signature)
+	add(pp.Bundle)
 	add(pp.Checksums)
+	add(pp.Provenance)
 
 	add(pp.Images.OCILayout)
 	add(pp.Images.Index)
@@ -352,5 +435,7 @@ func (pp *PackagePaths) Files() map[string]string {
 	if pp.SBOMs.IsTarball() {
 		add(pp.SBOMs.Path)
 	}
+
+	add(pp.Docs)
 	return pathMap
 }
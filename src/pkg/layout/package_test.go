@@ -5,13 +5,16 @@
 package layout
 
 import (
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 func TestPackageFiles(t *testing.T) {
@@ -61,6 +64,22 @@ func TestPackageFiles(t *testing.T) {
 		require.Equal(t, expected, files)
 	})
 
+	t.Run("Verify Files() with keyless signature", func(t *testing.T) {
+		t.Parallel()
+
+		pp := New("test")
+		pp.SetFromPaths([]string{Signature, Bundle})
+
+		files := pp.Files()
+		expected := map[string]string{
+			"zarf.yaml":        normalizePath("test/zarf.yaml"),
+			"checksums.txt":    normalizePath("test/checksums.txt"),
+			"zarf.yaml.sig":    normalizePath("test/zarf.yaml.sig"),
+			"zarf.yaml.bundle": normalizePath("test/zarf.yaml.bundle"),
+		}
+		require.Equal(t, expected, files)
+	})
+
 	t.Run("Verify Files() with images", func(t *testing.T) {
 		t.Parallel()
 
@@ -165,6 +184,47 @@ func TestPackageFiles(t *testing.T) {
 	})
 }
 
+func TestRemovePushed(t *testing.T) {
+	t.Parallel()
+
+	pp := New(t.TempDir())
+	blob := filepath.Join(pp.Base, "images", "blobs", "sha256", strings.Repeat("1", 64))
+	tarball := filepath.Join(pp.Base, "components", "c1.tar")
+	for _, path := range []string{pp.ZarfYAML, blob, tarball} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(filepath.Base(path)), 0o600))
+	}
+	pp.SetFromPaths([]string{"zarf.yaml", "components/c1.tar", "images/blobs/sha256/" + strings.Repeat("1", 64)})
+	expected, err := pp.GenerateChecksums()
+	require.NoError(t, err)
+
+	for _, path := range []string{blob, tarball} {
+		sha, err := helpers.GetSHA256OfFile(path)
+		require.NoError(t, err)
+		require.NoError(t, pp.RemovePushed(path, sha))
+		require.NoFileExists(t, path)
+	}
+	require.Empty(t, pp.Images.Blobs)
+	require.Empty(t, pp.Components.Tarballs)
+
+	// The checksums of the package are unchanged by the files being removed once pushed.
+	actual, err := pp.GenerateChecksums()
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestSignPackageKeyAndKeyless(t *testing.T) {
+	t.Parallel()
+
+	pp := New(t.TempDir())
+	err := pp.SignPackage("cosign.key", "", types.ZarfKeylessSigningOptions{Enabled: true}, false)
+	require.EqualError(t, err, "a package can be signed with either a signing key or keyless, not both")
+	require.Empty(t, pp.Signature)
+
+	require.NoError(t, pp.SignPackage("", "", types.ZarfKeylessSigningOptions{}, false))
+	require.Empty(t, pp.Signature)
+}
+
 // normalizePath ensures that the filepaths being generated are normalized to the host OS.
 func normalizePath(path string) string {
 	if runtime.GOOS != "windows" {
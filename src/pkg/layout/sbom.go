@@ -12,12 +12,16 @@ import (
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/mholt/archiver/v3"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 )
 
 // ComponentSBOM contains paths for a component's SBOM.
 type ComponentSBOM struct {
 	Files     []string
+	Repos     []string
 	Component *ComponentPaths
+	Rules     v1alpha1.ZarfComponentSBOM
 }
 
 // SBOMs contains paths for SBOMs.
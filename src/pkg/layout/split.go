@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -18,7 +19,7 @@ import (
 )
 
 // splitFile will split the file into chunks and remove the original file.
-func splitFile(srcPath string, chunkSize int) error {
+func splitFile(srcPath string, chunkSize int64) error {
 	srcFile, err := os.Open(srcPath)
 	if err != nil {
 		return err
@@ -35,6 +36,7 @@ func splitFile(srcPath string, chunkSize int) error {
 
 	hash := sha256.New()
 	fileCount := 0
+	parts := []types.ZarfSplitPackagePart{}
 	for {
 		path := fmt.Sprintf("%s.part%03d", srcPath, fileCount+1)
 		dstFile, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, helpers.ReadAllWriteUser)
@@ -43,9 +45,9 @@ func splitFile(srcPath string, chunkSize int) error {
 		}
 		defer dstFile.Close()
 
-		written, copyErr := io.CopyN(dstFile, srcFile, int64(chunkSize))
+		written, copyErr := io.CopyN(dstFile, srcFile, chunkSize)
 		if copyErr != nil && !errors.Is(copyErr, io.EOF) {
-			return err
+			return copyErr
 		}
 		progressBar.Add(int(written))
 		title := fmt.Sprintf("[%d/%d] MB bytes written", progressBar.GetCurrent()/1000/1000, fi.Size()/1000/1000)
@@ -55,7 +57,8 @@ func splitFile(srcPath string, chunkSize int) error {
 		if err != nil {
 			return err
 		}
-		_, err = io.Copy(hash, dstFile)
+		partHash := sha256.New()
+		_, err = io.Copy(io.MultiWriter(hash, partHash), dstFile)
 		if err != nil {
 			return err
 		}
@@ -73,6 +76,11 @@ func splitFile(srcPath string, chunkSize int) error {
 			break
 		}
 
+		parts = append(parts, types.ZarfSplitPackagePart{
+			Name:      filepath.Base(path),
+			Sha256Sum: fmt.Sprintf("%x", partHash.Sum(nil)),
+			Bytes:     written,
+		})
 		fileCount++
 		if errors.Is(copyErr, io.EOF) {
 			break
@@ -94,6 +102,7 @@ func splitFile(srcPath string, chunkSize int) error {
 		Count:     fileCount,
 		Bytes:     fi.Size(),
 		Sha256Sum: fmt.Sprintf("%x", hash.Sum(nil)),
+		Parts:     parts,
 	}
 	b, err := json.Marshal(data)
 	if err != nil {
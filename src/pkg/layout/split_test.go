@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -20,7 +21,7 @@ func TestSplitFile(t *testing.T) {
 	tests := []struct {
 		name                 string
 		fileSize             int
-		chunkSize            int
+		chunkSize            int64
 		expectedFileSize     int64
 		expectedLastFileSize int64
 		expectedFileCount    int
@@ -91,6 +92,14 @@ func TestSplitFile(t *testing.T) {
 			require.Equal(t, tt.expectedFileCount, data.Count)
 			require.Equal(t, int64(tt.fileSize), data.Bytes)
 			require.Equal(t, tt.expectedSha256Sum, data.Sha256Sum)
+			require.Len(t, data.Parts, tt.expectedFileCount)
+			for i, part := range data.Parts {
+				require.Equal(t, fmt.Sprintf("%s.part%03d", name, i+1), part.Name)
+				require.NoError(t, helpers.SHAsMatch(filepath.Join(dir, part.Name), part.Sha256Sum))
+				fi, err := os.Stat(filepath.Join(dir, part.Name))
+				require.NoError(t, err)
+				require.Equal(t, fi.Size(), part.Bytes)
+			}
 		})
 	}
 }
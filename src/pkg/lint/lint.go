@@ -6,16 +6,20 @@ package lint
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 
 	goyaml "github.com/goccy/go-yaml"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/packager/sumdb"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/composer"
+	"github.com/zarf-dev/zarf/src/pkg/packager/workspace"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
@@ -79,31 +83,83 @@ func Validate(ctx context.Context, baseDir, flavor string, setVariables map[stri
 	}
 }
 
+// ValidateWorkspace lints every package in the workspace in the given directory, returning the lint errors of the
+// packages with findings.
+func ValidateWorkspace(ctx context.Context, baseDir, flavor string, setVariables map[string]string) ([]*LintError, error) {
+	ws, err := workspace.Read(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	lintErrs := []*LintError{}
+	for _, dir := range ws.PackageDirs() {
+		err := Validate(ctx, dir, flavor, ws.MergeVariables(setVariables))
+		var lintErr *LintError
+		if errors.As(err, &lintErr) {
+			lintErrs = append(lintErrs, lintErr)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to lint workspace package %s: %w", dir, err)
+		}
+	}
+	return lintErrs, nil
+}
+
 func lintComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor string, setVariables map[string]string) ([]PackageFinding, error) {
 	findings := []PackageFinding{}
+	flavors, err := pkg.ResolveFlavor(flavor)
+	if err != nil {
+		return nil, err
+	}
+	// Remote files recorded in the checksum database can suggest the shasum to pin them with.
+	sums, err := sumdb.Load(sumdb.Path())
+	if err != nil {
+		return nil, err
+	}
+	overridden := composer.OverriddenComponents(pkg.Components, flavors)
 	for i, component := range pkg.Components {
+		if overridden[i] {
+			continue
+		}
 		arch := config.GetArch(pkg.Metadata.Architecture)
-		if !composer.CompatibleComponent(component, arch, flavor) {
+		// lint the components of every architecture in a multi-architecture package
+		if slices.Contains(pkg.Metadata.Architectures, component.Only.Cluster.Architecture) {
+			arch = component.Only.Cluster.Architecture
+		}
+		if err := composer.ValidateExpression(component.Only.Expression); err != nil {
+			return nil, fmt.Errorf("component %q: %w", component.Name, err)
+		}
+		if !composer.CompatibleComponent(component, arch, flavors, setVariables) {
 			continue
 		}
-		chain, err := composer.NewImportChain(ctx, component, i, pkg.Metadata.Name, arch, flavor)
+		expanded, err := composer.ExpandComponent(ctx, component, arch, flavors, setVariables)
 		if err != nil {
 			return nil, err
 		}
-		node := chain.Head()
-		for node != nil {
-			component := node.ZarfComponent
-			compFindings, err := templateZarfObj(&component, setVariables)
+		for j, component := range expanded {
+			chain, err := composer.NewImportChain(ctx, component, i, pkg.Metadata.Name, arch, flavors, setVariables)
 			if err != nil {
 				return nil, err
 			}
-			compFindings = append(compFindings, CheckComponentValues(component, node.Index())...)
-			for i := range compFindings {
-				compFindings[i].PackagePathOverride = node.ImportLocation()
-				compFindings[i].PackageNameOverride = node.OriginalPackageName()
+			node := chain.Head()
+			// a component importing every component of a package is only linted once
+			if j > 0 {
+				node = node.Next()
+			}
+			for node != nil {
+				component := node.ZarfComponent
+				compFindings, err := templateZarfObj(&component, setVariables)
+				if err != nil {
+					return nil, err
+				}
+				compFindings = append(compFindings, CheckComponentValues(component, node.Index(), sums)...)
+				for i := range compFindings {
+					compFindings[i].PackagePathOverride = node.ImportLocation()
+					compFindings[i].PackageNameOverride = node.OriginalPackageName()
+				}
+				findings = append(findings, compFindings...)
+				node = node.Next()
 			}
-			findings = append(findings, compFindings...)
-			node = node.Next()
 		}
 	}
 	return findings, nil
@@ -120,14 +176,18 @@ func templateZarfObj(zarfObj any, setVariables map[string]string) ([]PackageFind
 		}
 
 		var unSetTemplates bool
-		for key := range yamlTemplates {
+		for key, defaultValue := range yamlTemplates {
 			if deprecated {
 				findings = append(findings, PackageFinding{
 					Description: fmt.Sprintf(lang.PkgValidateTemplateDeprecation, key, key, key),
 					Severity:    SevWarn,
 				})
 			}
-			if _, present := setVariables[key]; !present {
+			_, present := setVariables[key]
+			if !present && defaultValue != "" {
+				// A template with a default is optional, the default function fills it in when it is left empty.
+				templateMap[fmt.Sprintf("%s%s###", templatePrefix, key)] = ""
+			} else if !present {
 				unSetTemplates = true
 			}
 		}
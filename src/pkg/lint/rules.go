@@ -10,6 +10,7 @@ import (
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/sumdb"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 )
 
@@ -40,12 +41,15 @@ func isPinnedRepo(repo string) bool {
 	return (strings.Contains(repo, "@"))
 }
 
-// CheckComponentValues runs lint rules validating values on component keys, should be run after templating
-func CheckComponentValues(c v1alpha1.ZarfComponent, i int) []PackageFinding {
+// CheckComponentValues runs lint rules validating values on component keys, should be run after templating.
+// The checksum database is optional and is used to suggest the shasum of unpinned remote files.
+func CheckComponentValues(c v1alpha1.ZarfComponent, i int, sums *sumdb.DB) []PackageFinding {
 	var findings []PackageFinding
 	findings = append(findings, checkForUnpinnedRepos(c, i)...)
 	findings = append(findings, checkForUnpinnedImages(c, i)...)
-	findings = append(findings, checkForUnpinnedFiles(c, i)...)
+	findings = append(findings, checkForUnpinnedArtifacts(c, i)...)
+	findings = append(findings, checkForUnpinnedFiles(c, i, sums)...)
+	findings = append(findings, checkForAbsoluteActionDirs(c, i)...)
 	return findings
 }
 
@@ -91,14 +95,90 @@ func checkForUnpinnedImages(c v1alpha1.ZarfComponent, i int) []PackageFinding {
 	return findings
 }
 
-func checkForUnpinnedFiles(c v1alpha1.ZarfComponent, i int) []PackageFinding {
+func checkForUnpinnedArtifacts(c v1alpha1.ZarfComponent, i int) []PackageFinding {
+	var findings []PackageFinding
+	for j, artifact := range c.Artifacts {
+		artifactYqPath := fmt.Sprintf(".components.[%d].artifacts.[%d]", i, j)
+		pinnedArtifact, err := isPinnedImage(artifact)
+		if err != nil {
+			findings = append(findings, PackageFinding{
+				YqPath:      artifactYqPath,
+				Description: "Failed to parse artifact reference",
+				Item:        artifact,
+				Severity:    SevWarn,
+			})
+			continue
+		}
+		if !pinnedArtifact {
+			findings = append(findings, PackageFinding{
+				YqPath:      artifactYqPath,
+				Description: "Artifact not pinned with digest",
+				Item:        artifact,
+				Severity:    SevWarn,
+			})
+		}
+	}
+	return findings
+}
+
+// checkForAbsoluteActionDirs warns about actions that run in an absolute dir, as the dir may not exist on the machine
+// that the package is created or deployed on.
+func checkForAbsoluteActionDirs(c v1alpha1.ZarfComponent, i int) []PackageFinding {
+	var findings []PackageFinding
+	check := func(yqPath, dir string) {
+		if isAbsolutePath(dir) {
+			findings = append(findings, PackageFinding{
+				YqPath:      yqPath,
+				Description: "Action runs in an absolute dir",
+				Item:        dir,
+				Severity:    SevWarn,
+			})
+		}
+	}
+	actionSets := []struct {
+		name string
+		set  v1alpha1.ZarfComponentActionSet
+	}{
+		{"onCreate", c.Actions.OnCreate},
+		{"onDeploy", c.Actions.OnDeploy},
+		{"onRemove", c.Actions.OnRemove},
+	}
+	for _, as := range actionSets {
+		setYqPath := fmt.Sprintf(".components.[%d].actions.%s", i, as.name)
+		check(setYqPath+".defaults.dir", as.set.Defaults.Dir)
+		actionLists := []struct {
+			name    string
+			actions []v1alpha1.ZarfComponentAction
+		}{
+			{"before", as.set.Before},
+			{"after", as.set.After},
+			{"onSuccess", as.set.OnSuccess},
+			{"onFailure", as.set.OnFailure},
+		}
+		for _, al := range actionLists {
+			for j, action := range al.actions {
+				if action.Dir != nil {
+					check(fmt.Sprintf("%s.%s.[%d].dir", setYqPath, al.name, j), *action.Dir)
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func checkForUnpinnedFiles(c v1alpha1.ZarfComponent, i int, sums *sumdb.DB) []PackageFinding {
 	var findings []PackageFinding
 	for j, file := range c.Files {
 		fileYqPath := fmt.Sprintf(".components.[%d].files.[%d]", i, j)
 		if file.Shasum == "" && helpers.IsURL(file.Source) {
+			description := "No shasum for remote file"
+			// The shasum of an extracted file is of the extracted path rather than the downloaded archive.
+			if sum, ok := sums.Lookup(file.Source); ok && file.ExtractPath == "" {
+				description = fmt.Sprintf("%s, recorded shasum: %s", description, sum)
+			}
 			findings = append(findings, PackageFinding{
 				YqPath:      fileYqPath,
-				Description: "No shasum for remote file",
+				Description: description,
 				Item:        file.Source,
 				Severity:    SevWarn,
 			})
@@ -6,10 +6,13 @@ package lint
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/sumdb"
 )
 
 func TestUnpinnedRepo(t *testing.T) {
@@ -62,6 +65,58 @@ func TestUnpinnedImageWarning(t *testing.T) {
 	require.Equal(t, expected, findings)
 }
 
+func TestUnpinnedArtifactWarning(t *testing.T) {
+	t.Parallel()
+	unpinnedArtifact := "ghcr.io/stefanprodan/manifests/podinfo:6.4.0"
+	component := v1alpha1.ZarfComponent{Artifacts: []string{
+		unpinnedArtifact,
+		"ghcr.io/stefanprodan/charts/podinfo:6.4.0@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79",
+	}}
+	findings := checkForUnpinnedArtifacts(component, 0)
+	expected := []PackageFinding{
+		{
+			Item:        unpinnedArtifact,
+			Description: "Artifact not pinned with digest",
+			Severity:    SevWarn,
+			YqPath:      ".components.[0].artifacts.[0]",
+		},
+	}
+	require.Equal(t, expected, findings)
+}
+
+func TestAbsoluteActionDirWarning(t *testing.T) {
+	t.Parallel()
+	absoluteDir := "/opt/scripts"
+	relativeDir := "scripts"
+	component := v1alpha1.ZarfComponent{
+		Actions: v1alpha1.ZarfComponentActions{
+			OnCreate: v1alpha1.ZarfComponentActionSet{
+				Defaults: v1alpha1.ZarfComponentActionDefaults{Dir: `C:\temp`},
+			},
+			OnDeploy: v1alpha1.ZarfComponentActionSet{
+				Before: []v1alpha1.ZarfComponentAction{{Cmd: "ls", Dir: &relativeDir}},
+				After:  []v1alpha1.ZarfComponentAction{{Cmd: "ls"}, {Cmd: "./register.sh", Dir: &absoluteDir}},
+			},
+		},
+	}
+	findings := checkForAbsoluteActionDirs(component, 1)
+	expected := []PackageFinding{
+		{
+			Item:        `C:\temp`,
+			Description: "Action runs in an absolute dir",
+			Severity:    SevWarn,
+			YqPath:      ".components.[1].actions.onCreate.defaults.dir",
+		},
+		{
+			Item:        absoluteDir,
+			Description: "Action runs in an absolute dir",
+			Severity:    SevWarn,
+			YqPath:      ".components.[1].actions.onDeploy.after.[1].dir",
+		},
+	}
+	require.Equal(t, expected, findings)
+}
+
 func TestUnpinnnedFileWarning(t *testing.T) {
 	t.Parallel()
 	fileURL := "http://example.com/file.zip"
@@ -79,7 +134,7 @@ func TestUnpinnnedFileWarning(t *testing.T) {
 		},
 	}
 	component := v1alpha1.ZarfComponent{Files: zarfFiles}
-	findings := checkForUnpinnedFiles(component, 0)
+	findings := checkForUnpinnedFiles(component, 0, nil)
 	expected := []PackageFinding{
 		{
 			Item:        fileURL,
@@ -92,6 +147,39 @@ func TestUnpinnnedFileWarning(t *testing.T) {
 	require.Len(t, findings, 1)
 }
 
+func TestUnpinnedFileRecordedShasum(t *testing.T) {
+	t.Parallel()
+	fileURL := "http://example.com/file.zip"
+	path := filepath.Join(t.TempDir(), "file.zip")
+	require.NoError(t, os.WriteFile(path, []byte("file"), 0o600))
+	sums, err := sumdb.Load(filepath.Join(t.TempDir(), sumdb.FileName))
+	require.NoError(t, err)
+	require.NoError(t, sums.Verify(fileURL, path))
+	sum, ok := sums.Lookup(fileURL)
+	require.True(t, ok)
+
+	component := v1alpha1.ZarfComponent{Files: []v1alpha1.ZarfFile{
+		{Source: fileURL},
+		{Source: fileURL, ExtractPath: "file"},
+	}}
+	findings := checkForUnpinnedFiles(component, 0, sums)
+	expected := []PackageFinding{
+		{
+			Item:        fileURL,
+			Description: "No shasum for remote file, recorded shasum: " + sum,
+			Severity:    SevWarn,
+			YqPath:      ".components.[0].files.[0]",
+		},
+		{
+			Item:        fileURL,
+			Description: "No shasum for remote file",
+			Severity:    SevWarn,
+			YqPath:      ".components.[0].files.[1]",
+		},
+	}
+	require.Equal(t, expected, findings)
+}
+
 func TestIsImagePinned(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
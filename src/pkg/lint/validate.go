@@ -8,9 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
 
@@ -21,6 +26,17 @@ var (
 	// Define allowed OS, an empty string means it is allowed on all operating systems
 	// same as enums on ZarfComponentOnlyTarget
 	supportedOS = []string{"linux", "darwin", "windows", ""}
+	// isAbsolutePath is a regex for absolute unix, windows drive, and UNC paths regardless of the current OS.
+	isAbsolutePath = regexp.MustCompile(`^([/\\]|[A-Za-z]:)`).MatchString
+	// same as enums on ZarfPolicy
+	supportedPolicyEngines = []v1alpha1.PolicyEngine{v1alpha1.PolicyEngineKyverno, v1alpha1.PolicyEngineGatekeeper}
+	// same as enums on ZarfPolicyGate
+	supportedPolicyGateEngines = []v1alpha1.PolicyGateEngine{v1alpha1.PolicyGateEngineRego, v1alpha1.PolicyGateEngineCEL}
+	// same as enums on ZarfChartHooks
+	supportedHookEvents   = []string{"pre-install", "post-install", "pre-upgrade", "post-upgrade", "pre-delete", "post-delete", "pre-rollback", "post-rollback", "test"}
+	convertibleHookEvents = []string{"pre-install", "post-install", "pre-upgrade", "post-upgrade"}
+	// same as enums on ZarfComponentReadiness
+	supportedReadinessProtocols = []string{"tcp", "http", "https"}
 )
 
 // SupportedOS returns the supported operating systems.
@@ -42,13 +58,17 @@ const (
 const (
 	PkgValidateErrInitNoYOLO              = "sorry, you can't YOLO an init package"
 	PkgValidateErrConstant                = "invalid package constant: %w"
-	PkgValidateErrYOLONoOCI               = "OCI images not allowed in YOLO"
+	PkgValidateErrPackageActions          = "invalid package actions: %w"
+	PkgValidateErrYOLONoOCI               = "OCI images and artifacts not allowed in YOLO"
 	PkgValidateErrYOLONoGit               = "git repos not allowed in YOLO"
 	PkgValidateErrYOLONoArch              = "cluster architecture not allowed in YOLO"
 	PkgValidateErrYOLONoDistro            = "cluster distros not allowed in YOLO"
 	PkgValidateErrComponentNameNotUnique  = "component name %q is not unique"
 	PkgValidateErrComponentReqDefault     = "component %q cannot be both required and default"
 	PkgValidateErrComponentReqGrouped     = "component %q cannot be both required and grouped"
+	PkgValidateErrComponentReqEntitlement = "component %q cannot be both required and gated by an entitlement"
+	PkgValidateErrEntitlementNoKey        = "component %q requires an entitlement but the package has no metadata.entitlementKey"
+	PkgValidateErrComponentTimeout        = "component %q timeout %q must be a positive duration"
 	PkgValidateErrChartNameNotUnique      = "chart name %q is not unique"
 	PkgValidateErrChart                   = "invalid chart definition: %w"
 	PkgValidateErrManifestNameNotUnique   = "manifest name %q is not unique"
@@ -58,13 +78,50 @@ const (
 	PkgValidateErrAction                  = "invalid action: %w"
 	PkgValidateErrActionCmdWait           = "action %q cannot be both a command and wait action"
 	PkgValidateErrActionClusterNetwork    = "a single wait action must contain only one of cluster or network"
+	PkgValidateErrActionEnv               = "env %q must be in the form KEY=VALUE with a valid variable name"
+	PkgValidateErrActionNegative          = "%s cannot be negative"
+	PkgValidateErrActionRetriesTimeout    = "maxTotalSeconds (%d) must be greater than or equal to maxRetries (%d)"
+	PkgValidateErrActionShell             = "shell %q is not supported on %s, must be one of %v"
+	PkgValidateErrActionRunIn             = "runIn %q must be an image reference of the form image:tag"
+	PkgValidateErrActionRunInWait         = "wait actions cannot set runIn"
 	PkgValidateErrChartName               = "chart %q exceed the maximum length of %d characters"
 	PkgValidateErrChartNamespaceMissing   = "chart %q must include a namespace"
 	PkgValidateErrChartURLOrPath          = "chart %q must have either a url or localPath"
 	PkgValidateErrChartVersion            = "chart %q must include a chart version"
+	PkgValidateErrChartHookEvent          = "chart %q hooks.%s event %q is not supported, must be one of %v"
+	PkgValidateErrChartHookSkipConvert    = "chart %q cannot both skip and convert %q hooks"
+	PkgValidateErrChartHookTimeout        = "chart %q hooks.timeout %q must be a positive duration"
+	PkgValidateErrChartTimeout            = "chart %q timeout %q must be a positive duration"
 	PkgValidateErrManifestFileOrKustomize = "manifest %q must have at least one file or kustomization"
 	PkgValidateErrManifestNameLength      = "manifest %q exceed the maximum length of %d characters"
+	PkgValidateErrManifestTimeout         = "manifest %q timeout %q must be a positive duration"
+	PkgValidateErrPolicyNameNotUnique     = "policy name %q is not unique"
+	PkgValidateErrPolicyManifestName      = "policy %q is deployed as manifest %q which is already a manifest of the component"
+	PkgValidateErrPolicy                  = "invalid policy definition: %w"
+	PkgValidateErrPolicyEngine            = "policy %q engine %q is not supported, must be one of %v"
+	PkgValidateErrPolicyFiles             = "policy %q must have at least one file"
+	PkgValidateErrPolicyNameLength        = "policy %q exceed the maximum length of %d characters"
+	PkgValidateErrPolicyGateNameNotUnique = "policy gate name %q is not unique"
+	PkgValidateErrPolicyGate              = "invalid policy gate definition: %w"
+	PkgValidateErrPolicyGateEngine        = "policy gate %q engine %q is not supported, must be one of %v"
+	PkgValidateErrPolicyGateFiles         = "policy gate %q must have at least one file"
+	PkgValidateErrPolicyGateName          = "policy gate name %q must not be empty or contain path separators"
+	PkgValidateErrPolicyGateNameLength    = "policy gate %q exceed the maximum length of %d characters"
+	PkgValidateErrArtifactRuntime         = "component %q artifact %q must be pulled from a registry"
+	PkgValidateErrSBOMExcludePath         = "component %q sbom exclude path %q is not a valid glob pattern"
 	PkgValidateErrVariable                = "invalid package variable: %w"
+	PkgValidateErrReadinessNameNotUnique  = "component %q readiness probe name %q is not unique"
+	PkgValidateErrReadinessProtocol       = "component %q readiness probe %q protocol %q is not supported, must be one of %v"
+	PkgValidateErrReadinessAddress        = "component %q readiness probe %q must include an address"
+	PkgValidateErrReadinessCode           = "component %q readiness probe %q can only expect a code with http or https"
+	PkgValidateErrReadiness               = "component %q readiness probe %q: %w"
+	PkgValidateErrConnectNameNotUnique    = "component %q connect name %q is not unique in the package"
+	PkgValidateErrConnectName             = "component %q connect name %q must be all lowercase and contain no special characters except '-' and cannot start with a '-'"
+	PkgValidateErrConnectService          = "component %q connect %q must include a namespace and a service"
+	PkgValidateErrConnectPort             = "component %q connect %q port %d must be between 1 and 65535"
+	PkgValidateErrDependsOnSelf           = "component %q cannot depend on itself"
+	PkgValidateErrDependsOnMissing        = "component %q depends on component %q which is not in the package"
+	PkgValidateErrDependsOnOrder          = "component %q depends on component %q which must come before it in the package"
 )
 
 // ValidatePackage runs all validation checks on the package.
@@ -78,12 +135,16 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 			err = errors.Join(err, fmt.Errorf(PkgValidateErrConstant, varErr))
 		}
 	}
+	if actionsErr := validateActions(pkg.Actions); actionsErr != nil {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrPackageActions, actionsErr))
+	}
 	uniqueComponentNames := make(map[string]bool)
+	uniqueConnectNames := make(map[string]bool)
 	groupDefault := make(map[string]string)
 	groupedComponents := make(map[string][]string)
 	if pkg.Metadata.YOLO {
 		for _, component := range pkg.Components {
-			if len(component.Images) > 0 {
+			if len(component.ImagesAndArtifacts()) > 0 {
 				err = errors.Join(err, errors.New(PkgValidateErrYOLONoOCI))
 			}
 			if len(component.Repos) > 0 {
@@ -110,6 +171,23 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 			if component.DeprecatedGroup != "" {
 				err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentReqGrouped, component.Name))
 			}
+			if component.Entitlement != "" {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentReqEntitlement, component.Name))
+			}
+		}
+		if component.Entitlement != "" && pkg.Metadata.EntitlementKey == "" {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrEntitlementNoKey, component.Name))
+		}
+		if component.Timeout != "" && !isPositiveDuration(component.Timeout) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentTimeout, component.Name, component.Timeout))
+		}
+		for _, artifact := range component.Artifacts {
+			// Only images can be loaded from a container runtime or an image tarball.
+			refInfo, refErr := transform.ParseImageRef(artifact)
+			isTarball := strings.HasSuffix(artifact, ".tar") || strings.HasSuffix(artifact, ".tar.gz") || strings.HasSuffix(artifact, ".tgz")
+			if (refErr == nil && refInfo.Runtime != "") || isTarball {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrArtifactRuntime, component.Name, artifact))
+			}
 		}
 		uniqueChartNames := make(map[string]bool)
 		for _, chart := range component.Charts {
@@ -133,6 +211,50 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 				err = errors.Join(err, fmt.Errorf(PkgValidateErrManifest, manifestErr))
 			}
 		}
+		uniquePolicyNames := make(map[string]bool)
+		for _, policy := range component.Policies {
+			// ensure policy name is unique
+			if _, ok := uniquePolicyNames[policy.Name]; ok {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyNameNotUnique, policy.Name))
+			}
+			uniquePolicyNames[policy.Name] = true
+			// Policy bundles are deployed as a manifest named after the bundle, which must not replace a manifest of the component.
+			if manifestName := fmt.Sprintf("policy-%s", policy.Name); uniqueManifestNames[manifestName] {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyManifestName, policy.Name, manifestName))
+			}
+			if policyErr := validatePolicy(policy); policyErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicy, policyErr))
+			}
+		}
+		uniquePolicyGateNames := make(map[string]bool)
+		for _, policyGate := range component.PolicyGates {
+			// ensure policy gate name is unique
+			if _, ok := uniquePolicyGateNames[policyGate.Name]; ok {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyGateNameNotUnique, policyGate.Name))
+			}
+			uniquePolicyGateNames[policyGate.Name] = true
+			if policyGateErr := validatePolicyGate(policyGate); policyGateErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyGate, policyGateErr))
+			}
+		}
+		uniqueReadinessNames := make(map[string]bool)
+		for _, probe := range component.Readiness {
+			if uniqueReadinessNames[probe.Name] {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrReadinessNameNotUnique, component.Name, probe.Name))
+			}
+			uniqueReadinessNames[probe.Name] = true
+			err = errors.Join(err, validateReadiness(component.Name, probe))
+		}
+		for _, target := range component.Connect {
+			if uniqueConnectNames[target.Name] {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrConnectNameNotUnique, component.Name, target.Name))
+			}
+			uniqueConnectNames[target.Name] = true
+			err = errors.Join(err, validateConnect(component.Name, target))
+		}
+		if sbomErr := validateSBOM(component.Name, component.SBOM); sbomErr != nil {
+			err = errors.Join(err, sbomErr)
+		}
 		if actionsErr := validateActions(component.Actions); actionsErr != nil {
 			err = errors.Join(err, fmt.Errorf("%q: %w", component.Name, actionsErr))
 		}
@@ -152,10 +274,39 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 			err = errors.Join(err, fmt.Errorf(PkgValidateErrGroupOneComponent, groupKey, componentNames[0]))
 		}
 	}
+	if dependsOnErr := validateDependsOn(pkg.Components); dependsOnErr != nil {
+		err = errors.Join(err, dependsOnErr)
+	}
 	return err
 }
 
-// validateActions validates the actions of a component.
+// validateDependsOn validates that the dependencies of the components name components that come before them in the
+// package, so that deploying the components in order also honors their dependencies and there are no cycles.
+func validateDependsOn(components []v1alpha1.ZarfComponent) error {
+	var err error
+	index := map[string]int{}
+	for i, component := range components {
+		if _, ok := index[component.Name]; !ok {
+			index[component.Name] = i
+		}
+	}
+	for i, component := range components {
+		for _, dep := range component.DependsOn {
+			depIndex, ok := index[dep]
+			switch {
+			case dep == component.Name:
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrDependsOnSelf, component.Name))
+			case !ok:
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrDependsOnMissing, component.Name, dep))
+			case depIndex > i:
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrDependsOnOrder, component.Name, dep))
+			}
+		}
+	}
+	return err
+}
+
+// validateActions validates the actions of a component or package.
 func validateActions(a v1alpha1.ZarfComponentActions) error {
 	var err error
 
@@ -193,6 +344,9 @@ func hasSetVariables(as v1alpha1.ZarfComponentActionSet) bool {
 // validateActionSet runs all validation checks on component action sets.
 func validateActionSet(as v1alpha1.ZarfComponentActionSet) error {
 	var err error
+	if defaultsErr := validateActionDefaults(as.Defaults); defaultsErr != nil {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrAction, defaultsErr))
+	}
 	validate := func(actions []v1alpha1.ZarfComponentAction) {
 		for _, action := range actions {
 			if actionErr := validateAction(action); actionErr != nil {
@@ -229,6 +383,130 @@ func validateAction(action v1alpha1.ZarfComponentAction) error {
 		}
 	}
 
+	err = errors.Join(err, validateActionEnv(action.Env))
+
+	if action.Shell != nil {
+		err = errors.Join(err, validateActionShell(*action.Shell))
+	}
+
+	if action.RunIn != nil {
+		if action.Wait != nil && *action.RunIn != "" {
+			err = errors.Join(err, errors.New(PkgValidateErrActionRunInWait))
+		}
+		err = errors.Join(err, validateActionRunIn(*action.RunIn))
+	}
+
+	maxTotalSeconds := 0
+	if action.MaxTotalSeconds != nil {
+		maxTotalSeconds = *action.MaxTotalSeconds
+	}
+	maxRetries := 0
+	if action.MaxRetries != nil {
+		maxRetries = *action.MaxRetries
+	}
+	err = errors.Join(err, validateActionLimits(maxTotalSeconds, maxRetries))
+
+	return err
+}
+
+// validateActionDefaults runs all validation checks on the defaults of an action set.
+func validateActionDefaults(defaults v1alpha1.ZarfComponentActionDefaults) error {
+	var err error
+	err = errors.Join(err, validateActionEnv(defaults.Env))
+	err = errors.Join(err, validateActionShell(defaults.Shell))
+	err = errors.Join(err, validateActionRunIn(defaults.RunIn))
+	err = errors.Join(err, validateActionLimits(defaults.MaxTotalSeconds, defaults.MaxRetries))
+	return err
+}
+
+// validateActionEnv ensures every env entry is KEY=VALUE shaped with a valid variable name.
+func validateActionEnv(env []string) error {
+	var err error
+	for _, e := range env {
+		key, _, ok := strings.Cut(e, "=")
+		if !ok || !exec.IsEnvVarName(key) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrActionEnv, e))
+		}
+	}
+	return err
+}
+
+// validateActionRunIn ensures the image an action runs in is pulled from a registry rather than a local runtime.
+func validateActionRunIn(runIn string) error {
+	if runIn == "" {
+		return nil
+	}
+	refInfo, err := transform.ParseImageRef(runIn)
+	if err != nil || refInfo.Runtime != "" {
+		return fmt.Errorf(PkgValidateErrActionRunIn, runIn)
+	}
+	return nil
+}
+
+// validateActionShell ensures each OS specific shell preference is one Zarf can run commands in.
+func validateActionShell(shell v1alpha1.Shell) error {
+	var err error
+	check := func(goos, name string) {
+		if name == "" {
+			return
+		}
+		if !exec.IsSupportedShell(goos, name) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrActionShell, name, goos, exec.SupportedShells(goos)))
+		}
+	}
+	check("windows", shell.Windows)
+	check("linux", shell.Linux)
+	check("darwin", shell.Darwin)
+	return err
+}
+
+// validateActionLimits ensures the timeout and retry settings of an action are sane.
+func validateActionLimits(maxTotalSeconds, maxRetries int) error {
+	var err error
+	if maxTotalSeconds < 0 {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrActionNegative, "maxTotalSeconds"))
+	}
+	if maxRetries < 0 {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrActionNegative, "maxRetries"))
+	}
+	// Each retry needs at least a second of the total timeout budget to have any chance of running.
+	if maxTotalSeconds > 0 && maxTotalSeconds < maxRetries {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrActionRetriesTimeout, maxTotalSeconds, maxRetries))
+	}
+	return err
+}
+
+// validateReadiness runs all validation checks on a readiness probe of a component.
+func validateReadiness(componentName string, probe v1alpha1.ZarfComponentReadiness) error {
+	var err error
+	protocol := strings.ToLower(probe.Protocol)
+	if !slices.Contains(supportedReadinessProtocols, protocol) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrReadinessProtocol, componentName, probe.Name, probe.Protocol, supportedReadinessProtocols))
+	}
+	if probe.Address == "" {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrReadinessAddress, componentName, probe.Name))
+	}
+	if probe.Code != 0 && protocol == "tcp" {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrReadinessCode, componentName, probe.Name))
+	}
+	if limitsErr := validateActionLimits(probe.MaxTotalSeconds, probe.MaxRetries); limitsErr != nil {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrReadiness, componentName, probe.Name, limitsErr))
+	}
+	return err
+}
+
+// validateConnect runs all validation checks on a connect target of a component.
+func validateConnect(componentName string, target v1alpha1.ZarfComponentConnect) error {
+	var err error
+	if !IsLowercaseNumberHyphenNoStartHyphen(target.Name) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrConnectName, componentName, target.Name))
+	}
+	if target.Namespace == "" || target.Service == "" {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrConnectService, componentName, target.Name))
+	}
+	if target.Port < 1 || target.Port > 65535 {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrConnectPort, componentName, target.Name, target.Port))
+	}
 	return err
 }
 
@@ -283,6 +561,26 @@ func validateChart(chart v1alpha1.ZarfChart) error {
 		err = errors.Join(err, nameErr)
 	}
 
+	for _, event := range chart.Hooks.Skip {
+		if !slices.Contains(supportedHookEvents, event) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrChartHookEvent, chart.Name, "skip", event, supportedHookEvents))
+		}
+	}
+	for _, event := range chart.Hooks.Convert {
+		if !slices.Contains(convertibleHookEvents, event) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrChartHookEvent, chart.Name, "convert", event, convertibleHookEvents))
+		}
+		if slices.Contains(chart.Hooks.Skip, event) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrChartHookSkipConvert, chart.Name, event))
+		}
+	}
+	if chart.Hooks.Timeout != "" && !isPositiveDuration(chart.Hooks.Timeout) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrChartHookTimeout, chart.Name, chart.Hooks.Timeout))
+	}
+	if chart.Timeout != "" && !isPositiveDuration(chart.Timeout) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrChartTimeout, chart.Name, chart.Timeout))
+	}
+
 	return err
 }
 
@@ -298,5 +596,67 @@ func validateManifest(manifest v1alpha1.ZarfManifest) error {
 		err = errors.Join(err, fmt.Errorf(PkgValidateErrManifestFileOrKustomize, manifest.Name))
 	}
 
+	if manifest.Timeout != "" && !isPositiveDuration(manifest.Timeout) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrManifestTimeout, manifest.Name, manifest.Timeout))
+	}
+
+	return err
+}
+
+func validatePolicy(policy v1alpha1.ZarfPolicy) error {
+	var err error
+
+	if len(policy.Name) > ZarfMaxChartNameLength {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyNameLength, policy.Name, ZarfMaxChartNameLength))
+	}
+
+	if !slices.Contains(supportedPolicyEngines, policy.Engine) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyEngine, policy.Name, policy.Engine, supportedPolicyEngines))
+	}
+
+	if len(policy.Files) < 1 {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyFiles, policy.Name))
+	}
+
 	return err
 }
+
+func validatePolicyGate(policyGate v1alpha1.ZarfPolicyGate) error {
+	var err error
+
+	// The name of a gate is part of the names of its files in the package.
+	if policyGate.Name == "" || strings.ContainsAny(policyGate.Name, `/\`) || policyGate.Name == "." || policyGate.Name == ".." {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyGateName, policyGate.Name))
+	}
+
+	if len(policyGate.Name) > ZarfMaxChartNameLength {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyGateNameLength, policyGate.Name, ZarfMaxChartNameLength))
+	}
+
+	if !slices.Contains(supportedPolicyGateEngines, policyGate.Engine) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyGateEngine, policyGate.Name, policyGate.Engine, supportedPolicyGateEngines))
+	}
+
+	if len(policyGate.Files) < 1 {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrPolicyGateFiles, policyGate.Name))
+	}
+
+	return err
+}
+
+// validateSBOM validates the SBOM rules of a component.
+func validateSBOM(componentName string, sbom v1alpha1.ZarfComponentSBOM) error {
+	var err error
+	for _, pattern := range sbom.ExcludePaths {
+		if !doublestar.ValidatePattern(pattern) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrSBOMExcludePath, componentName, pattern))
+		}
+	}
+	return err
+}
+
+// isPositiveDuration returns if the value is a duration greater than zero (i.e. 15m).
+func isPositiveDuration(value string) bool {
+	d, err := time.ParseDuration(value)
+	return err == nil && d > 0
+}
@@ -5,6 +5,7 @@
 package lint
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -12,10 +13,12 @@ import (
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
 )
 
 func TestZarfPackageValidate(t *testing.T) {
 	t.Parallel()
+	absoluteDir := "/opt"
 	tests := []struct {
 		name         string
 		pkg          v1alpha1.ZarfPackage
@@ -98,6 +101,123 @@ func TestZarfPackageValidate(t *testing.T) {
 				fmt.Sprintf(PkgValidateErrGroupMultipleDefaults, "multi-default", "multi-default", "multi-default-2"),
 			},
 		},
+		{
+			name: "policy replaces a manifest",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "policy-manifest",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "policies",
+						Manifests: []v1alpha1.ZarfManifest{
+							{Name: "policy-labels", Files: []string{"labels.yaml"}},
+						},
+						Policies: []v1alpha1.ZarfPolicy{
+							{Name: "labels", Engine: v1alpha1.PolicyEngineKyverno, Files: []string{"policy.yaml"}},
+						},
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrPolicyManifestName, "labels", "policy-labels"),
+			},
+		},
+		{
+			name: "invalid entitlement",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "invalid-entitlement",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:        "required-premium",
+						Required:    helpers.BoolPtr(true),
+						Entitlement: "premium",
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrComponentReqEntitlement, "required-premium"),
+				fmt.Sprintf(PkgValidateErrEntitlementNoKey, "required-premium"),
+			},
+		},
+		{
+			name: "invalid timeout",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "invalid-timeout",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:    "valid-timeout",
+						Timeout: "15m",
+					},
+					{
+						Name:    "invalid-timeout",
+						Timeout: "15",
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrComponentTimeout, "invalid-timeout", "15"),
+			},
+		},
+		{
+			name: "invalid artifacts",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "invalid-artifacts",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "artifacts",
+						Artifacts: []string{
+							"ghcr.io/stefanprodan/manifests/podinfo:6.4.0",
+							"docker-daemon:podinfo:local",
+							"artifact.tar",
+						},
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrArtifactRuntime, "artifacts", "docker-daemon:podinfo:local"),
+				fmt.Sprintf(PkgValidateErrArtifactRuntime, "artifacts", "artifact.tar"),
+			},
+		},
+		{
+			name: "invalid package actions",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "invalid-package-actions",
+				},
+				Actions: v1alpha1.ZarfComponentActions{
+					OnCreate: v1alpha1.ZarfComponentActionSet{
+						Before: []v1alpha1.ZarfComponentAction{
+							{Cmd: "echo license", SetVariables: []v1alpha1.Variable{{Name: "LICENSE"}}},
+						},
+					},
+					OnDeploy: v1alpha1.ZarfComponentActionSet{
+						After: []v1alpha1.ZarfComponentAction{
+							{Cmd: "./register.sh", Dir: &absoluteDir},
+						},
+					},
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "component1",
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Errorf(PkgValidateErrPackageActions, errors.New("cannot contain setVariables outside of onDeploy in actions")).Error(),
+			},
+		},
 		{
 			name: "invalid yolo",
 			pkg: v1alpha1.ZarfPackage{
@@ -168,6 +288,16 @@ func TestValidateManifest(t *testing.T) {
 			manifest:     v1alpha1.ZarfManifest{Name: "nothing-there"},
 			expectedErrs: []string{fmt.Sprintf(PkgValidateErrManifestFileOrKustomize, "nothing-there")},
 		},
+		{
+			name:         "valid timeout",
+			manifest:     v1alpha1.ZarfManifest{Name: "valid", Files: []string{"a-file"}, Timeout: "10m"},
+			expectedErrs: nil,
+		},
+		{
+			name:         "invalid timeout",
+			manifest:     v1alpha1.ZarfManifest{Name: "invalid", Files: []string{"a-file"}, Timeout: "0s"},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrManifestTimeout, "invalid", "0s")},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -184,6 +314,271 @@ func TestValidateManifest(t *testing.T) {
 	}
 }
 
+func TestValidatePolicy(t *testing.T) {
+	t.Parallel()
+	longName := strings.Repeat("a", ZarfMaxChartNameLength+1)
+	tests := []struct {
+		policy       v1alpha1.ZarfPolicy
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name:         "valid",
+			policy:       v1alpha1.ZarfPolicy{Name: "valid", Engine: v1alpha1.PolicyEngineKyverno, Files: []string{"a-file"}},
+			expectedErrs: nil,
+		},
+		{
+			name:         "long name",
+			policy:       v1alpha1.ZarfPolicy{Name: longName, Engine: v1alpha1.PolicyEngineGatekeeper, Files: []string{"a-file"}},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrPolicyNameLength, longName, ZarfMaxChartNameLength)},
+		},
+		{
+			name:         "unsupported engine",
+			policy:       v1alpha1.ZarfPolicy{Name: "opa", Engine: "opa", Files: []string{"a-file"}},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrPolicyEngine, "opa", "opa", supportedPolicyEngines)},
+		},
+		{
+			name:         "no files",
+			policy:       v1alpha1.ZarfPolicy{Name: "nothing-there", Engine: v1alpha1.PolicyEngineKyverno},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrPolicyFiles, "nothing-there")},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validatePolicy(tt.policy)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
+func TestValidatePolicyGate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		policyGate   v1alpha1.ZarfPolicyGate
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name:         "valid",
+			policyGate:   v1alpha1.ZarfPolicyGate{Name: "valid", Engine: v1alpha1.PolicyGateEngineRego, Files: []string{"policy.rego"}},
+			expectedErrs: nil,
+		},
+		{
+			name:       "unsupported engine and no files",
+			policyGate: v1alpha1.ZarfPolicyGate{Name: "invalid", Engine: "opa"},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrPolicyGateEngine, "invalid", "opa", supportedPolicyGateEngines),
+				fmt.Sprintf(PkgValidateErrPolicyGateFiles, "invalid"),
+			},
+		},
+		{
+			name:         "empty name",
+			policyGate:   v1alpha1.ZarfPolicyGate{Engine: v1alpha1.PolicyGateEngineRego, Files: []string{"policy.rego"}},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrPolicyGateName, "")},
+		},
+		{
+			name:         "path separator in name",
+			policyGate:   v1alpha1.ZarfPolicyGate{Name: "../gates", Engine: v1alpha1.PolicyGateEngineRego, Files: []string{"policy.rego"}},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrPolicyGateName, "../gates")},
+		},
+		{
+			name:         "long name",
+			policyGate:   v1alpha1.ZarfPolicyGate{Name: strings.Repeat("a", ZarfMaxChartNameLength+1), Engine: v1alpha1.PolicyGateEngineCEL, Files: []string{"policy.yaml"}},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrPolicyGateNameLength, strings.Repeat("a", ZarfMaxChartNameLength+1), ZarfMaxChartNameLength)},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validatePolicyGate(tt.policyGate)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
+func TestValidateReadiness(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		probe        v1alpha1.ZarfComponentReadiness
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name:         "valid http",
+			probe:        v1alpha1.ZarfComponentReadiness{Name: "api", Protocol: "HTTP", Address: "podinfo.podinfo.svc.cluster.local:9898/readyz", Code: 204, MaxTotalSeconds: 60, MaxRetries: 10},
+			expectedErrs: nil,
+		},
+		{
+			name:         "valid tcp",
+			probe:        v1alpha1.ZarfComponentReadiness{Name: "db", Protocol: "tcp", Address: "postgres.db.svc.cluster.local:5432"},
+			expectedErrs: nil,
+		},
+		{
+			name:  "invalid",
+			probe: v1alpha1.ZarfComponentReadiness{Name: "db", Protocol: "udp", Code: 200, MaxRetries: -1},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrReadinessProtocol, "component", "db", "udp", supportedReadinessProtocols),
+				fmt.Sprintf(PkgValidateErrReadinessAddress, "component", "db"),
+				fmt.Errorf(PkgValidateErrReadiness, "component", "db", fmt.Errorf(PkgValidateErrActionNegative, "maxRetries")).Error(),
+			},
+		},
+		{
+			name:         "tcp code",
+			probe:        v1alpha1.ZarfComponentReadiness{Name: "db", Protocol: "tcp", Address: "postgres.db.svc.cluster.local:5432", Code: 200},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrReadinessCode, "component", "db")},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateReadiness("component", tt.probe)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
+func TestValidateConnect(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		target       v1alpha1.ZarfComponentConnect
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name:         "valid",
+			target:       v1alpha1.ZarfComponentConnect{Name: "podinfo", Namespace: "podinfo", Service: "podinfo", Port: 9898, URL: "/healthz"},
+			expectedErrs: nil,
+		},
+		{
+			name:   "invalid",
+			target: v1alpha1.ZarfComponentConnect{Name: "-Podinfo", Service: "podinfo", Port: 70000},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrConnectName, "component", "-Podinfo"),
+				fmt.Sprintf(PkgValidateErrConnectService, "component", "-Podinfo"),
+				fmt.Sprintf(PkgValidateErrConnectPort, "component", "-Podinfo", 70000),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateConnect("component", tt.target)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
+func TestValidateDependsOn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		components   []v1alpha1.ZarfComponent
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name: "valid",
+			components: []v1alpha1.ZarfComponent{
+				{Name: "crds"},
+				{Name: "database", DependsOn: []string{"crds"}},
+				{Name: "app", DependsOn: []string{"database", "crds"}},
+			},
+			expectedErrs: nil,
+		},
+		{
+			name: "invalid",
+			components: []v1alpha1.ZarfComponent{
+				{Name: "app", DependsOn: []string{"app", "missing"}},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrDependsOnSelf, "app"),
+				fmt.Sprintf(PkgValidateErrDependsOnMissing, "app", "missing"),
+			},
+		},
+		{
+			name: "order",
+			components: []v1alpha1.ZarfComponent{
+				{Name: "crds"},
+				{Name: "app", DependsOn: []string{"crds", "database"}},
+				{Name: "database", DependsOn: []string{"app"}},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrDependsOnOrder, "app", "database"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateDependsOn(tt.components)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
+func TestValidateSBOM(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		sbom         v1alpha1.ZarfComponentSBOM
+		expectedErrs []string
+		name         string
+	}{
+		{
+			name:         "valid",
+			sbom:         v1alpha1.ZarfComponentSBOM{ExcludePaths: []string{"/opt/classified/**", "files/0/*.jar"}, StripLicenses: true},
+			expectedErrs: nil,
+		},
+		{
+			name:         "invalid pattern",
+			sbom:         v1alpha1.ZarfComponentSBOM{ExcludePaths: []string{"/opt/[classified"}},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrSBOMExcludePath, "component", "/opt/[classified")},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateSBOM("component", tt.sbom)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			errs := strings.Split(err.Error(), "\n")
+			require.ElementsMatch(t, errs, tt.expectedErrs)
+		})
+	}
+}
+
 func TestValidateReleaseName(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -301,6 +696,35 @@ func TestValidateChart(t *testing.T) {
 			chart:        v1alpha1.ZarfChart{Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0"},
 			expectedErrs: []string{errChartReleaseNameEmpty},
 		},
+		{
+			name: "valid hooks",
+			chart: v1alpha1.ZarfChart{Name: "chart4", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0",
+				Hooks: v1alpha1.ZarfChartHooks{Skip: []string{"pre-delete", "test"}, Convert: []string{"post-install"}, Timeout: "5m"}},
+			expectedErrs: nil,
+		},
+		{
+			name: "invalid hooks",
+			chart: v1alpha1.ZarfChart{Name: "chart5", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0",
+				Hooks: v1alpha1.ZarfChartHooks{Skip: []string{"pre-install", "on-delete"}, Convert: []string{"pre-install", "pre-delete"}, Timeout: "-1m"}},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrChartHookEvent, "chart5", "skip", "on-delete", supportedHookEvents),
+				fmt.Sprintf(PkgValidateErrChartHookSkipConvert, "chart5", "pre-install"),
+				fmt.Sprintf(PkgValidateErrChartHookEvent, "chart5", "convert", "pre-delete", convertibleHookEvents),
+				fmt.Sprintf(PkgValidateErrChartHookTimeout, "chart5", "-1m"),
+			},
+		},
+		{
+			name:         "valid timeout",
+			chart:        v1alpha1.ZarfChart{Name: "chart6", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0", Timeout: "30m"},
+			expectedErrs: nil,
+		},
+		{
+			name:  "invalid timeout",
+			chart: v1alpha1.ZarfChart{Name: "chart7", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0", Timeout: "thirty minutes"},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrChartTimeout, "chart7", "thirty minutes"),
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -366,6 +790,24 @@ func TestValidateComponentActions(t *testing.T) {
 			},
 			expectedErrs: []string{"cannot contain setVariables outside of onDeploy in actions"},
 		},
+		{
+			name: "invalid defaults",
+			actions: v1alpha1.ZarfComponentActions{
+				OnDeploy: v1alpha1.ZarfComponentActionSet{
+					Defaults: v1alpha1.ZarfComponentActionDefaults{
+						Env:   []string{"BAD"},
+						Dir:   `C:\temp`,
+						Shell: v1alpha1.Shell{Darwin: "cmd"},
+						RunIn: "Alpine:3.20",
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Errorf(PkgValidateErrAction, fmt.Errorf(PkgValidateErrActionEnv, "BAD")).Error(),
+				fmt.Sprintf(PkgValidateErrActionShell, "cmd", "darwin", exec.SupportedShells("darwin")),
+				fmt.Sprintf(PkgValidateErrActionRunIn, "Alpine:3.20"),
+			},
+		},
 		{
 			name: "invalid onCreate action",
 			actions: v1alpha1.ZarfComponentActions{
@@ -453,6 +895,95 @@ func TestValidateComponentAction(t *testing.T) {
 			},
 			expectedErrs: []string{PkgValidateErrActionClusterNetwork},
 		},
+		{
+			name: "valid env, shell and limits",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd:             "ls",
+				Env:             []string{"FOO=bar", "_EMPTY=", "WITH_EQUALS=a=b"},
+				Shell:           &v1alpha1.Shell{Windows: "pwsh", Linux: "bash", Darwin: "zsh"},
+				MaxTotalSeconds: intPtr(30),
+				MaxRetries:      intPtr(3),
+			},
+		},
+		{
+			name: "invalid env entries",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd: "ls",
+				Env: []string{"NO_VALUE", "1BAD=value", "BAD-NAME=value", "=value"},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrActionEnv, "NO_VALUE"),
+				fmt.Sprintf(PkgValidateErrActionEnv, "1BAD=value"),
+				fmt.Sprintf(PkgValidateErrActionEnv, "BAD-NAME=value"),
+				fmt.Sprintf(PkgValidateErrActionEnv, "=value"),
+			},
+		},
+		{
+			name: "unsupported shells",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd:   "ls",
+				Shell: &v1alpha1.Shell{Windows: "zsh", Linux: "cmd"},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrActionShell, "zsh", "windows", exec.SupportedShells("windows")),
+				fmt.Sprintf(PkgValidateErrActionShell, "cmd", "linux", exec.SupportedShells("linux")),
+			},
+		},
+		{
+			name: "invalid limits",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd:             "ls",
+				MaxTotalSeconds: intPtr(2),
+				MaxRetries:      intPtr(5),
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrActionRetriesTimeout, 2, 5),
+			},
+		},
+		{
+			name: "relative dirs",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd: "ls",
+				Dir: strPtr("../.."),
+			},
+		},
+		{
+			name: "absolute dirs",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd: "ls",
+				Dir: strPtr("/opt/scripts"),
+			},
+		},
+		{
+			name: "runIn image",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd:   "ls",
+				RunIn: strPtr("alpine:3.20"),
+			},
+		},
+		{
+			name: "invalid runIn",
+			action: v1alpha1.ZarfComponentAction{
+				Wait:  &v1alpha1.ZarfComponentActionWait{Network: &v1alpha1.ZarfComponentActionWaitNetwork{}},
+				RunIn: strPtr("docker-daemon:alpine:3.20"),
+			},
+			expectedErrs: []string{
+				PkgValidateErrActionRunInWait,
+				fmt.Sprintf(PkgValidateErrActionRunIn, "docker-daemon:alpine:3.20"),
+			},
+		},
+		{
+			name: "negative limits",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd:             "ls",
+				MaxTotalSeconds: intPtr(-1),
+				MaxRetries:      intPtr(-1),
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrActionNegative, "maxTotalSeconds"),
+				fmt.Sprintf(PkgValidateErrActionNegative, "maxRetries"),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -469,3 +1000,11 @@ func TestValidateComponentAction(t *testing.T) {
 		})
 	}
 }
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
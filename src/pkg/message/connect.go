@@ -6,6 +6,7 @@ package message
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -25,3 +26,23 @@ func PrintConnectStringTable(connectStrings types.ConnectStrings) {
 		Table(header, connectData)
 	}
 }
+
+// PrintDeployStatusTable prints a table of the published status of package deploys.
+func PrintDeployStatusTable(statuses []types.DeployStatus) {
+	statusData := [][]string{}
+	for _, status := range statuses {
+		current := status.Component
+		if status.Step != "" {
+			current = fmt.Sprintf("%s: %s", status.Component, status.Step)
+		}
+		if status.Error != "" {
+			current = status.Error
+		}
+		progress := fmt.Sprintf("%d/%d", status.ComponentsCompleted, status.ComponentsTotal)
+		updated := fmt.Sprintf("%s ago", time.Since(status.Updated).Round(time.Second))
+		statusData = append(statusData, []string{status.Package, string(status.Phase), progress, current, updated})
+	}
+
+	header := []string{"Package", "Phase", "Components", "Current", "Updated"}
+	Table(header, statusData)
+}
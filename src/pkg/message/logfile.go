@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package message provides a rich set of functions for displaying messages to the user.
+package message
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// LogFileName is the name of the log file that is currently being written to.
+	LogFileName = "zarf.log"
+	// LogFileMaxSize is the size a log file can grow to before it is rotated.
+	LogFileMaxSize = 10 * 1024 * 1024
+	// LogFileMaxAge is how long rotated log files are kept.
+	LogFileMaxAge = 7 * 24 * time.Hour
+	// LogFileMaxBackups is the number of rotated log files that are kept.
+	LogFileMaxBackups = 5
+
+	// rotatedTimeFormat sorts rotated log files by the time they were rotated.
+	rotatedTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// correlationID identifies the current operation in the log file, cluster Events and package secrets.
+var correlationID = newCorrelationID()
+
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// CorrelationID returns the ID of the current operation.
+func CorrelationID() string {
+	return correlationID
+}
+
+// RotatingWriter writes to a log file in a directory, rotating it once it reaches a maximum size and removing rotated
+// files once they are too old or there are too many of them.
+type RotatingWriter struct {
+	mu         sync.Mutex
+	dir        string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+// NewRotatingWriter opens the log file in dir for appending, creating dir if it does not exist.
+func NewRotatingWriter(dir string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingWriter, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	w := &RotatingWriter{dir: dir, maxSize: maxSize, maxAge: maxAge, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	if err := w.prune(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Path returns the path of the log file that is currently being written to.
+func (w *RotatingWriter) Path() string {
+	return filepath.Join(w.dir, LogFileName)
+}
+
+// Write writes p to the log file, rotating it first if p would grow it past its maximum size.
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file.
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}
+
+func (w *RotatingWriter) open() error {
+	f, err := os.OpenFile(w.Path(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f = f
+	w.size = fi.Size()
+	return nil
+}
+
+func (w *RotatingWriter) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	rotated := filepath.Join(w.dir, fmt.Sprintf("zarf-%s.log", time.Now().UTC().Format(rotatedTimeFormat)))
+	if err := os.Rename(w.Path(), rotated); err != nil {
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	return w.prune()
+}
+
+// prune removes the rotated log files that are older than the maximum age or beyond the maximum number of backups.
+func (w *RotatingWriter) prune() error {
+	rotated, err := filepath.Glob(filepath.Join(w.dir, "zarf-*.log"))
+	if err != nil {
+		return err
+	}
+	// Newest first, the timestamp in the name sorts in the order the files were rotated.
+	sort.Sort(sort.Reverse(sort.StringSlice(rotated)))
+	for i, path := range rotated {
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if i >= w.maxBackups || time.Since(fi.ModTime()) > w.maxAge {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// prefixWriter prefixes every line written to it.
+type prefixWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	prefix  []byte
+	midLine bool
+}
+
+func (pw *prefixWriter) Write(p []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	var buf bytes.Buffer
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if !pw.midLine {
+			buf.Write(pw.prefix)
+		}
+		buf.Write(line)
+		pw.midLine = line[len(line)-1] != '\n'
+	}
+	if _, err := pw.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package message
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingWriter(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	w, err := NewRotatingWriter(dir, 10, time.Hour, 2)
+	require.NoError(t, err)
+	t.Cleanup(func() { w.Close() })
+	require.Equal(t, filepath.Join(dir, LogFileName), w.Path())
+
+	// A write that does not fit rotates the file first, but a write larger than the maximum is never split.
+	for _, line := range []string{"first\n", "second\n", "a very long third line\n"} {
+		n, err := w.Write([]byte(line))
+		require.NoError(t, err)
+		require.Equal(t, len(line), n)
+		// Rotated files are named by the time they were rotated.
+		time.Sleep(2 * time.Millisecond)
+	}
+	b, err := os.ReadFile(w.Path())
+	require.NoError(t, err)
+	require.Equal(t, "a very long third line\n", string(b))
+	rotated, err := filepath.Glob(filepath.Join(dir, "zarf-*.log"))
+	require.NoError(t, err)
+	require.Len(t, rotated, 2)
+
+	// Only the newest backups are kept.
+	_, err = w.Write([]byte("fourth\n"))
+	require.NoError(t, err)
+	rotated, err = filepath.Glob(filepath.Join(dir, "zarf-*.log"))
+	require.NoError(t, err)
+	require.Len(t, rotated, 2)
+	b, err = os.ReadFile(rotated[0])
+	require.NoError(t, err)
+	require.Equal(t, "second\n", string(b))
+	require.NoError(t, w.Close())
+
+	// Backups older than the maximum age are removed when the log file is opened.
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(rotated[0], old, old))
+	w, err = NewRotatingWriter(dir, 10, time.Hour, 2)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	remaining, err := filepath.Glob(filepath.Join(dir, "zarf-*.log"))
+	require.NoError(t, err)
+	require.Equal(t, rotated[1:], remaining)
+}
+
+func TestPrefixWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	pw := &prefixWriter{w: &buf, prefix: []byte("[id] ")}
+	for _, s := range []string{"one\ntw", "o\n", "\n", "three"} {
+		n, err := pw.Write([]byte(s))
+		require.NoError(t, err)
+		require.Equal(t, len(s), n)
+	}
+	require.Equal(t, "[id] one\n[id] two\n[id] \n[id] three", buf.String())
+	require.Len(t, CorrelationID(), 16)
+}
@@ -76,10 +76,10 @@ func InitializePTerm(w io.Writer) {
 	pterm.SetDefaultOutput(w)
 }
 
-// UseLogFile wraps a given file in a PausableWriter
-// and sets it as the log file used by the message package.
-func UseLogFile(f *os.File) (*PausableWriter, error) {
-	logFile = NewPausableWriter(f)
+// UseLogFile wraps a given writer in a PausableWriter that prefixes every line with the correlation ID of the current
+// operation and sets it as the log file used by the message package.
+func UseLogFile(w io.Writer) (*PausableWriter, error) {
+	logFile = NewPausableWriter(&prefixWriter{w: w, prefix: []byte(fmt.Sprintf("[%s] ", correlationID))})
 
 	return logFile, nil
 }
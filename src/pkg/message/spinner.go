@@ -33,18 +33,22 @@ func NewProgressSpinner(format string, a ...any) *Spinner {
 		return activeSpinner
 	}
 
-	var spinner *pterm.SpinnerPrinter
 	text := pterm.Sprintf(format, a...)
 	if NoProgress {
 		Info(text)
-	} else {
-		spinner, _ = pterm.DefaultSpinner.
-			WithRemoveWhenDone(false).
-			// Src: https://github.com/gernest/wow/blob/master/spin/spinners.go#L335
-			WithSequence(sequence...).
-			Start(text)
+		// Spinners without progress only print, so they are not shared and can be used at the same time.
+		return &Spinner{
+			startText: text,
+			termWidth: pterm.GetTerminalWidth(),
+		}
 	}
 
+	spinner, _ := pterm.DefaultSpinner.
+		WithRemoveWhenDone(false).
+		// Src: https://github.com/gernest/wow/blob/master/spin/spinners.go#L335
+		WithSequence(sequence...).
+		Start(text)
+
 	activeSpinner = &Spinner{
 		spinner:   spinner,
 		startText: text,
@@ -110,7 +114,9 @@ func (p *Spinner) Stop() {
 	if p.spinner != nil && p.spinner.IsActive {
 		_ = p.spinner.Stop()
 	}
-	activeSpinner = nil
+	if activeSpinner == p {
+		activeSpinner = nil
+	}
 }
 
 // Success prints a success message and stops the spinner.
@@ -6,37 +6,96 @@ package actions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/internal/packager/template"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
 	"github.com/zarf-dev/zarf/src/pkg/variables"
 )
 
+// runOptions are the options actions are run with.
+type runOptions struct {
+	cluster    *cluster.Cluster
+	kubeconfig string
+}
+
+// Option configures how actions are run.
+type Option func(*runOptions)
+
+// WithCluster runs the actions that set runIn in a Job in the cluster instead of a local container.
+func WithCluster(c *cluster.Cluster) Option {
+	return func(o *runOptions) {
+		o.cluster = c
+	}
+}
+
+// WithKubeconfig sets KUBECONFIG for the commands run on the host, so that the kubectl and wait commands of the actions
+// connect to the cluster being deployed to instead of the current context.
+func WithKubeconfig(path string) Option {
+	return func(o *runOptions) {
+		o.kubeconfig = path
+	}
+}
+
 // Run runs all provided actions.
-func Run(ctx context.Context, defaultCfg v1alpha1.ZarfComponentActionDefaults, actions []v1alpha1.ZarfComponentAction, variableConfig *variables.VariableConfig) error {
+func Run(ctx context.Context, defaultCfg v1alpha1.ZarfComponentActionDefaults, actions []v1alpha1.ZarfComponentAction, variableConfig *variables.VariableConfig, opts ...Option) error {
 	if variableConfig == nil {
 		variableConfig = template.GetZarfVariableConfig()
 	}
 
+	o := runOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
 	for _, a := range actions {
-		if err := runAction(ctx, defaultCfg, a, variableConfig); err != nil {
+		if err := runAction(ctx, defaultCfg, a, variableConfig, o); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// RunSet runs the before actions of the set, then op, then the after and success actions of the set, running the failure
+// actions instead if any of them fail.
+func RunSet(ctx context.Context, set v1alpha1.ZarfComponentActionSet, variableConfig *variables.VariableConfig, op func() error, opts ...Option) error {
+	onFailure := func() {
+		if err := Run(ctx, set.Defaults, set.OnFailure, variableConfig, opts...); err != nil {
+			message.Debugf("unable to run the failure action: %s", err.Error())
+		}
+	}
+
+	if err := Run(ctx, set.Defaults, set.Before, variableConfig, opts...); err != nil {
+		onFailure()
+		return fmt.Errorf("unable to run the before action: %w", err)
+	}
+	if err := op(); err != nil {
+		onFailure()
+		return err
+	}
+	if err := Run(ctx, set.Defaults, set.After, variableConfig, opts...); err != nil {
+		onFailure()
+		return fmt.Errorf("unable to run the after action: %w", err)
+	}
+	if err := Run(ctx, set.Defaults, set.OnSuccess, variableConfig, opts...); err != nil {
+		onFailure()
+		return fmt.Errorf("unable to run the success action: %w", err)
+	}
+	return nil
+}
+
 // Run commands that a component has provided.
-func runAction(ctx context.Context, defaultCfg v1alpha1.ZarfComponentActionDefaults, action v1alpha1.ZarfComponentAction, variableConfig *variables.VariableConfig) error {
+func runAction(ctx context.Context, defaultCfg v1alpha1.ZarfComponentActionDefaults, action v1alpha1.ZarfComponentAction, variableConfig *variables.VariableConfig, opts runOptions) error {
 	var (
 		cmdEscaped string
 		out        string
@@ -69,6 +128,7 @@ func runAction(ctx context.Context, defaultCfg v1alpha1.ZarfComponentActionDefau
 		// Not used for wait actions.
 		d := ""
 		action.Dir = &d
+		action.RunIn = &d
 		action.Env = []string{}
 		action.SetVariables = []v1alpha1.Variable{}
 	}
@@ -79,14 +139,23 @@ func runAction(ctx context.Context, defaultCfg v1alpha1.ZarfComponentActionDefau
 		cmdEscaped = helpers.Truncate(cmd, 60, false)
 	}
 
+	// Catch misconfigured actions before anything is run.
+	if err := validateActionCfg(actionGetCfg(ctx, defaultCfg, action, nil)); err != nil {
+		return fmt.Errorf("invalid action %q: %w", cmdEscaped, err)
+	}
+
 	spinner := message.NewProgressSpinner("Running \"%s\"", cmdEscaped)
+	defer spinner.Stop()
 	// Persist the spinner output so it doesn't get overwritten by the command output.
 	spinner.EnablePreserveWrites()
 
 	actionDefaults := actionGetCfg(ctx, defaultCfg, action, variableConfig.GetAllTemplates())
 
-	if cmd, err = actionCmdMutation(ctx, cmd, actionDefaults.Shell); err != nil {
-		spinner.Errorf(err, "Error mutating command: %s", cmdEscaped)
+	// Commands run in a container are not mutated for the host.
+	if actionDefaults.RunIn == "" {
+		if cmd, err = actionCmdMutation(ctx, cmd, actionDefaults.Shell); err != nil {
+			spinner.Errorf(err, "Error mutating command: %s", cmdEscaped)
+		}
 	}
 
 	duration := time.Duration(actionDefaults.MaxTotalSeconds) * time.Second
@@ -99,7 +168,7 @@ retryCmd:
 		// Perform the action run.
 		tryCmd := func(ctx context.Context) error {
 			// Try running the command and continue the retry loop if it fails.
-			if out, err = actionRun(ctx, actionDefaults, cmd, actionDefaults.Shell, spinner); err != nil {
+			if out, err = actionRun(ctx, actionDefaults, cmd, actionDefaults.Shell, spinner, opts); err != nil {
 				return err
 			}
 
@@ -262,6 +331,10 @@ func actionGetCfg(_ context.Context, cfg v1alpha1.ZarfComponentActionDefaults, a
 		cfg.Shell = *a.Shell
 	}
 
+	if a.RunIn != nil {
+		cfg.RunIn = *a.RunIn
+	}
+
 	// Add variables to the environment.
 	for k, v := range vars {
 		// Remove # from env variable name.
@@ -275,7 +348,32 @@ func actionGetCfg(_ context.Context, cfg v1alpha1.ZarfComponentActionDefaults, a
 	return cfg
 }
 
-func actionRun(ctx context.Context, cfg v1alpha1.ZarfComponentActionDefaults, cmd string, shellPref v1alpha1.Shell, spinner *message.Spinner) (string, error) {
+// validateActionCfg ensures the merged action config can be run on the current OS.
+func validateActionCfg(cfg v1alpha1.ZarfComponentActionDefaults) error {
+	var err error
+	for _, e := range cfg.Env {
+		key, _, ok := strings.Cut(e, "=")
+		if !ok || !exec.IsEnvVarName(key) {
+			err = errors.Join(err, fmt.Errorf("env %q must be in the form KEY=VALUE with a valid variable name", e))
+		}
+	}
+	// Commands run in a container always run in its sh.
+	shell, _ := exec.GetOSShell(cfg.Shell)
+	if cfg.RunIn == "" && len(exec.SupportedShells(runtime.GOOS)) > 0 && !exec.IsSupportedShell(runtime.GOOS, shell) {
+		err = errors.Join(err, fmt.Errorf("shell %q is not supported on %s", shell, runtime.GOOS))
+	}
+	return err
+}
+
+func actionRun(ctx context.Context, cfg v1alpha1.ZarfComponentActionDefaults, cmd string, shellPref v1alpha1.Shell, spinner *message.Spinner, opts runOptions) (string, error) {
+	// Job pods mount the working directory at the same path, which cannot be a Windows path.
+	if cfg.RunIn != "" && opts.cluster != nil && runtime.GOOS != "windows" {
+		return jobRun(ctx, opts.cluster, cfg, cmd, spinner)
+	}
+	if cfg.RunIn != "" {
+		return containerRun(ctx, cfg, cmd, spinner)
+	}
+
 	shell, shellArgs := exec.GetOSShell(shellPref)
 
 	message.Debugf("Running command in %s: %s", shell, cmd)
@@ -284,6 +382,9 @@ func actionRun(ctx context.Context, cfg v1alpha1.ZarfComponentActionDefaults, cm
 		Env: cfg.Env,
 		Dir: cfg.Dir,
 	}
+	if opts.kubeconfig != "" {
+		execCfg.Env = append(slices.Clone(cfg.Env), fmt.Sprintf("KUBECONFIG=%s", opts.kubeconfig))
+	}
 
 	if !cfg.Mute {
 		execCfg.Stdout = spinner
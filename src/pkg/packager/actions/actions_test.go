@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package actions
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestActionRunKubeconfig(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the command prints the variable with sh syntax")
+	}
+
+	ctx := testutil.TestContext(t)
+	cfg := v1alpha1.ZarfComponentActionDefaults{Mute: true, Env: []string{"KUBECONFIG=/home/zarf/.kube/config"}}
+	out, err := actionRun(ctx, cfg, "echo $KUBECONFIG", v1alpha1.Shell{}, nil, runOptions{kubeconfig: "/tmp/zarf-123/kubeconfig"})
+	require.NoError(t, err)
+	require.Equal(t, "/tmp/zarf-123/kubeconfig", strings.TrimSpace(out))
+	// The environment of the action is not changed for the actions that run after it
+	require.Equal(t, []string{"KUBECONFIG=/home/zarf/.kube/config"}, cfg.Env)
+
+	out, err = actionRun(ctx, cfg, "echo $KUBECONFIG", v1alpha1.Shell{}, nil, runOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "/home/zarf/.kube/config", strings.TrimSpace(out))
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package actions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
+)
+
+// containerRunArgs returns the container runtime arguments to run a command in an ephemeral container of the runIn image,
+// with the working directory of the action mounted at the same path so that paths in the command resolve the same way
+// they would on the host.
+func containerRunArgs(cfg v1alpha1.ZarfComponentActionDefaults, name, cmd string) ([]string, error) {
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"run", "--rm", "--name", name, "--volume", fmt.Sprintf("%s:%s", dir, dir), "--workdir", dir}
+	// Run as the current user so that files written to the mounted directory are not owned by root.
+	if runtime.GOOS == "linux" {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	// Only the names of the variables are passed so that their values are read from the environment of the runtime CLI
+	// and sensitive values do not show up in its arguments.
+	for _, e := range cfg.Env {
+		key, _, _ := strings.Cut(e, "=")
+		args = append(args, "--env", key)
+	}
+	return append(args, cfg.RunIn, "sh", "-e", "-c", cmd), nil
+}
+
+// containerRun runs a command in an ephemeral local container of the runIn image with the working directory mounted.
+func containerRun(ctx context.Context, cfg v1alpha1.ZarfComponentActionDefaults, cmd string, spinner *message.Spinner) (string, error) {
+	containerRuntime, err := exec.GetContainerRuntime()
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("zarf-action-%s", hex.EncodeToString(b))
+	args, err := containerRunArgs(cfg, name, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	message.Debugf("Running command in %s container %s: %s", containerRuntime, cfg.RunIn, cmd)
+
+	execCfg := exec.Config{
+		Env: cfg.Env,
+	}
+
+	if !cfg.Mute {
+		execCfg.Stdout = spinner
+		execCfg.Stderr = spinner
+	}
+
+	out, errOut, err := exec.CmdWithContext(ctx, execCfg, containerRuntime, args...)
+	// Stopping the runtime CLI does not stop the container, so remove it if the command timed out.
+	if ctx.Err() != nil {
+		if _, _, rmErr := exec.CmdWithContext(context.Background(), exec.Config{}, containerRuntime, "rm", "--force", name); rmErr != nil {
+			message.Debugf("unable to remove the action container %s: %s", name, rmErr.Error())
+		}
+	}
+	// Dump final complete output (respect mute to prevent sensitive values from hitting the logs).
+	if !cfg.Mute {
+		message.Debug(cmd, out, errOut)
+	}
+
+	return out, err
+}
+
+// jobRun runs a command in a Job of the runIn image in the cluster, with a copy of the working directory.
+func jobRun(ctx context.Context, c *cluster.Cluster, cfg v1alpha1.ZarfComponentActionDefaults, cmd string, spinner *message.Spinner) (string, error) {
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return "", err
+	}
+
+	message.Debugf("Running command in a cluster job of %s: %s", cfg.RunIn, cmd)
+
+	var out io.Writer = io.Discard
+	if !cfg.Mute {
+		out = spinner
+	}
+	opts := cluster.ActionJobOptions{
+		Image: cfg.RunIn,
+		Dir:   dir,
+		Cmd:   cmd,
+		Env:   cfg.Env,
+	}
+	stdout, err := c.RunActionJob(ctx, opts, out)
+	// Dump final complete output (respect mute to prevent sensitive values from hitting the logs).
+	if !cfg.Mute {
+		message.Debug(cmd, stdout)
+	}
+
+	return stdout, err
+}
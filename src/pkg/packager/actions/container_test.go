@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestContainerRunArgs(t *testing.T) {
+	t.Parallel()
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	user := []string{}
+	if runtime.GOOS == "linux" {
+		user = []string{"--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      v1alpha1.ZarfComponentActionDefaults
+		dir      string
+		expected []string
+	}{
+		{
+			name: "current directory",
+			cfg:  v1alpha1.ZarfComponentActionDefaults{RunIn: "alpine:3.20"},
+			dir:  cwd,
+			expected: []string{
+				"alpine:3.20", "sh", "-e", "-c", "ls",
+			},
+		},
+		{
+			name: "relative directory and env",
+			cfg: v1alpha1.ZarfComponentActionDefaults{
+				RunIn: "alpine:3.20",
+				Dir:   "scripts",
+				Env:   []string{"ZARF_VAR_SECRET=hunter2", "EMPTY="},
+			},
+			dir: filepath.Join(cwd, "scripts"),
+			expected: []string{
+				"--env", "ZARF_VAR_SECRET", "--env", "EMPTY", "alpine:3.20", "sh", "-e", "-c", "ls",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args, err := containerRunArgs(tt.cfg, "zarf-action-1234", "ls")
+			require.NoError(t, err)
+			expected := []string{"run", "--rm", "--name", "zarf-action-1234", "--volume", fmt.Sprintf("%s:%s", tt.dir, tt.dir), "--workdir", tt.dir}
+			expected = append(expected, user...)
+			expected = append(expected, tt.expected...)
+			require.Equal(t, expected, args)
+			// Env values are read from the environment of the runtime CLI so they are never in its arguments.
+			require.NotContains(t, args, "ZARF_VAR_SECRET=hunter2")
+		})
+	}
+}
@@ -6,22 +6,26 @@ package packager
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
+	"runtime"
 	"slices"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/packager/gate"
 	"github.com/zarf-dev/zarf/src/internal/packager/template"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/deprecated"
+	"github.com/zarf-dev/zarf/src/pkg/packager/features"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/variables"
@@ -30,13 +34,22 @@ import (
 
 // Packager is the main struct for managing packages.
 type Packager struct {
-	cfg            *types.PackagerConfig
-	variableConfig *variables.VariableConfig
-	state          *types.ZarfState
-	cluster        *cluster.Cluster
-	layout         *layout.PackagePaths
-	hpaModified    bool
-	source         sources.PackageSource
+	cfg              *types.PackagerConfig
+	variableConfig   *variables.VariableConfig
+	state            *types.ZarfState
+	cluster          *cluster.Cluster
+	layout           *layout.PackagePaths
+	hpaModified      bool
+	pushedImages     []string
+	pushedRepos      []string
+	source           sources.PackageSource
+	commonOpts       types.ZarfCommonOptions
+	variableOverlays []string
+	deployStatus     *types.DeployStatus
+	preflightChecked bool
+	rollback         *deployRollback
+	policyGates      []*gate.Gate
+	kubeconfig       string
 }
 
 // Modifier is a function that modifies the packager.
@@ -56,6 +69,14 @@ func WithCluster(cluster *cluster.Cluster) Modifier {
 	}
 }
 
+// WithCommonOptions sets the options that apply across operations for the packager, instead of the global
+// config.CommonOptions that are set by the CLI.
+func WithCommonOptions(opts types.ZarfCommonOptions) Modifier {
+	return func(p *Packager) {
+		p.commonOpts = opts
+	}
+}
+
 // WithTemp sets the temp directory for the packager.
 //
 // This temp directory is used as the destination where p.source loads the package.
@@ -78,23 +99,24 @@ func New(cfg *types.PackagerConfig, mods ...Modifier) (*Packager, error) {
 	var (
 		err  error
 		pkgr = &Packager{
-			cfg: cfg,
+			cfg:        cfg,
+			commonOpts: config.CommonOptions,
 		}
 	)
 
 	pkgr.variableConfig = template.GetZarfVariableConfig()
 
-	if config.CommonOptions.TempDirectory != "" {
-		// If the cache directory is within the temp directory, warn the user
-		if strings.HasPrefix(config.CommonOptions.CachePath, config.CommonOptions.TempDirectory) {
-			message.Warnf("The cache directory (%q) is within the temp directory (%q) and will be removed when the temp directory is cleaned up", config.CommonOptions.CachePath, config.CommonOptions.TempDirectory)
-		}
-	}
-
 	for _, mod := range mods {
 		mod(pkgr)
 	}
 
+	if pkgr.commonOpts.TempDirectory != "" {
+		// If the cache directory is within the temp directory, warn the user
+		if strings.HasPrefix(pkgr.commonOpts.CachePath, pkgr.commonOpts.TempDirectory) {
+			message.Warnf("The cache directory (%q) is within the temp directory (%q) and will be removed when the temp directory is cleaned up", pkgr.commonOpts.CachePath, pkgr.commonOpts.TempDirectory)
+		}
+	}
+
 	// Fill the source if it wasn't provided - note source can be nil if the package is being created
 	if pkgr.source == nil && pkgr.cfg.CreateOpts.BaseDir == "" {
 		pkgr.source, err = sources.New(&pkgr.cfg.PkgOpts)
@@ -105,7 +127,7 @@ func New(cfg *types.PackagerConfig, mods ...Modifier) (*Packager, error) {
 
 	// If the temp directory is not set, set it to the default
 	if pkgr.layout == nil {
-		dir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+		dir, err := utils.MakeTempDir(pkgr.commonOpts.TempDirectory)
 		if err != nil {
 			return nil, fmt.Errorf("unable to create package temp paths: %w", err)
 		}
@@ -134,7 +156,7 @@ func (p *Packager) connectToCluster(ctx context.Context) error {
 		return nil
 	}
 
-	cluster, err := cluster.NewClusterWithWait(ctx)
+	cluster, err := cluster.NewClusterWithWait(ctx, cluster.WithKubeContext(p.cfg.DeployOpts.KubeContext))
 	if err != nil {
 		return err
 	}
@@ -154,15 +176,6 @@ func (p *Packager) attemptClusterChecks(ctx context.Context) error {
 	spinner := message.NewProgressSpinner("Gathering additional cluster information (if available)")
 	defer spinner.Stop()
 
-	// Check the clusters architecture matches the package spec
-	if err := p.validatePackageArchitecture(ctx); err != nil {
-		if errors.Is(err, lang.ErrUnableToCheckArch) {
-			message.Warnf("Unable to validate package architecture: %s", err.Error())
-		} else {
-			return err
-		}
-	}
-
 	// Check for any breaking changes between the initialized Zarf version and this CLI
 	if existingInitPackage, _ := p.cluster.GetDeployedPackage(ctx, "init"); existingInitPackage != nil {
 		// Use the build version instead of the metadata since this will support older Zarf versions
@@ -184,13 +197,38 @@ func (p *Packager) validatePackageArchitecture(ctx context.Context) error {
 		return nil
 	}
 
+	architectures, err := p.clusterArchitectures(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A multi-architecture package only needs to share one architecture with the cluster.
+	if p.cfg.Pkg.IsMultiArch() {
+		for _, arch := range architectures {
+			if slices.Contains(p.cfg.Pkg.Metadata.Architectures, arch) {
+				return nil
+			}
+		}
+		return fmt.Errorf(lang.CmdPackageDeployValidateArchitectureErr, strings.Join(p.cfg.Pkg.Metadata.Architectures, ", "), strings.Join(architectures, ", "))
+	}
+
+	// Check if the package architecture and the cluster architecture are the same.
+	if !slices.Contains(architectures, p.cfg.Pkg.Metadata.Architecture) {
+		return fmt.Errorf(lang.CmdPackageDeployValidateArchitectureErr, p.cfg.Pkg.Metadata.Architecture, strings.Join(architectures, ", "))
+	}
+
+	return nil
+}
+
+// clusterArchitectures returns the architectures of the nodes in the connected cluster.
+func (p *Packager) clusterArchitectures(ctx context.Context) ([]string, error) {
 	// Get node architectures
 	nodeList, err := p.cluster.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return lang.ErrUnableToCheckArch
+		return nil, lang.ErrUnableToCheckArch
 	}
 	if len(nodeList.Items) == 0 {
-		return lang.ErrUnableToCheckArch
+		return nil, lang.ErrUnableToCheckArch
 	}
 	archMap := map[string]bool{}
 	for _, node := range nodeList.Items {
@@ -200,12 +238,42 @@ func (p *Packager) validatePackageArchitecture(ctx context.Context) error {
 	for arch := range archMap {
 		architectures = append(architectures, arch)
 	}
+	slices.Sort(architectures)
+	return architectures, nil
+}
 
-	// Check if the package architecture and the cluster architecture are the same.
-	if !slices.Contains(architectures, p.cfg.Pkg.Metadata.Architecture) {
-		return fmt.Errorf(lang.CmdPackageDeployValidateArchitectureErr, p.cfg.Pkg.Metadata.Architecture, strings.Join(architectures, ", "))
+// filterComponentsByArchitecture removes the components of a multi-architecture package that are not for the
+// architectures being deployed to. These are the architectures from the --architecture flag, the architectures of the
+// cluster's nodes if a component requires a cluster, or the local architecture, in that order.
+func (p *Packager) filterComponentsByArchitecture(ctx context.Context) error {
+	var archs []string
+	requiresCluster := slices.ContainsFunc(p.cfg.Pkg.Components, func(c v1alpha1.ZarfComponent) bool {
+		return c.RequiresCluster()
+	})
+	switch {
+	case config.CLIArch != "":
+		archs = []string{config.CLIArch}
+	case requiresCluster:
+		connectCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		if err := p.connectToCluster(connectCtx); err != nil {
+			return fmt.Errorf("unable to connect to the Kubernetes cluster: %w", err)
+		}
+		clusterArchs, err := p.clusterArchitectures(ctx)
+		if err != nil {
+			return err
+		}
+		archs = clusterArchs
+	default:
+		archs = []string{runtime.GOARCH}
 	}
 
+	message.Debugf("Deploying the components of this multi-architecture package for %s", strings.Join(archs, ", "))
+	components, err := filters.ByArchitecture(archs...).Apply(p.cfg.Pkg)
+	if err != nil {
+		return err
+	}
+	p.cfg.Pkg.Components = components
 	return nil
 }
 
@@ -233,3 +301,16 @@ func validateLastNonBreakingVersion(cliVersion, lastNonBreakingVersion string) (
 	}
 	return nil, nil
 }
+
+// validateFeatures returns warnings for the features a package relies on that the running CLI does not support or has deprecated.
+func validateFeatures(cliVersion string, packageFeatures []string) []string {
+	unsupported, deprecatedFeatures := features.Check(packageFeatures)
+	warnings := []string{}
+	for _, feature := range unsupported {
+		warnings = append(warnings, fmt.Sprintf(lang.CmdPackageDeployUnsupportedFeatureWarn, feature, cliVersion))
+	}
+	for _, feature := range deprecatedFeatures {
+		warnings = append(warnings, fmt.Sprintf(lang.CmdPackageDeployDeprecatedFeatureWarn, feature, features.Deprecated[feature]))
+	}
+	return warnings
+}
@@ -6,6 +6,7 @@ package packager
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -14,8 +15,10 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/packager/features"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -183,3 +186,28 @@ func TestValidateLastNonBreakingVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFeatures(t *testing.T) {
+	t.Parallel()
+
+	warnings := validateFeatures("v0.36.0", []string{features.DataInjections, "teleportation", features.ComponentGroups})
+	require.Equal(t, []string{
+		fmt.Sprintf(lang.CmdPackageDeployUnsupportedFeatureWarn, "teleportation", "v0.36.0"),
+		fmt.Sprintf(lang.CmdPackageDeployDeprecatedFeatureWarn, features.ComponentGroups, features.Deprecated[features.ComponentGroups]),
+	}, warnings)
+	require.Empty(t, validateFeatures("v0.36.0", nil))
+}
+
+func TestWithCommonOptions(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	cfg := &types.PackagerConfig{CreateOpts: types.ZarfCreateOptions{BaseDir: "."}}
+	p, err := New(cfg, WithCommonOptions(types.ZarfCommonOptions{TempDirectory: tmp, Confirm: true}))
+	require.NoError(t, err)
+	t.Cleanup(p.ClearTempPaths)
+
+	require.Equal(t, tmp, filepath.Dir(p.layout.Base))
+	require.NoError(t, p.confirmAction(config.ZarfCreateStage, nil, nil, nil))
+	require.NotEqual(t, tmp, config.CommonOptions.TempDirectory)
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/packager/workspace"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// ImportAllComponents is the value of import.components that imports every component of the referenced zarf.yaml.
+const ImportAllComponents = "*"
+
+// ExpandComponent returns a component importing each component of the referenced zarf.yaml by name when the given
+// component imports every component, otherwise the component itself is returned.
+//
+// The expanded components are copies of the given component named after the component they import with the import
+// prefix, only the components matching the given architecture, flavors and package templates are imported.
+func ExpandComponent(ctx context.Context, c v1alpha1.ZarfComponent, arch string, flavors []string, variables map[string]string) ([]v1alpha1.ZarfComponent, error) {
+	if c.Import.Components == "" {
+		return []v1alpha1.ZarfComponent{c}, nil
+	}
+	if err := validateComponentCompose(c); err != nil {
+		return nil, fmt.Errorf("invalid imported definition for %s: %w", c.Name, err)
+	}
+
+	pkg, location, err := fetchImportedPackage(ctx, c.Import)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := []v1alpha1.ZarfComponent{}
+	seen := map[string]bool{}
+	overridden := OverriddenComponents(pkg.Components, flavors)
+	for i, component := range pkg.Components {
+		if err := ValidateExpression(component.Only.Expression); err != nil {
+			return nil, fmt.Errorf("component %q in %q: %w", component.Name, location, err)
+		}
+		// a component can be defined once per architecture and flavor but is only imported once
+		if overridden[i] || !CompatibleComponent(component, arch, flavors, variables) || seen[component.Name] {
+			continue
+		}
+		seen[component.Name] = true
+
+		imported := c
+		imported.Name = c.Import.Prefix + component.Name
+		imported.Import = v1alpha1.ZarfComponentImport{
+			Name: component.Name,
+			Path: c.Import.Path,
+			URL:  c.Import.URL,
+		}
+		expanded = append(expanded, imported)
+	}
+	if len(expanded) == 0 {
+		return nil, fmt.Errorf("no components found in %q satisfying %q", location, arch)
+	}
+	return expanded, nil
+}
+
+// fetchImportedPackage returns the zarf.yaml referenced by an import of the head component and where it was found.
+func fetchImportedPackage(ctx context.Context, imp v1alpha1.ZarfComponentImport) (v1alpha1.ZarfPackage, string, error) {
+	var pkg v1alpha1.ZarfPackage
+	if imp.Path != "" {
+		importPath, err := workspace.ResolveImportPath(".", imp.Path)
+		if err != nil {
+			return pkg, "", err
+		}
+		// this assumes the composed package is following the zarf layout
+		err = utils.ReadYaml(filepath.Join(importPath, layout.ZarfYAML), &pkg)
+		return pkg, importPath, err
+	}
+	if isGitImport(imp.URL) {
+		dir, err := resolveGitImport(ctx, imp.URL)
+		if err != nil {
+			return pkg, "", err
+		}
+		err = utils.ReadYaml(filepath.Join(dir, layout.ZarfYAML), &pkg)
+		return pkg, imp.URL, err
+	}
+	pkg, err := (&ImportChain{}).fetchZarfYAML(ctx, imp.URL)
+	return pkg, imp.URL, err
+}
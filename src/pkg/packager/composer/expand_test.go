@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+)
+
+func TestExpandComponent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pkg := `kind: ZarfPackageConfig
+metadata:
+  name: monitoring
+components:
+  - name: prometheus
+    only:
+      cluster:
+        architecture: amd64
+  - name: prometheus
+    only:
+      cluster:
+        architecture: arm64
+  - name: grafana
+  - name: loki
+    only:
+      flavor: [logging]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, layout.ZarfYAML), []byte(pkg), 0o600))
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	path, err := filepath.Rel(cwd, dir)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		component   v1alpha1.ZarfComponent
+		flavors     []string
+		expected    []v1alpha1.ZarfComponent
+		expectedErr string
+	}{
+		{
+			name: "single component",
+			component: v1alpha1.ZarfComponent{
+				Name:   "grafana",
+				Import: v1alpha1.ZarfComponentImport{Path: path},
+			},
+			expected: []v1alpha1.ZarfComponent{
+				{
+					Name:   "grafana",
+					Import: v1alpha1.ZarfComponentImport{Path: path},
+				},
+			},
+		},
+		{
+			name: "every component",
+			component: v1alpha1.ZarfComponent{
+				Name:        "monitoring",
+				Description: "Monitoring stack",
+				Import:      v1alpha1.ZarfComponentImport{Path: path, Components: ImportAllComponents},
+			},
+			expected: []v1alpha1.ZarfComponent{
+				{
+					Name:        "prometheus",
+					Description: "Monitoring stack",
+					Import:      v1alpha1.ZarfComponentImport{Name: "prometheus", Path: path},
+				},
+				{
+					Name:        "grafana",
+					Description: "Monitoring stack",
+					Import:      v1alpha1.ZarfComponentImport{Name: "grafana", Path: path},
+				},
+			},
+		},
+		{
+			name: "every component with a prefix and flavor",
+			component: v1alpha1.ZarfComponent{
+				Name:   "monitoring",
+				Import: v1alpha1.ZarfComponentImport{Path: path, Components: ImportAllComponents, Prefix: "mon-"},
+			},
+			flavors: []string{"logging"},
+			expected: []v1alpha1.ZarfComponent{
+				{
+					Name:   "mon-prometheus",
+					Import: v1alpha1.ZarfComponentImport{Name: "prometheus", Path: path},
+				},
+				{
+					Name:   "mon-grafana",
+					Import: v1alpha1.ZarfComponentImport{Name: "grafana", Path: path},
+				},
+				{
+					Name:   "mon-loki",
+					Import: v1alpha1.ZarfComponentImport{Name: "loki", Path: path},
+				},
+			},
+		},
+		{
+			name: "every component and a name",
+			component: v1alpha1.ZarfComponent{
+				Name:   "monitoring",
+				Import: v1alpha1.ZarfComponentImport{Name: "grafana", Path: path, Components: ImportAllComponents},
+			},
+			expectedErr: "invalid imported definition for monitoring: both a name and every component were provided",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expanded, err := ExpandComponent(context.Background(), tt.component, "amd64", tt.flavors, nil)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, expanded)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// ExpressionEnv is what the only.expression of a component is evaluated against on create.
+type ExpressionEnv struct {
+	// The architecture the package is created for, as arch
+	Arch string
+	// The flavor the package is created for, as flavor
+	Flavor string
+	// The package templates given with --set, as vars.<NAME>
+	Variables map[string]string
+}
+
+// EvaluateExpression evaluates a component expression such as `arch == "amd64" && (vars.GPU || flavor != "upstream")`.
+//
+// Expressions compare strings with == and != and combine conditions with &&, || and !, where a value is true if it
+// parses as a true boolean. Package templates that were not given evaluate to an empty string.
+func EvaluateExpression(expression string, env ExpressionEnv) (bool, error) {
+	expr, err := parser.ParseExpr(expression)
+	if err != nil {
+		return false, fmt.Errorf("invalid expression %q: %w", expression, err)
+	}
+	value, err := evaluate(expr, env)
+	if err != nil {
+		return false, fmt.Errorf("invalid expression %q: %w", expression, err)
+	}
+	return truthy(value), nil
+}
+
+// ValidateExpression returns an error if the expression is not a valid component expression.
+func ValidateExpression(expression string) error {
+	if expression == "" {
+		return nil
+	}
+	_, err := EvaluateExpression(expression, ExpressionEnv{})
+	return err
+}
+
+func evaluate(expr ast.Expr, env ExpressionEnv) (string, error) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return evaluate(e.X, env)
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", fmt.Errorf("unsupported literal %s, values must be quoted strings", e.Value)
+		}
+		return strconv.Unquote(e.Value)
+	case *ast.Ident:
+		switch e.Name {
+		case "true", "false":
+			return e.Name, nil
+		case "arch":
+			return env.Arch, nil
+		case "flavor":
+			return env.Flavor, nil
+		}
+		return "", fmt.Errorf("unknown identifier %s, expected arch, flavor or vars.<NAME>", e.Name)
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && x.Name == "vars" {
+			return env.Variables[e.Sel.Name], nil
+		}
+		return "", fmt.Errorf("unsupported selector, only vars.<NAME> is supported")
+	case *ast.UnaryExpr:
+		if e.Op != token.NOT {
+			return "", fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		x, err := evaluate(e.X, env)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(!truthy(x)), nil
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.EQL, token.NEQ, token.LAND, token.LOR:
+		default:
+			return "", fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		// both operands are always evaluated so that invalid expressions are found regardless of their values
+		x, err := evaluate(e.X, env)
+		if err != nil {
+			return "", err
+		}
+		y, err := evaluate(e.Y, env)
+		if err != nil {
+			return "", err
+		}
+		switch e.Op {
+		case token.EQL:
+			return strconv.FormatBool(x == y), nil
+		case token.NEQ:
+			return strconv.FormatBool(x != y), nil
+		case token.LAND:
+			return strconv.FormatBool(truthy(x) && truthy(y)), nil
+		default:
+			return strconv.FormatBool(truthy(x) || truthy(y)), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported expression")
+}
+
+func truthy(value string) bool {
+	b, err := strconv.ParseBool(value)
+	return err == nil && b
+}
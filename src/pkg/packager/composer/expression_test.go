@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	t.Parallel()
+
+	env := ExpressionEnv{
+		Arch:      "amd64",
+		Flavor:    "upstream",
+		Variables: map[string]string{"GPU": "true", "REGISTRY": "ghcr.io"},
+	}
+
+	tests := []struct {
+		name        string
+		expression  string
+		expected    bool
+		expectedErr string
+	}{
+		{
+			name:       "architecture",
+			expression: `arch == "amd64"`,
+			expected:   true,
+		},
+		{
+			name:       "flavor",
+			expression: `flavor != "upstream"`,
+			expected:   false,
+		},
+		{
+			name:       "boolean variable",
+			expression: `vars.GPU && (arch == "arm64" || flavor == "upstream")`,
+			expected:   true,
+		},
+		{
+			name:       "string variable",
+			expression: "vars.REGISTRY == `ghcr.io`",
+			expected:   true,
+		},
+		{
+			name:       "missing variable",
+			expression: `!vars.MISSING && vars.MISSING == ""`,
+			expected:   true,
+		},
+		{
+			name:       "non boolean value",
+			expression: "vars.REGISTRY || false",
+			expected:   false,
+		},
+		{
+			name:        "unknown identifier",
+			expression:  `distro == "k3s"`,
+			expectedErr: `invalid expression "distro == \"k3s\"": unknown identifier distro, expected arch, flavor or vars.<NAME>`,
+		},
+		{
+			name:        "unsupported operator",
+			expression:  `arch < "arm64"`,
+			expectedErr: `invalid expression "arch < \"arm64\"": unsupported operator <`,
+		},
+		{
+			name:        "unquoted value",
+			expression:  "arch == 64",
+			expectedErr: `invalid expression "arch == 64": unsupported literal 64, values must be quoted strings`,
+		},
+		{
+			name:        "syntax error",
+			expression:  `arch ==`,
+			expectedErr: `invalid expression "arch =="`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ok, err := EvaluateExpression(tt.expression, env)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				require.Error(t, ValidateExpression(tt.expression))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, ok)
+			require.NoError(t, ValidateExpression(tt.expression))
+		})
+	}
+}
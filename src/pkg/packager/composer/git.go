@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/git"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// GitImportPrefix is the prefix of component import URLs that reference a zarf.yaml in a git repository, e.g.
+// git:https://github.com/org/components.git@v1.0.0//path/to/package.
+const GitImportPrefix = "git:"
+
+var (
+	gitImportsLock sync.Mutex
+	// gitImports are the directories the repositories of git imports were cloned to by this process keyed by address.
+	gitImports = map[string]string{}
+)
+
+// isGitImport returns true if the import URL references a git repository.
+func isGitImport(url string) bool {
+	return strings.HasPrefix(url, GitImportPrefix)
+}
+
+// splitGitImport returns the repository address (including its ref) and the subpath of the package within the
+// repository from a git import URL.
+func splitGitImport(url string) (string, string, error) {
+	address, ok := strings.CutPrefix(url, GitImportPrefix)
+	if !ok {
+		return "", "", fmt.Errorf("import URL %q is not a git URL", url)
+	}
+	// git://host/repo.git is the git protocol rather than the prefix of another URL
+	if strings.HasPrefix(address, "//") {
+		address = url
+	}
+	schemeEnd := strings.Index(address, "://")
+	if schemeEnd < 0 {
+		return "", "", fmt.Errorf("import URL %q must be a git URL like %shttps://host/repo.git@ref//path", url, GitImportPrefix)
+	}
+	var subpath string
+	if idx := strings.Index(address[schemeEnd+len("://"):], "//"); idx >= 0 {
+		idx += schemeEnd + len("://")
+		subpath = filepath.Clean(address[idx+len("//"):])
+		address = address[:idx]
+		if !filepath.IsLocal(subpath) {
+			return "", "", fmt.Errorf("import URL %q must reference a path within the repository, got %q", url, subpath)
+		}
+	}
+	if _, _, err := transform.GitURLSplitRef(address); err != nil {
+		return "", "", err
+	}
+	return address, subpath, nil
+}
+
+// resolveGitImport clones the repository of a git import into the cache and returns the directory of the imported
+// package within it.
+//
+// Repositories are cloned once per process, and repositories pinned to a commit SHA are reused from the cache.
+func resolveGitImport(ctx context.Context, url string) (string, error) {
+	address, subpath, err := splitGitImport(url)
+	if err != nil {
+		return "", err
+	}
+
+	gitImportsLock.Lock()
+	defer gitImportsLock.Unlock()
+
+	dir, ok := gitImports[address]
+	if !ok {
+		dir, err = cloneGitImport(ctx, address)
+		if err != nil {
+			return "", err
+		}
+		gitImports[address] = dir
+	}
+	return filepath.Join(dir, subpath), nil
+}
+
+func cloneGitImport(ctx context.Context, address string) (string, error) {
+	_, ref, err := transform.GitURLSplitRef(address)
+	if err != nil {
+		return "", err
+	}
+	repoFolder, err := transform.GitURLtoFolderName(address)
+	if err != nil {
+		return "", err
+	}
+	cache := filepath.Join(config.GetAbsCachePath(), "git")
+	dir := filepath.Join(cache, repoFolder)
+
+	// branches and tags can move so only clones of a commit SHA are reused
+	if plumbing.IsHash(ref) && !helpers.InvalidPath(dir) {
+		message.Debug("using cached clone of remote component repository:", filepath.Join("<zarf-cache>", "git", repoFolder))
+		return dir, nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := helpers.CreateDirectory(cache, helpers.ReadWriteExecuteUser); err != nil {
+		return "", err
+	}
+	if _, err := git.Clone(ctx, cache, address, false); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("unable to clone %s: %w", address, err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluxcd/gitkit"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+)
+
+func TestSplitGitImport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		url             string
+		expectedAddress string
+		expectedSubpath string
+		expectedErr     string
+	}{
+		{
+			name:            "repository root",
+			url:             "git:https://github.com/zarf-dev/components.git@v1.0.0",
+			expectedAddress: "https://github.com/zarf-dev/components.git@v1.0.0",
+		},
+		{
+			name:            "subpath",
+			url:             "git:https://github.com/zarf-dev/components.git@refs/heads/main//packages/nginx/",
+			expectedAddress: "https://github.com/zarf-dev/components.git@refs/heads/main",
+			expectedSubpath: filepath.Join("packages", "nginx"),
+		},
+		{
+			name:            "git protocol",
+			url:             "git://github.com/zarf-dev/components.git//packages/nginx",
+			expectedAddress: "git://github.com/zarf-dev/components.git",
+			expectedSubpath: filepath.Join("packages", "nginx"),
+		},
+		{
+			name:        "subpath outside of the repository",
+			url:         "git:https://github.com/zarf-dev/components.git//../nginx",
+			expectedErr: `import URL "git:https://github.com/zarf-dev/components.git//../nginx" must reference a path within the repository, got "../nginx"`,
+		},
+		{
+			name:        "missing scheme",
+			url:         "git:github.com/zarf-dev/components.git",
+			expectedErr: `import URL "git:github.com/zarf-dev/components.git" must be a git URL like git:https://host/repo.git@ref//path`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			address, subpath, err := splitGitImport(tt.url)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedAddress, address)
+			require.Equal(t, tt.expectedSubpath, subpath)
+		})
+	}
+}
+
+func TestGitImportChain(t *testing.T) {
+	cachePath := config.CommonOptions.CachePath
+	config.CommonOptions.CachePath = t.TempDir()
+	t.Cleanup(func() {
+		config.CommonOptions.CachePath = cachePath
+	})
+
+	srv := newGitImportRemote(t, map[string]string{
+		"packages/nginx/zarf.yaml": `kind: ZarfPackageConfig
+metadata:
+  name: nginx
+components:
+  - name: nginx
+    import:
+      path: ../base
+`,
+		"packages/base/zarf.yaml": `kind: ZarfPackageConfig
+metadata:
+  name: base
+components:
+  - name: nginx
+    files:
+      - source: nginx.conf
+        target: /etc/nginx/nginx.conf
+`,
+		"packages/base/nginx.conf": "worker_processes 1;",
+	})
+	url := fmt.Sprintf("%s%s/components.git@refs/heads/master//packages/nginx", GitImportPrefix, srv.URL)
+
+	head := v1alpha1.ZarfComponent{
+		Name:   "nginx",
+		Import: v1alpha1.ZarfComponentImport{URL: url},
+	}
+	chain, err := NewImportChain(context.Background(), head, 0, "test-package", "amd64", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("component %q imports %q in %s, which imports %q in ../base", "nginx", "nginx", url, "nginx"), chain.String())
+	require.False(t, chain.ContainsOCIImport())
+
+	composed, err := chain.Compose(context.Background())
+	require.NoError(t, err)
+	dir, err := resolveGitImport(context.Background(), url)
+	require.NoError(t, err)
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	source, err := filepath.Rel(cwd, filepath.Join(dir, "..", "base", "nginx.conf"))
+	require.NoError(t, err)
+	require.Len(t, composed.Files, 1)
+	require.Equal(t, source, composed.Files[0].Source)
+	require.FileExists(t, source)
+
+	head.Name = "missing"
+	_, err = NewImportChain(context.Background(), head, 0, "test-package", "amd64", nil, nil)
+	require.EqualError(t, err, fmt.Sprintf("component %q not found in %q", "missing", url))
+}
+
+// newGitImportRemote starts a git server with a single "components" repository containing the given files.
+func newGitImportRemote(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+
+	gitSrv := gitkit.New(gitkit.Config{
+		Dir:        t.TempDir(),
+		AutoCreate: true,
+	})
+	require.NoError(t, gitSrv.Setup())
+	srv := httptest.NewServer(http.HandlerFunc(gitSrv.ServeHTTP))
+	t.Cleanup(srv.Close)
+
+	fs := memfs.New()
+	repo, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	for path, content := range files {
+		f, err := fs.Create(path)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		_, err = w.Add(path)
+		require.NoError(t, err)
+	}
+	_, err = w.Commit("Initial commit", &git.CommitOptions{
+		Author: &object.Signature{
+			Email: "example@example.com",
+		},
+	})
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{
+		Name: "origin",
+		URLs: []string{fmt.Sprintf("%s/components.git", srv.URL)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Push(&git.PushOptions{RemoteName: "origin"}))
+	return srv
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// Layer is what a node in an import chain contributes to the composed component.
+type Layer struct {
+	// The name of the component within its zarf.yaml
+	Component string
+	// The .components index of the component within its zarf.yaml
+	Index int
+	// The .metadata.name of the zarf.yaml
+	PackageName string
+	// Where the zarf.yaml was imported from
+	Location string
+	// The filters the component was matched with
+	Only v1alpha1.ZarfComponentOnlyTarget
+	// The images the component adds
+	Images []string
+	// The charts the component adds, as name@version
+	Charts []string
+	// The variables and constants the zarf.yaml adds to the package
+	Variables []string
+}
+
+// Layers returns what each node in the import chain contributes to the composed component, starting with the head.
+//
+// The layers must be taken before the chain is composed as composing fixes the paths within each node.
+func (ic *ImportChain) Layers() []Layer {
+	layers := []Layer{}
+	for node := ic.head; node != nil; node = node.next {
+		layer := Layer{
+			Component:   node.Name,
+			Index:       node.Index(),
+			PackageName: node.OriginalPackageName(),
+			Location:    node.ImportLocation(),
+			Only:        node.Only,
+			Images:      node.Images,
+		}
+		for _, chart := range node.Charts {
+			name := chart.Name
+			if chart.Version != "" {
+				name += "@" + chart.Version
+			}
+			layer.Charts = append(layer.Charts, name)
+		}
+		for _, v := range node.vars {
+			layer.Variables = append(layer.Variables, v.Name)
+		}
+		for _, c := range node.consts {
+			layer.Variables = append(layer.Variables, c.Name)
+		}
+		layers = append(layers, layer)
+	}
+	return layers
+}
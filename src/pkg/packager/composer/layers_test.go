@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package composer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestLayers(t *testing.T) {
+	t.Parallel()
+
+	ic := createChainFromSlice(t, []v1alpha1.ZarfComponent{
+		{
+			Name:   "frontend",
+			Import: v1alpha1.ZarfComponentImport{Path: "base", Name: "web"},
+			Images: []string{"busybox:1.36"},
+		},
+		{
+			Name: "web",
+			Only: v1alpha1.ZarfComponentOnlyTarget{Flavor: v1alpha1.FlavorList{"upstream"}},
+			Charts: []v1alpha1.ZarfChart{
+				{Name: "podinfo", Version: "6.4.0"},
+				{Name: "local", LocalPath: "chart"},
+			},
+		},
+	})
+	ic.tail.vars = []v1alpha1.InteractiveVariable{{Variable: v1alpha1.Variable{Name: "DOMAIN"}}}
+	ic.tail.consts = []v1alpha1.Constant{{Name: "VERSION"}}
+
+	expected := []Layer{
+		{
+			Component:   "frontend",
+			Index:       0,
+			PackageName: "test-package",
+			Location:    ".",
+			Images:      []string{"busybox:1.36"},
+		},
+		{
+			Component:   "web",
+			Index:       1,
+			PackageName: "test-package",
+			Location:    "base",
+			Only:        v1alpha1.ZarfComponentOnlyTarget{Flavor: v1alpha1.FlavorList{"upstream"}},
+			Charts:      []string{"podinfo@6.4.0", "local"},
+			Variables:   []string{"DOMAIN", "VERSION"},
+		},
+	}
+	require.Equal(t, expected, ic.Layers())
+}
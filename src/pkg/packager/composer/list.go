@@ -8,14 +8,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/defenseunicorns/pkg/oci"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/extensions/bigbang"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/deprecated"
+	"github.com/zarf-dev/zarf/src/pkg/packager/workspace"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 )
@@ -82,7 +87,11 @@ type ImportChain struct {
 	head *Node
 	tail *Node
 
-	remote *zoci.Remote
+	remote   *zoci.Remote
+	root     *oci.Manifest
+	rootDesc ocispec.Descriptor
+
+	provenance map[string]Origin
 }
 
 // Head returns the first node in the import chain
@@ -144,18 +153,33 @@ func validateComponentCompose(c v1alpha1.ZarfComponent) error {
 
 	// validation for url
 	if url != "" && path == "" {
-		ok := helpers.IsOCIURL(url)
-		if !ok {
-			err = errors.Join(err, errors.New("URL is not a valid OCI URL"))
+		if isGitImport(url) {
+			if _, _, gitErr := splitGitImport(url); gitErr != nil {
+				err = errors.Join(err, gitErr)
+			}
+		} else if !helpers.IsOCIURL(url) {
+			err = errors.Join(err, errors.New("URL is not a valid OCI or git URL"))
 		}
 	}
 
+	// validation for importing every component
+	if c.Import.Components != "" && c.Import.Components != ImportAllComponents {
+		err = errors.Join(err, fmt.Errorf("components must be %q to import every component", ImportAllComponents))
+	}
+	if c.Import.Components != "" && c.Import.Name != "" {
+		err = errors.Join(err, errors.New("both a name and every component were provided"))
+	}
+	if c.Import.Components == "" && c.Import.Prefix != "" {
+		err = errors.Join(err, errors.New("a prefix was provided without importing every component"))
+	}
+
 	return err
 }
 
-// NewImportChain creates a new import chain from a component
+// NewImportChain creates a new import chain from a component, matching imported components against the given flavors
+// as resolved by ZarfPackage.ResolveFlavor and the package templates given on create.
 // Returning the chain on error so we can have additional information to use during lint
-func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int, originalPackageName, arch, flavor string) (*ImportChain, error) {
+func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int, originalPackageName, arch string, flavors []string, variables map[string]string) (*ImportChain, error) {
 	ic := &ImportChain{}
 	if arch == "" {
 		return ic, fmt.Errorf("cannot build import chain: architecture must be provided")
@@ -168,9 +192,10 @@ func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int,
 	node := ic.head
 	for node != nil {
 		isLocal := node.Import.Path != ""
-		isRemote := node.Import.URL != ""
+		isGit := isGitImport(node.Import.URL)
+		isRemote := node.Import.URL != "" && !isGit
 
-		if !isLocal && !isRemote {
+		if !isLocal && !isGit && !isRemote {
 			// This is the end of the import chain,
 			// as the current node/component is not importing anything
 			return ic, nil
@@ -180,22 +205,52 @@ func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int,
 			return nil, fmt.Errorf("invalid imported definition for %s: %w", node.Name, err)
 		}
 
+		// components importing every component are expanded by ExpandComponent before their chain is built
+		if node.Import.Components != "" {
+			return ic, fmt.Errorf("detected malformed import chain, cannot import every component of a package from an imported component")
+		}
+
 		// ensure that remote components are not importing other remote components
-		if node.prev != nil && node.prev.Import.URL != "" && isRemote {
+		fromRemote := node.prev != nil && helpers.IsOCIURL(node.prev.Import.URL)
+		if fromRemote && (isRemote || isGit) {
 			return ic, fmt.Errorf("detected malformed import chain, cannot import remote components from remote components")
 		}
 		// ensure that remote components are not importing local components
-		if node.prev != nil && node.prev.Import.URL != "" && isLocal {
+		if fromRemote && isLocal {
 			return ic, fmt.Errorf("detected malformed import chain, cannot import local components from remote components")
 		}
 
 		var pkg v1alpha1.ZarfPackage
 
 		var relativeToHead string
-		var importURL string
-		if isLocal {
-			history = append(history, node.Import.Path)
-			relativeToHead = filepath.Join(history...)
+		var location string
+		if isLocal || isGit {
+			if isLocal {
+				importPath, err := workspace.ResolveImportPath(node.relativeToHead, node.Import.Path)
+				if err != nil {
+					return ic, err
+				}
+				history = append(history, importPath)
+				relativeToHead = filepath.Join(history...)
+				location = relativeToHead
+			} else {
+				dir, err := resolveGitImport(ctx, node.Import.URL)
+				if err != nil {
+					return ic, err
+				}
+				cwd, err := os.Getwd()
+				if err != nil {
+					return ic, err
+				}
+				// like remote components, the clone is based upon cwd<->cache and local imports within it are based upon
+				// the clone
+				relativeToHead, err = filepath.Rel(cwd, dir)
+				if err != nil {
+					return ic, err
+				}
+				history = []string{relativeToHead}
+				location = node.Import.URL
+			}
 
 			// prevent circular imports (including self-imports)
 			// this is O(n^2) but the import chain should be small
@@ -212,12 +267,9 @@ func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int,
 				return ic, err
 			}
 		} else if isRemote {
-			importURL = node.Import.URL
-			remote, err := ic.getRemote(ctx, node.Import.URL)
-			if err != nil {
-				return ic, err
-			}
-			pkg, err = remote.FetchZarfYAML(ctx)
+			location = node.Import.URL
+			var err error
+			pkg, err = ic.fetchZarfYAML(ctx, node.Import.URL)
 			if err != nil {
 				return ic, err
 			}
@@ -229,27 +281,24 @@ func NewImportChain(ctx context.Context, head v1alpha1.ZarfComponent, index int,
 		// found[0] and pkg[index[0]] would be the same component for example
 		found := []v1alpha1.ZarfComponent{}
 		index := []int{}
+		overridden := OverriddenComponents(pkg.Components, flavors)
 		for i, component := range pkg.Components {
-			if component.Name == name && CompatibleComponent(component, arch, flavor) {
+			if component.Name != name {
+				continue
+			}
+			if err := ValidateExpression(component.Only.Expression); err != nil {
+				return ic, fmt.Errorf("component %q in %q: %w", name, location, err)
+			}
+			if CompatibleComponent(component, arch, flavors, variables) && !overridden[i] {
 				found = append(found, component)
 				index = append(index, i)
 			}
 		}
 
 		if len(found) == 0 {
-			componentNotFound := "component %q not found in %q"
-			if isLocal {
-				return ic, fmt.Errorf(componentNotFound, name, relativeToHead)
-			} else if isRemote {
-				return ic, fmt.Errorf(componentNotFound, name, importURL)
-			}
+			return ic, fmt.Errorf("component %q not found in %q", name, location)
 		} else if len(found) > 1 {
-			multipleComponentsFound := "multiple components named %q found in %q satisfying %q"
-			if isLocal {
-				return ic, fmt.Errorf(multipleComponentsFound, name, relativeToHead, arch)
-			} else if isRemote {
-				return ic, fmt.Errorf(multipleComponentsFound, name, importURL, arch)
-			}
+			return ic, fmt.Errorf("multiple components named %q found in %q satisfying %q", name, location, arch)
 		}
 
 		ic.append(found[0], index[0], pkg.Metadata.Name, relativeToHead, pkg.Variables, pkg.Constants)
@@ -310,9 +359,13 @@ func (ic *ImportChain) Migrate(build v1alpha1.ZarfBuildData) (warnings []string)
 // fixing paths, overriding metadata, etc
 func (ic *ImportChain) Compose(ctx context.Context) (composed *v1alpha1.ZarfComponent, err error) {
 	composed = &ic.tail.ZarfComponent
+	ic.provenance = map[string]Origin{}
 
 	if ic.tail.prev == nil {
 		// only had one component in the import chain
+		if _, err := ic.recordProvenance(composed, ic.tail, nil); err != nil {
+			return nil, err
+		}
 		return composed, nil
 	}
 
@@ -324,6 +377,7 @@ func (ic *ImportChain) Compose(ctx context.Context) (composed *v1alpha1.ZarfComp
 	composed = &v1alpha1.ZarfComponent{}
 
 	// start overriding with the tail node
+	seen := map[string]flatValue{}
 	node := ic.tail
 	for node != nil {
 		fixPaths(&node.ZarfComponent, node.relativeToHead)
@@ -335,12 +389,21 @@ func (ic *ImportChain) Compose(ctx context.Context) (composed *v1alpha1.ZarfComp
 		}
 
 		overrideDeprecated(composed, node.ZarfComponent)
-		overrideResources(composed, node.ZarfComponent)
+		if err := overrideResources(composed, node.ZarfComponent); err != nil {
+			return nil, err
+		}
 		overrideActions(composed, node.ZarfComponent)
 		composed.HealthChecks = append(composed.HealthChecks, node.ZarfComponent.HealthChecks...)
+		composed.Readiness = append(composed.Readiness, node.ZarfComponent.Readiness...)
+		composed.Connect = append(composed.Connect, node.ZarfComponent.Connect...)
 
 		bigbang.Compose(composed, node.ZarfComponent, node.relativeToHead)
 
+		seen, err = ic.recordProvenance(composed, node, seen)
+		if err != nil {
+			return nil, err
+		}
+
 		node = node.prev
 	}
 
@@ -382,8 +445,53 @@ func (ic *ImportChain) MergeConstants(existing []v1alpha1.Constant) (merged []v1
 }
 
 // CompatibleComponent determines if this component is compatible with the given create options
-func CompatibleComponent(c v1alpha1.ZarfComponent, arch, flavor string) bool {
+//
+// Components with an invalid expression are never compatible, use ValidateExpression to find them.
+func CompatibleComponent(c v1alpha1.ZarfComponent, arch string, flavors []string, variables map[string]string) bool {
 	satisfiesArch := c.Only.Cluster.Architecture == "" || c.Only.Cluster.Architecture == arch
-	satisfiesFlavor := c.Only.Flavor == "" || c.Only.Flavor == flavor
-	return satisfiesArch && satisfiesFlavor
+	satisfiesFlavor := c.Only.Flavor.Matches(flavors)
+	satisfiesExpression := true
+	if c.Only.Expression != "" {
+		env := ExpressionEnv{Arch: arch, Variables: variables}
+		if len(flavors) > 0 {
+			env.Flavor = flavors[0]
+		}
+		satisfiesExpression, _ = EvaluateExpression(c.Only.Expression, env)
+	}
+	return satisfiesArch && satisfiesFlavor && satisfiesExpression
+}
+
+// OverriddenComponents returns the indexes of the components that are overridden by a component with the same name
+// and architecture that is limited to a more specific flavor, where flavors are ordered from the most to the least
+// specific.
+func OverriddenComponents(components []v1alpha1.ZarfComponent, flavors []string) map[int]bool {
+	rank := func(c v1alpha1.ZarfComponent) int {
+		best := -1
+		for _, flavor := range c.Only.Flavor {
+			if i := slices.Index(flavors, flavor); i >= 0 && (best < 0 || i < best) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	key := func(c v1alpha1.ZarfComponent) string {
+		return c.Name + "/" + c.Only.Cluster.Architecture
+	}
+	mostSpecific := map[string]int{}
+	for _, c := range components {
+		if r := rank(c); r >= 0 {
+			if best, ok := mostSpecific[key(c)]; !ok || r < best {
+				mostSpecific[key(c)] = r
+			}
+		}
+	}
+
+	overridden := map[int]bool{}
+	for i, c := range components {
+		if r := rank(c); r >= 0 && r > mostSpecific[key(c)] {
+			overridden[i] = true
+		}
+	}
+	return overridden
 }
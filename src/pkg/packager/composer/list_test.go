@@ -24,7 +24,7 @@ func TestNewImportChain(t *testing.T) {
 		name        string
 		head        v1alpha1.ZarfComponent
 		arch        string
-		flavor      string
+		flavors     []string
 		expectedErr string
 	}{
 		{
@@ -49,7 +49,7 @@ func TestNewImportChain(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			_, err := NewImportChain(context.Background(), tt.head, 0, testPackageName, tt.arch, tt.flavor)
+			_, err := NewImportChain(context.Background(), tt.head, 0, testPackageName, tt.arch, tt.flavors, nil)
 			require.ErrorContains(t, err, tt.expectedErr)
 		})
 	}
@@ -126,6 +126,60 @@ func TestCompose(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Timeouts",
+			ic: createChainFromSlice(t, []v1alpha1.ZarfComponent{
+				{
+					Name:    "base",
+					Timeout: "30m",
+					Charts: []v1alpha1.ZarfChart{
+						{
+							Name:    "database",
+							Timeout: "20m",
+						},
+					},
+					Manifests: []v1alpha1.ZarfManifest{
+						{
+							Name: "config",
+						},
+					},
+				},
+				{
+					Name:    "import-one",
+					Timeout: "5m",
+					Charts: []v1alpha1.ZarfChart{
+						{
+							Name:    "database",
+							URL:     "oci://ghcr.io/example/charts/database",
+							Timeout: "10m",
+						},
+					},
+					Manifests: []v1alpha1.ZarfManifest{
+						{
+							Name:    "config",
+							Timeout: "1m",
+						},
+					},
+				},
+			}),
+			expectedComposed: v1alpha1.ZarfComponent{
+				Name:    "base",
+				Timeout: "30m",
+				Charts: []v1alpha1.ZarfChart{
+					{
+						Name:    "database",
+						URL:     "oci://ghcr.io/example/charts/database",
+						Timeout: "20m",
+					},
+				},
+				Manifests: []v1alpha1.ZarfManifest{
+					{
+						Name:    "config",
+						Timeout: "1m",
+					},
+				},
+			},
+		},
 		{
 			name: "Multiple Components",
 			ic: createChainFromSlice(t, []v1alpha1.ZarfComponent{
@@ -487,6 +541,23 @@ func TestMerging(t *testing.T) {
 	}
 }
 
+func TestComposePolicyGateEngines(t *testing.T) {
+	t.Parallel()
+
+	ic := createChainFromSlice(t, []v1alpha1.ZarfComponent{
+		{
+			Name:        "base",
+			PolicyGates: []v1alpha1.ZarfPolicyGate{{Name: "labels", Engine: v1alpha1.PolicyGateEngineRego, Files: []string{"labels.rego"}}},
+		},
+		{
+			Name:        "import",
+			PolicyGates: []v1alpha1.ZarfPolicyGate{{Name: "labels", Engine: v1alpha1.PolicyGateEngineCEL, Files: []string{"labels.yaml"}}},
+		},
+	})
+	_, err := ic.Compose(context.Background())
+	require.EqualError(t, err, `policy gate "labels" of component "base" uses the cel engine and can not be merged with a rego gate of the same name`)
+}
+
 func createChainFromSlice(t *testing.T, components []v1alpha1.ZarfComponent) (ic *ImportChain) {
 	t.Helper()
 
@@ -684,7 +755,33 @@ func TestValidateZarfComponent(t *testing.T) {
 				},
 			},
 			expectedErrs: []string{
-				"URL is not a valid OCI URL",
+				"URL is not a valid OCI or git URL",
+			},
+		},
+		{
+			name: "invalid components provided",
+			component: v1alpha1.ZarfComponent{
+				Name: "bad-components",
+				Import: v1alpha1.ZarfComponentImport{
+					Path:       "relative/path",
+					Components: "prometheus",
+				},
+			},
+			expectedErrs: []string{
+				`components must be "*" to import every component`,
+			},
+		},
+		{
+			name: "prefix without every component provided",
+			component: v1alpha1.ZarfComponent{
+				Name: "prefix-only",
+				Import: v1alpha1.ZarfComponentImport{
+					Path:   "relative/path",
+					Prefix: "mon-",
+				},
+			},
+			expectedErrs: []string{
+				"a prefix was provided without importing every component",
 			},
 		},
 	}
@@ -5,24 +5,75 @@
 package composer
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/defenseunicorns/pkg/oci"
 	"github.com/mholt/archiver/v3"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
+	"oras.land/oras-go/v2/content"
 	ocistore "oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry"
 )
 
+// skeletonCache returns the directory and store that the manifests, zarf.yaml and component tarballs of remote skeleton
+// packages are cached in by digest.
+func skeletonCache() (string, *ocistore.Store, error) {
+	cache := filepath.Join(config.GetAbsCachePath(), "oci")
+	if err := helpers.CreateDirectory(cache, helpers.ReadWriteExecuteUser); err != nil {
+		return "", nil, err
+	}
+	store, err := ocistore.New(cache)
+	if err != nil {
+		return "", nil, err
+	}
+	return cache, store, nil
+}
+
+// cachedFetcher returns a fetcher that reads content from the skeleton cache and fetches the content that is not cached
+// yet, or all content when caching is disabled with --no-cache, from the remote of the import chain into the cache.
+func (ic *ImportChain) cachedFetcher(url string, store *ocistore.Store) func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+	return func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+		if !config.CommonOptions.NoCache {
+			exists, err := store.Exists(ctx, desc)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				message.Debugf("Using %s of %q from the cache", desc.Digest, url)
+				return content.FetchAll(ctx, store, desc)
+			}
+		}
+		remote, err := ic.getRemote(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		b, err := remote.FetchLayer(ctx, desc)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Push(ctx, desc, bytes.NewReader(b)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+			return nil, err
+		}
+		return b, nil
+	}
+}
+
 func (ic *ImportChain) getRemote(ctx context.Context, url string) (*zoci.Remote, error) {
 	if ic.remote != nil {
 		return ic.remote, nil
@@ -39,10 +90,85 @@ func (ic *ImportChain) getRemote(ctx context.Context, url string) (*zoci.Remote,
 	return ic.remote, nil
 }
 
+// fetchRoot returns the root manifest of the remote skeleton package, which is cached by the digest the URL currently
+// resolves to.
+//
+// Skeleton packages referenced by the digest of a cached manifest are reused without contacting the registry.
+func (ic *ImportChain) fetchRoot(ctx context.Context, url string) (*oci.Manifest, error) {
+	if ic.root != nil {
+		return ic.root, nil
+	}
+	_, store, err := skeletonCache()
+	if err != nil {
+		return nil, err
+	}
+	desc, ok := cachedRoot(ctx, store, url)
+	if !ok {
+		remote, err := ic.getRemote(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		desc, err = remote.ResolveRoot(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ic.root, err = oci.FetchUnmarshal[*oci.Manifest](ctx, ic.cachedFetcher(url, store), json.Unmarshal, desc)
+	if err != nil {
+		return nil, err
+	}
+	ic.rootDesc = desc
+	return ic.root, nil
+}
+
+// cachedRoot returns the descriptor of the cached manifest that the URL references by digest.
+func cachedRoot(ctx context.Context, store *ocistore.Store, url string) (ocispec.Descriptor, bool) {
+	if config.CommonOptions.NoCache {
+		return ocispec.Descriptor{}, false
+	}
+	ref, err := registry.ParseReference(strings.TrimPrefix(url, helpers.OCIURLPrefix))
+	if err != nil || ref.ValidateReferenceAsDigest() != nil {
+		return ocispec.Descriptor{}, false
+	}
+	desc, err := store.Resolve(ctx, ref.Reference)
+	if err != nil || desc.MediaType != ocispec.MediaTypeImageManifest {
+		return ocispec.Descriptor{}, false
+	}
+	return desc, true
+}
+
+// fetchZarfYAML returns the zarf.yaml of the remote skeleton package from the cache.
+func (ic *ImportChain) fetchZarfYAML(ctx context.Context, url string) (v1alpha1.ZarfPackage, error) {
+	manifest, err := ic.fetchRoot(ctx, url)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, err
+	}
+	_, store, err := skeletonCache()
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, err
+	}
+	return oci.FetchYAMLFile[v1alpha1.ZarfPackage](ctx, ic.cachedFetcher(url, store), manifest, layout.ZarfYAML)
+}
+
 // ContainsOCIImport returns true if the import chain contains a remote import
 func (ic *ImportChain) ContainsOCIImport() bool {
 	// only the 2nd to last node may have a remote import
-	return ic.tail.prev != nil && ic.tail.prev.Import.URL != ""
+	return ic.tail.prev != nil && helpers.IsOCIURL(ic.tail.prev.Import.URL)
+}
+
+// OCIImport returns the URL and the digest of the manifest the remote skeleton import of the chain was composed from,
+// rather than what the URL resolves to again afterwards.
+//
+// An empty URL is returned if the chain does not contain a remote import.
+func (ic *ImportChain) OCIImport(ctx context.Context) (string, string, error) {
+	if !ic.ContainsOCIImport() {
+		return "", "", nil
+	}
+	url := ic.tail.prev.Import.URL
+	if _, err := ic.fetchRoot(ctx, url); err != nil {
+		return "", "", err
+	}
+	return url, ic.rootDesc.Digest.String(), nil
 }
 
 func (ic *ImportChain) fetchOCISkeleton(ctx context.Context) error {
@@ -50,12 +176,7 @@ func (ic *ImportChain) fetchOCISkeleton(ctx context.Context) error {
 		return nil
 	}
 	node := ic.tail.prev
-	remote, err := ic.getRemote(ctx, node.Import.URL)
-	if err != nil {
-		return err
-	}
-
-	manifest, err := remote.FetchRoot(ctx)
+	manifest, err := ic.fetchRoot(ctx, node.Import.URL)
 	if err != nil {
 		return err
 	}
@@ -64,8 +185,8 @@ func (ic *ImportChain) fetchOCISkeleton(ctx context.Context) error {
 
 	componentDesc := manifest.Locate(filepath.Join(layout.ComponentsDir, fmt.Sprintf("%s.tar", name)))
 
-	cache := filepath.Join(config.GetAbsCachePath(), "oci")
-	if err := helpers.CreateDirectory(cache, helpers.ReadWriteExecuteUser); err != nil {
+	cache, store, err := skeletonCache()
+	if err != nil {
 		return err
 	}
 
@@ -85,16 +206,22 @@ func (ic *ImportChain) fetchOCISkeleton(ctx context.Context) error {
 		tb = filepath.Join(cache, "blobs", "sha256", componentDesc.Digest.Encoded())
 		dir = filepath.Join(cache, "dirs", componentDesc.Digest.Encoded())
 
-		store, err := ocistore.New(cache)
-		if err != nil {
-			return err
-		}
-
 		// ensure the tarball is in the cache
 		exists, err := store.Exists(ctx, componentDesc)
 		if err != nil {
 			return err
-		} else if !exists {
+		}
+		if exists && config.CommonOptions.NoCache {
+			if err := store.Delete(ctx, componentDesc); err != nil {
+				return err
+			}
+			exists = false
+		}
+		if !exists {
+			remote, err := ic.getRemote(ctx, node.Import.URL)
+			if err != nil {
+				return err
+			}
 			doneSaving := make(chan error)
 			successText := fmt.Sprintf("Pulling %q", helpers.OCIURLPrefix+remote.Repo().Reference.String())
 			go utils.RenderProgressBarForLocalDirWrite(cache, componentDesc.Size, doneSaving, "Pulling", successText)
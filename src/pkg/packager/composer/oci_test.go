@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package composer contains functions for composing components within Zarf packages.
+package composer
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/zoci"
+)
+
+func TestSkeletonCache(t *testing.T) {
+	cachePath, plainHTTP := config.CommonOptions.CachePath, config.CommonOptions.PlainHTTP
+	config.CommonOptions.CachePath = t.TempDir()
+	config.CommonOptions.PlainHTTP = true
+	t.Cleanup(func() {
+		config.CommonOptions.CachePath, config.CommonOptions.PlainHTTP = cachePath, plainHTTP
+		config.CommonOptions.NoCache = false
+	})
+
+	ctx := context.Background()
+	reg := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(reg.Close)
+
+	// publish a skeleton package with a single component
+	url := "oci://" + strings.TrimPrefix(reg.URL, "http://") + "/skeleton:0.0.1"
+	remote, err := zoci.NewRemote(url, zoci.PlatformForSkeleton())
+	require.NoError(t, err)
+	zarfYAML := filepath.Join(t.TempDir(), layout.ZarfYAML)
+	require.NoError(t, os.WriteFile(zarfYAML, []byte("kind: ZarfPackageConfig\nmetadata:\n  name: skeleton\ncomponents:\n  - name: nginx\n"), 0o600))
+	desc, err := remote.PushFile(ctx, layout.ZarfYAML, zarfYAML)
+	require.NoError(t, err)
+	pkg := v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Name: "skeleton"}}
+	require.NoError(t, remote.PublishPackageLayers(ctx, &pkg, []ocispec.Descriptor{desc}))
+	root, err := remote.ResolveRoot(ctx)
+	require.NoError(t, err)
+
+	head := v1alpha1.ZarfComponent{
+		Name:   "nginx",
+		Import: v1alpha1.ZarfComponentImport{URL: url},
+	}
+	chain, err := NewImportChain(ctx, head, 0, "test-package", "amd64", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "skeleton", chain.Tail().OriginalPackageName())
+
+	// the skeleton is reused by digest without contacting the registry
+	reg.Close()
+	head.Import.URL = strings.TrimSuffix(url, ":0.0.1") + "@" + root.Digest.String()
+	chain, err = NewImportChain(ctx, head, 0, "test-package", "amd64", nil, nil)
+	require.NoError(t, err)
+	_, err = chain.Compose(ctx)
+	require.NoError(t, err)
+
+	config.CommonOptions.NoCache = true
+	_, err = NewImportChain(ctx, head, 0, "test-package", "amd64", nil, nil)
+	require.ErrorContains(t, err, "published skeleton package for")
+}
@@ -14,12 +14,24 @@ func overrideMetadata(c *v1alpha1.ZarfComponent, override v1alpha1.ZarfComponent
 	c.Name = override.Name
 	c.Default = override.Default
 	c.Required = override.Required
+	// Dependencies name components of the package of the override, so they are not kept from imported components.
+	c.DependsOn = override.DependsOn
 
 	// Override description if it was provided.
 	if override.Description != "" {
 		c.Description = override.Description
 	}
 
+	// Override entitlement if it was provided.
+	if override.Entitlement != "" {
+		c.Entitlement = override.Entitlement
+	}
+
+	// Override timeout if it was provided.
+	if override.Timeout != "" {
+		c.Timeout = override.Timeout
+	}
+
 	if override.Only.LocalOS != "" {
 		if c.Only.LocalOS != "" {
 			return fmt.Errorf("component %q: \"only.localOS\" %q cannot be redefined as %q during compose", c.Name, c.Only.LocalOS, override.Only.LocalOS)
@@ -77,10 +89,11 @@ func overrideActions(c *v1alpha1.ZarfComponent, override v1alpha1.ZarfComponent)
 	c.Actions.OnRemove.OnSuccess = append(c.Actions.OnRemove.OnSuccess, override.Actions.OnRemove.OnSuccess...)
 }
 
-func overrideResources(c *v1alpha1.ZarfComponent, override v1alpha1.ZarfComponent) {
+func overrideResources(c *v1alpha1.ZarfComponent, override v1alpha1.ZarfComponent) error {
 	c.DataInjections = append(c.DataInjections, override.DataInjections...)
 	c.Files = append(c.Files, override.Files...)
 	c.Images = append(c.Images, override.Images...)
+	c.Artifacts = append(c.Artifacts, override.Artifacts...)
 	c.Repos = append(c.Repos, override.Repos...)
 
 	// Merge charts with the same name to keep them unique
@@ -94,6 +107,9 @@ func overrideResources(c *v1alpha1.ZarfComponent, override v1alpha1.ZarfComponen
 				if overrideChart.ReleaseName != "" {
 					c.Charts[idx].ReleaseName = overrideChart.ReleaseName
 				}
+				if overrideChart.Timeout != "" {
+					c.Charts[idx].Timeout = overrideChart.Timeout
+				}
 				c.Charts[idx].ValuesFiles = append(c.Charts[idx].ValuesFiles, overrideChart.ValuesFiles...)
 				c.Charts[idx].Variables = append(c.Charts[idx].Variables, overrideChart.Variables...)
 				existing = true
@@ -113,6 +129,9 @@ func overrideResources(c *v1alpha1.ZarfComponent, override v1alpha1.ZarfComponen
 				if overrideManifest.Namespace != "" {
 					c.Manifests[idx].Namespace = overrideManifest.Namespace
 				}
+				if overrideManifest.Timeout != "" {
+					c.Manifests[idx].Timeout = overrideManifest.Timeout
+				}
 				c.Manifests[idx].Files = append(c.Manifests[idx].Files, overrideManifest.Files...)
 				c.Manifests[idx].Kustomizations = append(c.Manifests[idx].Kustomizations, overrideManifest.Kustomizations...)
 
@@ -124,4 +143,45 @@ func overrideResources(c *v1alpha1.ZarfComponent, override v1alpha1.ZarfComponen
 			c.Manifests = append(c.Manifests, overrideManifest)
 		}
 	}
+
+	// Merge policies with the same name to keep them unique
+	for _, overridePolicy := range override.Policies {
+		existing := false
+		for idx := range c.Policies {
+			if c.Policies[idx].Name == overridePolicy.Name {
+				if overridePolicy.Namespace != "" {
+					c.Policies[idx].Namespace = overridePolicy.Namespace
+				}
+				c.Policies[idx].Files = append(c.Policies[idx].Files, overridePolicy.Files...)
+
+				existing = true
+			}
+		}
+
+		if !existing {
+			c.Policies = append(c.Policies, overridePolicy)
+		}
+	}
+
+	// Merge policy gates with the same name to keep them unique
+	for _, overrideGate := range override.PolicyGates {
+		existing := false
+		for idx := range c.PolicyGates {
+			if c.PolicyGates[idx].Name == overrideGate.Name {
+				// The files of a gate are all loaded by its engine, so gates of different engines can not be merged.
+				if c.PolicyGates[idx].Engine != overrideGate.Engine {
+					return fmt.Errorf("policy gate %q of component %q uses the %s engine and can not be merged with a %s gate of the same name",
+						overrideGate.Name, c.Name, c.PolicyGates[idx].Engine, overrideGate.Engine)
+				}
+				c.PolicyGates[idx].Files = append(c.PolicyGates[idx].Files, overrideGate.Files...)
+
+				existing = true
+			}
+		}
+
+		if !existing {
+			c.PolicyGates = append(c.PolicyGates, overrideGate)
+		}
+	}
+	return nil
 }
@@ -52,6 +52,20 @@ func fixPaths(child *v1alpha1.ZarfComponent, relativeToHead string) {
 		}
 	}
 
+	for policyIdx, policy := range child.Policies {
+		for fileIdx, file := range policy.Files {
+			composed := makePathRelativeTo(file, relativeToHead)
+			child.Policies[policyIdx].Files[fileIdx] = composed
+		}
+	}
+
+	for gateIdx, gate := range child.PolicyGates {
+		for fileIdx, file := range gate.Files {
+			composed := makePathRelativeTo(file, relativeToHead)
+			child.PolicyGates[gateIdx].Files[fileIdx] = composed
+		}
+	}
+
 	for dataInjectionsIdx, dataInjection := range child.DataInjections {
 		composed := makePathRelativeTo(dataInjection.Source, relativeToHead)
 		child.DataInjections[dataInjectionsIdx].Source = composed
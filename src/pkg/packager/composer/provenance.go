@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// Origin is the package in an import chain that a composed value came from.
+type Origin struct {
+	PackageName string
+	Location    string
+}
+
+// String returns a human readable representation of the origin.
+func (o Origin) String() string {
+	if o.Location == "" {
+		return fmt.Sprintf("from %s", o.PackageName)
+	}
+	return fmt.Sprintf("from %s (%s)", o.PackageName, o.Location)
+}
+
+// Provenance returns the origin of each value in the composed component keyed by its path within the component
+// (e.g. "images[0]" or "charts[1].valuesFiles[0]"), it is only populated once the chain has been composed.
+func (ic *ImportChain) Provenance() map[string]Origin {
+	return ic.provenance
+}
+
+// flatValue is a value in a component flattened by its path.
+type flatValue struct {
+	value     string
+	container bool
+}
+
+var simpleKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// flatten returns every value within v keyed by its path.
+func flatten(v any) (map[string]flatValue, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	flat := map[string]flatValue{}
+	flattenInto(flat, "", doc)
+	return flat, nil
+}
+
+// flattenInto adds v and its children to flat, skipping empty lists and objects since they are omitted from the
+// composed definition.
+func flattenInto(flat map[string]flatValue, path string, v any) bool {
+	found := false
+	switch t := v.(type) {
+	case map[string]any:
+		for key, child := range t {
+			// Keys that would need quoting in a path (e.g. chart values) are attributed to their parent.
+			if !simpleKey.MatchString(key) {
+				continue
+			}
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if flattenInto(flat, childPath, child) {
+				found = true
+			}
+		}
+	case []any:
+		for idx, child := range t {
+			if flattenInto(flat, fmt.Sprintf("%s[%d]", path, idx), child) {
+				found = true
+			}
+		}
+	default:
+		flat[path] = flatValue{value: fmt.Sprint(t)}
+		return true
+	}
+	if found && path != "" {
+		flat[path] = flatValue{container: true}
+	}
+	return found
+}
+
+// recordProvenance attributes the values that node added to or changed in the composed component since the last
+// record to node.
+//
+// Lists and objects keep the origin of the node that first added them while scalars take the origin of the last node
+// that changed them.
+func (ic *ImportChain) recordProvenance(composed *v1alpha1.ZarfComponent, node *Node, seen map[string]flatValue) (map[string]flatValue, error) {
+	current, err := flatten(composed)
+	if err != nil {
+		return nil, err
+	}
+	origin := Origin{PackageName: node.OriginalPackageName(), Location: node.ImportLocation()}
+	for path, v := range current {
+		prev, ok := seen[path]
+		if !ok || (!v.container && prev != v) {
+			ic.provenance[path] = origin
+		}
+	}
+	// Drop values that a later override removed.
+	for path := range ic.provenance {
+		if _, ok := current[path]; !ok {
+			delete(ic.provenance, path)
+		}
+	}
+	return current, nil
+}
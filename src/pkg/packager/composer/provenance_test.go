@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package composer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestProvenance(t *testing.T) {
+	t.Parallel()
+
+	head := Origin{PackageName: "test-package", Location: "."}
+	first := Origin{PackageName: "test-package", Location: "hello"}
+	second := Origin{PackageName: "test-package", Location: "hello/world"}
+
+	tests := []struct {
+		name     string
+		ic       *ImportChain
+		expected map[string]Origin
+	}{
+		{
+			name: "Single Component",
+			ic: createChainFromSlice(t, []v1alpha1.ZarfComponent{
+				{
+					Name:   "no-import",
+					Images: []string{"nginx"},
+				},
+			}),
+			expected: map[string]Origin{
+				"name":      head,
+				"images":    head,
+				"images[0]": head,
+			},
+		},
+		{
+			name: "Multiple Components",
+			ic: createChainFromSlice(t, []v1alpha1.ZarfComponent{
+				{
+					Name:   "base",
+					Import: v1alpha1.ZarfComponentImport{Path: "hello"},
+					Images: []string{"base-image"},
+				},
+				{
+					Name:        "first",
+					Description: "first description",
+					Import:      v1alpha1.ZarfComponentImport{Path: "world"},
+					Charts: []v1alpha1.ZarfChart{
+						{Name: "podinfo", Namespace: "first-ns", ValuesFiles: []string{"values.yaml"}},
+					},
+				},
+				{
+					Name:        "second",
+					Description: "second description",
+					Images:      []string{"second-image"},
+					Charts: []v1alpha1.ZarfChart{
+						{Name: "podinfo", Namespace: "second-ns", ValuesFiles: []string{"values.yaml"}},
+					},
+				},
+			}),
+			expected: map[string]Origin{
+				"name":                     head,
+				"description":              first,
+				"images":                   second,
+				"images[0]":                second,
+				"images[1]":                head,
+				"charts":                   second,
+				"charts[0]":                second,
+				"charts[0].name":           second,
+				"charts[0].namespace":      first,
+				"charts[0].valuesFiles":    second,
+				"charts[0].valuesFiles[0]": second,
+				"charts[0].valuesFiles[1]": first,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tt.ic.Compose(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tt.ic.Provenance())
+		})
+	}
+}
+
+func TestOriginString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "from podinfo", Origin{PackageName: "podinfo"}.String())
+	require.Equal(t, "from podinfo (oci://ghcr.io/stefanprodan/podinfo:1.0.0)", Origin{PackageName: "podinfo", Location: "oci://ghcr.io/stefanprodan/podinfo:1.0.0"}.String())
+}
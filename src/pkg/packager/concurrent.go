@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// concurrentDeploy tracks the components of a concurrent deployment, all of its fields but recordMu are guarded by mu.
+type concurrentDeploy struct {
+	mu                 sync.Mutex
+	generation         int
+	deployedComponents []types.DeployedComponent
+	running            []string
+	// recordMu orders the writes of the package secret, so that an older copy of the deployed components never
+	// replaces a newer one.
+	recordMu sync.Mutex
+}
+
+// deployComponentsConcurrently deploys up to the configured concurrency of components at the same time, starting each
+// component once the components it depends on have been deployed.
+//
+// Every component is deployed by a copy of the packager with its own variables, so that the templates and variables
+// of one component are not seen by the others until it has been deployed.
+func (p *Packager) deployComponentsConcurrently(ctx context.Context) ([]types.DeployedComponent, error) {
+	cd := &concurrentDeploy{generation: 1}
+
+	if slices.ContainsFunc(p.cfg.Pkg.Components, v1alpha1.ZarfComponent.RequiresCluster) {
+		connectCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		if err := p.connectToCluster(connectCtx); err != nil {
+			return nil, fmt.Errorf("unable to connect to the Kubernetes cluster: %w", err)
+		}
+		if err := p.runPreflightChecks(ctx); err != nil {
+			return nil, err
+		}
+		if p.state == nil {
+			if err := p.setupState(ctx); err != nil {
+				return nil, err
+			}
+		}
+		// If this package has been deployed before, increment the package generation within the secret
+		if existingDeployedPackage, _ := p.cluster.GetDeployedPackage(ctx, p.cfg.Pkg.Metadata.Name); existingDeployedPackage != nil {
+			cd.generation = existingDeployedPackage.Generation + 1
+		}
+	}
+
+	// Spinners of components deploying at the same time would overwrite each other.
+	noProgress := message.NoProgress
+	message.NoProgress = true
+	defer func() {
+		message.NoProgress = noProgress
+	}()
+
+	done := map[string]chan struct{}{}
+	for _, component := range p.cfg.Pkg.Components {
+		done[component.Name] = make(chan struct{})
+	}
+	sem := make(chan struct{}, p.cfg.DeployOpts.Concurrency)
+	g, gCtx := errgroup.WithContext(ctx)
+	for _, component := range p.cfg.Pkg.Components {
+		g.Go(func() error {
+			for _, dep := range component.DependsOn {
+				// Dependencies that were not selected for the deployment are not waited on.
+				depDone, ok := done[dep]
+				if !ok {
+					continue
+				}
+				select {
+				case <-depDone:
+				case <-gCtx.Done():
+					return gCtx.Err()
+				}
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-gCtx.Done():
+				return gCtx.Err()
+			}
+			defer func() {
+				<-sem
+			}()
+
+			if err := p.deployComponentConcurrently(gCtx, cd, component); err != nil {
+				return err
+			}
+			close(done[component.Name])
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return cd.deployedComponents, nil
+}
+
+// deployComponentConcurrently deploys a component with a copy of the packager and records its progress.
+func (p *Packager) deployComponentConcurrently(ctx context.Context, cd *concurrentDeploy, component v1alpha1.ZarfComponent) error {
+	start := time.Now()
+
+	cd.mu.Lock()
+	if err := p.recordRollbackPoint(ctx, component); err != nil {
+		cd.mu.Unlock()
+		return err
+	}
+	cp := *p
+	cp.variableConfig = p.variableConfig.Clone()
+	cp.pushedImages, cp.pushedRepos = nil, nil
+	// The deploy status is published for the copies by deployComponentsConcurrently.
+	cp.deployStatus = nil
+
+	deployedComponent := types.DeployedComponent{
+		Name:               component.Name,
+		Status:             types.ComponentStatusDeploying,
+		ObservedGeneration: cd.generation,
+	}
+	// Ensure we don't overwrite any installedCharts data when updating the package secret
+	if p.isConnectedToCluster() {
+		installedCharts, err := p.cluster.GetInstalledChartsForComponent(ctx, p.cfg.Pkg.Metadata.Name, component)
+		if err != nil {
+			message.Debugf("Unable to fetch installed Helm charts for component '%s': %s", component.Name, err.Error())
+		}
+		deployedComponent.InstalledCharts = installedCharts
+	}
+	cd.deployedComponents = append(cd.deployedComponents, deployedComponent)
+	idx := len(cd.deployedComponents) - 1
+	cd.running = append(cd.running, component.Name)
+	p.publishStatus(ctx, func(status *types.DeployStatus) {
+		status.Component = strings.Join(cd.running, ", ")
+		status.Step = "Starting"
+	})
+	cd.mu.Unlock()
+	p.recordConcurrentDeployment(ctx, cd, component)
+
+	message.Infof("Deploying component %s", component.Name)
+	charts, deployErr := cp.deployComponent(ctx, component, false, false)
+
+	onDeploy := component.Actions.OnDeploy
+	onFailure := func() {
+		if err := actions.Run(ctx, onDeploy.Defaults, onDeploy.OnFailure, cp.variableConfig, actions.WithCluster(cp.cluster), actions.WithKubeconfig(cp.kubeconfig)); err != nil {
+			message.Debugf("unable to run component failure action: %s", err.Error())
+		}
+	}
+
+	if deployErr != nil {
+		onFailure()
+	}
+
+	cd.mu.Lock()
+	cd.running = slices.DeleteFunc(cd.running, func(name string) bool { return name == component.Name })
+	if cp.hpaModified {
+		p.hpaModified = true
+	}
+	if deployErr != nil {
+		// Update the package secret to indicate that we failed to deploy this component
+		cd.deployedComponents[idx].Status = types.ComponentStatusFailed
+		cd.mu.Unlock()
+		p.recordConcurrentDeployment(ctx, cd, component)
+		return fmt.Errorf("unable to deploy component %q: %w", component.Name, deployErr)
+	}
+	p.publishStatus(ctx, func(status *types.DeployStatus) {
+		status.Component = strings.Join(cd.running, ", ")
+		status.ComponentsCompleted++
+	})
+	// Update the package secret to indicate that we successfully deployed this component
+	cd.deployedComponents[idx].InstalledCharts = charts
+	cd.deployedComponents[idx].PushedImages = cp.pushedImages
+	cd.deployedComponents[idx].PushedRepos = cp.pushedRepos
+	cd.deployedComponents[idx].Status = types.ComponentStatusSucceeded
+	cd.mu.Unlock()
+	p.recordConcurrentDeployment(ctx, cd, component)
+
+	if err := actions.Run(ctx, onDeploy.Defaults, onDeploy.OnSuccess, cp.variableConfig, actions.WithCluster(cp.cluster), actions.WithKubeconfig(cp.kubeconfig)); err != nil {
+		onFailure()
+		return fmt.Errorf("unable to run component success action: %w", err)
+	}
+
+	// Variables set by the actions of the component are seen by the components that start after it.
+	cd.mu.Lock()
+	p.variableConfig.MergeSetVariables(cp.variableConfig)
+	cd.mu.Unlock()
+
+	message.Successf("Deployed component %s in %s", component.Name, time.Since(start).Round(time.Second))
+	return nil
+}
+
+// recordConcurrentDeployment records the deployed components of a concurrent deployment in the package secret and waits
+// for the webhooks of the component, the caller must not hold the lock of the deployment.
+//
+// Only the write of the secret is done one at a time, from the latest deployed components, so that the other components
+// are not held up by the API calls of the write or by the webhooks of this component.
+func (p *Packager) recordConcurrentDeployment(ctx context.Context, cd *concurrentDeploy, component v1alpha1.ZarfComponent) {
+	if !p.isConnectedToCluster() {
+		return
+	}
+	cd.recordMu.Lock()
+	cd.mu.Lock()
+	deployedComponents := slices.Clone(cd.deployedComponents)
+	cd.mu.Unlock()
+	deployedPackage, err := p.cluster.RecordPackageDeployment(ctx, p.cfg.Pkg, deployedComponents, cd.generation, p.variableOverlays)
+	cd.recordMu.Unlock()
+	if err != nil {
+		message.Debugf("Unable to record package deployment for component %q: this will affect features like `zarf package remove`: %s", component.Name, err.Error())
+		return
+	}
+
+	for _, deployedComponent := range deployedComponents {
+		if deployedComponent.Name != component.Name {
+			continue
+		}
+		if err := p.cluster.RecordComponentEvent(ctx, p.cfg.Pkg.Metadata.Name, deployedComponent); err != nil {
+			message.Debugf("Unable to record an event for component %s: %s", component.Name, err.Error())
+		}
+	}
+	if _, err := p.cluster.WaitForPackageWebhooks(ctx, deployedPackage, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
+		message.Debugf("Unable to wait for the webhooks of component %q: %s", component.Name, err.Error())
+	}
+}
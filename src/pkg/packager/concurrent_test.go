@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/template"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestDeployComponentsConcurrently(t *testing.T) {
+	ctx := testutil.TestContext(t)
+
+	after := func(cmd string, setVariables ...v1alpha1.Variable) v1alpha1.ZarfComponentActions {
+		return v1alpha1.ZarfComponentActions{
+			OnDeploy: v1alpha1.ZarfComponentActionSet{
+				After: []v1alpha1.ZarfComponentAction{{Cmd: cmd, SetVariables: setVariables}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		components    []v1alpha1.ZarfComponent
+		expected      []string
+		expectedErr   string
+		expectedValue string
+	}{
+		{
+			name: "dependencies",
+			components: []v1alpha1.ZarfComponent{
+				{Name: "database", Actions: after("echo database", v1alpha1.Variable{Name: "DATABASE"})},
+				{Name: "cache", Actions: after("echo cache")},
+				{Name: "app", DependsOn: []string{"database", "cache"}, Actions: after(`test "$ZARF_VAR_DATABASE" = database`)},
+				{Name: "docs", DependsOn: []string{"not-selected"}, Actions: after("echo docs")},
+			},
+			expected:      []string{"database", "cache", "app", "docs"},
+			expectedValue: "database",
+		},
+		{
+			name: "failed dependency",
+			components: []v1alpha1.ZarfComponent{
+				{Name: "database", Actions: after("exit 1")},
+				{Name: "app", DependsOn: []string{"database"}, Actions: after("echo app")},
+			},
+			expectedErr: `unable to deploy component "database"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Packager{
+				cfg: &types.PackagerConfig{
+					Pkg:        v1alpha1.ZarfPackage{Components: tt.components},
+					DeployOpts: types.ZarfDeployOptions{Concurrency: 2},
+				},
+				layout:         &layout.PackagePaths{},
+				variableConfig: template.GetZarfVariableConfig(),
+			}
+			deployedComponents, err := p.deployComponentsConcurrently(ctx)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, dc := range deployedComponents {
+				require.Equal(t, types.ComponentStatusSucceeded, dc.Status)
+				names = append(names, dc.Name)
+			}
+			require.ElementsMatch(t, tt.expected, names)
+			variable, ok := p.variableConfig.GetSetVariable("DATABASE")
+			require.True(t, ok)
+			require.Equal(t, tt.expectedValue, variable.Value)
+		})
+	}
+}
+
+func TestRecordConcurrentDeployment(t *testing.T) {
+	ctx := testutil.TestContext(t)
+
+	cd := &concurrentDeploy{
+		generation: 1,
+		deployedComponents: []types.DeployedComponent{
+			{Name: "database", Status: types.ComponentStatusSucceeded},
+			{Name: "app", Status: types.ComponentStatusDeploying},
+		},
+	}
+	cs := fake.NewSimpleClientset()
+	// The other components must be able to start and finish while the package secret is written.
+	heldDuringWrite := false
+	cs.PrependReactor("*", "secrets", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		if cd.mu.TryLock() {
+			cd.mu.Unlock()
+		} else {
+			heldDuringWrite = true
+		}
+		return false, nil, nil
+	})
+	p := &Packager{
+		cfg: &types.PackagerConfig{
+			Pkg: v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Name: "test"}},
+		},
+		cluster: &cluster.Cluster{Clientset: cs},
+	}
+
+	p.recordConcurrentDeployment(ctx, cd, v1alpha1.ZarfComponent{Name: "app"})
+	require.False(t, heldDuringWrite)
+	deployedPackage, err := p.cluster.GetDeployedPackage(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, cd.deployedComponents, deployedPackage.DeployedComponents)
+}
@@ -7,45 +7,54 @@ package packager
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
 	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
 // Create generates a Zarf package tarball for a given PackageConfig and optional base directory.
 func (p *Packager) Create(ctx context.Context) error {
-	cwd, err := os.Getwd()
+	cwd, pc, warnings, err := p.loadCreateDefinition(ctx)
 	if err != nil {
 		return err
 	}
 
-	if err := os.Chdir(p.cfg.CreateOpts.BaseDir); err != nil {
-		return fmt.Errorf("unable to access directory %q: %w", p.cfg.CreateOpts.BaseDir, err)
+	if p.cfg.CreateOpts.DryRun {
+		for _, warning := range warnings {
+			message.Warn(warning)
+		}
+		report, err := pc.DryRun(ctx, p.cfg.Pkg)
+		if err != nil {
+			return err
+		}
+		utils.ColorPrintYAML(report, nil, false)
+		if len(report.Unsized) > 0 {
+			message.Warnf("The size of %d inputs could not be determined and are not included in the estimated size", len(report.Unsized))
+		}
+		message.Infof("The package is estimated to be %s before compression", report.EstimatedSize)
+		return os.Chdir(cwd)
 	}
 
-	message.Note(fmt.Sprintf("Using build directory %s", p.cfg.CreateOpts.BaseDir))
-
-	pc := creator.NewPackageCreator(p.cfg.CreateOpts, cwd)
-
-	if err := helpers.CreatePathAndCopy(layout.ZarfYAML, p.layout.ZarfYAML); err != nil {
-		return err
+	if err := p.confirmAction(config.ZarfCreateStage, p.cfg.Pkg.Components, warnings, nil); err != nil {
+		return fmt.Errorf("package creation canceled: %w", err)
 	}
 
-	pkg, warnings, err := pc.LoadPackageDefinition(ctx, p.layout)
+	err = actions.RunSet(ctx, p.cfg.Pkg.Actions.OnCreate, nil, func() error {
+		return pc.Assemble(ctx, p.layout, p.cfg.Pkg.Components, p.cfg.Pkg.Metadata.Architecture)
+	})
 	if err != nil {
 		return err
 	}
-	p.cfg.Pkg = pkg
-
-	if !p.confirmAction(config.ZarfCreateStage, warnings, nil) {
-		return fmt.Errorf("package creation canceled")
-	}
 
-	if err := pc.Assemble(ctx, p.layout, p.cfg.Pkg.Components, p.cfg.Pkg.Metadata.Architecture); err != nil {
+	if err := p.layout.AddDocs(p.cfg.Pkg.Docs); err != nil {
 		return err
 	}
 
@@ -56,3 +65,65 @@ func (p *Packager) Create(ctx context.Context) error {
 
 	return pc.Output(ctx, p.layout, &p.cfg.Pkg)
 }
+
+// Lock resolves every external input of the package and writes what they resolved to into zarf-lock.yaml, or with
+// --locked checks them against it, without running actions or writing a package.
+func (p *Packager) Lock(ctx context.Context) error {
+	cwd, pc, warnings, err := p.loadCreateDefinition(ctx)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		message.Warn(warning)
+	}
+
+	if err := pc.ResolveLock(ctx, p.layout, p.cfg.Pkg.Components, p.cfg.Pkg.Metadata.Architecture); err != nil {
+		return err
+	}
+	if !p.cfg.CreateOpts.Locked {
+		message.Successf("Wrote the resolved package inputs to %s", filepath.Join(p.cfg.CreateOpts.BaseDir, pc.LockPath()))
+	}
+	return os.Chdir(cwd)
+}
+
+// loadCreateDefinition changes into the base directory and loads the package definition for create, returning the
+// original working directory, the creator and the warnings found while loading.
+func (p *Packager) loadCreateDefinition(ctx context.Context) (string, *creator.PackageCreator, []string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	// Check the maximum artifact size before building the package rather than after.
+	if _, err := creator.MaxPartSize(p.cfg.CreateOpts); err != nil {
+		return "", nil, nil, err
+	}
+
+	// Variables from env files are read relative to the working directory and fill in those that were not set directly.
+	envVariables, err := utils.ReadEnvFiles(p.cfg.CreateOpts.EnvFiles...)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	maps.Copy(envVariables, p.cfg.CreateOpts.SetVariables)
+	p.cfg.CreateOpts.SetVariables = envVariables
+
+	if err := os.Chdir(p.cfg.CreateOpts.BaseDir); err != nil {
+		return "", nil, nil, fmt.Errorf("unable to access directory %q: %w", p.cfg.CreateOpts.BaseDir, err)
+	}
+
+	message.Note(fmt.Sprintf("Using build directory %s", p.cfg.CreateOpts.BaseDir))
+
+	pc := creator.NewPackageCreator(p.cfg.CreateOpts, cwd)
+
+	if err := helpers.CreatePathAndCopy(layout.ZarfYAML, p.layout.ZarfYAML); err != nil {
+		return "", nil, nil, err
+	}
+
+	pkg, warnings, err := pc.LoadPackageDefinition(ctx, p.layout)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	p.cfg.Pkg = pkg
+
+	return cwd, pc, warnings, nil
+}
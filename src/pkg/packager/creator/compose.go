@@ -6,14 +6,67 @@ package creator
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/packager/composer"
 )
 
-// ComposeComponents composes components and their dependencies into a single Zarf package using an import chain.
-func ComposeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor string) (v1alpha1.ZarfPackage, []string, error) {
+// ComposeComponents composes components and their dependencies into a single Zarf package using an import chain,
+// evaluating component expressions against the given package templates.
+func ComposeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor string, setVariables map[string]string) (v1alpha1.ZarfPackage, []string, error) {
+	pkg, warnings, _, err := composeComponents(ctx, pkg, flavor, setVariables, nil)
+	return pkg, warnings, err
+}
+
+// ImportTree is the import chain that a composed component was built from.
+type ImportTree struct {
+	// The name of the composed component
+	Component string
+	// What each package in the import chain contributes, starting with the package definition being composed
+	Layers []composer.Layer
+}
+
+// composeRecord collects how the components of a package were composed.
+type composeRecord struct {
+	// The origin of each composed value keyed by its path in the composed package
+	provenance map[string]composer.Origin
+	// The import chain of each composed component
+	trees []ImportTree
+}
+
+// ComposeWithProvenance composes components like ComposeComponents after resolving the package architecture the same way
+// as create and also returns the package in the import chain that each composed value came from, keyed by its path in
+// the composed package (e.g. "components[0].images[1]").
+func ComposeWithProvenance(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor string, setVariables map[string]string) (v1alpha1.ZarfPackage, map[string]composer.Origin, []string, error) {
+	pkg.Metadata.Architecture, pkg.Metadata.Architectures = resolveArchitectures(pkg.Metadata, nil)
+	record := &composeRecord{provenance: map[string]composer.Origin{}}
+	pkg, warnings, _, err := composeComponents(ctx, pkg, flavor, setVariables, record)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, nil, err
+	}
+	return pkg, record.provenance, warnings, nil
+}
+
+// ComposeWithImportTrees composes components like ComposeWithProvenance and also returns the import chain that each
+// composed component was built from, in the order of the composed components.
+func ComposeWithImportTrees(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor string, setVariables map[string]string) (v1alpha1.ZarfPackage, []ImportTree, []string, error) {
+	pkg.Metadata.Architecture, pkg.Metadata.Architectures = resolveArchitectures(pkg.Metadata, nil)
+	record := &composeRecord{provenance: map[string]composer.Origin{}, trees: []ImportTree{}}
+	pkg, warnings, _, err := composeComponents(ctx, pkg, flavor, setVariables, record)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, nil, err
+	}
+	return pkg, record.trees, warnings, nil
+}
+
+// composeComponents composes components like ComposeComponents and also returns what the remote skeleton imports of
+// the components resolved to for the package lock, recording the origin of each composed value and the import chain of
+// each composed component into record if it is not nil.
+func composeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor string, setVariables map[string]string, record *composeRecord) (v1alpha1.ZarfPackage, []string, []LockEntry, error) {
 	components := []v1alpha1.ZarfComponent{}
+	imports := []LockEntry{}
 	warnings := []string{}
 
 	pkgVars := pkg.Variables
@@ -21,36 +74,98 @@ func ComposeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor str
 
 	arch := pkg.Metadata.Architecture
 
+	flavors, err := pkg.ResolveFlavor(flavor)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, nil, err
+	}
+	overridden := composer.OverriddenComponents(pkg.Components, flavors)
+
 	for i, component := range pkg.Components {
-		// filter by architecture and flavor
-		if !composer.CompatibleComponent(component, arch, flavor) {
+		// skip components that are replaced by a component for a more specific flavor
+		if overridden[i] {
 			continue
 		}
+		if err := composer.ValidateExpression(component.Only.Expression); err != nil {
+			return v1alpha1.ZarfPackage{}, nil, nil, fmt.Errorf("component %q: %w", component.Name, err)
+		}
+		only := component.Only
 
-		// if a match was found, strip flavor and architecture to reduce bloat in the package definition
-		component.Only.Cluster.Architecture = ""
-		component.Only.Flavor = ""
+		componentArch := arch
+		if pkg.IsMultiArch() {
+			// multi-architecture packages keep architecture specific components so that they can be filtered on deploy
+			onlyArch := component.Only.Cluster.Architecture
+			if onlyArch != "" && !slices.Contains(pkg.Metadata.Architectures, onlyArch) {
+				continue
+			}
+			if !composer.CompatibleComponent(component, onlyArch, flavors, setVariables) {
+				continue
+			}
+			if onlyArch != "" {
+				componentArch = onlyArch
+			}
+		} else {
+			// filter by architecture and flavor
+			if !composer.CompatibleComponent(component, arch, flavors, setVariables) {
+				continue
+			}
 
-		// build the import chain
-		chain, err := composer.NewImportChain(ctx, component, i, pkg.Metadata.Name, arch, flavor)
-		if err != nil {
-			return v1alpha1.ZarfPackage{}, nil, err
+			// if a match was found, strip architecture to reduce bloat in the package definition
+			component.Only.Cluster.Architecture = ""
 		}
 
-		// migrate any deprecated component configurations now
-		warning := chain.Migrate(pkg.Build)
-		warnings = append(warnings, warning...)
+		// strip flavor and expression to reduce bloat in the package definition
+		component.Only.Flavor = nil
+		component.Only.Expression = ""
 
-		// get the composed component
-		composed, err := chain.Compose(ctx)
+		// expand imports of every component of a package into an import of each of them
+		expanded, err := composer.ExpandComponent(ctx, component, componentArch, flavors, setVariables)
 		if err != nil {
-			return v1alpha1.ZarfPackage{}, nil, err
+			return v1alpha1.ZarfPackage{}, nil, nil, err
 		}
-		components = append(components, *composed)
+		for _, component := range expanded {
+			// build the import chain
+			chain, err := composer.NewImportChain(ctx, component, i, pkg.Metadata.Name, componentArch, flavors, setVariables)
+			if err != nil {
+				return v1alpha1.ZarfPackage{}, nil, nil, err
+			}
 
-		// merge variables and constants
-		pkgVars = chain.MergeVariables(pkgVars)
-		pkgConsts = chain.MergeConstants(pkgConsts)
+			// migrate any deprecated component configurations now
+			warning := chain.Migrate(pkg.Build)
+			warnings = append(warnings, warning...)
+
+			// the layers must be taken before composing fixes the paths within the chain
+			var layers []composer.Layer
+			if record != nil {
+				layers = chain.Layers()
+				// the filters of the head component are stripped before its chain is built
+				layers[0].Only = only
+			}
+
+			// get the composed component
+			composed, err := chain.Compose(ctx)
+			if err != nil {
+				return v1alpha1.ZarfPackage{}, nil, nil, err
+			}
+			if record != nil {
+				for path, origin := range chain.Provenance() {
+					record.provenance[fmt.Sprintf("components[%d].%s", len(components), path)] = origin
+				}
+				record.trees = append(record.trees, ImportTree{Component: composed.Name, Layers: layers})
+			}
+			components = append(components, *composed)
+
+			url, digest, err := chain.OCIImport(ctx)
+			if err != nil {
+				return v1alpha1.ZarfPackage{}, nil, nil, err
+			}
+			if url != "" {
+				imports = append(imports, LockEntry{Component: component.Name, Source: url, Digest: digest})
+			}
+
+			// merge variables and constants
+			pkgVars = chain.MergeVariables(pkgVars)
+			pkgConsts = chain.MergeConstants(pkgConsts)
+		}
 	}
 
 	// set the filtered + composed components
@@ -59,5 +174,5 @@ func ComposeComponents(ctx context.Context, pkg v1alpha1.ZarfPackage, flavor str
 	pkg.Variables = pkgVars
 	pkg.Constants = pkgConsts
 
-	return pkg, warnings, nil
+	return pkg, warnings, imports, nil
 }
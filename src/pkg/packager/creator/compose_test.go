@@ -19,6 +19,7 @@ func TestComposeComponents(t *testing.T) {
 		name        string
 		pkg         v1alpha1.ZarfPackage
 		flavor      string
+		variables   map[string]string
 		expectedPkg v1alpha1.ZarfPackage
 		expectedErr string
 	}{
@@ -83,6 +84,47 @@ func TestComposeComponents(t *testing.T) {
 			},
 			expectedErr: "",
 		},
+		{
+			name: "filter by multiple architectures",
+			pkg: v1alpha1.ZarfPackage{
+				Metadata: v1alpha1.ZarfMetadata{Architecture: v1alpha1.MultiArch, Architectures: []string{"amd64", "arm64"}},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "component1",
+					},
+					{
+						Name: "component2",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Cluster: v1alpha1.ZarfComponentOnlyCluster{
+								Architecture: "arm64",
+							},
+						},
+					},
+					{
+						Name: "component3",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Cluster: v1alpha1.ZarfComponentOnlyCluster{
+								Architecture: "s390x",
+							},
+						},
+					},
+				},
+			},
+			expectedPkg: v1alpha1.ZarfPackage{
+				Components: []v1alpha1.ZarfComponent{
+					{Name: "component1"},
+					{
+						Name: "component2",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Cluster: v1alpha1.ZarfComponentOnlyCluster{
+								Architecture: "arm64",
+							},
+						},
+					},
+				},
+			},
+			expectedErr: "",
+		},
 		{
 			name: "filter by flavor match",
 			pkg: v1alpha1.ZarfPackage{
@@ -91,13 +133,13 @@ func TestComposeComponents(t *testing.T) {
 					{
 						Name: "component1",
 						Only: v1alpha1.ZarfComponentOnlyTarget{
-							Flavor: "default",
+							Flavor: v1alpha1.FlavorList{"default"},
 						},
 					},
 					{
 						Name: "component2",
 						Only: v1alpha1.ZarfComponentOnlyTarget{
-							Flavor: "default",
+							Flavor: v1alpha1.FlavorList{"default"},
 						},
 					},
 				},
@@ -119,13 +161,13 @@ func TestComposeComponents(t *testing.T) {
 					{
 						Name: "component1",
 						Only: v1alpha1.ZarfComponentOnlyTarget{
-							Flavor: "default",
+							Flavor: v1alpha1.FlavorList{"default"},
 						},
 					},
 					{
 						Name: "component2",
 						Only: v1alpha1.ZarfComponentOnlyTarget{
-							Flavor: "special",
+							Flavor: v1alpha1.FlavorList{"special"},
 						},
 					},
 				},
@@ -138,6 +180,136 @@ func TestComposeComponents(t *testing.T) {
 			},
 			expectedErr: "",
 		},
+		{
+			name: "filter by flavor list",
+			pkg: v1alpha1.ZarfPackage{
+				Metadata: v1alpha1.ZarfMetadata{Architecture: "amd64"},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "component1",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Flavor: v1alpha1.FlavorList{"default", "special"},
+						},
+					},
+					{
+						Name: "component2",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Flavor: v1alpha1.FlavorList{"upstream", "default"},
+						},
+					},
+					{
+						Name: "component3",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Flavor: v1alpha1.FlavorList{"upstream"},
+						},
+					},
+				},
+			},
+			flavor: "special",
+			expectedPkg: v1alpha1.ZarfPackage{
+				Components: []v1alpha1.ZarfComponent{
+					{Name: "component1"},
+				},
+			},
+			expectedErr: "",
+		},
+		{
+			name: "inherited flavor is overridden by a more specific flavor",
+			pkg: v1alpha1.ZarfPackage{
+				Metadata: v1alpha1.ZarfMetadata{Architecture: "amd64"},
+				Flavors: []v1alpha1.ZarfFlavor{
+					{Name: "registry1"},
+					{Name: "registry1-fips", Inherits: []string{"registry1"}},
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:        "component1",
+						Description: "registry1",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Flavor: v1alpha1.FlavorList{"registry1"},
+						},
+					},
+					{
+						Name:        "component1",
+						Description: "registry1-fips",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Flavor: v1alpha1.FlavorList{"registry1-fips"},
+						},
+					},
+					{
+						Name: "component2",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Flavor: v1alpha1.FlavorList{"registry1"},
+						},
+					},
+				},
+			},
+			flavor: "registry1-fips",
+			expectedPkg: v1alpha1.ZarfPackage{
+				Components: []v1alpha1.ZarfComponent{
+					{Name: "component1", Description: "registry1-fips"},
+					{Name: "component2"},
+				},
+			},
+			expectedErr: "",
+		},
+		{
+			name: "flavor inheritance cycle error",
+			pkg: v1alpha1.ZarfPackage{
+				Metadata: v1alpha1.ZarfMetadata{Architecture: "amd64"},
+				Flavors: []v1alpha1.ZarfFlavor{
+					{Name: "a", Inherits: []string{"b"}},
+					{Name: "b", Inherits: []string{"a"}},
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{Name: "component1"},
+				},
+			},
+			flavor:      "a",
+			expectedPkg: v1alpha1.ZarfPackage{},
+			expectedErr: "flavor inheritance cycle a -> b -> a",
+		},
+		{
+			name: "filter by expression",
+			pkg: v1alpha1.ZarfPackage{
+				Metadata: v1alpha1.ZarfMetadata{Architecture: "amd64"},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "gpu-operator",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Expression: `vars.GPU && arch == "amd64"`,
+						},
+					},
+					{
+						Name: "cpu-fallback",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Expression: "!vars.GPU",
+						},
+					},
+				},
+			},
+			variables: map[string]string{"GPU": "true"},
+			expectedPkg: v1alpha1.ZarfPackage{
+				Components: []v1alpha1.ZarfComponent{
+					{Name: "gpu-operator"},
+				},
+			},
+		},
+		{
+			name: "invalid expression error",
+			pkg: v1alpha1.ZarfPackage{
+				Metadata: v1alpha1.ZarfMetadata{Architecture: "amd64"},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "component1",
+						Only: v1alpha1.ZarfComponentOnlyTarget{
+							Expression: "os == \"linux\"",
+						},
+					},
+				},
+			},
+			expectedErr: `component "component1": invalid expression "os == \"linux\"": unknown identifier os, expected arch, flavor or vars.<NAME>`,
+		},
 		{
 			name: "no architecture set error",
 			pkg: v1alpha1.ZarfPackage{
@@ -145,7 +317,7 @@ func TestComposeComponents(t *testing.T) {
 					{
 						Name: "component1",
 						Only: v1alpha1.ZarfComponentOnlyTarget{
-							Flavor: "default",
+							Flavor: v1alpha1.FlavorList{"default"},
 						},
 					},
 				},
@@ -161,7 +333,7 @@ func TestComposeComponents(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			pkg, _, err := ComposeComponents(context.Background(), tt.pkg, tt.flavor)
+			pkg, _, err := ComposeComponents(context.Background(), tt.pkg, tt.flavor, tt.variables)
 
 			if tt.expectedErr == "" {
 				require.NoError(t, err)
@@ -38,6 +38,18 @@ func TestLoadPackageDefinition(t *testing.T) {
 			expectedErr: "linting error found 1 instance(s)",
 			creator:     NewPackageCreator(types.ZarfCreateOptions{}, ""),
 		},
+		{
+			name:        "locked without a lock file",
+			testDir:     "valid",
+			expectedErr: "unable to find zarf-lock.yaml, run package create without --locked to generate it",
+			creator:     NewPackageCreator(types.ZarfCreateOptions{Locked: true}, ""),
+		},
+		{
+			name:        "encrypted OCI output",
+			testDir:     "valid",
+			expectedErr: "unable to create package. Only package tarballs can be encrypted, not packages published to an OCI registry",
+			creator:     NewPackageCreator(types.ZarfCreateOptions{Output: "oci://ghcr.io/zarf-dev/packages", EncryptionPassphrase: "secret"}, ""),
+		},
 		{
 			name:        "valid package definition",
 			testDir:     "valid",
@@ -10,12 +10,13 @@ import (
 
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
-// loadDifferentialData sets any images and repos from the existing reference package in the DifferentialData and returns it.
+// loadDifferentialData sets any images, repos and pinned files from the existing reference package in the DifferentialData and returns it.
 func loadDifferentialData(ctx context.Context, diffPkgPath string) (diffData *types.DifferentialData, err error) {
 	tmpdir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
 	if err != nil {
@@ -32,26 +33,34 @@ func loadDifferentialData(ctx context.Context, diffPkgPath string) (diffData *ty
 		return nil, err
 	}
 
-	diffPkg, _, err := src.LoadPackageMetadata(ctx, diffLayout, false, false)
+	diffPkg, _, err := src.LoadPackageMetadata(ctx, diffLayout, false, false, false)
 	if err != nil {
 		return nil, err
 	}
 
 	allIncludedImagesMap := map[string]bool{}
 	allIncludedReposMap := map[string]bool{}
+	allIncludedFilesMap := map[string]bool{}
 
 	for _, component := range diffPkg.Components {
-		for _, image := range component.Images {
+		for _, image := range component.ImagesAndArtifacts() {
 			allIncludedImagesMap[image] = true
 		}
 		for _, repo := range component.Repos {
 			allIncludedReposMap[repo] = true
 		}
+		for _, file := range component.Files {
+			if key := filters.DifferentialFileKey(file); key != "" {
+				allIncludedFilesMap[key] = true
+			}
+		}
 	}
 
 	return &types.DifferentialData{
-		DifferentialImages:         allIncludedImagesMap,
-		DifferentialRepos:          allIncludedReposMap,
-		DifferentialPackageVersion: diffPkg.Metadata.Version,
+		DifferentialImages:          allIncludedImagesMap,
+		DifferentialRepos:           allIncludedReposMap,
+		DifferentialFiles:           allIncludedFilesMap,
+		DifferentialPackageVersion:  diffPkg.Metadata.Version,
+		DifferentialPackageChecksum: diffPkg.Metadata.AggregateChecksum,
 	}, nil
 }
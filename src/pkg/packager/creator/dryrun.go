@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/images"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// DryRunReport describes what would be packaged by a create without downloading any of it.
+type DryRunReport struct {
+	// The name of the package.
+	Name string `json:"name"`
+	// The version of the package.
+	Version string `json:"version,omitempty"`
+	// The architectures the package would be created for.
+	Architectures []string `json:"architectures"`
+	// The flavor the package would be created with.
+	Flavor string `json:"flavor,omitempty"`
+	// Remote skeleton package imports and the digest they resolved to.
+	Imports []LockEntry `json:"imports,omitempty"`
+	// The components that would be included in the package.
+	Components []DryRunComponent `json:"components"`
+	// The images and artifacts that would be pulled for each architecture.
+	Images []DryRunImage `json:"images,omitempty"`
+	// The estimated size of the package before compression, counting layers shared between images once.
+	EstimatedSize string `json:"estimatedSize"`
+	// The inputs whose size could not be determined and are not counted in the estimated size.
+	Unsized []string `json:"unsized,omitempty"`
+
+	estimatedBytes int64
+}
+
+// DryRunComponent describes the assets of a component that would be packaged.
+type DryRunComponent struct {
+	Name           string           `json:"name"`
+	Images         []string         `json:"images,omitempty"`
+	Artifacts      []string         `json:"artifacts,omitempty"`
+	Repos          []string         `json:"repos,omitempty"`
+	Charts         []DryRunArtifact `json:"charts,omitempty"`
+	Files          []DryRunArtifact `json:"files,omitempty"`
+	Manifests      []DryRunArtifact `json:"manifests,omitempty"`
+	DataInjections []DryRunArtifact `json:"dataInjections,omitempty"`
+}
+
+// DryRunArtifact is a single chart, file or manifest that would be packaged.
+type DryRunArtifact struct {
+	Source string `json:"source"`
+	Size   string `json:"size,omitempty"`
+}
+
+// DryRunImage is an image that would be pulled for an architecture.
+type DryRunImage struct {
+	Reference    string `json:"reference"`
+	Architecture string `json:"architecture"`
+	Layers       int    `json:"layers,omitempty"`
+	Size         string `json:"size,omitempty"`
+}
+
+// DryRun resolves the sizes of the images, files, charts and manifests of the given loaded package without downloading
+// them, using the image manifests and HEAD requests for remote files.
+func (pc *PackageCreator) DryRun(ctx context.Context, pkg v1alpha1.ZarfPackage) (DryRunReport, error) {
+	archs := []string{pkg.Metadata.Architecture}
+	if pkg.IsMultiArch() {
+		archs = pc.architectures
+	}
+	report := DryRunReport{
+		Name:          pkg.Metadata.Name,
+		Version:       pkg.Metadata.Version,
+		Architectures: archs,
+		Flavor:        pc.createOpts.Flavor,
+		Imports:       pc.lock.Imports,
+	}
+
+	spinner := message.NewProgressSpinner("Resolving the contents of the package")
+	defer spinner.Stop()
+
+	imageList := []string{}
+	for _, component := range pkg.Components {
+		c := DryRunComponent{
+			Name:      component.Name,
+			Images:    component.Images,
+			Artifacts: component.Artifacts,
+			Repos:     component.Repos,
+		}
+		report.Unsized = append(report.Unsized, component.Repos...)
+		imageList = append(imageList, component.ImagesAndArtifacts()...)
+
+		for _, chart := range component.Charts {
+			if chart.LocalPath != "" {
+				c.Charts = append(c.Charts, report.addArtifact(ctx, chart.LocalPath))
+				continue
+			}
+			source := chart.URL
+			if chart.Version != "" {
+				source = fmt.Sprintf("%s (%s)", chart.URL, chart.Version)
+			}
+			c.Charts = append(c.Charts, DryRunArtifact{Source: source})
+			report.Unsized = append(report.Unsized, source)
+		}
+		for _, file := range component.Files {
+			c.Files = append(c.Files, report.addArtifact(ctx, file.Source))
+		}
+		for _, manifest := range component.Manifests {
+			for _, path := range manifest.Files {
+				c.Manifests = append(c.Manifests, report.addArtifact(ctx, path))
+			}
+			for _, path := range manifest.Kustomizations {
+				c.Manifests = append(c.Manifests, report.addArtifact(ctx, path))
+			}
+		}
+		for _, data := range component.DataInjections {
+			c.DataInjections = append(c.DataInjections, report.addArtifact(ctx, data.Source))
+		}
+		report.Components = append(report.Components, c)
+	}
+
+	// Layers shared between images are only stored in the package once.
+	seenBlobs := map[string]bool{}
+	for _, arch := range archs {
+		for _, image := range helpers.Unique(imageList) {
+			spinner.Updatef("Resolving %s for %s", image, arch)
+			entry, err := pc.resolveImage(ctx, image, arch, seenBlobs, &report)
+			if err != nil {
+				return DryRunReport{}, err
+			}
+			report.Images = append(report.Images, entry)
+		}
+	}
+	report.EstimatedSize = utils.ByteFormat(float64(report.estimatedBytes), 2)
+
+	spinner.Success()
+	return report, nil
+}
+
+// resolveImage sizes an image for the given architecture from its manifest, adding the blobs that have not been seen yet
+// to the estimated size of the report.
+func (pc *PackageCreator) resolveImage(ctx context.Context, image string, arch string, seenBlobs map[string]bool, report *DryRunReport) (DryRunImage, error) {
+	entry := DryRunImage{Reference: image, Architecture: arch}
+	refInfo, err := transform.ParseImageRef(image)
+	if err != nil {
+		return entry, fmt.Errorf("failed to parse image ref %q: %w", image, err)
+	}
+	if refInfo.Runtime != "" {
+		report.Unsized = append(report.Unsized, image)
+		return entry, nil
+	}
+
+	ref := refInfo.Reference
+	for k, v := range pc.createOpts.RegistryOverrides {
+		if strings.HasPrefix(refInfo.Reference, k) {
+			ref = strings.Replace(refInfo.Reference, k, v, 1)
+		}
+	}
+	img, err := crane.Pull(ref, append(images.CommonOpts(arch), crane.WithContext(ctx))...)
+	if err != nil {
+		return entry, fmt.Errorf("unable to resolve %s: %w", image, err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return entry, fmt.Errorf("unable to resolve %s for %s: %w", image, arch, err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return entry, err
+	}
+	rawManifest, err := img.RawManifest()
+	if err != nil {
+		return entry, err
+	}
+
+	var size int64
+	addBlob := func(digest string, blobSize int64) {
+		size += blobSize
+		if !seenBlobs[digest] {
+			seenBlobs[digest] = true
+			report.estimatedBytes += blobSize
+		}
+	}
+	addBlob(digest.String(), int64(len(rawManifest)))
+	addBlob(manifest.Config.Digest.String(), manifest.Config.Size)
+	for _, layer := range manifest.Layers {
+		addBlob(layer.Digest.String(), layer.Size)
+	}
+	entry.Layers = len(manifest.Layers)
+	entry.Size = utils.ByteFormat(float64(size), 2)
+	return entry, nil
+}
+
+// addArtifact sizes a local path or remote URL, recording it as unsized when its size cannot be determined.
+func (report *DryRunReport) addArtifact(ctx context.Context, source string) DryRunArtifact {
+	artifact := DryRunArtifact{Source: source}
+	size, err := sourceSize(ctx, source)
+	if err != nil {
+		message.Debugf("Unable to determine the size of %s: %s", source, err.Error())
+		report.Unsized = append(report.Unsized, source)
+		return artifact
+	}
+	report.estimatedBytes += size
+	artifact.Size = utils.ByteFormat(float64(size), 2)
+	return artifact
+}
+
+// sourceSize returns the size of a local file or directory, or the content length of a remote URL from a HEAD request.
+func sourceSize(ctx context.Context, source string) (int64, error) {
+	if !helpers.IsURL(source) {
+		fi, err := os.Stat(source)
+		if err != nil {
+			return 0, err
+		}
+		if fi.IsDir() {
+			return helpers.GetDirSize(source)
+		}
+		return fi.Size(), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, source, nil)
+	if err != nil {
+		return 0, err
+	}
+	client, err := utils.NewHTTPClient()
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("bad HTTP status: %s", resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("%s did not report a content length", source)
+	}
+	return resp.ContentLength, nil
+}
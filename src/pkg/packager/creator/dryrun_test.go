@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	zarfTypes "github.com/zarf-dev/zarf/src/types"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	reg := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(reg.Close)
+	host := strings.TrimPrefix(reg.URL, "http://")
+
+	// Two images that share a base layer.
+	base, err := random.Layer(1024, types.OCILayer)
+	require.NoError(t, err)
+	var imagesBytes int64
+	seen := map[string]bool{}
+	for _, name := range []string{"podinfo", "game"} {
+		layer, err := random.Layer(512, types.OCILayer)
+		require.NoError(t, err)
+		img, err := mutate.AppendLayers(empty.Image, base, layer)
+		require.NoError(t, err)
+		require.NoError(t, crane.Push(img, fmt.Sprintf("%s/%s:1.0.0", host, name)))
+
+		manifest, err := img.Manifest()
+		require.NoError(t, err)
+		rawManifest, err := img.RawManifest()
+		require.NoError(t, err)
+		imagesBytes += int64(len(rawManifest))
+		for _, desc := range append(manifest.Layers, manifest.Config) {
+			if !seen[desc.Digest.String()] {
+				seen[desc.Digest.String()] = true
+				imagesBytes += desc.Size
+			}
+		}
+	}
+
+	// Remote files are only sized with HEAD requests.
+	files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("unexpected %s request for %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Path == "/unknown" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "2048")
+	}))
+	t.Cleanup(files.Close)
+
+	local := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(local, make([]byte, 100), 0o600))
+
+	pc := NewPackageCreator(zarfTypes.ZarfCreateOptions{}, "")
+	pkg := v1alpha1.ZarfPackage{
+		Metadata: v1alpha1.ZarfMetadata{Name: "dry-run", Architecture: "amd64"},
+		Components: []v1alpha1.ZarfComponent{
+			{
+				Name:   "first",
+				Images: []string{host + "/podinfo:1.0.0"},
+				Repos:  []string{"https://github.com/zarf-dev/zarf.git"},
+				Files: []v1alpha1.ZarfFile{
+					{Source: files.URL + "/bin"},
+					{Source: files.URL + "/unknown"},
+				},
+			},
+			{
+				Name:   "second",
+				Images: []string{host + "/podinfo:1.0.0", host + "/game:1.0.0"},
+				Manifests: []v1alpha1.ZarfManifest{
+					{Name: "local", Files: []string{local}},
+				},
+			},
+		},
+	}
+
+	report, err := pc.DryRun(context.Background(), pkg)
+	require.NoError(t, err)
+	require.Equal(t, "dry-run", report.Name)
+	require.Equal(t, []string{"amd64"}, report.Architectures)
+	require.Len(t, report.Components, 2)
+	require.Equal(t, []DryRunArtifact{{Source: files.URL + "/bin", Size: utils.ByteFormat(2048, 2)}, {Source: files.URL + "/unknown"}}, report.Components[0].Files)
+	require.Equal(t, []DryRunArtifact{{Source: local, Size: utils.ByteFormat(100, 2)}}, report.Components[1].Manifests)
+	require.Len(t, report.Images, 2)
+	for _, image := range report.Images {
+		require.Equal(t, 2, image.Layers)
+	}
+	require.ElementsMatch(t, []string{"https://github.com/zarf-dev/zarf.git", files.URL + "/unknown"}, report.Unsized)
+	require.Equal(t, imagesBytes+2048+100, report.estimatedBytes)
+}
+
+func TestSourceSizeCACert(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", "42")
+	}))
+	t.Cleanup(srv.Close)
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+
+	// Without the CA of the server the size cannot be determined.
+	_, err := sourceSize(ctx, srv.URL)
+	require.Error(t, err)
+
+	// The size is determined with the same CA options as a create.
+	commonOptions := config.CommonOptions
+	t.Cleanup(func() {
+		config.CommonOptions = commonOptions
+	})
+	config.CommonOptions.CACertPaths = []string{caPath}
+	size, err := sourceSize(ctx, srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), size)
+}
@@ -0,0 +1,364 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/git"
+	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/internal/packager/images"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// Lock records what every external input of a package resolved to during package create.
+type Lock struct {
+	// The architecture the package was created for.
+	Architecture string `json:"architecture"`
+	// The flavor the package was created with.
+	Flavor string `json:"flavor,omitempty"`
+	// Images and the manifest digests their references resolved to.
+	Images []LockEntry `json:"images,omitempty"`
+	// Remote helm charts and the digests of their archives, or the commit SHA for charts from git.
+	Charts []LockEntry `json:"charts,omitempty"`
+	// Git repos and the commit SHA their refs resolved to.
+	Repos []LockEntry `json:"repos,omitempty"`
+	// Remote files, manifests, policies, values files and data injections and the digests of their contents.
+	Files []LockEntry `json:"files,omitempty"`
+	// Remote skeleton package imports and the digest of the package manifest they resolved to.
+	Imports []LockEntry `json:"imports,omitempty"`
+}
+
+// LockEntry is a single resolved input of a package.
+type LockEntry struct {
+	// The component the input belongs to, empty for inputs shared by the whole package.
+	Component string `json:"component,omitempty"`
+	// The input as it is referenced in the package definition.
+	Source string `json:"source"`
+	// The digest or commit SHA the input resolved to.
+	Digest string `json:"digest"`
+	// The architecture the input was resolved for in a multi-architecture package.
+	Architecture string `json:"architecture,omitempty"`
+}
+
+// ReadLock reads a lock file from disk.
+func ReadLock(path string) (Lock, error) {
+	var lock Lock
+	if err := utils.ReadYaml(path, &lock); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Lock{}, fmt.Errorf("unable to find %s, run package create without --locked to generate it", path)
+		}
+		return Lock{}, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return lock, nil
+}
+
+// Diff returns every difference between the lock and the inputs of the same package resolved again.
+func (l Lock) Diff(resolved Lock) []string {
+	diffs := []string{}
+	if l.Architecture != resolved.Architecture {
+		diffs = append(diffs, fmt.Sprintf("architecture %q does not match the locked architecture %q", resolved.Architecture, l.Architecture))
+	}
+	if l.Flavor != resolved.Flavor {
+		diffs = append(diffs, fmt.Sprintf("flavor %q does not match the locked flavor %q", resolved.Flavor, l.Flavor))
+	}
+	diffs = append(diffs, diffEntries("image", l.Images, resolved.Images)...)
+	diffs = append(diffs, diffEntries("chart", l.Charts, resolved.Charts)...)
+	diffs = append(diffs, diffEntries("repo", l.Repos, resolved.Repos)...)
+	diffs = append(diffs, diffEntries("file", l.Files, resolved.Files)...)
+	diffs = append(diffs, diffEntries("import", l.Imports, resolved.Imports)...)
+	return diffs
+}
+
+func diffEntries(kind string, locked, resolved []LockEntry) []string {
+	key := func(e LockEntry) string {
+		return e.Component + "\x00" + e.Source + "\x00" + e.Architecture
+	}
+	describe := func(e LockEntry) string {
+		source := e.Source
+		if e.Architecture != "" {
+			source = fmt.Sprintf("%s (%s)", e.Source, e.Architecture)
+		}
+		if e.Component == "" {
+			return fmt.Sprintf("%s %s", kind, source)
+		}
+		return fmt.Sprintf("%s %s in component %q", kind, source, e.Component)
+	}
+
+	lockedByKey := map[string]LockEntry{}
+	for _, e := range locked {
+		lockedByKey[key(e)] = e
+	}
+	diffs := []string{}
+	for _, e := range resolved {
+		prev, ok := lockedByKey[key(e)]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s is not in the lock", describe(e)))
+			continue
+		}
+		delete(lockedByKey, key(e))
+		if prev.Digest != e.Digest {
+			diffs = append(diffs, fmt.Sprintf("%s resolved to %s but is locked to %s", describe(e), e.Digest, prev.Digest))
+		}
+	}
+	for _, e := range locked {
+		if _, ok := lockedByKey[key(e)]; ok {
+			diffs = append(diffs, fmt.Sprintf("%s is locked but no longer used", describe(e)))
+		}
+	}
+	return diffs
+}
+
+// sort orders the entries of the lock so that it is written the same way every time.
+func (l *Lock) sort() {
+	for _, entries := range [][]LockEntry{l.Images, l.Charts, l.Repos, l.Files, l.Imports} {
+		slices.SortFunc(entries, func(a, b LockEntry) int {
+			return cmp.Or(cmp.Compare(a.Component, b.Component), cmp.Compare(a.Source, b.Source), cmp.Compare(a.Architecture, b.Architecture))
+		})
+	}
+}
+
+// ResolveLock resolves the external inputs of the components and writes them to the lock, or with --locked checks them
+// against it, without running actions or pulling images and repos. Remote charts and files are still downloaded into
+// dst as they are locked by the digest of their contents.
+func (pc *PackageCreator) ResolveLock(ctx context.Context, dst *layout.PackagePaths, components []v1alpha1.ZarfComponent, arch string) error {
+	archs := []string{arch}
+	if arch == v1alpha1.MultiArch {
+		archs = pc.architectures
+	}
+	imageLists := map[string][]transform.Image{}
+	artifactList := []transform.Image{}
+
+	for _, component := range components {
+		if err := pc.resolveComponent(ctx, component, dst); err != nil {
+			return fmt.Errorf("unable to resolve component %q: %w", component.Name, err)
+		}
+
+		for _, src := range component.Images {
+			refInfo, err := transform.ParseImageRef(src)
+			if err != nil {
+				return fmt.Errorf("failed to create ref for image %s: %w", src, err)
+			}
+			for _, imageArch := range archs {
+				if component.Only.Cluster.Architecture == "" || component.Only.Cluster.Architecture == imageArch {
+					imageLists[imageArch] = append(imageLists[imageArch], refInfo)
+				}
+			}
+		}
+		for _, src := range component.Artifacts {
+			refInfo, err := transform.ParseImageRef(src)
+			if err != nil {
+				return fmt.Errorf("failed to create ref for artifact %s: %w", src, err)
+			}
+			artifactList = append(artifactList, refInfo)
+		}
+	}
+
+	for _, imageArch := range archs {
+		imageList := helpers.Unique(imageLists[imageArch])
+		if len(imageList) == 0 {
+			continue
+		}
+		resolved, err := images.Resolve(ctx, images.PullConfig{
+			ImageList:         imageList,
+			Arch:              imageArch,
+			RegistryOverrides: pc.createOpts.RegistryOverrides,
+		})
+		if err != nil {
+			return err
+		}
+		for info, digest := range resolved {
+			entry := LockEntry{Source: info.Reference, Digest: digest}
+			if arch == v1alpha1.MultiArch {
+				entry.Architecture = imageArch
+			}
+			pc.lock.Images = append(pc.lock.Images, entry)
+		}
+	}
+
+	if len(artifactList) > 0 {
+		resolved, err := images.Resolve(ctx, images.PullConfig{
+			ImageList:         helpers.Unique(artifactList),
+			Arch:              archs[0],
+			RegistryOverrides: pc.createOpts.RegistryOverrides,
+		})
+		if err != nil {
+			return err
+		}
+		for info, digest := range resolved {
+			pc.lock.Images = append(pc.lock.Images, LockEntry{Source: info.Reference, Digest: digest})
+		}
+	}
+
+	return pc.finalizeLock()
+}
+
+// resolveComponent records what the remote charts, files and repos of a component resolve to.
+func (pc *PackageCreator) resolveComponent(ctx context.Context, component v1alpha1.ZarfComponent, dst *layout.PackagePaths) error {
+	componentPaths, err := dst.Components.Create(component)
+	if err != nil {
+		return err
+	}
+
+	for _, chart := range component.Charts {
+		url, _, err := transform.GitURLSplitRef(chart.URL)
+		if chart.URL != "" && (err != nil || !strings.HasSuffix(url, ".git")) {
+			// Published charts are locked by the digest of their archive.
+			if err := helm.New(chart, componentPaths.Charts, componentPaths.Values).PackageChart(ctx, componentPaths.Charts); err != nil {
+				return err
+			}
+		} else {
+			// Charts from git are locked by commit and local charts are not locked, so only their values files are needed.
+			for idx, path := range chart.ValuesFiles {
+				if helpers.IsURL(path) {
+					if err := utils.DownloadToFile(ctx, path, helm.StandardValuesName(componentPaths.Values, chart, idx), component.DeprecatedCosignKeyPath); err != nil {
+						return fmt.Errorf(lang.ErrDownloading, path, err.Error())
+					}
+				}
+			}
+		}
+		if err := pc.lockChart(ctx, component.Name, chart, componentPaths); err != nil {
+			return err
+		}
+	}
+
+	sources := []string{}
+	for _, file := range component.Files {
+		sources = append(sources, file.Source)
+	}
+	for _, data := range component.DataInjections {
+		sources = append(sources, data.Source)
+	}
+	for _, manifest := range component.Manifests {
+		sources = append(sources, manifest.Files...)
+	}
+	for _, p := range component.Policies {
+		sources = append(sources, p.Files...)
+	}
+	for _, g := range component.PolicyGates {
+		sources = append(sources, g.Files...)
+	}
+	for idx, source := range sources {
+		if !helpers.IsURL(source) {
+			continue
+		}
+		dst := filepath.Join(componentPaths.Temp, strconv.Itoa(idx))
+		if err := utils.DownloadToFile(ctx, source, dst, component.DeprecatedCosignKeyPath); err != nil {
+			return fmt.Errorf(lang.ErrDownloading, source, err.Error())
+		}
+		if err := pc.lockFile(component.Name, source, dst); err != nil {
+			return err
+		}
+	}
+
+	for _, url := range component.Repos {
+		commit, err := git.ResolveRef(ctx, url)
+		if err != nil {
+			return fmt.Errorf("unable to resolve repo %s for the lock: %w", url, err)
+		}
+		pc.lockRepo(component.Name, url, commit)
+	}
+	return nil
+}
+
+// lockFile records the digest of a remote file that was downloaded to path.
+func (pc *PackageCreator) lockFile(component, source, path string) error {
+	shasum, err := helpers.GetSHA256OfFile(path)
+	if err != nil {
+		return err
+	}
+	pc.lock.Files = append(pc.lock.Files, LockEntry{Component: component, Source: source, Digest: "sha256:" + shasum})
+	return nil
+}
+
+// lockChart records what a remote chart and its remote values files resolved to.
+func (pc *PackageCreator) lockChart(ctx context.Context, component string, chart v1alpha1.ZarfChart, componentPaths *layout.ComponentPaths) error {
+	for idx, path := range chart.ValuesFiles {
+		if helpers.IsURL(path) {
+			if err := pc.lockFile(component, path, helm.StandardValuesName(componentPaths.Values, chart, idx)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Local charts are not external inputs.
+	if chart.URL == "" {
+		return nil
+	}
+
+	url, refPlain, err := transform.GitURLSplitRef(chart.URL)
+	if err == nil && strings.HasSuffix(url, ".git") {
+		// Charts from git without a ref are pulled at a tag matching the chart version.
+		address := chart.URL
+		if refPlain == "" {
+			address = fmt.Sprintf("%s@%s", chart.URL, chart.Version)
+		}
+		commit, err := git.ResolveRef(ctx, address)
+		if err != nil {
+			return fmt.Errorf("unable to resolve chart %s for the lock: %w", address, err)
+		}
+		pc.lock.Charts = append(pc.lock.Charts, LockEntry{Component: component, Source: address, Digest: commit})
+		return nil
+	}
+
+	source := chart.URL
+	if chart.Version != "" {
+		source = fmt.Sprintf("%s@%s", chart.URL, chart.Version)
+	}
+	shasum, err := helpers.GetSHA256OfFile(helm.StandardName(componentPaths.Charts, chart) + ".tgz")
+	if err != nil {
+		return err
+	}
+	pc.lock.Charts = append(pc.lock.Charts, LockEntry{Component: component, Source: source, Digest: "sha256:" + shasum})
+	return nil
+}
+
+// lockRepo records the commit a git repo resolved to.
+func (pc *PackageCreator) lockRepo(component, url, commit string) {
+	pc.lock.Repos = append(pc.lock.Repos, LockEntry{Component: component, Source: url, Digest: commit})
+}
+
+// LockPath returns the path the lock is read from and written to, relative to the package directory.
+func (pc *PackageCreator) LockPath() string {
+	return pc.lockPath
+}
+
+// finalizeLock writes the lock, or with --locked fails if it differs from the existing one.
+func (pc *PackageCreator) finalizeLock() error {
+	pc.lock.sort()
+
+	if !pc.createOpts.Locked {
+		if err := utils.WriteYaml(pc.lockPath, pc.lock, helpers.ReadWriteUser); err != nil {
+			return fmt.Errorf("unable to write %s: %w", pc.lockPath, err)
+		}
+		message.Debugf("Wrote the resolved package inputs to %s", pc.lockPath)
+		return nil
+	}
+
+	locked, err := ReadLock(pc.lockPath)
+	if err != nil {
+		return err
+	}
+	diffs := locked.Diff(*pc.lock)
+	if len(diffs) > 0 {
+		return fmt.Errorf("package inputs do not match %s:\n- %s", pc.lockPath, strings.Join(diffs, "\n- "))
+	}
+	message.Successf("Package inputs match %s", pc.lockPath)
+	return nil
+}
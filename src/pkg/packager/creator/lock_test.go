@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package creator
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	zarfTypes "github.com/zarf-dev/zarf/src/types"
+)
+
+func TestLockDiff(t *testing.T) {
+	t.Parallel()
+
+	locked := Lock{
+		Architecture: "amd64",
+		Images: []LockEntry{
+			{Source: "ghcr.io/stefanprodan/podinfo:6.4.0", Digest: "sha256:aaa"},
+			{Source: "ghcr.io/zarf-dev/zarf/agent:v0.38.1", Digest: "sha256:bbb"},
+		},
+		Repos: []LockEntry{
+			{Component: "git", Source: "https://github.com/zarf-dev/zarf.git@refs/heads/main", Digest: "1111"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		resolved      Lock
+		expectedDiffs []string
+	}{
+		{
+			name:          "same inputs",
+			resolved:      locked,
+			expectedDiffs: []string{},
+		},
+		{
+			name: "drifted inputs",
+			resolved: Lock{
+				Architecture: "arm64",
+				Images: []LockEntry{
+					{Source: "ghcr.io/stefanprodan/podinfo:6.4.0", Digest: "sha256:ccc"},
+					{Source: "ghcr.io/stefanprodan/podinfo:6.4.1", Digest: "sha256:ddd"},
+				},
+				Repos: []LockEntry{
+					{Component: "git", Source: "https://github.com/zarf-dev/zarf.git@refs/heads/main", Digest: "2222"},
+				},
+			},
+			expectedDiffs: []string{
+				`architecture "arm64" does not match the locked architecture "amd64"`,
+				"image ghcr.io/stefanprodan/podinfo:6.4.0 resolved to sha256:ccc but is locked to sha256:aaa",
+				"image ghcr.io/stefanprodan/podinfo:6.4.1 is not in the lock",
+				"image ghcr.io/zarf-dev/zarf/agent:v0.38.1 is locked but no longer used",
+				`repo https://github.com/zarf-dev/zarf.git@refs/heads/main in component "git" resolved to 2222 but is locked to 1111`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expectedDiffs, locked.Diff(tt.resolved))
+		})
+	}
+}
+
+func TestLockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	lock := Lock{
+		Architecture: "amd64",
+		Flavor:       "upstream",
+		Files: []LockEntry{
+			{Component: "b", Source: "https://example.com/b.yaml", Digest: "sha256:bbb"},
+			{Component: "a", Source: "https://example.com/z.yaml", Digest: "sha256:zzz"},
+			{Component: "a", Source: "https://example.com/a.yaml", Digest: "sha256:aaa"},
+		},
+		Imports: []LockEntry{
+			{Component: "a", Source: "oci://ghcr.io/zarf-dev/packages/dos-games:1.0.0", Digest: "sha256:ccc"},
+		},
+	}
+	lock.sort()
+	require.Equal(t, []string{"https://example.com/a.yaml", "https://example.com/z.yaml", "https://example.com/b.yaml"},
+		[]string{lock.Files[0].Source, lock.Files[1].Source, lock.Files[2].Source})
+
+	path := filepath.Join(t.TempDir(), "zarf-lock.yaml")
+	require.NoError(t, utils.WriteYaml(path, lock, 0o600))
+	read, err := ReadLock(path)
+	require.NoError(t, err)
+	require.Equal(t, lock, read)
+	require.Empty(t, lock.Diff(read))
+}
+
+func TestResolveComponentFiles(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	t.Cleanup(srv.Close)
+
+	local := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(local, []byte("local"), 0o600))
+
+	component := v1alpha1.ZarfComponent{
+		Name:  "files",
+		Files: []v1alpha1.ZarfFile{{Source: srv.URL + "/bin", Target: "bin"}},
+		Manifests: []v1alpha1.ZarfManifest{
+			{Name: "remote", Files: []string{srv.URL + "/manifest.yaml", local}},
+		},
+	}
+	pc := NewPackageCreator(zarfTypes.ZarfCreateOptions{}, "")
+	require.NoError(t, pc.resolveComponent(context.Background(), component, layout.New(t.TempDir())))
+
+	// Only remote inputs are locked, by the digest of their contents.
+	require.Equal(t, []LockEntry{
+		{Component: "files", Source: srv.URL + "/bin", Digest: "sha256:" + fmt.Sprintf("%x", sha256.Sum256([]byte("/bin")))},
+		{Component: "files", Source: srv.URL + "/manifest.yaml", Digest: "sha256:" + fmt.Sprintf("%x", sha256.Sum256([]byte("/manifest.yaml")))},
+	}, pc.lock.Files)
+}
+
+func TestLockPath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "zarf-lock.yaml", lockPath(""))
+	require.Equal(t, "zarf-lock.upstream.yaml", lockPath("upstream"))
+}
@@ -13,29 +13,37 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/defenseunicorns/pkg/oci"
 	"github.com/mholt/archiver/v3"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/extensions/bigbang"
 	"github.com/zarf-dev/zarf/src/internal/git"
+	"github.com/zarf-dev/zarf/src/internal/packager/entitlement"
+	"github.com/zarf-dev/zarf/src/internal/packager/gate"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
 	"github.com/zarf-dev/zarf/src/internal/packager/images"
 	"github.com/zarf-dev/zarf/src/internal/packager/kustomize"
+	"github.com/zarf-dev/zarf/src/internal/packager/policy"
 	"github.com/zarf-dev/zarf/src/internal/packager/sbom"
+	"github.com/zarf-dev/zarf/src/internal/packager/sumdb"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
+	"github.com/zarf-dev/zarf/src/pkg/packager/features"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -45,7 +53,13 @@ var (
 
 // PackageCreator provides methods for creating normal (not skeleton) Zarf packages.
 type PackageCreator struct {
-	createOpts types.ZarfCreateOptions
+	createOpts       types.ZarfCreateOptions
+	lock             *Lock
+	lockPath         string
+	architectures    []string
+	definitionDigest string
+	startedOn        time.Time
+	sums             *sumdb.DB
 }
 
 func updateRelativeDifferentialPackagePath(path string, cwd string) string {
@@ -55,27 +69,64 @@ func updateRelativeDifferentialPackagePath(path string, cwd string) string {
 	return path
 }
 
+// lockPath returns where the lock of a create is read from and written to, relative to the package directory. Each
+// flavor records different inputs, so flavored creates use a lock of their own.
+func lockPath(flavor string) string {
+	if flavor == "" {
+		return layout.ZarfLock
+	}
+	return strings.TrimSuffix(layout.ZarfLock, ".yaml") + "." + flavor + ".yaml"
+}
+
 // NewPackageCreator returns a new PackageCreator.
 func NewPackageCreator(createOpts types.ZarfCreateOptions, cwd string) *PackageCreator {
 	createOpts.DifferentialPackagePath = updateRelativeDifferentialPackagePath(createOpts.DifferentialPackagePath, cwd)
-	return &PackageCreator{createOpts}
+	return &PackageCreator{createOpts: createOpts, lock: &Lock{}, lockPath: lockPath(createOpts.Flavor)}
 }
 
 // LoadPackageDefinition loads and configures a zarf.yaml file during package create.
 func (pc *PackageCreator) LoadPackageDefinition(ctx context.Context, src *layout.PackagePaths) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	pc.startedOn = time.Now()
 	pkg, warnings, err = src.ReadZarfYAML()
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, nil, err
 	}
+	definitionSum, err := helpers.GetSHA256OfFile(src.ZarfYAML)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	pc.definitionDigest = "sha256:" + definitionSum
+
+	pkg.Metadata.Architecture, pkg.Metadata.Architectures = resolveArchitectures(pkg.Metadata, pc.createOpts.Architectures)
+	if pkg.IsMultiArch() && pkg.IsInitConfig() {
+		return v1alpha1.ZarfPackage{}, nil, errors.New(lang.PkgCreateErrMultiArchInit)
+	}
+	pc.architectures = pkg.Metadata.Architectures
+
+	if (pc.createOpts.EncryptionKeyPath != "" || pc.createOpts.EncryptionPassphrase != "") && helpers.IsOCIURL(pc.createOpts.Output) {
+		return v1alpha1.ZarfPackage{}, nil, errors.New(lang.PkgCreateErrEncryptOCI)
+	}
+
+	if err := sbom.ValidateFormats(pc.createOpts.SBOMFormats); err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
 
-	pkg.Metadata.Architecture = config.GetArch(pkg.Metadata.Architecture)
+	// Fail early instead of after pulling every input if there is nothing to check against.
+	if pc.createOpts.Locked {
+		if _, err := ReadLock(pc.lockPath); err != nil {
+			return v1alpha1.ZarfPackage{}, nil, err
+		}
+	}
+	pc.lock.Architecture = pkg.Metadata.Architecture
+	pc.lock.Flavor = pc.createOpts.Flavor
 
 	// Compose components into a single zarf.yaml file
-	pkg, composeWarnings, err := ComposeComponents(ctx, pkg, pc.createOpts.Flavor)
+	pkg, composeWarnings, imports, err := composeComponents(ctx, pkg, pc.createOpts.Flavor, pc.createOpts.SetVariables, nil)
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, nil, err
 	}
 	warnings = append(warnings, composeWarnings...)
+	pc.lock.Imports = imports
 
 	// After components are composed, template the active package.
 	pkg, templateWarnings, err := FillActiveTemplate(pkg, pc.createOpts.SetVariables)
@@ -85,12 +136,36 @@ func (pc *PackageCreator) LoadPackageDefinition(ctx context.Context, src *layout
 
 	warnings = append(warnings, templateWarnings...)
 
+	// Entitlement tokens are verified offline on deploy, so a key file is included in the package definition.
+	if pkg.Metadata.EntitlementKey != "" {
+		key, err := entitlement.ReadKey(pkg.Metadata.EntitlementKey)
+		if err != nil {
+			return v1alpha1.ZarfPackage{}, nil, err
+		}
+		if _, err := entitlement.ParsePublicKey(key); err != nil {
+			return v1alpha1.ZarfPackage{}, nil, err
+		}
+		pkg.Metadata.EntitlementKey = key
+	}
+
 	// After templates are filled process any create extensions
 	pkg.Components, err = pc.processExtensions(ctx, pkg.Components, src, pkg.Metadata.YOLO)
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, nil, err
 	}
 
+	// Collect the signatures and attestations of images so they can be mirrored alongside them.
+	if pc.createOpts.IncludeSignatures {
+		// Images are pulled with the registry overrides of create rather than the registry mirrors, so look their
+		// artifacts up in the registries they were referenced from.
+		pkg.Components, err = addCosignArtifacts(pkg.Components, func(image string) ([]string, error) {
+			return utils.GetCosignArtifacts(image, nil)
+		})
+		if err != nil {
+			return v1alpha1.ZarfPackage{}, nil, err
+		}
+	}
+
 	// If we are creating a differential package, remove duplicate images and repos.
 	if pc.createOpts.DifferentialPackagePath != "" {
 		pkg.Build.Differential = true
@@ -101,6 +176,7 @@ func (pc *PackageCreator) LoadPackageDefinition(ctx context.Context, src *layout
 		}
 
 		pkg.Build.DifferentialPackageVersion = diffData.DifferentialPackageVersion
+		pkg.Build.DifferentialPackageChecksum = diffData.DifferentialPackageChecksum
 
 		versionsMatch := diffData.DifferentialPackageVersion == pkg.Metadata.Version
 		if versionsMatch {
@@ -128,10 +204,25 @@ func (pc *PackageCreator) LoadPackageDefinition(ctx context.Context, src *layout
 
 // Assemble assembles all of the package assets into Zarf's tmp directory layout.
 func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths, components []v1alpha1.ZarfComponent, arch string) error {
-	var imageList []transform.Image
+	// Multi-architecture packages pull images for each of their architectures.
+	archs := []string{arch}
+	if arch == v1alpha1.MultiArch {
+		archs = pc.architectures
+	}
+	imageLists := map[string][]transform.Image{}
+	artifactList := []transform.Image{}
 
 	skipSBOMFlagUsed := pc.createOpts.SkipSBOM
 	componentSBOMs := map[string]*layout.ComponentSBOM{}
+	imageSBOMRules := map[string]v1alpha1.ZarfComponentSBOM{}
+
+	if pc.createOpts.ChecksumDB {
+		sums, err := sumdb.Load(sumdb.Path())
+		if err != nil {
+			return err
+		}
+		pc.sums = sums
+	}
 
 	for _, component := range components {
 		onCreate := component.Actions.OnCreate
@@ -152,12 +243,12 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 			return fmt.Errorf("unable to run component success action: %w", err)
 		}
 
-		if !skipSBOMFlagUsed {
+		if !skipSBOMFlagUsed && !component.SBOM.Skip {
 			componentSBOM, err := pc.getFilesToSBOM(component, dst)
 			if err != nil {
 				return fmt.Errorf("unable to create component SBOM: %w", err)
 			}
-			if componentSBOM != nil && len(componentSBOM.Files) > 0 {
+			if componentSBOM != nil && (len(componentSBOM.Files) > 0 || len(componentSBOM.Repos) > 0) {
 				componentSBOMs[component.Name] = componentSBOM
 			}
 		}
@@ -168,55 +259,126 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 			if err != nil {
 				return fmt.Errorf("failed to create ref for image %s: %w", src, err)
 			}
-			imageList = append(imageList, refInfo)
+			for _, imageArch := range archs {
+				if component.Only.Cluster.Architecture == "" || component.Only.Cluster.Architecture == imageArch {
+					imageLists[imageArch] = append(imageLists[imageArch], refInfo)
+				}
+			}
+			imageSBOMRules[refInfo.Reference] = mergeSBOMRules(imageSBOMRules[refInfo.Reference], component.SBOM)
+		}
+		for _, src := range component.Artifacts {
+			refInfo, err := transform.ParseImageRef(src)
+			if err != nil {
+				return fmt.Errorf("failed to create ref for artifact %s: %w", src, err)
+			}
+			artifactList = append(artifactList, refInfo)
+		}
+	}
+
+	if pc.sums != nil {
+		if err := pc.sums.Save(); err != nil {
+			return fmt.Errorf("unable to write the checksum database: %w", err)
 		}
 	}
 
-	imageList = helpers.Unique(imageList)
-	rs := rand.NewSource(time.Now().UnixNano())
-	rnd := rand.New(rs)
-	rnd.Shuffle(len(imageList), func(i, j int) { imageList[i], imageList[j] = imageList[j], imageList[i] })
 	var sbomImageList []transform.Image
 
 	// Images are handled separately from other component assets.
-	if len(imageList) > 0 {
+	if len(imageLists) > 0 {
 		message.HeaderInfof("📦 PACKAGE IMAGES")
 
 		dst.AddImages()
 
+		rs := rand.NewSource(time.Now().UnixNano())
+		rnd := rand.New(rs)
+		for _, imageArch := range archs {
+			imageList := helpers.Unique(imageLists[imageArch])
+			if len(imageList) == 0 {
+				continue
+			}
+			rnd.Shuffle(len(imageList), func(i, j int) { imageList[i], imageList[j] = imageList[j], imageList[i] })
+			if arch == v1alpha1.MultiArch {
+				message.Infof("Pulling %d images for %s", len(imageList), imageArch)
+			}
+
+			pullCfg := images.PullConfig{
+				DestinationDirectory: dst.Images.Base,
+				ImageList:            imageList,
+				Arch:                 imageArch,
+				RecordPlatform:       arch == v1alpha1.MultiArch,
+				RegistryOverrides:    pc.createOpts.RegistryOverrides,
+				CacheDirectory:       filepath.Join(config.GetAbsCachePath(), layout.ImagesDir),
+			}
+
+			pulled, err := images.Pull(ctx, pullCfg)
+			if err != nil {
+				return err
+			}
+
+			for info, img := range pulled {
+				if err := dst.Images.AddV1Image(img); err != nil {
+					return err
+				}
+				digest, err := img.Digest()
+				if err != nil {
+					return fmt.Errorf("unable to get digest for image %s: %w", info.Reference, err)
+				}
+				entry := LockEntry{Source: info.Reference, Digest: digest.String()}
+				if pullCfg.RecordPlatform {
+					entry.Architecture = imageArch
+				}
+				pc.lock.Images = append(pc.lock.Images, entry)
+				ok, err := utils.OnlyHasImageLayers(img)
+				if err != nil {
+					return fmt.Errorf("failed to validate %s is an image and not an artifact: %w", info, err)
+				}
+				if ok && !imageSBOMRules[info.Reference].Skip {
+					sbomImageList = append(sbomImageList, info)
+				}
+			}
+		}
+		sbomImageList = helpers.Unique(sbomImageList)
+	}
+
+	// Artifacts are stored alongside images, but are not tied to an architecture and are not cataloged.
+	if len(artifactList) > 0 {
+		message.HeaderInfof("📦 PACKAGE ARTIFACTS")
+
+		dst.AddImages()
+
 		pullCfg := images.PullConfig{
 			DestinationDirectory: dst.Images.Base,
-			ImageList:            imageList,
-			Arch:                 arch,
+			ImageList:            helpers.Unique(artifactList),
+			Arch:                 archs[0],
 			RegistryOverrides:    pc.createOpts.RegistryOverrides,
 			CacheDirectory:       filepath.Join(config.GetAbsCachePath(), layout.ImagesDir),
 		}
-
 		pulled, err := images.Pull(ctx, pullCfg)
 		if err != nil {
 			return err
 		}
-
 		for info, img := range pulled {
 			if err := dst.Images.AddV1Image(img); err != nil {
 				return err
 			}
-			ok, err := utils.OnlyHasImageLayers(img)
+			digest, err := img.Digest()
 			if err != nil {
-				return fmt.Errorf("failed to validate %s is an image and not an artifact: %w", info, err)
-			}
-			if ok {
-				sbomImageList = append(sbomImageList, info)
+				return fmt.Errorf("unable to get digest for artifact %s: %w", info.Reference, err)
 			}
+			pc.lock.Images = append(pc.lock.Images, LockEntry{Source: info.Reference, Digest: digest.String()})
 		}
 	}
 
+	if err := pc.finalizeLock(); err != nil {
+		return err
+	}
+
 	// Ignore SBOM creation if the flag is set.
 	if skipSBOMFlagUsed {
 		message.Debug("Skipping image SBOM processing per --skip-sbom flag")
 	} else {
 		dst.AddSBOMs()
-		if err := sbom.Catalog(componentSBOMs, sbomImageList, dst); err != nil {
+		if err := sbom.Catalog(componentSBOMs, sbomImageList, imageSBOMRules, pc.createOpts.SBOMFormats, dst); err != nil {
 			return fmt.Errorf("unable to create an SBOM catalog for the package: %w", err)
 		}
 	}
@@ -228,6 +390,8 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 //
 // - archives components
 //
+// - records the provenance of the package
+//
 // - generates checksums for all package files
 //
 // - writes the loaded zarf.yaml to disk
@@ -237,6 +401,26 @@ func (pc *PackageCreator) Assemble(ctx context.Context, dst *layout.PackagePaths
 // - writes the Zarf package as a tarball to a local directory,
 // or an OCI registry based on the --output flag
 func (pc *PackageCreator) Output(ctx context.Context, dst *layout.PackagePaths, pkg *v1alpha1.ZarfPackage) (err error) {
+	// When the output is OCI the layers of the package are pushed as soon as they are final and removed from disk,
+	// instead of the package being written to a local tarball first.
+	var remote *zoci.Remote
+	var pushed []ocispec.Descriptor
+	if helpers.IsOCIURL(pc.createOpts.Output) {
+		ref, err := zoci.ReferenceFromMetadata(pc.createOpts.Output, &pkg.Metadata, &v1alpha1.ZarfBuildData{Flavor: pc.createOpts.Flavor})
+		if err != nil {
+			return err
+		}
+		remote, err = zoci.NewRemote(ref, oci.PlatformForArch(config.GetArch()))
+		if err != nil {
+			return err
+		}
+		descs, err := pushPackageFiles(ctx, remote, dst, dst.Images.Blobs, true)
+		if err != nil {
+			return err
+		}
+		pushed = append(pushed, descs...)
+	}
+
 	// Process the component directories into compressed tarballs
 	// NOTE: This is purposefully being done after the SBOM cataloging
 	for _, component := range pkg.Components {
@@ -244,6 +428,18 @@ func (pc *PackageCreator) Output(ctx context.Context, dst *layout.PackagePaths,
 		if err := dst.Components.Archive(component, true); err != nil {
 			return fmt.Errorf("unable to archive component: %s", err.Error())
 		}
+		if tarball, ok := dst.Components.Tarballs[component.Name]; ok && remote != nil {
+			descs, err := pushPackageFiles(ctx, remote, dst, []string{tarball}, true)
+			if err != nil {
+				return err
+			}
+			pushed = append(pushed, descs...)
+		}
+	}
+
+	// Record the provenance before the checksums so that the package signature covers it
+	if err := pc.writeProvenance(dst, pkg); err != nil {
+		return err
 	}
 
 	// Calculate all the checksums
@@ -256,28 +452,30 @@ func (pc *PackageCreator) Output(ctx context.Context, dst *layout.PackagePaths,
 		return err
 	}
 
+	// Record the features the package relies on so that the deploying version of Zarf can check them.
+	pkg.Build.Features = features.Detect(*pkg, len(pc.lock.Imports) > 0)
+
 	if err := utils.WriteYaml(dst.ZarfYAML, pkg, helpers.ReadUser); err != nil {
 		return fmt.Errorf("unable to write zarf.yaml: %w", err)
 	}
 
-	// Sign the package if a key has been provided
-	if err := dst.SignPackage(pc.createOpts.SigningKeyPath, pc.createOpts.SigningKeyPassword, !config.CommonOptions.Confirm); err != nil {
+	// Sign the package if a key has been provided or keyless signing was requested
+	if err := dst.SignPackage(pc.createOpts.SigningKeyPath, pc.createOpts.SigningKeyPassword, pc.createOpts.Keyless, !config.CommonOptions.Confirm); err != nil {
 		return err
 	}
 
-	// Create a remote ref + client for the package (if output is OCI)
-	// then publish the package to the remote.
-	if helpers.IsOCIURL(pc.createOpts.Output) {
-		ref, err := zoci.ReferenceFromMetadata(pc.createOpts.Output, &pkg.Metadata, &pkg.Build)
-		if err != nil {
-			return err
+	// Push the remaining layers of the package (if output is OCI) then publish the package manifest.
+	if remote != nil {
+		files := []string{}
+		for _, path := range dst.Files() {
+			files = append(files, path)
 		}
-		remote, err := zoci.NewRemote(ref, oci.PlatformForArch(config.GetArch()))
+		descs, err := pushPackageFiles(ctx, remote, dst, files, false)
 		if err != nil {
 			return err
 		}
-		err = remote.PublishPackage(ctx, pkg, dst, config.CommonOptions.OCIConcurrency)
-		if err != nil {
+		pushed = append(pushed, descs...)
+		if err := remote.PublishPackageLayers(ctx, pkg, pushed); err != nil {
 			return fmt.Errorf("unable to publish package: %w", err)
 		}
 		message.HorizontalRule()
@@ -300,8 +498,18 @@ func (pc *PackageCreator) Output(ctx context.Context, dst *layout.PackagePaths,
 		// Try to remove the package if it already exists.
 		_ = os.Remove(tarballPath)
 
+		encryptionSecret, err := utils.ReadEncryptionSecret(pc.createOpts.EncryptionPassphrase, pc.createOpts.EncryptionKeyPath)
+		if err != nil {
+			return err
+		}
+
+		maxPartSize, err := MaxPartSize(pc.createOpts)
+		if err != nil {
+			return err
+		}
+
 		// Create the package tarball.
-		if err := dst.ArchivePackage(tarballPath, pc.createOpts.MaxPackageSizeMB); err != nil {
+		if err := dst.ArchivePackage(tarballPath, maxPartSize, encryptionSecret); err != nil {
 			return fmt.Errorf("unable to archive package: %w", err)
 		}
 	}
@@ -373,6 +581,9 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 		if err := helmCfg.PackageChart(ctx, componentPaths.Charts); err != nil {
 			return err
 		}
+		if err := pc.lockChart(ctx, component.Name, chart, componentPaths); err != nil {
+			return err
+		}
 	}
 
 	for filesIdx, file := range component.Files {
@@ -394,6 +605,12 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 				if err := utils.DownloadToFile(ctx, file.Source, compressedFile, component.DeprecatedCosignKeyPath); err != nil {
 					return fmt.Errorf(lang.ErrDownloading, file.Source, err.Error())
 				}
+				if err := pc.lockFile(component.Name, file.Source, compressedFile); err != nil {
+					return err
+				}
+				if err := pc.verifyChecksum(file, compressedFile); err != nil {
+					return err
+				}
 
 				err = archiver.Extract(compressedFile, file.ExtractPath, destinationDir)
 				if err != nil {
@@ -403,6 +620,12 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 				if err := utils.DownloadToFile(ctx, file.Source, dst, component.DeprecatedCosignKeyPath); err != nil {
 					return fmt.Errorf(lang.ErrDownloading, file.Source, err.Error())
 				}
+				if err := pc.lockFile(component.Name, file.Source, dst); err != nil {
+					return err
+				}
+				if err := pc.verifyChecksum(file, dst); err != nil {
+					return err
+				}
 			}
 		} else {
 			if file.ExtractPath != "" {
@@ -454,6 +677,9 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 				if err := utils.DownloadToFile(ctx, data.Source, dst, component.DeprecatedCosignKeyPath); err != nil {
 					return fmt.Errorf(lang.ErrDownloading, data.Source, err.Error())
 				}
+				if err := pc.lockFile(component.Name, data.Source, dst); err != nil {
+					return err
+				}
 			} else {
 				if err := helpers.CreatePathAndCopy(data.Source, dst); err != nil {
 					return fmt.Errorf("unable to copy data injection %s: %s", data.Source, err.Error())
@@ -487,6 +713,9 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 					if err := utils.DownloadToFile(ctx, path, dst, component.DeprecatedCosignKeyPath); err != nil {
 						return fmt.Errorf(lang.ErrDownloading, path, err.Error())
 					}
+					if err := pc.lockFile(component.Name, path, dst); err != nil {
+						return err
+					}
 				} else {
 					if err := helpers.CreatePathAndCopy(path, dst); err != nil {
 						return fmt.Errorf("unable to copy manifest %s: %w", path, err)
@@ -510,19 +739,103 @@ func (pc *PackageCreator) addComponent(ctx context.Context, component v1alpha1.Z
 		spinner.Success()
 	}
 
+	if len(component.Policies) > 0 {
+		spinner := message.NewProgressSpinner("Loading %d policy bundles", len(component.Policies))
+		defer spinner.Stop()
+
+		for _, p := range component.Policies {
+			dsts := []string{}
+			for fileIdx, path := range p.Files {
+				rel := filepath.Join(layout.PoliciesDir, fmt.Sprintf("%s-%d.yaml", p.Name, fileIdx))
+				dst := filepath.Join(componentPaths.Base, rel)
+
+				spinner.Updatef("Copying policy %s", path)
+				if helpers.IsURL(path) {
+					if err := utils.DownloadToFile(ctx, path, dst, component.DeprecatedCosignKeyPath); err != nil {
+						return fmt.Errorf(lang.ErrDownloading, path, err.Error())
+					}
+					if err := pc.lockFile(component.Name, path, dst); err != nil {
+						return err
+					}
+				} else {
+					if err := helpers.CreatePathAndCopy(path, dst); err != nil {
+						return fmt.Errorf("unable to copy policy %s: %w", path, err)
+					}
+				}
+				dsts = append(dsts, dst)
+			}
+
+			spinner.Updatef("Validating %s policy bundle %s", p.Engine, p.Name)
+			objs, err := policy.ReadFiles(dsts...)
+			if err != nil {
+				return err
+			}
+			if err := policy.Validate(p.Engine, objs); err != nil {
+				return fmt.Errorf("invalid policy bundle %s: %w", p.Name, err)
+			}
+		}
+		spinner.Success()
+	}
+
+	if len(component.PolicyGates) > 0 {
+		spinner := message.NewProgressSpinner("Loading %d policy gates", len(component.PolicyGates))
+		defer spinner.Stop()
+
+		for _, g := range component.PolicyGates {
+			dsts := []string{}
+			for fileIdx, path := range g.Files {
+				rel := filepath.Join(layout.PolicyGatesDir, gate.FileName(g, fileIdx))
+				dst := filepath.Join(componentPaths.Base, rel)
+
+				spinner.Updatef("Copying policy gate %s", path)
+				if helpers.IsURL(path) {
+					if err := utils.DownloadToFile(ctx, path, dst, component.DeprecatedCosignKeyPath); err != nil {
+						return fmt.Errorf(lang.ErrDownloading, path, err.Error())
+					}
+					if err := pc.lockFile(component.Name, path, dst); err != nil {
+						return err
+					}
+				} else {
+					if err := helpers.CreatePathAndCopy(path, dst); err != nil {
+						return fmt.Errorf("unable to copy policy gate %s: %w", path, err)
+					}
+				}
+				dsts = append(dsts, dst)
+			}
+
+			// Compile the policies so that mistakes in them fail the create rather than the deploy.
+			spinner.Updatef("Validating %s policy gate %s", g.Engine, g.Name)
+			if _, err := gate.Load(ctx, g.Name, g.Engine, dsts...); err != nil {
+				return err
+			}
+		}
+		spinner.Success()
+	}
+
 	// Load all specified git repos.
 	if len(component.Repos) > 0 {
 		spinner := message.NewProgressSpinner("Loading %d git repos", len(component.Repos))
 		defer spinner.Stop()
 
+		cacheDir := filepath.Join(config.GetAbsCachePath(), layout.ReposDir)
+		cached := 0
 		for _, url := range component.Repos {
 			// Pull all the references if there is no `@` in the string.
-			_, err := git.Clone(ctx, componentPaths.Repos, url, false)
+			repo, hit, err := git.CloneWithCache(ctx, cacheDir, componentPaths.Repos, url)
 			if err != nil {
 				return fmt.Errorf("unable to pull git repo %s: %w", url, err)
 			}
+			if hit {
+				cached++
+			}
+			// The commit that was cloned is locked rather than what the ref resolves to on the remote afterwards.
+			commit, err := repo.Head()
+			if err != nil {
+				return fmt.Errorf("unable to resolve repo %s for the lock: %w", url, err)
+			}
+			pc.lockRepo(component.Name, url, commit)
 		}
-		spinner.Success()
+		spinner.Successf("Loaded %d git repos (%d from the cache)", len(component.Repos), cached)
 	}
 
 	if err := actions.Run(ctx, onCreate.Defaults, onCreate.After, nil); err != nil {
@@ -540,7 +853,9 @@ func (pc *PackageCreator) getFilesToSBOM(component v1alpha1.ZarfComponent, dst *
 	// Create an struct to hold the SBOM information for this component.
 	componentSBOM := &layout.ComponentSBOM{
 		Files:     []string{},
+		Repos:     []string{},
 		Component: componentPaths,
+		Rules:     component.SBOM,
 	}
 
 	appendSBOMFiles := func(path string) {
@@ -563,5 +878,80 @@ func (pc *PackageCreator) getFilesToSBOM(component v1alpha1.ZarfComponent, dst *
 		appendSBOMFiles(path)
 	}
 
+	// Git repositories are cataloged as directories so that their dependency manifests are discovered.
+	for _, repoURL := range component.Repos {
+		repoFolder, err := transform.GitURLtoFolderName(repoURL)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(componentPaths.Repos, repoFolder)
+		if helpers.IsDir(path) {
+			componentSBOM.Repos = append(componentSBOM.Repos, path)
+		}
+	}
+
 	return componentSBOM, nil
 }
+
+// pushPackageFiles concurrently pushes the given files of the package to the remote as package layers, optionally
+// removing each of them from disk once it has been pushed.
+func pushPackageFiles(ctx context.Context, remote *zoci.Remote, dst *layout.PackagePaths, files []string, remove bool) ([]ocispec.Descriptor, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	spinner := message.NewProgressSpinner("Pushing %d layers to %s", len(files), remote.Repo().Reference)
+	defer spinner.Stop()
+
+	var mu sync.Mutex
+	descs := []ocispec.Descriptor{}
+	eg, ectx := errgroup.WithContext(ctx)
+	eg.SetLimit(max(config.CommonOptions.OCIConcurrency, 1))
+	for _, path := range files {
+		path := path
+		eg.Go(func() error {
+			rel, err := filepath.Rel(dst.Base, path)
+			if err != nil {
+				return err
+			}
+			desc, err := remote.PushFile(ectx, filepath.ToSlash(rel), path)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			descs = append(descs, desc)
+			spinner.Updatef("Pushed %s", helpers.First30Last30(filepath.ToSlash(rel)))
+			if remove {
+				return dst.RemovePushed(path, desc.Digest.Encoded())
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	spinner.Successf("Pushed %d layers to %s", len(files), remote.Repo().Reference)
+	return descs, nil
+}
+
+// verifyChecksum verifies a remote file without a shasum against the checksum database (if enabled), recording it the
+// first time it is fetched. Files with a shasum are already pinned and are verified against it instead.
+func (pc *PackageCreator) verifyChecksum(file v1alpha1.ZarfFile, path string) error {
+	if pc.sums == nil || file.Shasum != "" {
+		return nil
+	}
+	return pc.sums.Verify(file.Source, path)
+}
+
+// MaxPartSize returns the maximum size in bytes of each file of a package created with the given options, preferring the
+// maximum artifact size over the maximum package size in megabytes. A size of 0 disables splitting.
+func MaxPartSize(createOpts types.ZarfCreateOptions) (int64, error) {
+	if createOpts.MaxArtifactSize == "" {
+		return int64(createOpts.MaxPackageSizeMB) * 1000 * 1000, nil
+	}
+	size, err := utils.ParseByteSize(createOpts.MaxArtifactSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maximum artifact size: %w", err)
+	}
+	return size, nil
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/common"
+	slsa "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+const (
+	// ProvenanceStatementType is the in-toto statement type of the provenance recorded in packages.
+	ProvenanceStatementType = "https://in-toto.io/Statement/v1"
+	// ProvenanceBuildType is the SLSA build type of packages created by Zarf.
+	ProvenanceBuildType = "https://zarf.dev/package-create/v1"
+	// ProvenanceBuilderID identifies Zarf as the builder of packages in their provenance.
+	ProvenanceBuilderID = "https://github.com/zarf-dev/zarf"
+)
+
+// ProvenanceParameters are the options package create was run with that changed what the package contains.
+type ProvenanceParameters struct {
+	// The architecture the package was created for.
+	Architecture string `json:"architecture"`
+	// The flavor the package was created with.
+	Flavor string `json:"flavor,omitempty"`
+	// The names of the package templates that were set, their values are left out as they may be sensitive.
+	SetVariables []string `json:"setVariables,omitempty"`
+	// The registry domains that were overridden when pulling images.
+	RegistryOverrides map[string]string `json:"registryOverrides,omitempty"`
+	// The version of the package that a differential package was created against.
+	DifferentialPackageVersion string `json:"differentialPackageVersion,omitempty"`
+	// Whether the inputs of the package were checked against zarf-lock.yaml.
+	Locked bool `json:"locked,omitempty"`
+}
+
+// newProvenance creates the SLSA provenance of a package from the digest of its definition, the inputs it resolved to
+// and the digests of the package files it describes.
+func newProvenance(pkg v1alpha1.ZarfPackage, params ProvenanceParameters, definitionDigest string, lock Lock, subjects map[string]string, startedOn, finishedOn time.Time) in_toto.ProvenanceStatementSLSA1 {
+	statementSubjects := []in_toto.Subject{}
+	for name, digest := range subjects {
+		statementSubjects = append(statementSubjects, in_toto.Subject{Name: name, Digest: parseDigest(digest)})
+	}
+	slices.SortFunc(statementSubjects, func(a, b in_toto.Subject) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	dependencies := []slsa.ResourceDescriptor{
+		{Name: layout.ZarfYAML, Digest: parseDigest(definitionDigest)},
+	}
+	addDependencies := func(kind string, entries []LockEntry) {
+		for _, e := range entries {
+			uri := e.Source
+			switch kind {
+			case "image":
+				uri = "oci://" + e.Source
+			case "repo":
+				uri = "git+" + e.Source
+			}
+			annotations := map[string]interface{}{"kind": kind}
+			if e.Component != "" {
+				annotations["component"] = e.Component
+			}
+			if e.Architecture != "" {
+				annotations["architecture"] = e.Architecture
+			}
+			dependencies = append(dependencies, slsa.ResourceDescriptor{URI: uri, Digest: parseDigest(e.Digest), Annotations: annotations})
+		}
+	}
+	addDependencies("image", lock.Images)
+	addDependencies("chart", lock.Charts)
+	addDependencies("repo", lock.Repos)
+	addDependencies("file", lock.Files)
+	addDependencies("import", lock.Imports)
+
+	return in_toto.ProvenanceStatementSLSA1{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          ProvenanceStatementType,
+			PredicateType: slsa.PredicateSLSAProvenance,
+			Subject:       statementSubjects,
+		},
+		Predicate: slsa.ProvenancePredicate{
+			BuildDefinition: slsa.ProvenanceBuildDefinition{
+				BuildType:          ProvenanceBuildType,
+				ExternalParameters: params,
+				InternalParameters: map[string]string{
+					"package": pkg.Metadata.Name,
+					"version": pkg.Metadata.Version,
+				},
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: slsa.ProvenanceRunDetails{
+				Builder: slsa.Builder{
+					ID:      ProvenanceBuilderID,
+					Version: map[string]string{"zarf": config.CLIVersion},
+				},
+				BuildMetadata: slsa.BuildMetadata{
+					StartedOn:  &startedOn,
+					FinishedOn: &finishedOn,
+				},
+			},
+		},
+	}
+}
+
+// parseDigest converts an algorithm prefixed digest to a digest set, treating digests without an algorithm as git
+// commit SHAs.
+func parseDigest(digest string) common.DigestSet {
+	if algorithm, hex, ok := strings.Cut(digest, ":"); ok {
+		return common.DigestSet{algorithm: hex}
+	}
+	return common.DigestSet{"gitCommit": digest}
+}
+
+// writeProvenance records the SLSA provenance of the package in the package, it must be written after the components
+// are archived and before the checksums are generated so that the package signature covers it.
+func (pc *PackageCreator) writeProvenance(dst *layout.PackagePaths, pkg *v1alpha1.ZarfPackage) error {
+	// Images are described by their index as it holds the digest of every image manifest.
+	subjects := map[string]string{}
+	for rel, abs := range dst.Files() {
+		if rel != layout.IndexPath && rel != layout.SBOMTar && !strings.HasPrefix(rel, layout.ComponentsDir+"/") {
+			continue
+		}
+		sum, err := helpers.GetSHA256OfFile(abs)
+		if err != nil {
+			return err
+		}
+		subjects[rel] = "sha256:" + sum
+	}
+
+	setVariables := []string{}
+	for name := range pc.createOpts.SetVariables {
+		setVariables = append(setVariables, name)
+	}
+	slices.Sort(setVariables)
+	params := ProvenanceParameters{
+		Architecture:               pkg.Metadata.Architecture,
+		Flavor:                     pc.createOpts.Flavor,
+		SetVariables:               setVariables,
+		RegistryOverrides:          pc.createOpts.RegistryOverrides,
+		DifferentialPackageVersion: pkg.Build.DifferentialPackageVersion,
+		Locked:                     pc.createOpts.Locked,
+	}
+
+	provenance := newProvenance(*pkg, params, pc.definitionDigest, *pc.lock, subjects, pc.startedOn, time.Now())
+	b, err := json.MarshalIndent(provenance, "", "  ")
+	if err != nil {
+		return err
+	}
+	dst.Provenance = filepath.Join(dst.Base, layout.Provenance)
+	if err := os.WriteFile(dst.Provenance, b, helpers.ReadWriteUser); err != nil {
+		return fmt.Errorf("unable to write the package provenance: %w", err)
+	}
+	message.Debugf("Recorded the provenance of %d package files", len(subjects))
+	return nil
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package creator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/common"
+	slsa "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestNewProvenance(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{Metadata: v1alpha1.ZarfMetadata{Name: "podinfo", Version: "6.4.0"}}
+	lock := Lock{
+		Images: []LockEntry{
+			{Component: "podinfo", Source: "ghcr.io/stefanprodan/podinfo:6.4.0", Digest: "sha256:aaa", Architecture: "amd64"},
+		},
+		Repos: []LockEntry{
+			{Component: "podinfo", Source: "https://github.com/stefanprodan/podinfo.git@refs/tags/6.4.0", Digest: "1111"},
+		},
+	}
+	subjects := map[string]string{
+		"sboms.tar":              "sha256:ccc",
+		"components/podinfo.tar": "sha256:bbb",
+		"images/index.json":      "sha256:ddd",
+	}
+	params := ProvenanceParameters{Architecture: "amd64", SetVariables: []string{"NAME"}}
+	started := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	provenance := newProvenance(pkg, params, "sha256:eee", lock, subjects, started, started.Add(time.Minute))
+
+	require.Equal(t, ProvenanceStatementType, provenance.Type)
+	require.Equal(t, slsa.PredicateSLSAProvenance, provenance.PredicateType)
+	require.Len(t, provenance.Subject, 3)
+	require.Equal(t, "components/podinfo.tar", provenance.Subject[0].Name)
+	require.Equal(t, common.DigestSet{"sha256": "bbb"}, provenance.Subject[0].Digest)
+
+	deps := provenance.Predicate.BuildDefinition.ResolvedDependencies
+	require.Len(t, deps, 3)
+	require.Equal(t, slsa.ResourceDescriptor{Name: "zarf.yaml", Digest: common.DigestSet{"sha256": "eee"}}, deps[0])
+	require.Equal(t, "oci://ghcr.io/stefanprodan/podinfo:6.4.0", deps[1].URI)
+	require.Equal(t, map[string]interface{}{"kind": "image", "component": "podinfo", "architecture": "amd64"}, deps[1].Annotations)
+	require.Equal(t, "git+https://github.com/stefanprodan/podinfo.git@refs/tags/6.4.0", deps[2].URI)
+	require.Equal(t, common.DigestSet{"gitCommit": "1111"}, deps[2].Digest)
+
+	b, err := json.Marshal(provenance.Predicate.BuildDefinition.ExternalParameters)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"architecture":"amd64","setVariables":["NAME"]}`, string(b))
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// addCosignArtifacts appends the cosign signatures and attestations of each component's images to its image list
+// so that they are pulled into the package and pushed next to the images on deploy.
+func addCosignArtifacts(components []v1alpha1.ZarfComponent, getCosignArtifacts func(string) ([]string, error)) ([]v1alpha1.ZarfComponent, error) {
+	for idx, component := range components {
+		if len(component.Images) == 0 {
+			continue
+		}
+
+		spinner := message.NewProgressSpinner("Looking up cosign artifacts for %s images (0/%d)", component.Name, len(component.Images))
+		artifacts := []string{}
+		for imgIdx, image := range component.Images {
+			spinner.Updatef("Looking up cosign artifacts for %s images (%d/%d)", component.Name, imgIdx+1, len(component.Images))
+			if utils.IsCosignArtifact(image) {
+				continue
+			}
+			found, err := getCosignArtifacts(image)
+			if err != nil {
+				spinner.Stop()
+				return nil, fmt.Errorf("could not lookup the cosign artifacts for image %s: %w", image, err)
+			}
+			for _, artifact := range found {
+				if !slices.Contains(component.Images, artifact) && !slices.Contains(artifacts, artifact) {
+					artifacts = append(artifacts, artifact)
+				}
+			}
+		}
+		spinner.Successf("Found %d cosign artifacts for %s images", len(artifacts), component.Name)
+
+		components[idx].Images = append(component.Images, artifacts...)
+	}
+	return components, nil
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestAddCosignArtifacts(t *testing.T) {
+	t.Parallel()
+
+	artifacts := map[string][]string{
+		"ghcr.io/zarf-dev/zarf/agent:v0.38.1": {
+			"ghcr.io/zarf-dev/zarf/agent:sha256-f8b1c2f99349516ae1bd0711a19697abcc41555076b0ae90f1a70ca6b50dcbd8.sig",
+			"ghcr.io/zarf-dev/zarf/agent:sha256-f8b1c2f99349516ae1bd0711a19697abcc41555076b0ae90f1a70ca6b50dcbd8.att",
+		},
+	}
+	lookup := func(image string) ([]string, error) {
+		if image == "bad:image" {
+			return nil, errors.New("lookup failed")
+		}
+		return artifacts[image], nil
+	}
+
+	components := []v1alpha1.ZarfComponent{
+		{
+			Name: "signed",
+			Images: []string{
+				"ghcr.io/zarf-dev/zarf/agent:v0.38.1",
+				"ghcr.io/zarf-dev/zarf/agent:sha256-f8b1c2f99349516ae1bd0711a19697abcc41555076b0ae90f1a70ca6b50dcbd8.sig",
+			},
+		},
+		{
+			Name:   "unsigned",
+			Images: []string{"docker.io/library/alpine:latest"},
+		},
+		{
+			Name: "empty",
+		},
+	}
+	components, err := addCosignArtifacts(components, lookup)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"ghcr.io/zarf-dev/zarf/agent:v0.38.1",
+		"ghcr.io/zarf-dev/zarf/agent:sha256-f8b1c2f99349516ae1bd0711a19697abcc41555076b0ae90f1a70ca6b50dcbd8.sig",
+		"ghcr.io/zarf-dev/zarf/agent:sha256-f8b1c2f99349516ae1bd0711a19697abcc41555076b0ae90f1a70ca6b50dcbd8.att",
+	}, components[0].Images)
+	require.Equal(t, []string{"docker.io/library/alpine:latest"}, components[1].Images)
+	require.Empty(t, components[2].Images)
+
+	_, err = addCosignArtifacts([]v1alpha1.ZarfComponent{{Name: "bad", Images: []string{"bad:image"}}}, lookup)
+	require.EqualError(t, err, "could not lookup the cosign artifacts for image bad:image: lookup failed")
+}
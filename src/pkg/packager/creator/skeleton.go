@@ -18,6 +18,7 @@ import (
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/extensions/bigbang"
+	"github.com/zarf-dev/zarf/src/internal/packager/gate"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
 	"github.com/zarf-dev/zarf/src/internal/packager/kustomize"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
@@ -53,7 +54,7 @@ func (sc *SkeletonCreator) LoadPackageDefinition(ctx context.Context, src *layou
 	pkg.Metadata.Architecture = config.GetArch()
 
 	// Compose components into a single zarf.yaml file
-	pkg, composeWarnings, err := ComposeComponents(ctx, pkg, sc.createOpts.Flavor)
+	pkg, composeWarnings, err := ComposeComponents(ctx, pkg, sc.createOpts.Flavor, sc.createOpts.SetVariables)
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, nil, err
 	}
@@ -123,7 +124,7 @@ func (sc *SkeletonCreator) Output(_ context.Context, dst *layout.PackagePaths, p
 		return fmt.Errorf("unable to write zarf.yaml: %w", err)
 	}
 
-	return dst.SignPackage(sc.publishOpts.SigningKeyPath, sc.publishOpts.SigningKeyPassword, !config.CommonOptions.Confirm)
+	return dst.SignPackage(sc.publishOpts.SigningKeyPath, sc.publishOpts.SigningKeyPassword, sc.publishOpts.Keyless, !config.CommonOptions.Confirm)
 }
 
 func (sc *SkeletonCreator) processExtensions(components []v1alpha1.ZarfComponent, layout *layout.PackagePaths) (processedComponents []v1alpha1.ZarfComponent, err error) {
@@ -324,5 +325,57 @@ func (sc *SkeletonCreator) addComponent(component v1alpha1.ZarfComponent, dst *l
 		spinner.Success()
 	}
 
+	if len(component.Policies) > 0 {
+		spinner := message.NewProgressSpinner("Loading %d policy bundles", len(component.Policies))
+		defer spinner.Stop()
+
+		for policyIdx, policy := range component.Policies {
+			for fileIdx, path := range policy.Files {
+				if helpers.IsURL(path) {
+					continue
+				}
+
+				rel := filepath.Join(layout.PoliciesDir, fmt.Sprintf("%s-%d.yaml", policy.Name, fileIdx))
+				dst := filepath.Join(componentPaths.Base, rel)
+
+				spinner.Updatef("Copying policy %s", path)
+
+				if err := helpers.CreatePathAndCopy(path, dst); err != nil {
+					return nil, fmt.Errorf("unable to copy policy %s: %w", path, err)
+				}
+
+				updatedComponent.Policies[policyIdx].Files[fileIdx] = rel
+			}
+		}
+
+		spinner.Success()
+	}
+
+	if len(component.PolicyGates) > 0 {
+		spinner := message.NewProgressSpinner("Loading %d policy gates", len(component.PolicyGates))
+		defer spinner.Stop()
+
+		for gateIdx, policyGate := range component.PolicyGates {
+			for fileIdx, path := range policyGate.Files {
+				if helpers.IsURL(path) {
+					continue
+				}
+
+				rel := filepath.Join(layout.PolicyGatesDir, gate.FileName(policyGate, fileIdx))
+				dst := filepath.Join(componentPaths.Base, rel)
+
+				spinner.Updatef("Copying policy gate %s", path)
+
+				if err := helpers.CreatePathAndCopy(path, dst); err != nil {
+					return nil, fmt.Errorf("unable to copy policy gate %s: %w", path, err)
+				}
+
+				updatedComponent.PolicyGates[gateIdx].Files[fileIdx] = rel
+			}
+		}
+
+		spinner.Success()
+	}
+
 	return updatedComponent, nil
 }
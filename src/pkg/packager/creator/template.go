@@ -25,13 +25,16 @@ func FillActiveTemplate(pkg v1alpha1.ZarfPackage, setVariables map[string]string
 			return err
 		}
 
-		for key := range yamlTemplates {
+		for key, defaultValue := range yamlTemplates {
 			if deprecated {
 				warnings = append(warnings, fmt.Sprintf(lang.PkgValidateTemplateDeprecation, key, key, key))
 			}
 
 			_, present := setVariables[key]
-			if !present && !config.CommonOptions.Confirm {
+			if !present && defaultValue != "" {
+				// A template with a default is optional, the default function fills it in when it is left empty.
+				setVariables[key] = ""
+			} else if !present && !config.CommonOptions.Confirm {
 				setVal, err := interactive.PromptVariable(v1alpha1.InteractiveVariable{
 					Variable: v1alpha1.Variable{Name: key},
 				})
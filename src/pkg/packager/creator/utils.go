@@ -8,10 +8,14 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/packager/sbom"
 	"github.com/zarf-dev/zarf/src/pkg/lint"
 	"github.com/zarf-dev/zarf/src/pkg/packager/deprecated"
 	"github.com/zarf-dev/zarf/src/types"
@@ -77,8 +81,67 @@ func recordPackageMetadata(pkg *v1alpha1.ZarfPackage, createOpts types.ZarfCreat
 
 	pkg.Build.RegistryOverrides = createOpts.RegistryOverrides
 
+	// Record the formats the SBOMs were created in so that consumers can discover them.
+	if !createOpts.SkipSBOM && !createOpts.IsSkeleton {
+		pkg.Build.SBOMFormats = helpers.Unique(append([]string{sbom.FormatSyftJSON}, createOpts.SBOMFormats...))
+	}
+
+	// Record the SBOM rules that skipped or redacted the SBOMs of any components.
+	if !createOpts.SkipSBOM {
+		for _, component := range pkg.Components {
+			if !component.SBOM.Skip && !component.SBOM.IsRedacted() {
+				continue
+			}
+			if pkg.Build.SBOMRedactions == nil {
+				pkg.Build.SBOMRedactions = map[string]v1alpha1.ZarfComponentSBOM{}
+			}
+			pkg.Build.SBOMRedactions[component.Name] = component.SBOM
+		}
+	}
+
 	// Record the latest version of Zarf without breaking changes to the package structure.
 	pkg.Build.LastNonBreakingVersion = deprecated.LastNonBreakingVersion
 
 	return nil
 }
+
+// mergeSBOMRules combines the SBOM rules of components that share an image so that the image SBOM is skipped or
+// redacted if any of the components ask for it.
+func mergeSBOMRules(a, b v1alpha1.ZarfComponentSBOM) v1alpha1.ZarfComponentSBOM {
+	return v1alpha1.ZarfComponentSBOM{
+		Skip:           a.Skip || b.Skip,
+		ExcludePaths:   helpers.Unique(append(slices.Clone(a.ExcludePaths), b.ExcludePaths...)),
+		StripLicenses:  a.StripLicenses || b.StripLicenses,
+		OmitFileHashes: a.OmitFileHashes || b.OmitFileHashes,
+	}
+}
+
+// resolveArchitectures returns the architecture and the architectures of a package being created.
+//
+// The given architectures take precedence over the --architecture flag, which takes precedence over
+// metadata.architectures, which takes precedence over metadata.architecture. Packages with more than one architecture
+// have the MultiArch architecture.
+func resolveArchitectures(metadata v1alpha1.ZarfMetadata, createArchs []string) (string, []string) {
+	archs := metadata.Architectures
+	switch {
+	case len(createArchs) > 0:
+		archs = createArchs
+	case config.CLIArch != "":
+		archs = []string{config.CLIArch}
+	}
+	resolved := []string{}
+	for _, arch := range archs {
+		if arch = strings.TrimSpace(arch); arch != "" {
+			resolved = append(resolved, arch)
+		}
+	}
+	resolved = helpers.Unique(resolved)
+	switch len(resolved) {
+	case 0:
+		return config.GetArch(metadata.Architecture), nil
+	case 1:
+		return resolved[0], nil
+	default:
+		return v1alpha1.MultiArch, resolved
+	}
+}
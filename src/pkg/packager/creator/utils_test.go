@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package creator contains functions for creating Zarf packages.
+package creator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+)
+
+func TestResolveArchitectures(t *testing.T) {
+	tests := []struct {
+		name                  string
+		cliArch               string
+		createArchs           []string
+		metadata              v1alpha1.ZarfMetadata
+		expectedArch          string
+		expectedArchitectures []string
+	}{
+		{
+			name:         "single architecture",
+			metadata:     v1alpha1.ZarfMetadata{Architecture: "arm64"},
+			expectedArch: "arm64",
+		},
+		{
+			name:                  "multiple architectures",
+			metadata:              v1alpha1.ZarfMetadata{Architecture: "arm64", Architectures: []string{"amd64", "arm64", "amd64"}},
+			expectedArch:          v1alpha1.MultiArch,
+			expectedArchitectures: []string{"amd64", "arm64"},
+		},
+		{
+			name:         "one of architectures",
+			metadata:     v1alpha1.ZarfMetadata{Architectures: []string{"arm64"}},
+			expectedArch: "arm64",
+		},
+		{
+			name:                  "create architectures override metadata",
+			createArchs:           []string{"arm64", " amd64"},
+			metadata:              v1alpha1.ZarfMetadata{Architecture: "amd64"},
+			expectedArch:          v1alpha1.MultiArch,
+			expectedArchitectures: []string{"arm64", "amd64"},
+		},
+		{
+			name:         "single architecture flag overrides multiple architectures",
+			cliArch:      "amd64",
+			metadata:     v1alpha1.ZarfMetadata{Architectures: []string{"amd64", "arm64"}},
+			expectedArch: "amd64",
+		},
+		{
+			name:                  "create architectures override flag",
+			cliArch:               "s390x",
+			createArchs:           []string{"amd64", "arm64"},
+			expectedArch:          v1alpha1.MultiArch,
+			expectedArchitectures: []string{"amd64", "arm64"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.CLIArch = tt.cliArch
+			t.Cleanup(func() { config.CLIArch = "" })
+
+			arch, archs := resolveArchitectures(tt.metadata, tt.createArchs)
+			require.Equal(t, tt.expectedArch, arch)
+			require.Equal(t, tt.expectedArchitectures, archs)
+		})
+	}
+}
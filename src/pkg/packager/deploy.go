@@ -6,12 +6,15 @@ package packager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -19,21 +22,27 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/avast/retry-go/v4"
-	pkgkubernetes "github.com/defenseunicorns/pkg/kubernetes"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 	"sigs.k8s.io/cli-utils/pkg/object"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/faults"
 	"github.com/zarf-dev/zarf/src/internal/git"
 	"github.com/zarf-dev/zarf/src/internal/gitea"
+	"github.com/zarf-dev/zarf/src/internal/packager/gate"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
 	"github.com/zarf-dev/zarf/src/internal/packager/images"
+	"github.com/zarf-dev/zarf/src/internal/packager/overlay"
+	"github.com/zarf-dev/zarf/src/internal/packager/policy"
 	"github.com/zarf-dev/zarf/src/internal/packager/template"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
@@ -41,9 +50,14 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
+// policyAuditTimeout is how long a deploy waits for the policy engines to audit the cluster against newly installed
+// policy bundles before reporting violations.
+const policyAuditTimeout = 2 * time.Minute
+
 var (
 	// localClusterServiceRegex is used to match the local cluster service format:
 	localClusterServiceRegex = regexp.MustCompile(`^(?P<name>[^\.]+)\.(?P<namespace>[^\.]+)\.svc\.cluster\.local$`)
@@ -59,11 +73,12 @@ func (p *Packager) resetRegistryHPA(ctx context.Context) {
 
 // Deploy attempts to deploy the given PackageConfig.
 func (p *Packager) Deploy(ctx context.Context) error {
-	isInteractive := !config.CommonOptions.Confirm
+	isInteractive := !p.commonOpts.Confirm
 
 	deployFilter := filters.Combine(
 		filters.ByLocalOS(runtime.GOOS),
 		filters.ForDeploy(p.cfg.PkgOpts.OptionalComponents, isInteractive),
+		filters.ByEntitlement(p.cfg.DeployOpts.Entitlements, p.cfg.PkgOpts.OptionalComponents),
 	)
 
 	warnings := []string{}
@@ -75,6 +90,9 @@ func (p *Packager) Deploy(ctx context.Context) error {
 		}
 		p.cfg.Pkg = pkg
 		warnings = append(warnings, loadWarnings...)
+		if err := p.applyVariableOverlays(ctx); err != nil {
+			return err
+		}
 	} else {
 		pkg, loadWarnings, err := p.source.LoadPackage(ctx, p.layout, deployFilter, true)
 		if err != nil {
@@ -82,6 +100,9 @@ func (p *Packager) Deploy(ctx context.Context) error {
 		}
 		p.cfg.Pkg = pkg
 		warnings = append(warnings, loadWarnings...)
+		if err := p.applyVariableOverlays(ctx); err != nil {
+			return err
+		}
 		if err := p.populatePackageVariableConfig(); err != nil {
 			return fmt.Errorf("unable to set the active variables: %w", err)
 		}
@@ -92,6 +113,19 @@ func (p *Packager) Deploy(ctx context.Context) error {
 		return err
 	}
 	warnings = append(warnings, validateWarnings...)
+	warnings = append(warnings, validateFeatures(config.CLIVersion, p.cfg.Pkg.Build.Features)...)
+	timeoutWarnings, err := p.validateComponentTimeouts()
+	if err != nil {
+		return err
+	}
+	warnings = append(warnings, timeoutWarnings...)
+	p.policyGates, err = gate.LoadPaths(ctx, p.cfg.DeployOpts.PolicyGates)
+	if err != nil {
+		return fmt.Errorf("unable to load the policy gates: %w", err)
+	}
+	if err := p.validateStateEncryptionKey(); err != nil {
+		return err
+	}
 
 	sbomViewFiles, sbomWarnings, err := p.layout.SBOMs.StageSBOMViewFiles()
 	if err != nil {
@@ -99,9 +133,28 @@ func (p *Packager) Deploy(ctx context.Context) error {
 	}
 	warnings = append(warnings, sbomWarnings...)
 
-	// Confirm the overall package deployment
-	if !p.confirmAction(config.ZarfDeployStage, warnings, sbomViewFiles) {
-		return fmt.Errorf("deployment cancelled")
+	// Without --confirm the plan lists the components a confirmed deployment would select.
+	planned := p.cfg.Pkg.Components
+	if isInteractive {
+		planned, err = filters.Combine(
+			filters.ByLocalOS(runtime.GOOS),
+			filters.ForDeploy(p.cfg.PkgOpts.OptionalComponents, false),
+			filters.ByEntitlement(p.cfg.DeployOpts.Entitlements, p.cfg.PkgOpts.OptionalComponents),
+		).Apply(p.cfg.Pkg)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Confirm the overall package deployment, a dry run does not change the cluster so needs no confirmation
+	if !p.cfg.DeployOpts.DryRun {
+		if err := p.confirmAction(config.ZarfDeployStage, planned, warnings, sbomViewFiles); err != nil {
+			return fmt.Errorf("deployment cancelled: %w", err)
+		}
+	} else {
+		for _, warning := range warnings {
+			message.Warn(warning)
+		}
 	}
 
 	if isInteractive {
@@ -116,15 +169,60 @@ func (p *Packager) Deploy(ctx context.Context) error {
 		}
 	}
 
+	if p.cfg.Pkg.IsMultiArch() {
+		if err := p.filterComponentsByArchitecture(ctx); err != nil {
+			return err
+		}
+	}
+
+	if p.cfg.DeployOpts.DryRun {
+		return p.deployDryRun(ctx)
+	}
+
 	p.hpaModified = false
+	p.rollback = nil
 	// Reset registry HPA scale down whether an error occurs or not
 	defer p.resetRegistryHPA(ctx)
 
+	if p.cfg.DeployOpts.PublishStatus {
+		p.deployStatus = &types.DeployStatus{
+			Package:         p.cfg.Pkg.Metadata.Name,
+			CorrelationID:   message.CorrelationID(),
+			Phase:           types.ComponentStatusDeploying,
+			ComponentsTotal: len(p.cfg.Pkg.Components),
+			Started:         time.Now(),
+		}
+	}
+
+	// Commands run by the actions connect to the cluster being deployed to through KUBECONFIG
+	if p.cfg.DeployOpts.KubeContext != "" || config.CommonOptions.KubeConfig != "" {
+		p.kubeconfig = filepath.Join(p.layout.Base, "kubeconfig")
+		if err := cluster.WriteKubeconfig(p.kubeconfig, p.cfg.DeployOpts.KubeContext); err != nil {
+			return fmt.Errorf("unable to write the kubeconfig of context %s: %w", p.cfg.DeployOpts.KubeContext, err)
+		}
+	}
+
 	// Get a list of all the components we are deploying and actually deploy them
-	deployedComponents, err := p.deployComponents(ctx)
+	var deployedComponents []types.DeployedComponent
+	err = actions.RunSet(ctx, p.cfg.Pkg.Actions.OnDeploy, p.variableConfig, func() error {
+		deployedComponents, err = p.deployComponents(ctx)
+		return err
+	}, actions.WithKubeconfig(p.kubeconfig))
 	if err != nil {
+		if rollbackErr := p.rollbackDeploy(ctx); rollbackErr != nil {
+			err = errors.Join(err, fmt.Errorf("unable to roll back the deployment: %w", rollbackErr))
+		}
+		p.publishStatus(ctx, func(status *types.DeployStatus) {
+			status.Phase = types.ComponentStatusFailed
+			status.Error = err.Error()
+		})
 		return err
 	}
+	p.publishStatus(ctx, func(status *types.DeployStatus) {
+		status.Phase = types.ComponentStatusSucceeded
+		status.Component = ""
+		status.Step = ""
+	})
 	if len(deployedComponents) == 0 {
 		message.Warn("No components were selected for deployment.  Inspect the package to view the available components and select components interactively or by name with \"--components\"")
 	}
@@ -142,6 +240,11 @@ func (p *Packager) Deploy(ctx context.Context) error {
 
 // deployComponents loops through a list of ZarfComponents and deploys them.
 func (p *Packager) deployComponents(ctx context.Context) ([]types.DeployedComponent, error) {
+	// Init packages bootstrap the registry and git server that the components after them use, so are always deployed in order.
+	if p.cfg.DeployOpts.Concurrency > 1 && !p.cfg.Pkg.IsInitConfig() {
+		return p.deployComponentsConcurrently(ctx)
+	}
+
 	deployedComponents := []types.DeployedComponent{}
 
 	// Process all the components we are deploying
@@ -158,6 +261,9 @@ func (p *Packager) deployComponents(ctx context.Context) ([]types.DeployedCompon
 			if err := p.connectToCluster(connectCtx); err != nil {
 				return nil, fmt.Errorf("unable to connect to the Kubernetes cluster: %w", err)
 			}
+			if err := p.runPreflightChecks(ctx); err != nil {
+				return nil, err
+			}
 
 			// If this package has been deployed before, increment the package generation within the secret
 			if existingDeployedPackage, _ := p.cluster.GetDeployedPackage(ctx, p.cfg.Pkg.Metadata.Name); existingDeployedPackage != nil {
@@ -165,6 +271,10 @@ func (p *Packager) deployComponents(ctx context.Context) ([]types.DeployedCompon
 			}
 		}
 
+		if err := p.recordRollbackPoint(ctx, component); err != nil {
+			return nil, err
+		}
+
 		deployedComponent := types.DeployedComponent{
 			Name:               component.Name,
 			Status:             types.ComponentStatusDeploying,
@@ -183,9 +293,14 @@ func (p *Packager) deployComponents(ctx context.Context) ([]types.DeployedCompon
 		deployedComponents = append(deployedComponents, deployedComponent)
 		idx := len(deployedComponents) - 1
 
+		p.publishStatus(ctx, func(status *types.DeployStatus) {
+			status.Component = component.Name
+			status.Step = "Starting"
+		})
+
 		// Update the package secret to indicate that we are attempting to deploy this component
 		if p.isConnectedToCluster() {
-			if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, packageGeneration, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
+			if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, packageGeneration, p.variableOverlays, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
 				message.Debugf("Unable to record package deployment for component %s: this will affect features like `zarf package remove`: %s", component.Name, err.Error())
 			}
 		}
@@ -193,6 +308,7 @@ func (p *Packager) deployComponents(ctx context.Context) ([]types.DeployedCompon
 		// Deploy the component
 		var charts []types.InstalledChart
 		var deployErr error
+		p.pushedImages, p.pushedRepos = nil, nil
 		if p.cfg.Pkg.IsInitConfig() {
 			charts, deployErr = p.deployInitComponent(ctx, component)
 		} else {
@@ -202,7 +318,7 @@ func (p *Packager) deployComponents(ctx context.Context) ([]types.DeployedCompon
 		onDeploy := component.Actions.OnDeploy
 
 		onFailure := func() {
-			if err := actions.Run(ctx, onDeploy.Defaults, onDeploy.OnFailure, p.variableConfig); err != nil {
+			if err := actions.Run(ctx, onDeploy.Defaults, onDeploy.OnFailure, p.variableConfig, actions.WithCluster(p.cluster), actions.WithKubeconfig(p.kubeconfig)); err != nil {
 				message.Debugf("unable to run component failure action: %s", err.Error())
 			}
 		}
@@ -213,23 +329,29 @@ func (p *Packager) deployComponents(ctx context.Context) ([]types.DeployedCompon
 			// Update the package secret to indicate that we failed to deploy this component
 			deployedComponents[idx].Status = types.ComponentStatusFailed
 			if p.isConnectedToCluster() {
-				if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, packageGeneration, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
+				if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, packageGeneration, p.variableOverlays, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
 					message.Debugf("Unable to record package deployment for component %q: this will affect features like `zarf package remove`: %s", component.Name, err.Error())
 				}
 			}
 			return nil, fmt.Errorf("unable to deploy component %q: %w", component.Name, deployErr)
 		}
 
+		p.publishStatus(ctx, func(status *types.DeployStatus) {
+			status.ComponentsCompleted++
+		})
+
 		// Update the package secret to indicate that we successfully deployed this component
 		deployedComponents[idx].InstalledCharts = charts
+		deployedComponents[idx].PushedImages = p.pushedImages
+		deployedComponents[idx].PushedRepos = p.pushedRepos
 		deployedComponents[idx].Status = types.ComponentStatusSucceeded
 		if p.isConnectedToCluster() {
-			if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, packageGeneration, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
+			if _, err := p.cluster.RecordPackageDeploymentAndWait(ctx, p.cfg.Pkg, deployedComponents, packageGeneration, p.variableOverlays, component, p.cfg.DeployOpts.SkipWebhooks); err != nil {
 				message.Debugf("Unable to record package deployment for component %q: this will affect features like `zarf package remove`: %s", component.Name, err.Error())
 			}
 		}
 
-		if err := actions.Run(ctx, onDeploy.Defaults, onDeploy.OnSuccess, p.variableConfig); err != nil {
+		if err := actions.Run(ctx, onDeploy.Defaults, onDeploy.OnSuccess, p.variableConfig, actions.WithCluster(p.cluster), actions.WithKubeconfig(p.kubeconfig)); err != nil {
 			onFailure()
 			return nil, fmt.Errorf("unable to run component success action: %w", err)
 		}
@@ -255,15 +377,24 @@ func runHealthChecks(ctx context.Context, watcher watcher.StatusWatcher, healthC
 		}
 		objs = append(objs, obj)
 	}
-	err := pkgkubernetes.WaitForReady(ctx, watcher, objs)
-	if err != nil {
-		return err
+	return cluster.WaitForObjectsReady(ctx, watcher, objs)
+}
+
+// validateStateEncryptionKey ensures that the Zarf agent can decrypt the state when an init package deploys it, as
+// every admission request it fails to load the state for is rejected.
+func (p *Packager) validateStateEncryptionKey() error {
+	if !p.cfg.Pkg.IsInitConfig() || p.cfg.InitOpts.StateEncryptionKey == "" {
+		return nil
+	}
+	for _, component := range p.cfg.Pkg.Components {
+		if component.Name == "zarf-agent" {
+			return cluster.ValidateAgentStateEncryptionKey(p.cfg.InitOpts.StateEncryptionKey)
+		}
 	}
 	return nil
 }
 
 func (p *Packager) deployInitComponent(ctx context.Context, component v1alpha1.ZarfComponent) ([]types.InstalledChart, error) {
-	hasExternalRegistry := p.cfg.InitOpts.RegistryInfo.Address != ""
 	isSeedRegistry := component.Name == "zarf-seed-registry"
 	isRegistry := component.Name == "zarf-registry"
 	isInjector := component.Name == "zarf-injector"
@@ -282,8 +413,8 @@ func (p *Packager) deployInitComponent(ctx context.Context, component v1alpha1.Z
 		}
 	}
 
-	if hasExternalRegistry && (isSeedRegistry || isInjector || isRegistry) {
-		message.Notef("Not deploying the component (%s) since external registry information was provided during `zarf init`", component.Name)
+	if (isSeedRegistry || isInjector || isRegistry) && p.hasExternalRegistry(ctx) {
+		message.Notef("Not deploying the component (%s) since the cluster uses an external registry", component.Name)
 		return nil, nil
 	}
 
@@ -294,7 +425,11 @@ func (p *Packager) deployInitComponent(ctx context.Context, component v1alpha1.Z
 
 	// Before deploying the seed registry, start the injector
 	if isSeedRegistry {
-		err := p.cluster.StartInjection(ctx, p.layout.Base, p.layout.Images.Base, component.Images)
+		images, err := seedImages(p.cfg.Pkg, component.Images, p.registryType(ctx))
+		if err != nil {
+			return nil, err
+		}
+		err = p.cluster.StartInjection(ctx, p.layout.Base, p.layout.Images.Base, images)
 		if err != nil {
 			return nil, err
 		}
@@ -317,6 +452,22 @@ func (p *Packager) deployInitComponent(ctx context.Context, component v1alpha1.Z
 	return charts, nil
 }
 
+// hasExternalRegistry returns true if the cluster was initialized, or is being initialized, with an external registry.
+//
+// The registry in the Zarf state takes precedence over the init flags, as changes to the registry are ignored on a
+// re-init and the internal registry is not needed by a cluster that already uses an external one.
+func (p *Packager) hasExternalRegistry(ctx context.Context) bool {
+	if p.state != nil {
+		return !p.state.RegistryInfo.IsInternal()
+	}
+	if p.isConnectedToCluster() {
+		if state, err := p.cluster.LoadZarfState(ctx); err == nil {
+			return !state.RegistryInfo.IsInternal()
+		}
+	}
+	return p.cfg.InitOpts.RegistryInfo.Address != ""
+}
+
 // Deploy a Zarf Component.
 func (p *Packager) deployComponent(ctx context.Context, component v1alpha1.ZarfComponent, noImgChecksum bool, noImgPush bool) ([]types.InstalledChart, error) {
 	// Toggles for general deploy operations
@@ -325,9 +476,10 @@ func (p *Packager) deployComponent(ctx context.Context, component v1alpha1.ZarfC
 	// All components now require a name
 	message.HeaderInfof("📦 %s COMPONENT", strings.ToUpper(component.Name))
 
-	hasImages := len(component.Images) > 0 && !noImgPush
+	hasImages := len(component.ImagesAndArtifacts()) > 0 && !noImgPush
 	hasCharts := len(component.Charts) > 0
 	hasManifests := len(component.Manifests) > 0
+	hasPolicies := len(component.Policies) > 0
 	hasRepos := len(component.Repos) > 0
 	hasFiles := len(component.Files) > 0
 
@@ -357,23 +509,34 @@ func (p *Packager) deployComponent(ctx context.Context, component v1alpha1.ZarfC
 		return nil, err
 	}
 
-	if err = actions.Run(ctx, onDeploy.Defaults, onDeploy.Before, p.variableConfig); err != nil {
+	if err = actions.Run(ctx, onDeploy.Defaults, onDeploy.Before, p.variableConfig, actions.WithCluster(p.cluster), actions.WithKubeconfig(p.kubeconfig)); err != nil {
 		return nil, fmt.Errorf("unable to run component before action: %w", err)
 	}
 
+	// Policy gates are checked after the before actions, which can set the variables the manifests are rendered with.
+	if p.hasPolicyGates(component) {
+		p.publishStep(ctx, "Checking policy gates")
+		if err := p.enforcePolicyGates(ctx, componentPath, component); err != nil {
+			return nil, err
+		}
+	}
+
 	if hasFiles {
+		p.publishStep(ctx, "Copying files")
 		if err := p.processComponentFiles(component, componentPath.Files); err != nil {
 			return nil, fmt.Errorf("unable to process the component files: %w", err)
 		}
 	}
 
 	if hasImages {
-		if err := p.pushImagesToRegistry(ctx, component.Images, noImgChecksum); err != nil {
+		p.publishStep(ctx, "Pushing images")
+		if err := p.pushImagesToRegistry(ctx, component.ImagesAndArtifacts(), noImgChecksum); err != nil {
 			return nil, fmt.Errorf("unable to push images to the registry: %w", err)
 		}
 	}
 
 	if hasRepos {
+		p.publishStep(ctx, "Pushing repositories")
 		if err = p.pushReposToRepository(ctx, componentPath.Repos, component.Repos); err != nil {
 			return nil, fmt.Errorf("unable to push the repos to the repository: %w", err)
 		}
@@ -387,19 +550,37 @@ func (p *Packager) deployComponent(ctx context.Context, component v1alpha1.ZarfC
 	}
 
 	charts := []types.InstalledChart{}
+	policiesInstalledAt := time.Now()
+	// Policies are installed first so that they apply to everything else the component deploys.
+	if hasPolicies {
+		p.publishStep(ctx, "Installing policies")
+		policyCharts, err := p.installPolicies(ctx, componentPath, component)
+		if err != nil {
+			return nil, err
+		}
+		charts = append(charts, policyCharts...)
+	}
+
 	if hasCharts || hasManifests {
-		charts, err = p.installChartAndManifests(ctx, componentPath, component)
+		p.publishStep(ctx, "Installing charts and manifests")
+		installedCharts, err := p.installChartAndManifests(ctx, componentPath, component)
 		if err != nil {
 			return nil, err
 		}
+		charts = append(charts, installedCharts...)
 	}
 
-	if err = actions.Run(ctx, onDeploy.Defaults, onDeploy.After, p.variableConfig); err != nil {
+	if err = actions.Run(ctx, onDeploy.Defaults, onDeploy.After, p.variableConfig, actions.WithCluster(p.cluster), actions.WithKubeconfig(p.kubeconfig)); err != nil {
 		return nil, fmt.Errorf("unable to run component after action: %w", err)
 	}
 
 	if len(component.HealthChecks) > 0 {
-		healthCheckContext, cancel := context.WithTimeout(ctx, p.cfg.DeployOpts.Timeout)
+		p.publishStep(ctx, "Running health checks")
+		timeout, err := p.timeout(component, "")
+		if err != nil {
+			return nil, err
+		}
+		healthCheckContext, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 		spinner := message.NewProgressSpinner("Running health checks")
 		defer spinner.Stop()
@@ -409,13 +590,48 @@ func (p *Packager) deployComponent(ctx context.Context, component v1alpha1.ZarfC
 		spinner.Success()
 	}
 
+	if len(component.Readiness) > 0 {
+		p.publishStep(ctx, "Running readiness probes")
+		if err = p.runReadinessProbes(ctx, component); err != nil {
+			return nil, err
+		}
+	}
+
 	err = g.Wait()
 	if err != nil {
 		return nil, err
 	}
+
+	if hasPolicies {
+		p.reportPolicyViolations(ctx, componentPath, component, policiesInstalledAt)
+	}
+
 	return charts, nil
 }
 
+// publishStatus applies an update to the deploy status and publishes it to the cluster when the deploy was asked to
+// publish its status. Failing to publish does not fail the deploy.
+func (p *Packager) publishStatus(ctx context.Context, update func(status *types.DeployStatus)) {
+	if p.deployStatus == nil {
+		return
+	}
+	update(p.deployStatus)
+	p.deployStatus.Updated = time.Now()
+	if !p.isConnectedToCluster() {
+		return
+	}
+	if err := p.cluster.PublishDeployStatus(ctx, *p.deployStatus); err != nil {
+		message.Debugf("Unable to publish the deploy status of package %s: %s", p.deployStatus.Package, err.Error())
+	}
+}
+
+// publishStep publishes the step of the current component that the deploy is on.
+func (p *Packager) publishStep(ctx context.Context, step string) {
+	p.publishStatus(ctx, func(status *types.DeployStatus) {
+		status.Step = step
+	})
+}
+
 // Move files onto the host of the machine performing the deployment.
 func (p *Packager) processComponentFiles(component v1alpha1.ZarfComponent, pkgLocation string) error {
 	spinner := message.NewProgressSpinner("Copying %d files", len(component.Files))
@@ -560,7 +776,48 @@ func (p *Packager) populateComponentAndStateTemplates(componentName string) erro
 
 func (p *Packager) populatePackageVariableConfig() error {
 	p.variableConfig.SetConstants(p.cfg.Pkg.Constants)
-	return p.variableConfig.PopulateVariables(p.cfg.Pkg.Variables, p.cfg.PkgOpts.SetVariables)
+	setVariables := p.cfg.PkgOpts.SetVariables
+	if p.cfg.Pkg.IsInitConfig() {
+		overrides, err := initOverrideVariables(p.cfg.InitOpts)
+		if err != nil {
+			return err
+		}
+		// Variables given with --set take precedence over the init component overrides
+		maps.Copy(overrides, setVariables)
+		setVariables = overrides
+	}
+	return p.variableConfig.PopulateVariables(p.cfg.Pkg.Variables, setVariables)
+}
+
+// applyVariableOverlays merges the variables of the overlays that select the cluster being deployed to underneath the
+// variables that were set directly, so that values given with --set always take precedence.
+func (p *Packager) applyVariableOverlays(ctx context.Context) error {
+	if p.cfg.DeployOpts.VariableOverlays == "" {
+		return nil
+	}
+	overlays, err := overlay.Load(p.cfg.DeployOpts.VariableOverlays)
+	if err != nil {
+		return err
+	}
+	if err := p.connectToCluster(ctx); err != nil {
+		return fmt.Errorf("unable to connect to the Kubernetes cluster to select variable overlays: %w", err)
+	}
+	name, labels, err := p.cluster.GetIdentity(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to identify the cluster to select variable overlays: %w", err)
+	}
+
+	selected := overlay.Select(overlays, name, labels)
+	if len(selected) == 0 {
+		message.Warnf("No variable overlays in %s select the cluster %q", p.cfg.DeployOpts.VariableOverlays, name)
+	}
+	p.variableOverlays = []string{}
+	for _, o := range selected {
+		message.Debugf("Applying the variable overlay %s to the cluster %q", o.Name(), name)
+		p.variableOverlays = append(p.variableOverlays, o.Name())
+	}
+	p.cfg.PkgOpts.SetVariables = overlay.Merge(selected, p.cfg.PkgOpts.SetVariables)
+	return nil
 }
 
 // Push all of the components images to the configured container registry.
@@ -583,9 +840,39 @@ func (p *Packager) pushImagesToRegistry(ctx context.Context, componentImages []s
 		NoChecksum:      noImgChecksum,
 		Arch:            p.cfg.Pkg.Build.Architecture,
 		Retries:         p.cfg.PkgOpts.Retries,
+		Cluster:         p.cluster,
+	}
+
+	if err := images.Push(ctx, pushCfg); err != nil {
+		return err
+	}
+	pushedImages, err := registryReferences(imageList, noImgChecksum)
+	if err != nil {
+		return err
 	}
+	p.pushedImages = append(p.pushedImages, pushedImages...)
+	return nil
+}
 
-	return images.Push(ctx, pushCfg)
+// registryReferences returns the references, relative to the registry, that the images are pushed to.
+func registryReferences(imageList []transform.Image, noChecksum bool) ([]string, error) {
+	refs := []string{}
+	for _, refInfo := range imageList {
+		// Cosign artifacts are only pushed without a checksum, matching images.Push
+		if !noChecksum && !utils.IsCosignArtifact(refInfo.Reference) {
+			ref, err := transform.ImageTransformHost("", refInfo.Reference)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, strings.TrimPrefix(ref, "/"))
+		}
+		ref, err := transform.ImageTransformHostWithoutChecksum("", refInfo.Reference)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, strings.TrimPrefix(ref, "/"))
+	}
+	return helpers.Unique(refs), nil
 }
 
 // Push all of the components git repos to the configured git server.
@@ -596,6 +883,10 @@ func (p *Packager) pushReposToRepository(ctx context.Context, reposPath string,
 			return err
 		}
 		err = retry.Do(func() error {
+			if err := faults.Inject(faults.GitPush); err != nil {
+				return err
+			}
+
 			namespace, name, port, err := serviceInfoFromServiceURL(p.state.GitServer.Address)
 
 			// If this is a service (svcInfo is not nil), create a port-forward tunnel to that resource
@@ -649,6 +940,11 @@ func (p *Packager) pushReposToRepository(ctx context.Context, reposPath string,
 		if err != nil {
 			return fmt.Errorf("unable to push repo %s to the Git Server: %w", repoURL, err)
 		}
+		repoName, err := transform.GitURLtoRepoName(repoURL)
+		if err != nil {
+			return err
+		}
+		p.pushedRepos = append(p.pushedRepos, repoName)
 	}
 	return nil
 }
@@ -680,9 +976,70 @@ func (p *Packager) generateValuesOverrides(chart v1alpha1.ZarfChart, componentNa
 	return helpers.MergeMapRecursive(chartOverrides, valuesOverrides), nil
 }
 
+// componentChart is a chart of a component along with the namespace it is installed in.
+type componentChart struct {
+	namespace string
+	helm      *helm.Helm
+}
+
 // Install all Helm charts and raw k8s manifests into the k8s cluster.
+// validateComponentTimeouts validates the timeouts given for components on deploy, returning warnings for the ones that
+// name components that are not in the package.
+func (p *Packager) validateComponentTimeouts() ([]string, error) {
+	warnings := []string{}
+	for name, timeout := range p.cfg.DeployOpts.ComponentTimeouts {
+		if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+			return nil, fmt.Errorf("timeout %q given for component %s must be a positive duration", timeout, name)
+		}
+		if !slices.ContainsFunc(p.cfg.Pkg.Components, func(c v1alpha1.ZarfComponent) bool { return c.Name == name }) {
+			warnings = append(warnings, fmt.Sprintf("A timeout was given for component %s which is not in the package or was not selected", name))
+		}
+	}
+	slices.Sort(warnings)
+	return warnings, nil
+}
+
+// timeout returns how long to wait for a chart or manifest of a component, or for the health checks and readiness
+// probes of the component when chartTimeout is empty. A timeout given for the component on deploy comes first, then
+// the timeout of the chart or manifest and the timeout of the component in the package, and otherwise the deploy timeout.
+func (p *Packager) timeout(component v1alpha1.ZarfComponent, chartTimeout string) (time.Duration, error) {
+	for _, timeout := range []string{p.cfg.DeployOpts.ComponentTimeouts[component.Name], chartTimeout, component.Timeout} {
+		if timeout == "" {
+			continue
+		}
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("timeout %q of component %s must be a positive duration", timeout, component.Name)
+		}
+		return d, nil
+	}
+	return p.cfg.DeployOpts.Timeout, nil
+}
+
 func (p *Packager) installChartAndManifests(ctx context.Context, componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent) ([]types.InstalledChart, error) {
+	charts, err := p.chartsAndManifests(componentPaths, component, p.cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	installedCharts := []types.InstalledChart{}
+	for _, chart := range charts {
+		connectStrings, installedChartName, err := chart.helm.InstallOrUpgradeChart(ctx)
+		if err != nil {
+			return nil, err
+		}
+		installedCharts = append(installedCharts, types.InstalledChart{Namespace: chart.namespace, ChartName: installedChartName, ConnectStrings: connectStrings})
+	}
+
+	return installedCharts, nil
+}
+
+// chartsAndManifests returns the Helm charts and the charts generated for the raw k8s manifests of a component, in the
+// order they are installed.
+//
+// Charts without a cluster are only rendered, and do not look up or update the namespaces they are installed in.
+func (p *Packager) chartsAndManifests(componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent, c *cluster.Cluster) ([]componentChart, error) {
+	charts := []componentChart{}
 
 	for _, chart := range component.Charts {
 		// Do not wait for the chart to be ready if data injections are present.
@@ -704,6 +1061,10 @@ func (p *Packager) installChartAndManifests(ctx context.Context, componentPaths
 		if err != nil {
 			return nil, err
 		}
+		timeout, err := p.timeout(component, chart.Timeout)
+		if err != nil {
+			return nil, err
+		}
 
 		helmCfg := helm.New(
 			chart,
@@ -713,17 +1074,12 @@ func (p *Packager) installChartAndManifests(ctx context.Context, componentPaths
 				p.cfg,
 				p.variableConfig,
 				p.state,
-				p.cluster,
+				c,
 				valuesOverrides,
-				p.cfg.DeployOpts.Timeout,
+				timeout,
 				p.cfg.PkgOpts.Retries),
 		)
-
-		connectStrings, installedChartName, err := helmCfg.InstallOrUpgradeChart(ctx)
-		if err != nil {
-			return nil, err
-		}
-		installedCharts = append(installedCharts, types.InstalledChart{Namespace: chart.Namespace, ChartName: installedChartName, ConnectStrings: connectStrings})
+		charts = append(charts, componentChart{namespace: chart.Namespace, helm: helmCfg})
 	}
 
 	for _, manifest := range component.Manifests {
@@ -747,6 +1103,11 @@ func (p *Packager) installChartAndManifests(ctx context.Context, componentPaths
 			manifest.Namespace = corev1.NamespaceDefault
 		}
 
+		timeout, err := p.timeout(component, manifest.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
 		// Create a chart and helm cfg from a given Zarf Manifest.
 		helmCfg, err := helm.NewFromZarfManifest(
 			manifest,
@@ -757,26 +1118,166 @@ func (p *Packager) installChartAndManifests(ctx context.Context, componentPaths
 				p.cfg,
 				p.variableConfig,
 				p.state,
-				p.cluster,
+				c,
 				nil,
-				p.cfg.DeployOpts.Timeout,
+				timeout,
 				p.cfg.PkgOpts.Retries),
 		)
 		if err != nil {
 			return nil, err
 		}
+		charts = append(charts, componentChart{namespace: manifest.Namespace, helm: helmCfg})
+	}
+
+	return charts, nil
+}
 
-		// Install the chart.
-		connectStrings, installedChartName, err := helmCfg.InstallOrUpgradeChart(ctx)
+// Install the policy bundles of a component into the k8s cluster, each as its own generated chart.
+func (p *Packager) installPolicies(ctx context.Context, componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent) ([]types.InstalledChart, error) {
+	charts, err := p.policyCharts(componentPaths, component, p.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	installedCharts := []types.InstalledChart{}
+	for idx, chart := range charts {
+		connectStrings, installedChartName, err := chart.helm.InstallOrUpgradeChart(ctx)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("unable to install policy bundle %s: %w", component.Policies[idx].Name, err)
 		}
-		installedCharts = append(installedCharts, types.InstalledChart{Namespace: manifest.Namespace, ChartName: installedChartName, ConnectStrings: connectStrings})
+		installedCharts = append(installedCharts, types.InstalledChart{Namespace: chart.namespace, ChartName: installedChartName, ConnectStrings: connectStrings})
 	}
 
 	return installedCharts, nil
 }
 
+// policyCharts returns the charts generated for the policy bundles of a component, in the order of the bundles.
+func (p *Packager) policyCharts(componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent, c *cluster.Cluster) ([]componentChart, error) {
+	timeout, err := p.timeout(component, "")
+	if err != nil {
+		return nil, err
+	}
+
+	charts := []componentChart{}
+
+	for _, zarfPolicy := range component.Policies {
+		manifest := v1alpha1.ZarfManifest{
+			Name:      fmt.Sprintf("policy-%s", zarfPolicy.Name),
+			Namespace: zarfPolicy.Namespace,
+		}
+		for idx := range zarfPolicy.Files {
+			file := fmt.Sprintf("%s-%d.yaml", zarfPolicy.Name, idx)
+			if helpers.InvalidPath(filepath.Join(componentPaths.Policies, file)) {
+				return nil, fmt.Errorf("unable to find policy file %s", file)
+			}
+			manifest.Files = append(manifest.Files, file)
+		}
+
+		if manifest.Namespace == "" {
+			// Helm gets sad when you don't provide a namespace even though we aren't using helm templating
+			manifest.Namespace = corev1.NamespaceDefault
+		}
+
+		helmCfg, err := helm.NewFromZarfManifest(
+			manifest,
+			componentPaths.Policies,
+			p.cfg.Pkg.Metadata.Name,
+			component.Name,
+			helm.WithDeployInfo(
+				p.cfg,
+				p.variableConfig,
+				p.state,
+				c,
+				nil,
+				timeout,
+				p.cfg.PkgOpts.Retries),
+		)
+		if err != nil {
+			return nil, err
+		}
+		charts = append(charts, componentChart{namespace: manifest.Namespace, helm: helmCfg})
+	}
+
+	return charts, nil
+}
+
+// Print the violations the cluster reports for the policy bundles of a component.
+//
+// Policy engines audit asynchronously, so the engines are given up to policyAuditTimeout to audit the cluster since the
+// bundles were installed. Violations of bundles that were not audited in time are reported by a later deploy, and failing
+// to collect violations is only a warning.
+func (p *Packager) reportPolicyViolations(ctx context.Context, componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent, installedAt time.Time) {
+	dc, err := dynamic.NewForConfig(p.cluster.RestConfig)
+	if err != nil {
+		message.Warnf("Unable to check for policy violations: %s", err.Error())
+		return
+	}
+
+	bundles := map[string][]*unstructured.Unstructured{}
+	for _, zarfPolicy := range component.Policies {
+		paths := []string{}
+		for idx := range zarfPolicy.Files {
+			paths = append(paths, filepath.Join(componentPaths.Policies, fmt.Sprintf("%s-%d.yaml", zarfPolicy.Name, idx)))
+		}
+		objs, err := policy.ReadFiles(paths...)
+		if err != nil {
+			message.Warnf("Unable to read policy bundle %s: %s", zarfPolicy.Name, err.Error())
+			continue
+		}
+		bundles[zarfPolicy.Name] = objs
+	}
+
+	spinner := message.NewProgressSpinner("Waiting for the policy engines to audit the cluster")
+	defer spinner.Stop()
+	auditCtx, cancel := context.WithTimeout(ctx, policyAuditTimeout)
+	defer cancel()
+	pending := []string{}
+	for _, zarfPolicy := range component.Policies {
+		objs, ok := bundles[zarfPolicy.Name]
+		if !ok {
+			continue
+		}
+		err := wait.PollUntilContextCancel(auditCtx, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			return policy.Audited(ctx, dc, zarfPolicy.Engine, objs, installedAt)
+		})
+		if err != nil {
+			message.Debugf("Policy bundle %s was not audited: %s", zarfPolicy.Name, err.Error())
+			pending = append(pending, zarfPolicy.Name)
+		}
+	}
+	spinner.Success()
+	if len(pending) > 0 {
+		message.Warnf("The policy engines have not audited the cluster against %s yet, their violations will be reported by a later deploy", strings.Join(pending, ", "))
+	}
+
+	findings := []policy.Finding{}
+	for _, zarfPolicy := range component.Policies {
+		objs, ok := bundles[zarfPolicy.Name]
+		if !ok {
+			continue
+		}
+		bundleFindings, err := policy.Violations(ctx, dc, zarfPolicy.Engine, objs)
+		if err != nil {
+			message.Warnf("Unable to check policy bundle %s for violations: %s", zarfPolicy.Name, err.Error())
+			continue
+		}
+		findings = append(findings, bundleFindings...)
+	}
+
+	if len(findings) == 0 {
+		message.Debugf("No policy violations reported for component %s", component.Name)
+		return
+	}
+
+	message.Warnf("%d policy violations reported for component %s", len(findings), component.Name)
+	header := []string{"Engine", "Policy", "Rule", "Resource", "Message"}
+	data := [][]string{}
+	for _, f := range findings {
+		data = append(data, []string{string(f.Engine), f.Policy, f.Rule, f.Resource(), f.Message})
+	}
+	message.Table(header, data)
+}
+
 func (p *Packager) printTablesForDeployment(ctx context.Context, componentsToDeploy []types.DeployedComponent) error {
 	// If not init config, print the application connection table
 	if !p.cfg.Pkg.IsInitConfig() {
@@ -788,6 +1289,14 @@ func (p *Packager) printTablesForDeployment(ctx context.Context, componentsToDep
 				}
 			}
 		}
+		for _, comp := range p.cfg.Pkg.Components {
+			if !slices.ContainsFunc(componentsToDeploy, func(dc types.DeployedComponent) bool { return dc.Name == comp.Name }) {
+				continue
+			}
+			for _, target := range comp.Connect {
+				connectStrings[target.Name] = types.ConnectString{Description: target.Description, URL: target.URL}
+			}
+		}
 		message.PrintConnectStringTable(connectStrings)
 		return nil
 	}
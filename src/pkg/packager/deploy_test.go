@@ -5,18 +5,25 @@ package packager
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/template"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/types"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/watcher"
 	"sigs.k8s.io/cli-utils/pkg/testutil"
@@ -361,3 +368,237 @@ func TestRunHealthChecks(t *testing.T) {
 		})
 	}
 }
+
+func TestHasExternalRegistry(t *testing.T) {
+	t.Parallel()
+
+	internal := types.RegistryInfo{Address: "127.0.0.1:31999", NodePort: 31999}
+	external := types.RegistryInfo{Address: "registry.example.com"}
+	tests := []struct {
+		name      string
+		initOpts  types.RegistryInfo
+		state     *types.ZarfState
+		clusterFn func(t *testing.T) *cluster.Cluster
+		expected  bool
+	}{
+		{
+			name:     "internal registry",
+			expected: false,
+		},
+		{
+			name:     "external registry from init flags",
+			initOpts: external,
+			expected: true,
+		},
+		{
+			name:     "loaded state takes precedence over init flags",
+			initOpts: internal,
+			state:    &types.ZarfState{RegistryInfo: external},
+			expected: true,
+		},
+		{
+			name:     "external registry flags on a re-init with the internal registry",
+			initOpts: external,
+			clusterFn: func(t *testing.T) *cluster.Cluster {
+				t.Helper()
+				c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+				require.NoError(t, c.SaveZarfState(context.Background(), &types.ZarfState{RegistryInfo: internal}))
+				return c
+			},
+			expected: false,
+		},
+		{
+			name: "re-init of a cluster with an external registry",
+			clusterFn: func(t *testing.T) *cluster.Cluster {
+				t.Helper()
+				c := &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+				require.NoError(t, c.SaveZarfState(context.Background(), &types.ZarfState{RegistryInfo: external}))
+				return c
+			},
+			expected: true,
+		},
+		{
+			name:     "uninitialized cluster",
+			initOpts: external,
+			clusterFn: func(t *testing.T) *cluster.Cluster {
+				t.Helper()
+				return &cluster.Cluster{Clientset: fake.NewSimpleClientset()}
+			},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Packager{
+				cfg:   &types.PackagerConfig{InitOpts: types.ZarfInitOptions{RegistryInfo: tt.initOpts}},
+				state: tt.state,
+			}
+			if tt.clusterFn != nil {
+				p.cluster = tt.clusterFn(t)
+			}
+			require.Equal(t, tt.expected, p.hasExternalRegistry(context.Background()))
+		})
+	}
+}
+
+func TestRegistryReferences(t *testing.T) {
+	t.Parallel()
+
+	imageList := []transform.Image{}
+	for _, src := range []string{
+		"ghcr.io/stefanprodan/podinfo:6.4.0",
+		"ghcr.io/stefanprodan/podinfo@sha256:57a654ace69ec02ba8973093b6a786faa15640575fbf0dbb603db55aca2ccec8",
+		"ghcr.io/stefanprodan/podinfo:sha256-57a654ace69ec02ba8973093b6a786faa15640575fbf0dbb603db55aca2ccec8.sig",
+	} {
+		refInfo, err := transform.ParseImageRef(src)
+		require.NoError(t, err)
+		imageList = append(imageList, refInfo)
+	}
+
+	refs, err := registryReferences(imageList, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"stefanprodan/podinfo:6.4.0-zarf-2985051089",
+		"stefanprodan/podinfo:6.4.0",
+		"stefanprodan/podinfo@sha256:57a654ace69ec02ba8973093b6a786faa15640575fbf0dbb603db55aca2ccec8",
+		"stefanprodan/podinfo:sha256-57a654ace69ec02ba8973093b6a786faa15640575fbf0dbb603db55aca2ccec8.sig",
+	}, refs)
+
+	refs, err = registryReferences(imageList[:1], true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"stefanprodan/podinfo:6.4.0"}, refs)
+}
+
+func TestComponentCharts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	componentPaths := &layout.ComponentPaths{
+		Charts:    filepath.Join(dir, "charts"),
+		Values:    filepath.Join(dir, "values"),
+		Manifests: filepath.Join(dir, "manifests"),
+		Policies:  filepath.Join(dir, "policies"),
+	}
+	for _, path := range []string{filepath.Join(componentPaths.Manifests, "deployment.yaml"), filepath.Join(componentPaths.Policies, "require-labels-0.yaml")} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("kind: ConfigMap"), 0o644))
+	}
+
+	component := v1alpha1.ZarfComponent{
+		Name: "podinfo",
+		Charts: []v1alpha1.ZarfChart{
+			{Name: "podinfo", Namespace: "podinfo"},
+			{Name: "redis", ReleaseName: "cache", Namespace: "podinfo"},
+		},
+		Manifests: []v1alpha1.ZarfManifest{
+			{Name: "deployment", Files: []string{"deployment.yaml"}},
+		},
+		Policies: []v1alpha1.ZarfPolicy{
+			{Name: "require-labels", Namespace: "kyverno", Files: []string{"policy.yaml"}},
+		},
+	}
+	p := &Packager{
+		cfg: &types.PackagerConfig{
+			Pkg: v1alpha1.ZarfPackage{
+				Metadata:   v1alpha1.ZarfMetadata{Name: "test"},
+				Components: []v1alpha1.ZarfComponent{component},
+			},
+		},
+		variableConfig: template.GetZarfVariableConfig(),
+	}
+	policies, err := p.policyCharts(componentPaths, component, nil)
+	require.NoError(t, err)
+	charts, err := p.chartsAndManifests(componentPaths, component, nil)
+	require.NoError(t, err)
+
+	// The releases recorded for rollbacks are the releases that are installed.
+	releases := []releaseRevision{}
+	for _, chart := range append(charts, policies...) {
+		releases = append(releases, releaseRevision{namespace: chart.namespace, name: chart.helm.ReleaseName()})
+	}
+	require.Equal(t, componentReleases("test", component), releases)
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		component         v1alpha1.ZarfComponent
+		chartTimeout      string
+		componentTimeouts map[string]string
+		expected          time.Duration
+		expectedErr       string
+	}{
+		{
+			name:      "deploy timeout",
+			component: v1alpha1.ZarfComponent{Name: "podinfo"},
+			expected:  15 * time.Minute,
+		},
+		{
+			name:      "component timeout",
+			component: v1alpha1.ZarfComponent{Name: "podinfo", Timeout: "5m"},
+			expected:  5 * time.Minute,
+		},
+		{
+			name:         "chart timeout",
+			component:    v1alpha1.ZarfComponent{Name: "database", Timeout: "5m"},
+			chartTimeout: "30m",
+			expected:     30 * time.Minute,
+		},
+		{
+			name:              "deploy override",
+			component:         v1alpha1.ZarfComponent{Name: "database", Timeout: "5m"},
+			chartTimeout:      "30m",
+			componentTimeouts: map[string]string{"database": "1h", "podinfo": "1m"},
+			expected:          time.Hour,
+		},
+		{
+			name:        "invalid timeout",
+			component:   v1alpha1.ZarfComponent{Name: "podinfo", Timeout: "5"},
+			expectedErr: "timeout \"5\" of component podinfo must be a positive duration",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Packager{
+				cfg: &types.PackagerConfig{
+					DeployOpts: types.ZarfDeployOptions{Timeout: 15 * time.Minute, ComponentTimeouts: tt.componentTimeouts},
+				},
+			}
+			timeout, err := p.timeout(tt.component, tt.chartTimeout)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, timeout)
+		})
+	}
+}
+
+func TestValidateComponentTimeouts(t *testing.T) {
+	t.Parallel()
+
+	p := &Packager{
+		cfg: &types.PackagerConfig{
+			Pkg: v1alpha1.ZarfPackage{
+				Components: []v1alpha1.ZarfComponent{{Name: "database"}, {Name: "podinfo"}},
+			},
+			DeployOpts: types.ZarfDeployOptions{ComponentTimeouts: map[string]string{"database": "30m", "databse": "30m"}},
+		},
+	}
+	warnings, err := p.validateComponentTimeouts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"A timeout was given for component databse which is not in the package or was not selected"}, warnings)
+
+	p.cfg.DeployOpts.ComponentTimeouts["podinfo"] = "-1m"
+	_, err = p.validateComponentTimeouts()
+	require.EqualError(t, err, "timeout \"-1m\" given for component podinfo must be a positive duration")
+}
@@ -14,12 +14,16 @@ import (
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
 	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 // DevDeploy creates + deploys a package in one shot
 func (p *Packager) DevDeploy(ctx context.Context) error {
+	p.commonOpts.Confirm = true
+	// Templates of the package definition are still prompted for by the creator based on the global options.
 	config.CommonOptions.Confirm = true
 	p.cfg.CreateOpts.SkipSBOM = !p.cfg.CreateOpts.NoYOLO
 
@@ -68,12 +72,21 @@ func (p *Packager) DevDeploy(ctx context.Context) error {
 		}
 	}
 
-	if err := pc.Assemble(ctx, p.layout, p.cfg.Pkg.Components, p.cfg.Pkg.Metadata.Architecture); err != nil {
+	err = actions.RunSet(ctx, p.cfg.Pkg.Actions.OnCreate, nil, func() error {
+		return pc.Assemble(ctx, p.layout, p.cfg.Pkg.Components, p.cfg.Pkg.Metadata.Architecture)
+	})
+	if err != nil {
 		return err
 	}
 
 	message.HeaderInfof("📦 PACKAGE DEPLOY %s", p.cfg.Pkg.Metadata.Name)
 
+	if p.cfg.Pkg.IsMultiArch() {
+		if err := p.filterComponentsByArchitecture(ctx); err != nil {
+			return err
+		}
+	}
+
 	if !p.cfg.CreateOpts.NoYOLO {
 		p.cfg.Pkg.Metadata.YOLO = true
 	} else {
@@ -83,7 +96,11 @@ func (p *Packager) DevDeploy(ctx context.Context) error {
 	}
 
 	// Get a list of all the components we are deploying and actually deploy them
-	deployedComponents, err := p.deployComponents(ctx)
+	var deployedComponents []types.DeployedComponent
+	err = actions.RunSet(ctx, p.cfg.Pkg.Actions.OnDeploy, p.variableConfig, func() error {
+		deployedComponents, err = p.deployComponents(ctx)
+		return err
+	})
 	if err != nil {
 		return err
 	}
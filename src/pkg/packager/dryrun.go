@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+
+	"github.com/zarf-dev/zarf/src/internal/packager/gate"
+	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// deployDryRun renders the charts, manifests and policies of the components with the final values of the variables and
+// prints how deploying them would change the cluster, without pushing images, running actions or changing the cluster.
+func (p *Packager) deployDryRun(ctx context.Context) error {
+	// The state that the templates of the components are rendered with is created by the init package.
+	if p.cfg.Pkg.IsInitConfig() {
+		return errors.New("dry runs are not supported for init packages")
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+	defer cancel()
+	if err := p.connectToCluster(connectCtx); err != nil {
+		return fmt.Errorf("unable to connect to the Kubernetes cluster: %w", err)
+	}
+	state, err := p.cluster.LoadZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	p.state = state
+	serverVersion, err := p.cluster.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("unable to get the Kubernetes version of the cluster: %w", err)
+	}
+
+	changes := map[cluster.ObjectChange]int{}
+	violations := 0
+	for _, component := range p.cfg.Pkg.Components {
+		if err := p.populateComponentAndStateTemplates(component.Name); err != nil {
+			return err
+		}
+		componentPaths := p.layout.Components.Dirs[component.Name]
+		gates, err := p.loadPolicyGates(ctx, componentPaths, component)
+		if err != nil {
+			return err
+		}
+		// Charts are only rendered, so that the namespaces they are installed in are not created or updated.
+		policies, err := p.policyCharts(componentPaths, component, nil)
+		if err != nil {
+			return err
+		}
+		charts, err := p.chartsAndManifests(componentPaths, component, nil)
+		if err != nil {
+			return err
+		}
+
+		diffs := []cluster.ObjectDiff{}
+		componentViolations := []gate.Violation{}
+		for _, chart := range append(policies, charts...) {
+			helm.WithKubeVersion(serverVersion.String())(chart.helm)
+			manifest, _, err := chart.helm.TemplateChart(ctx)
+			if err != nil {
+				return err
+			}
+			chartDiffs, err := p.cluster.DiffRelease(ctx, chart.namespace, chart.helm.ReleaseName(), manifest)
+			if err != nil {
+				return fmt.Errorf("unable to diff helm release %s: %w", chart.helm.ReleaseName(), err)
+			}
+			diffs = append(diffs, chartDiffs...)
+			for _, g := range gates {
+				chartViolations, err := g.Evaluate(ctx, manifest)
+				if err != nil {
+					return err
+				}
+				componentViolations = append(componentViolations, chartViolations...)
+			}
+		}
+		for _, diff := range diffs {
+			changes[diff.Change]++
+		}
+		printObjectDiffs(component.Name, diffs)
+		if len(componentViolations) > 0 {
+			message.Warnf("Deploying component %s would fail, its rendered manifests have %d policy gate violations", component.Name, len(componentViolations))
+			printPolicyGateViolations(componentViolations)
+			violations += len(componentViolations)
+		}
+	}
+
+	if len(p.cfg.Pkg.Components) == 0 {
+		message.Warn("No components were selected for deployment.  Inspect the package to view the available components and select components interactively or by name with \"--components\"")
+	}
+	message.Infof("Deploying the package would create %d, update %d and delete %d objects", changes[cluster.ObjectChangeCreate], changes[cluster.ObjectChangeUpdate], changes[cluster.ObjectChangeDelete])
+	if violations > 0 {
+		message.Warnf("Deploying the package would fail, its rendered manifests have %d policy gate violations", violations)
+	}
+	message.Note("Images and repos are not pushed and actions are not run during a dry run, so objects that depend on them may differ when the package is deployed")
+	return nil
+}
+
+// printObjectDiffs prints the objects of a component that deploying it would change, followed by the lines of each
+// object that would change.
+func printObjectDiffs(componentName string, diffs []cluster.ObjectDiff) {
+	message.HeaderInfof("📦 %s COMPONENT", strings.ToUpper(componentName))
+	if len(diffs) == 0 {
+		message.Infof("Deploying component %s would not change the cluster", componentName)
+		return
+	}
+
+	header := []string{"Object", "Change"}
+	data := [][]string{}
+	for _, diff := range diffs {
+		data = append(data, []string{diff.Object, string(diff.Change)})
+	}
+	message.Table(header, data)
+
+	for _, diff := range diffs {
+		pterm.Println()
+		pterm.Println(pterm.Bold.Sprintf("%s (%s)", diff.Object, diff.Change))
+		for _, line := range strings.Split(strings.TrimSuffix(diff.Diff, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				pterm.Println(pterm.FgGreen.Sprint(line))
+			case strings.HasPrefix(line, "-"):
+				pterm.Println(pterm.FgRed.Sprint(line))
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package features records the Zarf features a package relies on and checks them against the running CLI.
+package features
+
+import (
+	"slices"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// Features of a package that are recorded in its build data on create.
+const (
+	DataInjections    = "data-injections"
+	Extensions        = "extensions"
+	OCIImports        = "oci-imports"
+	Policies          = "policies"
+	PolicyGates       = "policy-gates"
+	HealthChecks      = "health-checks"
+	Readiness         = "readiness"
+	Connect           = "connect"
+	DependsOn         = "depends-on"
+	Kustomizations    = "kustomizations"
+	ActionWaits       = "action-waits"
+	DistroTargeting   = "distro-targeting"
+	MultiArch         = "multi-arch"
+	FlavorInheritance = "flavor-inheritance"
+	ComponentGroups   = "component-groups"
+	CosignKeyPaths    = "cosign-key-paths"
+)
+
+// Supported is every feature this version of Zarf can deploy.
+var Supported = []string{
+	DataInjections,
+	Extensions,
+	OCIImports,
+	Policies,
+	PolicyGates,
+	HealthChecks,
+	Readiness,
+	Connect,
+	DependsOn,
+	Kustomizations,
+	ActionWaits,
+	DistroTargeting,
+	MultiArch,
+	FlavorInheritance,
+	ComponentGroups,
+	CosignKeyPaths,
+}
+
+// Deprecated maps the supported features that will be removed from Zarf to the reason they are deprecated.
+var Deprecated = map[string]string{
+	Extensions:      "the Big Bang extension will be removed in Zarf v1.0.0",
+	ComponentGroups: "component groups will be removed in Zarf v1.0.0, consider using 'only.flavor' instead",
+	CosignKeyPaths:  "component cosignKeyPath will be removed in Zarf v1.0.0",
+}
+
+// Detect returns the sorted features the given package uses, ociImports should be true when any of its components
+// were imported from an OCI registry since imports are removed from the package when it is composed.
+func Detect(pkg v1alpha1.ZarfPackage, ociImports bool) []string {
+	used := map[string]bool{
+		OCIImports:        ociImports,
+		MultiArch:         pkg.Metadata.Architecture == v1alpha1.MultiArch,
+		FlavorInheritance: slices.ContainsFunc(pkg.Flavors, func(f v1alpha1.ZarfFlavor) bool { return len(f.Inherits) > 0 }),
+	}
+	for _, component := range pkg.Components {
+		used[DataInjections] = used[DataInjections] || len(component.DataInjections) > 0
+		used[Extensions] = used[Extensions] || component.Extensions.BigBang != nil
+		used[OCIImports] = used[OCIImports] || helpers.IsOCIURL(component.Import.URL)
+		used[Policies] = used[Policies] || len(component.Policies) > 0
+		used[PolicyGates] = used[PolicyGates] || len(component.PolicyGates) > 0
+		used[HealthChecks] = used[HealthChecks] || len(component.HealthChecks) > 0
+		used[Readiness] = used[Readiness] || len(component.Readiness) > 0
+		used[Connect] = used[Connect] || len(component.Connect) > 0
+		used[DependsOn] = used[DependsOn] || len(component.DependsOn) > 0
+		used[DistroTargeting] = used[DistroTargeting] || len(component.Only.Cluster.Distros) > 0
+		used[ComponentGroups] = used[ComponentGroups] || component.DeprecatedGroup != ""
+		used[CosignKeyPaths] = used[CosignKeyPaths] || component.DeprecatedCosignKeyPath != ""
+		for _, manifest := range component.Manifests {
+			used[Kustomizations] = used[Kustomizations] || len(manifest.Kustomizations) > 0
+		}
+		for _, set := range []v1alpha1.ZarfComponentActionSet{component.Actions.OnCreate, component.Actions.OnDeploy, component.Actions.OnRemove} {
+			for _, actions := range [][]v1alpha1.ZarfComponentAction{set.Before, set.After, set.OnSuccess, set.OnFailure} {
+				used[ActionWaits] = used[ActionWaits] || slices.ContainsFunc(actions, func(a v1alpha1.ZarfComponentAction) bool { return a.Wait != nil })
+			}
+		}
+	}
+
+	features := []string{}
+	for feature, ok := range used {
+		if ok {
+			features = append(features, feature)
+		}
+	}
+	slices.Sort(features)
+	return features
+}
+
+// Check returns the given features that this version of Zarf does not support and the ones that are deprecated.
+func Check(features []string) (unsupported []string, deprecated []string) {
+	for _, feature := range features {
+		if !slices.Contains(Supported, feature) {
+			unsupported = append(unsupported, feature)
+			continue
+		}
+		if _, ok := Deprecated[feature]; ok {
+			deprecated = append(deprecated, feature)
+		}
+	}
+	return unsupported, deprecated
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1/extensions"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		pkg        v1alpha1.ZarfPackage
+		ociImports bool
+		expected   []string
+	}{
+		{
+			name:     "no features",
+			pkg:      v1alpha1.ZarfPackage{Components: []v1alpha1.ZarfComponent{{Name: "podinfo", Images: []string{"podinfo:6.4.0"}}}},
+			expected: []string{},
+		},
+		{
+			name:       "oci imports",
+			ociImports: true,
+			expected:   []string{OCIImports},
+		},
+		{
+			name: "component features",
+			pkg: v1alpha1.ZarfPackage{
+				Metadata: v1alpha1.ZarfMetadata{Architecture: v1alpha1.MultiArch},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:           "data",
+						DataInjections: []v1alpha1.ZarfDataInjection{{Source: "data"}},
+						Manifests:      []v1alpha1.ZarfManifest{{Name: "kustomize", Kustomizations: []string{"kustomization"}}},
+						Readiness:      []v1alpha1.ZarfComponentReadiness{{Name: "data", Protocol: "tcp", Address: "data.data.svc.cluster.local:8080"}},
+						Connect:        []v1alpha1.ZarfComponentConnect{{Name: "data", Namespace: "data", Service: "data", Port: 8080}},
+						DependsOn:      []string{"bigbang"},
+						PolicyGates:    []v1alpha1.ZarfPolicyGate{{Name: "data", Engine: v1alpha1.PolicyGateEngineCEL, Files: []string{"data.yaml"}}},
+					},
+					{
+						Name:            "bigbang",
+						DeprecatedGroup: "bigbang",
+						Extensions:      extensions.ZarfComponentExtensions{BigBang: &extensions.BigBang{Version: "2.0.0"}},
+						Actions: v1alpha1.ZarfComponentActions{
+							OnDeploy: v1alpha1.ZarfComponentActionSet{
+								After: []v1alpha1.ZarfComponentAction{{Wait: &v1alpha1.ZarfComponentActionWait{}}},
+							},
+						},
+					},
+				},
+			},
+			expected: []string{ActionWaits, ComponentGroups, Connect, DataInjections, DependsOn, Extensions, Kustomizations, MultiArch, PolicyGates, Readiness},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, Detect(tt.pkg, tt.ociImports))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	unsupported, deprecated := Check([]string{DataInjections, "teleportation", ComponentGroups})
+	require.Equal(t, []string{"teleportation"}, unsupported)
+	require.Equal(t, []string{ComponentGroups}, deprecated)
+}
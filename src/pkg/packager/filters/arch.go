@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// ByArchitecture creates a new filter that filters components based on the architectures of the target cluster.
+func ByArchitecture(archs ...string) ComponentFilterStrategy {
+	return &archFilter{archs}
+}
+
+// archFilter filters components based on the architectures of the target cluster.
+type archFilter struct {
+	archs []string
+}
+
+// ErrArchRequired is returned when no architectures are set.
+var ErrArchRequired = errors.New("at least one architecture is required")
+
+// Apply applies the filter.
+func (f *archFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
+	if len(f.archs) == 0 {
+		return nil, ErrArchRequired
+	}
+
+	filtered := []v1alpha1.ZarfComponent{}
+	for _, component := range pkg.Components {
+		if component.Only.Cluster.Architecture == "" || slices.Contains(f.archs, component.Only.Cluster.Architecture) {
+			filtered = append(filtered, component)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestArchFilter(t *testing.T) {
+	pkg := v1alpha1.ZarfPackage{}
+	for _, arch := range []string{"", "amd64", "arm64"} {
+		pkg.Components = append(pkg.Components, v1alpha1.ZarfComponent{
+			Name: "component-" + arch,
+			Only: v1alpha1.ZarfComponentOnlyTarget{
+				Cluster: v1alpha1.ZarfComponentOnlyCluster{
+					Architecture: arch,
+				},
+			},
+		})
+	}
+
+	_, err := ByArchitecture().Apply(pkg)
+	require.ErrorIs(t, err, ErrArchRequired)
+
+	result, err := ByArchitecture("arm64").Apply(pkg)
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.ZarfComponent{pkg.Components[0], pkg.Components[2]}, result)
+
+	result, err = ByArchitecture("amd64", "arm64").Apply(pkg)
+	require.NoError(t, err)
+	require.Equal(t, pkg.Components, result)
+}
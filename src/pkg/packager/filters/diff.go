@@ -4,6 +4,7 @@
 package filters
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-git/go-git/v5/plumbing"
@@ -13,7 +14,7 @@ import (
 	"github.com/zarf-dev/zarf/src/types"
 )
 
-// ByDifferentialData filters any images and repos already present in the reference package components.
+// ByDifferentialData filters any images, repos, and unchanged pinned files already present in the reference package components.
 func ByDifferentialData(diffData *types.DifferentialData) ComponentFilterStrategy {
 	return &differentialDataFilter{
 		diffData: diffData,
@@ -27,19 +28,16 @@ type differentialDataFilter struct {
 func (f *differentialDataFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
 	diffComponents := []v1alpha1.ZarfComponent{}
 	for _, component := range pkg.Components {
-		filteredImages := []string{}
-		for _, img := range component.Images {
-			imgRef, err := transform.ParseImageRef(img)
-			if err != nil {
-				return nil, fmt.Errorf("unable to parse image ref %s: %w", img, err)
-			}
-			imgTag := imgRef.TagOrDigest
-			includeImage := imgTag == ":latest" || imgTag == ":stable" || imgTag == ":nightly"
-			if includeImage || !f.diffData.DifferentialImages[img] {
-				filteredImages = append(filteredImages, img)
-			}
+		filteredImages, err := f.filterImages(component.Images)
+		if err != nil {
+			return nil, err
 		}
 		component.Images = filteredImages
+		filteredArtifacts, err := f.filterImages(component.Artifacts)
+		if err != nil {
+			return nil, err
+		}
+		component.Artifacts = filteredArtifacts
 
 		filteredRepos := []string{}
 		for _, repoURL := range component.Repos {
@@ -58,7 +56,49 @@ func (f *differentialDataFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.Zar
 		}
 		component.Repos = filteredRepos
 
+		filteredFiles := []v1alpha1.ZarfFile{}
+		for _, file := range component.Files {
+			key := DifferentialFileKey(file)
+			if key == "" || !f.diffData.DifferentialFiles[key] {
+				filteredFiles = append(filteredFiles, file)
+			}
+		}
+		component.Files = filteredFiles
+
 		diffComponents = append(diffComponents, component)
 	}
 	return diffComponents, nil
 }
+
+// filterImages removes the images or OCI artifacts that are already in the differential package unless they use a tag
+// that is expected to move.
+func (f *differentialDataFilter) filterImages(images []string) ([]string, error) {
+	filtered := []string{}
+	for _, img := range images {
+		imgRef, err := transform.ParseImageRef(img)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse image ref %s: %w", img, err)
+		}
+		imgTag := imgRef.TagOrDigest
+		includeImage := imgTag == ":latest" || imgTag == ":stable" || imgTag == ":nightly"
+		if includeImage || !f.diffData.DifferentialImages[img] {
+			filtered = append(filtered, img)
+		}
+	}
+	return filtered, nil
+}
+
+// DifferentialFileKey returns the key a file is matched on against the files of the reference package, or an empty key
+// for files that are not pinned with a shasum and so can not be known to be unchanged. Files only match when they are
+// placed the same way on deploy, the source they are pulled from is free to change.
+func DifferentialFileKey(file v1alpha1.ZarfFile) string {
+	if file.Shasum == "" {
+		return ""
+	}
+	file.Source = ""
+	b, err := json.Marshal(file)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
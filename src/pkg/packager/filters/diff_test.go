@@ -32,9 +32,31 @@ func TestCopyFilter(t *testing.T) {
 					"https://example.com/commit.git@524980951ff16e19dc25232e9aea8fd693989ba6",
 					"https://example.com/diff-commit.git@524980951ff16e19dc25232e9aea8fd693989ba6",
 				},
+				Files: []v1alpha1.ZarfFile{
+					{Source: "https://example.com/unpinned.txt", Target: "unpinned.txt"},
+					{Source: "https://example.com/changed.txt", Target: "changed.txt", Shasum: "changed"},
+					{Source: "https://example.com/diff.txt", Target: "diff.txt", Shasum: "diff"},
+					{Source: "https://mirror.example.com/diff.txt", Target: "mirrored.txt", Shasum: "mirrored"},
+					{Source: "https://example.com/moved.txt", Target: "new/moved.txt", Shasum: "moved"},
+					{Source: "https://example.com/tool", Target: "tool", Shasum: "tool", Executable: true},
+				},
 			},
 		},
 	}
+	referenceFiles := []v1alpha1.ZarfFile{
+		{Source: "https://example.com/unpinned.txt", Target: "unpinned.txt"},
+		{Source: "https://example.com/changed.txt", Target: "changed.txt", Shasum: "previous"},
+		{Source: "https://example.com/diff.txt", Target: "diff.txt", Shasum: "diff"},
+		{Source: "https://example.com/diff.txt", Target: "mirrored.txt", Shasum: "mirrored"},
+		{Source: "https://example.com/moved.txt", Target: "moved.txt", Shasum: "moved"},
+		{Source: "https://example.com/tool", Target: "tool", Shasum: "tool"},
+	}
+	differentialFiles := map[string]bool{}
+	for _, file := range referenceFiles {
+		if key := DifferentialFileKey(file); key != "" {
+			differentialFiles[key] = true
+		}
+	}
 	loadedDiffData := types.DifferentialData{
 		DifferentialImages: map[string]bool{
 			"example.com/include-image-tag:latest": true,
@@ -48,6 +70,7 @@ func TestCopyFilter(t *testing.T) {
 			"https://example.com/diff-tag.git@v1":                                          true,
 			"https://example.com/diff-commit.git@524980951ff16e19dc25232e9aea8fd693989ba6": true,
 		},
+		DifferentialFiles: differentialFiles,
 	}
 
 	filter := ByDifferentialData(&loadedDiffData)
@@ -68,4 +91,11 @@ func TestCopyFilter(t *testing.T) {
 		"https://example.com/commit.git@524980951ff16e19dc25232e9aea8fd693989ba6",
 	}
 	require.ElementsMatch(t, expectedRepos, diffComponents[0].Repos)
+	expectedFiles := []v1alpha1.ZarfFile{
+		{Source: "https://example.com/unpinned.txt", Target: "unpinned.txt"},
+		{Source: "https://example.com/changed.txt", Target: "changed.txt", Shasum: "changed"},
+		{Source: "https://example.com/moved.txt", Target: "new/moved.txt", Shasum: "moved"},
+		{Source: "https://example.com/tool", Target: "tool", Shasum: "tool", Executable: true},
+	}
+	require.ElementsMatch(t, expectedFiles, diffComponents[0].Files)
 }
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/entitlement"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// ByEntitlement creates a new filter that removes the components gated by an entitlement the given tokens do not grant.
+func ByEntitlement(tokens []string, optionalComponents string) ComponentFilterStrategy {
+	return &entitlementFilter{
+		tokens:              tokens,
+		requestedComponents: helpers.StringToSlice(optionalComponents),
+	}
+}
+
+// entitlementFilter filters components based on the entitlements granted by signed entitlement tokens.
+type entitlementFilter struct {
+	tokens              []string
+	requestedComponents []string
+}
+
+// ErrNotEntitled is returned when a component that was requested by name requires an entitlement that was not granted.
+var ErrNotEntitled = errors.New("component requires an entitlement that was not granted")
+
+// Apply applies the filter.
+func (f *entitlementFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
+	gated := slices.ContainsFunc(pkg.Components, func(c v1alpha1.ZarfComponent) bool { return c.Entitlement != "" })
+	if !gated {
+		return pkg.Components, nil
+	}
+
+	granted := map[string]bool{}
+	if len(f.tokens) > 0 {
+		var err error
+		granted, err = entitlement.Verify(pkg.Metadata.EntitlementKey, pkg.Metadata.Name, f.tokens)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filtered := []v1alpha1.ZarfComponent{}
+	for _, component := range pkg.Components {
+		if component.Entitlement == "" || granted[component.Entitlement] {
+			filtered = append(filtered, component)
+			continue
+		}
+		// Components requested by name or glob fail the deploy instead of being skipped so that a missing token is not missed.
+		if state, _ := includedOrExcluded(component.Name, f.requestedComponents); component.IsRequired() || state == included {
+			return nil, fmt.Errorf("%w: %q requires %q", ErrNotEntitled, component.Name, component.Entitlement)
+		}
+		message.Warnf("Skipping component %q as it requires the %q entitlement", component.Name, component.Entitlement)
+	}
+	return filtered, nil
+}
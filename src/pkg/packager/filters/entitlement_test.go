@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/entitlement"
+)
+
+func TestEntitlementFilter(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	b, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, entitlement.Claims{Entitlements: []string{"premium"}}).SignedString(priv)
+	require.NoError(t, err)
+
+	pkg := v1alpha1.ZarfPackage{
+		Metadata: v1alpha1.ZarfMetadata{
+			Name:           "vendor",
+			EntitlementKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b})),
+		},
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "free"},
+			{Name: "premium", Entitlement: "premium"},
+			{Name: "enterprise", Entitlement: "enterprise"},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		tokens              []string
+		optionalComponents  string
+		expectedComponents  []string
+		expectedErr         error
+		expectedErrContains string
+	}{
+		{
+			name:               "no tokens",
+			expectedComponents: []string{"free"},
+		},
+		{
+			name:               "granted entitlement",
+			tokens:             []string{token},
+			expectedComponents: []string{"free", "premium"},
+		},
+		{
+			name:               "gated component requested by name",
+			tokens:             []string{token},
+			optionalComponents: "free,enterprise",
+			expectedErr:        ErrNotEntitled,
+		},
+		{
+			name:               "gated component requested by glob",
+			tokens:             []string{token},
+			optionalComponents: "enter*",
+			expectedErr:        ErrNotEntitled,
+		},
+		{
+			name:               "gated component deselected",
+			tokens:             []string{token},
+			optionalComponents: "-enterprise",
+			expectedComponents: []string{"free", "premium"},
+		},
+		{
+			name:                "invalid token",
+			tokens:              []string{"not-a-token"},
+			expectedErrContains: "entitlement token 1 is not valid for this package",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := ByEntitlement(tt.tokens, tt.optionalComponents).Apply(pkg)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			if tt.expectedErrContains != "" {
+				require.ErrorContains(t, err, tt.expectedErrContains)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range result {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expectedComponents, names)
+		})
+	}
+
+	// Packages without gated components do not verify tokens.
+	result, err := ByEntitlement([]string{"not-a-token"}, "").Apply(v1alpha1.ZarfPackage{Components: []v1alpha1.ZarfComponent{{Name: "free"}}})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+}
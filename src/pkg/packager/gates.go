@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/gate"
+	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// hasPolicyGates returns if a component deploys anything that is checked by policy gates and has gates to check it.
+func (p *Packager) hasPolicyGates(component v1alpha1.ZarfComponent) bool {
+	deploysManifests := len(component.Charts) > 0 || len(component.Manifests) > 0 || len(component.Policies) > 0
+	return deploysManifests && (len(component.PolicyGates) > 0 || len(p.policyGates) > 0)
+}
+
+// loadPolicyGates loads the policy gates of a component from the package, followed by the policy gates given on deploy.
+func (p *Packager) loadPolicyGates(ctx context.Context, componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent) ([]*gate.Gate, error) {
+	gates := []*gate.Gate{}
+	for _, policyGate := range component.PolicyGates {
+		paths := []string{}
+		for idx := range policyGate.Files {
+			paths = append(paths, filepath.Join(componentPaths.PolicyGates, gate.FileName(policyGate, idx)))
+		}
+		g, err := gate.Load(ctx, policyGate.Name, policyGate.Engine, paths...)
+		if err != nil {
+			return nil, err
+		}
+		gates = append(gates, g)
+	}
+	return append(gates, p.policyGates...), nil
+}
+
+// checkPolicyGates renders the policy bundles, charts and manifests of a component and returns the objects of them that
+// its policy gates deny. Charts are only rendered, so that nothing is changed in the cluster before they are checked.
+func (p *Packager) checkPolicyGates(ctx context.Context, componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent) ([]gate.Violation, error) {
+	gates, err := p.loadPolicyGates(ctx, componentPaths, component)
+	if err != nil {
+		return nil, err
+	}
+	kubeVersion := ""
+	if p.cluster != nil {
+		serverVersion, err := p.cluster.Clientset.Discovery().ServerVersion()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the Kubernetes version of the cluster: %w", err)
+		}
+		kubeVersion = serverVersion.String()
+	}
+	policies, err := p.policyCharts(componentPaths, component, nil)
+	if err != nil {
+		return nil, err
+	}
+	charts, err := p.chartsAndManifests(componentPaths, component, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := []gate.Violation{}
+	for _, chart := range append(policies, charts...) {
+		if kubeVersion != "" {
+			helm.WithKubeVersion(kubeVersion)(chart.helm)
+		}
+		manifest, _, err := chart.helm.TemplateChart(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range gates {
+			chartViolations, err := g.Evaluate(ctx, manifest)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, chartViolations...)
+		}
+	}
+	return violations, nil
+}
+
+// enforcePolicyGates fails the deploy of a component before any of it is applied if its rendered manifests are denied
+// by its policy gates or the policy gates given on deploy.
+func (p *Packager) enforcePolicyGates(ctx context.Context, componentPaths *layout.ComponentPaths, component v1alpha1.ZarfComponent) error {
+	spinner := message.NewProgressSpinner("Checking the rendered manifests against the policy gates")
+	defer spinner.Stop()
+
+	violations, err := p.checkPolicyGates(ctx, componentPaths, component)
+	if err != nil {
+		return fmt.Errorf("unable to check the policy gates: %w", err)
+	}
+	if len(violations) > 0 {
+		printPolicyGateViolations(violations)
+		return fmt.Errorf("the rendered manifests of component %s have %d policy gate violations", component.Name, len(violations))
+	}
+	spinner.Success()
+	return nil
+}
+
+// printPolicyGateViolations prints the objects that are denied by policy gates with the reasons they are denied.
+func printPolicyGateViolations(violations []gate.Violation) {
+	header := []string{"Object", "Policy Gate", "Violation"}
+	data := [][]string{}
+	for _, v := range violations {
+		data = append(data, []string{v.Object, v.Gate, v.Message})
+	}
+	message.Table(header, data)
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/gate"
+	"github.com/zarf-dev/zarf/src/internal/packager/template"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestCheckPolicyGates(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	dir := t.TempDir()
+	componentPaths := &layout.ComponentPaths{
+		Manifests:   filepath.Join(dir, "manifests"),
+		PolicyGates: filepath.Join(dir, "gates"),
+	}
+	files := map[string]string{
+		filepath.Join(componentPaths.Manifests, "deployment.yaml"): `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: podinfo
+spec:
+  replicas: 5
+  template:
+    spec:
+      containers:
+      - name: podinfo
+        image: ghcr.io/stefanprodan/podinfo:latest
+`,
+		filepath.Join(componentPaths.PolicyGates, "replicas-0.yaml"): `validations:
+- expression: "object.kind != 'Deployment' || object.spec.replicas <= 3"
+  message: replicas must not be more than 3
+`,
+		filepath.Join(dir, "local", "tags.rego"): `package zarf
+
+import rego.v1
+
+deny contains msg if {
+	some container in input.spec.template.spec.containers
+	endswith(container.image, ":latest")
+	msg := sprintf("container %s must not use the latest tag", [container.name])
+}
+`,
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	localGates, err := gate.LoadPaths(ctx, []string{filepath.Join(dir, "local")})
+	require.NoError(t, err)
+
+	component := v1alpha1.ZarfComponent{
+		Name: "podinfo",
+		Manifests: []v1alpha1.ZarfManifest{
+			{Name: "podinfo", Namespace: "podinfo", Files: []string{"deployment.yaml"}},
+		},
+		PolicyGates: []v1alpha1.ZarfPolicyGate{
+			{Name: "replicas", Engine: v1alpha1.PolicyGateEngineCEL, Files: []string{"replicas.yaml"}},
+		},
+	}
+	p := &Packager{
+		cfg: &types.PackagerConfig{
+			Pkg: v1alpha1.ZarfPackage{
+				Metadata:   v1alpha1.ZarfMetadata{Name: "test"},
+				Components: []v1alpha1.ZarfComponent{component},
+			},
+		},
+		variableConfig: template.GetZarfVariableConfig(),
+		policyGates:    localGates,
+	}
+	require.True(t, p.hasPolicyGates(component))
+	violations, err := p.checkPolicyGates(ctx, componentPaths, component)
+	require.NoError(t, err)
+	expected := []gate.Violation{
+		{Gate: "replicas", Object: "Deployment podinfo/podinfo", Message: "replicas must not be more than 3"},
+		{Gate: filepath.Join(dir, "local"), Object: "Deployment podinfo/podinfo", Message: "container podinfo must not use the latest tag"},
+	}
+	require.Equal(t, expected, violations)
+
+	// Components that do not deploy any manifests have nothing to check.
+	require.False(t, p.hasPolicyGates(v1alpha1.ZarfComponent{Name: "files", PolicyGates: component.PolicyGates}))
+}
@@ -8,17 +8,28 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/pterm/pterm"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/internal/packager/sbom"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/composer"
+	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
 // Inspect list the contents of a package.
 func (p *Packager) Inspect(ctx context.Context) error {
 	wantSBOM := p.cfg.InspectOpts.ViewSBOM || p.cfg.InspectOpts.SBOMOutputDir != ""
+	wantDocs := p.cfg.InspectOpts.DocsOutputDir != ""
 
-	pkg, _, err := p.source.LoadPackageMetadata(ctx, p.layout, wantSBOM, true)
+	pkg, _, err := p.source.LoadPackageMetadata(ctx, p.layout, wantSBOM, wantDocs, true)
 	if err != nil {
 		return err
 	}
@@ -39,6 +50,15 @@ func (p *Packager) Inspect(ctx context.Context) error {
 
 	sbomDir := p.layout.SBOMs.Path
 
+	if wantSBOM && p.layout.SBOMs.Path != "" {
+		// Packages created before SBOM formats could be selected only contain syft JSON SBOMs.
+		formats := p.cfg.Pkg.Build.SBOMFormats
+		if len(formats) == 0 {
+			formats = []string{sbom.FormatSyftJSON}
+		}
+		message.Notef("This package's SBOMs are available in the following formats: %s", strings.Join(formats, ", "))
+	}
+
 	if p.cfg.InspectOpts.SBOMOutputDir != "" {
 		out, err := p.layout.SBOMs.OutputSBOMFiles(p.cfg.InspectOpts.SBOMOutputDir, p.cfg.Pkg.Metadata.Name)
 		if err != nil {
@@ -54,5 +74,167 @@ func (p *Packager) Inspect(ctx context.Context) error {
 		}
 	}
 
+	if wantDocs {
+		if p.layout.Docs == "" {
+			message.Warnf(lang.CmdPackageInspectNoDocsWarn, p.cfg.Pkg.Metadata.Name)
+			return nil
+		}
+		docsDir := filepath.Join(p.cfg.InspectOpts.DocsOutputDir, p.cfg.Pkg.Metadata.Name)
+		if err := p.layout.ExtractDocs(docsDir); err != nil {
+			return fmt.Errorf("unable to extract the package docs: %w", err)
+		}
+		message.Infof("Package docs successfully extracted to %s", docsDir)
+	}
+
+	return nil
+}
+
+// InspectDefinition displays the composed package definition in the create base directory, optionally annotating each
+// composed value with the package in the import chain that it came from.
+func (p *Packager) InspectDefinition(ctx context.Context) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Return to the original working directory
+		if err := os.Chdir(cwd); err != nil {
+			message.Warnf("Unable to return to the original working directory: %s", err.Error())
+		}
+	}()
+	if err := os.Chdir(p.cfg.CreateOpts.BaseDir); err != nil {
+		return fmt.Errorf("unable to access directory %q: %w", p.cfg.CreateOpts.BaseDir, err)
+	}
+
+	var pkg v1alpha1.ZarfPackage
+	if err := utils.ReadYaml(layout.ZarfYAML, &pkg); err != nil {
+		return err
+	}
+	pkg, provenance, warnings, err := creator.ComposeWithProvenance(ctx, pkg, p.cfg.CreateOpts.Flavor, p.cfg.CreateOpts.SetVariables)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		message.Warn(warning)
+	}
+
+	if !p.cfg.InspectOpts.Provenance {
+		utils.ColorPrintYAML(pkg, nil, false)
+		return nil
+	}
+
+	comments := goyaml.CommentMap{}
+	for path, origin := range provenance {
+		comments["$."+path] = []*goyaml.Comment{goyaml.LineComment(" " + origin.String())}
+	}
+	b, err := goyaml.MarshalWithOptions(pkg, goyaml.WithComment(comments))
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(os.Stdout, string(b))
 	return nil
 }
+
+// InspectImportTree displays the import chain of each component of the package definition in the create base
+// directory, with the filters each imported component was matched with and what it contributes to the package.
+func (p *Packager) InspectImportTree(ctx context.Context) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Return to the original working directory
+		if err := os.Chdir(cwd); err != nil {
+			message.Warnf("Unable to return to the original working directory: %s", err.Error())
+		}
+	}()
+	if err := os.Chdir(p.cfg.CreateOpts.BaseDir); err != nil {
+		return fmt.Errorf("unable to access directory %q: %w", p.cfg.CreateOpts.BaseDir, err)
+	}
+
+	var pkg v1alpha1.ZarfPackage
+	if err := utils.ReadYaml(layout.ZarfYAML, &pkg); err != nil {
+		return err
+	}
+	variables := []string{}
+	for _, v := range pkg.Variables {
+		variables = append(variables, v.Name)
+	}
+	for _, c := range pkg.Constants {
+		variables = append(variables, c.Name)
+	}
+
+	composed, trees, warnings, err := creator.ComposeWithImportTrees(ctx, pkg, p.cfg.CreateOpts.Flavor, p.cfg.CreateOpts.SetVariables)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		message.Warn(warning)
+	}
+
+	title := fmt.Sprintf("%s (architecture: %s", composed.Metadata.Name, composed.Metadata.Architecture)
+	if p.cfg.CreateOpts.Flavor != "" {
+		title += fmt.Sprintf(", flavor: %s", p.cfg.CreateOpts.Flavor)
+	}
+	root := pterm.TreeNode{Text: title + ")"}
+	if len(variables) > 0 {
+		root.Children = append(root.Children, pterm.TreeNode{Text: "variables: " + strings.Join(variables, ", ")})
+	}
+	for _, tree := range trees {
+		node := pterm.TreeNode{Text: fmt.Sprintf("component %q", tree.Component)}
+		// each layer is nested under the layer that imports it
+		var layer *pterm.TreeNode
+		for i := len(tree.Layers) - 1; i >= 0; i-- {
+			n := importLayerNode(tree.Layers[i], i == 0)
+			if layer != nil {
+				n.Children = append(n.Children, *layer)
+			}
+			layer = &n
+		}
+		node.Children = append(node.Children, *layer)
+		root.Children = append(root.Children, node)
+	}
+	return pterm.DefaultTree.WithRoot(root).Render()
+}
+
+// importLayerNode returns the tree node of a layer in the import chain of a component.
+func importLayerNode(layer composer.Layer, head bool) pterm.TreeNode {
+	text := fmt.Sprintf("%s components[%d] %q", layer.PackageName, layer.Index, layer.Component)
+	if !head {
+		text += fmt.Sprintf(" from %s", layer.Location)
+	}
+	node := pterm.TreeNode{Text: text}
+
+	only := []string{}
+	if layer.Only.LocalOS != "" {
+		only = append(only, fmt.Sprintf("localOS=%s", layer.Only.LocalOS))
+	}
+	if layer.Only.Cluster.Architecture != "" {
+		only = append(only, fmt.Sprintf("architecture=%s", layer.Only.Cluster.Architecture))
+	}
+	if len(layer.Only.Cluster.Distros) > 0 {
+		only = append(only, fmt.Sprintf("distros=%s", strings.Join(layer.Only.Cluster.Distros, ",")))
+	}
+	if len(layer.Only.Flavor) > 0 {
+		only = append(only, fmt.Sprintf("flavor=%s", strings.Join(layer.Only.Flavor, ",")))
+	}
+	if layer.Only.Expression != "" {
+		only = append(only, fmt.Sprintf("expression=%q", layer.Only.Expression))
+	}
+
+	leaves := []struct {
+		name   string
+		values []string
+	}{
+		{"only", only},
+		{"images", layer.Images},
+		{"charts", layer.Charts},
+		{"variables", layer.Variables},
+	}
+	for _, leaf := range leaves {
+		if len(leaf.values) > 0 {
+			node.Children = append(node.Children, pterm.TreeNode{Text: fmt.Sprintf("%s: %s", leaf.name, strings.Join(leaf.values, ", "))})
+		}
+	}
+	return node
+}
@@ -9,16 +9,25 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/pterm/pterm"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 )
 
-func (p *Packager) confirmAction(stage string, warnings []string, sbomViewFiles []string) bool {
+// confirmAction displays the package definition and confirms the operation of the given stage on the given components.
+func (p *Packager) confirmAction(stage string, components []v1alpha1.ZarfComponent, warnings []string, sbomViewFiles []string) error {
+	confirmer := interactive.NewConfirmer(p.commonOpts.Confirm)
+	plan := p.plan(stage, components, warnings)
+	// Without a prompt only the plan is written, so that it is the only output to parse.
+	if !confirmer.Prompts() {
+		return confirmer.Confirm(plan, "")
+	}
+
 	pterm.Println()
 	message.HeaderInfof("📦 PACKAGE DEFINITION")
 	utils.ColorPrintYAML(p.cfg.Pkg, p.getPackageYAMLHints(stage), true)
@@ -63,27 +72,65 @@ func (p *Packager) confirmAction(stage string, warnings []string, sbomViewFiles
 
 	message.HorizontalRule()
 
-	// Display prompt if not auto-confirmed
-	if config.CommonOptions.Confirm {
-		pterm.Println()
+	pterm.Println()
+	if err := confirmer.Confirm(plan, stage+" this Zarf package?"); err != nil {
+		return err
+	}
+	if p.commonOpts.Confirm {
 		message.Successf("%s Zarf package confirmed", stage)
-		return config.CommonOptions.Confirm
 	}
+	return nil
+}
 
-	prompt := &survey.Confirm{
-		Message: stage + " this Zarf package?",
+// plan describes the operation of the given stage on the given components for confirmation.
+func (p *Packager) plan(stage string, components []v1alpha1.ZarfComponent, warnings []string) interactive.Plan {
+	plan := interactive.Plan{
+		Operation:  stage,
+		Kind:       string(p.cfg.Pkg.Kind),
+		Package:    p.cfg.Pkg.Metadata.Name,
+		Version:    p.cfg.Pkg.Metadata.Version,
+		Source:     p.cfg.PkgOpts.PackageSource,
+		Components: []interactive.PlanComponent{},
+		Warnings:   warnings,
 	}
-
-	pterm.Println()
-
-	// Prompt the user for confirmation, on abort return false
-	var confirm bool
-	if err := survey.AskOne(prompt, &confirm); err != nil || !confirm {
-		// User aborted or declined, cancel the action
-		return false
+	for _, component := range components {
+		pc := interactive.PlanComponent{
+			Name:   component.Name,
+			Images: component.Images,
+			Repos:  component.Repos,
+		}
+		for _, chart := range component.Charts {
+			name := chart.ReleaseName
+			if name == "" {
+				name = chart.Name
+			}
+			pc.Charts = append(pc.Charts, interactive.PlanResource{Name: name, Namespace: chart.Namespace, Version: chart.Version})
+		}
+		for _, manifest := range component.Manifests {
+			pc.Manifests = append(pc.Manifests, interactive.PlanResource{Name: manifest.Name, Namespace: manifest.Namespace})
+		}
+		for _, file := range component.Files {
+			pc.Files = append(pc.Files, file.Target)
+		}
+		for _, data := range component.DataInjections {
+			pc.DataInjections = append(pc.DataInjections, fmt.Sprintf("%s/%s:%s", data.Target.Namespace, data.Target.Selector, data.Target.Path))
+		}
+		plan.Components = append(plan.Components, pc)
 	}
-
-	return true
+	if stage == config.ZarfDeployStage && len(p.cfg.Pkg.Variables) > 0 {
+		plan.Variables = map[string]string{}
+		for _, variable := range p.cfg.Pkg.Variables {
+			value, present := p.cfg.PkgOpts.SetVariables[variable.Name]
+			if !present {
+				value = variable.Default
+			}
+			if variable.Sensitive {
+				value = "**sanitized**"
+			}
+			plan.Variables[variable.Name] = value
+		}
+	}
+	return plan
 }
 
 func (p *Packager) getPackageYAMLHints(stage string) map[string]string {
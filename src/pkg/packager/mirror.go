@@ -22,6 +22,7 @@ func (p *Packager) Mirror(ctx context.Context) error {
 	filter := filters.Combine(
 		filters.ByLocalOS(runtime.GOOS),
 		filters.BySelectState(p.cfg.PkgOpts.OptionalComponents),
+		filters.ByEntitlement(p.cfg.DeployOpts.Entitlements, p.cfg.PkgOpts.OptionalComponents),
 	)
 
 	pkg, warnings, err := p.source.LoadPackage(ctx, p.layout, filter, true)
@@ -37,8 +38,8 @@ func (p *Packager) Mirror(ctx context.Context) error {
 	warnings = append(warnings, sbomWarnings...)
 
 	// Confirm the overall package mirror
-	if !p.confirmAction(config.ZarfMirrorStage, warnings, sbomViewFiles) {
-		return fmt.Errorf("mirror cancelled")
+	if err := p.confirmAction(config.ZarfMirrorStage, p.cfg.Pkg.Components, warnings, sbomViewFiles); err != nil {
+		return fmt.Errorf("mirror cancelled: %w", err)
 	}
 
 	p.state = &types.ZarfState{
@@ -61,11 +62,11 @@ func (p *Packager) mirrorComponent(ctx context.Context, component v1alpha1.ZarfC
 	// All components now require a name
 	message.HeaderInfof("📦 %s COMPONENT", strings.ToUpper(component.Name))
 
-	hasImages := len(component.Images) > 0
+	hasImages := len(component.ImagesAndArtifacts()) > 0
 	hasRepos := len(component.Repos) > 0
 
 	if hasImages {
-		if err := p.pushImagesToRegistry(ctx, component.Images, p.cfg.MirrorOpts.NoImgChecksum); err != nil {
+		if err := p.pushImagesToRegistry(ctx, component.ImagesAndArtifacts(), p.cfg.MirrorOpts.NoImgChecksum); err != nil {
 			return fmt.Errorf("unable to push images to the registry: %w", err)
 		}
 	}
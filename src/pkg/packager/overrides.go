@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// resourceVariables maps the keys of the resource overrides of an init component to the suffixes of its package variables.
+var resourceVariables = map[string]string{
+	"requests.cpu":    "CPU_REQ",
+	"requests.memory": "MEM_REQ",
+	"limits.cpu":      "CPU_LIMIT",
+	"limits.memory":   "MEM_LIMIT",
+}
+
+// initOverrideVariables returns the package variables of the init package that set the resource and scheduling
+// overrides of the registry, git server and agent.
+func initOverrideVariables(opts types.ZarfInitOptions) (map[string]string, error) {
+	variables := map[string]string{}
+	for prefix, overrides := range map[string]types.InitComponentOverrides{
+		"REGISTRY":   opts.RegistryOverrides,
+		"GIT_SERVER": opts.GitServerOverrides,
+		"AGENT":      opts.AgentOverrides,
+	} {
+		if err := addVariables(prefix, overrides, variables); err != nil {
+			return nil, err
+		}
+	}
+	return variables, nil
+}
+
+// addVariables adds the package variables for the overrides of the component with the given variable prefix.
+func addVariables(prefix string, overrides types.InitComponentOverrides, variables map[string]string) error {
+	name := strings.ToLower(strings.ReplaceAll(prefix, "_", " "))
+	for key, value := range overrides.Resources {
+		suffix, ok := resourceVariables[key]
+		if !ok {
+			return fmt.Errorf("invalid resource %s for %s, expected requests.cpu, requests.memory, limits.cpu or limits.memory", key, name)
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("invalid %s %s for %s: %w", key, value, name, err)
+		}
+		variables[prefix+"_"+suffix] = value
+	}
+	if overrides.PVCSize != "" {
+		if _, err := resource.ParseQuantity(overrides.PVCSize); err != nil {
+			return fmt.Errorf("invalid PVC size %s for %s: %w", overrides.PVCSize, name, err)
+		}
+		variables[prefix+"_PVC_SIZE"] = overrides.PVCSize
+	}
+	if len(overrides.NodeSelector) > 0 {
+		b, err := yaml.Marshal(overrides.NodeSelector)
+		if err != nil {
+			return err
+		}
+		variables[prefix+"_NODE_SELECTOR"] = strings.TrimSpace(string(b))
+	}
+	if len(overrides.Tolerations) > 0 {
+		tolerations := []corev1.Toleration{}
+		for _, t := range overrides.Tolerations {
+			toleration, err := parseToleration(t)
+			if err != nil {
+				return fmt.Errorf("invalid toleration for %s: %w", name, err)
+			}
+			tolerations = append(tolerations, toleration)
+		}
+		b, err := yaml.Marshal(tolerations)
+		if err != nil {
+			return err
+		}
+		variables[prefix+"_TOLERATIONS"] = strings.TrimSpace(string(b))
+	}
+	return nil
+}
+
+// parseToleration parses a toleration in the format KEY[=VALUE][:EFFECT], which tolerates any value of the key if no
+// value is given and any effect if no effect is given.
+func parseToleration(s string) (corev1.Toleration, error) {
+	toleration := corev1.Toleration{Operator: corev1.TolerationOpExists}
+	keyValue, effect, hasEffect := strings.Cut(s, ":")
+	if hasEffect {
+		switch corev1.TaintEffect(effect) {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+			toleration.Effect = corev1.TaintEffect(effect)
+		default:
+			return corev1.Toleration{}, fmt.Errorf("%s has an unknown effect %s, expected NoSchedule, PreferNoSchedule or NoExecute", s, effect)
+		}
+	}
+	key, value, hasValue := strings.Cut(keyValue, "=")
+	if key == "" {
+		return corev1.Toleration{}, fmt.Errorf("%s has no key, expected KEY[=VALUE][:EFFECT]", s)
+	}
+	toleration.Key = key
+	if hasValue {
+		toleration.Operator = corev1.TolerationOpEqual
+		toleration.Value = value
+	}
+	return toleration, nil
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestInitOverrideVariables(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     types.ZarfInitOptions
+		expected map[string]string
+		wantErr  string
+	}{
+		{
+			name:     "no overrides",
+			expected: map[string]string{},
+		},
+		{
+			name: "overrides of each component",
+			opts: types.ZarfInitOptions{
+				RegistryOverrides: types.InitComponentOverrides{
+					Resources:    map[string]string{"requests.cpu": "500m", "limits.memory": "4Gi"},
+					PVCSize:      "100Gi",
+					NodeSelector: map[string]string{"node-role.kubernetes.io/infra": "true", "kubernetes.io/os": "linux"},
+				},
+				GitServerOverrides: types.InitComponentOverrides{
+					Tolerations: []string{"dedicated=infra:NoSchedule", "node-role.kubernetes.io/control-plane"},
+				},
+				AgentOverrides: types.InitComponentOverrides{
+					Resources: map[string]string{"requests.memory": "64Mi", "limits.cpu": "1"},
+				},
+			},
+			expected: map[string]string{
+				"REGISTRY_CPU_REQ":       "500m",
+				"REGISTRY_MEM_LIMIT":     "4Gi",
+				"REGISTRY_PVC_SIZE":      "100Gi",
+				"REGISTRY_NODE_SELECTOR": "kubernetes.io/os: linux\nnode-role.kubernetes.io/infra: \"true\"",
+				"GIT_SERVER_TOLERATIONS": "- effect: NoSchedule\n  key: dedicated\n  operator: Equal\n  value: infra\n- key: node-role.kubernetes.io/control-plane\n  operator: Exists",
+				"AGENT_MEM_REQ":          "64Mi",
+				"AGENT_CPU_LIMIT":        "1",
+			},
+		},
+		{
+			name: "unknown resource",
+			opts: types.ZarfInitOptions{
+				AgentOverrides: types.InitComponentOverrides{Resources: map[string]string{"requests.gpu": "1"}},
+			},
+			wantErr: "invalid resource requests.gpu for agent",
+		},
+		{
+			name: "invalid quantity",
+			opts: types.ZarfInitOptions{
+				RegistryOverrides: types.InitComponentOverrides{PVCSize: "lots"},
+			},
+			wantErr: "invalid PVC size lots for registry",
+		},
+		{
+			name: "invalid toleration",
+			opts: types.ZarfInitOptions{
+				GitServerOverrides: types.InitComponentOverrides{Tolerations: []string{"dedicated=infra:Never"}},
+			},
+			wantErr: "invalid toleration for git server",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			variables, err := initOverrideVariables(tt.opts)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, variables)
+		})
+	}
+}
+
+func TestParseToleration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		toleration string
+		expected   corev1.Toleration
+		wantErr    bool
+	}{
+		{
+			toleration: "dedicated",
+			expected:   corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists},
+		},
+		{
+			toleration: "dedicated=infra",
+			expected:   corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "infra"},
+		},
+		{
+			toleration: "dedicated:NoExecute",
+			expected:   corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+		},
+		{
+			toleration: "=infra:NoSchedule",
+			wantErr:    true,
+		},
+		{
+			toleration: "dedicated:Sometimes",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.toleration, func(t *testing.T) {
+			t.Parallel()
+
+			toleration, err := parseToleration(tt.toleration)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, toleration)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+// runPreflightChecks checks that the connected cluster meets the needs of the package before any component is deployed,
+// printing the results and returning an error if a check failed. The checks are only run once per deployment.
+func (p *Packager) runPreflightChecks(ctx context.Context) error {
+	if p.preflightChecked {
+		return nil
+	}
+	p.preflightChecked = true
+
+	spinner := message.NewProgressSpinner("Running preflight checks against the cluster")
+	defer spinner.Stop()
+	results := p.cluster.RunPreflightChecks(ctx, p.preflightOptions(ctx))
+	if p.cfg.Pkg.HasImages() {
+		results = append(results, p.architectureResult(ctx))
+	}
+	spinner.Success()
+
+	header := []string{"Check", "Status", "Details"}
+	data := [][]string{}
+	for _, result := range results {
+		data = append(data, []string{result.Check, string(result.Status), result.Message})
+	}
+	message.Table(header, data)
+	return cluster.PreflightError(results)
+}
+
+// preflightOptions returns what the package needs from the cluster it is deployed to.
+func (p *Packager) preflightOptions(ctx context.Context) cluster.PreflightOptions {
+	opts := cluster.PreflightOptions{}
+	if p.cfg.Pkg.IsInitConfig() {
+		opts.Namespaces = append(opts.Namespaces, cluster.ZarfNamespaceName)
+		// The registry and git server of the init package store their data in persistent volumes
+		opts.RequiresStorage = true
+		opts.StorageClass = p.cfg.InitOpts.StorageClass
+	} else if state, err := p.cluster.LoadZarfState(ctx); err == nil && state.StorageClass != "" {
+		opts.RequiresStorage = true
+		opts.StorageClass = state.StorageClass
+	}
+
+	for _, component := range p.cfg.Pkg.Components {
+		for _, chart := range component.Charts {
+			opts.Namespaces = append(opts.Namespaces, chart.Namespace)
+		}
+		for _, manifest := range component.Manifests {
+			opts.Namespaces = append(opts.Namespaces, manifest.Namespace)
+		}
+		if component.Name == "zarf-seed-registry" && !p.hasExternalRegistry(ctx) {
+			images, err := seedImages(p.cfg.Pkg, component.Images, p.registryType(ctx))
+			if err != nil {
+				message.Debugf("Unable to determine the images the injector needs: %s", err.Error())
+				continue
+			}
+			storage, err := cluster.InjectorStorage(p.layout.Images.Base, images)
+			if err != nil {
+				message.Debugf("Unable to determine the storage the injector needs: %s", err.Error())
+				continue
+			}
+			opts.InjectorStorage = storage
+		}
+	}
+	opts.Namespaces = slices.DeleteFunc(opts.Namespaces, func(namespace string) bool { return namespace == "" })
+	slices.Sort(opts.Namespaces)
+	opts.Namespaces = slices.Compact(opts.Namespaces)
+	return opts
+}
+
+// architectureResult returns the result of checking that the package architecture matches the nodes of the cluster.
+func (p *Packager) architectureResult(ctx context.Context) cluster.PreflightResult {
+	result := cluster.PreflightResult{Check: cluster.PreflightArchitecture}
+	err := p.validatePackageArchitecture(ctx)
+	switch {
+	case errors.Is(err, lang.ErrUnableToCheckArch):
+		result.Status, result.Message = cluster.PreflightWarn, err.Error()
+	case err != nil:
+		result.Status, result.Message = cluster.PreflightFail, err.Error()
+	default:
+		result.Status, result.Message = cluster.PreflightPass, "the nodes match the package architecture"
+	}
+	return result
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
 	"github.com/zarf-dev/zarf/src/internal/packager/images"
@@ -31,6 +32,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/creator"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -236,14 +238,8 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 				if err := p.variableConfig.ReplaceTextTemplate(f); err != nil {
 					return nil, err
 				}
-				// Read the contents of each file
-				contents, err := os.ReadFile(f)
-				if err != nil {
-					return nil, fmt.Errorf("could not read the file %s: %w", f, err)
-				}
-
-				// Break the manifest into separate resources
-				yamls, err := utils.SplitYAML(contents)
+				// Stream each file into separate resources
+				yamls, err := utils.SplitYAMLFile(f)
 				if err != nil {
 					return nil, err
 				}
@@ -285,7 +281,12 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 		if len(sortedExpectedImages) > 0 {
 			var validImages []string
 			for _, image := range sortedExpectedImages {
-				if descriptor, err := crane.Head(image, images.WithGlobalInsecureFlag()...); err != nil {
+				mirrored, err := transform.ImageMirrorHost(config.CommonOptions.RegistryMirrors, image)
+				if err != nil {
+					message.Debugf("Suspected image does not appear to be valid: %#v", err)
+					continue
+				}
+				if descriptor, err := crane.Head(mirrored, images.WithGlobalInsecureFlag()...); err != nil {
 					// Test if this is a real image, if not just quiet log to debug, this is normal
 					message.Debugf("Suspected image does not appear to be valid: %#v", err)
 				} else {
@@ -315,7 +316,7 @@ func (p *Packager) findImages(ctx context.Context) (map[string][]string, error)
 
 				for idx, image := range imagesMap[component.Name] {
 					spinner.Updatef("Looking up cosign artifacts for discovered images (%d/%d)", idx+1, len(imagesMap[component.Name]))
-					cosignArtifacts, err := utils.GetCosignArtifacts(image)
+					cosignArtifacts, err := utils.GetCosignArtifacts(image, config.CommonOptions.RegistryMirrors)
 					if err != nil {
 						return nil, fmt.Errorf("could not lookup the cosing artifacts for image %s: %w", image, err)
 					}
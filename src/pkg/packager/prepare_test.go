@@ -86,7 +86,7 @@ func TestFindImages(t *testing.T) {
 					BaseDir: "./testdata/find-images/invalid-manifest-yaml",
 				},
 			},
-			expectedErr: "failed to unmarshal manifest: error converting YAML to JSON: yaml: line 12: could not find expected ':'",
+			expectedErr: "failed to unmarshal manifest: line 11, column 1: yaml: could not find expected ':'",
 		},
 	}
 	for _, tt := range tests {
@@ -27,7 +27,7 @@ import (
 // Publish publishes the package to a registry
 func (p *Packager) Publish(ctx context.Context) (err error) {
 	_, isOCISource := p.source.(*sources.OCISource)
-	if isOCISource && p.cfg.PublishOpts.SigningKeyPath == "" {
+	if isOCISource && p.cfg.PublishOpts.SigningKeyPath == "" && !p.cfg.PublishOpts.Keyless.Enabled {
 		// oci --> oci is a special case, where we will use oci.CopyPackage so that we can transfer the package
 		// w/o layers touching the filesystem
 		srcRemote := p.source.(*sources.OCISource).Remote
@@ -44,7 +44,7 @@ func (p *Packager) Publish(ctx context.Context) (err error) {
 			return err
 		}
 
-		return zoci.CopyPackage(ctx, srcRemote, dstRemote, config.CommonOptions.OCIConcurrency)
+		return zoci.CopyPackage(ctx, srcRemote, dstRemote, p.commonOpts.OCIConcurrency)
 	}
 
 	if p.cfg.CreateOpts.IsSkeleton {
@@ -67,6 +67,10 @@ func (p *Packager) Publish(ctx context.Context) (err error) {
 			return err
 		}
 
+		if err := p.layout.AddDocs(p.cfg.Pkg.Docs); err != nil {
+			return err
+		}
+
 		if err := sc.Output(ctx, p.layout, &p.cfg.Pkg); err != nil {
 			return err
 		}
@@ -77,8 +81,8 @@ func (p *Packager) Publish(ctx context.Context) (err error) {
 			return fmt.Errorf("unable to load the package: %w", err)
 		}
 
-		// Sign the package if a key has been provided
-		if err := p.layout.SignPackage(p.cfg.PublishOpts.SigningKeyPath, p.cfg.PublishOpts.SigningKeyPassword, !config.CommonOptions.Confirm); err != nil {
+		// Sign the package if a key has been provided or keyless signing was requested
+		if err := p.layout.SignPackage(p.cfg.PublishOpts.SigningKeyPath, p.cfg.PublishOpts.SigningKeyPassword, p.cfg.PublishOpts.Keyless, !p.commonOpts.Confirm); err != nil {
 			return err
 		}
 	}
@@ -88,22 +92,31 @@ func (p *Packager) Publish(ctx context.Context) (err error) {
 	if err != nil {
 		return err
 	}
-	var platform ocispec.Platform
-	if p.cfg.CreateOpts.IsSkeleton {
-		platform = zoci.PlatformForSkeleton()
-	} else {
-		platform = oci.PlatformForArch(p.cfg.Pkg.Build.Architecture)
-	}
-	remote, err := zoci.NewRemote(ref, platform)
-	if err != nil {
-		return err
+	var platforms []ocispec.Platform
+	switch {
+	case p.cfg.CreateOpts.IsSkeleton:
+		platforms = []ocispec.Platform{zoci.PlatformForSkeleton()}
+	case p.cfg.Pkg.IsMultiArch():
+		// Multi-architecture packages are published for each of their architectures so they can be pulled from any of them
+		for _, arch := range p.cfg.Pkg.Metadata.Architectures {
+			platforms = append(platforms, oci.PlatformForArch(arch))
+		}
+	default:
+		platforms = []ocispec.Platform{oci.PlatformForArch(p.cfg.Pkg.Build.Architecture)}
 	}
 
 	message.HeaderInfof("📦 PACKAGE PUBLISH %s:%s", p.cfg.Pkg.Metadata.Name, ref)
 
 	// Publish the package/skeleton to the registry
-	if err := remote.PublishPackage(ctx, &p.cfg.Pkg, p.layout, config.CommonOptions.OCIConcurrency); err != nil {
-		return err
+	var remote *zoci.Remote
+	for _, platform := range platforms {
+		remote, err = zoci.NewRemote(ref, platform)
+		if err != nil {
+			return err
+		}
+		if err := remote.PublishPackage(ctx, &p.cfg.Pkg, p.layout, p.commonOpts.OCIConcurrency); err != nil {
+			return err
+		}
 	}
 	if p.cfg.CreateOpts.IsSkeleton {
 		message.Title("How to import components from this skeleton:", "")
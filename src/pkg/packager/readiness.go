@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package packager contains functions for interacting with, managing and deploying Zarf packages.
+package packager
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+)
+
+const (
+	// readinessProbeInterval is the time between the attempts of a readiness probe.
+	readinessProbeInterval = 2 * time.Second
+	// readinessProbeAttemptTimeout limits how long a single attempt of a readiness probe may take.
+	readinessProbeAttemptTimeout = 10 * time.Second
+)
+
+// runReadinessProbes runs the readiness probes of a component in order, waiting for each of them to pass.
+func (p *Packager) runReadinessProbes(ctx context.Context, component v1alpha1.ZarfComponent) error {
+	timeout, err := p.timeout(component, "")
+	if err != nil {
+		return err
+	}
+	for _, probe := range component.Readiness {
+		if err := p.runReadinessProbe(ctx, probe, timeout); err != nil {
+			return fmt.Errorf("readiness probe %s failed: %w", probe.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *Packager) runReadinessProbe(ctx context.Context, probe v1alpha1.ZarfComponentReadiness, timeout time.Duration) error {
+	spinner := message.NewProgressSpinner("Waiting for readiness probe %s", probe.Name)
+	defer spinner.Stop()
+
+	address := probe.Address
+	if p.variableConfig != nil {
+		address = p.variableConfig.ReplaceTemplates(address)
+	}
+	target, err := url.Parse(fmt.Sprintf("%s://%s", strings.ToLower(probe.Protocol), address))
+	if err != nil {
+		return err
+	}
+	serverName := target.Hostname()
+
+	// Services within the cluster are reached through a tunnel, the same way zarf connect reaches them.
+	if get, matchErr := helpers.MatchRegex(localClusterServiceRegex, target.Hostname()); matchErr == nil {
+		if p.cluster == nil {
+			return errors.New("unable to reach a service in the cluster without a cluster connection")
+		}
+		port, err := readinessProbePort(target)
+		if err != nil {
+			return err
+		}
+		tunnel, err := p.cluster.NewTunnel(get("namespace"), cluster.SvcResource, get("name"), "", 0, port)
+		if err != nil {
+			return err
+		}
+		if _, err := tunnel.Connect(ctx); err != nil {
+			return err
+		}
+		defer tunnel.Close()
+		target.Host = tunnel.Endpoint()
+	}
+
+	if probe.MaxTotalSeconds > 0 {
+		timeout = time.Duration(probe.MaxTotalSeconds) * time.Second
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := waitForReadiness(probeCtx, probe, target, serverName); err != nil {
+		return err
+	}
+	spinner.Successf("Readiness probe %s passed", probe.Name)
+	return nil
+}
+
+// readinessProbePort returns the port of the target of a probe, defaulting to the standard port of http and https.
+func readinessProbePort(target *url.URL) (int, error) {
+	switch {
+	case target.Port() != "":
+		return strconv.Atoi(target.Port())
+	case target.Scheme == "http":
+		return 80, nil
+	case target.Scheme == "https":
+		return 443, nil
+	default:
+		return 0, fmt.Errorf("address %s must include a port", target.Host)
+	}
+}
+
+// waitForReadiness attempts the probe against the target until it passes, the probe runs out of retries or the
+// context is done. Certificates of https targets are verified against the given server name.
+func waitForReadiness(ctx context.Context, probe v1alpha1.ZarfComponentReadiness, target *url.URL, serverName string) error {
+	expectedCode := probe.Code
+	if expectedCode == 0 {
+		expectedCode = http.StatusOK
+	}
+	client := &http.Client{
+		Timeout: readinessProbeAttemptTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				ServerName:         serverName,
+				InsecureSkipVerify: config.CommonOptions.InsecureSkipTLSVerify, //nolint:gosec // only with --insecure-skip-tls-verify
+				MinVersion:         tls.VersionTLS12,
+			},
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	attempt := func() error {
+		if target.Scheme == "tcp" {
+			dialer := net.Dialer{Timeout: readinessProbeAttemptTimeout}
+			conn, err := dialer.DialContext(ctx, "tcp", target.Host)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expectedCode {
+			return fmt.Errorf("expected status code %d but got %d", expectedCode, resp.StatusCode)
+		}
+		return nil
+	}
+	return retry.Do(attempt,
+		retry.Context(ctx),
+		retry.Attempts(uint(probe.MaxRetries)),
+		retry.Delay(readinessProbeInterval),
+		retry.DelayType(retry.FixedDelay),
+		retry.LastErrorOnly(true),
+	)
+}
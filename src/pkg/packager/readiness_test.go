@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestWaitForReadiness(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddr := closed.Addr().String()
+	require.NoError(t, closed.Close())
+
+	tests := []struct {
+		name        string
+		probe       v1alpha1.ZarfComponentReadiness
+		target      *url.URL
+		expectedErr string
+	}{
+		{
+			name:   "http with expected code",
+			probe:  v1alpha1.ZarfComponentReadiness{Name: "api", Code: http.StatusAccepted, MaxRetries: 1},
+			target: serverURL,
+		},
+		{
+			name:        "http with unexpected code",
+			probe:       v1alpha1.ZarfComponentReadiness{Name: "api", MaxRetries: 1},
+			target:      serverURL,
+			expectedErr: "expected status code 200 but got 202",
+		},
+		{
+			name:   "tcp",
+			probe:  v1alpha1.ZarfComponentReadiness{Name: "db", MaxRetries: 1},
+			target: &url.URL{Scheme: "tcp", Host: listener.Addr().String()},
+		},
+		{
+			name:        "tcp refused",
+			probe:       v1alpha1.ZarfComponentReadiness{Name: "db", MaxRetries: 1},
+			target:      &url.URL{Scheme: "tcp", Host: closedAddr},
+			expectedErr: "connection refused",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			err := waitForReadiness(ctx, tt.probe, tt.target, tt.target.Hostname())
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestReadinessProbePort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		address     string
+		expected    int
+		expectedErr string
+	}{
+		{address: "http://podinfo.podinfo.svc.cluster.local", expected: 80},
+		{address: "https://podinfo.podinfo.svc.cluster.local", expected: 443},
+		{address: "https://podinfo.podinfo.svc.cluster.local:9898", expected: 9898},
+		{address: "tcp://postgres.db.svc.cluster.local", expectedErr: "must include a port"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.address, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := url.Parse(tt.address)
+			require.NoError(t, err)
+			port, err := readinessProbePort(target)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, port)
+		})
+	}
+}
@@ -9,10 +9,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"runtime"
 	"slices"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -20,12 +23,16 @@ import (
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/gitea"
 	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/internal/packager/images"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/packager/sources"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -35,12 +42,9 @@ func (p *Packager) Remove(ctx context.Context) error {
 	if isClusterSource {
 		p.cluster = p.source.(*sources.ClusterSource).Cluster
 	}
-	spinner := message.NewProgressSpinner("Removing Zarf package %s", p.cfg.PkgOpts.PackageSource)
-	defer spinner.Stop()
-
 	// we do not want to allow removal of signed packages without a signature if there are remove actions
 	// as this is arbitrary code execution from an untrusted source
-	pkg, _, err := p.source.LoadPackageMetadata(ctx, p.layout, false, false)
+	pkg, _, err := p.source.LoadPackageMetadata(ctx, p.layout, false, false, false)
 	if err != nil {
 		return err
 	}
@@ -92,18 +96,64 @@ func (p *Packager) Remove(ctx context.Context) error {
 		}
 	}
 
+	if err := interactive.NewConfirmer(p.commonOpts.Confirm).Confirm(p.removePlan(*deployedPackage, componentsToRemove), "Remove this Zarf package?"); err != nil {
+		return fmt.Errorf("removal cancelled: %w", err)
+	}
+
+	spinner := message.NewProgressSpinner("Removing Zarf package %s", p.cfg.PkgOpts.PackageSource)
+	defer spinner.Stop()
+
+	removeComponents := func() error {
+		removedImages, removedRepos := []string{}, []string{}
+		for _, dc := range helpers.Reverse(deployedPackage.DeployedComponents) {
+			// Only remove the component if it was requested or if we are removing the whole package
+			if !slices.Contains(componentsToRemove, dc.Name) {
+				continue
+			}
+
+			if deployedPackage, err = p.removeComponent(ctx, deployedPackage, dc, spinner); err != nil {
+				return fmt.Errorf("unable to remove the component '%s': %w", dc.Name, err)
+			}
+			removedImages = append(removedImages, dc.PushedImages...)
+			removedRepos = append(removedRepos, dc.PushedRepos...)
+		}
+		if p.cfg.RemoveOpts.PruneArtifacts && p.cluster != nil {
+			spinner.Updatef("Pruning the images and repos of package %s", packageName)
+			if err := p.pruneArtifacts(ctx, removedImages, removedRepos); err != nil {
+				return fmt.Errorf("unable to prune the images and repos of the package: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// The package actions only run when the whole package is being removed
+	if p.cfg.PkgOpts.OptionalComponents != "" {
+		return removeComponents()
+	}
+	return actions.RunSet(ctx, p.cfg.Pkg.Actions.OnRemove, nil, removeComponents)
+}
+
+// removePlan describes the removal of the given components of a deployed package for confirmation.
+func (p *Packager) removePlan(deployedPackage types.DeployedPackage, componentsToRemove []string) interactive.Plan {
+	plan := interactive.Plan{
+		Operation:  config.ZarfRemoveStage,
+		Kind:       string(deployedPackage.Data.Kind),
+		Package:    deployedPackage.Name,
+		Version:    deployedPackage.Data.Metadata.Version,
+		Source:     p.cfg.PkgOpts.PackageSource,
+		Components: []interactive.PlanComponent{},
+	}
 	for _, dc := range helpers.Reverse(deployedPackage.DeployedComponents) {
-		// Only remove the component if it was requested or if we are removing the whole package
 		if !slices.Contains(componentsToRemove, dc.Name) {
 			continue
 		}
-
-		if deployedPackage, err = p.removeComponent(ctx, deployedPackage, dc, spinner); err != nil {
-			return fmt.Errorf("unable to remove the component '%s': %w", dc.Name, err)
+		pc := interactive.PlanComponent{Name: dc.Name}
+		for _, chart := range helpers.Reverse(dc.InstalledCharts) {
+			pc.Charts = append(pc.Charts, interactive.PlanResource{Name: chart.ChartName, Namespace: chart.Namespace})
 		}
+		plan.Components = append(plan.Components, pc)
 	}
-
-	return nil
+	return plan
 }
 
 func (p *Packager) updatePackageSecret(ctx context.Context, deployedPackage types.DeployedPackage) error {
@@ -218,6 +268,13 @@ func (p *Packager) removeComponent(ctx context.Context, deployedPackage *types.D
 		return deployedPackage, fmt.Errorf("unable to run the success action: %w", err)
 	}
 
+	if p.cluster != nil {
+		msg := fmt.Sprintf("Component %s of package %s was removed", c.Name, deployedPackage.Name)
+		if err := p.cluster.RecordPackageEvent(ctx, deployedPackage.Name, corev1.EventTypeNormal, "ComponentRemoved", msg); err != nil {
+			message.Debugf("Unable to record an event for component %s: %s", c.Name, err.Error())
+		}
+	}
+
 	// Remove the component we just removed from the array
 	deployedPackage.DeployedComponents = helpers.RemoveMatches(deployedPackage.DeployedComponents, func(t types.DeployedComponent) bool {
 		return t.Name == c.Name
@@ -247,3 +304,178 @@ func (p *Packager) removeComponent(ctx context.Context, deployedPackage *types.D
 
 	return deployedPackage, nil
 }
+
+// pruneArtifacts deletes the images and repos that were pushed by removed components from the internal registry and
+// git server, unless they are still used by a deployed package.
+func (p *Packager) pruneArtifacts(ctx context.Context, removedImages, removedRepos []string) error {
+	state, err := p.cluster.LoadZarfState(ctx)
+	if err != nil {
+		return err
+	}
+	deployedPackages, err := p.cluster.GetDeployedZarfPackages(ctx)
+	if err != nil {
+		return err
+	}
+	usedImages, usedRepos, err := usedArtifacts(deployedPackages)
+	if err != nil {
+		return err
+	}
+	removedImages = slices.DeleteFunc(helpers.Unique(removedImages), func(ref string) bool {
+		return slices.Contains(usedImages, ref)
+	})
+	removedRepos = slices.DeleteFunc(helpers.Unique(removedRepos), func(name string) bool {
+		return slices.Contains(usedRepos, name)
+	})
+
+	if len(removedImages) > 0 {
+		if !state.RegistryInfo.IsInternal() {
+			message.Warnf("Not pruning %d images as Zarf is using an external registry", len(removedImages))
+		} else if err := p.pruneImages(ctx, state.RegistryInfo, removedImages, usedImages); err != nil {
+			return err
+		}
+	}
+	if len(removedRepos) > 0 {
+		if !state.GitServer.IsInternal() {
+			message.Warnf("Not pruning %d repos as Zarf is using an external git server", len(removedRepos))
+		} else if err := p.pruneRepos(ctx, state.GitServer, removedRepos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usedArtifacts returns the images and repos in the registry and git server that the deployed packages use.
+//
+// Packages deployed before the pushed images and repos were recorded are matched by the images and repos of their
+// deployed components.
+func usedArtifacts(deployedPackages []types.DeployedPackage) ([]string, []string, error) {
+	usedImages, usedRepos := []string{}, []string{}
+	for _, deployedPackage := range deployedPackages {
+		for _, dc := range deployedPackage.DeployedComponents {
+			usedImages = append(usedImages, dc.PushedImages...)
+			usedRepos = append(usedRepos, dc.PushedRepos...)
+
+			component := helpers.Find(deployedPackage.Data.Components, func(c v1alpha1.ZarfComponent) bool {
+				return c.Name == dc.Name
+			})
+			imageList := []transform.Image{}
+			for _, src := range component.ImagesAndArtifacts() {
+				refInfo, err := transform.ParseImageRef(src)
+				if err != nil {
+					return nil, nil, err
+				}
+				imageList = append(imageList, refInfo)
+			}
+			refs, err := registryReferences(imageList, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			usedImages = append(usedImages, refs...)
+			for _, repoURL := range component.Repos {
+				repoName, err := transform.GitURLtoRepoName(repoURL)
+				if err != nil {
+					return nil, nil, err
+				}
+				usedRepos = append(usedRepos, repoName)
+			}
+		}
+	}
+	return helpers.Unique(usedImages), helpers.Unique(usedRepos), nil
+}
+
+// pruneImages deletes the images from the internal registry through a tunnel.
+func (p *Packager) pruneImages(ctx context.Context, registryInfo types.RegistryInfo, removedImages, usedImages []string) error {
+	registryEndpoint, tunnel, err := p.cluster.ConnectToZarfRegistryEndpoint(ctx, registryInfo)
+	if err != nil {
+		return err
+	}
+	if tunnel == nil {
+		return deleteRegistryImages(registryEndpoint, registryInfo, removedImages, usedImages)
+	}
+	defer tunnel.Close()
+	return tunnel.Wrap(func() error {
+		return deleteRegistryImages(registryEndpoint, registryInfo, removedImages, usedImages)
+	})
+}
+
+// deleteRegistryImages deletes the manifests of the removed images that no used image shares.
+//
+// The registry can only delete manifests by digest, which also removes every other tag of the manifest, so manifests
+// that are tagged by a used image are kept.
+func deleteRegistryImages(registryEndpoint string, registryInfo types.RegistryInfo, removedImages, usedImages []string) error {
+	authOption := images.WithPushAuth(registryInfo)
+	digest := func(ref string) (string, bool, error) {
+		d, err := crane.Digest(fmt.Sprintf("%s/%s", registryEndpoint, ref), authOption)
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+		return d, true, nil
+	}
+
+	usedDigests := map[string]bool{}
+	for _, ref := range usedImages {
+		d, ok, err := digest(ref)
+		if err != nil {
+			return err
+		}
+		if ok {
+			usedDigests[d] = true
+		}
+	}
+	toDelete := []string{}
+	for _, ref := range removedImages {
+		d, ok, err := digest(ref)
+		if err != nil {
+			return err
+		}
+		if !ok || usedDigests[d] {
+			continue
+		}
+		refInfo, err := transform.ParseImageRef(fmt.Sprintf("%s/%s", registryEndpoint, ref))
+		if err != nil {
+			return err
+		}
+		toDelete = append(toDelete, fmt.Sprintf("%s@%s", refInfo.Name, d))
+	}
+	for _, digestRef := range helpers.Unique(toDelete) {
+		message.Debugf("Deleting image %s", digestRef)
+		if err := crane.Delete(digestRef, authOption); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneRepos deletes the repos from the internal git server through a tunnel.
+func (p *Packager) pruneRepos(ctx context.Context, gitServer types.GitServerInfo, removedRepos []string) error {
+	namespace, name, port, err := serviceInfoFromServiceURL(gitServer.Address)
+	if err != nil {
+		return err
+	}
+	tunnel, err := p.cluster.NewTunnel(namespace, cluster.SvcResource, name, "", 0, port)
+	if err != nil {
+		return err
+	}
+	_, err = tunnel.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer tunnel.Close()
+	giteaClient, err := gitea.NewClient(tunnel.HTTPEndpoint(), gitServer.PushUsername, gitServer.PushPassword)
+	if err != nil {
+		return err
+	}
+	return tunnel.Wrap(func() error {
+		for _, repoName := range removedRepos {
+			message.Debugf("Deleting repo %s", repoName)
+			if err := giteaClient.DeleteRepository(ctx, repoName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
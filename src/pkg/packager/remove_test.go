@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestUsedArtifacts(t *testing.T) {
+	t.Parallel()
+
+	deployedPackages := []types.DeployedPackage{
+		{
+			Name: "recorded",
+			DeployedComponents: []types.DeployedComponent{
+				{
+					Name:         "podinfo",
+					PushedImages: []string{"stefanprodan/podinfo:6.4.0-zarf-2985051089", "stefanprodan/podinfo:6.4.0"},
+					PushedRepos:  []string{"podinfo-1646971829"},
+				},
+			},
+		},
+		{
+			Name: "unrecorded",
+			Data: v1alpha1.ZarfPackage{
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:   "nginx",
+						Images: []string{"nginx:1.25"},
+						Repos:  []string{"https://github.com/zarf-dev/zarf.git"},
+					},
+					{
+						Name:   "not-deployed",
+						Images: []string{"busybox:1.36"},
+					},
+				},
+			},
+			DeployedComponents: []types.DeployedComponent{{Name: "nginx"}},
+		},
+	}
+	usedImages, usedRepos, err := usedArtifacts(deployedPackages)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"stefanprodan/podinfo:6.4.0-zarf-2985051089",
+		"stefanprodan/podinfo:6.4.0",
+		"library/nginx:1.25-zarf-3793515731",
+		"library/nginx:1.25",
+	}, usedImages)
+	require.ElementsMatch(t, []string{"podinfo-1646971829", "zarf-4156197301"}, usedRepos)
+}
+
+func TestDeleteRegistryImages(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+	registryEndpoint := strings.TrimPrefix(srv.URL, "http://")
+
+	push := func(refs ...string) string {
+		t.Helper()
+		img, err := random.Image(16, 1)
+		require.NoError(t, err)
+		for _, ref := range refs {
+			require.NoError(t, crane.Push(img, fmt.Sprintf("%s/%s", registryEndpoint, ref)))
+		}
+		digest, err := img.Digest()
+		require.NoError(t, err)
+		return digest.String()
+	}
+	exists := func(ref string) bool {
+		t.Helper()
+		_, err := crane.Digest(fmt.Sprintf("%s/%s", registryEndpoint, ref))
+		return err == nil
+	}
+
+	podinfo := push("stefanprodan/podinfo:6.4.0-zarf-2985051089", "stefanprodan/podinfo:6.4.0")
+	// The same image is used by another package under a different tag
+	nginx := push("library/nginx:1.25", "library/nginx:latest")
+
+	removedImages := []string{
+		"stefanprodan/podinfo:6.4.0-zarf-2985051089",
+		"stefanprodan/podinfo:6.4.0",
+		"library/nginx:latest",
+		"library/missing:1.0.0",
+	}
+	err := deleteRegistryImages(registryEndpoint, types.RegistryInfo{}, removedImages, []string{"library/nginx:1.25"})
+	require.NoError(t, err)
+	// Manifests are deleted by digest, which removes all of their tags
+	require.False(t, exists("stefanprodan/podinfo@"+podinfo))
+	require.True(t, exists("library/nginx@"+nginx))
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// deployRollback is the state of the cluster before a deploy, recorded to roll the deploy back if it fails.
+type deployRollback struct {
+	// The deployed package before the deploy, nil if the package was not deployed
+	deployedPackage *types.DeployedPackage
+	// The releases of the components that were started, in the order they were started
+	releases []releaseRevision
+}
+
+// releaseRevision is the revision of a helm release before a deploy.
+type releaseRevision struct {
+	namespace string
+	name      string
+	// The revision of the release, 0 if the release was not installed
+	revision int
+}
+
+// componentReleases returns the helm releases that deploying a component installs or upgrades.
+func componentReleases(packageName string, component v1alpha1.ZarfComponent) []releaseRevision {
+	releases := []releaseRevision{}
+	for _, chart := range component.Charts {
+		name := chart.ReleaseName
+		if name == "" {
+			name = chart.Name
+		}
+		releases = append(releases, releaseRevision{namespace: chart.Namespace, name: name})
+	}
+	manifests := slices.Clone(component.Manifests)
+	for _, policy := range component.Policies {
+		manifests = append(manifests, v1alpha1.ZarfManifest{Name: fmt.Sprintf("policy-%s", policy.Name), Namespace: policy.Namespace})
+	}
+	for _, manifest := range manifests {
+		namespace := manifest.Namespace
+		if namespace == "" {
+			namespace = corev1.NamespaceDefault
+		}
+		releases = append(releases, releaseRevision{namespace: namespace, name: helm.ManifestReleaseName(packageName, component.Name, manifest.Name)})
+	}
+	return releases
+}
+
+// recordRollbackPoint records the revisions of the helm releases of a component before it is deployed, along with the
+// deployed package the first time it is called.
+//
+// Init packages are not rolled back, as the components after the registry and git server depend on them.
+func (p *Packager) recordRollbackPoint(ctx context.Context, component v1alpha1.ZarfComponent) error {
+	if !p.cfg.DeployOpts.RollbackOnFailure || p.cfg.Pkg.IsInitConfig() || !p.isConnectedToCluster() {
+		return nil
+	}
+	if p.rollback == nil {
+		deployedPackage, err := p.cluster.GetDeployedPackage(ctx, p.cfg.Pkg.Metadata.Name)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("unable to record the deployed package for rollback: %w", err)
+		}
+		p.rollback = &deployRollback{deployedPackage: deployedPackage}
+	}
+	for _, rel := range componentReleases(p.cfg.Pkg.Metadata.Name, component) {
+		revision, err := p.cluster.ReleaseRevision(rel.namespace, rel.name)
+		if err != nil {
+			return fmt.Errorf("unable to record the revision of helm release %s for rollback: %w", rel.name, err)
+		}
+		rel.revision = revision
+		p.rollback.releases = append(p.rollback.releases, rel)
+	}
+	return nil
+}
+
+// rollbackDeploy rolls the helm releases of the started components back to their revisions before the deploy, in the
+// reverse order the components were started, and restores the deployed package.
+//
+// Releases that were not installed before the deploy are uninstalled.
+func (p *Packager) rollbackDeploy(ctx context.Context) error {
+	if p.rollback == nil {
+		return nil
+	}
+
+	spinner := message.NewProgressSpinner("Rolling back the deployment of package %s", p.cfg.Pkg.Metadata.Name)
+	defer spinner.Stop()
+
+	helmCfg := helm.NewClusterOnly(p.cfg, p.variableConfig, p.state, p.cluster)
+	var errs []error
+	for i := len(p.rollback.releases) - 1; i >= 0; i-- {
+		rel := p.rollback.releases[i]
+		revision, err := p.cluster.ReleaseRevision(rel.namespace, rel.name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		switch {
+		case revision == rel.revision:
+			continue
+		case rel.revision == 0:
+			spinner.Updatef("Uninstalling helm release %s", rel.name)
+			err = helmCfg.RemoveChart(rel.namespace, rel.name, spinner)
+		default:
+			spinner.Updatef("Rolling back helm release %s to revision %d", rel.name, rel.revision)
+			err = helmCfg.RollbackChart(rel.namespace, rel.name, rel.revision, spinner)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to roll back helm release %s: %w", rel.name, err))
+		}
+	}
+	if err := p.cluster.RestoreDeployedPackage(ctx, p.cfg.Pkg.Metadata.Name, p.rollback.deployedPackage); err != nil {
+		errs = append(errs, fmt.Errorf("unable to restore the deployed package: %w", err))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	spinner.Successf("Rolled back the deployment of package %s", p.cfg.Pkg.Metadata.Name)
+	return nil
+}
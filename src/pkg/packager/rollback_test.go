@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/helm"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestRecordRollbackPoint(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	c := &cluster.Cluster{
+		Clientset: fake.NewSimpleClientset(),
+	}
+	for _, rel := range []*release.Release{
+		{Name: "podinfo", Namespace: "podinfo", Version: 1, Info: &release.Info{Status: release.StatusSuperseded}},
+		{Name: "podinfo", Namespace: "podinfo", Version: 2, Info: &release.Info{Status: release.StatusDeployed}},
+	} {
+		secrets := driver.NewSecrets(c.Clientset.CoreV1().Secrets(rel.Namespace))
+		err := secrets.Create(fmt.Sprintf("sh.helm.release.v1.%s.v%d", rel.Name, rel.Version), rel)
+		require.NoError(t, err)
+	}
+
+	component := v1alpha1.ZarfComponent{
+		Name: "podinfo",
+		Charts: []v1alpha1.ZarfChart{
+			{Name: "podinfo", Namespace: "podinfo"},
+			{Name: "redis", ReleaseName: "cache", Namespace: "podinfo"},
+		},
+		Manifests: []v1alpha1.ZarfManifest{
+			{Name: "config"},
+		},
+		Policies: []v1alpha1.ZarfPolicy{
+			{Name: "require-labels", Namespace: "kyverno"},
+		},
+	}
+	p := &Packager{
+		cfg: &types.PackagerConfig{
+			Pkg: v1alpha1.ZarfPackage{
+				Metadata:   v1alpha1.ZarfMetadata{Name: "test"},
+				Components: []v1alpha1.ZarfComponent{component},
+			},
+			DeployOpts: types.ZarfDeployOptions{RollbackOnFailure: true},
+		},
+		cluster: c,
+	}
+	err := p.recordRollbackPoint(ctx, component)
+	require.NoError(t, err)
+	expected := &deployRollback{
+		releases: []releaseRevision{
+			{namespace: "podinfo", name: "podinfo", revision: 2},
+			{namespace: "podinfo", name: "cache"},
+			{namespace: "default", name: helm.ManifestReleaseName("test", "podinfo", "config")},
+			{namespace: "kyverno", name: helm.ManifestReleaseName("test", "podinfo", "policy-require-labels")},
+		},
+	}
+	require.Equal(t, expected, p.rollback)
+
+	// Nothing is recorded for init packages.
+	p.rollback = nil
+	p.cfg.Pkg.Kind = v1alpha1.ZarfInitConfig
+	err = p.recordRollbackPoint(ctx, component)
+	require.NoError(t, err)
+	require.Nil(t, p.rollback)
+}
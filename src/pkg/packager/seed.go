@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// Package constants of the init package that name the zot registry image.
+const (
+	zotImageConstant    = "REGISTRY_ZOT_IMAGE"
+	zotImageTagConstant = "REGISTRY_ZOT_IMAGE_TAG"
+)
+
+// registryType returns the implementation of the internal registry of the cluster.
+//
+// The registry in the Zarf state takes precedence over the init flags, as changes to the registry are ignored on a
+// re-init.
+func (p *Packager) registryType(ctx context.Context) types.RegistryType {
+	registryType := p.cfg.InitOpts.RegistryInfo.Type
+	if p.state != nil {
+		registryType = p.state.RegistryInfo.Type
+	} else if p.isConnectedToCluster() {
+		if state, err := p.cluster.LoadZarfState(ctx); err == nil {
+			registryType = state.RegistryInfo.Type
+		}
+	}
+	if registryType == "" {
+		return types.RegistryTypeDistribution
+	}
+	return registryType
+}
+
+// seedImages returns the images of the seed registry component that the injector bootstraps into the cluster.
+//
+// The init package carries the images of every registry implementation, so only the image of the zot registry is
+// injected when it was chosen and every other image otherwise.
+func seedImages(pkg v1alpha1.ZarfPackage, images []string, registryType types.RegistryType) ([]string, error) {
+	var zotImage, zotImageTag string
+	for _, constant := range pkg.Constants {
+		switch constant.Name {
+		case zotImageConstant:
+			zotImage = constant.Value
+		case zotImageTagConstant:
+			zotImageTag = constant.Value
+		}
+	}
+
+	seed := []string{}
+	for _, image := range images {
+		ref, err := transform.ParseImageRef(image)
+		if err != nil {
+			return nil, err
+		}
+		isZot := zotImage != "" && ref.Path == zotImage && ref.Tag == zotImageTag
+		if isZot == (registryType == types.RegistryTypeZot) {
+			seed = append(seed, image)
+		}
+	}
+	if len(seed) == 0 {
+		return nil, fmt.Errorf("the init package does not include an image for the %s registry", registryType)
+	}
+	return seed, nil
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package packager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestSeedImages(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Constants: []v1alpha1.Constant{
+			{Name: "REGISTRY_IMAGE", Value: "library/registry"},
+			{Name: "REGISTRY_IMAGE_TAG", Value: "2.8.3"},
+			{Name: zotImageConstant, Value: "project-zot/zot-minimal"},
+			{Name: zotImageTagConstant, Value: "v2.1.0"},
+		},
+	}
+	images := []string{"library/registry:2.8.3", "ghcr.io/project-zot/zot-minimal:v2.1.0"}
+
+	tests := []struct {
+		name         string
+		pkg          v1alpha1.ZarfPackage
+		registryType types.RegistryType
+		expected     []string
+		expectedErr  string
+	}{
+		{
+			name:         "distribution",
+			pkg:          pkg,
+			registryType: types.RegistryTypeDistribution,
+			expected:     []string{"library/registry:2.8.3"},
+		},
+		{
+			name:         "zot",
+			pkg:          pkg,
+			registryType: types.RegistryTypeZot,
+			expected:     []string{"ghcr.io/project-zot/zot-minimal:v2.1.0"},
+		},
+		{
+			name:         "init package without zot",
+			registryType: types.RegistryTypeZot,
+			expectedErr:  "the init package does not include an image for the zot registry",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			seed, err := seedImages(tt.pkg, images, tt.registryType)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, seed)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var (
+	// verify that AzureBlobSource implements PackageSource
+	_ PackageSource = (*AzureBlobSource)(nil)
+)
+
+// AzureBlobSource is a package source for azblob://CONTAINER/BLOB URLs of blobs in Azure Blob Storage.
+//
+// The storage account is read from AZURE_STORAGE_ACCOUNT. Requests are authenticated with the connection string in
+// AZURE_STORAGE_CONNECTION_STRING if it is set, and otherwise with the default Azure credential chain, which includes
+// AKS workload identity and managed identities.
+type AzureBlobSource struct {
+	*types.ZarfPackageOptions
+}
+
+// azureBlob is a blob in Azure Blob Storage, read at the ETag that was current when its properties were read.
+type azureBlob struct {
+	client *blob.Client
+	etag   *azcore.ETag
+}
+
+func (b *azureBlob) attrs(ctx context.Context) (int64, string, error) {
+	props, err := b.client.GetProperties(ctx, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	if props.ContentLength == nil || props.ETag == nil {
+		return 0, "", errors.New("the blob properties do not include its length and ETag")
+	}
+	b.etag = props.ETag
+	return *props.ContentLength, string(*props.ETag), nil
+}
+
+func (b *azureBlob) newReader(ctx context.Context, offset int64) (io.ReadCloser, error) {
+	resp, err := b.client.DownloadStream(ctx, &blob.DownloadStreamOptions{
+		Range: blob.HTTPRange{Offset: offset},
+		AccessConditions: &blob.AccessConditions{
+			ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfMatch: b.etag},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// newAzureBlobClient returns a client for the storage account configured in the environment.
+func newAzureBlobClient() (*azblob.Client, error) {
+	if connectionString := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); connectionString != "" {
+		return azblob.NewClientFromConnectionString(connectionString, nil)
+	}
+	account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	if account == "" {
+		return nil, errors.New("AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_CONNECTION_STRING must be set to read from Azure Blob Storage")
+	}
+	domain := os.Getenv("AZURE_STORAGE_DOMAIN")
+	if domain == "" {
+		domain = "blob.core.windows.net"
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return azblob.NewClient(fmt.Sprintf("https://%s.%s/", account, domain), cred, nil)
+}
+
+// Collect downloads a package from Azure Blob Storage.
+func (s *AzureBlobSource) Collect(ctx context.Context, dir string) (string, error) {
+	container, name, err := parseBucketURL(s.PackageSource)
+	if err != nil {
+		return "", err
+	}
+	client, err := newAzureBlobClient()
+	if err != nil {
+		return "", fmt.Errorf("unable to create an Azure Blob Storage client: %w", err)
+	}
+
+	obj := &azureBlob{client: client.ServiceClient().NewContainerClient(container).NewBlobClient(name)}
+	return collectRemoteObject(ctx, obj, s.ZarfPackageOptions, dir)
+}
+
+// LoadPackage loads a package from Azure Blob Storage.
+func (s *AzureBlobSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	return loadCollectedPackage(ctx, s, s.ZarfPackageOptions, dst, filter, unarchiveAll)
+}
+
+// LoadPackageMetadata loads a package's metadata from Azure Blob Storage.
+func (s *AzureBlobSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	return loadCollectedPackageMetadata(ctx, s, s.ZarfPackageOptions, dst, wantSBOM, wantDocs, skipValidation)
+}
@@ -60,7 +60,7 @@ func (s *ClusterSource) Collect(_ context.Context, _ string) (string, error) {
 }
 
 // LoadPackageMetadata loads package metadata from a cluster.
-func (s *ClusterSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, _ bool, _ bool) (v1alpha1.ZarfPackage, []string, error) {
+func (s *ClusterSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, _ bool, _ bool, _ bool) (v1alpha1.ZarfPackage, []string, error) {
 	dpkg, err := s.GetDeployedPackage(ctx, s.PackageSource)
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, nil, err
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"cloud.google.com/go/storage"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var (
+	// verify that GCSSource implements PackageSource
+	_ PackageSource = (*GCSSource)(nil)
+)
+
+// GCSSource is a package source for gs:// URLs of objects in Google Cloud Storage.
+//
+// Requests are authenticated with Application Default Credentials, which include GKE workload identity.
+type GCSSource struct {
+	*types.ZarfPackageOptions
+}
+
+// gcsObject is an object in Google Cloud Storage, read at the generation that was current when its attributes were read.
+type gcsObject struct {
+	handle *storage.ObjectHandle
+}
+
+func (o *gcsObject) attrs(ctx context.Context) (int64, string, error) {
+	attrs, err := o.handle.Attrs(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	o.handle = o.handle.Generation(attrs.Generation)
+	return attrs.Size, strconv.FormatInt(attrs.Generation, 10), nil
+}
+
+func (o *gcsObject) newReader(ctx context.Context, offset int64) (io.ReadCloser, error) {
+	return o.handle.NewRangeReader(ctx, offset, -1)
+}
+
+// Collect downloads a package from Google Cloud Storage.
+func (s *GCSSource) Collect(ctx context.Context, dir string) (string, error) {
+	bucket, name, err := parseBucketURL(s.PackageSource)
+	if err != nil {
+		return "", err
+	}
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to create a Google Cloud Storage client: %w", err)
+	}
+	defer client.Close()
+
+	obj := &gcsObject{handle: client.Bucket(bucket).Object(name)}
+	return collectRemoteObject(ctx, obj, s.ZarfPackageOptions, dir)
+}
+
+// LoadPackage loads a package from Google Cloud Storage.
+func (s *GCSSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	return loadCollectedPackage(ctx, s, s.ZarfPackageOptions, dst, filter, unarchiveAll)
+}
+
+// LoadPackageMetadata loads a package's metadata from Google Cloud Storage.
+func (s *GCSSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	return loadCollectedPackageMetadata(ctx, s, s.ZarfPackageOptions, dst, wantSBOM, wantDocs, skipValidation)
+}
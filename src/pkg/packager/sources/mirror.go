@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var (
+	// verify that MirroredSource implements PackageSource
+	_ PackageSource = (*MirroredSource)(nil)
+)
+
+// MirroredSource is a package source that falls back to mirrors of the package, in order, when the package can not be
+// loaded from its source.
+//
+// Every mirror is verified against the same shasum as the source, which is the digest of the package manifest for OCI
+// packages and the checksum of the tarball for https packages.
+type MirroredSource struct {
+	sources   []PackageSource
+	locations []string
+}
+
+// mirrorKind returns the kind of package source that can mirror the given source, as the shasum of a package only
+// identifies the same package in sources of the same kind.
+func mirrorKind(pkgSrc string) string {
+	switch Identify(pkgSrc) {
+	case "oci":
+		return "oci"
+	case "http", "https":
+		return "https"
+	default:
+		return ""
+	}
+}
+
+// newMirroredSource returns a source that loads the package from the primary source or the mirrors in the options.
+func newMirroredSource(primary PackageSource, pkgOpts *types.ZarfPackageOptions) (*MirroredSource, error) {
+	kind := mirrorKind(pkgOpts.PackageSource)
+	if kind == "" {
+		return nil, fmt.Errorf("source mirrors are only supported for https and oci packages, not %q", pkgOpts.PackageSource)
+	}
+	if pkgOpts.Shasum == "" {
+		return nil, errors.New("a shasum is required to use source mirrors, so that every mirror is verified to serve the same package")
+	}
+
+	s := &MirroredSource{
+		sources:   []PackageSource{primary},
+		locations: []string{pkgOpts.PackageSource},
+	}
+	for _, mirror := range pkgOpts.SourceMirrors {
+		if mirrorKind(mirror) != kind {
+			return nil, fmt.Errorf("source mirror %q must be an %s package like its source %q", mirror, kind, pkgOpts.PackageSource)
+		}
+		mirrorOpts := *pkgOpts
+		mirrorOpts.PackageSource = mirror
+		mirrorOpts.SourceMirrors = nil
+		src, err := New(&mirrorOpts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source mirror %q: %w", mirror, err)
+		}
+		s.sources = append(s.sources, src)
+		s.locations = append(s.locations, mirror)
+	}
+	return s, nil
+}
+
+// try runs load against the source and then each mirror until it succeeds.
+func (s *MirroredSource) try(ctx context.Context, load func(src PackageSource) error) error {
+	errs := []error{}
+	for i, src := range s.sources {
+		err := load(src)
+		if err == nil {
+			if i > 0 {
+				message.Infof("Loaded the package from the source mirror %s", s.locations[i])
+			}
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", s.locations[i], err))
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(s.sources)-1 {
+			message.Warnf("Unable to load the package from %s, trying the source mirror %s: %s", s.locations[i], s.locations[i+1], err.Error())
+		}
+	}
+	return fmt.Errorf("unable to load the package from its source or any of its mirrors: %w", errors.Join(errs...))
+}
+
+// LoadPackage loads a package from the first of the source and its mirrors that serves it.
+func (s *MirroredSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	err = s.try(ctx, func(src PackageSource) error {
+		var err error
+		pkg, warnings, err = src.LoadPackage(ctx, dst, filter, unarchiveAll)
+		return err
+	})
+	return pkg, warnings, err
+}
+
+// LoadPackageMetadata loads a package's metadata from the first of the source and its mirrors that serves it.
+func (s *MirroredSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	err = s.try(ctx, func(src PackageSource) error {
+		var err error
+		pkg, warnings, err = src.LoadPackageMetadata(ctx, dst, wantSBOM, wantDocs, skipValidation)
+		return err
+	})
+	return pkg, warnings, err
+}
+
+// Collect collects a package from the first of the source and its mirrors that serves it.
+func (s *MirroredSource) Collect(ctx context.Context, dir string) (tarball string, err error) {
+	err = s.try(ctx, func(src PackageSource) error {
+		var err error
+		tarball, err = src.Collect(ctx, dir)
+		return err
+	})
+	return tarball, err
+}
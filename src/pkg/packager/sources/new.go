@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -34,12 +35,15 @@ type PackageSource interface {
 	LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error)
 
 	// LoadPackageMetadata loads a package's metadata from a source.
-	LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error)
+	LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error)
 
 	// Collect relocates a package from its source to a tarball in a given destination directory.
 	Collect(ctx context.Context, destinationDirectory string) (tarball string, err error)
 }
 
+// splitPartRegex matches the suffix of any file of a split package.
+var splitPartRegex = regexp.MustCompile(`\.part\d{3}$`)
+
 // Identify returns the type of package source based on the provided package source string.
 func Identify(pkgSrc string) string {
 	if helpers.IsURL(pkgSrc) {
@@ -47,7 +51,7 @@ func Identify(pkgSrc string) string {
 		return parsed.Scheme
 	}
 
-	if strings.Contains(pkgSrc, ".part000") {
+	if strings.Contains(pkgSrc, ".part000") || splitPartRegex.MatchString(pkgSrc) {
 		return "split"
 	}
 
@@ -66,6 +70,10 @@ func New(pkgOpts *types.ZarfPackageOptions) (PackageSource, error) {
 
 	switch Identify(pkgSrc) {
 	case "oci":
+		// OCI packages are not pulled as an archive, their layers are verified against the digest of the manifest instead.
+		if pkgOpts.ArchiveChecksum != "" || pkgOpts.ArchiveSignature != "" {
+			return nil, fmt.Errorf("an archive checksum or signature can not be used with the OCI package %q, verify it with --shasum or --key instead", pkgSrc)
+		}
 		if pkgOpts.Shasum != "" {
 			pkgSrc = fmt.Sprintf("%s@sha256:%s", pkgSrc, pkgOpts.Shasum)
 		}
@@ -76,14 +84,41 @@ func New(pkgOpts *types.ZarfPackageOptions) (PackageSource, error) {
 		}
 		source = &OCISource{ZarfPackageOptions: pkgOpts, Remote: remote}
 	case "tarball":
+		// A package that was split at create can be referenced by the name it would have had if it was not split.
+		if helpers.InvalidPath(pkgSrc) && !helpers.InvalidPath(pkgSrc+".part000") {
+			pkgOpts.PackageSource = pkgSrc + ".part000"
+			source = &SplitTarballSource{pkgOpts}
+			break
+		}
 		source = &TarballSource{pkgOpts}
 	case "http", "https", "sget":
+		// Any part of a split package can be referenced, the parts are always collected from the first file.
+		if parsed, err := url.Parse(pkgSrc); err == nil && splitPartRegex.MatchString(parsed.Path) {
+			parsed.Path = splitPartRegex.ReplaceAllString(parsed.Path, ".part000")
+			pkgOpts.PackageSource = parsed.String()
+		}
 		source = &URLSource{pkgOpts}
+	case "gs":
+		source = &GCSSource{pkgOpts}
+	case "azblob":
+		source = &AzureBlobSource{pkgOpts}
+	case "sftp":
+		source = &SFTPSource{pkgOpts}
 	case "split":
+		// Any part of a split package can be referenced, the parts are always collected from the first file.
+		pkgOpts.PackageSource = splitPartRegex.ReplaceAllString(pkgSrc, ".part000")
 		source = &SplitTarballSource{pkgOpts}
 	default:
+		if resolver, ok := resolverFor(Identify(pkgSrc)); ok {
+			source = &ResolverSource{ZarfPackageOptions: pkgOpts, Resolver: resolver}
+			break
+		}
 		return nil, fmt.Errorf("could not identify source type for %q", pkgSrc)
 	}
 
+	if len(pkgOpts.SourceMirrors) > 0 {
+		return newMirroredSource(source, pkgOpts)
+	}
+
 	return source, nil
 }
@@ -6,6 +6,7 @@ package sources
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,13 +14,18 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
 )
 
@@ -62,6 +68,24 @@ func TestNewPackageSource(t *testing.T) {
 			expectedIdentify: "http",
 			expectedType:     &URLSource{},
 		},
+		{
+			name:             "gcs",
+			src:              "gs://airgap-staging/packages/zarf-init-amd64-v1.0.0.tar.zst",
+			expectedIdentify: "gs",
+			expectedType:     &GCSSource{},
+		},
+		{
+			name:             "azure blob",
+			src:              "azblob://airgap-staging/packages/zarf-init-amd64-v1.0.0.tar.zst",
+			expectedIdentify: "azblob",
+			expectedType:     &AzureBlobSource{},
+		},
+		{
+			name:             "sftp",
+			src:              "sftp://zarf@files.example.com/~/packages/zarf-init-amd64-v1.0.0.tar.zst",
+			expectedIdentify: "sftp",
+			expectedType:     &SFTPSource{},
+		},
 		{
 			name:             "local tar init zst",
 			src:              "zarf-init-amd64-v1.0.0.tar.zst",
@@ -86,6 +110,12 @@ func TestNewPackageSource(t *testing.T) {
 			expectedIdentify: "split",
 			expectedType:     &SplitTarballSource{},
 		},
+		{
+			name:             "local tar split later part",
+			src:              "zarf-package-manifests-amd64-v1.0.0.tar.zst.part002",
+			expectedIdentify: "split",
+			expectedType:     &SplitTarballSource{},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -116,6 +146,17 @@ func TestPackageSource(t *testing.T) {
 	src.Close()
 	dst.Close()
 
+	// Encrypt separate copies for local and remote loading since Collect moves local packages.
+	passphrase := "correct horse battery staple"
+	encryptedPath := filepath.Join(testDir, "local", tarName)
+	servedDir := filepath.Join(testDir, "served")
+	for _, dir := range []string{filepath.Dir(encryptedPath), servedDir} {
+		require.NoError(t, os.MkdirAll(dir, 0o700))
+		require.NoError(t, utils.EncryptFile(tarPath, filepath.Join(dir, tarName), []byte(passphrase)))
+	}
+	encryptedShasum, err := helpers.GetSHA256OfFile(filepath.Join(servedDir, tarName))
+	require.NoError(t, err)
+
 	b, err := os.ReadFile("./testdata/expected-pkg.json")
 	require.NoError(t, err)
 	expectedPkg := v1alpha1.ZarfPackage{}
@@ -123,8 +164,17 @@ func TestPackageSource(t *testing.T) {
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		_, fp := filepath.Split(req.URL.Path)
-		f, err := os.Open(filepath.Join("testdata", fp))
+		dir, fp := filepath.Split(req.URL.Path)
+		if dir == "/missing/" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if dir == "/encrypted/" {
+			dir = servedDir
+		} else {
+			dir = "testdata"
+		}
+		f, err := os.Open(filepath.Join(dir, fp))
 		if err != nil {
 			rw.WriteHeader(http.StatusNotFound)
 			return
@@ -138,7 +188,9 @@ func TestPackageSource(t *testing.T) {
 	tests := []struct {
 		name        string
 		src         string
+		mirrors     []string
 		shasum      string
+		passphrase  string
 		expectedErr string
 	}{
 		{
@@ -152,19 +204,46 @@ func TestPackageSource(t *testing.T) {
 			shasum:      "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26",
 			expectedErr: "",
 		},
+		{
+			name:        "http-mirror",
+			src:         fmt.Sprintf("%s/missing/zarf-package-wordpress-amd64-16.0.4.tar.zst", ts.URL),
+			mirrors:     []string{fmt.Sprintf("%s/zarf-package-wordpress-amd64-16.0.4.tar.zst", ts.URL)},
+			shasum:      "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26",
+			expectedErr: "",
+		},
 		{
 			name:        "http-insecure",
 			src:         fmt.Sprintf("%s/zarf-package-wordpress-amd64-16.0.4.tar.zst", ts.URL),
 			expectedErr: "remote package provided without a shasum, please provide one with --shasum",
 		},
+		{
+			name:        "local-encrypted-without-key",
+			src:         encryptedPath,
+			expectedErr: ErrPkgEncryptedButNoKey.Error(),
+		},
+		{
+			name:        "http-encrypted",
+			src:         fmt.Sprintf("%s/encrypted/%s", ts.URL, tarName),
+			shasum:      encryptedShasum,
+			passphrase:  passphrase,
+			expectedErr: "",
+		},
+		{
+			name:        "local-encrypted",
+			src:         encryptedPath,
+			passphrase:  passphrase,
+			expectedErr: "",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			// TODO once our messaging is thread safe, re-parallelize this test
 			opts := &types.ZarfPackageOptions{
-				PackageSource: tt.src,
-				Shasum:        tt.shasum,
+				PackageSource:        tt.src,
+				SourceMirrors:        tt.mirrors,
+				Shasum:               tt.shasum,
+				DecryptionPassphrase: tt.passphrase,
 			}
 
 			ps, err := New(opts)
@@ -184,7 +263,7 @@ func TestPackageSource(t *testing.T) {
 			require.NoError(t, err)
 			metadataDir := t.TempDir()
 			metadataLayout := layout.New(metadataDir)
-			metadata, warnings, err := ps.LoadPackageMetadata(context.Background(), metadataLayout, true, false)
+			metadata, warnings, err := ps.LoadPackageMetadata(context.Background(), metadataLayout, true, false, false)
 			require.NoError(t, err)
 			require.Empty(t, warnings)
 			require.Equal(t, expectedPkg, metadata)
@@ -198,3 +277,206 @@ func TestPackageSource(t *testing.T) {
 		})
 	}
 }
+
+// writeSplitPackage splits the tarball into parts of chunkSize bytes in dir, leaving out the part at the skip index, and
+// returns the path of the first file of the split package.
+func writeSplitPackage(t *testing.T, tarPath, dir string, chunkSize int, skip int) string {
+	t.Helper()
+
+	b, err := os.ReadFile(tarPath)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	name := filepath.Base(tarPath)
+	pkgData := types.ZarfSplitPackageData{
+		Sha256Sum: fmt.Sprintf("%x", sha256.Sum256(b)),
+		Bytes:     int64(len(b)),
+	}
+	for i := 0; i*chunkSize < len(b); i++ {
+		chunk := b[i*chunkSize : min((i+1)*chunkSize, len(b))]
+		part := types.ZarfSplitPackagePart{
+			Name:      fmt.Sprintf("%s.part%03d", name, i+1),
+			Sha256Sum: fmt.Sprintf("%x", sha256.Sum256(chunk)),
+			Bytes:     int64(len(chunk)),
+		}
+		pkgData.Parts = append(pkgData.Parts, part)
+		pkgData.Count++
+		if i+1 != skip {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, part.Name), chunk, 0o600))
+		}
+	}
+	b, err = json.Marshal(pkgData)
+	require.NoError(t, err)
+	firstPart := filepath.Join(dir, name+".part000")
+	require.NoError(t, os.WriteFile(firstPart, b, 0o600))
+	return firstPart
+}
+
+func TestSplitPackageSource(t *testing.T) {
+	t.Parallel()
+
+	tarName := "zarf-package-wordpress-amd64-16.0.4.tar.zst"
+	tarPath := filepath.Join("testdata", tarName)
+	shasum := "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26"
+	chunkSize := 200
+	testDir := t.TempDir()
+
+	servedDir := filepath.Join(testDir, "served")
+	writeSplitPackage(t, tarPath, servedDir, chunkSize, 0)
+	requests := map[string]int{}
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		requests[filepath.Base(req.URL.Path)]++
+		mu.Unlock()
+		http.ServeFile(rw, req, filepath.Join(servedDir, filepath.Base(req.URL.Path)))
+	}))
+	t.Cleanup(func() { ts.Close() })
+
+	tests := []struct {
+		name        string
+		src         func(t *testing.T) string
+		shasum      string
+		expectedErr string
+	}{
+		{
+			name: "local",
+			src: func(t *testing.T) string {
+				return writeSplitPackage(t, tarPath, filepath.Join(testDir, "local"), chunkSize, 0)
+			},
+		},
+		{
+			name: "local later part",
+			src: func(t *testing.T) string {
+				firstPart := writeSplitPackage(t, tarPath, filepath.Join(testDir, "later"), chunkSize, 0)
+				return strings.Replace(firstPart, ".part000", ".part002", 1)
+			},
+		},
+		{
+			name: "local package name",
+			src: func(t *testing.T) string {
+				firstPart := writeSplitPackage(t, tarPath, filepath.Join(testDir, "name"), chunkSize, 0)
+				return strings.TrimSuffix(firstPart, ".part000")
+			},
+		},
+		{
+			name: "local missing part",
+			src: func(t *testing.T) string {
+				return writeSplitPackage(t, tarPath, filepath.Join(testDir, "missing"), chunkSize, 2)
+			},
+			expectedErr: fmt.Sprintf("package is missing 1 of 4 parts: %s.part002", tarName),
+		},
+		{
+			name: "local corrupt part",
+			src: func(t *testing.T) string {
+				dir := filepath.Join(testDir, "corrupt")
+				firstPart := writeSplitPackage(t, tarPath, dir, chunkSize, 0)
+				require.NoError(t, os.WriteFile(filepath.Join(dir, tarName+".part003"), []byte("corrupt"), 0o600))
+				return firstPart
+			},
+			expectedErr: fmt.Sprintf("package part %s.part003 is corrupt, expected shasum", tarName),
+		},
+		{
+			name: "http",
+			src: func(_ *testing.T) string {
+				return fmt.Sprintf("%s/%s.part000", ts.URL, tarName)
+			},
+			shasum: shasum,
+		},
+		{
+			name: "http resume",
+			src: func(t *testing.T) string {
+				// Leave the earlier parts of an interrupted download in the cache.
+				writeSplitPackage(t, tarPath, filepath.Join(config.GetAbsCachePath(), "split", shasum), chunkSize, 4)
+				return fmt.Sprintf("%s/%s.part000", ts.URL, tarName)
+			},
+			shasum: shasum,
+		},
+		{
+			name: "http shasum mismatch",
+			src: func(_ *testing.T) string {
+				return fmt.Sprintf("%s/%s.part000", ts.URL, tarName)
+			},
+			shasum:      "a" + shasum[1:],
+			expectedErr: "mismatch in CLI options and package metadata",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// TODO once our messaging is thread safe, re-parallelize this test
+			ps, err := New(&types.ZarfPackageOptions{PackageSource: tt.src(t), Shasum: tt.shasum})
+			require.NoError(t, err)
+			collectDir := t.TempDir()
+			fp, err := ps.Collect(context.Background(), collectDir)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(collectDir, tarName), fp)
+			require.NoError(t, helpers.SHAsMatch(fp, shasum))
+		})
+	}
+	require.Equal(t, 1, requests[tarName+".part001"])
+	require.Equal(t, 2, requests[tarName+".part004"])
+}
+
+func TestNewMirroredSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		src         string
+		mirrors     []string
+		shasum      string
+		expectedErr string
+	}{
+		{
+			name:    "oci mirrors",
+			src:     "oci://ghcr.io/zarf-dev/packages/dos-games:1.0.0",
+			mirrors: []string{"oci://registry.example.com/packages/dos-games:1.0.0", "oci://registry.example.org/dos-games:1.0.0"},
+			shasum:  "d9bde5b9ad0a0d3ba4fa1be1bbdb4ae8e5c5fb5e0f7a1ab3e8f45d3eb5d0e1b7",
+		},
+		{
+			name:    "https mirror of an http source",
+			src:     "http://files.example.com/zarf-package-dos-games-amd64-1.0.0.tar.zst",
+			mirrors: []string{"https://mirror.example.com/zarf-package-dos-games-amd64-1.0.0.tar.zst"},
+			shasum:  "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26",
+		},
+		{
+			name:        "without shasum",
+			src:         "oci://ghcr.io/zarf-dev/packages/dos-games:1.0.0",
+			mirrors:     []string{"oci://registry.example.com/packages/dos-games:1.0.0"},
+			expectedErr: "a shasum is required to use source mirrors, so that every mirror is verified to serve the same package",
+		},
+		{
+			name:        "mirror of a different kind",
+			src:         "oci://ghcr.io/zarf-dev/packages/dos-games:1.0.0",
+			mirrors:     []string{"https://mirror.example.com/zarf-package-dos-games-amd64-1.0.0.tar.zst"},
+			shasum:      "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26",
+			expectedErr: `source mirror "https://mirror.example.com/zarf-package-dos-games-amd64-1.0.0.tar.zst" must be an oci package like its source "oci://ghcr.io/zarf-dev/packages/dos-games:1.0.0"`,
+		},
+		{
+			name:        "local source",
+			src:         "zarf-package-dos-games-amd64-1.0.0.tar.zst",
+			mirrors:     []string{"https://mirror.example.com/zarf-package-dos-games-amd64-1.0.0.tar.zst"},
+			shasum:      "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26",
+			expectedErr: `source mirrors are only supported for https and oci packages, not "zarf-package-dos-games-amd64-1.0.0.tar.zst"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ps, err := New(&types.ZarfPackageOptions{PackageSource: tt.src, SourceMirrors: tt.mirrors, Shasum: tt.shasum})
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.IsType(t, &MirroredSource{}, ps)
+			require.Equal(t, append([]string{tt.src}, tt.mirrors...), ps.(*MirroredSource).locations)
+		})
+	}
+}
@@ -80,7 +80,7 @@ func (s *OCISource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, f
 		spinner.Success()
 
 		if !s.SkipSignatureValidation {
-			if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath); err != nil {
+			if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath, s.CertificateIdentity, s.CertificateOIDCIssuer, s.TrustedRootPath); err != nil {
 				return pkg, nil, err
 			}
 		}
@@ -111,11 +111,14 @@ func (s *OCISource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, f
 }
 
 // LoadPackageMetadata loads a package's metadata from an OCI registry.
-func (s *OCISource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+func (s *OCISource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
 	toPull := zoci.PackageAlwaysPull
 	if wantSBOM {
 		toPull = append(toPull, layout.SBOMTar)
 	}
+	if wantDocs {
+		toPull = append(toPull, layout.DocsTar)
+	}
 	layersFetched, err := s.PullPaths(ctx, dst.Base, toPull)
 	if err != nil {
 		return pkg, nil, err
@@ -132,8 +135,8 @@ func (s *OCISource) LoadPackageMetadata(ctx context.Context, dst *layout.Package
 	}
 
 	if !dst.IsLegacyLayout() {
-		if wantSBOM {
-			spinner := message.NewProgressSpinner("Validating SBOM checksums")
+		if wantSBOM || wantDocs {
+			spinner := message.NewProgressSpinner("Validating SBOM and docs checksums")
 			defer spinner.Stop()
 
 			if err := ValidatePackageIntegrity(dst, pkg.Metadata.AggregateChecksum, true); err != nil {
@@ -144,10 +147,13 @@ func (s *OCISource) LoadPackageMetadata(ctx context.Context, dst *layout.Package
 		}
 
 		if !s.SkipSignatureValidation {
-			if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath); err != nil {
-				if errors.Is(err, ErrPkgSigButNoKey) && skipValidation {
+			if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath, s.CertificateIdentity, s.CertificateOIDCIssuer, s.TrustedRootPath); err != nil {
+				switch {
+				case errors.Is(err, ErrPkgSigButNoKey) && skipValidation:
 					message.Warn("The package was signed but no public key was provided, skipping signature validation")
-				} else {
+				case errors.Is(err, ErrPkgKeylessSigButNoIdentity) && skipValidation:
+					message.Warn("The package was signed keyless but no certificate identity was provided, skipping signature validation")
+				default:
 					return pkg, nil, err
 				}
 			}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// remoteDownloadsDir is the directory in the cache that holds partial downloads from URLs, cloud storage and SFTP servers.
+const remoteDownloadsDir = "downloads"
+
+// remoteObject is a package stored in a cloud storage bucket or on an SFTP server.
+type remoteObject interface {
+	// attrs returns the size of the object and a version that changes whenever the object is overwritten.
+	attrs(ctx context.Context) (size int64, version string, err error)
+	// newReader reads the version of the object returned by attrs, starting at the given offset.
+	newReader(ctx context.Context, offset int64) (io.ReadCloser, error)
+}
+
+// parseBucketURL splits a cloud storage URL into its bucket and the name of the object.
+func parseBucketURL(packageURL string) (string, string, error) {
+	parsed, err := url.Parse(packageURL)
+	if err != nil {
+		return "", "", err
+	}
+	name := strings.TrimPrefix(parsed.Path, "/")
+	if parsed.Host == "" || name == "" {
+		return "", "", fmt.Errorf("%q must be of the form %s://BUCKET/OBJECT", packageURL, parsed.Scheme)
+	}
+	return parsed.Host, name, nil
+}
+
+// collectRemoteObject downloads a package from cloud storage or an SFTP server into the given directory. The download
+// is written to the cache until it completes, so that an interrupted download resumes where it stopped as long as the
+// object was not overwritten in the meantime.
+func collectRemoteObject(ctx context.Context, obj remoteObject, pkgOpts *types.ZarfPackageOptions, dir string) (string, error) {
+	if isSplitURL(pkgOpts.PackageSource) {
+		return "", errors.New("split packages can not be collected from cloud storage or SFTP servers, reassemble the package before uploading it")
+	}
+	size, version, err := obj.attrs(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s: %w", pkgOpts.PackageSource, err)
+	}
+
+	downloadsDir := filepath.Join(config.GetAbsCachePath(), remoteDownloadsDir)
+	if err := helpers.CreateDirectory(downloadsDir, helpers.ReadWriteExecuteUser); err != nil {
+		return "", err
+	}
+	partial := filepath.Join(downloadsDir, fmt.Sprintf("%x", sha256.Sum256([]byte(pkgOpts.PackageSource+"\x00"+version))))
+	if err := downloadRemoteObject(ctx, obj, pkgOpts.PackageSource, partial, size); err != nil {
+		return "", err
+	}
+	if pkgOpts.Shasum != "" {
+		if err := helpers.SHAsMatch(partial, pkgOpts.Shasum); err != nil {
+			_ = os.Remove(partial)
+			return "", err
+		}
+	}
+
+	dstTarball := filepath.Join(dir, "zarf-package-bucket-unknown")
+	if err := helpers.CreatePathAndCopy(partial, dstTarball); err != nil {
+		return "", err
+	}
+	if err := os.Remove(partial); err != nil {
+		return "", err
+	}
+	return renameDownloaded(dstTarball, pkgOpts.PackageSource)
+}
+
+// downloadRemoteObject appends the part of the object that is missing from the partial download at path.
+func downloadRemoteObject(ctx context.Context, obj remoteObject, source, path string, size int64) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, helpers.ReadWriteUser)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	offset := fi.Size()
+	if offset > size {
+		offset = 0
+	}
+	if offset == size {
+		return nil
+	}
+	if err := f.Truncate(offset); err != nil {
+		return err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if offset > 0 {
+		message.Infof("Resuming the download of %s at %s of %s", source, utils.ByteFormat(float64(offset), 2), utils.ByteFormat(float64(size), 2))
+	}
+
+	r, err := obj.newReader(ctx, offset)
+	if err != nil {
+		return fmt.Errorf("unable to download %s: %w", source, err)
+	}
+	defer r.Close()
+	progressBar := message.NewProgressBar(size, fmt.Sprintf("Downloading %s", source))
+	progressBar.Add(int(offset))
+	if _, err := io.Copy(io.MultiWriter(f, progressBar), r); err != nil {
+		progressBar.Failf("Unable to download %s", source)
+		return fmt.Errorf("unable to download %s, run the command again to resume the download: %w", source, err)
+	}
+	progressBar.Successf("Downloaded %s", source)
+	return nil
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sources
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// fakeRemoteObject serves an object from memory, failing reads after failAfter bytes when it is set.
+type fakeRemoteObject struct {
+	data      []byte
+	version   string
+	failAfter int
+	offsets   []int64
+}
+
+func (o *fakeRemoteObject) attrs(_ context.Context) (int64, string, error) {
+	return int64(len(o.data)), o.version, nil
+}
+
+func (o *fakeRemoteObject) newReader(_ context.Context, offset int64) (io.ReadCloser, error) {
+	o.offsets = append(o.offsets, offset)
+	data := o.data[offset:]
+	if o.failAfter > 0 {
+		return io.NopCloser(io.MultiReader(bytes.NewReader(data[:o.failAfter]), failingReader{})), nil
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// failingReader is a reader that fails like a dropped connection.
+type failingReader struct{}
+
+func (failingReader) Read(_ []byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func TestParseBucketURL(t *testing.T) {
+	t.Parallel()
+
+	bucket, name, err := parseBucketURL("gs://airgap-staging/packages/zarf-init-amd64-v1.0.0.tar.zst")
+	require.NoError(t, err)
+	require.Equal(t, "airgap-staging", bucket)
+	require.Equal(t, "packages/zarf-init-amd64-v1.0.0.tar.zst", name)
+
+	_, _, err = parseBucketURL("azblob://airgap-staging")
+	require.EqualError(t, err, `"azblob://airgap-staging" must be of the form azblob://BUCKET/OBJECT`)
+}
+
+func TestCollectRemoteObject(t *testing.T) {
+	t.Parallel()
+
+	tarName := "zarf-package-wordpress-amd64-16.0.4.tar.zst"
+	shasum := "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26"
+	data, err := os.ReadFile(filepath.Join("testdata", tarName))
+	require.NoError(t, err)
+
+	partialPath := func(source, version string) string {
+		return filepath.Join(config.GetAbsCachePath(), remoteDownloadsDir, fmt.Sprintf("%x", sha256.Sum256([]byte(source+"\x00"+version))))
+	}
+
+	tests := []struct {
+		name            string
+		partial         []byte
+		partialVersion  string
+		shasum          string
+		expectedOffsets []int64
+		expectedErr     string
+	}{
+		{
+			name:            "download",
+			shasum:          shasum,
+			expectedOffsets: []int64{0},
+		},
+		{
+			name:            "resume",
+			partial:         data[:300],
+			partialVersion:  "1",
+			shasum:          shasum,
+			expectedOffsets: []int64{300},
+		},
+		{
+			name:            "overwritten since the interrupted download",
+			partial:         data[:300],
+			partialVersion:  "0",
+			expectedOffsets: []int64{0},
+		},
+		{
+			name:            "shasum mismatch",
+			shasum:          "a" + shasum[1:],
+			expectedOffsets: []int64{0},
+			expectedErr:     "expected",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			source := fmt.Sprintf("gs://zarf-test/%s/%s", t.Name(), tarName)
+			if tt.partial != nil {
+				partial := partialPath(source, tt.partialVersion)
+				require.NoError(t, helpers.CreateDirectory(filepath.Dir(partial), helpers.ReadWriteExecuteUser))
+				require.NoError(t, os.WriteFile(partial, tt.partial, helpers.ReadWriteUser))
+				t.Cleanup(func() { os.Remove(partial) })
+			}
+
+			obj := &fakeRemoteObject{data: data, version: "1"}
+			dir := t.TempDir()
+			fp, err := collectRemoteObject(context.Background(), obj, &types.ZarfPackageOptions{PackageSource: source, Shasum: tt.shasum}, dir)
+			require.Equal(t, tt.expectedOffsets, obj.offsets)
+			require.NoFileExists(t, partialPath(source, obj.version))
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(dir, tarName), fp)
+			require.NoError(t, helpers.SHAsMatch(fp, shasum))
+		})
+	}
+}
+
+func TestCollectRemoteObjectInterrupted(t *testing.T) {
+	t.Parallel()
+
+	tarName := "zarf-package-wordpress-amd64-16.0.4.tar.zst"
+	data, err := os.ReadFile(filepath.Join("testdata", tarName))
+	require.NoError(t, err)
+	source := fmt.Sprintf("azblob://zarf-test/%s/%s", t.Name(), tarName)
+	pkgOpts := &types.ZarfPackageOptions{PackageSource: source}
+
+	obj := &fakeRemoteObject{data: data, version: `"0x8DC"`, failAfter: 200}
+	_, err = collectRemoteObject(context.Background(), obj, pkgOpts, t.TempDir())
+	require.ErrorContains(t, err, "run the command again to resume the download")
+
+	obj.failAfter = 0
+	fp, err := collectRemoteObject(context.Background(), obj, pkgOpts, t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, []int64{0, 200}, obj.offsets)
+	require.Equal(t, tarName, filepath.Base(fp))
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var (
+	// verify that ResolverSource implements PackageSource
+	_ PackageSource = (*ResolverSource)(nil)
+)
+
+const (
+	// ResolverAPIVersion is the version of the protocol between Zarf and source resolvers.
+	ResolverAPIVersion = "zarf.dev/source-resolver/v1"
+	// ResolverPrefix is the prefix of the name of source resolvers that are found on the PATH, followed by the scheme
+	// they resolve (e.g. zarf-source-artifactory for artifactory:// sources).
+	ResolverPrefix = "zarf-source-"
+)
+
+var (
+	resolversLock sync.RWMutex
+	resolvers     = map[string]string{}
+
+	// resolverSchemeRegex matches URL schemes as defined by RFC 3986.
+	resolverSchemeRegex = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+	// builtinSchemes are the schemes of the package sources built into Zarf, which can not be taken over by resolvers.
+	builtinSchemes = []string{"oci", "http", "https", "sget", "gs", "azblob", "sftp", "file"}
+)
+
+// ResolverRequest is written as JSON to the standard input of a source resolver, which is run with a single resolve
+// argument.
+type ResolverRequest struct {
+	// The version of the protocol, currently zarf.dev/source-resolver/v1
+	APIVersion string `json:"apiVersion"`
+	// The package source as given to Zarf
+	Source string `json:"source"`
+	// The shasum of the package given with --shasum, if any
+	Shasum string `json:"shasum,omitempty"`
+	// The architecture of the package Zarf is looking for
+	Architecture string `json:"architecture"`
+	// A directory the resolver can write the package tarball to
+	Destination string `json:"destination"`
+}
+
+// ResolverResponse is read as JSON from the standard output of a source resolver that exits successfully. Anything the
+// resolver writes to standard error is shown to the user.
+type ResolverResponse struct {
+	// The absolute path of the package tarball
+	Path string `json:"path"`
+	// The SHA256 checksum of the package tarball, which Zarf verifies
+	Shasum string `json:"shasum,omitempty"`
+	// Information about where the package was resolved from, which Zarf logs
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// RegisterResolver registers the executable that resolves package sources with the given URL scheme, taking precedence
+// over any resolver for the scheme on the PATH.
+func RegisterResolver(scheme, command string) error {
+	if !resolverSchemeRegex.MatchString(scheme) {
+		return fmt.Errorf("invalid source resolver scheme %q, it must be a lowercase URL scheme", scheme)
+	}
+	if slices.Contains(builtinSchemes, scheme) {
+		return fmt.Errorf("unable to register a source resolver for %s://, it is built into Zarf", scheme)
+	}
+	if command == "" {
+		return fmt.Errorf("the source resolver for %s:// must have a command", scheme)
+	}
+	resolversLock.Lock()
+	defer resolversLock.Unlock()
+	resolvers[scheme] = command
+	return nil
+}
+
+// resolverFor returns the executable that resolves package sources with the given URL scheme, either registered with
+// RegisterResolver or found on the PATH.
+func resolverFor(scheme string) (string, bool) {
+	if !resolverSchemeRegex.MatchString(scheme) || slices.Contains(builtinSchemes, scheme) {
+		return "", false
+	}
+	resolversLock.RLock()
+	command, ok := resolvers[scheme]
+	resolversLock.RUnlock()
+	if ok {
+		return command, true
+	}
+	path, err := exec.LookPath(ResolverPrefix + scheme)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// ResolverSource is a package source for URL schemes that are resolved to a package tarball by an external executable,
+// integrating artifact systems that Zarf does not support itself.
+type ResolverSource struct {
+	*types.ZarfPackageOptions
+	// The executable that resolves the source
+	Resolver string
+}
+
+// Collect resolves a package with the source resolver and copies it into the given directory.
+func (s *ResolverSource) Collect(ctx context.Context, dir string) (string, error) {
+	req, err := json.Marshal(ResolverRequest{
+		APIVersion:   ResolverAPIVersion,
+		Source:       s.PackageSource,
+		Shasum:       s.Shasum,
+		Architecture: config.GetArch(),
+		Destination:  dir,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.Resolver, "resolve")
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	message.Debugf("Resolving %s with %s", s.PackageSource, s.Resolver)
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("the source resolver %s was unable to resolve %s: %w", s.Resolver, s.PackageSource, err)
+	}
+
+	var resp ResolverResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return "", fmt.Errorf("the source resolver %s returned an invalid response: %w", s.Resolver, err)
+	}
+	if resp.Path == "" || !filepath.IsAbs(resp.Path) {
+		return "", fmt.Errorf("the source resolver %s must return the absolute path of the package, got %q", s.Resolver, resp.Path)
+	}
+	if helpers.InvalidPath(resp.Path) {
+		return "", fmt.Errorf("the source resolver %s returned the package %s which does not exist", s.Resolver, resp.Path)
+	}
+	keys := make([]string, 0, len(resp.Metadata))
+	for k := range resp.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		message.Debugf("Resolved %s with %s=%s", s.PackageSource, k, resp.Metadata[k])
+	}
+
+	if s.Shasum != "" && resp.Shasum != "" && s.Shasum != resp.Shasum {
+		return "", fmt.Errorf("the source resolver %s resolved %s to a package with shasum %s, expected %s", s.Resolver, s.PackageSource, resp.Shasum, s.Shasum)
+	}
+	shasum := s.Shasum
+	if shasum == "" {
+		shasum = resp.Shasum
+	}
+	if shasum != "" {
+		if err := helpers.SHAsMatch(resp.Path, shasum); err != nil {
+			return "", err
+		}
+	}
+
+	// The resolver owns the package it returned unless it wrote it to the destination, so leave it in place.
+	dstTarball := resp.Path
+	if !strings.HasPrefix(resp.Path, filepath.Clean(dir)+string(filepath.Separator)) {
+		dstTarball = filepath.Join(dir, filepath.Base(resp.Path))
+		if err := helpers.CreatePathAndCopy(resp.Path, dstTarball); err != nil {
+			return "", err
+		}
+	}
+	return renameDownloaded(dstTarball, resp.Path)
+}
+
+// LoadPackage loads a package resolved by a source resolver.
+func (s *ResolverSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	return loadCollectedPackage(ctx, s, s.ZarfPackageOptions, dst, filter, unarchiveAll)
+}
+
+// LoadPackageMetadata loads a package's metadata from a package resolved by a source resolver.
+func (s *ResolverSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	return loadCollectedPackageMetadata(ctx, s, s.ZarfPackageOptions, dst, wantSBOM, wantDocs, skipValidation)
+}
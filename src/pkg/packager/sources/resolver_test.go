@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// writeResolver writes a source resolver script that records its request next to itself and runs the given body.
+func writeResolver(t *testing.T, dir, name, body string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	script := fmt.Sprintf("#!/bin/sh\n[ \"$1\" = resolve ] || exit 2\ncat > %s\n%s\n", filepath.Join(dir, name+".request.json"), body)
+	require.NoError(t, os.WriteFile(path, []byte(script), helpers.ReadWriteExecuteUser))
+	return path
+}
+
+func TestRegisterResolver(t *testing.T) {
+	t.Parallel()
+
+	require.EqualError(t, RegisterResolver("oci", "/usr/local/bin/zarf-source-oci"), "unable to register a source resolver for oci://, it is built into Zarf")
+	require.EqualError(t, RegisterResolver("Artifactory", "/usr/local/bin/zarf-source-artifactory"), `invalid source resolver scheme "Artifactory", it must be a lowercase URL scheme`)
+	require.EqualError(t, RegisterResolver("blobstore", ""), "the source resolver for blobstore:// must have a command")
+}
+
+func TestResolverSource(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("source resolver scripts require a POSIX shell")
+	}
+
+	tarName := "zarf-package-wordpress-amd64-16.0.4.tar.zst"
+	shasum := "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26"
+	tarPath, err := filepath.Abs(filepath.Join("testdata", tarName))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		scheme      string
+		body        string
+		shasum      string
+		expectedErr string
+	}{
+		{
+			name:   "package written to the destination",
+			scheme: "blobstore",
+			body: fmt.Sprintf(`dest=$(sed -n 's/.*"destination":"\([^"]*\)".*/\1/p' %s)
+cp %s "$dest/resolved.tar.zst"
+echo "{\"path\":\"$dest/resolved.tar.zst\",\"shasum\":\"%s\",\"metadata\":{\"repository\":\"zarf-local\"}}"`, "$(dirname $0)/blobstore.request.json", tarPath, shasum),
+		},
+		{
+			name:   "package owned by the resolver",
+			scheme: "artifactory",
+			body:   fmt.Sprintf(`echo '{"path":"%s"}'`, tarPath),
+			shasum: shasum,
+		},
+		{
+			name:        "shasum mismatch",
+			scheme:      "nexus",
+			body:        fmt.Sprintf(`echo '{"path":"%s","shasum":"%s"}'`, tarPath, "a"+shasum[1:]),
+			shasum:      shasum,
+			expectedErr: fmt.Sprintf("resolved nexus://packages/wordpress to a package with shasum a%s, expected %s", shasum[1:], shasum),
+		},
+		{
+			name:        "relative path",
+			scheme:      "relative",
+			body:        fmt.Sprintf(`echo '{"path":"testdata/%s"}'`, tarName),
+			expectedErr: fmt.Sprintf(`must return the absolute path of the package, got "testdata/%s"`, tarName),
+		},
+		{
+			name:        "resolver failure",
+			scheme:      "failing",
+			body:        `echo "package not found" >&2; exit 1`,
+			expectedErr: "was unable to resolve failing://packages/wordpress: exit status 1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			resolver := writeResolver(t, dir, tt.scheme, tt.body)
+			require.NoError(t, RegisterResolver(tt.scheme, resolver))
+
+			src := fmt.Sprintf("%s://packages/wordpress", tt.scheme)
+			ps, err := New(&types.ZarfPackageOptions{PackageSource: src, Shasum: tt.shasum})
+			require.NoError(t, err)
+			require.Equal(t, &ResolverSource{ZarfPackageOptions: &types.ZarfPackageOptions{PackageSource: src, Shasum: tt.shasum}, Resolver: resolver}, ps)
+
+			collectDir := t.TempDir()
+			fp, err := ps.Collect(context.Background(), collectDir)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(collectDir, tarName), fp)
+			require.NoError(t, helpers.SHAsMatch(fp, shasum))
+			require.FileExists(t, tarPath)
+
+			b, err := os.ReadFile(filepath.Join(dir, tt.scheme+".request.json"))
+			require.NoError(t, err)
+			var req ResolverRequest
+			require.NoError(t, json.Unmarshal(b, &req))
+			expected := ResolverRequest{
+				APIVersion:   ResolverAPIVersion,
+				Source:       src,
+				Shasum:       tt.shasum,
+				Architecture: config.GetArch(),
+				Destination:  collectDir,
+			}
+			require.Equal(t, expected, req)
+		})
+	}
+}
+
+func TestResolverOnPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("source resolver scripts require a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	resolver := writeResolver(t, dir, ResolverPrefix+"s3compat", `exit 1`)
+	t.Setenv("PATH", dir)
+
+	ps, err := New(&types.ZarfPackageOptions{PackageSource: "s3compat://packages/wordpress"})
+	require.NoError(t, err)
+	require.Equal(t, resolver, ps.(*ResolverSource).Resolver)
+
+	_, err = New(&types.ZarfPackageOptions{PackageSource: "unknown://packages/wordpress"})
+	require.EqualError(t, err, `could not identify source type for "unknown://packages/wordpress"`)
+}
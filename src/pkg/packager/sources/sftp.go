@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package sources contains core implementations of the PackageSource interface.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/skeema/knownhosts"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+var (
+	// verify that SFTPSource implements PackageSource
+	_ PackageSource = (*SFTPSource)(nil)
+)
+
+// sftpDialTimeout limits how long connecting to an SFTP server may take.
+const sftpDialTimeout = 30 * time.Second
+
+// sftpIdentityFiles are the private keys in ~/.ssh that are offered to SFTP servers, in the same order as ssh.
+var sftpIdentityFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// SFTPSource is a package source for sftp://[USER@]HOST[:PORT]/PATH URLs of packages on SFTP servers.
+//
+// Paths are absolute unless they start with /~/, which makes them relative to the home directory of the user. Like
+// ssh, the client authenticates with the keys in the SSH agent and the unencrypted default keys in ~/.ssh, and only
+// connects to servers whose host key is recorded in ~/.ssh/known_hosts or /etc/ssh/ssh_known_hosts.
+type SFTPSource struct {
+	*types.ZarfPackageOptions
+}
+
+// sftpObject is a file on an SFTP server.
+type sftpObject struct {
+	client *sftp.Client
+	path   string
+}
+
+func (o *sftpObject) attrs(_ context.Context) (int64, string, error) {
+	fi, err := o.client.Stat(o.path)
+	if err != nil {
+		return 0, "", err
+	}
+	// SFTP has no object versions, so an overwritten file is recognized by its modification time and size.
+	return fi.Size(), fmt.Sprintf("%d-%d", fi.ModTime().Unix(), fi.Size()), nil
+}
+
+func (o *sftpObject) newReader(_ context.Context, offset int64) (io.ReadCloser, error) {
+	f, err := o.client.Open(o.path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Collect downloads a package from an SFTP server.
+func (s *SFTPSource) Collect(ctx context.Context, dir string) (string, error) {
+	parsed, err := url.Parse(s.PackageSource)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Hostname() == "" || parsed.Path == "" || parsed.Path == "/" {
+		return "", fmt.Errorf("%q must be of the form sftp://[USER@]HOST[:PORT]/PATH", s.PackageSource)
+	}
+	address := parsed.Host
+	if parsed.Port() == "" {
+		address = net.JoinHostPort(parsed.Hostname(), "22")
+	}
+	username := parsed.User.Username()
+	if username == "" {
+		current, err := user.Current()
+		if err != nil {
+			return "", fmt.Errorf("unable to determine the user to connect to %s as: %w", address, err)
+		}
+		username = current.Username
+	}
+	path := parsed.Path
+	if strings.HasPrefix(path, "/~/") {
+		path = strings.TrimPrefix(path, "/~/")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	cfg, err := sshClientConfig(username, address, home)
+	if err != nil {
+		return "", err
+	}
+	sshClient, err := dialSSH(ctx, address, cfg)
+	if err != nil {
+		return "", err
+	}
+	defer sshClient.Close()
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return "", fmt.Errorf("unable to start an SFTP session with %s: %w", address, err)
+	}
+	defer client.Close()
+
+	return collectRemoteObject(ctx, &sftpObject{client: client, path: path}, s.ZarfPackageOptions, dir)
+}
+
+// sshClientConfig returns the configuration to connect to the SSH server at the given address as the given user, with
+// the keys and known hosts that ssh would use for the given home directory.
+func sshClientConfig(username, address, home string) (*ssh.ClientConfig, error) {
+	knownHostsFiles := []string{}
+	for _, path := range []string{filepath.Join(home, ".ssh", "known_hosts"), "/etc/ssh/ssh_known_hosts"} {
+		if _, err := os.Stat(path); err == nil {
+			knownHostsFiles = append(knownHostsFiles, path)
+		}
+	}
+	if len(knownHostsFiles) == 0 {
+		return nil, fmt.Errorf("unable to verify the host key of %s without a known_hosts file, add it to ~/.ssh/known_hosts (e.g. with ssh-keyscan)", address)
+	}
+	kh, err := knownhosts.New(knownHostsFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the known hosts: %w", err)
+	}
+
+	signers := []ssh.Signer{}
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			message.Debugf("Unable to connect to the SSH agent at %s: %s", sock, err)
+		} else {
+			agentSigners, err := agent.NewClient(conn).Signers()
+			if err != nil {
+				message.Debugf("Unable to list the keys of the SSH agent: %s", err)
+			}
+			signers = append(signers, agentSigners...)
+		}
+	}
+	for _, name := range sftpIdentityFiles {
+		b, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(b)
+		if err != nil {
+			// Keys with a passphrase can only be used through the SSH agent.
+			message.Debugf("Unable to use the private key %s: %s", name, err)
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("unable to authenticate with %s without a key, add one to the SSH agent or ~/.ssh", address)
+	}
+
+	return &ssh.ClientConfig{
+		User:              username,
+		Auth:              []ssh.AuthMethod{ssh.PublicKeys(signers...)},
+		HostKeyCallback:   kh.HostKeyCallback(),
+		HostKeyAlgorithms: kh.HostKeyAlgorithms(address),
+		Timeout:           sftpDialTimeout,
+	}, nil
+}
+
+// dialSSH connects to the SSH server at the given address, explaining host key verification failures.
+func dialSSH(ctx context.Context, address string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	dialer := net.Dialer{Timeout: cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %s: %w", address, err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, address, cfg)
+	if err != nil {
+		conn.Close()
+		switch {
+		case knownhosts.IsHostKeyChanged(err):
+			return nil, fmt.Errorf("the host key of %s does not match the key in the known hosts, it may have been replaced or the connection may be intercepted: %w", address, err)
+		case knownhosts.IsHostUnknown(err):
+			return nil, fmt.Errorf("the host key of %s is not in the known hosts, add it to ~/.ssh/known_hosts (e.g. with ssh-keyscan): %w", address, err)
+		default:
+			return nil, fmt.Errorf("unable to connect to %s: %w", address, err)
+		}
+	}
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// LoadPackage loads a package from an SFTP server.
+func (s *SFTPSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	return loadCollectedPackage(ctx, s, s.ZarfPackageOptions, dst, filter, unarchiveAll)
+}
+
+// LoadPackageMetadata loads a package's metadata from an SFTP server.
+func (s *SFTPSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	return loadCollectedPackageMetadata(ctx, s, s.ZarfPackageOptions, dst, wantSBOM, wantDocs, skipValidation)
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sources
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// startSFTPServer serves dir over SFTP on a local port to clients with the given key, returning its address and host key.
+func startSFTPServer(t *testing.T, dir string, clientKey ssh.PublicKey) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientKey.Marshal()) {
+				return nil, fmt.Errorf("unknown public key")
+			}
+			return nil, nil
+		},
+	}
+	cfg.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTP(conn, cfg, dir)
+		}
+	}()
+	return listener.Addr().String(), hostSigner.PublicKey()
+}
+
+func serveSFTP(conn net.Conn, cfg *ssh.ServerConfig, dir string) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				req.Reply(req.Type == "subsystem" && string(req.Payload[4:]) == "sftp", nil)
+			}
+		}()
+		server, err := sftp.NewServer(channel, sftp.WithServerWorkingDirectory(dir))
+		if err != nil {
+			return
+		}
+		server.Serve()
+		server.Close()
+	}
+}
+
+func TestSFTPSourceCollect(t *testing.T) {
+	tarName := "zarf-package-wordpress-amd64-16.0.4.tar.zst"
+	shasum := "835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26"
+	testdata, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	clientPub, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	clientKey, err := ssh.NewPublicKey(clientPub)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(clientPriv, "")
+	require.NoError(t, err)
+	address, hostKey := startSFTPServer(t, testdata, clientKey)
+
+	tests := []struct {
+		name        string
+		path        string
+		knownHost   ssh.PublicKey
+		expectedErr string
+	}{
+		{
+			name:      "absolute path",
+			path:      filepath.Join(testdata, tarName),
+			knownHost: hostKey,
+		},
+		{
+			name:      "path relative to the home directory",
+			path:      "/~/" + tarName,
+			knownHost: hostKey,
+		},
+		{
+			name:        "unknown host",
+			path:        "/~/" + tarName,
+			expectedErr: "is not in the known hosts",
+		},
+		{
+			name:        "changed host key",
+			path:        "/~/" + tarName,
+			knownHost:   clientKey,
+			expectedErr: "does not match the key in the known hosts",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("SSH_AUTH_SOCK", "")
+			sshDir := filepath.Join(home, ".ssh")
+			require.NoError(t, helpers.CreateDirectory(sshDir, helpers.ReadWriteExecuteUser))
+			require.NoError(t, os.WriteFile(filepath.Join(sshDir, "id_ed25519"), pem.EncodeToMemory(block), helpers.ReadWriteUser))
+			knownHosts := ""
+			if tt.knownHost != nil {
+				knownHosts = knownhosts.Line([]string{address}, tt.knownHost) + "\n"
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(sshDir, "known_hosts"), []byte(knownHosts), helpers.ReadWriteUser))
+
+			source := fmt.Sprintf("sftp://zarf@%s%s", address, tt.path)
+			src := &SFTPSource{&types.ZarfPackageOptions{PackageSource: source, Shasum: shasum}}
+			dir := t.TempDir()
+			fp, err := src.Collect(context.Background(), dir)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(dir, tarName), fp)
+			require.NoError(t, helpers.SHAsMatch(fp, shasum))
+		})
+	}
+}
@@ -6,12 +6,13 @@ package sources
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -34,80 +35,115 @@ type SplitTarballSource struct {
 
 // Collect turns a split tarball into a full tarball.
 func (s *SplitTarballSource) Collect(_ context.Context, dir string) (string, error) {
-	pattern := strings.Replace(s.PackageSource, ".part000", ".part*", 1)
-	fileList, err := filepath.Glob(pattern)
+	pkgData, err := readSplitPackageData(s.PackageSource)
 	if err != nil {
-		return "", fmt.Errorf("unable to find split tarball files: %w", err)
+		return "", err
+	}
+	if len(s.Shasum) > 0 && pkgData.Sha256Sum != s.Shasum {
+		return "", fmt.Errorf("mismatch in CLI options and package metadata, expected %s, found %s", s.Shasum, pkgData.Sha256Sum)
 	}
 
-	// Ensure the files are in order so they are appended in the correct order
-	sort.Strings(fileList)
+	partsDir := filepath.Dir(s.PackageSource)
+	parts := []string{}
+	missing := []string{}
+	for _, name := range splitPartNames(s.PackageSource, pkgData) {
+		part := filepath.Join(partsDir, name)
+		if helpers.InvalidPath(part) {
+			missing = append(missing, name)
+		}
+		parts = append(parts, part)
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("package is missing %d of %d parts: %s", len(missing), len(parts), strings.Join(missing, ", "))
+	}
 
 	reassembled := filepath.Join(dir, filepath.Base(strings.Replace(s.PackageSource, ".part000", "", 1)))
-	// Create the new package
-	pkgFile, err := os.Create(reassembled)
-	if err != nil {
-		return "", fmt.Errorf("unable to create new package file: %w", err)
+	if err := reassembleSplitPackage(parts, pkgData, reassembled); err != nil {
+		return "", err
 	}
-	defer pkgFile.Close()
 
-	var pkgData types.ZarfSplitPackageData
-	for idx, file := range fileList {
-		// The first file contains metadata about the package
-		if idx == 0 {
-			var bytes []byte
-
-			if bytes, err = os.ReadFile(file); err != nil {
-				return "", fmt.Errorf("unable to read file %s: %w", file, err)
-			}
+	// Remove the parts to reduce disk space before extracting
+	for _, file := range append(parts, s.PackageSource) {
+		_ = os.Remove(file)
+	}
 
-			if err := json.Unmarshal(bytes, &pkgData); err != nil {
-				return "", fmt.Errorf("unable to unmarshal file %s: %w", file, err)
-			}
+	// communicate to the user that the package was reassembled
+	message.Infof("Reassembled package to: %q", reassembled)
 
-			count := len(fileList) - 1
-			if count != pkgData.Count {
-				return "", fmt.Errorf("package is missing parts, expected %d, found %d", pkgData.Count, count)
-			}
+	return reassembled, nil
+}
 
-			if len(s.Shasum) > 0 && pkgData.Sha256Sum != s.Shasum {
-				return "", fmt.Errorf("mismatch in CLI options and package metadata, expected %s, found %s", s.Shasum, pkgData.Sha256Sum)
-			}
+// readSplitPackageData reads the split package data from the first file of a split package.
+func readSplitPackageData(path string) (types.ZarfSplitPackageData, error) {
+	var pkgData types.ZarfSplitPackageData
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return pkgData, fmt.Errorf("unable to read file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(b, &pkgData); err != nil {
+		return pkgData, fmt.Errorf("unable to unmarshal file %s: %w", path, err)
+	}
+	if sum, err := hex.DecodeString(pkgData.Sha256Sum); err != nil || len(sum) != sha256.Size {
+		return pkgData, fmt.Errorf("split package data in %s has an invalid shasum %q", path, pkgData.Sha256Sum)
+	}
+	if len(pkgData.Parts) > 0 && len(pkgData.Parts) != pkgData.Count {
+		return pkgData, fmt.Errorf("split package data in %s lists %d parts but the package is split into %d", path, len(pkgData.Parts), pkgData.Count)
+	}
+	return pkgData, nil
+}
 
-			continue
+// splitPartNames returns the file names of the parts of a split package in the order they are reassembled, falling back
+// to the default part names for packages split before the part names were recorded.
+func splitPartNames(firstPart string, pkgData types.ZarfSplitPackageData) []string {
+	names := []string{}
+	if len(pkgData.Parts) > 0 {
+		for _, part := range pkgData.Parts {
+			// Only the base name is used so that parts cannot be read from outside of the package directory.
+			names = append(names, filepath.Base(part.Name))
 		}
+		return names
+	}
+	base := filepath.Base(strings.Replace(firstPart, ".part000", "", 1))
+	for i := range pkgData.Count {
+		names = append(names, fmt.Sprintf("%s.part%03d", base, i+1))
+	}
+	return names
+}
 
-		// Open the file
-		f, err := os.Open(file)
+// reassembleSplitPackage concatenates the parts of a split package into dst, verifying the shasum of each part that
+// has one recorded and the shasum of the reassembled package.
+func reassembleSplitPackage(parts []string, pkgData types.ZarfSplitPackageData, dst string) error {
+	pkgFile, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("unable to create new package file: %w", err)
+	}
+	defer pkgFile.Close()
+
+	for i, part := range parts {
+		f, err := os.Open(part)
 		if err != nil {
-			return "", fmt.Errorf("unable to open file %s: %w", file, err)
+			return fmt.Errorf("unable to open file %s: %w", part, err)
 		}
-		defer f.Close()
-
-		// Add the file contents to the package
-		if _, err = io.Copy(pkgFile, f); err != nil {
-			return "", fmt.Errorf("unable to copy file %s: %w", file, err)
+		hash := sha256.New()
+		_, err = io.Copy(io.MultiWriter(pkgFile, hash), f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("unable to copy file %s: %w", part, err)
 		}
-
-		// Close the file when done copying
-		if err := f.Close(); err != nil {
-			return "", fmt.Errorf("unable to close file %s: %w", file, err)
+		if len(pkgData.Parts) > 0 {
+			if sum := fmt.Sprintf("%x", hash.Sum(nil)); sum != pkgData.Parts[i].Sha256Sum {
+				return fmt.Errorf("package part %s is corrupt, expected shasum %s, found %s", filepath.Base(part), pkgData.Parts[i].Sha256Sum, sum)
+			}
 		}
 	}
-
-	if err := helpers.SHAsMatch(reassembled, pkgData.Sha256Sum); err != nil {
-		return "", fmt.Errorf("package integrity check failed: %w", err)
+	if err := pkgFile.Close(); err != nil {
+		return err
 	}
 
-	// Remove the parts to reduce disk space before extracting
-	for _, file := range fileList {
-		_ = os.Remove(file)
+	if err := helpers.SHAsMatch(dst, pkgData.Sha256Sum); err != nil {
+		return fmt.Errorf("package integrity check failed: %w", err)
 	}
-
-	// communicate to the user that the package was reassembled
-	message.Infof("Reassembled package to: %q", reassembled)
-
-	return reassembled, nil
+	return nil
 }
 
 // LoadPackage loads a package from a split tarball.
@@ -129,7 +165,7 @@ func (s *SplitTarballSource) LoadPackage(ctx context.Context, dst *layout.Packag
 }
 
 // LoadPackageMetadata loads a package's metadata from a split tarball.
-func (s *SplitTarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+func (s *SplitTarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
 	tb, err := s.Collect(ctx, filepath.Dir(s.PackageSource))
 	if err != nil {
 		return pkg, nil, err
@@ -141,5 +177,5 @@ func (s *SplitTarballSource) LoadPackageMetadata(ctx context.Context, dst *layou
 	ts := &TarballSource{
 		s.ZarfPackageOptions,
 	}
-	return ts.LoadPackageMetadata(ctx, dst, wantSBOM, skipValidation)
+	return ts.LoadPackageMetadata(ctx, dst, wantSBOM, wantDocs, skipValidation)
 }
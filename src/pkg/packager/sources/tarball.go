@@ -6,12 +6,15 @@ package sources
 
 import (
 	"archive/tar"
+	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/mholt/archiver/v3"
@@ -19,6 +22,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
 )
@@ -28,6 +32,9 @@ var (
 	_ PackageSource = (*TarballSource)(nil)
 )
 
+// ErrPkgEncryptedButNoKey is returned when a package is encrypted but no key or passphrase was provided
+var ErrPkgEncryptedButNoKey = errors.New("package is encrypted but no key was provided - add a key with the --decryption-key or --decryption-passphrase flag and run the command again")
+
 // TarballSource is a package source for tarballs.
 type TarballSource struct {
 	*types.ZarfPackageOptions
@@ -43,41 +50,11 @@ func (s *TarballSource) LoadPackage(ctx context.Context, dst *layout.PackagePath
 			return pkg, nil, err
 		}
 	}
+	if err := ValidateArchive(ctx, s.PackageSource, s.ZarfPackageOptions); err != nil {
+		return pkg, nil, err
+	}
 
-	pathsExtracted := []string{}
-
-	err = archiver.Walk(s.PackageSource, func(f archiver.File) error {
-		if f.IsDir() {
-			return nil
-		}
-		header, ok := f.Header.(*tar.Header)
-		if !ok {
-			return fmt.Errorf("expected header to be *tar.Header but was %T", f.Header)
-		}
-		path := header.Name
-
-		dir := filepath.Dir(path)
-		if dir != "." {
-			if err := os.MkdirAll(filepath.Join(dst.Base, dir), helpers.ReadExecuteAllWriteUser); err != nil {
-				return err
-			}
-		}
-
-		dstPath := filepath.Join(dst.Base, path)
-		pathsExtracted = append(pathsExtracted, path)
-		dst, err := os.Create(dstPath)
-		if err != nil {
-			return err
-		}
-		defer dst.Close()
-
-		_, err = io.Copy(dst, f)
-		if err != nil {
-			return err
-		}
-
-		return nil
-	})
+	pathsExtracted, err := s.unarchive(dst.Base, nil)
 	if err != nil {
 		return pkg, nil, err
 	}
@@ -108,7 +85,7 @@ func (s *TarballSource) LoadPackage(ctx context.Context, dst *layout.PackagePath
 		spinner.Success()
 
 		if !s.SkipSignatureValidation {
-			if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath); err != nil {
+			if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath, s.CertificateIdentity, s.CertificateOIDCIssuer, s.TrustedRootPath); err != nil {
 				return pkg, nil, err
 			}
 		}
@@ -141,27 +118,26 @@ func (s *TarballSource) LoadPackage(ctx context.Context, dst *layout.PackagePath
 }
 
 // LoadPackageMetadata loads a package's metadata from a tarball.
-func (s *TarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+func (s *TarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
 	if s.Shasum != "" {
 		if err := helpers.SHAsMatch(s.PackageSource, s.Shasum); err != nil {
 			return pkg, nil, err
 		}
 	}
+	if err := ValidateArchive(ctx, s.PackageSource, s.ZarfPackageOptions); err != nil {
+		return pkg, nil, err
+	}
 
 	toExtract := zoci.PackageAlwaysPull
 	if wantSBOM {
 		toExtract = append(toExtract, layout.SBOMTar)
 	}
-	pathsExtracted := []string{}
-
-	for _, rel := range toExtract {
-		if err := archiver.Extract(s.PackageSource, rel, dst.Base); err != nil {
-			return pkg, nil, err
-		}
-		// archiver.Extract will not return an error if the file does not exist, so we must manually check
-		if !helpers.InvalidPath(filepath.Join(dst.Base, rel)) {
-			pathsExtracted = append(pathsExtracted, rel)
-		}
+	if wantDocs {
+		toExtract = append(toExtract, layout.DocsTar)
+	}
+	pathsExtracted, err := s.unarchive(dst.Base, toExtract)
+	if err != nil {
+		return pkg, nil, err
 	}
 
 	dst.SetFromPaths(pathsExtracted)
@@ -176,8 +152,8 @@ func (s *TarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.Pac
 	}
 
 	if !dst.IsLegacyLayout() {
-		if wantSBOM {
-			spinner := message.NewProgressSpinner("Validating SBOM checksums")
+		if wantSBOM || wantDocs {
+			spinner := message.NewProgressSpinner("Validating SBOM and docs checksums")
 			defer spinner.Stop()
 
 			if err := ValidatePackageIntegrity(dst, pkg.Metadata.AggregateChecksum, true); err != nil {
@@ -188,10 +164,13 @@ func (s *TarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.Pac
 		}
 
 		if !s.SkipSignatureValidation {
-			if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath); err != nil {
-				if errors.Is(err, ErrPkgSigButNoKey) && skipValidation {
+			if err := ValidatePackageSignature(ctx, dst, s.PublicKeyPath, s.CertificateIdentity, s.CertificateOIDCIssuer, s.TrustedRootPath); err != nil {
+				switch {
+				case errors.Is(err, ErrPkgSigButNoKey) && skipValidation:
 					message.Warn("The package was signed but no public key was provided, skipping signature validation")
-				} else {
+				case errors.Is(err, ErrPkgKeylessSigButNoIdentity) && skipValidation:
+					message.Warn("The package was signed keyless but no certificate identity was provided, skipping signature validation")
+				default:
 					return pkg, nil, err
 				}
 			}
@@ -207,6 +186,128 @@ func (s *TarballSource) LoadPackageMetadata(ctx context.Context, dst *layout.Pac
 	return pkg, warnings, nil
 }
 
+// zstdMagic is the magic number at the start of a zstd compressed package tarball.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// unarchive streams the files of the package tarball into dir, returning their paths relative to dir. If names is not
+// nil only the files with those names are extracted. Encrypted packages are decrypted as they are read so that no
+// decrypted copy of the tarball is written to disk.
+func (s *TarballSource) unarchive(dir string, names []string) ([]string, error) {
+	r, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	paths, err := unarchivePackage(r, dir, names)
+	if errors.Is(err, utils.ErrDecrypt) {
+		return nil, fmt.Errorf("unable to decrypt package %q: %w", s.PackageSource, err)
+	}
+	return paths, err
+}
+
+// open opens the package tarball for reading, decrypting it as it is read if it is encrypted.
+func (s *TarballSource) open() (io.ReadCloser, error) {
+	encrypted, err := utils.IsEncrypted(s.PackageSource)
+	if err != nil {
+		return nil, err
+	}
+	var secret []byte
+	if encrypted {
+		secret, err = utils.ReadEncryptionSecret(s.DecryptionPassphrase, s.DecryptionKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		if secret == nil {
+			return nil, ErrPkgEncryptedButNoKey
+		}
+	}
+
+	f, err := os.Open(s.PackageSource)
+	if err != nil {
+		return nil, err
+	}
+	if !encrypted {
+		return f, nil
+	}
+	message.Debugf("Decrypting package %q", s.PackageSource)
+	r, err := utils.NewDecryptReader(f, secret)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to decrypt package %q: %w", s.PackageSource, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, f}, nil
+}
+
+// unarchivePackage extracts the files of a package tarball, which may be compressed with zstd, from r into dir in a
+// single pass, returning their paths relative to dir. If names is not nil only the files with those names are extracted
+// and reading stops once all of them have been found.
+func unarchivePackage(r io.Reader, dir string, names []string) ([]string, error) {
+	br := bufio.NewReader(r)
+	var tarball archiver.Reader = archiver.NewTar()
+	if magic, err := br.Peek(len(zstdMagic)); err == nil && bytes.Equal(magic, zstdMagic) {
+		tarball = archiver.NewTarZstd()
+	}
+	if err := tarball.Open(br, 0); err != nil {
+		return nil, err
+	}
+	defer tarball.Close()
+
+	pathsExtracted := []string{}
+	for names == nil || len(pathsExtracted) < len(names) {
+		f, err := tarball.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := extractPackageFile(f, dir, names, &pathsExtracted); err != nil {
+			return nil, err
+		}
+	}
+	return pathsExtracted, nil
+}
+
+// extractPackageFile writes a file read from a package tarball into dir if it is one of names, or names is nil.
+func extractPackageFile(f archiver.File, dir string, names []string, pathsExtracted *[]string) error {
+	defer f.Close()
+
+	if f.IsDir() {
+		return nil
+	}
+	header, ok := f.Header.(*tar.Header)
+	if !ok {
+		return fmt.Errorf("expected header to be *tar.Header but was %T", f.Header)
+	}
+	path := header.Name
+	if names != nil && !slices.Contains(names, path) {
+		return nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(path)) {
+		return fmt.Errorf("invalid path %q in package tarball", path)
+	}
+
+	dstPath := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(dstPath), helpers.ReadExecuteAllWriteUser); err != nil {
+		return err
+	}
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, f); err != nil {
+		return err
+	}
+	*pathsExtracted = append(*pathsExtracted, path)
+	return dst.Close()
+}
+
 // Collect for the TarballSource is essentially an `mv`
 func (s *TarballSource) Collect(_ context.Context, dir string) (string, error) {
 	dst := filepath.Join(dir, filepath.Base(s.PackageSource))
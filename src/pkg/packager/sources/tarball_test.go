@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sources
+
+import (
+	"archive/tar"
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+)
+
+// writeTar returns a tarball holding the given files in order.
+func writeTar(t *testing.T, files [][2]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f[0], Mode: 0o600, Size: int64(len(f[1])), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(f[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestUnarchivePackage(t *testing.T) {
+	t.Parallel()
+
+	tarball := writeTar(t, [][2]string{
+		{layout.ZarfYAML, "kind: ZarfPackageConfig"},
+		{"components/wordpress.tar", "component"},
+		{layout.Checksums, "checksums"},
+	})
+
+	tests := []struct {
+		name          string
+		tarball       []byte
+		names         []string
+		expectedPaths []string
+		expectedErr   string
+	}{
+		{
+			name:          "all files",
+			tarball:       tarball,
+			expectedPaths: []string{layout.ZarfYAML, "components/wordpress.tar", layout.Checksums},
+		},
+		{
+			name:          "only the named files",
+			tarball:       tarball,
+			names:         []string{layout.Checksums, layout.ZarfYAML, layout.Signature},
+			expectedPaths: []string{layout.ZarfYAML, layout.Checksums},
+		},
+		{
+			name: "stops once the named files are found",
+			// The stream is cut off after the first file so reading any further would fail.
+			tarball:       tarball[:1024],
+			names:         []string{layout.ZarfYAML},
+			expectedPaths: []string{layout.ZarfYAML},
+		},
+		{
+			name:        "path outside of the package",
+			tarball:     writeTar(t, [][2]string{{"../zarf.yaml", "kind: ZarfPackageConfig"}}),
+			expectedErr: `invalid path "../zarf.yaml" in package tarball`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			paths, err := unarchivePackage(bytes.NewReader(tt.tarball), dir, tt.names)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedPaths, paths)
+			for _, path := range paths {
+				require.FileExists(t, filepath.Join(dir, path))
+			}
+		})
+	}
+}
@@ -6,8 +6,12 @@ package sources
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -15,6 +19,7 @@ import (
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/types"
@@ -32,8 +37,42 @@ type URLSource struct {
 
 // Collect downloads a package from the source URL.
 func (s *URLSource) Collect(ctx context.Context, dir string) (string, error) {
+	if isSplitURL(s.PackageSource) {
+		if err := s.requireShasum(); err != nil {
+			return "", err
+		}
+		return s.collectSplit(ctx, dir)
+	}
+	partial, err := s.download(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	dstTarball := filepath.Join(dir, "zarf-package-url-unknown")
+	if err := helpers.CreatePathAndCopy(partial, dstTarball); err != nil {
+		return "", err
+	}
+	if err := os.Remove(partial); err != nil {
+		return "", err
+	}
+
+	return renameDownloaded(dstTarball, s.PackageSource)
+}
+
+// requireShasum returns an error if the package can not be verified, only sget packages are verified by their
+// signature instead of a shasum.
+func (s *URLSource) requireShasum() error {
 	if s.Shasum == "" && !strings.HasPrefix(s.PackageSource, helpers.SGETURLPrefix) {
-		return "", fmt.Errorf("remote package provided without a shasum, please provide one with --shasum")
+		return errors.New("remote package provided without a shasum, please provide one with --shasum")
+	}
+	return nil
+}
+
+// download downloads a package that is not split into the cache and returns its path, the download is kept in the
+// cache until it completes so that running the command again resumes it.
+func (s *URLSource) download(ctx context.Context) (string, error) {
+	if err := s.requireShasum(); err != nil {
+		return "", err
 	}
 	var packageURL string
 	if s.Shasum != "" {
@@ -42,57 +81,159 @@ func (s *URLSource) Collect(ctx context.Context, dir string) (string, error) {
 		packageURL = s.PackageSource
 	}
 
-	dstTarball := filepath.Join(dir, "zarf-package-url-unknown")
+	// The shasum is part of the name so that a partial download of a different package published at the same URL is
+	// not resumed.
+	downloadsDir := filepath.Join(config.GetAbsCachePath(), remoteDownloadsDir)
+	partial := filepath.Join(downloadsDir, fmt.Sprintf("%x", sha256.Sum256([]byte(s.PackageSource+"\x00"+s.Shasum))))
+	if err := utils.ResumeDownloadToFile(ctx, packageURL, partial, s.SGetKeyPath, s.Retries); err != nil {
+		return "", fmt.Errorf("unable to download %s, run the command again to resume the download: %w", s.PackageSource, err)
+	}
+	return partial, nil
+}
+
+// downloadedSource downloads a package that is not split into the cache and returns a source that loads it from there,
+// so that the package is extracted without first being copied out of the cache. The download must be removed once the
+// package is loaded.
+func (s *URLSource) downloadedSource(ctx context.Context) (*TarballSource, error) {
+	tarball, err := s.download(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pkgOpts := *s.ZarfPackageOptions
+	pkgOpts.PackageSource = tarball
+	// The download was already verified against the shasum
+	pkgOpts.Shasum = ""
+	return &TarballSource{&pkgOpts}, nil
+}
 
-	if err := utils.DownloadToFile(ctx, packageURL, dstTarball, s.SGetKeyPath); err != nil {
+// renameDownloaded renames a tarball downloaded from the given URL to the name of the package.
+func renameDownloaded(tarball, packageURL string) (string, error) {
+	encrypted, err := utils.IsEncrypted(tarball)
+	if err != nil {
 		return "", err
 	}
+	if encrypted {
+		// The metadata of an encrypted package can not be read without decrypting it, so keep the name it was published with.
+		return renameFromURL(tarball, packageURL)
+	}
 
-	return RenameFromMetadata(dstTarball)
+	return RenameFromMetadata(tarball)
 }
 
-// LoadPackage loads a package from an http, https or sget URL.
-func (s *URLSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
-	tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+// isSplitURL returns true if the URL points at the first file of a split package.
+func isSplitURL(packageURL string) bool {
+	parsed, err := url.Parse(packageURL)
 	if err != nil {
-		return pkg, nil, err
+		return false
 	}
-	defer os.Remove(tmp)
+	return strings.HasSuffix(parsed.Path, ".part000")
+}
 
-	dstTarball, err := s.Collect(ctx, tmp)
+// collectSplit downloads the parts of a split package that sit next to the first file of the package and reassembles
+// them. Parts are downloaded into the cache and kept until the package is reassembled, so that an interrupted download
+// resumes with the parts that are still missing, starting where the interrupted part stopped.
+func (s *URLSource) collectSplit(ctx context.Context, dir string) (string, error) {
+	parsed, err := url.Parse(s.PackageSource)
 	if err != nil {
-		return pkg, nil, err
+		return "", err
+	}
+	name := path.Base(parsed.Path)
+	manifestPath := filepath.Join(dir, name)
+	if err := utils.DownloadToFile(ctx, s.PackageSource, manifestPath, s.SGetKeyPath); err != nil {
+		return "", err
+	}
+	pkgData, err := readSplitPackageData(manifestPath)
+	if err != nil {
+		return "", err
+	}
+	if s.Shasum != "" && pkgData.Sha256Sum != s.Shasum {
+		return "", fmt.Errorf("mismatch in CLI options and package metadata, expected %s, found %s", s.Shasum, pkgData.Sha256Sum)
 	}
 
-	s.PackageSource = dstTarball
-	// Clear the shasum so that it doesn't get used again
-	s.Shasum = ""
+	partsDir := filepath.Join(config.GetAbsCachePath(), "split", pkgData.Sha256Sum)
+	parts := []string{}
+	downloaded := 0
+	for i, partName := range splitPartNames(manifestPath, pkgData) {
+		part := filepath.Join(partsDir, partName)
+		parts = append(parts, part)
+
+		partURL := siblingURL(*parsed, partName)
+		if len(pkgData.Parts) > 0 {
+			if helpers.SHAsMatch(part, pkgData.Parts[i].Sha256Sum) == nil {
+				continue
+			}
+			partURL = fmt.Sprintf("%s@%s", partURL, pkgData.Parts[i].Sha256Sum)
+		}
+		if err := utils.ResumeDownloadToFile(ctx, partURL, part, s.SGetKeyPath, s.Retries); err != nil {
+			return "", fmt.Errorf("unable to download package part %s, run the command again to resume the download: %w", partName, err)
+		}
+		downloaded++
+	}
+	if downloaded < len(parts) {
+		message.Infof("Resumed the download of split package %s, %d of %d parts were already downloaded", name, len(parts)-downloaded, len(parts))
+	}
 
-	ts := &TarballSource{
-		s.ZarfPackageOptions,
+	reassembled := filepath.Join(dir, strings.TrimSuffix(name, ".part000"))
+	if err := reassembleSplitPackage(parts, pkgData, reassembled); err != nil {
+		// Drop the parts so that corrupted parts are downloaded again.
+		_ = os.RemoveAll(partsDir)
+		return "", err
 	}
+	if err := os.RemoveAll(partsDir); err != nil {
+		return "", err
+	}
+	if err := os.Remove(manifestPath); err != nil {
+		return "", err
+	}
+	return reassembled, nil
+}
 
-	return ts.LoadPackage(ctx, dst, filter, unarchiveAll)
+// siblingURL returns the URL of the file with the given name in the same directory as the given URL.
+func siblingURL(packageURL url.URL, name string) string {
+	packageURL.Path = path.Join(path.Dir(packageURL.Path), name)
+	packageURL.RawPath = ""
+	return packageURL.String()
 }
 
-// LoadPackageMetadata loads a package's metadata from an http, https or sget URL.
-func (s *URLSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
-	tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+// renameFromURL renames a downloaded tarball to the last element of the URL it was downloaded from.
+func renameFromURL(tarball, packageURL string) (string, error) {
+	parsed, err := url.Parse(packageURL)
 	if err != nil {
-		return pkg, nil, err
+		return "", err
+	}
+	name := filepath.Base(parsed.Path)
+	if !IsValidFileExtension(name) {
+		return "", fmt.Errorf("unable to determine the package name of encrypted package %q, the URL must end with one of %v", packageURL, GetValidPackageExtensions())
+	}
+	dst := filepath.Join(filepath.Dir(tarball), name)
+	if err := os.Rename(tarball, dst); err != nil {
+		return "", err
 	}
-	defer os.Remove(tmp)
+	return dst, nil
+}
 
-	dstTarball, err := s.Collect(ctx, tmp)
+// LoadPackage loads a package from an http, https or sget URL.
+func (s *URLSource) LoadPackage(ctx context.Context, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	if isSplitURL(s.PackageSource) {
+		return loadCollectedPackage(ctx, s, s.ZarfPackageOptions, dst, filter, unarchiveAll)
+	}
+	ts, err := s.downloadedSource(ctx)
 	if err != nil {
 		return pkg, nil, err
 	}
+	defer os.Remove(ts.PackageSource)
+	return ts.LoadPackage(ctx, dst, filter, unarchiveAll)
+}
 
-	s.PackageSource = dstTarball
-
-	ts := &TarballSource{
-		s.ZarfPackageOptions,
+// LoadPackageMetadata loads a package's metadata from an http, https or sget URL.
+func (s *URLSource) LoadPackageMetadata(ctx context.Context, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (pkg v1alpha1.ZarfPackage, warnings []string, err error) {
+	if isSplitURL(s.PackageSource) {
+		return loadCollectedPackageMetadata(ctx, s, s.ZarfPackageOptions, dst, wantSBOM, wantDocs, skipValidation)
 	}
-
-	return ts.LoadPackageMetadata(ctx, dst, wantSBOM, skipValidation)
+	ts, err := s.downloadedSource(ctx)
+	if err != nil {
+		return pkg, nil, err
+	}
+	defer os.Remove(ts.PackageSource)
+	return ts.LoadPackageMetadata(ctx, dst, wantSBOM, wantDocs, skipValidation)
 }
@@ -5,6 +5,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -17,7 +18,10 @@ import (
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 // GetValidPackageExtensions returns the valid package extensions.
@@ -165,3 +169,41 @@ func PkgSuffix(uncompressed bool) (suffix string) {
 	}
 	return suffix
 }
+
+// loadCollectedPackage collects a remote package into a temporary directory and loads it as a tarball.
+func loadCollectedPackage(ctx context.Context, src PackageSource, pkgOpts *types.ZarfPackageOptions, dst *layout.PackagePaths, filter filters.ComponentFilterStrategy, unarchiveAll bool) (v1alpha1.ZarfPackage, []string, error) {
+	tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	defer os.Remove(tmp)
+
+	dstTarball, err := src.Collect(ctx, tmp)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	pkgOpts.PackageSource = dstTarball
+	// Clear the shasum so that it doesn't get used again
+	pkgOpts.Shasum = ""
+
+	ts := &TarballSource{pkgOpts}
+	return ts.LoadPackage(ctx, dst, filter, unarchiveAll)
+}
+
+// loadCollectedPackageMetadata collects a remote package into a temporary directory and loads its metadata as a tarball.
+func loadCollectedPackageMetadata(ctx context.Context, src PackageSource, pkgOpts *types.ZarfPackageOptions, dst *layout.PackagePaths, wantSBOM bool, wantDocs bool, skipValidation bool) (v1alpha1.ZarfPackage, []string, error) {
+	tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	defer os.Remove(tmp)
+
+	dstTarball, err := src.Collect(ctx, tmp)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, nil, err
+	}
+	pkgOpts.PackageSource = dstTarball
+
+	ts := &TarballSource{pkgOpts}
+	return ts.LoadPackageMetadata(ctx, dst, wantSBOM, wantDocs, skipValidation)
+}
@@ -7,17 +7,27 @@ package sources
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/layout"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+	"github.com/zeebo/blake3"
 )
 
 var (
@@ -25,25 +35,65 @@ var (
 	ErrPkgKeyButNoSig = errors.New("a key was provided but the package is not signed - the package may be corrupted or the --key flag was erroneously specified")
 	// ErrPkgSigButNoKey is returned when a package is signed but no key was provided
 	ErrPkgSigButNoKey = errors.New("package is signed but no key was provided - add a key with the --key flag or use the --skip-signature-validation flag and run the command again")
+	// ErrPkgIdentityButNoSig is returned when a certificate identity was provided but the package is not signed keyless
+	ErrPkgIdentityButNoSig = errors.New("a certificate identity was provided but the package is not signed keyless - the package may be corrupted or the --certificate-identity flag was erroneously specified")
+	// ErrPkgKeylessSigButNoIdentity is returned when a package is signed keyless but no certificate identity was provided
+	ErrPkgKeylessSigButNoIdentity = errors.New("package is signed keyless but no certificate identity was provided - add the expected identity with the --certificate-identity and --certificate-oidc-issuer flags or use the --skip-signature-validation flag and run the command again")
+	// ErrPkgIncompleteIdentity is returned when only one of a certificate identity or OIDC issuer was provided
+	ErrPkgIncompleteIdentity = errors.New("both --certificate-identity and --certificate-oidc-issuer must be provided to validate a keyless signature")
+	// ErrPkgKeyAndIdentity is returned when both a key and a certificate identity were provided
+	ErrPkgKeyAndIdentity = errors.New("only one of a key or a certificate identity can be provided to validate a package signature")
 )
 
-// ValidatePackageSignature validates the signature of a package
-func ValidatePackageSignature(ctx context.Context, paths *layout.PackagePaths, publicKeyPath string) error {
+// ValidatePackageSignature validates the signature of a package against either a public key or, for packages signed
+// keyless, the identity and OIDC issuer its signing certificate must have been issued to. The certificate of a keyless
+// signature is checked against the trusted root directory when one is given, see utils.CosignVerifyBlobKeyless.
+func ValidatePackageSignature(ctx context.Context, paths *layout.PackagePaths, publicKeyPath, certIdentity, certOIDCIssuer, trustedRoot string) error {
 	if publicKeyPath != "" {
 		message.Debugf("Using public key %q for signature validation", publicKeyPath)
 	}
 
+	keyless := certIdentity != "" || certOIDCIssuer != ""
+	if keyless && publicKeyPath != "" {
+		return ErrPkgKeyAndIdentity
+	}
+	if keyless && (certIdentity == "" || certOIDCIssuer == "") {
+		return ErrPkgIncompleteIdentity
+	}
+
 	// Handle situations where there is no signature within the package
-	sigExist := paths.Signature != ""
-	if !sigExist && publicKeyPath == "" {
-		// Nobody was expecting a signature, so we can just return
+	sigExist := paths.Signature != "" || paths.Bundle != ""
+	if !sigExist {
+		switch {
+		case publicKeyPath != "":
+			// A key was provided but there is no signature
+			return ErrPkgKeyButNoSig
+		case keyless:
+			return ErrPkgIdentityButNoSig
+		default:
+			// Nobody was expecting a signature, so we can just return
+			return nil
+		}
+	}
+
+	// Packages signed keyless carry a bundle with the signing certificate
+	if paths.Bundle != "" {
+		if !keyless {
+			return ErrPkgKeylessSigButNoIdentity
+		}
+		if err := utils.CosignVerifyBlobKeyless(ctx, paths.ZarfYAML, paths.Bundle, certIdentity, certOIDCIssuer, trustedRoot); err != nil {
+			return fmt.Errorf("package signature did not match the provided identity: %w", err)
+		}
 		return nil
-	} else if sigExist && publicKeyPath == "" {
+	}
+
+	if keyless {
+		// The package was signed with a key-pair so there is no certificate to check the identity of
+		return ErrPkgIdentityButNoSig
+	}
+	if publicKeyPath == "" {
 		// The package is signed but no key was provided
 		return ErrPkgSigButNoKey
-	} else if !sigExist && publicKeyPath != "" {
-		// A key was provided but there is no signature
-		return ErrPkgKeyButNoSig
 	}
 
 	// Validate the signature with the key we were provided
@@ -54,6 +104,79 @@ func ValidatePackageSignature(ctx context.Context, paths *layout.PackagePaths, p
 	return nil
 }
 
+// archiveChecksumAlgorithms are the hash algorithms that an archive checksum can use.
+var archiveChecksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+	"blake3": func() hash.Hash { return blake3.New() },
+}
+
+// ValidateArchive validates a package archive against the archive checksum and detached signature in the package
+// options, before the archive is decrypted or extracted.
+func ValidateArchive(ctx context.Context, archive string, pkgOpts *types.ZarfPackageOptions) error {
+	if pkgOpts.ArchiveChecksum != "" {
+		if err := archiveChecksumMatches(archive, pkgOpts.ArchiveChecksum); err != nil {
+			return err
+		}
+	}
+	if pkgOpts.ArchiveSignature == "" {
+		return nil
+	}
+
+	keyPath := pkgOpts.ArchiveKeyPath
+	if keyPath == "" {
+		keyPath = pkgOpts.PublicKeyPath
+	}
+	if keyPath == "" {
+		return errors.New("a public key is required to verify the archive signature - add one with the --archive-key or --key flag and run the command again")
+	}
+	sigPath := pkgOpts.ArchiveSignature
+	if parsed, err := url.Parse(sigPath); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") {
+		tmp, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		sigPath = filepath.Join(tmp, "archive.sig")
+		if err := utils.DownloadToFile(ctx, pkgOpts.ArchiveSignature, sigPath, ""); err != nil {
+			return fmt.Errorf("unable to download the archive signature: %w", err)
+		}
+	}
+	if err := utils.CosignVerifyBlob(ctx, archive, sigPath, keyPath); err != nil {
+		return fmt.Errorf("package archive signature did not match the provided key: %w", err)
+	}
+	return nil
+}
+
+// archiveChecksumMatches returns an error if the file does not match the checksum, which is prefixed with its algorithm.
+func archiveChecksumMatches(path, checksum string) error {
+	algorithm, expected, ok := strings.Cut(checksum, ":")
+	newHash, supported := archiveChecksumAlgorithms[algorithm]
+	if !ok || !supported {
+		algorithms := []string{}
+		for a := range archiveChecksumAlgorithms {
+			algorithms = append(algorithms, a)
+		}
+		slices.Sort(algorithms)
+		return fmt.Errorf("invalid archive checksum %q, it must be prefixed with one of %s (e.g. sha512:<digest>)", checksum, strings.Join(algorithms, ", "))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := newHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if actual != strings.ToLower(expected) {
+		return fmt.Errorf("expected %s of %s to be %s, found %s", algorithm, path, expected, actual)
+	}
+	return nil
+}
+
 // ValidatePackageIntegrity validates the integrity of a package by comparing checksums
 func ValidatePackageIntegrity(loaded *layout.PackagePaths, aggregateChecksum string, isPartial bool) error {
 	// ensure checksums.txt and zarf.yaml were loaded
@@ -77,6 +200,7 @@ func ValidatePackageIntegrity(loaded *layout.PackagePaths, aggregateChecksum str
 	checkedMap[loaded.ZarfYAML] = true
 	checkedMap[loaded.Checksums] = true
 	checkedMap[loaded.Signature] = true
+	checkedMap[loaded.Bundle] = true
 
 	err = lineByLine(checksumPath, func(line string) error {
 		// If the line is empty (i.e. there is no checksum) simply skip it - this can result from a package with no images/components
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package sources
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+func TestValidatePackageSignature(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		signed         []string
+		publicKeyPath  string
+		certIdentity   string
+		certOIDCIssuer string
+		trustedRoot    string
+		expectedErr    error
+		expectedErrMsg string
+	}{
+		{
+			name: "unsigned without a key",
+		},
+		{
+			name:          "unsigned with a key",
+			publicKeyPath: "cosign.pub",
+			expectedErr:   ErrPkgKeyButNoSig,
+		},
+		{
+			name:           "unsigned with an identity",
+			certIdentity:   "dev@example.com",
+			certOIDCIssuer: "https://accounts.example.com",
+			expectedErr:    ErrPkgIdentityButNoSig,
+		},
+		{
+			name:        "signed with a key without a key",
+			signed:      []string{layout.Signature},
+			expectedErr: ErrPkgSigButNoKey,
+		},
+		{
+			name:           "signed with a key with an identity",
+			signed:         []string{layout.Signature},
+			certIdentity:   "dev@example.com",
+			certOIDCIssuer: "https://accounts.example.com",
+			expectedErr:    ErrPkgIdentityButNoSig,
+		},
+		{
+			name:        "signed keyless without an identity",
+			signed:      []string{layout.Signature, layout.Bundle},
+			expectedErr: ErrPkgKeylessSigButNoIdentity,
+		},
+		{
+			name:          "signed keyless with a key",
+			signed:        []string{layout.Signature, layout.Bundle},
+			publicKeyPath: "cosign.pub",
+			expectedErr:   ErrPkgKeylessSigButNoIdentity,
+		},
+		{
+			name:         "identity without an issuer",
+			signed:       []string{layout.Signature, layout.Bundle},
+			certIdentity: "dev@example.com",
+			expectedErr:  ErrPkgIncompleteIdentity,
+		},
+		{
+			name:           "trusted root without the keys",
+			signed:         []string{layout.Signature, layout.Bundle},
+			certIdentity:   "dev@example.com",
+			certOIDCIssuer: "https://accounts.example.com",
+			trustedRoot:    "testdata/missing-trusted-root",
+			expectedErrMsg: "unable to read the trusted root",
+		},
+		{
+			name:           "key and identity",
+			signed:         []string{layout.Signature},
+			publicKeyPath:  "cosign.pub",
+			certIdentity:   "dev@example.com",
+			certOIDCIssuer: "https://accounts.example.com",
+			expectedErr:    ErrPkgKeyAndIdentity,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			paths := layout.New(t.TempDir())
+			paths.SetFromPaths(tt.signed)
+			err := ValidatePackageSignature(context.Background(), paths, tt.publicKeyPath, tt.certIdentity, tt.certOIDCIssuer, tt.trustedRoot)
+			if tt.expectedErrMsg != "" {
+				require.ErrorContains(t, err, tt.expectedErrMsg)
+				return
+			}
+			if tt.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestValidateArchive(t *testing.T) {
+	t.Parallel()
+
+	archive := filepath.Join("testdata", "zarf-package-wordpress-amd64-16.0.4.tar.zst")
+	passFn := func(bool) ([]byte, error) { return []byte{}, nil }
+	keys, err := cosign.GenerateKeyPair(passFn)
+	require.NoError(t, err)
+	keyDir := t.TempDir()
+	privateKey := filepath.Join(keyDir, "cosign.key")
+	publicKey := filepath.Join(keyDir, "cosign.pub")
+	require.NoError(t, os.WriteFile(privateKey, keys.PrivateBytes, helpers.ReadWriteUser))
+	require.NoError(t, os.WriteFile(publicKey, keys.PublicBytes, helpers.ReadWriteUser))
+	signature := filepath.Join(keyDir, "archive.sig")
+	_, err = utils.CosignSignBlob(archive, signature, privateKey, passFn)
+	require.NoError(t, err)
+	otherSignature := filepath.Join(keyDir, "other.sig")
+	_, err = utils.CosignSignBlob(filepath.Join("testdata", "zarf.yaml"), otherSignature, privateKey, passFn)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		pkgOpts     types.ZarfPackageOptions
+		expectedErr string
+	}{
+		{
+			name:    "sha512 checksum",
+			pkgOpts: types.ZarfPackageOptions{ArchiveChecksum: "sha512:1d6a48ee9cb1d53b17e7ee0df527178bf70b3fd6d64fe1bc1da97089abf349c9f437c285305da58d2f5e611847087c169548605090bc11e7bb3aa7bc0a18f892"},
+		},
+		{
+			name:    "blake3 checksum",
+			pkgOpts: types.ZarfPackageOptions{ArchiveChecksum: "blake3:fe097fb0a2785ec986efaa65281806efee7e46bad4100630ab68f427622f175c"},
+		},
+		{
+			name:    "sha256 checksum",
+			pkgOpts: types.ZarfPackageOptions{ArchiveChecksum: "sha256:835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26"},
+		},
+		{
+			name:        "checksum mismatch",
+			pkgOpts:     types.ZarfPackageOptions{ArchiveChecksum: "sha256:0000"},
+			expectedErr: "expected sha256 of testdata/zarf-package-wordpress-amd64-16.0.4.tar.zst to be 0000, found 835b06fc509e639497fb45f45d432e5c4cbd5d84212db5357b16bc69724b0e26",
+		},
+		{
+			name:        "unsupported algorithm",
+			pkgOpts:     types.ZarfPackageOptions{ArchiveChecksum: "md5:0000"},
+			expectedErr: `invalid archive checksum "md5:0000", it must be prefixed with one of blake3, sha256, sha512 (e.g. sha512:<digest>)`,
+		},
+		{
+			name:    "signature with the archive key",
+			pkgOpts: types.ZarfPackageOptions{ArchiveSignature: signature, ArchiveKeyPath: publicKey},
+		},
+		{
+			name:    "signature with the package key",
+			pkgOpts: types.ZarfPackageOptions{ArchiveSignature: signature, PublicKeyPath: publicKey},
+		},
+		{
+			name:        "signature of another file",
+			pkgOpts:     types.ZarfPackageOptions{ArchiveSignature: otherSignature, ArchiveKeyPath: publicKey},
+			expectedErr: "package archive signature did not match the provided key",
+		},
+		{
+			name:        "signature without a key",
+			pkgOpts:     types.ZarfPackageOptions{ArchiveSignature: signature},
+			expectedErr: "a public key is required to verify the archive signature",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateArchive(context.Background(), archive, &tt.pkgOpts)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
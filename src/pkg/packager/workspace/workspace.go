@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package workspace contains functions for working with directories of related Zarf packages.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// ImportPrefix is the prefix of component import paths that reference another package in the workspace by name.
+const ImportPrefix = "workspace:"
+
+// Workspace is a directory of related packages that are linted and created together.
+type Workspace struct {
+	// The directories of the packages in the workspace, relative to the workspace, in the order they are created.
+	Packages []string `json:"packages"`
+	// Package template values shared by every package in the workspace, values given with --set take precedence.
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// Base is the absolute path of the directory containing the workspace file.
+	Base string `json:"-"`
+	// names maps the name of each package in the workspace to its absolute directory.
+	names map[string]string
+}
+
+// IsWorkspace returns true if the directory contains a workspace file.
+func IsWorkspace(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, layout.ZarfWorkspace))
+	return err == nil
+}
+
+// Read reads and validates the workspace file in the given directory.
+func Read(dir string) (*Workspace, error) {
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	ws := &Workspace{}
+	if err := utils.ReadYaml(filepath.Join(base, layout.ZarfWorkspace), ws); err != nil {
+		return nil, err
+	}
+	ws.Base = base
+	ws.names = map[string]string{}
+
+	if len(ws.Packages) == 0 {
+		return nil, fmt.Errorf("workspace %s does not list any packages", base)
+	}
+	seen := map[string]bool{}
+	for _, path := range ws.Packages {
+		if filepath.IsAbs(path) {
+			return nil, fmt.Errorf("workspace package path %q cannot be an absolute path", path)
+		}
+		pkgDir := filepath.Join(base, path)
+		if seen[pkgDir] {
+			return nil, fmt.Errorf("workspace package path %q is listed more than once", path)
+		}
+		seen[pkgDir] = true
+
+		var pkg v1alpha1.ZarfPackage
+		if err := utils.ReadYaml(filepath.Join(pkgDir, layout.ZarfYAML), &pkg); err != nil {
+			return nil, fmt.Errorf("unable to read workspace package %q: %w", path, err)
+		}
+		if existing, ok := ws.names[pkg.Metadata.Name]; ok {
+			return nil, fmt.Errorf("workspace packages %q and %q are both named %q", existing, pkgDir, pkg.Metadata.Name)
+		}
+		ws.names[pkg.Metadata.Name] = pkgDir
+	}
+	return ws, nil
+}
+
+// Find returns the workspace containing the given directory, searching up through its parents, or nil if the directory
+// is not in a workspace.
+func Find(dir string) (*Workspace, error) {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		_, err := os.Stat(filepath.Join(current, layout.ZarfWorkspace))
+		if err == nil {
+			return Read(current)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil, nil
+		}
+		current = parent
+	}
+}
+
+// PackageDirs returns the absolute directories of the packages in the workspace in the order they are listed.
+func (ws *Workspace) PackageDirs() []string {
+	dirs := []string{}
+	for _, path := range ws.Packages {
+		dirs = append(dirs, filepath.Join(ws.Base, path))
+	}
+	return dirs
+}
+
+// PackageDir returns the absolute directory of the workspace package with the given name.
+func (ws *Workspace) PackageDir(name string) (string, error) {
+	dir, ok := ws.names[name]
+	if !ok {
+		return "", fmt.Errorf("package %q is not in the workspace %s", name, ws.Base)
+	}
+	return dir, nil
+}
+
+// MergeVariables returns the shared workspace variables overridden by the given package template values.
+func (ws *Workspace) MergeVariables(setVariables map[string]string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range ws.Variables {
+		merged[strings.ToUpper(k)] = v
+	}
+	for k, v := range setVariables {
+		merged[k] = v
+	}
+	return merged
+}
+
+// ResolveImportPath resolves a component import path that references a workspace package by name to the path of that
+// package relative to dir, the directory of the importing package. Paths without the workspace prefix are returned as is.
+func ResolveImportPath(dir, path string) (string, error) {
+	name, ok := strings.CutPrefix(path, ImportPrefix)
+	if !ok {
+		return path, nil
+	}
+	ws, err := Find(dir)
+	if err != nil {
+		return "", err
+	}
+	if ws == nil {
+		return "", fmt.Errorf("unable to import %q as %s is not in a workspace", path, dir)
+	}
+	target, err := ws.PackageDir(name)
+	if err != nil {
+		return "", err
+	}
+	from, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(from, target)
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/layout"
+)
+
+func writeWorkspace(t *testing.T, workspace string, packages map[string]string) string {
+	t.Helper()
+
+	base := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(base, layout.ZarfWorkspace), []byte(workspace), 0o600))
+	for dir, name := range packages {
+		require.NoError(t, os.MkdirAll(filepath.Join(base, dir), 0o700))
+		zarfYAML := fmt.Sprintf("kind: ZarfPackageConfig\nmetadata:\n  name: %s\n", name)
+		require.NoError(t, os.WriteFile(filepath.Join(base, dir, layout.ZarfYAML), []byte(zarfYAML), 0o600))
+	}
+	return base
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		workspace   string
+		packages    map[string]string
+		expectedErr string
+	}{
+		{
+			name:      "valid workspace",
+			workspace: "packages:\n  - shared\n  - apps/podinfo\n",
+			packages:  map[string]string{"shared": "shared", "apps/podinfo": "podinfo"},
+		},
+		{
+			name:        "no packages",
+			workspace:   "variables:\n  REGISTRY: ghcr.io\n",
+			expectedErr: "does not list any packages",
+		},
+		{
+			name:        "duplicate path",
+			workspace:   "packages:\n  - shared\n  - ./shared\n",
+			packages:    map[string]string{"shared": "shared"},
+			expectedErr: `workspace package path "./shared" is listed more than once`,
+		},
+		{
+			name:        "duplicate name",
+			workspace:   "packages:\n  - one\n  - two\n",
+			packages:    map[string]string{"one": "podinfo", "two": "podinfo"},
+			expectedErr: `are both named "podinfo"`,
+		},
+		{
+			name:        "missing package",
+			workspace:   "packages:\n  - missing\n",
+			expectedErr: `unable to read workspace package "missing"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			base := writeWorkspace(t, tt.workspace, tt.packages)
+			ws, err := Read(base)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{filepath.Join(base, "shared"), filepath.Join(base, "apps", "podinfo")}, ws.PackageDirs())
+		})
+	}
+}
+
+func TestResolveImportPath(t *testing.T) {
+	t.Parallel()
+
+	base := writeWorkspace(t, "packages:\n  - shared\n  - apps/podinfo\n", map[string]string{"shared": "shared", "apps/podinfo": "podinfo"})
+	podinfo := filepath.Join(base, "apps", "podinfo")
+
+	path, err := ResolveImportPath(podinfo, "workspace:shared")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("..", "..", "shared"), path)
+
+	path, err = ResolveImportPath(podinfo, "../../shared")
+	require.NoError(t, err)
+	require.Equal(t, "../../shared", path)
+
+	_, err = ResolveImportPath(podinfo, "workspace:missing")
+	require.ErrorContains(t, err, `package "missing" is not in the workspace`)
+
+	_, err = ResolveImportPath(t.TempDir(), "workspace:shared")
+	require.ErrorContains(t, err, "is not in a workspace")
+}
+
+func TestMergeVariables(t *testing.T) {
+	t.Parallel()
+
+	ws := Workspace{Variables: map[string]string{"registry": "ghcr.io", "TAG": "1.0.0"}}
+	merged := ws.MergeVariables(map[string]string{"TAG": "2.0.0"})
+	require.Equal(t, map[string]string{"REGISTRY": "ghcr.io", "TAG": "2.0.0"}, merged)
+}
@@ -5,11 +5,14 @@
 package pki
 
 import (
+	"bytes"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
 	"net"
@@ -28,30 +31,141 @@ const org = "Zarf Cluster"
 // 13 months is the max length allowed by browsers.
 const validFor = time.Hour * 24 * 375
 
+// CertificateAuthority is a CA that server keypairs can be issued from.
+type CertificateAuthority struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	// chain holds the intermediates between the CA and the root that are served alongside issued certificates.
+	chain []*x509.Certificate
+	// bundle holds every certificate that clients need to trust to verify issued certificates.
+	bundle []byte
+}
+
 // GeneratePKI create a CA and signed server keypair.
 func GeneratePKI(host string, dnsNames ...string) (types.GeneratedPKI, error) {
-	results := types.GeneratedPKI{}
 	ca, caKey, err := generateCA(validFor)
 	if err != nil {
 		return types.GeneratedPKI{}, fmt.Errorf("unable to generate the ephemeral CA: %w", err)
 	}
-	hostCert, hostKey, err := generateCert(host, ca, caKey, validFor, dnsNames...)
+	authority := &CertificateAuthority{
+		cert:   ca,
+		key:    caKey,
+		bundle: encodeCertificates(ca),
+	}
+	return authority.Issue(host, dnsNames...)
+}
+
+// LoadCA parses a PEM encoded CA certificate and its private key, the certificate may be followed by the intermediates
+// and root that issued it so that an intermediate CA can be used.
+func LoadCA(certPEM, keyPEM []byte) (*CertificateAuthority, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the CA certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificates were found in the CA certificate")
+	}
+	ca := certs[0]
+	if !ca.IsCA || (ca.KeyUsage != 0 && ca.KeyUsage&x509.KeyUsageCertSign == 0) {
+		return nil, fmt.Errorf("the certificate for %q is not a CA that can sign certificates", ca.Subject.CommonName)
+	}
+	if time.Now().After(ca.NotAfter) {
+		return nil, fmt.Errorf("the CA certificate for %q expired at %s", ca.Subject.CommonName, ca.NotAfter)
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(ca.PublicKey) {
+		return nil, errors.New("the CA private key does not match the CA certificate")
+	}
+
+	authority := &CertificateAuthority{
+		cert:   ca,
+		key:    key,
+		bundle: encodeCertificates(certs...),
+	}
+	for _, cert := range certs {
+		if !isSelfSigned(cert) {
+			authority.chain = append(authority.chain, cert)
+		}
+	}
+	return authority, nil
+}
+
+// Bundle returns the PEM encoded certificates that clients must trust to verify certificates issued by the CA.
+func (ca *CertificateAuthority) Bundle() []byte {
+	return ca.bundle
+}
+
+// Issue creates a server keypair for host signed by the CA, the certificate includes any intermediates between it and
+// the root so that clients only need to trust the root.
+func (ca *CertificateAuthority) Issue(host string, dnsNames ...string) (types.GeneratedPKI, error) {
+	// Certificates can not outlive the CA that issued them.
+	hostCert, hostKey, err := generateCert(host, ca.cert, ca.key, min(validFor, time.Until(ca.cert.NotAfter)), dnsNames...)
 	if err != nil {
 		return types.GeneratedPKI{}, fmt.Errorf("unable to generate the cert for %s: %w", host, err)
 	}
-	results.CA = pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: ca.Raw,
-	})
-	results.Cert = pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: hostCert.Raw,
-	})
-	results.Key = pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(hostKey),
-	})
-	return results, nil
+	return types.GeneratedPKI{
+		CA:   ca.bundle,
+		Cert: encodeCertificates(append([]*x509.Certificate{hostCert}, ca.chain...)...),
+		Key: pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(hostKey),
+		}),
+	}, nil
+}
+
+func encodeCertificates(certs ...*x509.Certificate) []byte {
+	var out []byte
+	for _, cert := range certs {
+		out = append(out, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Raw,
+		})...)
+	}
+	return out
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+// parsePrivateKey parses a PEM encoded PKCS #1, PKCS #8 or EC private key.
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key was found in the CA key")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported CA private key type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the CA private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("the CA private key can not be used to sign certificates")
+	}
+	return signer, nil
 }
 
 // newCertificate creates a new template.
@@ -119,20 +233,26 @@ func generateCA(validFor time.Duration) (*x509.Certificate, *rsa.PrivateKey, err
 // generateCert generates a new certificate for the given host using the
 // provided certificate authority. The cert and key files are stored in
 // the provided files.
-func generateCert(host string, ca *x509.Certificate, caKey *rsa.PrivateKey, validFor time.Duration, dnsNames ...string) (*x509.Certificate, *rsa.PrivateKey, error) {
+func generateCert(host string, ca *x509.Certificate, caKey crypto.Signer, validFor time.Duration, dnsNames ...string) (*x509.Certificate, *rsa.PrivateKey, error) {
 	template, err := newCertificate(validFor)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	template.IPAddresses = append(template.IPAddresses, net.ParseIP(helpers.IPV4Localhost))
+	template.IPAddresses = append(template.IPAddresses, net.ParseIP(helpers.IPV4Localhost), net.IPv6loopback)
 
 	// Only use SANs to keep golang happy, https://go-review.googlesource.com/c/go/+/231379
 	if ip := net.ParseIP(host); ip != nil {
 		template.IPAddresses = append(template.IPAddresses, ip)
 	} else {
 		template.DNSNames = append(template.DNSNames, host)
-		template.DNSNames = append(template.DNSNames, dnsNames...)
+		for _, name := range dnsNames {
+			if ip := net.ParseIP(name); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+				continue
+			}
+			template.DNSNames = append(template.DNSNames, name)
+		}
 	}
 
 	template.Subject.CommonName = host
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCA creates a CA certificate signed by parent, or self-signed if parent is nil.
+func newTestCA(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func encodeKey(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestLoadCA(t *testing.T) {
+	t.Parallel()
+
+	root, rootKey := newTestCA(t, "root", nil, nil)
+	intermediate, intermediateKey := newTestCA(t, "intermediate", root, rootKey)
+
+	t.Run("root CA", func(t *testing.T) {
+		t.Parallel()
+
+		ca, err := LoadCA(encodeCertificates(root), encodeKey(t, rootKey))
+		require.NoError(t, err)
+		tls, err := ca.Issue("zarf-docker-registry.zarf.svc", "registry.example.com", "10.0.0.1")
+		require.NoError(t, err)
+		require.Equal(t, encodeCertificates(root), tls.CA)
+
+		block, rest := pem.Decode(tls.Cert)
+		require.Empty(t, rest)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		require.Equal(t, []string{"zarf-docker-registry.zarf.svc", "registry.example.com"}, cert.DNSNames)
+		require.Len(t, cert.IPAddresses, 3)
+		require.False(t, cert.NotAfter.After(root.NotAfter))
+
+		roots := x509.NewCertPool()
+		roots.AddCert(root)
+		_, err = cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "registry.example.com"})
+		require.NoError(t, err)
+	})
+
+	t.Run("intermediate CA with chain", func(t *testing.T) {
+		t.Parallel()
+
+		ca, err := LoadCA(encodeCertificates(intermediate, root), encodeKey(t, intermediateKey))
+		require.NoError(t, err)
+		tls, err := ca.Issue("agent-hook.zarf.svc")
+		require.NoError(t, err)
+		require.Equal(t, encodeCertificates(intermediate, root), tls.CA)
+
+		// The intermediate is served with the certificate so that only the root needs to be trusted.
+		block, rest := pem.Decode(tls.Cert)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		block, rest = pem.Decode(rest)
+		require.Empty(t, rest)
+		served, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		require.Equal(t, intermediate.Raw, served.Raw)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(root)
+		intermediates := x509.NewCertPool()
+		intermediates.AddCert(served)
+		_, err = cert.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, DNSName: "agent-hook.zarf.svc"})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid CAs", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := LoadCA(encodeCertificates(root), encodeKey(t, intermediateKey))
+		require.EqualError(t, err, "the CA private key does not match the CA certificate")
+
+		_, err = LoadCA([]byte("not a certificate"), encodeKey(t, rootKey))
+		require.EqualError(t, err, "no PEM encoded certificates were found in the CA certificate")
+
+		generated, err := GeneratePKI("example.com")
+		require.NoError(t, err)
+		_, err = LoadCA(generated.Cert, generated.Key)
+		require.EqualError(t, err, `the certificate for "example.com" is not a CA that can sign certificates`)
+	})
+}
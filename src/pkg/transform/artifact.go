@@ -49,11 +49,23 @@ func PipTransformURL(targetBaseURL string, sourceURL string) (*url.URL, error) {
 	return transformRegistryPath(targetBaseURL, sourceURL, pipURLRegex, "pipPath", "pypi")
 }
 
+// MavenTransformURL finds the Maven repository path on a given URL and transforms that to align with the offline registry.
+func MavenTransformURL(targetBaseURL string, sourceURL string) (*url.URL, error) {
+	// The repository prefixes of Maven Central, Nexus, Artifactory and Gitea are removed so that only the
+	// group/artifact/version/file path remains, other repositories are expected to serve that path from their root.
+	// This regex was created with information from https://maven.apache.org/repositories/layout.html
+	mavenURLRegex := regexp.MustCompile(`^(?P<proto>[a-z]+:\/\/)(?P<hostPath>.+?)` +
+		`(?P<repoPath>\/maven2|\/m2|\/(?:nexus\/)?(?:content\/(?:repositories|groups)|repository)\/[\w\-\.]+|\/artifactory\/[\w\-\.]+|\/api\/packages\/[\w\-\.]+\/maven)?` +
+		`(?P<mavenPath>(?:\/[\w\-\.]+){2,}\/(?:maven-metadata\.xml(?:\.\w+)?|[\w\-\.+]+\/[\w\-\.+]+))$`)
+
+	return transformRegistryPath(targetBaseURL, sourceURL, mavenURLRegex, "mavenPath", "maven")
+}
+
 // GenTransformURL finds the generic API path on a given URL and transforms that to align with the offline registry.
 func GenTransformURL(targetBaseURL string, sourceURL string) (*url.URL, error) {
 	// For further explanation: https://regex101.com/r/bwMkCm/5
 	// This regex was created with information from https://www.rfc-editor.org/rfc/rfc3986#section-2
-	genURLRegex := regexp.MustCompile(`^(?P<proto>[a-z]+:\/\/)(?P<host>[a-zA-Z0-9\-\.]+)(?P<port>:[0-9]+?)?(?P<startPath>\/[\w\-\.+~%]+?\/[\w\-\.+~%]+?)?(?P<midPath>\/.+?)??(?P<version>\/[\w\-\.+~%]+?)??(?P<fileName>\/[\w\-\.+~%]*)?(?P<query>[\w\-\.\?\=,;+~!$'*&%#()\[\]]*?)?$`)
+	genURLRegex := regexp.MustCompile(`^(?P<proto>[a-z]+:\/\/)(?P<host>[a-zA-Z0-9\-\.]+|\[[0-9a-fA-F:\.]+\])(?P<port>:[0-9]+?)?(?P<startPath>\/[\w\-\.+~%]+?\/[\w\-\.+~%]+?)?(?P<midPath>\/.+?)??(?P<version>\/[\w\-\.+~%]+?)??(?P<fileName>\/[\w\-\.+~%]*)?(?P<query>[\w\-\.\?\=,;+~!$'*&%#()\[\]]*?)?$`)
 
 	matches := genURLRegex.FindStringSubmatch(sourceURL)
 	idx := genURLRegex.SubexpIndex
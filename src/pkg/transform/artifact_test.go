@@ -5,6 +5,7 @@
 package transform
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -106,6 +107,47 @@ func TestPipTransformURL(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestMavenTransformURL(t *testing.T) {
+	protocolPaths := []string{
+		"/org/apache/commons/commons-lang3/maven-metadata.xml",
+		"/org/apache/commons/commons-lang3/maven-metadata.xml.sha1",
+		"/org/apache/commons/commons-lang3/3.14.0/commons-lang3-3.14.0.pom",
+		"/org/apache/commons/commons-lang3/3.14.0/commons-lang3-3.14.0.jar",
+		"/org/apache/commons/commons-lang3/3.14.0/commons-lang3-3.14.0.jar.sha256",
+		"/com/example/app/1.0.0-SNAPSHOT/maven-metadata.xml",
+		"/com/example/app/1.0.0-SNAPSHOT/app-1.0.0-20240101.120000-1-sources.jar",
+		"/junit/junit/4.13.2/junit-4.13.2.jar",
+	}
+
+	protocolHosts := []string{
+		"https://repo.maven.apache.org/maven2",
+		"https://repo1.maven.org/maven2",
+		"https://maven.google.com",
+		"https://nexus.example.com/repository/maven-public",
+		"https://nexus.example.com/nexus/content/repositories/releases",
+		"https://nexus.example.com/content/groups/public",
+		"https://example.jfrog.io/artifactory/libs-release",
+		"https://git.privatemirror.com/api/packages/zarf-mirror-user/maven",
+	}
+
+	for _, host := range protocolHosts {
+		for _, path := range protocolPaths {
+			newURL, err := MavenTransformURL("https://gitlab.com/project", host+path)
+			require.NoError(t, err)
+			// For each host/path swap them and add `maven` for compatibility with Gitea/Gitlab
+			require.Equal(t, "https://gitlab.com/project/maven"+path, newURL.String())
+		}
+	}
+
+	// Returns an error when the URL is not a Maven repository path
+	_, err := MavenTransformURL("https://gitlab.com/project", "https://repo.maven.apache.org/maven2/")
+	require.Error(t, err)
+
+	// Returns an error when given a bad base url
+	_, err = MavenTransformURL("https*://gitlab.com/project", "https://repo.maven.apache.org/maven2/junit/junit/4.13.2/junit-4.13.2.jar")
+	require.Error(t, err)
+}
+
 func TestGenTransformURL(t *testing.T) {
 	urls := []string{
 		"https://git.example.com/api/packages/zarf-git-user/generic",
@@ -155,3 +197,37 @@ func TestGenTransformURL(t *testing.T) {
 	_, err := GenTransformURL("https*://gitlab.com/project", "http://i.end.in.nothing.com")
 	require.Error(t, err)
 }
+
+func TestTransformURLIPv6(t *testing.T) {
+	// The git server is reached on an IPv6 address in brackets on IPv6 clusters
+	targetBaseURL := "http://[fd00::20]:3000/api/packages/zarf-git-user"
+
+	newURL, err := NpmTransformURL(targetBaseURL, "https://registry.npmjs.org/lodash/-/4.17.21/lodash-4.17.21.tgz")
+	require.NoError(t, err)
+	require.Equal(t, "http://[fd00::20]:3000/api/packages/zarf-git-user/npm/lodash/-/4.17.21/lodash-4.17.21.tgz", newURL.String())
+
+	newURL, err = PipTransformURL(targetBaseURL, "https://pypi.org/simple/numpy/")
+	require.NoError(t, err)
+	require.Equal(t, "http://[fd00::20]:3000/api/packages/zarf-git-user/pypi/simple/numpy/", newURL.String())
+
+	newURL, err = MavenTransformURL(targetBaseURL, "https://repo1.maven.org/maven2/org/slf4j/slf4j-api/2.0.7/slf4j-api-2.0.7.jar")
+	require.NoError(t, err)
+	require.Equal(t, "http://[fd00::20]:3000/api/packages/zarf-git-user/maven/org/slf4j/slf4j-api/2.0.7/slf4j-api-2.0.7.jar", newURL.String())
+
+	newURL, err = GenTransformURL(targetBaseURL, "https://example.com/files/app/1.0/app.zip")
+	require.NoError(t, err)
+	require.Equal(t, "http://[fd00::20]:3000/api/packages/zarf-git-user/generic/filesapp-2426359097/1.0/app.zip", newURL.String())
+
+	// Sources that are served from an IPv6 address resolve to the same package regardless of the port
+	newURL, err = GenTransformURL(targetBaseURL, "https://[2001:db8::1]:8443/files/app/1.0/app.zip")
+	require.NoError(t, err)
+	sameURL, err := GenTransformURL(targetBaseURL, "http://[2001:db8::1]/files/app/1.0/app.zip")
+	require.NoError(t, err)
+	require.Equal(t, newURL.String(), sameURL.String())
+	require.Contains(t, newURL.String(), "http://[fd00::20]:3000/api/packages/zarf-git-user/generic/filesapp-")
+	require.True(t, strings.HasSuffix(newURL.String(), "/1.0/app.zip"))
+
+	newURL, err = NoTransformTarget("http://[fd00::20]:3000", NoTransform+"/some-path/without-query")
+	require.NoError(t, err)
+	require.Equal(t, "http://[fd00::20]:3000/some-path/without-query", newURL.String())
+}
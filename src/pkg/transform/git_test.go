@@ -237,3 +237,19 @@ func TestGitURL(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestGitURLIPv6(t *testing.T) {
+	// The git server is reached on an IPv6 address in brackets on IPv6 clusters
+	repoURL, err := GitURL("http://[fd00::20]:3000", "https://github.com/zarf-dev/zarf.git", "repo-owner")
+	require.NoError(t, err)
+	require.Equal(t, "http://[fd00::20]:3000/repo-owner/zarf-4156197301.git", repoURL.String())
+
+	// Repositories that are served from an IPv6 address are transformed as well
+	repoURL, err = GitURL("http://[fd00::20]:3000", "http://[2001:db8::1]:8080/zarf-dev/zarf.git@v0.16.0", "repo-owner")
+	require.NoError(t, err)
+	require.Regexp(t, `^http://\[fd00::20\]:3000/repo-owner/zarf-[0-9]+\.git$`, repoURL.String())
+
+	dummyLogger := func(_ string, _ ...any) {}
+	resultingText := MutateGitURLsInText(dummyLogger, "http://[fd00::20]:3000", "stuff https://github.com/zarf-dev/zarf.git andthings", "repo-owner")
+	require.Equal(t, "stuff http://[fd00::20]:3000/repo-owner/zarf-4156197301.git andthings", resultingText)
+}
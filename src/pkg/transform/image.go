@@ -12,6 +12,20 @@ import (
 	"github.com/distribution/reference"
 )
 
+// Prefixes of image references that are loaded from a local container runtime instead of a registry.
+const (
+	DockerDaemonPrefix = "docker-daemon:"
+	PodmanPrefix       = "podman:"
+	ContainerdPrefix   = "containerd:"
+)
+
+// Local container runtimes that images can be loaded from.
+const (
+	DockerDaemon = "docker-daemon"
+	Podman       = "podman"
+	Containerd   = "containerd"
+)
+
 // Image represents a config for an OCI image.
 type Image struct {
 	Host        string
@@ -21,6 +35,23 @@ type Image struct {
 	Digest      string
 	Reference   string
 	TagOrDigest string
+	// Runtime is the local container runtime the image is loaded from, empty for images pulled from a registry.
+	Runtime string
+}
+
+// SplitImageRuntime returns the local container runtime of an image reference and the reference without its
+// runtime prefix, the runtime is empty for images that are pulled from a registry.
+func SplitImageRuntime(srcReference string) (string, string) {
+	for runtime, prefix := range map[string]string{
+		DockerDaemon: DockerDaemonPrefix,
+		Podman:       PodmanPrefix,
+		Containerd:   ContainerdPrefix,
+	} {
+		if ref, ok := strings.CutPrefix(srcReference, prefix); ok {
+			return runtime, ref
+		}
+	}
+	return "", srcReference
 }
 
 // ImageTransformHost replaces the base url for an image and adds a crc32 of the original url to the end of the src (note image refs are not full URLs).
@@ -61,8 +92,35 @@ func ImageTransformHostWithoutChecksum(targetHost, srcReference string) (string,
 	return fmt.Sprintf("%s/%s%s", targetHost, image.Path, image.TagOrDigest), nil
 }
 
+// ImageMirrorHost replaces the registry host of an image or oci:// reference with the mirror that mirrors maps the host
+// to, returning the reference unchanged if the host is not mirrored. A mirror can include a path that the repositories
+// of the host are nested under (e.g. mirror.internal/docker.io).
+func ImageMirrorHost(mirrors map[string]string, srcReference string) (string, error) {
+	if len(mirrors) == 0 {
+		return srcReference, nil
+	}
+	if runtime, _ := SplitImageRuntime(srcReference); runtime != "" {
+		return srcReference, nil
+	}
+	image, err := ParseImageRef(srcReference)
+	if err != nil {
+		return "", err
+	}
+	mirror, ok := mirrors[image.Host]
+	if !ok {
+		return srcReference, nil
+	}
+
+	prefix := ""
+	if strings.HasPrefix(srcReference, helpers.OCIURLPrefix) {
+		prefix = helpers.OCIURLPrefix
+	}
+	return fmt.Sprintf("%s%s/%s%s", prefix, strings.TrimSuffix(mirror, "/"), image.Path, image.TagOrDigest), nil
+}
+
 // ParseImageRef parses a source reference into an Image struct
 func ParseImageRef(srcReference string) (Image, error) {
+	runtime, srcReference := SplitImageRuntime(srcReference)
 	srcReference = strings.TrimPrefix(srcReference, helpers.OCIURLPrefix)
 
 	ref, err := reference.ParseAnyReference(srcReference)
@@ -81,6 +139,7 @@ func ParseImageRef(srcReference string) (Image, error) {
 		Path:      reference.Path(named),
 		Host:      reference.Domain(named),
 		Reference: ref.String(),
+		Runtime:   runtime,
 	}
 
 	// TODO(mkcp): This rewriting tag and digest code could probably be consolidated with types
@@ -54,6 +54,26 @@ func TestImageTransformHost(t *testing.T) {
 	}
 }
 
+func TestImageTransformHostIPv6(t *testing.T) {
+	// The cluster IP of the registry service is an IPv6 address in brackets on IPv6 clusters
+	newRef, err := ImageTransformHost("[fd00::10]:5000", "ghcr.io/stefanprodan/podinfo:6.3.3")
+	require.NoError(t, err)
+	require.Equal(t, "[fd00::10]:5000/stefanprodan/podinfo:6.3.3-zarf-2985051089", newRef)
+
+	image, err := ParseImageRef("oci://" + newRef)
+	require.NoError(t, err)
+	require.Equal(t, "[fd00::10]:5000", image.Host)
+	require.Equal(t, "stefanprodan/podinfo", image.Path)
+
+	// References that were already transformed are not transformed again
+	sameRef, err := ImageTransformHost("[fd00::10]:5000", newRef)
+	require.NoError(t, err)
+	require.Equal(t, newRef, sameRef)
+	sameRef, err = ImageTransformHostWithoutChecksum("[fd00::10]:5000", newRef)
+	require.NoError(t, err)
+	require.Equal(t, newRef, sameRef)
+}
+
 func TestImageTransformHostWithoutChecksum(t *testing.T) {
 	var expectedResult = []string{
 		"gitlab.com/project/library/nginx:latest",
@@ -124,3 +144,73 @@ func TestParseImageRef(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestParseImageRefRuntime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref       string
+		runtime   string
+		reference string
+	}{
+		{ref: "docker-daemon:podinfo:local", runtime: DockerDaemon, reference: "docker.io/library/podinfo:local"},
+		{ref: "podman:localhost/podinfo:local", runtime: Podman, reference: "localhost/podinfo:local"},
+		{ref: "containerd:ghcr.io/stefanprodan/podinfo", runtime: Containerd, reference: "ghcr.io/stefanprodan/podinfo:latest"},
+		{ref: "ghcr.io/stefanprodan/podinfo:6.3.3", reference: "ghcr.io/stefanprodan/podinfo:6.3.3"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.ref, func(t *testing.T) {
+			t.Parallel()
+
+			img, err := ParseImageRef(tt.ref)
+			require.NoError(t, err)
+			require.Equal(t, tt.runtime, img.Runtime)
+			require.Equal(t, tt.reference, img.Reference)
+		})
+	}
+
+	// Images loaded from a runtime are pushed to the same location as images pulled from a registry.
+	newRef, err := ImageTransformHostWithoutChecksum("gitlab.com/project", "docker-daemon:podinfo:local")
+	require.NoError(t, err)
+	require.Equal(t, "gitlab.com/project/library/podinfo:local", newRef)
+}
+
+func TestImageMirrorHost(t *testing.T) {
+	t.Parallel()
+
+	mirrors := map[string]string{
+		"docker.io":            "mirror.enterprise.intranet/docker.io/",
+		"ghcr.io":              "mirror.enterprise.intranet/ghcr.io",
+		"10.43.130.183:5000":   "localhost:5000",
+		"registry.example.com": "mirror.internal",
+	}
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{ref: "nginx", expected: "mirror.enterprise.intranet/docker.io/library/nginx:latest"},
+		{ref: "ghcr.io/stefanprodan/podinfo:6.3.3", expected: "mirror.enterprise.intranet/ghcr.io/stefanprodan/podinfo:6.3.3"},
+		{ref: "oci://registry.example.com/packages/podinfo:0.0.1", expected: "oci://mirror.internal/packages/podinfo:0.0.1"},
+		{ref: "oci://10.43.130.183:5000/packages/podinfo@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79", expected: "oci://localhost:5000/packages/podinfo@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79"},
+		{ref: "registry1.dso.mil/ironbank/opensource/zarf-dev/zarf/zarf-agent:v0.25.0", expected: "registry1.dso.mil/ironbank/opensource/zarf-dev/zarf/zarf-agent:v0.25.0"},
+		{ref: "docker-daemon:podinfo:local", expected: "docker-daemon:podinfo:local"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.ref, func(t *testing.T) {
+			t.Parallel()
+
+			ref, err := ImageMirrorHost(mirrors, tt.ref)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, ref)
+
+			ref, err = ImageMirrorHost(nil, tt.ref)
+			require.NoError(t, err)
+			require.Equal(t, tt.ref, ref)
+		})
+	}
+
+	_, err := ImageMirrorHost(mirrors, "http://urls.are/not/refs")
+	require.Error(t, err)
+}
@@ -9,7 +9,9 @@ package utils
 import (
 	"fmt"
 	"math"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -81,6 +83,48 @@ func ByteFormat(in float64, precision int) string {
 	return vFmt + " " + u
 }
 
+var byteSizeRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-zA-Z]*)$`)
+
+// byteSizeUnits maps the lowercase units accepted by ParseByteSize to their size in bytes.
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+}
+
+// ParseByteSize parses a human-readable size such as 4GB, 512MiB or 1024 into a number of bytes. Decimal units (KB,
+// MB, GB, TB) are powers of 1000 and binary units (KiB, MiB, GiB, TiB) are powers of 1024.
+func ParseByteSize(size string) (int64, error) {
+	matches := byteSizeRegex.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	multiplier, ok := byteSizeUnits[strings.ToLower(matches[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", size, matches[2])
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", size, err)
+	}
+	return int64(value * multiplier), nil
+}
+
 // RenderProgressBarForLocalDirWrite creates a progress bar that continuously tracks the progress of writing files to a local directory and all of its subdirectories.
 // NOTE: This function runs infinitely until either completeChan or errChan is triggered, this function should be run in a goroutine while a different thread/process is writing to the directory.
 func RenderProgressBarForLocalDirWrite(filepath string, expectedTotal int64, completeChan chan error, updateText string, successText string) {
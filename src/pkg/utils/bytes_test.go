@@ -76,3 +76,69 @@ func TestByteFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestParseByteSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		size        string
+		expected    int64
+		expectedErr string
+	}{
+		{
+			name:     "bytes",
+			size:     "1024",
+			expected: 1024,
+		},
+		{
+			name:     "decimal units",
+			size:     "4GB",
+			expected: 4000000000,
+		},
+		{
+			name:     "binary units",
+			size:     "512MiB",
+			expected: 512 * 1024 * 1024,
+		},
+		{
+			name:     "lowercase units with a space and a fraction",
+			size:     "1.5 gb",
+			expected: 1500000000,
+		},
+		{
+			name:     "unit without a byte suffix",
+			size:     "2Ki",
+			expected: 2048,
+		},
+		{
+			name:        "unknown unit",
+			size:        "4PB",
+			expectedErr: `invalid size "4PB": unknown unit "PB"`,
+		},
+		{
+			name:        "negative",
+			size:        "-1GB",
+			expectedErr: `invalid size "-1GB"`,
+		},
+		{
+			name:        "empty",
+			size:        "",
+			expectedErr: `invalid size ""`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			size, err := ParseByteSize(tt.size)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, size)
+		})
+	}
+}
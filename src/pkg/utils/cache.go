@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+)
+
+// CacheGroupOther is the group of cache entries that are not part of a content-addressed cache directory.
+const CacheGroupOther = "other"
+
+// CacheEntry is a single item in the Zarf cache that can be pruned on its own.
+type CacheEntry struct {
+	Path    string
+	Group   string
+	Size    int64
+	ModTime time.Time
+}
+
+// ListCacheEntries lists the entries in the cache directory.
+//
+// Every child of a group directory (e.g. a single image layer or git repo) is its own entry, while any other top level
+// item is an entry in the CacheGroupOther group. A cache directory that does not exist has no entries.
+func ListCacheEntries(cacheDir string, groups ...string) ([]CacheEntry, error) {
+	top, err := os.ReadDir(cacheDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []CacheEntry{}
+	for _, de := range top {
+		path := filepath.Join(cacheDir, de.Name())
+		if de.IsDir() && slices.Contains(groups, de.Name()) {
+			children, err := os.ReadDir(path)
+			if err != nil {
+				return nil, err
+			}
+			for _, child := range children {
+				entry, err := newCacheEntry(filepath.Join(path, child.Name()), de.Name())
+				if err != nil {
+					return nil, err
+				}
+				entries = append(entries, entry)
+			}
+			continue
+		}
+		entry, err := newCacheEntry(path, CacheGroupOther)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func newCacheEntry(path, group string) (CacheEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	size := info.Size()
+	if info.IsDir() {
+		size, err = helpers.GetDirSize(path)
+		if err != nil {
+			return CacheEntry{}, err
+		}
+	}
+	return CacheEntry{Path: path, Group: group, Size: size, ModTime: info.ModTime()}, nil
+}
+
+// PruneCacheEntries removes the entries last used before olderThan and then the least recently used entries until the
+// remaining entries take up at most maxSize bytes, returning the removed entries.
+//
+// A zero olderThan or a negative maxSize disables that limit.
+func PruneCacheEntries(entries []CacheEntry, olderThan time.Time, maxSize int64) ([]CacheEntry, error) {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b CacheEntry) int {
+		return a.ModTime.Compare(b.ModTime)
+	})
+
+	total := int64(0)
+	for _, entry := range sorted {
+		total += entry.Size
+	}
+
+	removed := []CacheEntry{}
+	for _, entry := range sorted {
+		expired := !olderThan.IsZero() && entry.ModTime.Before(olderThan)
+		oversize := maxSize >= 0 && total > maxSize
+		if !expired && !oversize {
+			continue
+		}
+		if err := os.RemoveAll(entry.Path); err != nil {
+			return removed, err
+		}
+		total -= entry.Size
+		removed = append(removed, entry)
+	}
+	return removed, nil
+}
+
+// TouchCacheEntry marks a cache entry as used so that it is pruned after less recently used entries.
+func TouchCacheEntry(path string) error {
+	now := time.Now()
+	return os.Chtimes(path, now, now)
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneCacheEntries(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	writeEntry := func(t *testing.T, path string, size int, age time.Duration) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0o600))
+		require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+	}
+	setup := func(t *testing.T) (string, []CacheEntry) {
+		t.Helper()
+		dir := t.TempDir()
+		writeEntry(t, filepath.Join(dir, "images", "sha256:old"), 100, 48*time.Hour)
+		writeEntry(t, filepath.Join(dir, "images", "sha256:new"), 100, time.Minute)
+		writeEntry(t, filepath.Join(dir, "repos", "podinfo-1234", "HEAD"), 50, time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "repos", "podinfo-1234"), now.Add(-time.Hour), now.Add(-time.Hour)))
+		writeEntry(t, filepath.Join(dir, "zarf-init-amd64.tar.zst"), 10, 24*time.Hour)
+
+		entries, err := ListCacheEntries(dir, "images", "repos")
+		require.NoError(t, err)
+		require.Len(t, entries, 4)
+		return dir, entries
+	}
+
+	tests := []struct {
+		name            string
+		olderThan       time.Time
+		maxSize         int64
+		expectedRemoved []string
+	}{
+		{
+			name:            "no limits",
+			maxSize:         -1,
+			expectedRemoved: []string{},
+		},
+		{
+			name:            "max age",
+			olderThan:       now.Add(-12 * time.Hour),
+			maxSize:         -1,
+			expectedRemoved: []string{"images/sha256:old", "zarf-init-amd64.tar.zst"},
+		},
+		{
+			name:            "max size",
+			maxSize:         140,
+			expectedRemoved: []string{"images/sha256:old", "zarf-init-amd64.tar.zst", "repos/podinfo-1234"},
+		},
+		{
+			name:            "zero size",
+			maxSize:         0,
+			expectedRemoved: []string{"images/sha256:old", "zarf-init-amd64.tar.zst", "repos/podinfo-1234", "images/sha256:new"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir, entries := setup(t)
+			removed, err := PruneCacheEntries(entries, tt.olderThan, tt.maxSize)
+			require.NoError(t, err)
+
+			removedPaths := []string{}
+			for _, entry := range removed {
+				rel, err := filepath.Rel(dir, entry.Path)
+				require.NoError(t, err)
+				removedPaths = append(removedPaths, filepath.ToSlash(rel))
+				require.NoFileExists(t, entry.Path)
+			}
+			require.Equal(t, tt.expectedRemoved, removedPaths)
+
+			remaining, err := ListCacheEntries(dir, "images", "repos")
+			require.NoError(t, err)
+			require.Len(t, remaining, len(entries)-len(removed))
+		})
+	}
+}
@@ -5,11 +5,15 @@
 package utils
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -20,8 +24,10 @@ import (
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/pkcs11key"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
 
 	// Register the provider-specific plugins
 	_ "github.com/sigstore/sigstore/pkg/signature/kms/aws"
@@ -31,6 +37,7 @@ import (
 
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 )
 
 const (
@@ -178,6 +185,9 @@ func Sget(ctx context.Context, image, key string, out io.Writer) error {
 
 // CosignVerifyBlob verifies the zarf.yaml.sig was signed with the key provided by the flag
 func CosignVerifyBlob(ctx context.Context, blobRef, sigRef, keyPath string) error {
+	if err := ValidateKeyRef(keyPath); err != nil {
+		return err
+	}
 	keyOptions := options.KeyOpts{KeyRef: keyPath}
 	cmd := &verify.VerifyBlobCmd{
 		KeyOpts:    keyOptions,
@@ -195,8 +205,35 @@ func CosignVerifyBlob(ctx context.Context, blobRef, sigRef, keyPath string) erro
 	return nil
 }
 
-// CosignSignBlob signs the provide binary and returns the signature
+// IsKMSKeyRef returns true if the key reference is a URI for a key held in a KMS or a PKCS#11 token instead of a path to
+// a key file.
+func IsKMSKeyRef(keyRef string) bool {
+	if strings.HasPrefix(keyRef, pkcs11key.ReferenceScheme) {
+		return true
+	}
+	for _, provider := range kms.SupportedProviders() {
+		if strings.HasPrefix(keyRef, provider) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateKeyRef returns an error if the key reference can not be used by this build of Zarf.
+func ValidateKeyRef(keyRef string) error {
+	if strings.HasPrefix(keyRef, pkcs11key.ReferenceScheme) && !pkcs11Supported {
+		return errors.New("PKCS#11 keys require a Zarf binary built with cgo and the pkcs11key build tag")
+	}
+	return nil
+}
+
+// CosignSignBlob signs the provide binary and returns the signature, the key may be a key file or a KMS or PKCS#11 key
+// URI in which case the key never leaves the KMS or token
 func CosignSignBlob(blobPath, outputSigPath, keyPath string, passFn cosign.PassFunc) ([]byte, error) {
+	if err := ValidateKeyRef(keyPath); err != nil {
+		return []byte{}, err
+	}
+
 	rootOptions := &options.RootOptions{
 		Verbose: false,
 		Timeout: options.DefaultTimeout,
@@ -222,8 +259,138 @@ func CosignSignBlob(blobPath, outputSigPath, keyPath string, passFn cosign.PassF
 	return sig, nil
 }
 
-// GetCosignArtifacts returns signatures and attestations for the given image
-func GetCosignArtifacts(image string) ([]string, error) {
+// CosignKeylessOptions configures signing with a short-lived certificate issued by Fulcio for an OIDC identity,
+// the public Sigstore instances are used for any URL that is not set.
+type CosignKeylessOptions struct {
+	FulcioURL  string
+	RekorURL   string
+	OIDCIssuer string
+	IDToken    string
+	// SkipConfirmation skips the prompt to consent to the identity being recorded in the public transparency log
+	SkipConfirmation bool
+}
+
+// CosignSignBlobKeyless signs the provided binary with a certificate from Fulcio, records the signature in Rekor and
+// writes the signature and a bundle containing the certificate and transparency log entry needed to verify it offline.
+func CosignSignBlobKeyless(blobPath, outputSigPath, outputBundlePath string, opts CosignKeylessOptions) ([]byte, error) {
+	rootOptions := &options.RootOptions{
+		Verbose: false,
+		Timeout: options.DefaultTimeout,
+	}
+
+	keyOptions := options.KeyOpts{
+		FulcioURL:        cmp.Or(opts.FulcioURL, options.DefaultFulcioURL),
+		RekorURL:         cmp.Or(opts.RekorURL, options.DefaultRekorURL),
+		OIDCIssuer:       cmp.Or(opts.OIDCIssuer, options.DefaultOIDCIssuerURL),
+		OIDCClientID:     "sigstore",
+		IDToken:          opts.IDToken,
+		BundlePath:       outputBundlePath,
+		SkipConfirmation: opts.SkipConfirmation,
+	}
+
+	return sign.SignBlobCmd(
+		rootOptions,
+		keyOptions,
+		blobPath,
+		cosignB64Enabled,
+		outputSigPath,
+		cosignOutputCertificate,
+		true)
+}
+
+// The files of a trusted root directory, named like the targets of the Sigstore TUF repository.
+const (
+	TrustedRootFulcioChain = "fulcio.crt.pem"
+	TrustedRootRekorKey    = "rekor.pub"
+	TrustedRootCTLogKey    = "ctfe.pub"
+)
+
+// trustedRootMu guards the environment that points cosign at the keys of a trusted root.
+var trustedRootMu sync.Mutex
+
+// CosignVerifyBlobKeyless verifies a blob against a bundle created by CosignSignBlobKeyless, requiring that the
+// certificate was issued to the given identity by the given OIDC issuer.
+//
+// The certificate is checked against the public Sigstore trust root, which is downloaded the first time it is needed,
+// unless trustedRoot is a directory with the Fulcio certificate chain and the Rekor and CT log public keys to check it
+// against, such as those of a private Sigstore instance or a copy of the public ones for air-gapped environments.
+func CosignVerifyBlobKeyless(ctx context.Context, blobRef, bundleRef, certIdentity, certOIDCIssuer, trustedRoot string) error {
+	cmd := &verify.VerifyBlobCmd{
+		KeyOpts: options.KeyOpts{BundlePath: bundleRef},
+		CertVerifyOptions: options.CertVerifyOptions{
+			CertIdentity:   certIdentity,
+			CertOidcIssuer: certOIDCIssuer,
+		},
+		// The bundle contains the signed transparency log entry so Rekor does not need to be reachable
+		Offline: true,
+	}
+
+	if trustedRoot != "" {
+		paths := map[string]string{}
+		for _, name := range []string{TrustedRootFulcioChain, TrustedRootRekorKey, TrustedRootCTLogKey} {
+			path := filepath.Join(trustedRoot, name)
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("unable to read the trusted root: %w", err)
+			}
+			paths[name] = path
+		}
+		cmd.CertChain = paths[TrustedRootFulcioChain]
+
+		// Cosign only reads alternate Rekor and CT log keys from the environment
+		trustedRootMu.Lock()
+		defer trustedRootMu.Unlock()
+		for key, name := range map[string]string{
+			"SIGSTORE_REKOR_PUBLIC_KEY":       TrustedRootRekorKey,
+			"SIGSTORE_CT_LOG_PUBLIC_KEY_FILE": TrustedRootCTLogKey,
+		} {
+			old, ok := os.LookupEnv(key)
+			if err := os.Setenv(key, paths[name]); err != nil {
+				return err
+			}
+			defer func() {
+				if ok {
+					_ = os.Setenv(key, old)
+				} else {
+					_ = os.Unsetenv(key)
+				}
+			}()
+		}
+	}
+
+	err := cmd.Exec(ctx, blobRef)
+	if err != nil {
+		return err
+	}
+
+	message.Successf("Package signature validated for %s!", certIdentity)
+	return nil
+}
+
+// IsCosignArtifact returns true if the image reference points to a cosign signature, attestation, or SBOM tag.
+func IsCosignArtifact(image string) bool {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return false
+	}
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return false
+	}
+	t := tag.TagStr()
+	if !strings.HasPrefix(t, "sha256-") {
+		return false
+	}
+	return strings.HasSuffix(t, ".sig") || strings.HasSuffix(t, ".att") || strings.HasSuffix(t, ".sbom")
+}
+
+// GetCosignArtifacts returns signatures and attestations for the given image, looking them up in the registry mirror of
+// the image's host if it has one in mirrors
+func GetCosignArtifacts(image string, mirrors map[string]string) ([]string, error) {
+	// Images loaded from a local container runtime are not signed in a registry.
+	if runtime, _ := transform.SplitImageRuntime(image); runtime != "" {
+		return []string{}, nil
+	}
+
 	var nameOpts []name.Option
 
 	ref, err := name.ParseReference(image, nameOpts...)
@@ -231,15 +398,27 @@ func GetCosignArtifacts(image string) ([]string, error) {
 		return []string{}, err
 	}
 
+	mirrored, err := transform.ImageMirrorHost(mirrors, image)
+	if err != nil {
+		return []string{}, err
+	}
+	lookupRef, err := name.ParseReference(mirrored, nameOpts...)
+	if err != nil {
+		return []string{}, err
+	}
+
 	var remoteOpts []ociremote.Option
-	simg, _ := ociremote.SignedEntity(ref, remoteOpts...)
+	simg, _ := ociremote.SignedEntity(lookupRef, remoteOpts...)
 	if simg == nil {
 		return []string{}, nil
 	}
 
-	// Errors are dogsled because these functions always return a name.Tag which we can check for layers
-	sigRef, _ := ociremote.SignatureTag(ref, remoteOpts...)
-	attRef, _ := ociremote.AttestationTag(ref, remoteOpts...)
+	// Errors are dogsled because these functions always return a name.Tag which we can check for layers, the artifacts
+	// are named after the image rather than its mirror
+	sigTag, _ := ociremote.SignatureTag(lookupRef, remoteOpts...)
+	attTag, _ := ociremote.AttestationTag(lookupRef, remoteOpts...)
+	sigRef := ref.Context().Tag(sigTag.TagStr())
+	attRef := ref.Context().Tag(attTag.TagStr())
 
 	ss, err := simg.Signatures()
 	if err != nil {
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsKMSKeyRef(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		keyRef   string
+		expected bool
+	}{
+		{keyRef: "cosign.key", expected: false},
+		{keyRef: "/home/zarf/keys/awskms.key", expected: false},
+		{keyRef: "awskms:///alias/zarf-signing", expected: true},
+		{keyRef: "gcpkms://projects/zarf/locations/global/keyRings/zarf/cryptoKeys/signing", expected: true},
+		{keyRef: "azurekms://zarf-vault.vault.azure.net/signing", expected: true},
+		{keyRef: "hashivault://zarf-signing", expected: true},
+		{keyRef: "pkcs11:token=zarf;object=signing", expected: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.keyRef, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, IsKMSKeyRef(tt.keyRef))
+		})
+	}
+}
+
+func TestValidateKeyRef(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateKeyRef("cosign.key"))
+	require.NoError(t, ValidateKeyRef("awskms:///alias/zarf-signing"))
+	err := ValidateKeyRef("pkcs11:token=zarf;object=signing")
+	if pkcs11Supported {
+		require.NoError(t, err)
+		return
+	}
+	require.EqualError(t, err, "PKCS#11 keys require a Zarf binary built with cgo and the pkcs11key build tag")
+}
@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// encryptionMagic identifies a file encrypted by EncryptFile and the version of the format.
+	encryptionMagic = "zarf-encrypted-v1\n"
+	// encryptionChunkSize is the size of the plaintext chunks that are sealed individually so that large packages can
+	// be streamed instead of held in memory.
+	encryptionChunkSize = 64 * 1024
+	encryptionSaltSize  = 16
+)
+
+// ErrDecrypt is returned when an encrypted file cannot be decrypted because the secret is wrong or the file has been
+// modified or truncated.
+var ErrDecrypt = errors.New("unable to decrypt, the key or passphrase is incorrect or the file is corrupted")
+
+// ReadEncryptionSecret returns the secret to encrypt or decrypt with from either a passphrase or a key file, returning
+// nil if neither is provided.
+func ReadEncryptionSecret(passphrase, keyPath string) ([]byte, error) {
+	if passphrase != "" && keyPath != "" {
+		return nil, errors.New("only one of an encryption key file or passphrase can be provided")
+	}
+	if keyPath != "" {
+		b, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the encryption key file: %w", err)
+		}
+		secret := bytes.TrimSpace(b)
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("the encryption key file %s is empty", keyPath)
+		}
+		return secret, nil
+	}
+	if passphrase != "" {
+		return []byte(passphrase), nil
+	}
+	return nil, nil
+}
+
+// IsEncrypted returns if the file at path was encrypted by EncryptFile.
+func IsEncrypted(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, len(encryptionMagic))
+	if _, err := io.ReadFull(f, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return string(header) == encryptionMagic, nil
+}
+
+// EncryptFile encrypts src into dst with AES-256-GCM using a key derived from secret with scrypt.
+//
+// The plaintext is sealed in fixed size chunks whose nonces hold the chunk number and whether it is the final chunk so
+// that reordered, dropped or truncated chunks fail to decrypt.
+func EncryptFile(src, dst string, secret []byte) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, out.Close())
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := newEncryptionAEAD(secret, salt)
+	if err != nil {
+		return err
+	}
+	if _, err := out.WriteString(encryptionMagic); err != nil {
+		return err
+	}
+	if _, err := out.Write(salt); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(in)
+	buf := make([]byte, encryptionChunkSize)
+	for counter := uint64(0); ; counter++ {
+		n, last, err := readChunk(r, buf)
+		if err != nil {
+			return err
+		}
+		if _, err := out.Write(aead.Seal(nil, chunkNonce(aead, counter, last), buf[:n], nil)); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// DecryptFile decrypts src, which was encrypted by EncryptFile, into dst.
+func DecryptFile(src, dst string, secret []byte) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	r, err := NewDecryptReader(in, secret)
+	if err != nil {
+		return fmt.Errorf("unable to decrypt %s: %w", src, err)
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, out.Close())
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	_, err = io.Copy(out, r)
+	return err
+}
+
+// NewDecryptReader returns a reader of the plaintext of in, which was encrypted by EncryptFile.
+//
+// Every chunk is authenticated before it is returned and a stream that was truncated fails with ErrDecrypt once the
+// end is reached, so the plaintext must not be trusted until the reader has returned io.EOF.
+func NewDecryptReader(in io.Reader, secret []byte) (io.Reader, error) {
+	r := bufio.NewReader(in)
+	header := make([]byte, len(encryptionMagic)+encryptionSaltSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrDecrypt
+	}
+	if !strings.HasPrefix(string(header), encryptionMagic) {
+		return nil, errors.New("the file is not encrypted")
+	}
+	aead, err := newEncryptionAEAD(secret, header[len(encryptionMagic):])
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: r, aead: aead, buf: make([]byte, encryptionChunkSize+aead.Overhead())}, nil
+}
+
+// decryptReader decrypts the chunks of a file encrypted by EncryptFile as they are read.
+type decryptReader struct {
+	r         *bufio.Reader
+	aead      cipher.AEAD
+	buf       []byte
+	counter   uint64
+	plaintext []byte
+	done      bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plaintext) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		n, last, err := readChunk(d.r, d.buf)
+		if err != nil {
+			return 0, err
+		}
+		d.plaintext, err = d.aead.Open(d.buf[:0], chunkNonce(d.aead, d.counter, last), d.buf[:n], nil)
+		if err != nil {
+			return 0, ErrDecrypt
+		}
+		d.counter++
+		d.done = last
+	}
+	n := copy(p, d.plaintext)
+	d.plaintext = d.plaintext[n:]
+	return n, nil
+}
+
+func newEncryptionAEAD(secret, salt []byte) (cipher.AEAD, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("an encryption key or passphrase must be provided")
+	}
+	key, err := scrypt.Key(secret, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readChunk fills buf from r, returning how much was read and if this was the final chunk of the stream.
+func readChunk(r *bufio.Reader, buf []byte) (int, bool, error) {
+	n, err := io.ReadFull(r, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return n, true, nil
+	}
+	if err != nil {
+		return n, false, err
+	}
+	if _, err := r.Peek(1); errors.Is(err, io.EOF) {
+		return n, true, nil
+	} else if err != nil {
+		return n, false, err
+	}
+	return n, false, nil
+}
+
+// chunkNonce returns the nonce for a chunk, every file is sealed with a unique key from its random salt so a counter
+// is safe to use as the nonce.
+func chunkNonce(aead cipher.AEAD, counter uint64, last bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-9:], counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
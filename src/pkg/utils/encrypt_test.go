@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptFile(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("correct horse battery staple")
+	large := make([]byte, 3*encryptionChunkSize+42)
+	_, err := rand.Read(large)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{
+			name:    "empty",
+			content: []byte{},
+		},
+		{
+			name:    "smaller than a chunk",
+			content: []byte("zarf package"),
+		},
+		{
+			name:    "exactly one chunk",
+			content: bytes.Repeat([]byte("z"), encryptionChunkSize),
+		},
+		{
+			name:    "multiple chunks",
+			content: large,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			src := filepath.Join(dir, "package.tar.zst")
+			enc := filepath.Join(dir, "package.enc")
+			dec := filepath.Join(dir, "package.dec")
+			require.NoError(t, os.WriteFile(src, tt.content, 0o600))
+
+			require.NoError(t, EncryptFile(src, enc, secret))
+			encrypted, err := IsEncrypted(enc)
+			require.NoError(t, err)
+			require.True(t, encrypted)
+			encrypted, err = IsEncrypted(src)
+			require.NoError(t, err)
+			require.False(t, encrypted)
+
+			require.NoError(t, DecryptFile(enc, dec, secret))
+			b, err := os.ReadFile(dec)
+			require.NoError(t, err)
+			require.Equal(t, tt.content, b)
+
+			f, err := os.Open(enc)
+			require.NoError(t, err)
+			defer f.Close()
+			r, err := NewDecryptReader(f, secret)
+			require.NoError(t, err)
+			b, err = io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, tt.content, b)
+
+			require.ErrorIs(t, DecryptFile(enc, dec, []byte("wrong")), ErrDecrypt)
+			require.NoFileExists(t, dec)
+		})
+	}
+}
+
+func TestDecryptFileTampered(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+	dir := t.TempDir()
+	src := filepath.Join(dir, "package.tar")
+	enc := filepath.Join(dir, "package.enc")
+	require.NoError(t, os.WriteFile(src, bytes.Repeat([]byte("z"), 2*encryptionChunkSize+1), 0o600))
+	require.NoError(t, EncryptFile(src, enc, secret))
+	b, err := os.ReadFile(enc)
+	require.NoError(t, err)
+
+	// Dropping the final chunk must not decrypt to a shorter package.
+	truncated := filepath.Join(dir, "truncated.enc")
+	require.NoError(t, os.WriteFile(truncated, b[:len(b)-17], 0o600))
+	require.ErrorIs(t, DecryptFile(truncated, filepath.Join(dir, "out"), secret), ErrDecrypt)
+
+	modified := filepath.Join(dir, "modified.enc")
+	b[len(b)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(modified, b, 0o600))
+	require.ErrorIs(t, DecryptFile(modified, filepath.Join(dir, "out"), secret), ErrDecrypt)
+}
+
+func TestReadEncryptionSecret(t *testing.T) {
+	t.Parallel()
+
+	keyPath := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyPath, []byte("from-file\n"), 0o600))
+
+	secret, err := ReadEncryptionSecret("", keyPath)
+	require.NoError(t, err)
+	require.Equal(t, []byte("from-file"), secret)
+
+	secret, err = ReadEncryptionSecret("passphrase", "")
+	require.NoError(t, err)
+	require.Equal(t, []byte("passphrase"), secret)
+
+	secret, err = ReadEncryptionSecret("", "")
+	require.NoError(t, err)
+	require.Nil(t, secret)
+
+	_, err = ReadEncryptionSecret("passphrase", keyPath)
+	require.EqualError(t, err, "only one of an encryption key file or passphrase can be provided")
+}
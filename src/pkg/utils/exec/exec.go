@@ -12,7 +12,9 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -20,6 +22,21 @@ import (
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 )
 
+var (
+	// IsEnvVarName is a regex for valid environment variable names.
+	IsEnvVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`).MatchString
+
+	// supportedShells are the shells Zarf can run commands in for each OS.
+	supportedShells = map[string][]string{
+		"windows": {"powershell", "cmd", "pwsh", "sh", "bash", "gsh"},
+		"linux":   {"sh", "bash", "fish", "zsh", "pwsh"},
+		"darwin":  {"sh", "bash", "fish", "zsh", "pwsh"},
+	}
+
+	// containerRuntimes are the container runtime CLIs Zarf can run commands in containers with, in order of preference.
+	containerRuntimes = []string{"docker", "podman"}
+)
+
 // Config is a struct for configuring the Cmd function.
 type Config struct {
 	Print          bool
@@ -207,6 +224,26 @@ func GetOSShell(shellPref v1alpha1.Shell) (string, []string) {
 	return shell, shellArgs
 }
 
+// SupportedShells returns the shells Zarf can run commands in on the given OS.
+func SupportedShells(goos string) []string {
+	return supportedShells[goos]
+}
+
+// IsSupportedShell returns whether a shell name can be used on the given OS.
+func IsSupportedShell(goos, shellName string) bool {
+	return slices.Contains(supportedShells[goos], shellName)
+}
+
+// GetContainerRuntime returns the first container runtime CLI found on the PATH.
+func GetContainerRuntime() (string, error) {
+	for _, name := range containerRuntimes {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no container runtime was found, one of %v must be installed", containerRuntimes)
+}
+
 // IsPowershell returns whether a shell name is powershell
 func IsPowershell(shellName string) bool {
 	return shellName == "powershell" || shellName == "pwsh"
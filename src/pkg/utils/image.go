@@ -21,6 +21,45 @@ import (
 func LoadOCIImage(imgPath string, refInfo transform.Image) (v1.Image, error) {
 	// Use the manifest within the index.json to load the specific image we want
 	layoutPath := layout.Path(imgPath)
+	manifests, err := imageManifests(layoutPath, refInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("unable to find image (%s) at the path (%s)", refInfo.Reference, imgPath)
+	}
+	// This is the image we are looking for, load it and then return
+	return layoutPath.Image(manifests[0].Digest)
+}
+
+// LoadOCIImages returns every platform of the image with the image ref specified from a location provided keyed by
+// architecture, or an error if the image cannot be found. Images saved without a platform are keyed by an empty string.
+func LoadOCIImages(imgPath string, refInfo transform.Image) (map[string]v1.Image, error) {
+	layoutPath := layout.Path(imgPath)
+	manifests, err := imageManifests(layoutPath, refInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("unable to find image (%s) at the path (%s)", refInfo.Reference, imgPath)
+	}
+	imgs := map[string]v1.Image{}
+	for _, manifest := range manifests {
+		arch := ""
+		if manifest.Platform != nil {
+			arch = manifest.Platform.Architecture
+		}
+		img, err := layoutPath.Image(manifest.Digest)
+		if err != nil {
+			return nil, err
+		}
+		imgs[arch] = img
+	}
+	return imgs, nil
+}
+
+// imageManifests returns the descriptors in the index.json of the layout that are annotated with the image ref.
+func imageManifests(layoutPath layout.Path, refInfo transform.Image) ([]v1.Descriptor, error) {
 	imgIdx, err := layoutPath.ImageIndex()
 	if err != nil {
 		return nil, err
@@ -30,17 +69,16 @@ func LoadOCIImage(imgPath string, refInfo transform.Image) (v1.Image, error) {
 		return nil, err
 	}
 
-	// Search through all the manifests within this package until we find the annotation that matches our ref
+	// Search through all the manifests within this package for the annotations that match our ref
+	manifests := []v1.Descriptor{}
 	for _, manifest := range idxManifest.Manifests {
 		if manifest.Annotations[ocispec.AnnotationBaseImageName] == refInfo.Reference ||
 			// A backwards compatibility shim for older Zarf versions that would leave docker.io off of image annotations
 			(manifest.Annotations[ocispec.AnnotationBaseImageName] == refInfo.Path+refInfo.TagOrDigest && refInfo.Host == "docker.io") {
-			// This is the image we are looking for, load it and then return
-			return layoutPath.Image(manifest.Digest)
+			manifests = append(manifests, manifest)
 		}
 	}
-
-	return nil, fmt.Errorf("unable to find image (%s) at the path (%s)", refInfo.Reference, imgPath)
+	return manifests, nil
 }
 
 // AddImageNameAnnotation adds an annotation to the index.json file so that the deploying code can figure out what the image reference <-> digest shasum will be.
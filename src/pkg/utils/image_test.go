@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+func TestLoadOCIImages(t *testing.T) {
+	t.Parallel()
+
+	lp, err := layout.Write(t.TempDir(), empty.Index)
+	require.NoError(t, err)
+
+	appendImage := func(ref string, platform *v1.Platform) v1.Image {
+		t.Helper()
+		img, err := random.Image(64, 1)
+		require.NoError(t, err)
+		opts := []layout.Option{layout.WithAnnotations(map[string]string{ocispec.AnnotationBaseImageName: ref})}
+		if platform != nil {
+			opts = append(opts, layout.WithPlatform(*platform))
+		}
+		require.NoError(t, lp.AppendImage(img, opts...))
+		return img
+	}
+	single := appendImage("docker.io/library/alpine:3.20", nil)
+	amd64 := appendImage("ghcr.io/stefanprodan/podinfo:6.4.0", &v1.Platform{OS: "linux", Architecture: "amd64"})
+	arm64 := appendImage("ghcr.io/stefanprodan/podinfo:6.4.0", &v1.Platform{OS: "linux", Architecture: "arm64"})
+
+	digest := func(img v1.Image) v1.Hash {
+		t.Helper()
+		d, err := img.Digest()
+		require.NoError(t, err)
+		return d
+	}
+
+	alpine, err := transform.ParseImageRef("alpine:3.20")
+	require.NoError(t, err)
+	imgs, err := LoadOCIImages(string(lp), alpine)
+	require.NoError(t, err)
+	require.Len(t, imgs, 1)
+	require.Equal(t, digest(single), digest(imgs[""]))
+
+	podinfo, err := transform.ParseImageRef("ghcr.io/stefanprodan/podinfo:6.4.0")
+	require.NoError(t, err)
+	imgs, err = LoadOCIImages(string(lp), podinfo)
+	require.NoError(t, err)
+	require.Len(t, imgs, 2)
+	require.Equal(t, digest(amd64), digest(imgs["amd64"]))
+	require.Equal(t, digest(arm64), digest(imgs["arm64"]))
+
+	img, err := LoadOCIImage(string(lp), podinfo)
+	require.NoError(t, err)
+	require.Equal(t, digest(amd64), digest(img))
+
+	missing, err := transform.ParseImageRef("ghcr.io/stefanprodan/podinfo:6.4.1")
+	require.NoError(t, err)
+	_, err = LoadOCIImages(string(lp), missing)
+	require.EqualError(t, err, "unable to find image (ghcr.io/stefanprodan/podinfo:6.4.1) at the path ("+string(lp)+")")
+}
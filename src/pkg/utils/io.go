@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/subosito/gotenv"
 	"github.com/zarf-dev/zarf/src/config"
 )
 
@@ -65,3 +67,24 @@ func GetFinalExecutableCommand() (string, error) {
 
 	return zarfCommand, nil
 }
+
+// ReadEnvFiles reads KEY=value variables from .env files, with the variables in later files overriding those in earlier
+// ones. Variable names are uppercased so that they match the names of package variables.
+func ReadEnvFiles(paths ...string) (map[string]string, error) {
+	variables := map[string]string{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the env file %s: %w", path, err)
+		}
+		env, err := gotenv.StrictParse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the env file %s: %w", path, err)
+		}
+		for k, v := range env {
+			variables[strings.ToUpper(k)] = v
+		}
+	}
+	return variables, nil
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic helper functions.
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEnvFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.env")
+	require.NoError(t, os.WriteFile(base, []byte("# shared values\nDOMAIN=example.com\nexport registry=ghcr.io\nMOTD=\"hello world\"\n"), 0o600))
+	overlay := filepath.Join(dir, "overlay.env")
+	require.NoError(t, os.WriteFile(overlay, []byte("DOMAIN='prod.example.com'\n"), 0o600))
+	invalid := filepath.Join(dir, "invalid.env")
+	require.NoError(t, os.WriteFile(invalid, []byte("DOMAIN\n"), 0o600))
+
+	variables, err := ReadEnvFiles(base, overlay)
+	require.NoError(t, err)
+	expected := map[string]string{
+		"DOMAIN":   "prod.example.com",
+		"REGISTRY": "ghcr.io",
+		"MOTD":     "hello world",
+	}
+	require.Equal(t, expected, variables)
+
+	variables, err = ReadEnvFiles()
+	require.NoError(t, err)
+	require.Empty(t, variables)
+
+	_, err = ReadEnvFiles(invalid)
+	require.ErrorContains(t, err, "unable to parse the env file "+invalid)
+
+	_, err = ReadEnvFiles(filepath.Join(dir, "missing.env"))
+	require.ErrorContains(t, err, "unable to read the env file")
+}
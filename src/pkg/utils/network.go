@@ -6,17 +6,28 @@ package utils
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/avast/retry-go/v4"
 	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/types"
+	"golang.org/x/net/http/httpproxy"
 )
 
 func parseChecksum(src string) (string, string, error) {
@@ -38,8 +49,96 @@ func parseChecksum(src string) (string, string, error) {
 	return src, checksum, nil
 }
 
+// ConfigureTransport applies the trusted certificate authorities, client certificate and proxy of the given options
+// to a transport that connects to servers of remote packages.
+func ConfigureTransport(transport *http.Transport, opts types.ZarfCommonOptions) error {
+	if len(opts.CACertPaths) > 0 || opts.ClientCertPath != "" || opts.ClientKeyPath != "" {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+	}
+
+	if len(opts.CACertPaths) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			message.Debugf("Unable to load the system certificate authorities: %s", err)
+			pool = x509.NewCertPool()
+		}
+		for _, path := range opts.CACertPaths {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("unable to read the CA bundle %s: %w", path, err)
+			}
+			if !pool.AppendCertsFromPEM(b) {
+				return fmt.Errorf("the CA bundle %s does not contain any PEM certificates", path)
+			}
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if opts.ClientCertPath != "" || opts.ClientKeyPath != "" {
+		if opts.ClientCertPath == "" || opts.ClientKeyPath == "" {
+			return errors.New("a client certificate requires both --client-cert and --client-key")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertPath, opts.ClientKeyPath)
+		if err != nil {
+			return fmt.Errorf("unable to load the client certificate %s: %w", opts.ClientCertPath, err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			// The error can include the proxy credentials.
+			return errors.New("unable to parse the proxy URL")
+		}
+		if (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+			return fmt.Errorf("the proxy %s must be an http:// or https:// URL", proxyURL.Redacted())
+		}
+		// Hosts in NO_PROXY still bypass the proxy, like they do for the proxy environment variables.
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  proxyURL.String(),
+			HTTPSProxy: proxyURL.String(),
+			NoProxy:    httpproxy.FromEnvironment().NoProxy,
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+	return nil
+}
+
+// NewHTTPClient returns a client for servers of remote packages, configured with ConfigureTransport for the common
+// options of the command.
+func NewHTTPClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if err := ConfigureTransport(transport, config.CommonOptions); err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// downloadRetryDelay is the delay before the first retry of an interrupted HTTP download, later retries back off.
+const downloadRetryDelay = time.Second
+
 // DownloadToFile downloads a given URL to the target filepath (including the cosign key if necessary).
 func DownloadToFile(ctx context.Context, src, dst, cosignKeyPath string) error {
+	return downloadToFile(ctx, src, dst, cosignKeyPath, config.ZarfDefaultRetries, false)
+}
+
+// ResumeDownloadToFile downloads a given URL to the target filepath like DownloadToFile, but keeps what an earlier
+// download already wrote to the file and only requests the rest of it from HTTP servers. Interrupted HTTP downloads
+// are retried with backoff up to the given number of attempts.
+//
+// What an earlier run downloaded is only kept when the URL has a shasum, as without a validator from that run the
+// server can not tell whether the file changed since, and the shasum catches a file that was pieced together from two
+// versions.
+func ResumeDownloadToFile(ctx context.Context, src, dst, cosignKeyPath string, attempts int) error {
+	return downloadToFile(ctx, src, dst, cosignKeyPath, attempts, true)
+}
+
+func downloadToFile(ctx context.Context, src, dst, cosignKeyPath string, attempts int, resume bool) error {
 	// check if the parsed URL has a checksum
 	// if so, remove it and use the checksum to validate the file
 	src, checksum, err := parseChecksum(src)
@@ -53,7 +152,11 @@ func DownloadToFile(ctx context.Context, src, dst, cosignKeyPath string) error {
 	}
 
 	// Create the file
-	file, err := os.Create(dst)
+	flags := os.O_CREATE | os.O_RDWR
+	if !resume || checksum == "" {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(dst, flags, helpers.ReadAllWriteUser)
 	if err != nil {
 		return fmt.Errorf(lang.ErrWritingFile, dst, err.Error())
 	}
@@ -63,56 +166,177 @@ func DownloadToFile(ctx context.Context, src, dst, cosignKeyPath string) error {
 	if err != nil {
 		return fmt.Errorf("unable to parse the URL: %s", src)
 	}
+	var received string
 	// If the source url starts with the sget protocol use that, otherwise do a typical GET call
 	if parsed.Scheme == helpers.SGETURLScheme {
+		// sget downloads can not be resumed
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
 		err = Sget(ctx, src, cosignKeyPath, file)
 		if err != nil {
 			return fmt.Errorf("unable to download file with sget: %s: %w", src, err)
 		}
+		if 0 < len(checksum) {
+			received, err = helpers.GetSHA256OfFile(dst)
+			if err != nil {
+				return err
+			}
+		}
 	} else {
-		err = httpGetFile(src, file)
+		received, err = httpGetFile(ctx, src, file, attempts)
 		if err != nil {
+			// Only keep the file if there is a partial download to resume.
+			if fi, statErr := file.Stat(); statErr == nil && fi.Size() == 0 {
+				file.Close()
+				_ = os.Remove(dst)
+			}
 			return err
 		}
 	}
 
 	// If the file has a checksum, validate it
-	if 0 < len(checksum) {
-		received, err := helpers.GetSHA256OfFile(dst)
-		if err != nil {
-			return err
-		}
-		if received != checksum {
-			return fmt.Errorf("shasum mismatch for file %s: expected %s, got %s ", dst, checksum, received)
-		}
+	if 0 < len(checksum) && received != checksum {
+		// Drop the file so that a corrupted download is not resumed.
+		file.Close()
+		_ = os.Remove(dst)
+		return fmt.Errorf("shasum mismatch for file %s: expected %s, got %s ", dst, checksum, received)
 	}
 
 	return nil
 }
 
-func httpGetFile(url string, destinationFile *os.File) error {
-	// Get the data
-	resp, err := http.Get(url)
+// httpGetFile downloads the rest of the file at url into destinationFile, starting after what the file already holds,
+// and returns the SHA256 checksum of the complete file.
+func httpGetFile(ctx context.Context, url string, destinationFile *os.File, attempts int) (string, error) {
+	// Hash what was downloaded before, which also moves to the end of the file to append the rest to it.
+	hasher := sha256.New()
+	if _, err := destinationFile.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	offset, err := io.Copy(hasher, destinationFile)
 	if err != nil {
-		return fmt.Errorf("unable to download the file %s", url)
+		return "", err
+	}
+	// The ETag or Last-Modified date of the file that is being downloaded, so that retries only get the rest of the file
+	// if it did not change in between.
+	validator := ""
+	restart := func() error {
+		offset = 0
+		hasher.Reset()
+		if err := destinationFile.Truncate(0); err != nil {
+			return err
+		}
+		_, err := destinationFile.Seek(0, io.SeekStart)
+		return err
 	}
-	defer resp.Body.Close()
 
-	// Check server response
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad HTTP status: %s", resp.Status)
+	if attempts < 1 {
+		attempts = config.ZarfDefaultRetries
+	}
+	client, err := NewHTTPClient()
+	if err != nil {
+		return "", err
 	}
+	attempt := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return retry.Unrecoverable(fmt.Errorf("unable to download the file %s: %w", url, err))
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			if validator != "" {
+				req.Header.Set("If-Range", validator)
+			}
+		}
+		// Get the data
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("unable to download the file %s: %w", url, err)
+		}
+		defer resp.Body.Close()
 
-	// Writer the body to file
-	title := fmt.Sprintf("Downloading %s", filepath.Base(url))
-	progressBar := message.NewProgressBar(resp.ContentLength, title)
+		// Check server response
+		switch {
+		case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+			if contentRangeSize(resp.Header.Get("Content-Range")) == offset {
+				// The file was already downloaded completely.
+				return nil
+			}
+			if err := restart(); err != nil {
+				return retry.Unrecoverable(err)
+			}
+			return fmt.Errorf("the file %s changed since it was partially downloaded", url)
+		case resp.StatusCode == http.StatusOK && offset > 0:
+			// The server does not support range requests or the file changed, so the download starts over.
+			if err := restart(); err != nil {
+				return retry.Unrecoverable(err)
+			}
+		case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPartialContent:
+		case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+			return fmt.Errorf("bad HTTP status: %s", resp.Status)
+		default:
+			return retry.Unrecoverable(fmt.Errorf("bad HTTP status: %s", resp.Status))
+		}
 
-	if _, err = io.Copy(destinationFile, io.TeeReader(resp.Body, progressBar)); err != nil {
-		progressBar.Failf("Unable to save the file %s: %s", destinationFile.Name(), err.Error())
-		return err
+		if resp.StatusCode == http.StatusOK || validator == "" {
+			validator = rangeValidator(resp.Header)
+		}
+
+		// Writer the body to file
+		total := resp.ContentLength
+		if total >= 0 {
+			total += offset
+		}
+		title := fmt.Sprintf("Downloading %s", filepath.Base(url))
+		progressBar := message.NewProgressBar(total, title)
+		progressBar.Add(int(offset))
+
+		n, err := io.Copy(io.MultiWriter(destinationFile, hasher), io.TeeReader(resp.Body, progressBar))
+		offset += n
+		if err != nil {
+			progressBar.Failf("Unable to save the file %s: %s", destinationFile.Name(), err.Error())
+			return err
+		}
+
+		title = fmt.Sprintf("Downloaded %s", url)
+		progressBar.Successf("%s", title)
+		return nil
+	}
+	err = retry.Do(attempt,
+		retry.Context(ctx),
+		retry.Attempts(uint(attempts)),
+		retry.Delay(downloadRetryDelay),
+		retry.LastErrorOnly(true),
+		retry.OnRetry(func(_ uint, err error) {
+			message.Debugf("Retrying the download of %s after %s: %s", url, ByteFormat(float64(offset), 2), err)
+		}),
+	)
+	if err != nil {
+		return "", err
 	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
 
-	title = fmt.Sprintf("Downloaded %s", url)
-	progressBar.Successf("%s", title)
-	return nil
+// rangeValidator returns the ETag or, if the server does not send a strong ETag, the Last-Modified date of a response
+// to send as If-Range, or an empty string if the response has neither.
+func rangeValidator(header http.Header) string {
+	// Weak ETags can not be used to resume downloads.
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+// contentRangeSize returns the complete size from a Content-Range header, or -1 if it is unknown.
+func contentRangeSize(contentRange string) int64 {
+	_, size, ok := strings.Cut(contentRange, "/")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
 }
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+//go:build !pkcs11key
+
+// Package utils provides generic utility functions.
+package utils
+
+// pkcs11Supported is true when Zarf is built with cosign's PKCS#11 support, which requires cgo.
+const pkcs11Supported = false
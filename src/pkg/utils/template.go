@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic utility functions.
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// templatePipeline matches the functions a template is piped through, i.e. the ` | default "podinfo" | upper` of
+// ###ZARF_PKG_TMPL_NAME | default "podinfo" | upper###. Quotes around arguments are escaped when the template is inside
+// a double-quoted yaml string.
+const templatePipeline = `(?:\s*\|\s*[a-z0-9]+(?:\s+\\?"[^"\\]*\\?")?)+\s*`
+
+var (
+	yamlTemplateFuncRegex = regexp.MustCompile(`(###[A-Z0-9_]+?)(` + templatePipeline + `)###`)
+	templateFuncRegex     = regexp.MustCompile(`\|\s*([a-z0-9]+)(?:\s+\\?"([^"\\]*)\\?")?`)
+	templateDefaultRegex  = regexp.MustCompile(`\|\s*default\s+\\?"([^"\\]*)\\?"`)
+)
+
+// templateFunc transforms the value of a template, taking an optional argument.
+type templateFunc struct {
+	hasArg bool
+	apply  func(value string, arg string) string
+}
+
+// templateFuncs are the functions that a template can be piped through.
+var templateFuncs = map[string]templateFunc{
+	"default": {hasArg: true, apply: func(value string, arg string) string {
+		if value == "" {
+			return arg
+		}
+		return value
+	}},
+	"upper": {apply: func(value string, _ string) string {
+		return strings.ToUpper(value)
+	}},
+	"trim": {apply: func(value string, _ string) string {
+		return strings.TrimSpace(value)
+	}},
+	"b64enc": {apply: func(value string, _ string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	}},
+}
+
+// applyTemplatePipeline passes a value through each function of a template pipeline in order.
+func applyTemplatePipeline(value string, pipeline string) (string, error) {
+	for _, match := range templateFuncRegex.FindAllStringSubmatch(pipeline, -1) {
+		name, hasArg := match[1], strings.Contains(match[0], `"`)
+		fn, ok := templateFuncs[name]
+		if !ok {
+			return "", fmt.Errorf("unknown template function %q", name)
+		}
+		if fn.hasArg != hasArg {
+			if fn.hasArg {
+				return "", fmt.Errorf("template function %q requires a quoted argument", name)
+			}
+			return "", fmt.Errorf("template function %q does not take an argument", name)
+		}
+		value = fn.apply(value, match[2])
+	}
+	return value, nil
+}
+
+// templateDefault returns the argument given to the default function of a template pipeline, if any.
+func templateDefault(pipeline string) string {
+	if match := templateDefaultRegex.FindStringSubmatch(pipeline); match != nil {
+		return match[1]
+	}
+	return ""
+}
@@ -7,6 +7,7 @@ package utils
 // fork from https://github.com/goccy/go-yaml/blob/master/cmd/ycat/ycat.go
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -14,11 +15,13 @@ import (
 	"io/fs"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
 	goyaml "github.com/goccy/go-yaml"
 	"github.com/goccy/go-yaml/lexer"
+	"github.com/goccy/go-yaml/parser"
 	"github.com/goccy/go-yaml/printer"
 	"github.com/pterm/pterm"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -123,14 +126,29 @@ func AddRootHint(hints map[string]string, rootKey string, hintText string) map[s
 	return hints
 }
 
+// MaxYAMLDocumentSize is the largest document of a multi-document YAML stream that is read into memory at once. The
+// files can be larger than this as their documents are streamed one at a time.
+var MaxYAMLDocumentSize int64 = 32 * 1024 * 1024
+
+// ErrYAMLDocumentTooLarge is returned when a YAML document is larger than MaxYAMLDocumentSize.
+var ErrYAMLDocumentTooLarge = errors.New("YAML document is too large")
+
+var (
+	yamlPositionRegex = regexp.MustCompile(`^\[(\d+):(\d+)\]`)
+	yamlLineRegex     = regexp.MustCompile(`line (\d+): `)
+)
+
 // ReadYaml reads a yaml file and unmarshals it into a given config.
 func ReadYaml(path string, destConfig any) error {
-	file, err := os.ReadFile(path)
+	b, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
 
-	return goyaml.Unmarshal(file, destConfig)
+	if err := goyaml.Unmarshal(b, destConfig); err != nil {
+		return fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+	return nil
 }
 
 // WriteYaml writes a given config to a yaml file on disk.
@@ -145,27 +163,58 @@ func WriteYaml(path string, srcConfig any, perm fs.FileMode) error {
 }
 
 // ReloadYamlTemplate marshals a given config, replaces strings and unmarshals it back.
+//
+// A template can be piped through functions before it is replaced, i.e. ###ZARF_PKG_TMPL_NAME | default "podinfo" | upper###.
 func ReloadYamlTemplate(config any, mappings map[string]string) error {
 	text, err := goyaml.Marshal(config)
 	if err != nil {
 		return err
 	}
 
-	for template, value := range mappings {
-		// Prevent user input from escaping the trailing " during yaml marshaling
-		lastIdx := len(value) - 1
-		if lastIdx > -1 && string(value[lastIdx]) == "\\" {
-			value = fmt.Sprintf("%s\\", value)
+	// Replace the templates that are piped through functions first, leaving templates without a mapping for a later reload.
+	var funcErr error
+	replaced := yamlTemplateFuncRegex.ReplaceAllStringFunc(string(text), func(match string) string {
+		groups := yamlTemplateFuncRegex.FindStringSubmatch(match)
+		value, ok := mappings[groups[1]+"###"]
+		if !ok {
+			return match
 		}
-		// Properly escape " in the yaml text output
-		value = strings.ReplaceAll(value, "\"", "\\\"")
-		text = []byte(strings.ReplaceAll(string(text), template, value))
+		value, err := applyTemplatePipeline(value, groups[2])
+		if err != nil {
+			funcErr = errors.Join(funcErr, fmt.Errorf("unable to template %s###: %w", groups[1], err))
+			return match
+		}
+		return escapeYamlTemplateValue(value)
+	})
+	if funcErr != nil {
+		return funcErr
+	}
+
+	// Replace every template in a single pass so that large configs are not copied once per template.
+	oldnew := make([]string, 0, len(mappings)*2)
+	for template, value := range mappings {
+		oldnew = append(oldnew, template, escapeYamlTemplateValue(value))
+	}
+	if len(oldnew) > 0 {
+		replaced = strings.NewReplacer(oldnew...).Replace(replaced)
 	}
 
-	return goyaml.Unmarshal(text, config)
+	return goyaml.Unmarshal([]byte(replaced), config)
 }
 
-// FindYamlTemplates finds strings with a given prefix in a config.
+// escapeYamlTemplateValue escapes a value so that it can be placed in a double-quoted string of marshaled yaml.
+func escapeYamlTemplateValue(value string) string {
+	// Prevent user input from escaping the trailing " during yaml marshaling
+	lastIdx := len(value) - 1
+	if lastIdx > -1 && string(value[lastIdx]) == "\\" {
+		value = fmt.Sprintf("%s\\", value)
+	}
+	// Properly escape " in the yaml text output
+	return strings.ReplaceAll(value, "\"", "\\\"")
+}
+
+// FindYamlTemplates finds strings with a given prefix in a config, mapping the name of each template to the argument of
+// its default function if it is piped through one.
 func FindYamlTemplates(config any, prefix string, suffix string) (map[string]string, error) {
 	mappings := map[string]string{}
 
@@ -175,58 +224,206 @@ func FindYamlTemplates(config any, prefix string, suffix string) (map[string]str
 	}
 
 	// Find all strings that are between the given prefix and suffix
-	r := regexp.MustCompile(fmt.Sprintf("%s([A-Z0-9_]+)%s", prefix, suffix))
+	r := regexp.MustCompile(fmt.Sprintf("%s([A-Z0-9_]+)(%s)?%s", prefix, templatePipeline, suffix))
 	matches := r.FindAllStringSubmatch(string(text), -1)
 
 	for _, match := range matches {
-		mappings[match[1]] = ""
+		if mappings[match[1]] == "" {
+			mappings[match[1]] = templateDefault(match[2])
+		}
 	}
 
 	return mappings, nil
 }
 
-// SplitYAML splits a YAML file into unstructured objects. Returns list of all unstructured objects
-// found in the yaml. If an error occurs, returns objects that have been parsed so far too.
-// Source: https://github.com/argoproj/gitops-engine/blob/v0.5.2/pkg/utils/kube/kube.go#L286.
-func SplitYAML(yamlData []byte) ([]*unstructured.Unstructured, error) {
-	var objs []*unstructured.Unstructured
-	ymls, err := SplitYAMLToString(yamlData)
-	if err != nil {
-		return []*unstructured.Unstructured{}, err
+// YAMLDocument is a single document of a multi-document YAML stream.
+type YAMLDocument struct {
+	// Line is the line of the stream that the document starts on.
+	Line int
+	Data []byte
+}
+
+// wrapError adds the line and, when it can be found, the column of a parse error in the document to err.
+func (doc YAMLDocument) wrapError(err error) error {
+	line, column := doc.Line, 0
+	msg := err.Error()
+	if m := yamlLineRegex.FindStringSubmatch(msg); m != nil {
+		l, _ := strconv.Atoi(m[1])
+		line = doc.Line + l - 1
+		msg = yamlLineRegex.ReplaceAllString(msg, "")
 	}
-	for _, yml := range ymls {
-		u := &unstructured.Unstructured{}
-		if err := k8syaml.Unmarshal([]byte(yml), u); err != nil {
-			return []*unstructured.Unstructured{}, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	// go-yaml reports the column of syntax errors which the Kubernetes YAML parser does not.
+	if _, perr := parser.ParseBytes(doc.Data, 0); perr != nil {
+		if m := yamlPositionRegex.FindStringSubmatch(perr.Error()); m != nil {
+			l, _ := strconv.Atoi(m[1])
+			line = doc.Line + l - 1
+			column, _ = strconv.Atoi(m[2])
 		}
-		objs = append(objs, u)
 	}
-	return objs, nil
+	if column > 0 {
+		return fmt.Errorf("line %d, column %d: %s", line, column, msg)
+	}
+	return fmt.Errorf("line %d: %s", line, msg)
 }
 
-// SplitYAMLToString splits a YAML file into strings. Returns list of yamls
-// found in the yaml. If an error occurs, returns objects that have been parsed so far too.
-// Source: https://github.com/argoproj/gitops-engine/blob/v0.5.2/pkg/utils/kube/kube.go#L304.
-func SplitYAMLToString(yamlData []byte) ([]string, error) {
-	// Similar way to what kubectl does
-	// https://github.com/kubernetes/cli-runtime/blob/master/pkg/resource/visitor.go#L573-L600
-	// Ideally k8s.io/cli-runtime/pkg/resource.Builder should be used instead of this method.
-	// E.g. Builder does list unpacking and flattening and this code does not.
-	d := kubeyaml.NewYAMLOrJSONDecoder(bytes.NewReader(yamlData), 4096)
-	var objs []string
+// StreamYAML calls fn with each document of the multi-document YAML in r in turn, only holding one document in memory at
+// a time so that files larger than memory (i.e. large CRD bundles) can be processed.
+func StreamYAML(r io.Reader, fn func(doc YAMLDocument) error) error {
+	reader := bufio.NewReader(r)
+	doc := YAMLDocument{Line: 1}
+	lineNum := 0
+	for {
+		line, err := reader.ReadSlice('\n')
+		for errors.Is(err, bufio.ErrBufferFull) {
+			if int64(len(doc.Data)+len(line)) > MaxYAMLDocumentSize {
+				return fmt.Errorf("line %d: %w (the limit is %s)", doc.Line, ErrYAMLDocumentTooLarge, ByteFormat(float64(MaxYAMLDocumentSize), 2))
+			}
+			doc.Data = append(doc.Data, line...)
+			line, err = reader.ReadSlice('\n')
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if len(line) > 0 {
+			lineNum++
+		}
+
+		if isYAMLSeparator(line) {
+			if err := emitYAMLDocument(doc, fn); err != nil {
+				return err
+			}
+			doc = YAMLDocument{Line: lineNum + 1}
+		} else {
+			if int64(len(doc.Data)+len(line)) > MaxYAMLDocumentSize {
+				return fmt.Errorf("line %d: %w (the limit is %s)", doc.Line, ErrYAMLDocumentTooLarge, ByteFormat(float64(MaxYAMLDocumentSize), 2))
+			}
+			doc.Data = append(doc.Data, line...)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return emitYAMLDocument(doc, fn)
+		}
+	}
+}
+
+// isYAMLSeparator returns true if the line separates two YAML documents, only comments may follow the separator.
+func isYAMLSeparator(line []byte) bool {
+	if !bytes.HasPrefix(line, []byte("---")) {
+		return false
+	}
+	rest := bytes.TrimSpace(line[3:])
+	return len(rest) == 0 || rest[0] == '#'
+}
+
+// emitYAMLDocument calls fn with the document unless it is empty.
+func emitYAMLDocument(doc YAMLDocument, fn func(doc YAMLDocument) error) error {
+	if len(bytes.TrimSpace(doc.Data)) == 0 {
+		return nil
+	}
+	return fn(doc)
+}
+
+// StreamUnstructured calls fn with each object of the multi-document YAML or JSON in r in turn.
+func StreamUnstructured(r io.Reader, fn func(obj *unstructured.Unstructured) error) error {
+	return streamRaw(r, func(raw []byte, wrapError func(error) error) error {
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(raw); err != nil {
+			return fmt.Errorf("failed to unmarshal manifest: %w", wrapError(err))
+		}
+		return fn(u)
+	})
+}
+
+// streamRaw calls fn with the JSON of each document of the multi-document YAML, or of each object of the JSON stream,
+// in r in turn. JSON streams of objects that are not separated by --- are decoded the way kubectl decodes them.
+func streamRaw(r io.Reader, fn func(raw []byte, wrapError func(error) error) error) error {
+	r, _, isJSON := kubeyaml.GuessJSONStream(r, 4096)
+	if !isJSON {
+		return StreamYAML(r, func(doc YAMLDocument) error {
+			raw, err := yamlDocumentToJSON(doc)
+			if err != nil || raw == nil {
+				return err
+			}
+			return fn(raw, doc.wrapError)
+		})
+	}
+
+	d := kubeyaml.NewYAMLOrJSONDecoder(r, 4096)
 	for {
 		ext := runtime.RawExtension{}
 		if err := d.Decode(&ext); err != nil {
 			if errors.Is(err, io.EOF) {
-				break
+				return nil
 			}
-			return []string{}, fmt.Errorf("failed to unmarshal manifest: %w", err)
+			return fmt.Errorf("failed to unmarshal manifest: %w", err)
 		}
-		ext.Raw = bytes.TrimSpace(ext.Raw)
-		if len(ext.Raw) == 0 || bytes.Equal(ext.Raw, []byte("null")) {
+		raw := bytes.TrimSpace(ext.Raw)
+		if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
 			continue
 		}
-		objs = append(objs, string(ext.Raw))
+		if err := fn(raw, func(err error) error { return err }); err != nil {
+			return err
+		}
+	}
+}
+
+// yamlDocumentToJSON converts the document to JSON, returning nil if it only contains comments or null.
+func yamlDocumentToJSON(doc YAMLDocument) ([]byte, error) {
+	raw, err := k8syaml.YAMLToJSON(doc.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %w", doc.wrapError(err))
+	}
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// SplitYAMLFile streams the multi-document YAML file at path into unstructured objects without reading the whole file
+// into memory.
+func SplitYAMLFile(path string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	objs := []*unstructured.Unstructured{}
+	err = StreamUnstructured(f, func(obj *unstructured.Unstructured) error {
+		objs = append(objs, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+// SplitYAML splits a YAML file into unstructured objects. Returns list of all unstructured objects
+// found in the yaml.
+func SplitYAML(yamlData []byte) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	err := StreamUnstructured(bytes.NewReader(yamlData), func(obj *unstructured.Unstructured) error {
+		objs = append(objs, obj)
+		return nil
+	})
+	if err != nil {
+		return []*unstructured.Unstructured{}, err
+	}
+	return objs, nil
+}
+
+// SplitYAMLToString splits a YAML file into strings of the JSON of each document. Returns list of yamls
+// found in the yaml.
+func SplitYAMLToString(yamlData []byte) ([]string, error) {
+	var objs []string
+	err := streamRaw(bytes.NewReader(yamlData), func(raw []byte, _ func(error) error) error {
+		objs = append(objs, string(raw))
+		return nil
+	})
+	if err != nil {
+		return []string{}, err
 	}
 	return objs, nil
 }
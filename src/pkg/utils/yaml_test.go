@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamYAML(t *testing.T) {
+	t.Parallel()
+
+	manifest := `# leading comment
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+--- # trailing comment
+---
+
+apiVersion: v1
+kind: Secret
+metadata:
+  name: second
+---
+null
+---
+{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "third"}}`
+
+	docs := []YAMLDocument{}
+	err := StreamYAML(strings.NewReader(manifest), func(doc YAMLDocument) error {
+		docs = append(docs, doc)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, docs, 5)
+	require.Equal(t, []int{1, 3, 9, 15, 17}, []int{docs[0].Line, docs[1].Line, docs[2].Line, docs[3].Line, docs[4].Line})
+
+	objs, err := SplitYAML([]byte(manifest))
+	require.NoError(t, err)
+	require.Len(t, objs, 3)
+	require.Equal(t, "first", objs[0].GetName())
+	require.Equal(t, "second", objs[1].GetName())
+	require.Equal(t, "third", objs[2].GetName())
+
+	yamls, err := SplitYAMLToString([]byte(manifest))
+	require.NoError(t, err)
+	require.Len(t, yamls, 3)
+	require.Equal(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"first"}}`, yamls[0])
+}
+
+func TestSplitYAMLJSONStream(t *testing.T) {
+	t.Parallel()
+
+	manifest := `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "first"}}
+{"apiVersion": "v1", "kind": "Secret",
+ "metadata": {"name": "second"}}`
+
+	objs, err := SplitYAML([]byte(manifest))
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	require.Equal(t, "first", objs[0].GetName())
+	require.Equal(t, "second", objs[1].GetName())
+
+	yamls, err := SplitYAMLToString([]byte(manifest))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "first"}}`,
+		`{"apiVersion": "v1", "kind": "Secret",
+ "metadata": {"name": "second"}}`,
+	}, yamls)
+}
+
+func TestSplitYAMLErrorLocation(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+  labels: {app: second
+`
+	_, err := SplitYAML([]byte(manifest))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to unmarshal manifest: line 10, column")
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(manifest), 0o600))
+	_, err = SplitYAMLFile(path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to unmarshal manifest: line 10, column")
+}
+
+func TestReadYaml(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zarf.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("kind: ZarfPackageConfig\nmetadata: {name: a\n"), 0o600))
+
+	var pkg map[string]any
+	err := ReadYaml(path, &pkg)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to parse "+path+": [")
+}
+
+func TestReloadYamlTemplate(t *testing.T) {
+	t.Parallel()
+
+	config := map[string]string{
+		"name":  "###ZARF_PKG_TMPL_NAME###",
+		"image": "###ZARF_PKG_TMPL_REGISTRY###/###ZARF_PKG_TMPL_NAME###:1.0.0",
+	}
+	err := ReloadYamlTemplate(&config, map[string]string{
+		"###ZARF_PKG_TMPL_NAME###":     `po"dinfo`,
+		"###ZARF_PKG_TMPL_REGISTRY###": "ghcr.io",
+	})
+	require.NoError(t, err)
+	require.Equal(t, `po"dinfo`, config["name"])
+	require.Equal(t, `ghcr.io/po"dinfo:1.0.0`, config["image"])
+}
+
+func TestReloadYamlTemplateFunctions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		template    string
+		mappings    map[string]string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "upper",
+			template: "###ZARF_PKG_TMPL_NAME | upper###",
+			mappings: map[string]string{"###ZARF_PKG_TMPL_NAME###": "podinfo"},
+			expected: "PODINFO",
+		},
+		{
+			name:     "default when empty",
+			template: `registry: ###ZARF_PKG_TMPL_REGISTRY | default "ghcr.io"###`,
+			mappings: map[string]string{"###ZARF_PKG_TMPL_REGISTRY###": ""},
+			expected: "registry: ghcr.io",
+		},
+		{
+			name:     "default when set",
+			template: `###ZARF_PKG_TMPL_REGISTRY | default "ghcr.io"###`,
+			mappings: map[string]string{"###ZARF_PKG_TMPL_REGISTRY###": "docker.io"},
+			expected: "docker.io",
+		},
+		{
+			name:     "pipeline",
+			template: "user:###ZARF_PKG_TMPL_USER|trim|b64enc### and ###ZARF_PKG_TMPL_USER###",
+			mappings: map[string]string{"###ZARF_PKG_TMPL_USER###": " admin "},
+			expected: "user:YWRtaW4= and  admin ",
+		},
+		{
+			name:     "no mapping",
+			template: "###ZARF_PKG_TMPL_NAME | upper###",
+			mappings: map[string]string{},
+			expected: "###ZARF_PKG_TMPL_NAME | upper###",
+		},
+		{
+			name:        "unknown function",
+			template:    "###ZARF_PKG_TMPL_NAME | lower###",
+			mappings:    map[string]string{"###ZARF_PKG_TMPL_NAME###": "podinfo"},
+			expectedErr: `unable to template ###ZARF_PKG_TMPL_NAME###: unknown template function "lower"`,
+		},
+		{
+			name:        "missing argument",
+			template:    "###ZARF_PKG_TMPL_NAME | default###",
+			mappings:    map[string]string{"###ZARF_PKG_TMPL_NAME###": "podinfo"},
+			expectedErr: `unable to template ###ZARF_PKG_TMPL_NAME###: template function "default" requires a quoted argument`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := map[string]string{"value": tt.template}
+			err := ReloadYamlTemplate(&config, tt.mappings)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, config["value"])
+		})
+	}
+}
+
+func TestFindYamlTemplates(t *testing.T) {
+	t.Parallel()
+
+	config := map[string]string{
+		"name":     "###ZARF_PKG_TMPL_NAME | upper###",
+		"registry": `###ZARF_PKG_TMPL_REGISTRY | default "ghcr.io" | trim###`,
+		"image":    "###ZARF_PKG_TMPL_REGISTRY###/###ZARF_PKG_TMPL_NAME###",
+	}
+	templates, err := FindYamlTemplates(&config, "###ZARF_PKG_TMPL_", "###")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"NAME": "", "REGISTRY": "ghcr.io"}, templates)
+}
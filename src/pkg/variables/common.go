@@ -6,6 +6,8 @@ package variables
 
 import (
 	"log/slog"
+	"maps"
+	"slices"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 )
@@ -20,6 +22,9 @@ type VariableConfig struct {
 
 	prompt func(variable v1alpha1.InteractiveVariable) (value string, err error)
 	logger *slog.Logger
+
+	// The variables of the variable config this config was cloned from, at the time it was cloned
+	clonedVariableMap SetVariableMap
 }
 
 // New creates a new VariableConfig
@@ -42,3 +47,25 @@ func (vc *VariableConfig) SetApplicationTemplates(applicationTemplates map[strin
 func (vc *VariableConfig) SetConstants(constants []v1alpha1.Constant) {
 	vc.constants = constants
 }
+
+// Clone returns a copy of the variable config whose variables and application templates can be set without changing
+// this config, for use by a single component when components are deployed concurrently.
+func (vc *VariableConfig) Clone() *VariableConfig {
+	clone := *vc
+	clone.applicationTemplates = maps.Clone(vc.applicationTemplates)
+	clone.setVariableMap = maps.Clone(vc.setVariableMap)
+	clone.constants = slices.Clone(vc.constants)
+	clone.clonedVariableMap = maps.Clone(vc.setVariableMap)
+	return &clone
+}
+
+// MergeSetVariables sets the variables that were set in a clone of the variable config after it was cloned.
+func (vc *VariableConfig) MergeSetVariables(clone *VariableConfig) {
+	for name, variable := range clone.setVariableMap {
+		// Variables are replaced when they are set, so an unchanged pointer is a variable the clone did not set.
+		if clone.clonedVariableMap[name] == variable {
+			continue
+		}
+		vc.setVariableMap[name] = variable
+	}
+}
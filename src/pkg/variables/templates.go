@@ -6,8 +6,11 @@ package variables
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -48,6 +51,19 @@ func (vc *VariableConfig) GetAllTemplates() map[string]*TextTemplate {
 	return templateMap
 }
 
+// ReplaceTemplates replaces the templates in a single value, such as an address, with the values of the variables,
+// constants and application templates. File variables are replaced by their path rather than their contents.
+func (vc *VariableConfig) ReplaceTemplates(text string) string {
+	templateRegex := regexp.MustCompile(fmt.Sprintf("###%s_[A-Z0-9_]+###", strings.ToUpper(vc.templatePrefix)))
+	templateMap := vc.GetAllTemplates()
+	return templateRegex.ReplaceAllStringFunc(text, func(templateKey string) string {
+		if template := templateMap[templateKey]; template != nil {
+			return template.Value
+		}
+		return templateKey
+	})
+}
+
 // ReplaceTextTemplate loads a file from a given path, replaces text in it and writes it back in place.
 func (vc *VariableConfig) ReplaceTextTemplate(path string) error {
 	templateRegex := fmt.Sprintf("###%s_[A-Z0-9_]+###", strings.ToUpper(vc.templatePrefix))
@@ -58,32 +74,43 @@ func (vc *VariableConfig) ReplaceTextTemplate(path string) error {
 		return err
 	}
 	defer textFile.Close()
+	fi, err := textFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Stream the templated text into a temporary file next to the original so that large files are never held in memory.
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmpl-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
 
 	// This regex takes a line and parses the text before and after a discovered template: https://regex101.com/r/ilUxAz/1
 	regexTemplateLine := regexp.MustCompile(fmt.Sprintf("(?P<preTemplate>.*?)(?P<template>%s)(?P<postTemplate>.*)", templateRegex))
 
-	fileScanner := bufio.NewScanner(textFile)
-
-	// Set the buffer to 1 MiB to handle long lines (i.e. base64 text in a secret)
-	// 1 MiB is around the documented maximum size for secrets and configmaps
-	const maxCapacity = 1024 * 1024
-	buf := make([]byte, maxCapacity)
-	fileScanner.Buffer(buf, maxCapacity)
-
-	// Set the scanner to split on new lines
-	fileScanner.Split(bufio.ScanLines)
+	reader := bufio.NewReader(textFile)
+	writer := bufio.NewWriter(tmpFile)
 
-	text := ""
-
-	for fileScanner.Scan() {
-		line := fileScanner.Text()
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return readErr
+		}
+		if line == "" && readErr != nil {
+			break
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
 
 		for {
 			matches := regexTemplateLine.FindStringSubmatch(line)
 
 			// No template left on this line so move on
 			if len(matches) == 0 {
-				text += fmt.Sprintln(line)
+				if _, err := fmt.Fprintln(writer, line); err != nil {
+					return err
+				}
 				break
 			}
 
@@ -125,10 +152,27 @@ func (vc *VariableConfig) ReplaceTextTemplate(path string) error {
 			}
 
 			// Add the processed text and continue processing the line
-			text += fmt.Sprintf("%s%s", preTemplate, value)
+			if _, err := writer.WriteString(preTemplate + value); err != nil {
+				return err
+			}
 			line = matches[regexTemplateLine.SubexpIndex("postTemplate")]
 		}
+
+		if readErr != nil {
+			break
+		}
 	}
 
-	return os.WriteFile(path, []byte(text), helpers.ReadWriteUser)
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	// Keep the mode of the original file, such as the executable bit of a templated script
+	if err := tmpFile.Chmod(fi.Mode()); err != nil {
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	textFile.Close()
+	return os.Rename(tmpFile.Name(), path)
 }
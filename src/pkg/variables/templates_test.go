@@ -138,6 +138,7 @@ func TestReplaceTextTemplate(t *testing.T) {
 
 			_, err := f.WriteString(start)
 			require.NoError(t, err)
+			require.NoError(t, f.Chmod(0o755))
 		}
 
 		gotErr := tc.vc.ReplaceTextTemplate(tc.path)
@@ -147,6 +148,24 @@ func TestReplaceTextTemplate(t *testing.T) {
 			require.NoError(t, gotErr)
 			gotContents, _ := os.ReadFile(tc.path)
 			require.Equal(t, tc.wantContents, string(gotContents))
+			fi, err := os.Stat(tc.path)
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0o755), fi.Mode().Perm())
 		}
 	}
 }
+
+func TestReplaceTemplates(t *testing.T) {
+	t.Parallel()
+
+	vc := VariableConfig{
+		templatePrefix: "PREFIX",
+		setVariableMap: SetVariableMap{
+			"HOST": {Value: "podinfo.podinfo.svc.cluster.local"},
+		},
+		constants:            []v1alpha1.Constant{{Name: "PORT", Value: "9898"}},
+		applicationTemplates: map[string]*TextTemplate{},
+	}
+	require.Equal(t, "podinfo.podinfo.svc.cluster.local:9898/###PREFIX_VAR_PATH###",
+		vc.ReplaceTemplates("###PREFIX_VAR_HOST###:###PREFIX_CONST_PORT###/###PREFIX_VAR_PATH###"))
+}
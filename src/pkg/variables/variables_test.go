@@ -159,3 +159,28 @@ func TestCheckVariablePattern(t *testing.T) {
 		}
 	}
 }
+
+func TestCloneAndMergeSetVariables(t *testing.T) {
+	t.Parallel()
+
+	vc := New("ZARF", nil, nil)
+	vc.SetVariable("SHARED", "shared", false, false, "")
+	vc.SetVariable("OTHER", "other", false, false, "")
+
+	clone := vc.Clone()
+	clone.SetVariable("SET", "set", false, false, "")
+	clone.SetApplicationTemplates(map[string]*TextTemplate{"###ZARF_COMPONENT_NAME###": {Value: "component"}})
+	_, ok := vc.GetSetVariable("SET")
+	require.False(t, ok)
+	require.Empty(t, vc.applicationTemplates)
+
+	// A variable set by another clone after this one was cloned is kept.
+	vc.SetVariable("OTHER", "changed", false, false, "")
+	vc.MergeSetVariables(clone)
+	expected := SetVariableMap{
+		"SHARED": {Variable: v1alpha1.Variable{Name: "SHARED"}, Value: "shared"},
+		"OTHER":  {Variable: v1alpha1.Variable{Name: "OTHER"}, Value: "changed"},
+		"SET":    {Variable: v1alpha1.Variable{Name: "SET"}, Value: "set"},
+	}
+	require.Equal(t, expected, vc.setVariableMap)
+}
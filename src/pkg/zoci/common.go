@@ -6,11 +6,15 @@ package zoci
 
 import (
 	"log/slog"
+	"net/http"
 
 	"github.com/defenseunicorns/pkg/oci"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"oras.land/oras-go/v2/registry/remote/auth"
 )
 
 const (
@@ -28,12 +32,20 @@ type Remote struct {
 }
 
 // NewRemote returns an oras remote repository client and context for the given url
-// with zarf opination embedded
+// with zarf opination embedded, using the registry mirror of the url's host if one is configured
 func NewRemote(url string, platform ocispec.Platform, mods ...oci.Modifier) (*Remote, error) {
+	mirrored, err := transform.ImageMirrorHost(config.CommonOptions.RegistryMirrors, url)
+	if err == nil && mirrored != url {
+		message.Debugf("Using the registry mirror %s for %s", mirrored, url)
+		url = mirrored
+	}
+
 	logger := slog.New(message.ZarfHandler{})
+	var transportErr error
 	modifiers := append([]oci.Modifier{
 		oci.WithPlainHTTP(config.CommonOptions.PlainHTTP),
 		oci.WithInsecureSkipVerify(config.CommonOptions.InsecureSkipTLSVerify),
+		withConfiguredTransport(&transportErr),
 		oci.WithLogger(logger),
 		oci.WithUserAgent("zarf/" + config.CLIVersion),
 	}, mods...)
@@ -41,9 +53,28 @@ func NewRemote(url string, platform ocispec.Platform, mods ...oci.Modifier) (*Re
 	if err != nil {
 		return nil, err
 	}
+	if transportErr != nil {
+		return nil, transportErr
+	}
 	return &Remote{remote}, nil
 }
 
+// withConfiguredTransport applies the trusted certificate authorities, client certificate and proxy of the common
+// options to the transport of the remote, storing any error in errp as modifiers can not fail.
+func withConfiguredTransport(errp *error) oci.Modifier {
+	return func(o *oci.OrasRemote) {
+		client, ok := o.Repo().Client.(*auth.Client)
+		if !ok {
+			return
+		}
+		transport, ok := client.Client.Transport.(*http.Transport)
+		if !ok {
+			return
+		}
+		*errp = utils.ConfigureTransport(transport, config.CommonOptions)
+	}
+}
+
 // PlatformForSkeleton sets the target architecture for the remote to skeleton
 func PlatformForSkeleton() ocispec.Platform {
 	return ocispec.Platform{
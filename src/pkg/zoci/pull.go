@@ -21,7 +21,7 @@ import (
 
 var (
 	// PackageAlwaysPull is a list of paths that will always be pulled from the remote repository.
-	PackageAlwaysPull = []string{layout.ZarfYAML, layout.Checksums, layout.Signature}
+	PackageAlwaysPull = []string{layout.ZarfYAML, layout.Checksums, layout.Signature, layout.Bundle, layout.Provenance}
 )
 
 // PullPackage pulls the package from the remote repository and saves it to the given path.
@@ -32,6 +32,7 @@ var (
 //   - zarf.yaml
 //   - checksums.txt
 //   - zarf.yaml.sig
+//   - zarf.yaml.bundle
 func (r *Remote) PullPackage(ctx context.Context, destinationDir string, concurrency int, layersToPull ...ocispec.Descriptor) ([]ocispec.Descriptor, error) {
 	isPartialPull := len(layersToPull) > 0
 	r.Log().Debug(fmt.Sprintf("Pulling %s", r.Repo().Reference))
@@ -100,7 +101,7 @@ func (r *Remote) LayersFromRequestedComponents(ctx context.Context, requestedCom
 		if component.Name == "" {
 			return nil, fmt.Errorf("component %s does not exist in this package", rc.Name)
 		}
-		for _, image := range component.Images {
+		for _, image := range component.ImagesAndArtifacts() {
 			images[image] = true
 		}
 		desc := root.Locate(filepath.Join(layout.ComponentsDir, fmt.Sprintf(tarballFormat, component.Name)))
@@ -164,3 +165,8 @@ func (r *Remote) PullPackageMetadata(ctx context.Context, destinationDir string)
 func (r *Remote) PullPackageSBOM(ctx context.Context, destinationDir string) ([]ocispec.Descriptor, error) {
 	return r.PullPaths(ctx, destinationDir, []string{layout.SBOMTar})
 }
+
+// PullPackageDocs pulls the package's docs.tar from the remote repository and saves it to `destinationDir`.
+func (r *Remote) PullPackageDocs(ctx context.Context, destinationDir string) ([]ocispec.Descriptor, error) {
+	return r.PullPaths(ctx, destinationDir, []string{layout.DocsTar})
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package zoci contains functions for interacting with Zarf packages stored in OCI registries.
+package zoci
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"oras.land/oras-go/v2"
+)
+
+// PushFile pushes the file at path to the remote repository as the package layer with the given name, skipping the
+// upload if the registry already has the layer. The returned descriptor can be passed to PublishPackageLayers once the
+// rest of the package has been pushed.
+func (r *Remote) PushFile(ctx context.Context, name string, path string) (ocispec.Descriptor, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer f.Close()
+
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(digester.Hash(), f)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: ZarfLayerMediaTypeBlob,
+		Digest:    digester.Digest(),
+		Size:      size,
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle: name,
+		},
+	}
+
+	exists, err := r.Repo().Exists(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if exists {
+		r.Log().Debug(fmt.Sprintf("Layer %s already exists in %s", name, r.Repo().Reference))
+		return desc, nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := r.Repo().Push(ctx, desc, f); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("unable to push %s: %w", name, err)
+	}
+	return desc, nil
+}
+
+// PublishPackageLayers publishes the manifest of a package whose layers have already been pushed with PushFile.
+func (r *Remote) PublishPackageLayers(ctx context.Context, pkg *v1alpha1.ZarfPackage, descs []ocispec.Descriptor) error {
+	annotations := annotationsFromMetadata(&pkg.Metadata)
+
+	// assumes referrers API is not supported since OCI artifact
+	// media type is not supported
+	if err := r.Repo().SetReferrersCapability(false); err != nil {
+		return err
+	}
+
+	manifestConfigDesc, err := r.CreateAndPushManifestConfig(ctx, annotations, ZarfConfigMediaType)
+	if err != nil {
+		return err
+	}
+	root, err := oras.PackManifest(ctx, r.Repo(), oras.PackManifestVersion1_1_RC4, "", oras.PackManifestOptions{
+		Layers:              descs,
+		ConfigDescriptor:    manifestConfigDesc,
+		ManifestAnnotations: annotations,
+	})
+	if err != nil {
+		return err
+	}
+	return r.UpdateIndex(ctx, r.Repo().Reference.Reference, root)
+}
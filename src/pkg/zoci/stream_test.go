@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package zoci contains functions for interacting with Zarf packages stored in OCI registries.
+package zoci
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestPushFileAndPublishPackageLayers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	reg := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(reg.Close)
+
+	pkg := v1alpha1.ZarfPackage{
+		Metadata: v1alpha1.ZarfMetadata{Name: "stream", Version: "0.0.1"},
+	}
+	url := "oci://" + strings.TrimPrefix(reg.URL, "http://") + "/stream:0.0.1"
+	remote, err := NewRemote(url, oci.PlatformForArch("amd64"), oci.WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	src := t.TempDir()
+	files := map[string]string{
+		"zarf.yaml":                 "kind: ZarfPackageConfig\nmetadata:\n  name: stream\n  version: 0.0.1\n",
+		"components/first.tar":      "first",
+		"images/blobs/sha256/layer": "layer",
+	}
+	descs := []ocispec.Descriptor{}
+	for name, contents := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		desc, err := remote.PushFile(ctx, name, path)
+		require.NoError(t, err)
+		require.Equal(t, name, desc.Annotations[ocispec.AnnotationTitle])
+		require.Equal(t, int64(len(contents)), desc.Size)
+		descs = append(descs, desc)
+	}
+
+	// Layers that already exist in the registry are not pushed again.
+	again, err := remote.PushFile(ctx, "zarf.yaml", filepath.Join(src, "zarf.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, descs, again)
+
+	require.NoError(t, remote.PublishPackageLayers(ctx, &pkg, descs))
+
+	fetched, err := remote.FetchZarfYAML(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "stream", fetched.Metadata.Name)
+
+	dst := t.TempDir()
+	pulled, err := remote.PullPackage(ctx, dst, 3)
+	require.NoError(t, err)
+	require.Len(t, pulled, len(files)+1)
+	for name, contents := range files {
+		b, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		require.NoError(t, err)
+		require.Equal(t, contents, string(b))
+	}
+}
@@ -29,7 +29,7 @@ type ZarfE2ETest struct {
 	ApplianceModeKeep bool
 }
 
-var logRegex = regexp.MustCompile(`Saving log file to (?P<logFile>.*?\.log)`)
+var logRegex = regexp.MustCompile(`Saving log file to (?P<logFile>.*?\.log) with correlation ID (?P<correlationID>[0-9a-f]+)`)
 
 // GetCLIName looks at the OS and CPU architecture to determine which Zarf binary needs to be run.
 func GetCLIName() string {
@@ -111,7 +111,15 @@ func (e2e *ZarfE2ETest) GetLogFileContents(t *testing.T, stdErr string) string {
 	logFile := get("logFile")
 	logContents, err := os.ReadFile(logFile)
 	require.NoError(t, err)
-	return string(logContents)
+	// The log file is shared between runs, so only keep the lines of this run.
+	prefix := fmt.Sprintf("[%s] ", get("correlationID"))
+	lines := []string{}
+	for _, line := range strings.Split(string(logContents), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			lines = append(lines, strings.TrimPrefix(line, prefix))
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 // GetZarfVersion returns the current build/zarf version
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package test provides e2e tests for Zarf.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/types"
+)
+
+// TestIPFamily checks the addresses Zarf uses for the internal registry against the IP family of the cluster, and passes
+// on IPv4, IPv6-only (e.g. kind with networking.ipFamily set to ipv6) and dual-stack clusters.
+func TestIPFamily(t *testing.T) {
+	t.Log("E2E: IP family")
+	ctx := context.Background()
+
+	c, err := cluster.NewCluster()
+	require.NoError(t, err)
+	state, err := c.LoadZarfState(ctx)
+	require.NoError(t, err)
+	if !state.RegistryInfo.IsInternal() {
+		t.Skip("the cluster uses an external registry")
+	}
+
+	nodeList, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	ipv6Only := true
+	for _, node := range nodeList.Items {
+		for _, address := range node.Status.Addresses {
+			if ip := net.ParseIP(address.Address); address.Type == corev1.NodeInternalIP && ip != nil && ip.To4() != nil {
+				ipv6Only = false
+			}
+		}
+	}
+
+	// The registry NodePort is reached on the loopback address of the IP family of the nodes
+	host, _, err := net.SplitHostPort(state.RegistryInfo.Address)
+	require.NoError(t, err)
+	if ipv6Only {
+		require.Equal(t, types.IPV6Localhost, host)
+	} else {
+		require.Equal(t, helpers.IPV4Localhost, host)
+	}
+
+	// The in-cluster address of the registry is a valid host and port, with IPv6 cluster IPs in brackets
+	address, err := c.GetServiceInfoFromRegistryAddress(ctx, state.RegistryInfo.Address)
+	require.NoError(t, err)
+	_, _, err = net.SplitHostPort(address)
+	require.NoError(t, err)
+
+	// The image pull secret authenticates with both addresses of the registry
+	secret, err := c.Clientset.CoreV1().Secrets(cluster.ZarfNamespaceName).Get(ctx, "private-registry", metav1.GetOptions{})
+	require.NoError(t, err)
+	dockerConfig := cluster.DockerConfig{}
+	require.NoError(t, json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &dockerConfig))
+	require.Contains(t, dockerConfig.Auths, state.RegistryInfo.Address)
+	require.Contains(t, dockerConfig.Auths, address)
+
+	// The registry runs the image served by its own NodePort
+	podList, err := c.Clientset.CoreV1().Pods(cluster.ZarfNamespaceName).List(ctx, metav1.ListOptions{LabelSelector: "app=docker-registry"})
+	require.NoError(t, err)
+	require.NotEmpty(t, podList.Items)
+	for _, pod := range podList.Items {
+		for _, container := range pod.Spec.Containers {
+			require.True(t, strings.HasPrefix(container.Image, state.RegistryInfo.Address+"/"), container.Image)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package testutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/mholt/archiver/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// PackageBuilder programmatically constructs Zarf packages for use in tests.
+type PackageBuilder struct {
+	pkg v1alpha1.ZarfPackage
+}
+
+// NewPackageBuilder returns a builder for a minimal, valid ZarfPackageConfig with the given name.
+func NewPackageBuilder(name string) *PackageBuilder {
+	return &PackageBuilder{
+		pkg: v1alpha1.ZarfPackage{
+			APIVersion: v1alpha1.APIVersion,
+			Kind:       v1alpha1.ZarfPackageConfig,
+			Metadata: v1alpha1.ZarfMetadata{
+				Name: name,
+			},
+			Build: v1alpha1.ZarfBuildData{
+				Architecture: "amd64",
+				Version:      "v0.0.0",
+			},
+		},
+	}
+}
+
+// WithKind sets the kind of the package.
+func (b *PackageBuilder) WithKind(kind v1alpha1.ZarfPackageKind) *PackageBuilder {
+	b.pkg.Kind = kind
+	return b
+}
+
+// WithVersion sets the package metadata version.
+func (b *PackageBuilder) WithVersion(version string) *PackageBuilder {
+	b.pkg.Metadata.Version = version
+	return b
+}
+
+// WithArchitecture sets both the metadata and build architecture of the package.
+func (b *PackageBuilder) WithArchitecture(arch string) *PackageBuilder {
+	b.pkg.Metadata.Architecture = arch
+	b.pkg.Build.Architecture = arch
+	return b
+}
+
+// WithComponent appends a component to the package.
+func (b *PackageBuilder) WithComponent(component v1alpha1.ZarfComponent) *PackageBuilder {
+	b.pkg.Components = append(b.pkg.Components, component)
+	return b
+}
+
+// WithRequiredComponent appends a required component with no content to the package.
+func (b *PackageBuilder) WithRequiredComponent(name string) *PackageBuilder {
+	required := true
+	return b.WithComponent(v1alpha1.ZarfComponent{Name: name, Required: &required})
+}
+
+// WithVariable appends a deploy time variable to the package.
+func (b *PackageBuilder) WithVariable(variable v1alpha1.InteractiveVariable) *PackageBuilder {
+	b.pkg.Variables = append(b.pkg.Variables, variable)
+	return b
+}
+
+// WithConstant appends a constant to the package.
+func (b *PackageBuilder) WithConstant(constant v1alpha1.Constant) *PackageBuilder {
+	b.pkg.Constants = append(b.pkg.Constants, constant)
+	return b
+}
+
+// Build returns the in-memory package.
+//
+// A package without components is given a single required component so that it passes schema validation.
+func (b *PackageBuilder) Build() v1alpha1.ZarfPackage {
+	pkg := b.pkg
+	if len(pkg.Components) == 0 {
+		required := true
+		pkg.Components = []v1alpha1.ZarfComponent{{Name: pkg.Metadata.Name, Required: &required}}
+	}
+	return pkg
+}
+
+// WriteLayout writes a minimal package layout (zarf.yaml and checksums.txt) to dir and returns the package.
+func (b *PackageBuilder) WriteLayout(t *testing.T, dir string) v1alpha1.ZarfPackage {
+	t.Helper()
+
+	pkg := b.Build()
+	// checksums.txt only covers layers other than zarf.yaml, of which a minimal layout has none.
+	checksums := []byte("\n")
+	sum := sha256.Sum256(checksums)
+	pkg.Metadata.AggregateChecksum = hex.EncodeToString(sum[:])
+
+	err := os.WriteFile(filepath.Join(dir, "checksums.txt"), checksums, 0o600)
+	require.NoError(t, err)
+	b2, err := goyaml.Marshal(pkg)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "zarf.yaml"), b2, 0o600)
+	require.NoError(t, err)
+	return pkg
+}
+
+// WriteTarball writes the package as a .tar.zst archive into dir and returns the path to the archive.
+//
+// The archive is named the same way Zarf names created packages.
+func (b *PackageBuilder) WriteTarball(t *testing.T, dir string) string {
+	t.Helper()
+
+	layoutDir := t.TempDir()
+	pkg := b.WriteLayout(t, layoutDir)
+
+	name := fmt.Sprintf("zarf-package-%s-%s", pkg.Metadata.Name, pkg.Build.Architecture)
+	if pkg.Kind == v1alpha1.ZarfInitConfig {
+		name = fmt.Sprintf("zarf-init-%s", pkg.Build.Architecture)
+	}
+	if pkg.Metadata.Version != "" {
+		name = fmt.Sprintf("%s-%s", name, pkg.Metadata.Version)
+	}
+	tarPath := filepath.Join(dir, fmt.Sprintf("%s.tar.zst", name))
+	err := archiver.Archive([]string{layoutDir + string(os.PathSeparator)}, tarPath)
+	require.NoError(t, err)
+	return tarPath
+}
@@ -6,6 +6,8 @@ package types
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -40,8 +42,10 @@ const (
 	ZarfGeneratedPasswordLen               = 24
 	ZarfGeneratedSecretLen                 = 48
 	ZarfInClusterContainerRegistryNodePort = 31999
-	ZarfRegistryPushUser                   = "zarf-push"
-	ZarfRegistryPullUser                   = "zarf-pull"
+	// IPV6Localhost is the loopback address the registry NodePort is reached on by the nodes of an IPv6-only cluster
+	IPV6Localhost        = "::1"
+	ZarfRegistryPushUser = "zarf-push"
+	ZarfRegistryPullUser = "zarf-pull"
 
 	ZarfGitPushUser = "zarf-git-user"
 	ZarfGitReadUser = "zarf-git-read-user"
@@ -50,6 +54,17 @@ const (
 	ZarfInClusterArtifactServiceURL = ZarfInClusterGitServiceURL + "/api/packages/" + ZarfGitPushUser
 )
 
+// RegistryType is the implementation of the registry Zarf deploys into the cluster.
+type RegistryType string
+
+// The registry implementations Zarf can deploy into the cluster.
+const (
+	// RegistryTypeDistribution is the CNCF distribution registry, the default
+	RegistryTypeDistribution RegistryType = "distribution"
+	// RegistryTypeZot is the zot registry, which supports OCI artifacts and the referrers API for signatures and SBOMs
+	RegistryTypeZot RegistryType = "zot"
+)
+
 // GeneratedPKI is a struct for storing generated PKI data.
 type GeneratedPKI struct {
 	CA   []byte `json:"ca"`
@@ -69,6 +84,8 @@ type ZarfState struct {
 	StorageClass string `json:"storageClass"`
 	// PKI certificate information for the agent pods Zarf manages
 	AgentTLS GeneratedPKI `json:"agentTLS"`
+	// PEM encoded certificates of the private CA that Zarf's certificates were issued from, if one was provided at init
+	CABundle []byte `json:"caBundle,omitempty"`
 
 	// Information about the repository Zarf is configured to use
 	GitServer GitServerInfo `json:"gitServer"`
@@ -76,6 +93,21 @@ type ZarfState struct {
 	RegistryInfo RegistryInfo `json:"registryInfo"`
 	// Information about the artifact registry Zarf is configured to use
 	ArtifactServer ArtifactServerInfo `json:"artifactServer"`
+	// Encryption of the credentials and private keys in the state, if they are encrypted
+	Encryption *StateEncryption `json:"encryption,omitempty"`
+}
+
+// StateEncryption describes how the credentials and private keys in the Zarf state are encrypted.
+//
+// They are encrypted with a random data key, which is in turn encrypted with a key in a key management service or
+// an in-cluster secret, so that the state secret alone does not reveal them.
+type StateEncryption struct {
+	// URI of the key that encrypts the data key (e.g. awskms:///alias/zarf or k8s://zarf/zarf-state-key)
+	KeyURI string `json:"keyURI"`
+	// The data key, encrypted with the key
+	DataKey []byte `json:"dataKey,omitempty"`
+	// The credentials and private keys, encrypted with the data key
+	Ciphertext []byte `json:"ciphertext,omitempty"`
 }
 
 // DeployedPackage contains information about a Zarf Package that has been deployed to a cluster
@@ -88,6 +120,23 @@ type DeployedPackage struct {
 	DeployedComponents []DeployedComponent           `json:"deployedComponents"`
 	ComponentWebhooks  map[string]map[string]Webhook `json:"componentWebhooks,omitempty"`
 	ConnectStrings     ConnectStrings                `json:"connectStrings,omitempty"`
+	VariableOverlays   []string                      `json:"variableOverlays,omitempty"`
+	CorrelationID      string                        `json:"correlationID,omitempty"`
+}
+
+// DeployStatus is the progress of a package deployment that is published to the cluster for remote observers.
+// This object is saved as the data of a k8s config map within the 'Zarf' namespace and served by the Zarf agent.
+type DeployStatus struct {
+	Package             string          `json:"package"`
+	CorrelationID       string          `json:"correlationID,omitempty"`
+	Phase               ComponentStatus `json:"phase"`
+	Component           string          `json:"component,omitempty"`
+	Step                string          `json:"step,omitempty"`
+	ComponentsCompleted int             `json:"componentsCompleted"`
+	ComponentsTotal     int             `json:"componentsTotal"`
+	Error               string          `json:"error,omitempty"`
+	Started             time.Time       `json:"started"`
+	Updated             time.Time       `json:"updated"`
 }
 
 // ConnectString contains information about a connection made with Zarf connect.
@@ -107,6 +156,10 @@ type DeployedComponent struct {
 	InstalledCharts    []InstalledChart `json:"installedCharts"`
 	Status             ComponentStatus  `json:"status"`
 	ObservedGeneration int              `json:"observedGeneration"`
+	// References of the images the component pushed, relative to the registry (e.g. stefanprodan/podinfo:6.4.0)
+	PushedImages []string `json:"pushedImages,omitempty"`
+	// Names of the repos the component pushed to the git server
+	PushedRepos []string `json:"pushedRepos,omitempty"`
 }
 
 // Webhook contains information about a Component Webhook operating on a Zarf package secret.
@@ -223,11 +276,23 @@ type RegistryInfo struct {
 	NodePort int `json:"nodePort"`
 	// Secret value that the registry was seeded with
 	Secret string `json:"secret"`
+	// Implementation of the registry, only set if the registry is running inside the kubernetes cluster
+	Type RegistryType `json:"type,omitempty"`
 }
 
 // IsInternal returns true if the registry URL is equivalent to the registry deployed through the default init package
 func (ri RegistryInfo) IsInternal() bool {
-	return ri.Address == fmt.Sprintf("%s:%d", helpers.IPV4Localhost, ri.NodePort)
+	for _, localhost := range []string{helpers.IPV4Localhost, IPV6Localhost} {
+		if ri.Address == net.JoinHostPort(localhost, strconv.Itoa(ri.NodePort)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsZot returns true if the registry is the zot registry deployed through the default init package
+func (ri RegistryInfo) IsZot() bool {
+	return ri.Type == RegistryTypeZot
 }
 
 // FillInEmptyValues sets every necessary value not already set to a reasonable default
@@ -240,7 +305,12 @@ func (ri *RegistryInfo) FillInEmptyValues() error {
 
 	// Set default url if an external registry was not provided
 	if ri.Address == "" {
-		ri.Address = fmt.Sprintf("%s:%d", helpers.IPV4Localhost, ri.NodePort)
+		ri.Address = net.JoinHostPort(helpers.IPV4Localhost, strconv.Itoa(ri.NodePort))
+	}
+
+	// Set the default implementation of the internal registry
+	if ri.Type == "" && ri.IsInternal() {
+		ri.Type = RegistryTypeDistribution
 	}
 
 	// Generate a push-user password if not provided by init flag
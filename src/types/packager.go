@@ -17,6 +17,9 @@ type PackagerConfig struct {
 	// DeployOpts tracks user-defined values for the active deployment
 	DeployOpts ZarfDeployOptions
 
+	// RemoveOpts tracks user-defined values for the active removal
+	RemoveOpts ZarfRemoveOptions
+
 	// MirrorOpts tracks user-defined values for the active mirror
 	MirrorOpts ZarfMirrorOptions
 
@@ -18,12 +18,31 @@ type ZarfCommonOptions struct {
 	InsecureSkipTLSVerify bool
 	// Force connections to be over http instead of https
 	PlainHTTP bool
+	// Paths to PEM bundles of certificate authorities to trust in addition to the system roots for remote packages
+	CACertPaths []string
+	// Path to the PEM client certificate presented to servers of remote packages that require mutual TLS
+	ClientCertPath string
+	// Path to the PEM private key of the client certificate
+	ClientKeyPath string
+	// URL of the HTTP or HTTPS proxy, with optional credentials, to connect to servers of remote packages through
+	Proxy string
+	// Map of registry hosts to the mirrors that OCI packages are pulled from and published to, and images are looked up
+	// in, in their place
+	RegistryMirrors map[string]string
 	// Path to use to cache images and git repos on package create
 	CachePath string
+	// Fetch remote skeleton packages from their registry instead of reusing them from the cache
+	NoCache bool
 	// Location Zarf should use as a staging ground when managing files and images for package creation and deployment
 	TempDirectory string
 	// Number of concurrent layer operations to perform when interacting with a remote package
 	OCIConcurrency int
+	// Maximum sustained queries per second to the Kubernetes API server
+	KubeQPS float32
+	// Maximum burst of queries to the Kubernetes API server above the sustained rate
+	KubeBurst int
+	// Path to the kubeconfig file to connect to the cluster with, instead of the default locations
+	KubeConfig string
 }
 
 // ZarfPackageOptions tracks the user-defined preferences during common package operations.
@@ -32,6 +51,14 @@ type ZarfPackageOptions struct {
 	Shasum string
 	// Location where a Zarf package can be found
 	PackageSource string
+	// Ordered list of mirrors of the package source that are tried when the package can not be loaded from the source
+	SourceMirrors []string
+	// Checksum of the package archive prefixed with its algorithm, one of sha256, sha512 or blake3 (e.g. sha512:<digest>)
+	ArchiveChecksum string
+	// Path or URL of a detached cosign signature of the package archive
+	ArchiveSignature string
+	// Location where the public key that the detached signature of the package archive is verified with can be found
+	ArchiveKeyPath string
 	// Comma separated list of optional components
 	OptionalComponents string
 	// Location where the public key component of a cosign key-pair can be found
@@ -44,6 +71,17 @@ type ZarfPackageOptions struct {
 	Retries int
 	// Skip validating the signature of the Zarf package
 	SkipSignatureValidation bool
+	// Location of the key file used to decrypt an encrypted Zarf package
+	DecryptionKeyPath string
+	// Passphrase used to decrypt an encrypted Zarf package
+	DecryptionPassphrase string
+	// Identity that the certificate of a keyless signed Zarf package must have been issued to
+	CertificateIdentity string
+	// OIDC issuer that must have issued the identity of a keyless signed Zarf package
+	CertificateOIDCIssuer string
+	// Directory with the Fulcio certificate chain and the Rekor and CT log public keys to verify keyless signatures
+	// with instead of the public Sigstore trust root
+	TrustedRootPath string
 }
 
 // ZarfInspectOptions tracks the user-defined preferences during a package inspection.
@@ -52,8 +90,12 @@ type ZarfInspectOptions struct {
 	ViewSBOM bool
 	// Location to output an SBOM into after package inspection
 	SBOMOutputDir string
+	// Location to extract the package's docs into after package inspection
+	DocsOutputDir string
 	// ListImages will list the images in the package
 	ListImages bool
+	// Provenance will annotate each value of a composed package definition with the package it came from
+	Provenance bool
 }
 
 // ZarfFindImagesOptions tracks the user-defined preferences during a prepare find-images search.
@@ -78,10 +120,34 @@ type ZarfDeployOptions struct {
 	SkipWebhooks bool
 	// Timeout for performing Helm operations
 	Timeout time.Duration
+	// A map of component names to timeouts (i.e. 30m) that override the timeouts of the component and its charts
+	ComponentTimeouts map[string]string
+	// Signed entitlement tokens (or paths to files containing them) granting the entitlements of gated components
+	Entitlements []string
+	// Directory of per-cluster variable overlay files to select from for the cluster being deployed to
+	VariableOverlays string
+	// Whether to publish the progress of the deploy to the cluster so that it can be observed through the Zarf agent
+	PublishStatus bool
+	// Maximum number of components to deploy at the same time, honoring the dependencies of the components
+	Concurrency int
+	// Whether to roll the helm releases of the deployed components back to their state before the deploy if it fails
+	RollbackOnFailure bool
+	// Whether to only print how deploying the package would change the cluster without changing it
+	DryRun bool
+	// Local Rego or CEL policy files, or directories of them, that the rendered manifests of every component must pass
+	PolicyGates []string
+	// Name of the kubeconfig context of the cluster to deploy to, instead of the current context
+	KubeContext string
 	// [Library Only] A map of component names to chart names containing Helm Chart values to override values on deploy
 	ValuesOverridesMap map[string]map[string]map[string]interface{}
 }
 
+// ZarfRemoveOptions tracks the user-defined preferences during a package removal.
+type ZarfRemoveOptions struct {
+	// Whether to delete the images and repos that the removed components pushed and no other package uses
+	PruneArtifacts bool
+}
+
 // ZarfMirrorOptions tracks the user-defined preferences during a package mirror.
 type ZarfMirrorOptions struct {
 	// Whether to skip adding a Zarf checksum to image references
@@ -96,6 +162,23 @@ type ZarfPublishOptions struct {
 	SigningKeyPassword string
 	// Location where the private key component of a cosign key-pair can be found
 	SigningKeyPath string
+	// Options for signing the published package without a key-pair
+	Keyless ZarfKeylessSigningOptions
+}
+
+// ZarfKeylessSigningOptions tracks the user-defined preferences for signing a package with a short-lived certificate
+// issued for an OIDC identity instead of a key-pair.
+type ZarfKeylessSigningOptions struct {
+	// Whether to sign the package keyless
+	Enabled bool
+	// URL of the Fulcio certificate authority that issues the signing certificate
+	FulcioURL string
+	// URL of the Rekor transparency log the signature is recorded in
+	RekorURL string
+	// URL of the OIDC provider used to authenticate the signing identity
+	OIDCIssuer string
+	// OIDC identity token to sign with instead of authenticating interactively
+	IdentityToken string
 }
 
 // ZarfPullOptions tracks the user-defined preferences during a package pull.
@@ -130,6 +213,33 @@ type ZarfInitOptions struct {
 	ArtifactServer ArtifactServerInfo
 	// StorageClass of the k8s cluster Zarf is initializing
 	StorageClass string
+	// Location of the PEM encoded CA certificate, optionally followed by its chain, to issue Zarf's certificates from
+	CACertPath string
+	// Location of the PEM encoded private key of the CA certificate
+	CAKeyPath string
+	// Additional subject alternative names to add to the certificates issued from the CA
+	TLSSubjectAltNames []string
+	// URI of the key to encrypt the credentials and private keys in the Zarf state with
+	StateEncryptionKey string
+	// Overrides of the resources and scheduling of the registry
+	RegistryOverrides InitComponentOverrides
+	// Overrides of the resources and scheduling of the git server
+	GitServerOverrides InitComponentOverrides
+	// Overrides of the resources and scheduling of the agent
+	AgentOverrides InitComponentOverrides
+}
+
+// InitComponentOverrides are overrides of the resources and scheduling of a component of the init package, which are
+// set as its package variables.
+type InitComponentOverrides struct {
+	// Resource requests and limits, keyed by requests.cpu, requests.memory, limits.cpu and limits.memory
+	Resources map[string]string
+	// Size of the persistent volume claim
+	PVCSize string
+	// Labels of the nodes the component is scheduled on
+	NodeSelector map[string]string
+	// Tolerations of node taints, in the format KEY[=VALUE][:EFFECT]
+	Tolerations []string
 }
 
 // ZarfCreateOptions tracks the user-defined options used to create the package.
@@ -144,24 +254,48 @@ type ZarfCreateOptions struct {
 	ViewSBOM bool
 	// Location to output an SBOM into after package creation
 	SBOMOutputDir string
+	// Additional formats to create the SBOMs in alongside syft JSON
+	SBOMFormats []string
 	// Key-Value map of variable names and their corresponding values that will be used to template against the Zarf package being used
 	SetVariables map[string]string
+	// Paths of .env files to read variables from, variables in later files override earlier ones and SetVariables override all of them
+	EnvFiles []string
 	// Size of chunks to use when splitting a zarf package into multiple files in megabytes
 	MaxPackageSizeMB int
+	// Maximum size of each file of a zarf package with a unit (i.e. 4GB or 4GiB), overrides MaxPackageSizeMB when set
+	MaxArtifactSize string
 	// Location where the private key component of a cosign key-pair can be found
 	SigningKeyPath string
 	// Password to the private key signature file that will be used to sigh the created package
 	SigningKeyPassword string
+	// Options for signing the created package without a key-pair
+	Keyless ZarfKeylessSigningOptions
 	// Path to a previously built package used as the basis for creating a differential package
 	DifferentialPackagePath string
 	// A map of domains to override on package create when pulling images
 	RegistryOverrides map[string]string
 	// An optional variant that controls which components will be included in a package
 	Flavor string
+	// Whether to create one package for every flavor defined by the package instead of a single flavor
+	AllFlavors bool
+	// Architectures to create a multi-architecture package for, overrides the architectures of the package when set
+	Architectures []string
+	// Whether to only report what would be packaged and its estimated size without downloading anything
+	DryRun bool
 	// Whether to create a skeleton package
 	IsSkeleton bool
 	// Whether to create a YOLO package
 	NoYOLO bool
+	// Whether to include the cosign signatures and attestations of images in the package
+	IncludeSignatures bool
+	// Whether to fail if the resolved package inputs differ from the zarf-lock.yaml file instead of writing it
+	Locked bool
+	// Whether to verify remote files without a shasum against the checksum database, recording them on first fetch
+	ChecksumDB bool
+	// Location of the key file used to encrypt the created package tarball
+	EncryptionKeyPath string
+	// Passphrase used to encrypt the created package tarball
+	EncryptionPassphrase string
 }
 
 // ZarfSplitPackageData contains info about a split package.
@@ -172,11 +306,25 @@ type ZarfSplitPackageData struct {
 	Bytes int64
 	// The number of parts the package is split into
 	Count int
+	// The parts of the package in the order they are reassembled
+	Parts []ZarfSplitPackagePart
+}
+
+// ZarfSplitPackagePart contains info about one part of a split package.
+type ZarfSplitPackagePart struct {
+	// The file name of the part
+	Name string
+	// The sha256sum of the part
+	Sha256Sum string
+	// The size of the part in bytes
+	Bytes int64
 }
 
-// DifferentialData contains image and repository information about the package a Differential Package is Based on.
+// DifferentialData contains image, repository, and file information about the package a Differential Package is Based on.
 type DifferentialData struct {
-	DifferentialImages         map[string]bool
-	DifferentialRepos          map[string]bool
-	DifferentialPackageVersion string
+	DifferentialImages          map[string]bool
+	DifferentialRepos           map[string]bool
+	DifferentialFiles           map[string]bool
+	DifferentialPackageVersion  string
+	DifferentialPackageChecksum string
 }